@@ -0,0 +1,9 @@
+#!/bin/bash
+
+echo "################################## Creating Blob Bucket ##################################"
+# the users lambda keeps avatars here with BLOB_STORE=s3 and BLOB_STORE_BUCKET=blobs
+aws s3api create-bucket \
+    --region=us-east-1 \
+    --bucket=blobs \
+    --endpoint-url=http://localhost:4566 \
+    --profile=localstack
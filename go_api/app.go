@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"net/http"
+	"os"
 
+	"the-drink-almanac-api/blob"
+	"the-drink-almanac-api/catalog"
 	"the-drink-almanac-api/handler/middleware"
 	"the-drink-almanac-api/handler/server"
 	"the-drink-almanac-api/model"
@@ -20,33 +26,152 @@ func Start(port string) {
 	// set up default endpoint
 	router.GET("", hello_world_handler)
 
+	// publish the expvar metrics, such as the expiring records'
+	router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+
+	// short-lived records, such as reset tokens and lockout counters, go in the expiring store, which is created once
+	// so that there's a single sweeper and set of metrics; no endpoint keeps such records yet, so it isn't passed on
+	if _, err := startExpiringStore(context.Background(), appConfig); err != nil {
+		panic(err)
+	}
+
 	// set up auth middleware
 	authService := service.NewJwtAuthService(appConfig.JwtSecretKey)
 	authMiddleware := middleware.NewAuthMiddleware(authService)
 
+	// set up the repositories for the configured table design and the cache they share
+	userStore, favoriteStore, _ := repository.NewRepositories(appConfig)
+	cache := repository.NewLRUCache(appConfig.CacheSize)
+
+	// every endpoint reads the same drinks, from the catalog or from the dataset, which is only read once
+	drinkStore, err := catalog.NewDrinkRepository(appConfig)
+	if err != nil {
+		panic(err)
+	}
+	drinkService := service.NewDefaultDrinkService(drinkStore)
+
 	// set up favorite endpoints
-	favoriteStore, _ := repository.NewFavoriteRepository(appConfig.FavoritesTableName, appConfig.AwsEndpoint)
-	favoriteService := service.NewDefaultFavoriteService(favoriteStore)
-	favoriteHandler := server.FavoriteHandler{Service: favoriteService}
+	cachedFavoriteStore := repository.NewCachedFavoriteRepository(favoriteStore, cache, appConfig.CacheTTL)
+	recipeStore, _ := repository.NewRecipeRepository(appConfig)
+	recipeService := service.NewDefaultRecipeService(recipeStore)
+	// an invalid lookup is a configuration error, which shouldn't fall back to accepting every drink id
+	favoriteService, err := newFavoriteService(appConfig, cachedFavoriteStore, recipeService, drinkStore)
+	if err != nil {
+		panic(err)
+	}
+	// favorites of users' recipes are read wherever favorites of drinks are, as long as the user can see the recipe
+	drinkResolver := service.NewDefaultDrinkResolver(drinkService, recipeService)
+	favoriteHandler := server.FavoriteHandler{Service: favoriteService, DrinkResolver: drinkResolver}
 	favoriteRouteGroup := router.Group("/favorite")
 	favoriteRouteGroup.GET("", authMiddleware.AuthUser, favoriteHandler.FindFavoritesByUser)
+	favoriteRouteGroup.GET("/drink/:drinkId", authMiddleware.AuthUser, favoriteHandler.FindFavoriteByUserAndDrink)
 	favoriteRouteGroup.POST("", authMiddleware.AuthUser, favoriteHandler.CreateNewFavorite)
 	favoriteRouteGroup.DELETE("/:favoriteId", authMiddleware.AuthUser, favoriteHandler.DeleteFavorite)
 
+	// set up the shopping list endpoint;
+	// a token is only needed to shop for the user's favorites
+	shoppingListHandler := server.ShoppingListHandler{Service: service.NewDefaultShoppingListService(drinkResolver, favoriteService)}
+	router.POST("/shopping-list", authMiddleware.OptionalAuthUser, shoppingListHandler.CreateShoppingList)
+
+	// set up the nutrition endpoint; a token is only needed to estimate one of the user's favorites
+	nutritionHandler := server.NutritionHandler{Service: service.NewDefaultNutritionService(drinkResolver, favoriteService)}
+
 	// set up user endpoints
-	userStore, _ := repository.NewUserRepository(appConfig.UsersTableName, appConfig.AwsEndpoint)
-	userService := service.NewDefaultUserService(userStore)
+	cachedUserStore := repository.NewCachedUserRepository(userStore, cache, appConfig.CacheTTL)
+	inventoryStore, _ := repository.NewInventoryRepository(appConfig)
+	inventoryService := service.NewDefaultInventoryService(inventoryStore)
+	// avatars are kept in the blob store, which has to be usable before anyone uploads one
+	avatarStore, err := blob.NewStore(appConfig)
+	if err != nil {
+		panic(err)
+	}
+	// public profiles only list favorites of public recipes
+	profileService := service.NewDefaultProfileService(service.NewDefaultUserService(cachedUserStore), favoriteService, avatarStore).
+		WithRecipes(recipeService)
+	// deleting a user also deletes their inventory, recipes and avatar
+	// GET /user?include=favorites reads the user and their favorites with one Query in the single-table design,
+	// which bypasses the cache
+	userService := service.NewDefaultUserService(cachedUserStore).WithUserData(inventoryService, recipeService, profileService).
+		WithFavorites(repository.NewUserWithFavoritesFinder(userStore, favoriteStore))
 	userHandler := server.NewUserHandler(userService, authService)
+	profileHandler := server.ProfileHandler{Service: profileService, UserService: userService}
 	userRouteGroup := router.Group("/user")
 	userRouteGroup.GET("", authMiddleware.AuthUser, userHandler.FindUser)
 	userRouteGroup.POST("", userHandler.CreateNewUser)
+	userRouteGroup.PATCH("", authMiddleware.AuthUser, userHandler.UpdateUser)
 	userRouteGroup.DELETE("", authMiddleware.AuthUser, userHandler.DeleteUser)
 	userRouteGroup.POST("/login", userHandler.Login)
+	userRouteGroup.PUT("/preferences", authMiddleware.AuthUser, userHandler.UpdatePreferences)
+	userRouteGroup.PUT("/avatar", authMiddleware.AuthUser, profileHandler.UpdateAvatar)
+	userRouteGroup.DELETE("/avatar", authMiddleware.AuthUser, profileHandler.DeleteAvatar)
+
+	// set up the public profile endpoints, which don't require a token
+	usersRouteGroup := router.Group("/users")
+	usersRouteGroup.GET("/:username", profileHandler.FindProfile)
+	usersRouteGroup.GET("/:username/avatar", profileHandler.FindAvatar)
+
+	// set up inventory endpoints
+	inventoryHandler := server.InventoryHandler{Service: inventoryService}
+	inventoryRouteGroup := router.Group("/inventory")
+	inventoryRouteGroup.GET("", authMiddleware.AuthUser, inventoryHandler.FindInventory)
+	inventoryRouteGroup.POST("", authMiddleware.AuthUser, inventoryHandler.AddInventoryItem)
+	inventoryRouteGroup.GET("/:ingredient", authMiddleware.AuthUser, inventoryHandler.FindInventoryItem)
+	inventoryRouteGroup.PUT("/:ingredient", authMiddleware.AuthUser, inventoryHandler.UpdateInventoryItem)
+	inventoryRouteGroup.DELETE("/:ingredient", authMiddleware.AuthUser, inventoryHandler.DeleteInventoryItem)
+
+	// set up recipe endpoints; a token is only needed to read a private recipe
+	recipeHandler := server.RecipeHandler{Service: recipeService}
+	recipeRouteGroup := router.Group("/recipe")
+	recipeRouteGroup.GET("", authMiddleware.AuthUser, recipeHandler.FindRecipes)
+	recipeRouteGroup.POST("", authMiddleware.AuthUser, recipeHandler.CreateRecipe)
+	recipeRouteGroup.GET("/:recipeId", authMiddleware.OptionalAuthUser, recipeHandler.FindRecipe)
+	recipeRouteGroup.PUT("/:recipeId", authMiddleware.AuthUser, recipeHandler.UpdateRecipe)
+	recipeRouteGroup.DELETE("/:recipeId", authMiddleware.AuthUser, recipeHandler.DeleteRecipe)
+
+	// set up drink endpoints, which don't require a token
+	// public recipes are searched alongside the drinks
+	drinkSearchService := service.NewIndexedDrinkSearchService(service.WithPublicRecipes(drinkStore.FindAll, recipeService), favoriteStore,
+		drinkResolver, appConfig.DrinkSearchRefreshInterval)
+	// the index is built before the api starts serving and rebuilt in the background
+	if err := drinkSearchService.Start(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build the drink search index; the first search will try again: %v\n", err)
+	}
+	drinkHandler := server.DrinkHandler{Service: drinkService, SearchService: drinkSearchService, UserService: userService}
+	drinkRouteGroup := router.Group("/drink")
+	drinkRouteGroup.GET("", drinkHandler.FindAllDrinks)
+	drinkRouteGroup.GET("/search", drinkHandler.SearchDrinks)
+	drinkRouteGroup.POST("/makeable", authMiddleware.OptionalAuthUser, drinkHandler.FindMakeableDrinks)
+	// a token renders the recipe in the units that the user prefers
+	drinkRouteGroup.GET("/:drinkId", authMiddleware.OptionalAuthUser, drinkHandler.FindDrinkById)
+	drinkRouteGroup.GET("/:drinkId/nutrition", authMiddleware.OptionalAuthUser, nutritionHandler.EstimateDrink)
+
+	// publish the domain events recorded in the outbox in the background;
+	// without a sink, the events are kept in the outbox until one is configured
+	relay, err := newEventRelay(appConfig)
+	switch {
+	case errors.Is(err, errNoEventSink):
+		fmt.Fprintf(os.Stderr, "the event relay is disabled: %v\n", err)
+	case err != nil:
+		panic(err)
+	default:
+		go relay.Run(context.Background(), appConfig.EventRelayInterval)
+	}
 
 	// running the app
 	router.Run(fmt.Sprintf(":%s", port))
 }
 
+// newFavoriteService creates the favorite service that checks new favorites' drinks against the given drinks
+// with the configured lookup; users' recipes can be favorited as long as the user can see them
+func newFavoriteService(appConfig model.AppConfig, favorites repository.FavoriteRepository, recipes service.RecipeService,
+	drinks repository.DrinkRepository) (service.DefaultFavoriteService, error) {
+	drinkLookup, err := catalog.NewDrinkLookup(appConfig, drinks)
+	if err != nil {
+		return service.DefaultFavoriteService{}, err
+	}
+	return service.NewDefaultFavoriteService(favorites).WithDrinkLookup(drinkLookup).WithRecipes(recipes), nil
+}
+
 func hello_world_handler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Hello World!",
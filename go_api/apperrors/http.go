@@ -0,0 +1,23 @@
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// InternalErrorMessage is returned to clients in place of unexpected errors,
+// which may contain details about the data store that shouldn't be exposed
+const InternalErrorMessage = "an unexpected error occurred; please try again later"
+
+// StatusCode maps the store errors to the http status code and message that should be returned to the client;
+// any other error is treated as an unexpected internal error and its message is replaced
+func StatusCode(err error) (int, string) {
+	switch {
+	case errors.As(err, &ConflictError{}):
+		return http.StatusConflict, err.Error()
+	case errors.As(err, &UnavailableError{}):
+		return http.StatusServiceUnavailable, err.Error()
+	default:
+		return http.StatusInternalServerError, InternalErrorMessage
+	}
+}
@@ -0,0 +1,45 @@
+package apperrors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCode(t *testing.T) {
+	cause := fmt.Errorf("operation error DynamoDB: Query, https response error StatusCode: 400")
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+		expectedMessage    string
+	}{
+		{
+			name:               "Conflict",
+			err:                fmt.Errorf("wrapped: %w", NewConflictError("conflict", cause)),
+			expectedStatusCode: http.StatusConflict,
+			expectedMessage:    "wrapped: conflict",
+		},
+		{
+			name:               "Unavailable",
+			err:                NewUnavailableError("unavailable", cause),
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedMessage:    "unavailable",
+		},
+		{
+			name:               "Unexpected error",
+			err:                cause,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedMessage:    InternalErrorMessage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusCode, message := StatusCode(tt.err)
+			assert.Equal(t, tt.expectedStatusCode, statusCode)
+			assert.Equal(t, tt.expectedMessage, message)
+		})
+	}
+}
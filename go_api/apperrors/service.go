@@ -61,3 +61,51 @@ func (e IncorrectPasswordError) Error() string {
 func NewIncorrectPasswordError(username string) IncorrectPasswordError {
 	return IncorrectPasswordError{message: fmt.Sprintf("incorrect password for username '%s'", username)}
 }
+
+type DrinkNotFoundError struct {
+	message string
+}
+
+func (e DrinkNotFoundError) Error() string {
+	return e.message
+}
+
+func NewDrinkNotFoundError(drinkId string) DrinkNotFoundError {
+	return DrinkNotFoundError{message: fmt.Sprintf("no drink exists with the id '%s'", drinkId)}
+}
+
+type InventoryItemAlreadyExistsError struct {
+	message string
+}
+
+func (e InventoryItemAlreadyExistsError) Error() string {
+	return e.message
+}
+
+func NewInventoryItemAlreadyExistsError(ingredient string) InventoryItemAlreadyExistsError {
+	return InventoryItemAlreadyExistsError{message: fmt.Sprintf("the inventory already has the ingredient '%s'", ingredient)}
+}
+
+type RecipeNotOwnedError struct {
+	message string
+}
+
+func (e RecipeNotOwnedError) Error() string {
+	return e.message
+}
+
+func NewRecipeNotOwnedError(recipeId string) RecipeNotOwnedError {
+	return RecipeNotOwnedError{message: fmt.Sprintf("only the owner of the recipe '%s' can change it", recipeId)}
+}
+
+type InvalidAvatarError struct {
+	message string
+}
+
+func (e InvalidAvatarError) Error() string {
+	return e.message
+}
+
+func NewInvalidAvatarError(reason string) InvalidAvatarError {
+	return InvalidAvatarError{message: fmt.Sprintf("invalid avatar: %s", reason)}
+}
@@ -1 +1,39 @@
 package apperrors
+
+// ConflictError is returned when a write is rejected because the stored item
+// doesn't match what the request expected (e.g. it already exists or was modified by another request)
+type ConflictError struct {
+	message string
+	err     error
+}
+
+func (e ConflictError) Error() string {
+	return e.message
+}
+
+func (e ConflictError) Unwrap() error {
+	return e.err
+}
+
+func NewConflictError(message string, err error) ConflictError {
+	return ConflictError{message: message, err: err}
+}
+
+// UnavailableError is returned when the data store couldn't handle the request,
+// either because it kept throttling or failing the request or because the circuit breaker is open
+type UnavailableError struct {
+	message string
+	err     error
+}
+
+func (e UnavailableError) Error() string {
+	return e.message
+}
+
+func (e UnavailableError) Unwrap() error {
+	return e.err
+}
+
+func NewUnavailableError(message string, err error) UnavailableError {
+	return UnavailableError{message: message, err: err}
+}
@@ -0,0 +1,47 @@
+package archive
+
+import (
+	"io"
+	"time"
+
+	"the-drink-almanac-api/repository"
+)
+
+// Counts is the number of records handled for each record type
+type Counts struct {
+	Users     int
+	Favorites int
+}
+
+// Export writes every user and favorite in the repositories to w as an archive
+func Export(w io.Writer, users repository.UserRepository, favorites repository.FavoriteRepository, createdAt time.Time) (Counts, error) {
+	counts := Counts{}
+	writer, err := NewWriter(w, createdAt)
+	if err != nil {
+		return counts, err
+	}
+
+	allUsers, err := users.FindAll()
+	if err != nil {
+		return counts, err
+	}
+	for _, user := range allUsers {
+		if err := writer.WriteUser(user); err != nil {
+			return counts, err
+		}
+		counts.Users++
+	}
+
+	allFavorites, err := favorites.FindAll()
+	if err != nil {
+		return counts, err
+	}
+	for _, favorite := range allFavorites {
+		if err := writer.WriteFavorite(favorite); err != nil {
+			return counts, err
+		}
+		counts.Favorites++
+	}
+
+	return counts, writer.Close()
+}
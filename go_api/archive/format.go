@@ -0,0 +1,284 @@
+// Package archive reads and writes portable backups of the api's users and favorites.
+//
+// An archive is a JSON Lines file (optionally gzipped) that starts with a header, followed by one line per
+// user or favorite, and ends with a trailer containing the number of records and the SHA-256 checksum of every
+// line before the trailer, so that truncated or modified archives are rejected.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"the-drink-almanac-api/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	FormatName = "the-drink-almanac-archive"
+	// FormatVersion is incremented whenever the format changes in a way that older readers can't handle
+	FormatVersion = 1
+)
+
+type RecordType string
+
+const (
+	UserRecord     RecordType = "user"
+	FavoriteRecord RecordType = "favorite"
+	trailerRecord  RecordType = "trailer"
+)
+
+type header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// line is the json representation of every line after the header
+type line struct {
+	Type     RecordType `json:"type"`
+	User     *user      `json:"user,omitempty"`
+	Favorite *favorite  `json:"favorite,omitempty"`
+	Records  int        `json:"records,omitempty"`
+	Checksum string     `json:"sha256,omitempty"`
+}
+
+type user struct {
+	Id              string    `json:"id"`
+	Username        string    `json:"username"`
+	PasswordHash    string    `json:"password_hash"`
+	Units           string    `json:"units,omitempty"`
+	DisplayName     string    `json:"display_name,omitempty"`
+	Bio             string    `json:"bio,omitempty"`
+	Avatar          string    `json:"avatar,omitempty"`
+	PublicFavorites bool      `json:"public_favorites,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	Version         int       `json:"version"`
+}
+
+type favorite struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"user_id"`
+	DrinkId   string    `json:"drink_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int       `json:"version"`
+}
+
+// Record is a single user or favorite read from an archive
+type Record struct {
+	Type     RecordType
+	User     *model.User
+	Favorite *model.Favorite
+}
+
+// Writer writes an archive; Close must be called to write the trailer
+type Writer struct {
+	w        io.Writer
+	checksum hash.Hash
+	records  int
+}
+
+// NewWriter writes the archive's header to w
+func NewWriter(w io.Writer, createdAt time.Time) (*Writer, error) {
+	writer := &Writer{w: w, checksum: sha256.New()}
+	err := writer.writeLine(header{Format: FormatName, Version: FormatVersion, CreatedAt: createdAt.UTC()})
+	return writer, err
+}
+
+func (w *Writer) WriteUser(u model.User) error {
+	w.records++
+	return w.writeLine(line{Type: UserRecord, User: &user{
+		Id:              u.Id,
+		Username:        u.Username,
+		PasswordHash:    u.Password,
+		Units:           u.Units,
+		DisplayName:     u.DisplayName,
+		Bio:             u.Bio,
+		Avatar:          u.Avatar,
+		PublicFavorites: u.PublicFavorites,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+		Version:         u.Version,
+	}})
+}
+
+func (w *Writer) WriteFavorite(f model.Favorite) error {
+	w.records++
+	return w.writeLine(line{Type: FavoriteRecord, Favorite: &favorite{
+		Id:        f.Id,
+		UserId:    f.UserId,
+		DrinkId:   f.DrinkId,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+		Version:   f.Version,
+	}})
+}
+
+// Close writes the trailer; it doesn't close the underlying writer
+func (w *Writer) Close() error {
+	trailer, err := jsoniter.Marshal(line{
+		Type:     trailerRecord,
+		Records:  w.records,
+		Checksum: hex.EncodeToString(w.checksum.Sum(nil)),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(trailer, '\n'))
+	return err
+}
+
+func (w *Writer) writeLine(value interface{}) error {
+	encoded, err := jsoniter.Marshal(value)
+	if err != nil {
+		return err
+	}
+	encoded = append(encoded, '\n')
+	w.checksum.Write(encoded)
+	_, err = w.w.Write(encoded)
+	return err
+}
+
+// Reader reads the records of an archive, verifying the checksum once the trailer is reached
+type Reader struct {
+	r         *bufio.Reader
+	checksum  hash.Hash
+	records   int
+	CreatedAt time.Time
+	done      bool
+}
+
+// NewReader reads and validates the archive's header; gzipped archives are detected automatically
+func NewReader(r io.Reader) (*Reader, error) {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		buffered = bufio.NewReader(gzipReader)
+	}
+
+	reader := &Reader{r: buffered, checksum: sha256.New()}
+	headerLine, err := reader.readLine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the archive's header: %w", err)
+	}
+	var h header
+	if err := jsoniter.Unmarshal(headerLine, &h); err != nil || h.Format != FormatName {
+		return nil, fmt.Errorf("the file isn't a %s", FormatName)
+	}
+	if h.Version < 1 || h.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported archive version %d; the latest supported version is %d", h.Version, FormatVersion)
+	}
+	reader.CreatedAt = h.CreatedAt
+	return reader, nil
+}
+
+// Next returns the next record, or io.EOF once the trailer has been read and the checksum matches
+func (r *Reader) Next() (Record, error) {
+	if r.done {
+		return Record{}, io.EOF
+	}
+	rawLine, err := r.readLine()
+	if errors.Is(err, io.EOF) {
+		return Record{}, errors.New("the archive is truncated; it doesn't end with a trailer")
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	var l line
+	if err := jsoniter.Unmarshal(rawLine, &l); err != nil {
+		return Record{}, fmt.Errorf("invalid record %d: %w", r.records+1, err)
+	}
+	switch {
+	case l.Type == UserRecord && l.User != nil:
+		r.records++
+		return Record{Type: UserRecord, User: &model.User{
+			Id:              l.User.Id,
+			Username:        l.User.Username,
+			Password:        l.User.PasswordHash,
+			Units:           l.User.Units,
+			DisplayName:     l.User.DisplayName,
+			Bio:             l.User.Bio,
+			Avatar:          l.User.Avatar,
+			PublicFavorites: l.User.PublicFavorites,
+			CreatedAt:       l.User.CreatedAt,
+			UpdatedAt:       l.User.UpdatedAt,
+			Version:         l.User.Version,
+		}}, nil
+	case l.Type == FavoriteRecord && l.Favorite != nil:
+		r.records++
+		return Record{Type: FavoriteRecord, Favorite: &model.Favorite{
+			Id:        l.Favorite.Id,
+			UserId:    l.Favorite.UserId,
+			DrinkId:   l.Favorite.DrinkId,
+			CreatedAt: l.Favorite.CreatedAt,
+			UpdatedAt: l.Favorite.UpdatedAt,
+			Version:   l.Favorite.Version,
+		}}, nil
+	case l.Type == trailerRecord:
+		return Record{}, r.verify(l)
+	default:
+		return Record{}, fmt.Errorf("invalid record %d: unknown record type '%s'", r.records+1, l.Type)
+	}
+}
+
+func (r *Reader) verify(trailer line) error {
+	r.done = true
+	if trailer.Records != r.records {
+		return fmt.Errorf("the archive should contain %d records but %d were read", trailer.Records, r.records)
+	}
+	if checksum := hex.EncodeToString(r.checksum.Sum(nil)); checksum != trailer.Checksum {
+		return fmt.Errorf("the archive's checksum %s doesn't match the expected checksum %s", checksum, trailer.Checksum)
+	}
+	if _, err := r.r.Peek(1); err != io.EOF {
+		return errors.New("the archive contains data after its trailer")
+	}
+	return io.EOF
+}
+
+// readLine returns the next line and adds it to the checksum, unless it's the trailer
+func (r *Reader) readLine() ([]byte, error) {
+	rawLine, err := r.r.ReadBytes('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(rawLine) > 0 {
+			return nil, errors.New("the archive is truncated; its last line is incomplete")
+		}
+		return nil, err
+	}
+	var l struct {
+		Type RecordType `json:"type"`
+	}
+	if jsoniter.Unmarshal(rawLine, &l) != nil || l.Type != trailerRecord {
+		r.checksum.Write(rawLine)
+	}
+	return rawLine, nil
+}
+
+// Verify reads the entire archive and checks that it is complete and its checksum matches
+func Verify(r io.Reader) (records int, err error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		_, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return reader.records, nil
+		}
+		if err != nil {
+			return reader.records, err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+var (
+	testTime     = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testUser     = model.User{Id: "user0", Username: "username0", Password: "$2a$08$hash", Units: "metric", DisplayName: "Name Zero", Bio: "Mostly sours.", Avatar: "avatars/user0/avatar.png", PublicFavorites: true, CreatedAt: testTime, UpdatedAt: testTime, Version: 2}
+	testFavorite = model.Favorite{Id: "favorite0", UserId: "user0", DrinkId: "drink0", CreatedAt: testTime, UpdatedAt: testTime, Version: 1}
+)
+
+func exportTestArchive(t *testing.T) []byte {
+	mockUserRepo := repository.NewMockUserRepository(t)
+	mockUserRepo.On("FindAll").Return([]model.User{testUser}, nil)
+	mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindAll").Return([]model.Favorite{testFavorite}, nil)
+
+	var buffer bytes.Buffer
+	counts, err := Export(&buffer, mockUserRepo, mockFavoriteRepo, testTime)
+	assert.NoError(t, err)
+	assert.Equal(t, Counts{Users: 1, Favorites: 1}, counts)
+	return buffer.Bytes()
+}
+
+func readAll(r io.Reader) ([]Record, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	exported := exportTestArchive(t)
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	_, err := gzipWriter.Write(exported)
+	assert.NoError(t, err)
+	assert.NoError(t, gzipWriter.Close())
+
+	for name, archive := range map[string][]byte{"plain": exported, "gzip": gzipped.Bytes()} {
+		t.Run(name, func(t *testing.T) {
+			records, err := readAll(bytes.NewReader(archive))
+			assert.NoError(t, err)
+			assert.Equal(t, []Record{
+				{Type: UserRecord, User: &testUser},
+				{Type: FavoriteRecord, Favorite: &testFavorite},
+			}, records)
+		})
+	}
+}
+
+func TestReader_RejectsInvalidArchives(t *testing.T) {
+	exported := string(exportTestArchive(t))
+	lines := strings.SplitAfter(exported, "\n")
+	tests := map[string]string{
+		"Modified record":      strings.Replace(exported, "username0", "username1", 1),
+		"Missing trailer":      strings.Join(lines[:len(lines)-2], ""),
+		"Incomplete last line": exported[:len(exported)-5],
+		"Data after trailer":   exported + lines[1],
+		"Not an archive":       "{\"hello\":\"world\"}\n",
+		"Unsupported version":  strings.Replace(exported, "\"version\":1", "\"version\":99", 1),
+	}
+	for name, archive := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Verify(strings.NewReader(archive))
+			assert.Error(t, err)
+		})
+	}
+
+	records, err := Verify(strings.NewReader(exported))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, records)
+}
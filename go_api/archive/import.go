@@ -0,0 +1,170 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+// Mode decides what happens to records in the archive that already exist in the repositories
+type Mode string
+
+const (
+	// ModeMerge keeps the existing records and only adds the missing ones
+	ModeMerge Mode = "merge"
+	// ModeOverwrite replaces the existing records with the archived ones
+	ModeOverwrite Mode = "overwrite"
+)
+
+func ParseMode(mode string) (Mode, error) {
+	switch Mode(mode) {
+	case ModeMerge, ModeOverwrite:
+		return Mode(mode), nil
+	default:
+		return "", fmt.Errorf("invalid mode '%s'; the mode must be either '%s' or '%s'", mode, ModeMerge, ModeOverwrite)
+	}
+}
+
+// ImportCounts is the number of records of a single type that were (or would be, for a dry run)
+// created, updated, skipped because they already exist, or skipped because they conflict with another record
+type ImportCounts struct {
+	Created   int
+	Updated   int
+	Skipped   int
+	Conflicts int
+}
+
+type ImportReport struct {
+	Users     ImportCounts
+	Favorites ImportCounts
+}
+
+// Importer writes the records of an archive through the repositories, preserving their ids and password hashes
+type Importer struct {
+	users     repository.UserRepository
+	favorites repository.FavoriteRepository
+	mode      Mode
+	dryRun    bool
+}
+
+func NewImporter(users repository.UserRepository, favorites repository.FavoriteRepository, mode Mode, dryRun bool) Importer {
+	return Importer{
+		users:     users,
+		favorites: favorites,
+		mode:      mode,
+		dryRun:    dryRun,
+	}
+}
+
+// Import applies every record in the archive;
+// the checksum is only verified once the whole archive has been read,
+// so use Verify first to avoid partially importing a corrupted archive
+func (i Importer) Import(r io.Reader) (ImportReport, error) {
+	report := ImportReport{}
+	reader, err := NewReader(r)
+	if err != nil {
+		return report, err
+	}
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		if err != nil {
+			return report, err
+		}
+
+		switch record.Type {
+		case UserRecord:
+			err = i.importUser(*record.User, &report.Users)
+		case FavoriteRecord:
+			err = i.importFavorite(*record.Favorite, &report.Favorites)
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+}
+
+func (i Importer) importUser(user model.User, counts *ImportCounts) error {
+	existingUser, err := i.users.FindUserById(user.Id)
+	if err != nil {
+		return err
+	}
+	if existingUser != nil && i.mode == ModeMerge {
+		counts.Skipped++
+		return nil
+	}
+
+	// usernames must stay unique, so a user can't be imported if another user already has the username
+	userWithUsername, err := i.users.FindUserByUsername(user.Username)
+	if err != nil {
+		return err
+	}
+	if userWithUsername != nil && userWithUsername.Id != user.Id {
+		counts.Conflicts++
+		return nil
+	}
+
+	if existingUser == nil {
+		counts.Created++
+		if i.dryRun {
+			return nil
+		}
+		return i.users.CreateNewUser(user)
+	}
+
+	counts.Updated++
+	if i.dryRun {
+		return nil
+	}
+	user.Version = existingUser.Version + 1
+	return i.users.UpdateUser(user, existingUser.Version)
+}
+
+func (i Importer) importFavorite(favorite model.Favorite, counts *ImportCounts) error {
+	existingFavorite, err := i.favorites.FindFavoriteById(favorite.Id)
+	if err != nil {
+		return err
+	}
+
+	if existingFavorite == nil {
+		// users can only favorite a drink once, even if the favorites have different ids
+		favoriteForDrink, err := i.favorites.FindFavoriteByUserAndDrink(favorite.UserId, favorite.DrinkId)
+		if err != nil {
+			return err
+		}
+		if favoriteForDrink != nil {
+			counts.Conflicts++
+			return nil
+		}
+		if i.dryRun {
+			counts.Created++
+			return nil
+		}
+		err = i.favorites.CreateNewFavorite(favorite)
+		if errors.As(err, &apperrors.FavoriteAlreadyExistsError{}) {
+			counts.Conflicts++
+			return nil
+		}
+		if err == nil {
+			counts.Created++
+		}
+		return err
+	}
+
+	if i.mode == ModeMerge {
+		counts.Skipped++
+		return nil
+	}
+	counts.Updated++
+	if i.dryRun {
+		return nil
+	}
+	favorite.Version = existingFavorite.Version + 1
+	return i.favorites.UpdateFavorite(favorite, existingFavorite.Version)
+}
@@ -0,0 +1,107 @@
+package archive
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+func TestImporter_Import(t *testing.T) {
+	existingUser := testUser
+	existingUser.Version = 5
+	existingFavorite := testFavorite
+	existingFavorite.Version = 3
+	tests := []struct {
+		name           string
+		mode           Mode
+		dryRun         bool
+		mockCalls      func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository)
+		expectedReport ImportReport
+	}{
+		{
+			name: "Records don't exist yet",
+			mode: ModeMerge,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", "user0").Return(nil, nil)
+				users.On("FindUserByUsername", "username0").Return(nil, nil)
+				users.On("CreateNewUser", testUser).Return(nil)
+				favorites.On("FindFavoriteById", "favorite0").Return(nil, nil)
+				favorites.On("FindFavoriteByUserAndDrink", "user0", "drink0").Return(nil, nil)
+				favorites.On("CreateNewFavorite", testFavorite).Return(nil)
+			},
+			expectedReport: ImportReport{Users: ImportCounts{Created: 1}, Favorites: ImportCounts{Created: 1}},
+		},
+		{
+			name:   "Dry run doesn't write anything",
+			mode:   ModeMerge,
+			dryRun: true,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", "user0").Return(nil, nil)
+				users.On("FindUserByUsername", "username0").Return(nil, nil)
+				favorites.On("FindFavoriteById", "favorite0").Return(nil, nil)
+				favorites.On("FindFavoriteByUserAndDrink", "user0", "drink0").Return(nil, nil)
+			},
+			expectedReport: ImportReport{Users: ImportCounts{Created: 1}, Favorites: ImportCounts{Created: 1}},
+		},
+		{
+			name: "Merge keeps existing records",
+			mode: ModeMerge,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", "user0").Return(&existingUser, nil)
+				favorites.On("FindFavoriteById", "favorite0").Return(&existingFavorite, nil)
+			},
+			expectedReport: ImportReport{Users: ImportCounts{Skipped: 1}, Favorites: ImportCounts{Skipped: 1}},
+		},
+		{
+			name: "Overwrite replaces existing records",
+			mode: ModeOverwrite,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", "user0").Return(&existingUser, nil)
+				users.On("FindUserByUsername", "username0").Return(&existingUser, nil)
+				users.On("UpdateUser", mock.MatchedBy(func(user model.User) bool {
+					return user.Password == testUser.Password && user.Version == 6
+				}), 5).Return(nil)
+				favorites.On("FindFavoriteById", "favorite0").Return(&existingFavorite, nil)
+				favorites.On("UpdateFavorite", mock.MatchedBy(func(favorite model.Favorite) bool {
+					return favorite.Version == 4
+				}), 3).Return(nil)
+			},
+			expectedReport: ImportReport{Users: ImportCounts{Updated: 1}, Favorites: ImportCounts{Updated: 1}},
+		},
+		{
+			name: "Records conflict with different records",
+			mode: ModeOverwrite,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", "user0").Return(nil, nil)
+				users.On("FindUserByUsername", "username0").Return(&model.User{Id: "user1"}, nil)
+				favorites.On("FindFavoriteById", "favorite0").Return(nil, nil)
+				favorites.On("FindFavoriteByUserAndDrink", "user0", "drink0").Return(&model.Favorite{Id: "favorite1"}, nil)
+			},
+			expectedReport: ImportReport{Users: ImportCounts{Conflicts: 1}, Favorites: ImportCounts{Conflicts: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exported := exportTestArchive(t)
+			mockUserRepo := repository.NewMockUserRepository(t)
+			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+			tt.mockCalls(mockUserRepo, mockFavoriteRepo)
+
+			report, err := NewImporter(mockUserRepo, mockFavoriteRepo, tt.mode, tt.dryRun).Import(bytes.NewReader(exported))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("overwrite")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeOverwrite, mode)
+	_, err = ParseMode("replace")
+	assert.Error(t, err)
+}
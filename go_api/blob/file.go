@@ -0,0 +1,83 @@
+package blob
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps each object in a file under its directory, at the path given by the object's key
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates the directory if it doesn't exist yet
+func NewFileStore(dir string) (FileStore, error) {
+	if dir == "" {
+		return FileStore{}, fmt.Errorf("the blob store directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return FileStore{}, err
+	}
+	return FileStore{dir: dir}, nil
+}
+
+// Put writes the data to a temporary file first and renames it over the key's file,
+// so a reader never sees a partly written object
+func (s FileStore) Put(key string, data []byte) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filePath)
+}
+
+func (s FileStore) Get(key string) ([]byte, bool, error) {
+	filePath, err := s.path(key)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+func (s FileStore) Delete(key string) error {
+	filePath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// path is the file of the key; keys that could refer to a file outside of the store's directory are rejected
+func (s FileStore) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
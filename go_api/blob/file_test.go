@@ -0,0 +1,49 @@
+package blob
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "blobs"))
+	assert.NoError(t, err, "No error should have been returned from NewFileStore")
+
+	_, found, err := store.Get("avatars/0/avatar.png")
+	assert.NoError(t, err, "No error should have been returned from Get")
+	assert.False(t, found, "Nothing should have been found before the key was put")
+
+	assert.NoError(t, store.Put("avatars/0/avatar.png", []byte("first")), "No error should have been returned from Put")
+	assert.NoError(t, store.Put("avatars/0/avatar.png", []byte("second")), "No error should have been returned from Put")
+	data, found, err := store.Get("avatars/0/avatar.png")
+	assert.NoError(t, err, "No error should have been returned from Get")
+	assert.True(t, found, "The data should have been found")
+	assert.Equal(t, []byte("second"), data, "The second put should have replaced the first")
+
+	assert.NoError(t, store.Delete("avatars/0/avatar.png"), "No error should have been returned from Delete")
+	_, found, err = store.Get("avatars/0/avatar.png")
+	assert.NoError(t, err, "No error should have been returned from Get")
+	assert.False(t, found, "The data should have been deleted")
+	assert.NoError(t, store.Delete("avatars/0/avatar.png"), "Deleting a missing key should do nothing")
+
+	entries, err := os.ReadDir(filepath.Join(store.dir, "avatars", "0"))
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "No temporary files should have been left behind")
+}
+
+func TestFileStore_InvalidKeys(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	assert.NoError(t, err, "No error should have been returned from NewFileStore")
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "avatars/../../outside", "avatars//0", "avatars/.hidden", `avatars\0`} {
+		t.Run(key, func(t *testing.T) {
+			assert.Error(t, store.Put(key, []byte("data")), "An error should have been returned from Put")
+			_, _, err := store.Get(key)
+			assert.Error(t, err, "An error should have been returned from Get")
+			assert.Error(t, store.Delete(key), "An error should have been returned from Delete")
+		})
+	}
+}
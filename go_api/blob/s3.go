@@ -0,0 +1,93 @@
+//go:generate mockery --name=S3Client --output=./ --outpkg=blob --filename=s3_client_mock.go --inpackage
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Client is the part of the S3 client that S3Store uses
+type S3Client interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// S3Store keeps each object in an S3 bucket under its key, or in localstack's S3 if an endpoint is given;
+// unlike a FileStore, it's shared by every instance of the api and every lambda container
+type S3Store struct {
+	client S3Client
+	bucket string
+}
+
+func NewS3Store(awsEndpoint, bucket string) (S3Store, error) {
+	if bucket == "" {
+		return S3Store{}, fmt.Errorf("the blob store bucket must not be empty")
+	}
+	cfg, err := config.LoadDefaultConfig(context.TODO())
+	if err != nil {
+		return S3Store{}, err
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// only set the endpoint resolver if an endpoint is provided; localstack only serves path-style urls
+		if awsEndpoint != "" {
+			o.EndpointResolver = s3.EndpointResolverFromURL(awsEndpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return S3Store{client: client, bucket: bucket}, nil
+}
+
+func (s S3Store) Put(key string, data []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	_, err := s.client.PutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s S3Store) Get(key string) ([]byte, bool, error) {
+	if err := checkKey(key); err != nil {
+		return nil, false, err
+	}
+	output, err := s.client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if errors.As(err, new(*types.NoSuchKey)) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer output.Body.Close()
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Delete doesn't need to check that the object exists, since S3 doesn't fail to delete a missing key
+func (s S3Store) Delete(key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
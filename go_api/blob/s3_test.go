@@ -0,0 +1,67 @@
+package blob
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestS3Store(t *testing.T) {
+	client := NewMockS3Client(t)
+	store := S3Store{client: client, bucket: "blobs"}
+	isObject := func(key string) func(input interface{}) bool {
+		return func(input interface{}) bool {
+			switch input := input.(type) {
+			case *s3.PutObjectInput:
+				data, _ := io.ReadAll(input.Body)
+				return *input.Bucket == "blobs" && *input.Key == key && string(data) == "avatar"
+			case *s3.GetObjectInput:
+				return *input.Bucket == "blobs" && *input.Key == key
+			case *s3.DeleteObjectInput:
+				return *input.Bucket == "blobs" && *input.Key == key
+			}
+			return false
+		}
+	}
+
+	client.On("PutObject", mock.Anything, mock.MatchedBy(isObject("avatars/0/avatar.png"))).Return(&s3.PutObjectOutput{}, nil).Once()
+	assert.NoError(t, store.Put("avatars/0/avatar.png", []byte("avatar")), "No error should have been returned from Put")
+
+	client.On("GetObject", mock.Anything, mock.MatchedBy(isObject("avatars/0/avatar.png"))).
+		Return(&s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader([]byte("avatar")))}, nil).Once()
+	data, found, err := store.Get("avatars/0/avatar.png")
+	assert.NoError(t, err, "No error should have been returned from Get")
+	assert.True(t, found, "The data should have been found")
+	assert.Equal(t, []byte("avatar"), data)
+
+	client.On("GetObject", mock.Anything, mock.MatchedBy(isObject("avatars/0/missing.png"))).Return(nil, &types.NoSuchKey{}).Once()
+	_, found, err = store.Get("avatars/0/missing.png")
+	assert.NoError(t, err, "A missing key shouldn't be an error")
+	assert.False(t, found, "Nothing should have been found under a missing key")
+
+	client.On("GetObject", mock.Anything, mock.MatchedBy(isObject("avatars/0/failed.png"))).Return(nil, errors.New("failed")).Once()
+	_, _, err = store.Get("avatars/0/failed.png")
+	assert.Error(t, err, "An error should have been returned from Get")
+
+	client.On("DeleteObject", mock.Anything, mock.MatchedBy(isObject("avatars/0/avatar.png"))).Return(&s3.DeleteObjectOutput{}, nil).Once()
+	assert.NoError(t, store.Delete("avatars/0/avatar.png"), "No error should have been returned from Delete")
+}
+
+func TestS3Store_InvalidKeys(t *testing.T) {
+	// the mock fails the test if the client is called
+	store := S3Store{client: NewMockS3Client(t), bucket: "blobs"}
+	for _, key := range []string{"", "/etc/passwd", "../outside", "avatars//0", "avatars/.hidden"} {
+		t.Run(key, func(t *testing.T) {
+			assert.Error(t, store.Put(key, []byte("data")), "An error should have been returned from Put")
+			_, _, err := store.Get(key)
+			assert.Error(t, err, "An error should have been returned from Get")
+			assert.Error(t, store.Delete(key), "An error should have been returned from Delete")
+		})
+	}
+}
@@ -0,0 +1,86 @@
+//go:generate mockery --name=Store --output=./ --outpkg=blob --filename=store_mock.go --inpackage
+
+// Package blob stores binary objects, such as users' avatar images, by key.
+//
+// Keys are slash-separated paths like "avatars/<user id>/<name>.png"; the Store behind them is pluggable,
+// so the images can be kept on the local filesystem or in S3 without changing the services.
+package blob
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+)
+
+// Store keeps binary objects by key
+type Store interface {
+	// Put saves the data under the key, replacing anything already saved under it
+	Put(key string, data []byte) error
+	// Get returns the data saved under the key; the bool is false if nothing is saved under it
+	Get(key string) ([]byte, bool, error)
+	// Delete removes the data saved under the key; deleting a key that has no data does nothing
+	Delete(key string) error
+}
+
+// NewStore creates the store selected by the app config's BlobStore
+func NewStore(appConfig model.AppConfig) (Store, error) {
+	switch appConfig.BlobStore {
+	case "", model.BlobStoreFile:
+		return NewFileStore(appConfig.BlobStoreDir)
+	case model.BlobStoreS3:
+		return NewS3Store(appConfig.AwsEndpoint, appConfig.BlobStoreBucket)
+	default:
+		return nil, fmt.Errorf("invalid blob store '%s'; it must be '%s' or '%s'", appConfig.BlobStore, model.BlobStoreFile, model.BlobStoreS3)
+	}
+}
+
+// NewSharedStore creates the store selected by the app config's BlobStore, as long as it's shared by every instance
+// of the api, which a lambda needs, since each of its containers has its own filesystem
+func NewSharedStore(appConfig model.AppConfig) (Store, error) {
+	if appConfig.BlobStore == "" || appConfig.BlobStore == model.BlobStoreFile {
+		return nil, fmt.Errorf("the '%s' blob store keeps blobs on the local filesystem, which isn't shared; BLOB_STORE must be '%s'",
+			model.BlobStoreFile, model.BlobStoreS3)
+	}
+	return NewStore(appConfig)
+}
+
+// UnavailableStore is the store used when no usable store is configured: nothing can be saved, nothing is found,
+// and deleting does nothing
+type UnavailableStore struct {
+	reason string
+}
+
+// NewUnavailableStore creates a store whose Put returns the UnavailableError with the reason
+func NewUnavailableStore(reason string) UnavailableStore {
+	return UnavailableStore{reason: reason}
+}
+
+func (s UnavailableStore) Put(key string, data []byte) error {
+	return apperrors.NewUnavailableError(s.reason, nil)
+}
+
+func (s UnavailableStore) Get(key string) ([]byte, bool, error) {
+	return nil, false, nil
+}
+
+func (s UnavailableStore) Delete(key string) error {
+	return nil
+}
+
+// checkKey rejects keys that aren't clean, relative, slash-separated paths, or that have hidden elements,
+// so that a key can't refer to anything outside of a store
+func checkKey(key string) error {
+	if key == "" || strings.Contains(key, `\`) || strings.HasPrefix(key, "/") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid blob key '%s'", key)
+	}
+	for _, element := range strings.Split(key, "/") {
+		if strings.HasPrefix(element, ".") {
+			return fmt.Errorf("invalid blob key '%s'", key)
+		}
+	}
+	return nil
+}
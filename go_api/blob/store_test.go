@@ -0,0 +1,53 @@
+package blob
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStore(t *testing.T) {
+	store, err := NewStore(model.AppConfig{BlobStoreDir: filepath.Join(t.TempDir(), "blobs")})
+	assert.NoError(t, err, "No error should have been returned from NewStore")
+	assert.IsType(t, FileStore{}, store, "The file store should be the default")
+
+	store, err = NewStore(model.AppConfig{BlobStore: model.BlobStoreS3, BlobStoreBucket: "blobs"})
+	assert.NoError(t, err, "No error should have been returned from NewStore")
+	assert.IsType(t, S3Store{}, store)
+
+	_, err = NewStore(model.AppConfig{BlobStore: model.BlobStoreS3})
+	assert.Error(t, err, "An error should have been returned without a bucket")
+
+	_, err = NewStore(model.AppConfig{BlobStore: "tape"})
+	assert.Error(t, err, "An error should have been returned for an unknown store")
+}
+
+func TestNewSharedStore(t *testing.T) {
+	for _, blobStore := range []string{"", model.BlobStoreFile} {
+		_, err := NewSharedStore(model.AppConfig{BlobStore: blobStore, BlobStoreDir: t.TempDir()})
+		assert.Error(t, err, "The file store isn't shared, so it should have been refused")
+	}
+
+	store, err := NewSharedStore(model.AppConfig{BlobStore: model.BlobStoreS3, BlobStoreBucket: "blobs"})
+	assert.NoError(t, err, "No error should have been returned from NewSharedStore")
+	assert.IsType(t, S3Store{}, store)
+}
+
+func TestUnavailableStore(t *testing.T) {
+	store := NewUnavailableStore("avatars are disabled")
+
+	err := store.Put("avatars/0/avatar.png", []byte("avatar"))
+	assert.True(t, errors.As(err, &apperrors.UnavailableError{}), "Put should have returned the UnavailableError")
+	assert.EqualError(t, err, "avatars are disabled")
+
+	_, found, err := store.Get("avatars/0/avatar.png")
+	assert.NoError(t, err, "No error should have been returned from Get")
+	assert.False(t, found, "Nothing should be found")
+
+	assert.NoError(t, store.Delete("avatars/0/avatar.png"), "Deleting should do nothing")
+}
@@ -0,0 +1,40 @@
+// Package catalog creates the drinks that the api serves, which are either the drink catalog or a TheCocktailDB
+// dump, as selected by the app config. It's separate from repository and service because the dumps are read by
+// cocktaildb, which depends on both.
+package catalog
+
+import (
+	"fmt"
+
+	"the-drink-almanac-api/cocktaildb"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+// NewDrinkRepository creates the drink catalog's repository, or reads the drinks of the app config's DrinkDataset
+// into memory if it's set; the repository is meant to be created once and shared by everything that reads drinks,
+// so that a dataset is only read once and every endpoint agrees on which drinks exist
+func NewDrinkRepository(appConfig model.AppConfig) (repository.DrinkRepository, error) {
+	if appConfig.DrinkDataset != "" {
+		drinks, err := cocktaildb.NewDumpRepository(appConfig.DrinkDataset)
+		if err != nil {
+			return nil, err
+		}
+		return drinks, nil
+	}
+	return repository.NewDrinkRepository(appConfig)
+}
+
+// NewDrinkLookup creates the lookup selected by the app config's DrinkLookup, which checks the given drinks
+func NewDrinkLookup(appConfig model.AppConfig, drinks repository.DrinkRepository) (service.DrinkLookup, error) {
+	switch appConfig.DrinkLookup {
+	case model.DrinkLookupNone:
+		return service.UncheckedDrinkLookup{}, nil
+	case "", model.DrinkLookupCatalog:
+		return service.NewCatalogDrinkLookup(drinks), nil
+	default:
+		return nil, fmt.Errorf("invalid drink lookup '%s'; it must be '%s' or '%s'",
+			appConfig.DrinkLookup, model.DrinkLookupNone, model.DrinkLookupCatalog)
+	}
+}
@@ -0,0 +1,52 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDrinkRepository(t *testing.T) {
+	drinks, err := NewDrinkRepository(model.AppConfig{DrinksTableName: "drinks"})
+	assert.NoError(t, err, "No error should have been returned from NewDrinkRepository")
+	assert.IsType(t, &repository.DrinkRepositoryDDB{}, drinks, "The catalog should be read without a dataset")
+
+	path := filepath.Join(t.TempDir(), "drinks.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"drinks": [
+		{"idDrink": "11007", "strDrink": "Margarita", "strIngredient1": "Tequila"},
+		{"idDrink": "11000"}
+	]}`), 0o644))
+	drinks, err = NewDrinkRepository(model.AppConfig{DrinkDataset: path})
+	assert.NoError(t, err, "No error should have been returned from NewDrinkRepository")
+	allDrinks, _ := drinks.FindAll()
+	assert.Len(t, allDrinks, 1, "Only the dataset's well-formed drinks should be read")
+	assert.Equal(t, "Margarita", allDrinks[0].Name)
+
+	_, err = NewDrinkRepository(model.AppConfig{DrinkDataset: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err, "An error should have been returned for a missing dataset")
+}
+
+func TestNewDrinkLookup(t *testing.T) {
+	drinks := repository.NewMemoryDrinkRepository(model.Drink{Id: "11007", Name: "Margarita"})
+
+	lookup, err := NewDrinkLookup(model.AppConfig{DrinkLookup: model.DrinkLookupNone}, drinks)
+	assert.NoError(t, err, "No error should have been returned from NewDrinkLookup")
+	assert.Equal(t, service.UncheckedDrinkLookup{}, lookup, "No lookup should check every drink id")
+
+	lookup, err = NewDrinkLookup(model.AppConfig{}, drinks)
+	assert.NoError(t, err, "No error should have been returned from NewDrinkLookup")
+	assert.IsType(t, service.CatalogDrinkLookup{}, lookup, "The drinks should be checked by default")
+	exists, _ := lookup.DrinkExists("11007")
+	assert.True(t, exists, "The given drinks should be checked")
+	exists, _ = lookup.DrinkExists("banana")
+	assert.False(t, exists, "Only the given drinks should exist")
+
+	_, err = NewDrinkLookup(model.AppConfig{DrinkLookup: "file"}, drinks)
+	assert.Error(t, err, "An error should have been returned for an unknown lookup")
+}
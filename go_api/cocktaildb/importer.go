@@ -0,0 +1,135 @@
+package cocktaildb
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+// Problem describes a drink of a dump that couldn't be imported
+type Problem struct {
+	File string
+	// Index is the position of the drink in the dump
+	Index int
+	// Id is empty if the drink is too malformed to have one
+	Id     string
+	Reason string
+}
+
+// Report counts what happened to the drinks of the dumps; a drink that appears in several dumps is counted each time
+type Report struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Malformed int
+	Problems  []Problem
+}
+
+// Importer upserts the drinks of dumps into the catalog
+type Importer struct {
+	drinks service.DrinkService
+	dryRun bool
+}
+
+// NewImporter creates an importer; when dryRun is set, the drinks are checked and counted but not saved
+func NewImporter(drinks service.DrinkService, dryRun bool) Importer {
+	return Importer{
+		drinks: drinks,
+		dryRun: dryRun,
+	}
+}
+
+// Import normalizes and saves every drink of the dump, so importing the same dump again only updates
+// the drinks that changed; malformed drinks are reported and skipped, and only failing to read
+// or save the catalog returns an error
+func (i Importer) Import(file string, records []RawRecord, report *Report) error {
+	for _, raw := range records {
+		drink, problem := normalizeRaw(file, raw)
+		if problem != nil {
+			report.Malformed++
+			report.Problems = append(report.Problems, *problem)
+			continue
+		}
+
+		if err := i.save(drink, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i Importer) save(drink model.Drink, report *Report) error {
+	existing, err := i.drinks.FindDrinkById(drink.Id)
+	if err != nil {
+		return err
+	}
+	if existing != nil && reflect.DeepEqual(*existing, drink) {
+		report.Unchanged++
+		return nil
+	}
+	if !i.dryRun {
+		if err := i.drinks.SaveDrink(drink); err != nil {
+			return err
+		}
+	}
+	if existing == nil {
+		report.Created++
+	} else {
+		report.Updated++
+	}
+	return nil
+}
+
+// ReadDrinks reads and normalizes the drinks of a dump, e.g. to search a dump without importing it;
+// malformed drinks are left out and returned as problems
+func ReadDrinks(path string) ([]model.Drink, []Problem, error) {
+	records, err := ReadDump(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	drinks := []model.Drink{}
+	problems := []Problem{}
+	for _, raw := range records {
+		drink, problem := normalizeRaw(path, raw)
+		if problem != nil {
+			problems = append(problems, *problem)
+			continue
+		}
+		drinks = append(drinks, drink)
+	}
+	return drinks, problems, nil
+}
+
+// normalizeRaw decodes and normalizes the drink, or describes why it's malformed
+func normalizeRaw(file string, raw RawRecord) (model.Drink, *Problem) {
+	problem := &Problem{File: file, Index: raw.Index}
+	record, err := raw.Decode()
+	if err != nil {
+		problem.Reason = err.Error()
+		return model.Drink{}, problem
+	}
+	problem.Id = record.field("idDrink")
+	drink, err := Normalize(record)
+	if err != nil {
+		problem.Reason = err.Error()
+		return model.Drink{}, problem
+	}
+	return drink, nil
+}
+
+// NewDumpRepository reads the drinks of the dump into a drink repository that's kept in memory,
+// e.g. to serve the drinks without a catalog table; malformed drinks are only counted on stderr
+func NewDumpRepository(path string) (*repository.MemoryDrinkRepository, error) {
+	drinks, problems, err := ReadDrinks(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d malformed drinks of %s\n", len(problems), path)
+	}
+	return repository.NewMemoryDrinkRepository(drinks...), nil
+}
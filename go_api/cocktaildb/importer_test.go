@@ -0,0 +1,76 @@
+package cocktaildb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+func rawRecords(drinks ...string) []RawRecord {
+	records := make([]RawRecord, len(drinks))
+	for i, drink := range drinks {
+		records[i] = RawRecord{Index: i, Data: []byte(drink)}
+	}
+	return records
+}
+
+func TestImporter_Import(t *testing.T) {
+	records := rawRecords(
+		`{"idDrink": "11007", "strDrink": "Margarita", "strIngredient1": "Tequila", "strMeasure1": "1 1/2 oz"}`,
+		`{"idDrink": "11008", "strDrink": "Manhattan", "strIngredient1": "Sweet Vermouth", "strMeasure1": "3/4 oz"}`,
+		`{"idDrink": "11009", "strDrink": "Moscow Mule"}`,
+		`{"idDrink": 11010}`,
+	)
+	repo := repository.NewMemoryDrinkRepository()
+	importer := NewImporter(service.NewDefaultDrinkService(repo), false)
+
+	report := Report{}
+	err := importer.Import("drinks.json", records, &report)
+	assert.NoError(t, err, "No error should have been returned from Import")
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Malformed)
+	assert.Equal(t, "11009", report.Problems[0].Id)
+	assert.Equal(t, 2, report.Problems[0].Index)
+	assert.Equal(t, "", report.Problems[1].Id, "The id of an undecodable drink should be unknown")
+	assert.Equal(t, "drinks.json", report.Problems[1].File)
+
+	// importing the dump again with one drink changed only updates that drink
+	records[1] = RawRecord{Index: 1, Data: []byte(`{"idDrink": "11008", "strDrink": "Manhattan", "strIngredient1": "Sweet Vermouth", "strMeasure1": "1 oz"}`)}
+	report = Report{}
+	err = importer.Import("drinks.json", records, &report)
+	assert.NoError(t, err, "No error should have been returned from Import")
+	assert.Equal(t, Report{Updated: 1, Unchanged: 1, Malformed: 2, Problems: report.Problems}, report)
+
+	drink, err := repo.FindDrinkById("11008")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Ingredient{{Name: "Sweet Vermouth", Measure: "1 oz"}}, drink.Ingredients)
+}
+
+func TestImporter_Import_DryRun(t *testing.T) {
+	repo := repository.NewMemoryDrinkRepository()
+	importer := NewImporter(service.NewDefaultDrinkService(repo), true)
+
+	report := Report{}
+	err := importer.Import("drinks.json", rawRecords(`{"idDrink": "11007", "strDrink": "Margarita", "strIngredient1": "Tequila"}`), &report)
+	assert.NoError(t, err, "No error should have been returned from Import")
+	assert.Equal(t, 1, report.Created)
+
+	drinks, err := repo.FindAll()
+	assert.NoError(t, err)
+	assert.Empty(t, drinks, "A dry run shouldn't have saved anything")
+}
+
+func TestImporter_Import_SaveError(t *testing.T) {
+	mockDrinkService := service.NewMockDrinkService(t)
+	mockDrinkService.On("FindDrinkById", "11007").Return(nil, nil)
+	mockDrinkService.On("SaveDrink", mock.AnythingOfType("model.Drink")).Return(errors.New("testing"))
+	importer := NewImporter(mockDrinkService, false)
+
+	err := importer.Import("drinks.json", rawRecords(`{"idDrink": "11007", "strDrink": "Margarita", "strIngredient1": "Tequila"}`), &Report{})
+	assert.Error(t, err, "The error from saving the drink should have been returned")
+}
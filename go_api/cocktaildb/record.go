@@ -0,0 +1,145 @@
+// Package cocktaildb imports drinks from local dumps of TheCocktailDB's JSON into the drink catalog,
+// so the catalog can be populated without the api calling TheCocktailDB at runtime.
+//
+// A dump is the body of one of TheCocktailDB's lookup or search responses, {"drinks": [...]},
+// or just the array of drinks. Each drink has up to 15 numbered ingredients and measures:
+//
+//	{"idDrink": "11007", "strDrink": "Margarita", "strIngredient1": "Tequila", "strMeasure1": "1 1/2 oz ", ...}
+package cocktaildb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	jsoniter "github.com/json-iterator/go"
+	"the-drink-almanac-api/model"
+)
+
+// maxIngredients is the number of strIngredientN and strMeasureN fields of each drink
+const maxIngredients = 15
+
+// Record is a single drink of a dump; every field is a string or null
+type Record map[string]*string
+
+// field returns the trimmed value of the field, or "" if it's missing or null
+func (r Record) field(name string) string {
+	value := r[name]
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
+
+// RawRecord is a drink of a dump that hasn't been decoded yet, so that a malformed drink
+// can be reported without rejecting the rest of the dump
+type RawRecord struct {
+	// Index is the position of the drink in the dump
+	Index int
+	Data  jsoniter.RawMessage
+}
+
+// Decode decodes the drink, returning an error if any field isn't a string or null
+func (r RawRecord) Decode() (Record, error) {
+	record := Record{}
+	if err := jsoniter.Unmarshal(r.Data, &record); err != nil {
+		return nil, fmt.Errorf("the drink isn't an object of strings: %w", err)
+	}
+	return record, nil
+}
+
+// ReadDump reads the drinks of a dump file without decoding them
+func ReadDump(path string) ([]RawRecord, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var drinks []jsoniter.RawMessage
+	if trimmed := bytes.TrimSpace(contents); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = jsoniter.Unmarshal(trimmed, &drinks)
+	} else {
+		// TheCocktailDB returns {"drinks": null} when a search doesn't match anything
+		var response struct {
+			Drinks []jsoniter.RawMessage `json:"drinks"`
+		}
+		err = jsoniter.Unmarshal(contents, &response)
+		drinks = response.Drinks
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the dump '%s': %w", path, err)
+	}
+
+	records := make([]RawRecord, len(drinks))
+	for i, data := range drinks {
+		records[i] = RawRecord{Index: i, Data: data}
+	}
+	return records, nil
+}
+
+// Normalize converts the record into a drink of the catalog:
+//   - the numbered ingredients become a list, skipping the empty ones, with each measure next to its ingredient
+//   - whitespace is trimmed and collapsed, and categories are title-cased (e.g. "Ordinary drink" becomes "Ordinary Drink")
+//   - strAlcoholic is "Alcoholic", "Non alcoholic" or "Optional alcohol"; only non-alcoholic drinks aren't alcoholic
+//
+// An error is returned if the record doesn't have an id, a name or any ingredients,
+// or if it has a measure without an ingredient
+func Normalize(record Record) (model.Drink, error) {
+	drink := model.Drink{
+		Id:           record.field("idDrink"),
+		Name:         collapseSpaces(record.field("strDrink")),
+		Category:     titleCase(collapseSpaces(record.field("strCategory"))),
+		Glass:        collapseSpaces(record.field("strGlass")),
+		Instructions: record.field("strInstructions"),
+		ImageUrl:     record.field("strDrinkThumb"),
+		Alcoholic:    isAlcoholic(record.field("strAlcoholic")),
+	}
+	if drink.Id == "" {
+		return drink, errors.New("the drink doesn't have an idDrink")
+	}
+	if drink.Name == "" {
+		return drink, errors.New("the drink doesn't have a strDrink")
+	}
+
+	for i := 1; i <= maxIngredients; i++ {
+		name := collapseSpaces(record.field(fmt.Sprintf("strIngredient%d", i)))
+		measure := collapseSpaces(record.field(fmt.Sprintf("strMeasure%d", i)))
+		if name == "" {
+			if measure != "" {
+				return drink, fmt.Errorf("strMeasure%d '%s' doesn't have an ingredient", i, measure)
+			}
+			continue
+		}
+		drink.Ingredients = append(drink.Ingredients, model.Ingredient{Name: name, Measure: measure})
+	}
+	if len(drink.Ingredients) == 0 {
+		return drink, errors.New("the drink doesn't have any ingredients")
+	}
+	return drink, nil
+}
+
+func isAlcoholic(value string) bool {
+	normalized := strings.ToLower(strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}), ""))
+	return normalized != "nonalcoholic"
+}
+
+func collapseSpaces(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func titleCase(value string) string {
+	words := strings.Split(value, " ")
+	for i, word := range words {
+		runes := []rune(strings.ToLower(word))
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
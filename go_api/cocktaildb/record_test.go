@@ -0,0 +1,189 @@
+package cocktaildb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func TestNormalize(t *testing.T) {
+	margarita := Record{
+		"idDrink":           stringPointer("11007"),
+		"strDrink":          stringPointer(" Margarita"),
+		"strCategory":       stringPointer("ordinary  drink"),
+		"strAlcoholic":      stringPointer("Alcoholic"),
+		"strGlass":          stringPointer("Cocktail glass"),
+		"strInstructions":   stringPointer("Rub the rim of the glass with the lime slice to make the salt stick to it. "),
+		"strDrinkThumb":     stringPointer("https://www.thecocktaildb.com/images/media/drink/5noda61589575158.jpg"),
+		"strIngredient1":    stringPointer("Tequila"),
+		"strMeasure1":       stringPointer("1 1/2  oz "),
+		"strIngredient2":    stringPointer("Triple sec"),
+		"strMeasure2":       stringPointer("1/2 oz"),
+		"strIngredient3":    stringPointer(""),
+		"strMeasure3":       nil,
+		"strIngredient4":    stringPointer("Salt"),
+		"strMeasure4":       nil,
+		"strIngredient5":    nil,
+		"strImageSource":    nil,
+		"strDrinkAlternate": nil,
+	}
+	expectedMargarita := model.Drink{
+		Id:           "11007",
+		Name:         "Margarita",
+		Category:     "Ordinary Drink",
+		Glass:        "Cocktail glass",
+		Instructions: "Rub the rim of the glass with the lime slice to make the salt stick to it.",
+		Ingredients: []model.Ingredient{
+			{Name: "Tequila", Measure: "1 1/2 oz"},
+			{Name: "Triple sec", Measure: "1/2 oz"},
+			{Name: "Salt"},
+		},
+		ImageUrl:  "https://www.thecocktaildb.com/images/media/drink/5noda61589575158.jpg",
+		Alcoholic: true,
+	}
+	withFields := func(fields Record) Record {
+		record := Record{}
+		for name, value := range margarita {
+			record[name] = value
+		}
+		for name, value := range fields {
+			record[name] = value
+		}
+		return record
+	}
+
+	tests := []struct {
+		name          string
+		record        Record
+		expectedDrink model.Drink
+		expectError   bool
+	}{
+		{
+			name:          "Normalizes a drink",
+			record:        margarita,
+			expectedDrink: expectedMargarita,
+		},
+		{
+			name:   "Non-alcoholic drink",
+			record: withFields(Record{"strAlcoholic": stringPointer("Non alcoholic")}),
+			expectedDrink: func() model.Drink {
+				drink := expectedMargarita
+				drink.Alcoholic = false
+				return drink
+			}(),
+		},
+		{
+			name:          "Optional alcohol is alcoholic",
+			record:        withFields(Record{"strAlcoholic": stringPointer("Optional alcohol")}),
+			expectedDrink: expectedMargarita,
+		},
+		{
+			name:        "Missing id",
+			record:      withFields(Record{"idDrink": nil}),
+			expectError: true,
+		},
+		{
+			name:        "Missing name",
+			record:      withFields(Record{"strDrink": stringPointer("  ")}),
+			expectError: true,
+		},
+		{
+			name:        "Measure without an ingredient",
+			record:      withFields(Record{"strMeasure3": stringPointer("1 oz")}),
+			expectError: true,
+		},
+		{
+			name:        "No ingredients",
+			record:      Record{"idDrink": stringPointer("11007"), "strDrink": stringPointer("Margarita")},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drink, err := Normalize(tt.record)
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from Normalize")
+				return
+			}
+			assert.NoError(t, err, "No error should have been returned from Normalize")
+			assert.Equal(t, tt.expectedDrink, drink)
+		})
+	}
+}
+
+func TestReadDump(t *testing.T) {
+	tests := []struct {
+		name            string
+		contents        string
+		expectedRecords int
+		expectError     bool
+	}{
+		{
+			name:            "Reads a lookup response",
+			contents:        `{"drinks": [{"idDrink": "11007"}, {"idDrink": "11008"}]}`,
+			expectedRecords: 2,
+		},
+		{
+			name:            "Reads an array of drinks",
+			contents:        ` [{"idDrink": "11007"}]`,
+			expectedRecords: 1,
+		},
+		{
+			name:     "Reads a search without matches",
+			contents: `{"drinks": null}`,
+		},
+		{
+			name:        "Rejects invalid json",
+			contents:    `{"drinks": [`,
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "drinks.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o600))
+
+			records, err := ReadDump(path)
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from ReadDump")
+				return
+			}
+			assert.NoError(t, err, "No error should have been returned from ReadDump")
+			assert.Len(t, records, tt.expectedRecords)
+			for i, record := range records {
+				assert.Equal(t, i, record.Index)
+			}
+		})
+	}
+}
+
+func TestRawRecord_Decode(t *testing.T) {
+	record, err := RawRecord{Data: []byte(`{"idDrink": "11007", "strVideo": null}`)}.Decode()
+	assert.NoError(t, err, "No error should have been returned from Decode")
+	assert.Equal(t, "11007", record.field("idDrink"))
+	assert.Equal(t, "", record.field("strVideo"))
+
+	_, err = RawRecord{Data: []byte(`{"idDrink": 11007}`)}.Decode()
+	assert.Error(t, err, "A number should have been rejected")
+}
+
+func TestReadDrinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drinks.json")
+	contents := `{"drinks": [
+		{"idDrink": "11007", "strDrink": "Margarita", "strIngredient1": "Tequila"},
+		{"idDrink": "11008", "strDrink": "Manhattan"}
+	]}`
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	drinks, problems, err := ReadDrinks(path)
+	assert.NoError(t, err, "No error should have been returned from ReadDrinks")
+	assert.Equal(t, []model.Drink{{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila"}}, Alcoholic: true}}, drinks)
+	assert.Equal(t, []Problem{{File: path, Index: 1, Id: "11008", Reason: "the drink doesn't have any ingredients"}}, problems)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"the-drink-almanac-api/model"
+)
+
+// commands maps the name of each subcommand of the api binary to the function that runs it;
+// running the binary without a subcommand starts the api
+var commands = map[string]func(appConfig model.AppConfig, args []string) error{
+	"export":             runExport,
+	"import":             runImport,
+	"import-drinks":      runImportDrinks,
+	"migrate":            runMigrate,
+	"migrate-legacy":     runMigrateLegacy,
+	"orphaned-favorites": runOrphanedFavorites,
+	"relay-events":       runRelayEvents,
+	"seed":               runSeed,
+}
+
+func runCommand(name string, args []string) error {
+	command, ok := commands[name]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for commandName := range commands {
+			names = append(names, commandName)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown command '%s'; available commands: %s", name, strings.Join(names, ", "))
+	}
+	return command(model.NewAppConfig(), args)
+}
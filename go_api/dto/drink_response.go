@@ -0,0 +1,50 @@
+package dto
+
+import "the-drink-almanac-api/model"
+
+type DrinkResponse struct {
+	Id           string               `json:"id"`
+	Name         string               `json:"name"`
+	Category     string               `json:"category,omitempty"`
+	Glass        string               `json:"glass,omitempty"`
+	Instructions string               `json:"instructions,omitempty"`
+	Ingredients  []IngredientResponse `json:"ingredients"`
+	ImageUrl     string               `json:"imageUrl,omitempty"`
+	Alcoholic    bool                 `json:"alcoholic"`
+	// Servings and Units are only set when the recipe was formatted, see NewFormattedDrinkResponse
+	Servings int    `json:"servings,omitempty"`
+	Units    string `json:"units,omitempty"`
+}
+
+type IngredientResponse struct {
+	Name    string `json:"name"`
+	Measure string `json:"measure,omitempty"`
+}
+
+func NewDrinkResponse(drink model.Drink) DrinkResponse {
+	ingredients := make([]IngredientResponse, len(drink.Ingredients))
+	for i, ingredient := range drink.Ingredients {
+		ingredients[i] = IngredientResponse{
+			Name:    ingredient.Name,
+			Measure: ingredient.Measure,
+		}
+	}
+	return DrinkResponse{
+		Id:           drink.Id,
+		Name:         drink.Name,
+		Category:     drink.Category,
+		Glass:        drink.Glass,
+		Instructions: drink.Instructions,
+		Ingredients:  ingredients,
+		ImageUrl:     drink.ImageUrl,
+		Alcoholic:    drink.Alcoholic,
+	}
+}
+
+func NewDrinksResponse(drinks []model.Drink) []DrinkResponse {
+	drinksResponse := make([]DrinkResponse, len(drinks))
+	for i, drink := range drinks {
+		drinksResponse[i] = NewDrinkResponse(drink)
+	}
+	return drinksResponse
+}
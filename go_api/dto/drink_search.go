@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"the-drink-almanac-api/search"
+)
+
+const (
+	defaultDrinkSearchLimit = 20
+	maxDrinkSearchLimit     = 100
+)
+
+// NewDrinkSearchQuery parses the query parameters of a drink search:
+//   - `q`: words that the drink's name or instructions must contain
+//   - `ingredient`: comma-separated ingredients that the drink must have
+//   - `category`, `glass`: the drink's category and glass
+//   - `alcoholic`: `true` or `false`
+//   - `sort`: `relevance` (the default) or `popularity`
+//   - `offset`, `limit`: the page of drinks to return; the limit defaults to 20 and can be at most 100
+//
+// Repeated parameters should be joined with commas first, the way API Gateway passes them to lambdas
+func NewDrinkSearchQuery(params map[string]string) (search.Query, error) {
+	query := search.Query{
+		Text:     params["q"],
+		Category: params["category"],
+		Glass:    params["glass"],
+		Sort:     search.SortRelevance,
+		Limit:    defaultDrinkSearchLimit,
+	}
+	if ingredients := params["ingredient"]; ingredients != "" {
+		query.Ingredients = strings.Split(ingredients, ",")
+	}
+
+	if alcoholic := params["alcoholic"]; alcoholic != "" {
+		value, err := strconv.ParseBool(alcoholic)
+		if err != nil {
+			return query, fmt.Errorf("invalid alcoholic '%s'; it must be either 'true' or 'false'", alcoholic)
+		}
+		query.Alcoholic = &value
+	}
+
+	switch sort := search.Sort(params["sort"]); sort {
+	case "":
+	case search.SortRelevance, search.SortPopularity:
+		query.Sort = sort
+	default:
+		return query, fmt.Errorf("invalid sort '%s'; the sort must be either 'relevance' or 'popularity'", sort)
+	}
+
+	if offset := params["offset"]; offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			return query, fmt.Errorf("invalid offset '%s'; it must be a number that's at least 0", offset)
+		}
+		query.Offset = value
+	}
+	if limit := params["limit"]; limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > maxDrinkSearchLimit {
+			return query, fmt.Errorf("invalid limit '%s'; it must be a number from 1 to %d", limit, maxDrinkSearchLimit)
+		}
+		query.Limit = value
+	}
+	return query, nil
+}
+
+type DrinkSearchResponse struct {
+	Total  int                       `json:"total"`
+	Offset int                       `json:"offset"`
+	Limit  int                       `json:"limit"`
+	Drinks []DrinkSearchHitResponse  `json:"drinks"`
+	Facets DrinkSearchFacetsResponse `json:"facets"`
+}
+
+type DrinkSearchHitResponse struct {
+	DrinkResponse
+	Score      float64 `json:"score"`
+	Popularity int     `json:"popularity"`
+}
+
+type DrinkSearchFacetsResponse struct {
+	Categories  []FacetValueResponse   `json:"categories"`
+	Glasses     []FacetValueResponse   `json:"glasses"`
+	Ingredients []FacetValueResponse   `json:"ingredients"`
+	Alcoholic   AlcoholicFacetResponse `json:"alcoholic"`
+}
+
+type FacetValueResponse struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type AlcoholicFacetResponse struct {
+	Alcoholic    int `json:"alcoholic"`
+	NonAlcoholic int `json:"nonAlcoholic"`
+}
+
+func NewDrinkSearchResponse(query search.Query, result search.Result) DrinkSearchResponse {
+	drinks := make([]DrinkSearchHitResponse, len(result.Hits))
+	for i, hit := range result.Hits {
+		drinks[i] = DrinkSearchHitResponse{
+			DrinkResponse: NewDrinkResponse(hit.Drink),
+			Score:         hit.Score,
+			Popularity:    hit.Popularity,
+		}
+	}
+	return DrinkSearchResponse{
+		Total:  result.Total,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+		Drinks: drinks,
+		Facets: DrinkSearchFacetsResponse{
+			Categories:  newFacetValuesResponse(result.Facets.Categories),
+			Glasses:     newFacetValuesResponse(result.Facets.Glasses),
+			Ingredients: newFacetValuesResponse(result.Facets.Ingredients),
+			Alcoholic: AlcoholicFacetResponse{
+				Alcoholic:    result.Facets.Alcoholic,
+				NonAlcoholic: result.Facets.NonAlcoholic,
+			},
+		},
+	}
+}
+
+func newFacetValuesResponse(values []search.FacetValue) []FacetValueResponse {
+	response := make([]FacetValueResponse, len(values))
+	for i, value := range values {
+		response[i] = FacetValueResponse{Value: value.Value, Count: value.Count}
+	}
+	return response
+}
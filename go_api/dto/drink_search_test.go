@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/search"
+)
+
+func TestNewDrinkSearchQuery(t *testing.T) {
+	alcoholic := false
+	tests := []struct {
+		name          string
+		params        map[string]string
+		expectedQuery search.Query
+		expectError   bool
+	}{
+		{
+			name:          "Defaults",
+			params:        map[string]string{},
+			expectedQuery: search.Query{Sort: search.SortRelevance, Limit: 20},
+		},
+		{
+			name: "Every parameter",
+			params: map[string]string{
+				"q":          "margarita",
+				"ingredient": "Tequila,Lime juice",
+				"category":   "Ordinary Drink",
+				"glass":      "Cocktail glass",
+				"alcoholic":  "false",
+				"sort":       "popularity",
+				"offset":     "40",
+				"limit":      "100",
+			},
+			expectedQuery: search.Query{
+				Text:        "margarita",
+				Ingredients: []string{"Tequila", "Lime juice"},
+				Category:    "Ordinary Drink",
+				Glass:       "Cocktail glass",
+				Alcoholic:   &alcoholic,
+				Sort:        search.SortPopularity,
+				Offset:      40,
+				Limit:       100,
+			},
+		},
+		{name: "Invalid alcoholic", params: map[string]string{"alcoholic": "maybe"}, expectError: true},
+		{name: "Invalid sort", params: map[string]string{"sort": "name"}, expectError: true},
+		{name: "Negative offset", params: map[string]string{"offset": "-1"}, expectError: true},
+		{name: "Limit too large", params: map[string]string{"limit": "101"}, expectError: true},
+		{name: "Limit isn't a number", params: map[string]string{"limit": "ten"}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := NewDrinkSearchQuery(tt.params)
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from NewDrinkSearchQuery")
+				return
+			}
+			assert.NoError(t, err, "No error should have been returned from NewDrinkSearchQuery")
+			assert.Equal(t, tt.expectedQuery, query)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NewETag formats the record's version as a strong entity tag for the ETag header
+func NewETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ParseIfMatch extracts the version that the client expects to modify from the If-Match header;
+// present is false when the header is missing or '*', in which case the write shouldn't be conditional
+func ParseIfMatch(header string) (version int, present bool, err error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, false, nil
+	}
+	unquoted, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err == nil {
+		version, err = strconv.Atoi(unquoted)
+	}
+	if err != nil || version < 0 {
+		return 0, false, fmt.Errorf("invalid If-Match header '%s'; it must be an ETag returned by the api", header)
+	}
+	return version, true, nil
+}
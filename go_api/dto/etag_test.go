@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewETag(t *testing.T) {
+	assert.Equal(t, `"3"`, NewETag(3))
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header          string
+		expectedVersion int
+		expectedPresent bool
+		expectError     bool
+	}{
+		{header: "", expectedPresent: false},
+		{header: "*", expectedPresent: false},
+		{header: `"3"`, expectedVersion: 3, expectedPresent: true},
+		{header: `W/"3"`, expectedVersion: 3, expectedPresent: true},
+		{header: "3", expectError: true},
+		{header: `"three"`, expectError: true},
+		{header: `"-1"`, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			version, present, err := ParseIfMatch(tt.header)
+			assert.Equal(t, tt.expectError, err != nil, "ParseIfMatch() error = %v", err)
+			assert.Equal(t, tt.expectedVersion, version)
+			assert.Equal(t, tt.expectedPresent, present)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"the-drink-almanac-api/model"
+)
+
+// FavoriteExpansion describes the related records requested through the `expand` query parameter
+// when retrieving a user's favorites
+type FavoriteExpansion struct {
+	Drink bool
+}
+
+// NewFavoriteExpansion parses the `expand` query parameter, which can be empty or a comma-separated list
+// of expansions; `drink` is the only one so far
+func NewFavoriteExpansion(expand string) (FavoriteExpansion, error) {
+	expansion := FavoriteExpansion{}
+	for _, value := range strings.Split(expand, ",") {
+		switch strings.TrimSpace(value) {
+		case "":
+		case "drink":
+			expansion.Drink = true
+		default:
+			return FavoriteExpansion{}, fmt.Errorf("invalid expand '%s'; the only expansion is 'drink'", value)
+		}
+	}
+	return expansion, nil
+}
+
+// FavoriteDrinkResponse is the part of a drink that's embedded in an expanded favorite
+type FavoriteDrinkResponse struct {
+	Id       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	ImageUrl string `json:"imageUrl,omitempty"`
+}
+
+// ExpandedFavoriteResponse is a favorite with its drink, which is null if the drink isn't in the catalog
+type ExpandedFavoriteResponse struct {
+	FavoriteResponse
+	Drink *FavoriteDrinkResponse `json:"drink"`
+}
+
+// NewExpandedFavoritesResponse embeds each favorite's drink from the given drinks, which are mapped by id
+func NewExpandedFavoritesResponse(favorites []model.Favorite, drinks map[string]model.Drink) []ExpandedFavoriteResponse {
+	favoritesResponse := make([]ExpandedFavoriteResponse, len(favorites))
+	for i, favorite := range favorites {
+		favoritesResponse[i] = ExpandedFavoriteResponse{FavoriteResponse: NewFavoriteResponse(favorite)}
+		if drink, ok := drinks[favorite.DrinkId]; ok {
+			favoritesResponse[i].Drink = &FavoriteDrinkResponse{
+				Id:       drink.Id,
+				Name:     drink.Name,
+				Category: drink.Category,
+				ImageUrl: drink.ImageUrl,
+			}
+		}
+	}
+	return favoritesResponse
+}
+
+// FavoriteDrinkIds returns the drink id of every favorite, in the order of the favorites
+func FavoriteDrinkIds(favorites []model.Favorite) []string {
+	ids := make([]string, len(favorites))
+	for i, favorite := range favorites {
+		ids[i] = favorite.DrinkId
+	}
+	return ids
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFavoriteExpansion(t *testing.T) {
+	tests := []struct {
+		expand            string
+		expectedExpansion FavoriteExpansion
+		expectError       bool
+	}{
+		{expand: ""},
+		{expand: "drink", expectedExpansion: FavoriteExpansion{Drink: true}},
+		{expand: " drink, ", expectedExpansion: FavoriteExpansion{Drink: true}},
+		{expand: "drinks", expectError: true},
+		{expand: "drink,user", expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expand, func(t *testing.T) {
+			expansion, err := NewFavoriteExpansion(tt.expand)
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from NewFavoriteExpansion")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from NewFavoriteExpansion")
+			}
+			assert.Equal(t, tt.expectedExpansion, expansion)
+		})
+	}
+}
@@ -3,14 +3,18 @@ package dto
 import "the-drink-almanac-api/model"
 
 type FavoriteResponse struct {
-	Id      string `json:"id"`
-	DrinkId string `json:"drinkId"`
+	Id        string `json:"id"`
+	DrinkId   string `json:"drinkId"`
+	CreatedAt string `json:"createdAt,omitempty"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
 }
 
 func NewFavoriteResponse(favorite model.Favorite) FavoriteResponse {
 	return FavoriteResponse{
-		Id:      favorite.Id,
-		DrinkId: favorite.DrinkId,
+		Id:        favorite.Id,
+		DrinkId:   favorite.DrinkId,
+		CreatedAt: formatTimestamp(favorite.CreatedAt),
+		UpdatedAt: formatTimestamp(favorite.UpdatedAt),
 	}
 }
 
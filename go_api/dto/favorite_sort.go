@@ -0,0 +1,25 @@
+package dto
+
+import "fmt"
+
+// FavoriteSort describes the order requested through the `sort` query parameter
+// when retrieving a user's favorites
+type FavoriteSort struct {
+	ByCreation  bool
+	NewestFirst bool
+}
+
+// NewFavoriteSort parses the `sort` query parameter, which can be empty (unsorted),
+// `created_at` (oldest first), or `-created_at` (newest first)
+func NewFavoriteSort(sort string) (FavoriteSort, error) {
+	switch sort {
+	case "":
+		return FavoriteSort{}, nil
+	case "created_at":
+		return FavoriteSort{ByCreation: true}, nil
+	case "-created_at":
+		return FavoriteSort{ByCreation: true, NewestFirst: true}, nil
+	default:
+		return FavoriteSort{}, fmt.Errorf("invalid sort '%s'; the sort must be either 'created_at' or '-created_at'", sort)
+	}
+}
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"the-drink-almanac-api/model"
+)
+
+// dateLayout is the format of the dates that only have a day, such as an inventory item's expiry
+const dateLayout = "2006-01-02"
+
+// InventoryItemRequest is the body of both adding and updating an inventory item;
+// an update replaces every field, so omitting the unit or expiry clears them
+type InventoryItemRequest struct {
+	Name string `json:"name"`
+	// Quantity is a pointer so that a missing quantity can be told apart from zero
+	Quantity  *float64 `json:"quantity"`
+	Unit      string   `json:"unit"`
+	ExpiresOn string   `json:"expiresOn"`
+}
+
+func (r InventoryItemRequest) ValidateRequest() error {
+	if model.IngredientKey(r.Name) == "" {
+		return fmt.Errorf("no ingredient name provided")
+	}
+	if r.Quantity == nil {
+		return fmt.Errorf("no quantity provided")
+	}
+	if *r.Quantity < 0 {
+		return fmt.Errorf("the quantity must not be negative")
+	}
+	if r.ExpiresOn != "" {
+		if _, err := time.Parse(dateLayout, r.ExpiresOn); err != nil {
+			return fmt.Errorf("invalid expiresOn '%s'; it must be a date formatted as YYYY-MM-DD", r.ExpiresOn)
+		}
+	}
+	return nil
+}
+
+// ToInventoryItem converts a validated request to the item's fields that the client controls
+func (r InventoryItemRequest) ToInventoryItem() model.InventoryItem {
+	item := model.InventoryItem{
+		Name: r.Name,
+		Unit: r.Unit,
+	}
+	if r.Quantity != nil {
+		item.Quantity = *r.Quantity
+	}
+	if expiresOn, err := time.Parse(dateLayout, r.ExpiresOn); err == nil {
+		item.ExpiresOn = &expiresOn
+	}
+	return item
+}
+
+type InventoryItemResponse struct {
+	Ingredient string  `json:"ingredient"`
+	Name       string  `json:"name"`
+	Quantity   float64 `json:"quantity"`
+	Unit       string  `json:"unit,omitempty"`
+	ExpiresOn  string  `json:"expiresOn,omitempty"`
+	CreatedAt  string  `json:"createdAt,omitempty"`
+	UpdatedAt  string  `json:"updatedAt,omitempty"`
+}
+
+func NewInventoryItemResponse(item model.InventoryItem) InventoryItemResponse {
+	response := InventoryItemResponse{
+		Ingredient: item.Ingredient,
+		Name:       item.Name,
+		Quantity:   item.Quantity,
+		Unit:       item.Unit,
+		CreatedAt:  formatTimestamp(item.CreatedAt),
+		UpdatedAt:  formatTimestamp(item.UpdatedAt),
+	}
+	if item.ExpiresOn != nil {
+		response.ExpiresOn = item.ExpiresOn.UTC().Format(dateLayout)
+	}
+	return response
+}
+
+func NewInventoryResponse(items []model.InventoryItem) []InventoryItemResponse {
+	inventoryResponse := make([]InventoryItemResponse, len(items))
+	for i, item := range items {
+		inventoryResponse[i] = NewInventoryItemResponse(item)
+	}
+	return inventoryResponse
+}
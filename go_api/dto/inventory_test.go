@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestInventoryItemRequest_ValidateRequest(t *testing.T) {
+	quantity := 1.5
+	negative := -1.0
+	tests := []struct {
+		name        string
+		request     InventoryItemRequest
+		expectError bool
+	}{
+		{name: "Valid request", request: InventoryItemRequest{Name: "Gin", Quantity: &quantity, Unit: "l", ExpiresOn: "2024-06-30"}},
+		{name: "Valid request without expiry", request: InventoryItemRequest{Name: "Gin", Quantity: &quantity}},
+		{name: "Missing name", request: InventoryItemRequest{Name: "  ", Quantity: &quantity}, expectError: true},
+		{name: "Missing quantity", request: InventoryItemRequest{Name: "Gin"}, expectError: true},
+		{name: "Negative quantity", request: InventoryItemRequest{Name: "Gin", Quantity: &negative}, expectError: true},
+		{name: "Invalid expiry", request: InventoryItemRequest{Name: "Gin", Quantity: &quantity, ExpiresOn: "30/06/2024"}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.ValidateRequest()
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from ValidateRequest")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from ValidateRequest")
+			}
+		})
+	}
+}
+
+func TestInventoryItemRequest_RoundTrip(t *testing.T) {
+	quantity := 2.0
+	request := InventoryItemRequest{Name: "Angostura Bitters", Quantity: &quantity, Unit: "bottle", ExpiresOn: "2024-06-30"}
+
+	item := request.ToInventoryItem()
+	assert.Equal(t, time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), *item.ExpiresOn)
+	item.Ingredient = model.IngredientKey(item.Name)
+
+	response := NewInventoryItemResponse(item)
+	assert.Equal(t, InventoryItemResponse{
+		Ingredient: "angostura bitters",
+		Name:       "Angostura Bitters",
+		Quantity:   2,
+		Unit:       "bottle",
+		ExpiresOn:  "2024-06-30",
+	}, response)
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"fmt"
+
+	"the-drink-almanac-api/search"
+)
+
+// MakeableDrinksRequest is the body of a search for the drinks that can be made with the given ingredients
+type MakeableDrinksRequest struct {
+	Ingredients []string `json:"ingredients"`
+	// MaxMissing is the most ingredients that a suggestion can be missing; it defaults to 2, and 0 turns off suggestions
+	MaxMissing *int `json:"maxMissing"`
+	// FavoritesOnly restricts the drinks to the favorites of the user in the token
+	FavoritesOnly bool `json:"favoritesOnly"`
+	// Limit is the maximum number of drinks in each list; it defaults to 20 and can be at most 100
+	Limit int `json:"limit"`
+}
+
+func (r MakeableDrinksRequest) ValidateRequest() error {
+	if len(r.Ingredients) == 0 {
+		return fmt.Errorf("no ingredients provided")
+	}
+	if r.MaxMissing != nil && (*r.MaxMissing < 0 || *r.MaxMissing > search.MaxMissingIngredients) {
+		return fmt.Errorf("invalid maxMissing %d; it must be a number from 0 to %d", *r.MaxMissing, search.MaxMissingIngredients)
+	}
+	if r.Limit < 0 || r.Limit > maxDrinkSearchLimit {
+		return fmt.Errorf("invalid limit %d; it must be a number from 1 to %d", r.Limit, maxDrinkSearchLimit)
+	}
+	return nil
+}
+
+// ToQuery converts a validated request to a query, filling in the defaults
+func (r MakeableDrinksRequest) ToQuery() search.MakeableQuery {
+	query := search.MakeableQuery{
+		Ingredients: r.Ingredients,
+		MaxMissing:  search.MaxMissingIngredients,
+		Limit:       defaultDrinkSearchLimit,
+	}
+	if r.MaxMissing != nil {
+		query.MaxMissing = *r.MaxMissing
+	}
+	if r.Limit > 0 {
+		query.Limit = r.Limit
+	}
+	return query
+}
+
+type MakeableDrinksResponse struct {
+	Makeable   []MakeableDrinkResponse `json:"makeable"`
+	MissingOne []MakeableDrinkResponse `json:"missingOne"`
+	MissingTwo []MakeableDrinkResponse `json:"missingTwo"`
+}
+
+type MakeableDrinkResponse struct {
+	DrinkResponse
+	// Missing is the names of the ingredients that the caller doesn't have
+	Missing    []string `json:"missing"`
+	Popularity int      `json:"popularity"`
+}
+
+func NewMakeableDrinksResponse(result search.MakeableResult) MakeableDrinksResponse {
+	return MakeableDrinksResponse{
+		Makeable:   newMakeableDrinkResponses(result.Makeable),
+		MissingOne: newMakeableDrinkResponses(result.MissingOne),
+		MissingTwo: newMakeableDrinkResponses(result.MissingTwo),
+	}
+}
+
+func newMakeableDrinkResponses(hits []search.MakeableHit) []MakeableDrinkResponse {
+	response := make([]MakeableDrinkResponse, len(hits))
+	for i, hit := range hits {
+		response[i] = MakeableDrinkResponse{
+			DrinkResponse: NewDrinkResponse(hit.Drink),
+			Missing:       hit.Missing,
+			Popularity:    hit.Popularity,
+		}
+	}
+	return response
+}
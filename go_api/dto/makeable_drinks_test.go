@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/search"
+)
+
+func TestMakeableDrinksRequest(t *testing.T) {
+	none := 0
+	tooMany := 3
+	tests := []struct {
+		name          string
+		request       MakeableDrinksRequest
+		expectedQuery search.MakeableQuery
+		expectError   bool
+	}{
+		{
+			name:          "Defaults",
+			request:       MakeableDrinksRequest{Ingredients: []string{"gin"}},
+			expectedQuery: search.MakeableQuery{Ingredients: []string{"gin"}, MaxMissing: 2, Limit: 20},
+		},
+		{
+			name:          "Without suggestions",
+			request:       MakeableDrinksRequest{Ingredients: []string{"gin"}, MaxMissing: &none, Limit: 5},
+			expectedQuery: search.MakeableQuery{Ingredients: []string{"gin"}, MaxMissing: 0, Limit: 5},
+		},
+		{name: "No ingredients", request: MakeableDrinksRequest{}, expectError: true},
+		{name: "Too many missing", request: MakeableDrinksRequest{Ingredients: []string{"gin"}, MaxMissing: &tooMany}, expectError: true},
+		{name: "Limit too high", request: MakeableDrinksRequest{Ingredients: []string{"gin"}, Limit: 101}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.ValidateRequest()
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from ValidateRequest")
+				return
+			}
+			assert.NoError(t, err, "No error should have been returned from ValidateRequest")
+			assert.Equal(t, tt.expectedQuery, tt.request.ToQuery())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"math"
+
+	"the-drink-almanac-api/nutrition"
+)
+
+// NutritionResponse is the estimate for a serving of a drink; FavoriteId is only set when the favorite was requested
+type NutritionResponse struct {
+	DrinkId               string   `json:"drinkId"`
+	Name                  string   `json:"name"`
+	FavoriteId            string   `json:"favoriteId,omitempty"`
+	Method                string   `json:"method"`
+	VolumeMl              float64  `json:"volumeMl"`
+	DilutionMl            float64  `json:"dilutionMl"`
+	ABV                   float64  `json:"abv"`
+	AlcoholGrams          float64  `json:"alcoholGrams"`
+	StandardDrinks        float64  `json:"standardDrinks"`
+	Calories              float64  `json:"calories"`
+	UnknownIngredients    []string `json:"unknownIngredients"`
+	UnmeasuredIngredients []string `json:"unmeasuredIngredients"`
+}
+
+// NewNutritionResponse rounds the estimate to what it can claim: tenths of a milliliter, a percent and a gram,
+// hundredths of a standard drink and whole calories
+func NewNutritionResponse(estimate nutrition.Estimate) NutritionResponse {
+	return NutritionResponse{
+		DrinkId:               estimate.DrinkId,
+		Name:                  estimate.DrinkName,
+		Method:                string(estimate.Method),
+		VolumeMl:              roundTo(estimate.Volume, 1),
+		DilutionMl:            roundTo(estimate.Dilution, 1),
+		ABV:                   roundTo(estimate.ABV, 1),
+		AlcoholGrams:          roundTo(estimate.AlcoholGrams, 1),
+		StandardDrinks:        roundTo(estimate.StandardDrinks, 2),
+		Calories:              math.Round(estimate.Calories),
+		UnknownIngredients:    estimate.Unknown,
+		UnmeasuredIngredients: estimate.Unmeasured,
+	}
+}
+
+// NewFavoriteNutritionResponse is the estimate of the favorite's drink
+func NewFavoriteNutritionResponse(favoriteId string, estimate nutrition.Estimate) NutritionResponse {
+	response := NewNutritionResponse(estimate)
+	response.FavoriteId = favoriteId
+	return response
+}
+
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/nutrition"
+)
+
+func TestNewNutritionResponse(t *testing.T) {
+	estimate := nutrition.Estimate{
+		DrinkId:        "11007",
+		DrinkName:      "Margarita",
+		Method:         nutrition.Shaken,
+		Volume:         154.5321,
+		Dilution:       65.3821,
+		ABV:            14.3547,
+		AlcoholGrams:   17.5012,
+		StandardDrinks: 1.25008,
+		Calories:       147.46,
+		Unknown:        []string{},
+		Unmeasured:     []string{"Salt"},
+	}
+
+	assert.Equal(t, NutritionResponse{
+		DrinkId:               "11007",
+		Name:                  "Margarita",
+		FavoriteId:            "fav0",
+		Method:                "shaken",
+		VolumeMl:              154.5,
+		DilutionMl:            65.4,
+		ABV:                   14.4,
+		AlcoholGrams:          17.5,
+		StandardDrinks:        1.25,
+		Calories:              147,
+		UnknownIngredients:    []string{},
+		UnmeasuredIngredients: []string{"Salt"},
+	}, NewFavoriteNutritionResponse("fav0", estimate))
+}
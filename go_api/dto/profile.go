@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+
+	"the-drink-almanac-api/model"
+)
+
+// ProfileResponse is a user's public profile, which anyone can see
+type ProfileResponse struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName,omitempty"`
+	Bio         string `json:"bio,omitempty"`
+	AvatarUrl   string `json:"avatarUrl,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
+	// PublicFavorites tells apart a user who hides their favorites from one who has none
+	PublicFavorites bool                      `json:"publicFavorites"`
+	Favorites       []ProfileFavoriteResponse `json:"favorites,omitempty"`
+}
+
+// ProfileFavoriteResponse leaves out the ids that are only useful to the favorite's owner
+type ProfileFavoriteResponse struct {
+	DrinkId   string `json:"drinkId"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+// NewProfileResponse only shows the favorites if the user made them public
+func NewProfileResponse(user model.User, favorites []model.Favorite) ProfileResponse {
+	response := ProfileResponse{
+		Username:        user.Username,
+		DisplayName:     user.DisplayName,
+		Bio:             user.Bio,
+		AvatarUrl:       avatarUrl(user),
+		CreatedAt:       formatTimestamp(user.CreatedAt),
+		PublicFavorites: user.PublicFavorites,
+	}
+	if user.PublicFavorites {
+		response.Favorites = make([]ProfileFavoriteResponse, len(favorites))
+		for i, favorite := range favorites {
+			response.Favorites[i] = ProfileFavoriteResponse{
+				DrinkId:   favorite.DrinkId,
+				CreatedAt: formatTimestamp(favorite.CreatedAt),
+			}
+		}
+	}
+	return response
+}
+
+// avatarUrl is the path that the user's avatar is served from, or empty if the user has no avatar
+func avatarUrl(user model.User) string {
+	if user.Avatar == "" {
+		return ""
+	}
+	return fmt.Sprintf("/users/%s/avatar", url.PathEscape(user.Username))
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestUserPatchRequest_ValidateRequest(t *testing.T) {
+	displayName := "Name Zero"
+	longDisplayName := strings.Repeat("n", MaxDisplayNameLength+1)
+	multilineDisplayName := "Name\nZero"
+	bio := "Mostly sours."
+	longBio := strings.Repeat("b", MaxBioLength+1)
+	units := "metric"
+	invalidUnits := "furlongs"
+	publicFavorites := true
+	tests := []struct {
+		name        string
+		request     UserPatchRequest
+		expectError bool
+	}{
+		{name: "Valid request", request: UserPatchRequest{DisplayName: &displayName, Bio: &bio, Units: &units, PublicFavorites: &publicFavorites}},
+		{name: "Only public favorites", request: UserPatchRequest{PublicFavorites: &publicFavorites}},
+		{name: "No changes", request: UserPatchRequest{}, expectError: true},
+		{name: "Display name is too long", request: UserPatchRequest{DisplayName: &longDisplayName}, expectError: true},
+		{name: "Display name with a line break", request: UserPatchRequest{DisplayName: &multilineDisplayName}, expectError: true},
+		{name: "Bio is too long", request: UserPatchRequest{Bio: &longBio}, expectError: true},
+		{name: "Invalid units", request: UserPatchRequest{Units: &invalidUnits}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.ValidateRequest()
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from ValidateRequest")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from ValidateRequest")
+			}
+		})
+	}
+}
+
+func TestUserPatchRequest_ApplyTo(t *testing.T) {
+	displayName := " Name Zero "
+	emptyBio := ""
+	user := model.User{Id: "0", Username: "username0", Bio: "Mostly sours.", Units: "imperial"}
+
+	UserPatchRequest{DisplayName: &displayName, Bio: &emptyBio}.ApplyTo(&user)
+
+	assert.Equal(t, model.User{Id: "0", Username: "username0", DisplayName: "Name Zero", Units: "imperial"}, user,
+		"Only the fields in the request should have changed")
+}
+
+func TestNewProfileResponse(t *testing.T) {
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	favorites := []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007", CreatedAt: createdAt}}
+	user := model.User{Id: "0", Username: "user name", Password: "hash", DisplayName: "Name Zero", Avatar: "avatars/0/avatar.png", CreatedAt: createdAt}
+
+	assert.Equal(t, ProfileResponse{
+		Username:    "user name",
+		DisplayName: "Name Zero",
+		AvatarUrl:   "/users/user%20name/avatar",
+		CreatedAt:   "2023-01-01T00:00:00Z",
+	}, NewProfileResponse(user, favorites), "The favorites should have been hidden")
+
+	user.PublicFavorites = true
+	assert.Equal(t, []ProfileFavoriteResponse{{DrinkId: "11007", CreatedAt: "2023-01-01T00:00:00Z"}},
+		NewProfileResponse(user, favorites).Favorites, "The favorites should have been shown")
+}
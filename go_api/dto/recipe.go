@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"the-drink-almanac-api/model"
+)
+
+// RecipeRequest is the body of both creating and updating a recipe;
+// an update replaces every field, so omitting an optional field clears it
+type RecipeRequest struct {
+	Name         string              `json:"name"`
+	Category     string              `json:"category"`
+	Glass        string              `json:"glass"`
+	Instructions string              `json:"instructions"`
+	Ingredients  []IngredientRequest `json:"ingredients"`
+	Alcoholic    bool                `json:"alcoholic"`
+	// Visibility is private, unlisted or public; a recipe is private if it's empty
+	Visibility string `json:"visibility"`
+}
+
+type IngredientRequest struct {
+	Name    string `json:"name"`
+	Measure string `json:"measure"`
+}
+
+func (r RecipeRequest) ValidateRequest() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("no recipe name provided")
+	}
+	if len(r.Ingredients) == 0 {
+		return fmt.Errorf("no ingredients provided")
+	}
+	for i, ingredient := range r.Ingredients {
+		if strings.TrimSpace(ingredient.Name) == "" {
+			return fmt.Errorf("no name provided for ingredient %d", i+1)
+		}
+	}
+	if r.Visibility != "" && !model.ValidRecipeVisibility(r.Visibility) {
+		return fmt.Errorf("invalid visibility '%s'; it must be '%s', '%s' or '%s'",
+			r.Visibility, model.RecipePrivate, model.RecipeUnlisted, model.RecipePublic)
+	}
+	return nil
+}
+
+// ToRecipe converts a validated request to the recipe's fields that the client controls
+func (r RecipeRequest) ToRecipe() model.Recipe {
+	ingredients := make([]model.Ingredient, len(r.Ingredients))
+	for i, ingredient := range r.Ingredients {
+		ingredients[i] = model.Ingredient{
+			Name:    ingredient.Name,
+			Measure: ingredient.Measure,
+		}
+	}
+	return model.Recipe{
+		Name:         r.Name,
+		Category:     r.Category,
+		Glass:        r.Glass,
+		Instructions: r.Instructions,
+		Ingredients:  ingredients,
+		Alcoholic:    r.Alcoholic,
+		Visibility:   r.Visibility,
+	}
+}
+
+// RecipeResponse has the same fields as a DrinkResponse, so that clients can show recipes and drinks alike
+type RecipeResponse struct {
+	Id           string               `json:"id"`
+	OwnerId      string               `json:"ownerId"`
+	Name         string               `json:"name"`
+	Category     string               `json:"category,omitempty"`
+	Glass        string               `json:"glass,omitempty"`
+	Instructions string               `json:"instructions,omitempty"`
+	Ingredients  []IngredientResponse `json:"ingredients"`
+	Alcoholic    bool                 `json:"alcoholic"`
+	Visibility   string               `json:"visibility"`
+	CreatedAt    string               `json:"createdAt,omitempty"`
+	UpdatedAt    string               `json:"updatedAt,omitempty"`
+}
+
+func NewRecipeResponse(recipe model.Recipe) RecipeResponse {
+	drink := NewDrinkResponse(recipe.Drink())
+	return RecipeResponse{
+		Id:           recipe.Id,
+		OwnerId:      recipe.OwnerId,
+		Name:         drink.Name,
+		Category:     drink.Category,
+		Glass:        drink.Glass,
+		Instructions: drink.Instructions,
+		Ingredients:  drink.Ingredients,
+		Alcoholic:    drink.Alcoholic,
+		Visibility:   recipe.Visibility,
+		CreatedAt:    formatTimestamp(recipe.CreatedAt),
+		UpdatedAt:    formatTimestamp(recipe.UpdatedAt),
+	}
+}
+
+func NewRecipesResponse(recipes []model.Recipe) []RecipeResponse {
+	recipesResponse := make([]RecipeResponse, len(recipes))
+	for i, recipe := range recipes {
+		recipesResponse[i] = NewRecipeResponse(recipe)
+	}
+	return recipesResponse
+}
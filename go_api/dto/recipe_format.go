@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"fmt"
+	"strconv"
+
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/model"
+)
+
+const maxRecipeServings = 100
+
+// writtenUnits is the `units` query parameter that renders the recipe as it's written, despite the user's preference
+const writtenUnits = "written"
+
+// RecipeFormat describes how a drink's recipe is rendered, through the `servings` and `units` query parameters
+type RecipeFormat struct {
+	Servings int
+	Units    measure.System
+	// UserUnits is true when the request didn't choose the units, so that the user's preference is used
+	UserUnits bool
+}
+
+// NewRecipeFormat parses the `servings` query parameter, which defaults to 1, and the `units` query parameter,
+// which can be `metric`, `imperial`, `written` or empty for the user's preference
+func NewRecipeFormat(servings, units string) (RecipeFormat, error) {
+	format := RecipeFormat{Servings: 1}
+	if servings != "" {
+		parsed, err := strconv.Atoi(servings)
+		if err != nil || parsed < 1 || parsed > maxRecipeServings {
+			return RecipeFormat{}, fmt.Errorf("invalid servings '%s'; it must be a number from 1 to %d", servings, maxRecipeServings)
+		}
+		format.Servings = parsed
+	}
+	switch units {
+	case "":
+		format.UserUnits = true
+	case writtenUnits:
+		format.Units = measure.AsWritten
+	default:
+		system, ok := measure.ParseSystem(units)
+		if !ok {
+			return RecipeFormat{}, fmt.Errorf("invalid units '%s'; the units must be either 'metric', 'imperial' or 'written'", units)
+		}
+		format.Units = system
+	}
+	return format, nil
+}
+
+// WithUser returns a copy of the format in the user's preferred units, unless the request chose the units
+func (f RecipeFormat) WithUser(user model.User) RecipeFormat {
+	if system, ok := measure.ParseSystem(user.Units); ok && f.UserUnits {
+		f.Units = system
+	}
+	return f
+}
+
+// NewFormattedDrinkResponse is the drink with its measures scaled to the servings and rendered in the units
+func NewFormattedDrinkResponse(drink model.Drink, format RecipeFormat) DrinkResponse {
+	response := NewDrinkResponse(drink)
+	for i, ingredient := range response.Ingredients {
+		response.Ingredients[i].Measure = measure.Render(ingredient.Measure, format.Servings, format.Units)
+	}
+	response.Servings = format.Servings
+	response.Units = string(format.Units)
+	return response
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/model"
+)
+
+func TestNewRecipeFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		servings       string
+		units          string
+		expectedFormat RecipeFormat
+		expectError    bool
+	}{
+		{name: "Defaults", expectedFormat: RecipeFormat{Servings: 1, UserUnits: true}},
+		{name: "Servings and units", servings: "4", units: "metric", expectedFormat: RecipeFormat{Servings: 4, Units: measure.Metric}},
+		{name: "As written", units: "written", expectedFormat: RecipeFormat{Servings: 1, Units: measure.AsWritten}},
+		{name: "Too many servings", servings: "101", expectError: true},
+		{name: "No servings", servings: "0", expectError: true},
+		{name: "Servings that aren't a number", servings: "two", expectError: true},
+		{name: "Unknown units", units: "nautical", expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := NewRecipeFormat(tt.servings, tt.units)
+			assert.Equal(t, tt.expectError, err != nil, "Unexpected error: %v", err)
+			assert.Equal(t, tt.expectedFormat, format)
+		})
+	}
+}
+
+func TestRecipeFormat_WithUser(t *testing.T) {
+	user := model.User{Id: "user0", Units: "imperial"}
+
+	format, _ := NewRecipeFormat("", "")
+	assert.Equal(t, measure.Imperial, format.WithUser(user).Units, "The user's preference should be used")
+
+	format, _ = NewRecipeFormat("", "written")
+	assert.Equal(t, measure.AsWritten, format.WithUser(user).Units, "The units of the request should be used")
+
+	format, _ = NewRecipeFormat("", "")
+	assert.Equal(t, measure.AsWritten, format.WithUser(model.User{Id: "user1"}).Units)
+}
+
+func TestNewFormattedDrinkResponse(t *testing.T) {
+	drink := model.Drink{
+		Id:   "11007",
+		Name: "Margarita",
+		Ingredients: []model.Ingredient{
+			{Name: "Tequila", Measure: "1 1/2 oz"},
+			{Name: "Lime juice", Measure: "Juice of 1/2"},
+			{Name: "Salt"},
+		},
+	}
+	response := NewFormattedDrinkResponse(drink, RecipeFormat{Servings: 2, Units: measure.Metric})
+
+	assert.Equal(t, []IngredientResponse{
+		{Name: "Tequila", Measure: "90 ml"},
+		{Name: "Lime juice", Measure: "juice of 1"},
+		{Name: "Salt"},
+	}, response.Ingredients)
+	assert.Equal(t, 2, response.Servings)
+	assert.Equal(t, "metric", response.Units)
+	assert.Equal(t, "1 1/2 oz", drink.Ingredients[0].Measure, "The drink shouldn't have been modified")
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestRecipeRequest_ValidateRequest(t *testing.T) {
+	ingredients := []IngredientRequest{{Name: "Tequila", Measure: "2 oz"}, {Name: "Salt"}}
+	tests := []struct {
+		name        string
+		request     RecipeRequest
+		expectError bool
+	}{
+		{name: "Valid request", request: RecipeRequest{Name: "House Margarita", Ingredients: ingredients, Visibility: model.RecipePublic}},
+		{name: "Valid request without visibility", request: RecipeRequest{Name: "House Margarita", Ingredients: ingredients}},
+		{name: "Missing name", request: RecipeRequest{Name: " ", Ingredients: ingredients}, expectError: true},
+		{name: "Missing ingredients", request: RecipeRequest{Name: "House Margarita"}, expectError: true},
+		{name: "Ingredient without a name", request: RecipeRequest{Name: "House Margarita", Ingredients: []IngredientRequest{{Measure: "2 oz"}}}, expectError: true},
+		{name: "Invalid visibility", request: RecipeRequest{Name: "House Margarita", Ingredients: ingredients, Visibility: "friends"}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.ValidateRequest()
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from ValidateRequest")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from ValidateRequest")
+			}
+		})
+	}
+}
+
+func TestRecipeRequest_RoundTrip(t *testing.T) {
+	request := RecipeRequest{
+		Name:         "House Margarita",
+		Glass:        "Coupe",
+		Instructions: "Shake with ice.",
+		Ingredients:  []IngredientRequest{{Name: "Tequila", Measure: "2 oz"}, {Name: "Salt"}},
+		Alcoholic:    true,
+		Visibility:   model.RecipeUnlisted,
+	}
+	recipe := request.ToRecipe()
+	recipe.Id = "recipe-0"
+	recipe.OwnerId = "0"
+	recipe.CreatedAt = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	response := NewRecipeResponse(recipe)
+	assert.Equal(t, RecipeResponse{
+		Id:           "recipe-0",
+		OwnerId:      "0",
+		Name:         "House Margarita",
+		Glass:        "Coupe",
+		Instructions: "Shake with ice.",
+		Ingredients:  []IngredientResponse{{Name: "Tequila", Measure: "2 oz"}, {Name: "Salt"}},
+		Alcoholic:    true,
+		Visibility:   model.RecipeUnlisted,
+		CreatedAt:    "2023-01-01T00:00:00Z",
+	}, response)
+}
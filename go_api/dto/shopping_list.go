@@ -0,0 +1,147 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/shopping"
+)
+
+const (
+	defaultShoppingListServings = 1
+	maxShoppingListServings     = 100
+)
+
+// ShoppingListRequest is the body of POST /shopping-list; the caller's favorites are used if there aren't any drink ids
+type ShoppingListRequest struct {
+	DrinkIds []string `json:"drinkIds"`
+	// Servings is the number of times that each drink is made; it defaults to 1 and can be at most 100
+	Servings int             `json:"servings"`
+	OnHand   []OnHandRequest `json:"onHand"`
+}
+
+// OnHandRequest is an ingredient that doesn't need to be bought; all of it is on hand unless a quantity is given
+type OnHandRequest struct {
+	Name     string   `json:"name"`
+	Quantity *float64 `json:"quantity"`
+	Unit     string   `json:"unit"`
+}
+
+func (r ShoppingListRequest) ValidateRequest() error {
+	if r.Servings < 0 || r.Servings > maxShoppingListServings {
+		return fmt.Errorf("invalid servings %d; it must be a number from 1 to %d", r.Servings, maxShoppingListServings)
+	}
+	for i, onHand := range r.OnHand {
+		if strings.TrimSpace(onHand.Name) == "" {
+			return fmt.Errorf("on hand ingredient %d doesn't have a name", i)
+		}
+		if onHand.Quantity != nil && *onHand.Quantity < 0 {
+			return fmt.Errorf("the quantity of '%s' on hand must not be negative", onHand.Name)
+		}
+	}
+	return nil
+}
+
+// ServingsOrDefault returns the number of servings, which defaults to 1
+func (r ShoppingListRequest) ServingsOrDefault() int {
+	if r.Servings == 0 {
+		return defaultShoppingListServings
+	}
+	return r.Servings
+}
+
+// ToOnHand converts the ingredients on hand; units that aren't known are kept as they're written, e.g. "slices"
+func (r ShoppingListRequest) ToOnHand() []shopping.OnHand {
+	onHand := make([]shopping.OnHand, len(r.OnHand))
+	for i, o := range r.OnHand {
+		onHand[i] = shopping.OnHand{Name: o.Name}
+		if o.Quantity == nil {
+			continue
+		}
+		unit, ok := measure.ParseUnit(o.Unit)
+		if !ok {
+			unit = measure.Unit(strings.ToLower(strings.TrimSpace(o.Unit)))
+		}
+		onHand[i].Quantity = &measure.Quantity{Amount: *o.Quantity, Unit: unit}
+	}
+	return onHand
+}
+
+type ShoppingListResponse struct {
+	Servings        int                         `json:"servings"`
+	Drinks          []string                    `json:"drinks"`
+	Groups          []ShoppingListGroupResponse `json:"groups"`
+	OnHand          []string                    `json:"onHand"`
+	MissingDrinkIds []string                    `json:"missingDrinkIds"`
+	// Markdown is the list rendered as a checklist
+	Markdown string `json:"markdown"`
+}
+
+type ShoppingListGroupResponse struct {
+	Category string                     `json:"category"`
+	Items    []ShoppingListItemResponse `json:"items"`
+}
+
+type ShoppingListItemResponse struct {
+	Ingredient string           `json:"ingredient"`
+	Name       string           `json:"name"`
+	Amounts    []AmountResponse `json:"amounts"`
+	// Unmeasured is true if some of the drinks don't give an amount, e.g. for a garnish
+	Unmeasured bool     `json:"unmeasured"`
+	Drinks     []string `json:"drinks"`
+}
+
+type AmountResponse struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit,omitempty"`
+}
+
+func NewShoppingListResponse(list shopping.List) ShoppingListResponse {
+	groups := make([]ShoppingListGroupResponse, len(list.Groups))
+	for i, group := range list.Groups {
+		items := make([]ShoppingListItemResponse, len(group.Items))
+		for j, item := range group.Items {
+			amounts := make([]AmountResponse, len(item.Amounts))
+			for k, amount := range item.Amounts {
+				amounts[k] = AmountResponse{Amount: amount.Amount, Unit: string(amount.Unit)}
+			}
+			items[j] = ShoppingListItemResponse{
+				Ingredient: item.Ingredient,
+				Name:       item.Name,
+				Amounts:    amounts,
+				Unmeasured: item.Unmeasured,
+				Drinks:     item.Drinks,
+			}
+		}
+		groups[i] = ShoppingListGroupResponse{Category: string(group.Category), Items: items}
+	}
+	return ShoppingListResponse{
+		Servings:        list.Servings,
+		Drinks:          list.Drinks,
+		Groups:          groups,
+		OnHand:          list.OnHand,
+		MissingDrinkIds: list.MissingDrinkIds,
+		Markdown:        list.Markdown(),
+	}
+}
+
+// ShoppingListFormat is how the list is returned, chosen by the `format` query parameter
+type ShoppingListFormat string
+
+const (
+	ShoppingListJSON     ShoppingListFormat = "json"
+	ShoppingListMarkdown ShoppingListFormat = "markdown"
+)
+
+// NewShoppingListFormat parses the `format` query parameter, which defaults to json
+func NewShoppingListFormat(format string) (ShoppingListFormat, error) {
+	switch ShoppingListFormat(format) {
+	case "", ShoppingListJSON:
+		return ShoppingListJSON, nil
+	case ShoppingListMarkdown:
+		return ShoppingListMarkdown, nil
+	default:
+		return "", fmt.Errorf("invalid format '%s'; the format must be either 'json' or 'markdown'", format)
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/shopping"
+)
+
+func TestShoppingListRequest_ValidateRequest(t *testing.T) {
+	negative := -1.0
+	tests := []struct {
+		name        string
+		request     ShoppingListRequest
+		expectError bool
+	}{
+		{name: "Valid request", request: ShoppingListRequest{DrinkIds: []string{"11007"}, Servings: 4, OnHand: []OnHandRequest{{Name: "Salt"}}}},
+		{name: "Favorites with the default servings", request: ShoppingListRequest{}},
+		{name: "Too many servings", request: ShoppingListRequest{Servings: 101}, expectError: true},
+		{name: "On hand without a name", request: ShoppingListRequest{OnHand: []OnHandRequest{{Name: " "}}}, expectError: true},
+		{name: "Negative quantity on hand", request: ShoppingListRequest{OnHand: []OnHandRequest{{Name: "Gin", Quantity: &negative}}}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.ValidateRequest()
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from ValidateRequest")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from ValidateRequest")
+			}
+		})
+	}
+}
+
+func TestShoppingListRequest_ToOnHand(t *testing.T) {
+	quantity := 2.0
+	request := ShoppingListRequest{OnHand: []OnHandRequest{
+		{Name: "Salt"},
+		{Name: "Gin", Quantity: &quantity, Unit: "Ounces"},
+		{Name: "Lemon", Quantity: &quantity, Unit: " Slices"},
+	}}
+
+	assert.Equal(t, []shopping.OnHand{
+		{Name: "Salt"},
+		{Name: "Gin", Quantity: &measure.Quantity{Amount: 2, Unit: measure.Ounce}},
+		{Name: "Lemon", Quantity: &measure.Quantity{Amount: 2, Unit: "slices"}},
+	}, request.ToOnHand())
+	assert.Equal(t, 1, request.ServingsOrDefault())
+}
+
+func TestNewShoppingListFormat(t *testing.T) {
+	format, err := NewShoppingListFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, ShoppingListJSON, format)
+
+	format, err = NewShoppingListFormat("markdown")
+	assert.NoError(t, err)
+	assert.Equal(t, ShoppingListMarkdown, format)
+
+	_, err = NewShoppingListFormat("csv")
+	assert.Error(t, err)
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+// formatTimestamp formats the time as RFC 3339 in UTC,
+// or returns an empty string for records that were created before timestamps were tracked
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UserInclusion describes the related records requested through the `include` query parameter of GET /user
+type UserInclusion struct {
+	Favorites bool
+}
+
+// NewUserInclusion parses the `include` query parameter, which can be empty or a comma-separated list
+// of related records; `favorites` is the only one so far
+func NewUserInclusion(include string) (UserInclusion, error) {
+	inclusion := UserInclusion{}
+	for _, value := range strings.Split(include, ",") {
+		switch strings.TrimSpace(value) {
+		case "":
+		case "favorites":
+			inclusion.Favorites = true
+		default:
+			return UserInclusion{}, fmt.Errorf("invalid include '%s'; the only inclusion is 'favorites'", value)
+		}
+	}
+	return inclusion, nil
+}
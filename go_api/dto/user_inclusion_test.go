@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserInclusion(t *testing.T) {
+	tests := []struct {
+		name              string
+		include           string
+		expectedInclusion UserInclusion
+		expectError       bool
+	}{
+		{name: "No inclusion", include: ""},
+		{name: "Favorites", include: "favorites", expectedInclusion: UserInclusion{Favorites: true}},
+		{name: "Favorites with spaces", include: " favorites ,", expectedInclusion: UserInclusion{Favorites: true}},
+		{name: "Unknown inclusion", include: "favorites,recipes", expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inclusion, err := NewUserInclusion(tt.include)
+			assert.Equal(t, tt.expectError, err != nil, "NewUserInclusion() error = %v", err)
+			assert.Equal(t, tt.expectedInclusion, inclusion)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/model"
+)
+
+const (
+	// MaxDisplayNameLength is the most characters that a display name can have
+	MaxDisplayNameLength = 50
+	// MaxBioLength is the most characters that a bio can have
+	MaxBioLength = 500
+)
+
+// UserPatchRequest is the body of PATCH /user; only the fields that are present are changed,
+// and an empty string clears the field
+type UserPatchRequest struct {
+	DisplayName *string `json:"displayName"`
+	Bio         *string `json:"bio"`
+	// Units is the system of units that recipes are rendered in, `metric` or `imperial`; empty renders them as they're written
+	Units *string `json:"units"`
+	// PublicFavorites shows the user's favorites on their public profile
+	PublicFavorites *bool `json:"publicFavorites"`
+}
+
+func (r UserPatchRequest) ValidateRequest() error {
+	if r.DisplayName == nil && r.Bio == nil && r.Units == nil && r.PublicFavorites == nil {
+		return fmt.Errorf("no changes provided; the body can have the displayName, bio, units and publicFavorites")
+	}
+	if r.DisplayName != nil {
+		displayName := strings.TrimSpace(*r.DisplayName)
+		if utf8.RuneCountInString(displayName) > MaxDisplayNameLength {
+			return fmt.Errorf("the display name must be at most %d characters", MaxDisplayNameLength)
+		}
+		if strings.IndexFunc(displayName, unicode.IsControl) >= 0 {
+			return fmt.Errorf("the display name must not contain control characters such as line breaks")
+		}
+	}
+	if r.Bio != nil && utf8.RuneCountInString(strings.TrimSpace(*r.Bio)) > MaxBioLength {
+		return fmt.Errorf("the bio must be at most %d characters", MaxBioLength)
+	}
+	if r.Units != nil {
+		if _, ok := measure.ParseSystem(*r.Units); !ok {
+			return fmt.Errorf("invalid units '%s'; the units must be either 'metric', 'imperial' or empty", *r.Units)
+		}
+	}
+	return nil
+}
+
+// ApplyTo changes the fields of the user that are present in the request
+func (r UserPatchRequest) ApplyTo(user *model.User) {
+	if r.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*r.DisplayName)
+	}
+	if r.Bio != nil {
+		user.Bio = strings.TrimSpace(*r.Bio)
+	}
+	if r.Units != nil {
+		user.Units = *r.Units
+	}
+	if r.PublicFavorites != nil {
+		user.PublicFavorites = *r.PublicFavorites
+	}
+}
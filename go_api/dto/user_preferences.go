@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"fmt"
+
+	"the-drink-almanac-api/measure"
+)
+
+// UserPreferencesRequest is the body of PUT /user/preferences
+type UserPreferencesRequest struct {
+	// Units is the system of units that recipes are rendered in, `metric` or `imperial`; empty renders them as they're written
+	Units *string `json:"units"`
+}
+
+func (r UserPreferencesRequest) ValidateRequest() error {
+	if r.Units == nil {
+		return fmt.Errorf("no units provided")
+	}
+	if _, ok := measure.ParseSystem(*r.Units); !ok {
+		return fmt.Errorf("invalid units '%s'; the units must be either 'metric', 'imperial' or empty", *r.Units)
+	}
+	return nil
+}
@@ -3,17 +3,40 @@ package dto
 import "the-drink-almanac-api/model"
 
 type UserResponse struct {
-	Id       string `json:"id"`
-	Username string `json:"username"`
+	Id              string `json:"id"`
+	Username        string `json:"username"`
+	CreatedAt       string `json:"createdAt,omitempty"`
+	UpdatedAt       string `json:"updatedAt,omitempty"`
+	Units           string `json:"units,omitempty"`
+	DisplayName     string `json:"displayName,omitempty"`
+	Bio             string `json:"bio,omitempty"`
+	AvatarUrl       string `json:"avatarUrl,omitempty"`
+	PublicFavorites bool   `json:"publicFavorites"`
+	// Favorites are only included if they were requested with `include=favorites`
+	Favorites []FavoriteResponse `json:"favorites,omitempty"`
 }
 
 func NewUserResponse(user model.User) UserResponse {
 	return UserResponse{
-		Id:       user.Id,
-		Username: user.Username,
+		Id:              user.Id,
+		Username:        user.Username,
+		CreatedAt:       formatTimestamp(user.CreatedAt),
+		UpdatedAt:       formatTimestamp(user.UpdatedAt),
+		Units:           user.Units,
+		DisplayName:     user.DisplayName,
+		Bio:             user.Bio,
+		AvatarUrl:       avatarUrl(user),
+		PublicFavorites: user.PublicFavorites,
 	}
 }
 
+// NewUserWithFavoritesResponse includes the user's favorites, as an empty list if the user has none
+func NewUserWithFavoritesResponse(user model.User, favorites []model.Favorite) UserResponse {
+	response := NewUserResponse(user)
+	response.Favorites = NewFavoritesResponse(favorites)
+	return response
+}
+
 func NewUsersResponse(users []model.User) []UserResponse {
 	usersResponse := make([]UserResponse, len(users))
 	for i, user := range users {
@@ -0,0 +1,87 @@
+// Package events publishes the domain events that the repositories record in their outbox
+// to the sinks that are interested in them
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"the-drink-almanac-api/model"
+)
+
+// Sink receives published domain events.
+// Delivery is at least once: the Relay publishes an event again if it couldn't delete it from the outbox,
+// or if its claim expired before it was deleted, so sinks and their subscribers should dedupe on Event.Id
+type Sink interface {
+	Publish(ctx context.Context, event model.Event) error
+}
+
+// ErrNoSubscribers is returned when an event is published to a Bus that nothing has subscribed to,
+// so that the Relay keeps the event in the outbox instead of deleting an event that nobody received
+var ErrNoSubscribers = errors.New("the event bus has no subscribers")
+
+// Handler is an in-process subscriber to the Bus
+type Handler func(ctx context.Context, event model.Event) error
+
+type subscription struct {
+	handler    Handler
+	eventTypes map[model.EventType]bool
+}
+
+// Bus is a Sink that passes each event to the subscribers of its type, in the order they subscribed
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers the handler for the given event types, or for every event if no types are given
+func (b *Bus) Subscribe(handler Handler, eventTypes ...model.EventType) {
+	var types map[model.EventType]bool
+	if len(eventTypes) > 0 {
+		types = map[model.EventType]bool{}
+		for _, eventType := range eventTypes {
+			types[eventType] = true
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, subscription{handler: handler, eventTypes: types})
+}
+
+// SubscribeSink forwards the given event types (or every event) to another sink
+func (b *Bus) SubscribeSink(sink Sink, eventTypes ...model.EventType) {
+	b.Subscribe(sink.Publish, eventTypes...)
+}
+
+// HasSubscribers reports whether anything has subscribed to the bus
+func (b *Bus) HasSubscribers() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscriptions) > 0
+}
+
+// Publish calls the event's subscribers synchronously, stopping at the first one that fails;
+// the event is then published again later, so the subscribers before it will see it twice
+func (b *Bus) Publish(ctx context.Context, event model.Event) error {
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+	if len(subscriptions) == 0 {
+		return ErrNoSubscribers
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.eventTypes != nil && !subscription.eventTypes[event.Type] {
+			continue
+		}
+		if err := subscription.handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+var (
+	userCreated = model.Event{
+		Id:         "event0",
+		Type:       model.UserCreated,
+		OccurredAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UserId:     "0",
+		Username:   "username0",
+	}
+	favoriteAdded = model.Event{
+		Id:         "event1",
+		Type:       model.FavoriteAdded,
+		OccurredAt: time.Date(2023, 1, 1, 0, 1, 0, 0, time.UTC),
+		UserId:     "0",
+		FavoriteId: "favorite0",
+		DrinkId:    "11007",
+	}
+)
+
+func TestBus_Publish(t *testing.T) {
+	tests := []struct {
+		name             string
+		event            model.Event
+		failingHandler   bool
+		expectedReceived []string
+		expectError      bool
+	}{
+		{
+			name:             "Delivers the event to every subscriber and the subscribers of its type",
+			event:            userCreated,
+			expectedReceived: []string{"all", "users"},
+		},
+		{
+			name:             "Skips the subscribers of other types",
+			event:            favoriteAdded,
+			expectedReceived: []string{"all", "favorites"},
+		},
+		{
+			name:             "Stops at the first subscriber that fails",
+			event:            userCreated,
+			failingHandler:   true,
+			expectedReceived: []string{"all"},
+			expectError:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := []string{}
+			subscriber := func(name string, err error) Handler {
+				return func(ctx context.Context, event model.Event) error {
+					if err != nil {
+						return err
+					}
+					received = append(received, name)
+					return nil
+				}
+			}
+			var userError error
+			if tt.failingHandler {
+				userError = fmt.Errorf("failed to handle the event")
+			}
+
+			bus := NewBus()
+			bus.Subscribe(subscriber("all", nil))
+			bus.Subscribe(subscriber("users", userError), model.UserCreated, model.UserDeleted)
+			bus.Subscribe(subscriber("favorites", nil), model.FavoriteAdded, model.FavoriteRemoved)
+			err := bus.Publish(context.TODO(), tt.event)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from bus.Publish")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from bus.Publish")
+			}
+			assert.Equal(t, tt.expectedReceived, received)
+		})
+	}
+}
+
+func TestBus_Publish_NoSubscribers(t *testing.T) {
+	bus := NewBus()
+	assert.False(t, bus.HasSubscribers())
+	assert.ErrorIs(t, bus.Publish(context.TODO(), userCreated), ErrNoSubscribers)
+}
+
+func TestFileSink_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(path)
+	assert.Nil(t, sink.Publish(context.TODO(), userCreated))
+	assert.Nil(t, sink.Publish(context.TODO(), favoriteAdded))
+
+	contents, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	assert.Equal(t, []string{
+		`{"id":"event0","type":"UserCreated","occurred_at":"2023-01-01T00:00:00Z","user_id":"0","username":"username0"}`,
+		`{"id":"event1","type":"FavoriteAdded","occurred_at":"2023-01-01T00:01:00Z","user_id":"0","favorite_id":"favorite0","drink_id":"11007"}`,
+	}, lines)
+}
+
+func TestQueueSink_Publish(t *testing.T) {
+	queue := NewLocalQueue()
+	sink := NewQueueSink(queue, "http://localhost:4566/000000000000/events.fifo")
+	assert.Nil(t, sink.Publish(context.TODO(), userCreated))
+	assert.Nil(t, sink.Publish(context.TODO(), favoriteAdded))
+	// publishing an event again is deduplicated on its id
+	assert.Nil(t, sink.Publish(context.TODO(), userCreated))
+
+	messages := queue.Receive(10)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "http://localhost:4566/000000000000/events.fifo", messages[0].QueueUrl)
+	assert.Equal(t, "0", messages[0].GroupId)
+	assert.Equal(t, "event0", messages[0].DeduplicationId)
+	assert.Equal(t, map[string]string{"event_type": "UserCreated"}, messages[0].Attributes)
+	assert.Contains(t, messages[1].Body, `"drink_id":"11007"`)
+	assert.Empty(t, queue.Receive(10))
+}
@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"the-drink-almanac-api/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// FileSink appends each event to a file as a line of json (JSON Lines)
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Publish opens the file for each event, so that the file can be rotated while the api is running
+func (s *FileSink) Publish(ctx context.Context, event model.Event) error {
+	line, err := jsoniter.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
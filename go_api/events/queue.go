@@ -0,0 +1,89 @@
+//go:generate mockery --name=QueueClient --output=./ --outpkg=events --filename=queue_mock.go --inpackage
+package events
+
+import (
+	"context"
+	"sync"
+
+	"the-drink-almanac-api/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// QueueMessage has the fields of an SQS SendMessage request that the QueueSink sets
+type QueueMessage struct {
+	QueueUrl string
+	Body     string
+	// GroupId keeps a user's events in order on a FIFO queue
+	GroupId string
+	// DeduplicationId lets a FIFO queue drop events that are published again after a failure
+	DeduplicationId string
+	Attributes      map[string]string
+}
+
+// QueueClient sends messages to an SQS-compatible queue;
+// a thin adapter over the SQS client implements it in aws, and LocalQueue replaces it when running locally
+type QueueClient interface {
+	SendMessage(ctx context.Context, message QueueMessage) error
+}
+
+// QueueSink sends each event to a queue as a json message
+type QueueSink struct {
+	client   QueueClient
+	queueUrl string
+}
+
+func NewQueueSink(client QueueClient, queueUrl string) *QueueSink {
+	return &QueueSink{client: client, queueUrl: queueUrl}
+}
+
+func (s *QueueSink) Publish(ctx context.Context, event model.Event) error {
+	body, err := jsoniter.MarshalToString(event)
+	if err != nil {
+		return err
+	}
+	return s.client.SendMessage(ctx, QueueMessage{
+		QueueUrl:        s.queueUrl,
+		Body:            body,
+		GroupId:         event.UserId,
+		DeduplicationId: event.Id,
+		Attributes:      map[string]string{"event_type": string(event.Type)},
+	})
+}
+
+// LocalQueue is an in-memory QueueClient that stands in for SQS when running locally and in tests;
+// like a FIFO queue, it drops messages whose DeduplicationId it has already seen
+type LocalQueue struct {
+	mu       sync.Mutex
+	messages []QueueMessage
+	seen     map[string]bool
+}
+
+func NewLocalQueue() *LocalQueue {
+	return &LocalQueue{seen: map[string]bool{}}
+}
+
+func (q *LocalQueue) SendMessage(ctx context.Context, message QueueMessage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if message.DeduplicationId != "" {
+		if q.seen[message.DeduplicationId] {
+			return nil
+		}
+		q.seen[message.DeduplicationId] = true
+	}
+	q.messages = append(q.messages, message)
+	return nil
+}
+
+// Receive removes and returns up to max messages from the front of the queue
+func (q *LocalQueue) Receive(max int) []QueueMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if max > len(q.messages) {
+		max = len(q.messages)
+	}
+	messages := q.messages[:max:max]
+	q.messages = q.messages[max:]
+	return messages
+}
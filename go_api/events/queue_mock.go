@@ -0,0 +1,42 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package events
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockQueueClient is an autogenerated mock type for the QueueClient type
+type MockQueueClient struct {
+	mock.Mock
+}
+
+// SendMessage provides a mock function with given fields: ctx, message
+func (_m *MockQueueClient) SendMessage(ctx context.Context, message QueueMessage) error {
+	ret := _m.Called(ctx, message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, QueueMessage) error); ok {
+		r0 = rf(ctx, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockQueueClient creates a new instance of MockQueueClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockQueueClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockQueueClient {
+	mock := &MockQueueClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"the-drink-almanac-api/repository"
+)
+
+const (
+	// relayBatchSize is the number of events read from the outbox at a time
+	relayBatchSize = 25
+	// relayClaimLease is how long a relay has to publish and delete an event it claimed
+	// before another relay may claim it again
+	relayClaimLease = time.Minute
+)
+
+// Relay publishes the events in the outbox to a sink, deleting each event once it has been published.
+// The api and the lambdas may run relays at the same time, so each event is claimed before it's published;
+// delivery is still at least once, since an event is published again if it couldn't be deleted
+type Relay struct {
+	outbox repository.OutboxRepository
+	sink   Sink
+	now    func() time.Time
+}
+
+func NewRelay(outbox repository.OutboxRepository, sink Sink) *Relay {
+	return &Relay{outbox: outbox, sink: sink, now: time.Now}
+}
+
+// RelayPending publishes the pending events oldest first until the outbox is empty, returning how many were published;
+// it stops at the first event that fails, so that events aren't published out of order, and at the first event
+// that another relay has claimed, leaving the rest of the outbox to that relay
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	// the pending events are read from an eventually consistent index, which can still return the events
+	// that this run deleted
+	relayed := map[string]bool{}
+	for {
+		events, err := r.outbox.FindPendingEvents(relayBatchSize)
+		if err != nil {
+			return published, err
+		}
+		progressed := false
+		for _, event := range events {
+			if relayed[event.Id] {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return published, err
+			}
+			claimed, err := r.outbox.ClaimEvent(event, r.now(), relayClaimLease)
+			if err != nil || !claimed {
+				return published, err
+			}
+			if err := r.sink.Publish(ctx, event); err != nil {
+				return published, err
+			}
+			if err := r.outbox.DeleteEvent(event); err != nil {
+				return published, err
+			}
+			relayed[event.Id] = true
+			published++
+			progressed = true
+		}
+		if len(events) < relayBatchSize || !progressed {
+			return published, nil
+		}
+	}
+}
+
+// Run relays the pending events every interval until the context is canceled
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "failed to relay the pending events: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
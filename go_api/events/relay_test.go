@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+func TestRelay_RelayPending(t *testing.T) {
+	tests := []struct {
+		name              string
+		findError         error
+		claimError        error
+		publishError      error
+		deleteError       error
+		expectedPublished int
+		expectError       bool
+	}{
+		{
+			name:              "Publishes and deletes every pending event",
+			expectedPublished: 2,
+		},
+		{
+			name:        "Failed to read the outbox",
+			findError:   fmt.Errorf("failed to read the outbox"),
+			expectError: true,
+		},
+		{
+			name:        "Stops at the first event that couldn't be claimed",
+			claimError:  fmt.Errorf("failed to claim the event"),
+			expectError: true,
+		},
+		{
+			name:         "Stops at the first event that couldn't be published",
+			publishError: fmt.Errorf("failed to publish the event"),
+			expectError:  true,
+		},
+		{
+			name:        "Stops at the first event that couldn't be deleted",
+			deleteError: fmt.Errorf("failed to delete the event"),
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOutbox := repository.NewMockOutboxRepository(t)
+			mockOutbox.On("FindPendingEvents", relayBatchSize).Return([]model.Event{userCreated, favoriteAdded}, tt.findError)
+			if tt.findError == nil {
+				mockOutbox.On("ClaimEvent", userCreated, mock.Anything, relayClaimLease).Return(tt.claimError == nil, tt.claimError).Once()
+			}
+			if tt.findError == nil && tt.claimError == nil && tt.publishError == nil {
+				mockOutbox.On("DeleteEvent", userCreated).Return(tt.deleteError).Once()
+				if tt.deleteError == nil {
+					mockOutbox.On("ClaimEvent", favoriteAdded, mock.Anything, relayClaimLease).Return(true, nil).Once()
+					mockOutbox.On("DeleteEvent", favoriteAdded).Return(nil).Once()
+				}
+			}
+			published := []model.Event{}
+			bus := NewBus()
+			bus.Subscribe(func(ctx context.Context, event model.Event) error {
+				if tt.publishError != nil {
+					return tt.publishError
+				}
+				published = append(published, event)
+				return nil
+			})
+
+			count, err := NewRelay(mockOutbox, bus).RelayPending(context.TODO())
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from relay.RelayPending")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from relay.RelayPending")
+				assert.Equal(t, []model.Event{userCreated, favoriteAdded}, published)
+			}
+			assert.Equal(t, tt.expectedPublished, count)
+			mockOutbox.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRelay_RelayPending_ClaimedByAnotherRelay(t *testing.T) {
+	mockOutbox := repository.NewMockOutboxRepository(t)
+	mockOutbox.On("FindPendingEvents", relayBatchSize).Return([]model.Event{userCreated, favoriteAdded}, nil)
+	mockOutbox.On("ClaimEvent", userCreated, mock.Anything, relayClaimLease).Return(false, nil).Once()
+	published := 0
+	bus := NewBus()
+	bus.Subscribe(func(ctx context.Context, event model.Event) error {
+		published++
+		return nil
+	})
+
+	// the rest of the outbox is left to the relay that claimed the event, so that it stays in order
+	count, err := NewRelay(mockOutbox, bus).RelayPending(context.TODO())
+	assert.Nil(t, err, "No error should have been returned from relay.RelayPending")
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 0, published)
+}
+
+func TestRelay_RelayPending_StaleIndex(t *testing.T) {
+	batch := make([]model.Event, relayBatchSize)
+	for i := range batch {
+		batch[i] = userCreated
+		batch[i].Id = fmt.Sprintf("event%d", i)
+	}
+	mockOutbox := repository.NewMockOutboxRepository(t)
+	// the index still returns the deleted events when it's read again
+	mockOutbox.On("FindPendingEvents", relayBatchSize).Return(batch, nil).Twice()
+	for _, event := range batch {
+		mockOutbox.On("ClaimEvent", event, mock.Anything, relayClaimLease).Return(true, nil).Once()
+		mockOutbox.On("DeleteEvent", event).Return(nil).Once()
+	}
+	published := map[string]int{}
+	bus := NewBus()
+	bus.Subscribe(func(ctx context.Context, event model.Event) error {
+		published[event.Id]++
+		return nil
+	})
+
+	count, err := NewRelay(mockOutbox, bus).RelayPending(context.TODO())
+	assert.Nil(t, err, "No error should have been returned from relay.RelayPending")
+	assert.Equal(t, relayBatchSize, count)
+	for _, event := range batch {
+		assert.Equal(t, 1, published[event.Id], "Each event should have been published once")
+	}
+}
+
+func TestRelay_RelayPending_NoSubscribers(t *testing.T) {
+	mockOutbox := repository.NewMockOutboxRepository(t)
+	mockOutbox.On("FindPendingEvents", relayBatchSize).Return([]model.Event{userCreated}, nil)
+	mockOutbox.On("ClaimEvent", userCreated, mock.Anything, relayClaimLease).Return(true, nil)
+
+	// the event stays in the outbox, since DeleteEvent isn't expected
+	count, err := NewRelay(mockOutbox, NewBus()).RelayPending(context.TODO())
+	assert.ErrorIs(t, err, ErrNoSubscribers)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// SQSQueue is the QueueClient that sends messages to SQS, or to localstack's SQS if an endpoint is given
+type SQSQueue struct {
+	client *sqs.Client
+}
+
+func NewSQSQueue(awsEndpoint string) (*SQSQueue, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	return &SQSQueue{client: sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		// only set the endpoint resolver if an endpoint is provided
+		if awsEndpoint != "" {
+			o.EndpointResolver = sqs.EndpointResolverFromURL(awsEndpoint)
+		}
+	})}, nil
+}
+
+func (q *SQSQueue) SendMessage(ctx context.Context, message QueueMessage) error {
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(message.QueueUrl),
+		MessageBody:       aws.String(message.Body),
+		MessageAttributes: map[string]types.MessageAttributeValue{},
+	}
+	// the group and deduplication ids are only accepted by FIFO queues, whose names end in .fifo
+	if strings.HasSuffix(message.QueueUrl, ".fifo") {
+		if message.GroupId != "" {
+			input.MessageGroupId = aws.String(message.GroupId)
+		}
+		if message.DeduplicationId != "" {
+			input.MessageDeduplicationId = aws.String(message.DeduplicationId)
+		}
+	}
+	for name, value := range message.Attributes {
+		input.MessageAttributes[name] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	_, err := q.client.SendMessage(ctx, input)
+	return err
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"expvar"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+// startExpiringStore creates the expiring store selected by the app config, publishing its metrics with expvar
+// as "expiring_records"; stores without a time to live are swept in the background until the context is done
+func startExpiringStore(ctx context.Context, appConfig model.AppConfig) (repository.ExpiringStore, error) {
+	metrics := &repository.ExpiryMetrics{}
+	store, err := repository.NewExpiringStore(appConfig, metrics)
+	if err != nil {
+		return nil, err
+	}
+	expvar.Publish("expiring_records", metrics)
+	if deleter, ok := store.(repository.ExpiredRecordDeleter); ok {
+		go repository.NewSweeper(deleter, metrics).Run(ctx, appConfig.ExpiringSweepInterval)
+	}
+	return store, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"the-drink-almanac-api/archive"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+// runExport writes every user and favorite to an archive file, or to stdout if no file is given
+func runExport(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := flags.String("o", "-", "the file to write the archive to, or '-' for stdout")
+	compress := flags.Bool("gzip", false, "gzip the archive; enabled automatically when the file ends with .gz")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	userStore, favoriteStore, err := repository.NewRepositories(appConfig)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	// closers are closed after the export, the gzip writer before the file that it flushes to;
+	// a failed close means the archive is truncated, so it fails the export
+	var closers []io.Closer
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		closers = append(closers, file)
+		w = file
+	}
+	if *compress || strings.HasSuffix(*output, ".gz") {
+		gzipWriter := gzip.NewWriter(w)
+		closers = append([]io.Closer{gzipWriter}, closers...)
+		w = gzipWriter
+	}
+
+	counts, err := archive.Export(w, userStore, favoriteStore, time.Now())
+	for _, closer := range closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	// the archive may be written to stdout, so the summary goes to stderr
+	fmt.Fprintf(os.Stderr, "exported %d users and %d favorites\n", counts.Users, counts.Favorites)
+	return nil
+}
@@ -0,0 +1,192 @@
+package lambda
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+)
+
+// DrinksLambdaHandler serves the drink catalog, which doesn't require a token
+type DrinksLambdaHandler struct {
+	drinkService  service.DrinkService
+	searchService service.DrinkSearchService
+	// authService and userService are only used for the user's favorites and preferred units
+	authService service.AuthService
+	userService service.UserService
+}
+
+func NewDrinksLambdaHandler(drinkService service.DrinkService, searchService service.DrinkSearchService, authService service.AuthService, userService service.UserService) DrinksLambdaHandler {
+	return DrinksLambdaHandler{
+		drinkService:  drinkService,
+		searchService: searchService,
+		authService:   authService,
+		userService:   userService,
+	}
+}
+
+func (h *DrinksLambdaHandler) FindAllDrinks(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	drinks, err := h.drinkService.FindAllDrinks()
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	body, err := jsoniter.MarshalToString(dto.NewDrinksResponse(drinks))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+// FindDrinkById renders the drink's recipe for the `servings` and in the `units` query parameters;
+// without `units`, the recipe is rendered in the units that the token's user prefers
+func (h *DrinksLambdaHandler) FindDrinkById(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	drinkId := request.PathParameters["drinkId"]
+	format, err := dto.NewRecipeFormat(request.QueryStringParameters["servings"], request.QueryStringParameters["units"])
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+	if _, hasToken := request.Headers["Token"]; hasToken && format.UserUnits {
+		userId, err := authorizeUser(request.Headers, h.authService)
+		if err != nil {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
+		}
+		user, err := h.userService.FindUser(userId)
+		if err != nil {
+			return errorResponse(err), nil
+		}
+		if user != nil {
+			format = format.WithUser(*user)
+		}
+	}
+
+	drink, err := h.drinkService.FindDrinkById(drinkId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	if drink == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no drink was found with id %s", drinkId)),
+		}
+		return response, nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewFormattedDrinkResponse(*drink, format))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+func (h *DrinksLambdaHandler) SearchDrinks(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	query, err := dto.NewDrinkSearchQuery(request.QueryStringParameters)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	result, err := h.searchService.SearchDrinks(query)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	body, err := jsoniter.MarshalToString(dto.NewDrinkSearchResponse(query, result))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+// FindMakeableDrinks finds the drinks that can be made with the ingredients in the body;
+// a token is only needed to restrict the drinks to the user's favorites
+func (h *DrinksLambdaHandler) FindMakeableDrinks(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	var makeableRequest dto.MakeableDrinksRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &makeableRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := makeableRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+
+	favoritesOf := ""
+	if makeableRequest.FavoritesOnly {
+		userId, err := authorizeUser(request.Headers, h.authService)
+		if err != nil {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
+		}
+		favoritesOf = userId
+	}
+
+	result, err := h.searchService.FindMakeableDrinks(makeableRequest.ToQuery(), favoritesOf)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	body, err := jsoniter.MarshalToString(dto.NewMakeableDrinksResponse(result))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+func (h *DrinksLambdaHandler) RouteRequest(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	requestMarshalled, _ := jsoniter.MarshalToString(request)
+	fmt.Printf("request: %v", requestMarshalled)
+	switch request.RouteKey {
+	case "GET /drink":
+		return h.FindAllDrinks(request)
+	case "GET /drink/search":
+		return h.SearchDrinks(request)
+	case "POST /drink/makeable":
+		return h.FindMakeableDrinks(request)
+	case "GET /drink/{drinkId}":
+		return h.FindDrinkById(request)
+	default:
+		fmt.Printf("invalid path in request: %v", request)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(fmt.Sprintf("invalid request path: '%s'", request.RawPath)),
+		}, nil
+	}
+}
@@ -0,0 +1,316 @@
+package lambda
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/search"
+	"the-drink-almanac-api/service"
+)
+
+func TestDrinksLambdaHandler_FindAllDrinks(t *testing.T) {
+	drinks := []model.Drink{
+		{Id: "11007", Name: "Margarita"},
+		{Id: "11008", Name: "Water"},
+	}
+	marshalledDrinks, err := jsoniter.MarshalToString(dto.NewDrinksResponse(drinks))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		returnedDrinks []model.Drink
+		returnedError  error
+		expectedResult events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			returnedDrinks: drinks,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledDrinks,
+			},
+		},
+		"Database unavailable": {
+			returnedError: apperrors.NewUnavailableError("unavailable", errors.New("testing")),
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       messageToResponseBody("unavailable"),
+			},
+		},
+		"Drink service error": {
+			returnedError: errors.New("testing"),
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockDrinkService := service.NewMockDrinkService(t)
+			mockDrinkService.On("FindAllDrinks").Return(tc.returnedDrinks, tc.returnedError)
+			handler := NewDrinksLambdaHandler(mockDrinkService, nil, nil, nil)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{RouteKey: "GET /drink"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestDrinksLambdaHandler_FindDrinkById(t *testing.T) {
+	drink := &model.Drink{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "1 1/2 oz"}}}
+	marshalledDrink, err := jsoniter.MarshalToString(dto.NewFormattedDrinkResponse(*drink, dto.RecipeFormat{Servings: 1, UserUnits: true}))
+	assert.NoError(t, err)
+	marshalledMetricDrink, err := jsoniter.MarshalToString(dto.NewFormattedDrinkResponse(*drink, dto.RecipeFormat{Servings: 2, Units: measure.Metric}))
+	assert.NoError(t, err)
+	marshalledImperialDrink, err := jsoniter.MarshalToString(dto.NewFormattedDrinkResponse(*drink, dto.RecipeFormat{Servings: 1, Units: measure.Imperial, UserUnits: true}))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		queryParameters map[string]string
+		token           string
+		tokenError      error
+		returnedUser    *model.User
+		expectFindDrink bool
+		returnedDrink   *model.Drink
+		returnedError   error
+		expectedResult  events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			expectFindDrink: true,
+			returnedDrink:   drink,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledDrink,
+			},
+		},
+		"Servings in units": {
+			queryParameters: map[string]string{"servings": "2", "units": "metric"},
+			expectFindDrink: true,
+			returnedDrink:   drink,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledMetricDrink,
+			},
+		},
+		"The user's units": {
+			token:           "token",
+			returnedUser:    &model.User{Id: "user0", Units: "imperial"},
+			expectFindDrink: true,
+			returnedDrink:   drink,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledImperialDrink,
+			},
+		},
+		"Invalid token": {
+			token:      "token",
+			tokenError: errors.New("invalid token"),
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(InvalidTokenError.Error()),
+			},
+		},
+		"Invalid units": {
+			queryParameters: map[string]string{"units": "nautical"},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody("invalid units 'nautical'; the units must be either 'metric', 'imperial' or 'written'"),
+			},
+		},
+		"Drink isn't in the catalog": {
+			expectFindDrink: true,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody("no drink was found with id 11007"),
+			},
+		},
+		"Drink service error": {
+			expectFindDrink: true,
+			returnedError:   errors.New("testing"),
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockDrinkService := service.NewMockDrinkService(t)
+			if tc.expectFindDrink {
+				mockDrinkService.On("FindDrinkById", "11007").Return(tc.returnedDrink, tc.returnedError)
+			}
+			mockAuthService := service.NewMockAuthService(t)
+			mockUserService := service.NewMockUserService(t)
+			headers := map[string]string{}
+			if tc.token != "" {
+				headers["Token"] = tc.token
+				mockAuthService.On("ValidateToken", tc.token).Return("user0", tc.tokenError)
+			}
+			if tc.returnedUser != nil {
+				mockUserService.On("FindUser", "user0").Return(tc.returnedUser, nil)
+			}
+			handler := NewDrinksLambdaHandler(mockDrinkService, nil, mockAuthService, mockUserService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:              "GET /drink/{drinkId}",
+				Headers:               headers,
+				PathParameters:        map[string]string{"drinkId": "11007"},
+				QueryStringParameters: tc.queryParameters,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestDrinksLambdaHandler_RouteRequest_InvalidPath(t *testing.T) {
+	handler := NewDrinksLambdaHandler(service.NewMockDrinkService(t), nil, nil, nil)
+	result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{RouteKey: "POST /drink", RawPath: "/drink"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+}
+
+func TestDrinksLambdaHandler_SearchDrinks(t *testing.T) {
+	query := search.Query{Text: "margarita", Ingredients: []string{"Tequila", "Lime juice"}, Sort: search.SortRelevance, Limit: 20}
+	result := search.Result{Total: 1, Hits: []search.Hit{{Drink: model.Drink{Id: "11007", Name: "Margarita"}}}}
+	marshalledResult, err := jsoniter.MarshalToString(dto.NewDrinkSearchResponse(query, result))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		params         map[string]string
+		mockCalls      func(mockSearchService *service.MockDrinkSearchService)
+		expectedResult events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			params: map[string]string{"q": "margarita", "ingredient": "Tequila,Lime juice"},
+			mockCalls: func(mockSearchService *service.MockDrinkSearchService) {
+				mockSearchService.On("SearchDrinks", query).Return(result, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledResult,
+			},
+		},
+		"Invalid query": {
+			params:    map[string]string{"limit": "0"},
+			mockCalls: func(mockSearchService *service.MockDrinkSearchService) {},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody("invalid limit '0'; it must be a number from 1 to 100"),
+			},
+		},
+		"Search service error": {
+			params: map[string]string{},
+			mockCalls: func(mockSearchService *service.MockDrinkSearchService) {
+				mockSearchService.On("SearchDrinks", search.Query{Sort: search.SortRelevance, Limit: 20}).Return(search.Result{}, errors.New("testing"))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockSearchService := service.NewMockDrinkSearchService(t)
+			tc.mockCalls(mockSearchService)
+			handler := NewDrinksLambdaHandler(service.NewMockDrinkService(t), mockSearchService, nil, nil)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:              "GET /drink/search",
+				QueryStringParameters: tc.params,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestDrinksLambdaHandler_FindMakeableDrinks(t *testing.T) {
+	query := search.MakeableQuery{Ingredients: []string{"Tequila", "Lime juice"}, MaxMissing: 2, Limit: 20}
+	result := search.MakeableResult{
+		Makeable:   []search.MakeableHit{},
+		MissingOne: []search.MakeableHit{{Drink: model.Drink{Id: "11007", Name: "Margarita"}, Missing: []string{"Triple sec"}}},
+		MissingTwo: []search.MakeableHit{},
+	}
+	marshalledResult, err := jsoniter.MarshalToString(dto.NewMakeableDrinksResponse(result))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		body           string
+		headers        map[string]string
+		mockCalls      func(mockSearchService *service.MockDrinkSearchService, mockAuthService *service.MockAuthService)
+		expectedResult events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			body: `{"ingredients": ["Tequila", "Lime juice"]}`,
+			mockCalls: func(mockSearchService *service.MockDrinkSearchService, mockAuthService *service.MockAuthService) {
+				mockSearchService.On("FindMakeableDrinks", query, "").Return(result, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledResult,
+			},
+		},
+		"Restricted to favorites": {
+			body:    `{"ingredients": ["Tequila", "Lime juice"], "favoritesOnly": true}`,
+			headers: map[string]string{"Token": "token"},
+			mockCalls: func(mockSearchService *service.MockDrinkSearchService, mockAuthService *service.MockAuthService) {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+				mockSearchService.On("FindMakeableDrinks", query, "0").Return(result, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledResult,
+			},
+		},
+		"Favorites without a token": {
+			body:      `{"ingredients": ["Tequila"], "favoritesOnly": true}`,
+			mockCalls: func(mockSearchService *service.MockDrinkSearchService, mockAuthService *service.MockAuthService) {},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(MissingTokenError.Error()),
+			},
+		},
+		"No ingredients": {
+			body:      `{"ingredients": []}`,
+			mockCalls: func(mockSearchService *service.MockDrinkSearchService, mockAuthService *service.MockAuthService) {},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody("no ingredients provided"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockSearchService := service.NewMockDrinkSearchService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			tc.mockCalls(mockSearchService, mockAuthService)
+			handler := NewDrinksLambdaHandler(service.NewMockDrinkService(t), mockSearchService, mockAuthService, nil)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey: "POST /drink/makeable",
+				Headers:  tc.headers,
+				Body:     tc.body,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
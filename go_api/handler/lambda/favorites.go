@@ -9,18 +9,23 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/service"
 )
 
 type FavoritesLambdaHandler struct {
 	favoriteService service.FavoriteService
 	authService     service.AuthService
+	drinkResolver   service.DrinkResolver
 }
 
-func NewFavoritesLambdaHandler(favoriteService service.FavoriteService, authService service.AuthService) FavoritesLambdaHandler {
+// NewFavoritesLambdaHandler creates the handler; the drink resolver provides the drinks and the user's recipes
+// embedded by `expand=drink`
+func NewFavoritesLambdaHandler(favoriteService service.FavoriteService, authService service.AuthService, drinkResolver service.DrinkResolver) FavoritesLambdaHandler {
 	return FavoritesLambdaHandler{
 		favoriteService: favoriteService,
 		authService:     authService,
+		drinkResolver:   drinkResolver,
 	}
 }
 
@@ -35,18 +40,12 @@ func (h *FavoritesLambdaHandler) FindAllFavorites(request events.APIGatewayV2HTT
 
 	favorites, err := h.favoriteService.FindAllFavorites()
 	if err != nil {
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 	favoritesResponse := dto.NewFavoritesResponse(favorites)
 	body, err := jsoniter.MarshalToString(favoritesResponse)
 	if err != nil {
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 
 	response := events.APIGatewayV2HTTPResponse{
@@ -65,14 +64,31 @@ func (h *FavoritesLambdaHandler) FindFavoritesByUser(request events.APIGatewayV2
 		}, nil
 	}
 
-	favorites, err := h.favoriteService.FindFavoritesByUser(userId)
+	sort, err := dto.NewFavoriteSort(request.QueryStringParameters["sort"])
 	if err != nil {
 		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+	expansion, err := dto.NewFavoriteExpansion(request.QueryStringParameters["expand"])
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
 			Body:       messageToResponseBody(err.Error()),
 		}, nil
 	}
 
+	var favorites []model.Favorite
+	if sort.ByCreation {
+		favorites, err = h.favoriteService.FindFavoritesByUserSortedByCreation(userId, sort.NewestFirst)
+	} else {
+		favorites, err = h.favoriteService.FindFavoritesByUser(userId)
+	}
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
 	if len(favorites) == 0 {
 		response := events.APIGatewayV2HTTPResponse{
 			StatusCode: http.StatusNotFound,
@@ -81,18 +97,61 @@ func (h *FavoritesLambdaHandler) FindFavoritesByUser(request events.APIGatewayV2
 		return response, nil
 	}
 
-	favoritesResponse := dto.NewFavoritesResponse(favorites)
+	var favoritesResponse interface{} = dto.NewFavoritesResponse(favorites)
+	if expansion.Drink {
+		drinks, err := h.drinkResolver.FindDrinks(userId, dto.FavoriteDrinkIds(favorites))
+		if err != nil {
+			return errorResponse(err), nil
+		}
+		favoritesResponse = dto.NewExpandedFavoritesResponse(favorites, drinks)
+	}
 	body, err := jsoniter.MarshalToString(favoritesResponse)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+func (h *FavoritesLambdaHandler) FindFavoriteByUserAndDrink(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
 	if err != nil {
 		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
+			StatusCode: http.StatusForbidden,
 			Body:       messageToResponseBody(err.Error()),
 		}, nil
 	}
 
+	drinkId := request.PathParameters["drinkId"]
+	favorite, err := h.favoriteService.FindFavoriteByUserAndDrink(userId, drinkId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	if favorite == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no favorite was found for user with id %s and drink with id %s", userId, drinkId)),
+		}
+		return response, nil
+	}
+
+	favoriteResponse := dto.NewFavoriteResponse(*favorite)
+	body, err := jsoniter.MarshalToString(favoriteResponse)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
 	response := events.APIGatewayV2HTTPResponse{
 		StatusCode: http.StatusOK,
 		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(favorite.Version),
+		},
 	}
 	return response, nil
 }
@@ -132,26 +191,30 @@ func (h *FavoritesLambdaHandler) CreateNewFavorite(request events.APIGatewayV2HT
 			}
 			return response, nil
 		}
-
-		response := events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(fmt.Sprintf("unable to add the new favorite due to %s", err.Error())),
+		// the request is well-formed, but the drink it refers to doesn't exist
+		if errors.As(err, &apperrors.DrinkNotFoundError{}) {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       messageToResponseBody(err.Error()),
+			}
+			return response, nil
 		}
-		return response, nil
+
+		return errorResponse(err), nil
 	}
 
 	favoriteResponse := dto.NewFavoriteResponse(*newFavorite)
 	body, err := jsoniter.MarshalToString(favoriteResponse)
 	if err != nil {
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 
 	response := events.APIGatewayV2HTTPResponse{
 		StatusCode: http.StatusOK,
 		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(newFavorite.Version),
+		},
 	}
 	return response, nil
 }
@@ -168,10 +231,7 @@ func (h *FavoritesLambdaHandler) DeleteFavorite(request events.APIGatewayV2HTTPR
 	favoriteId := request.QueryStringParameters["favoriteId"]
 	err = h.favoriteService.DeleteFavorite(favoriteId)
 	if err != nil {
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 
 	response := events.APIGatewayV2HTTPResponse{
@@ -186,6 +246,8 @@ func (h *FavoritesLambdaHandler) RouteRequest(request events.APIGatewayV2HTTPReq
 	switch request.RouteKey {
 	case "GET /favorites":
 		return h.FindAllFavorites(request)
+	case "GET /favorite/drink/{drinkId}":
+		return h.FindFavoriteByUserAndDrink(request)
 	case "ANY /favorite/{drinkId}":
 		switch request.RequestContext.HTTP.Method {
 		case "GET":
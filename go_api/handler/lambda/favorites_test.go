@@ -15,7 +15,7 @@ import (
 )
 
 func TestNewFavoritesLambdaHandler(t *testing.T) {
-	h := NewFavoritesLambdaHandler(nil, nil)
+	h := NewFavoritesLambdaHandler(nil, nil, nil)
 	assert.NotNil(t, h)
 }
 
@@ -57,6 +57,24 @@ func TestFavoritesLambdaHandler_FindAllFavorites(t *testing.T) {
 				Body:       marshalledFavorites,
 			},
 		},
+		"Database unavailable": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("FindAllFavorites").
+					Return(nil, apperrors.NewUnavailableError("unavailable", errors.New("testing")))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       messageToResponseBody("unavailable"),
+			},
+		},
 		"Favorite service error": {
 			request: events.APIGatewayV2HTTPRequest{
 				Headers: map[string]string{
@@ -72,7 +90,7 @@ func TestFavoritesLambdaHandler_FindAllFavorites(t *testing.T) {
 			},
 			expectedResult: events.APIGatewayV2HTTPResponse{
 				StatusCode: http.StatusInternalServerError,
-				Body:       messageToResponseBody("testing"),
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
 			},
 		},
 		"Auth service error": {
@@ -118,6 +136,10 @@ func TestFavoritesLambdaHandler_FindFavoritesByUser(t *testing.T) {
 	}
 	marshalledFavorites, err := jsoniter.MarshalToString(dtoFavorites)
 	assert.NoError(t, err)
+	favoritesWithDrinks := []model.Favorite{
+		{Id: "favorite1", DrinkId: "11007"},
+		{Id: "favorite2", DrinkId: "banana"},
+	}
 
 	testCases := map[string]struct {
 		request        events.APIGatewayV2HTTPRequest
@@ -143,6 +165,111 @@ func TestFavoritesLambdaHandler_FindFavoritesByUser(t *testing.T) {
 				Body:       marshalledFavorites,
 			},
 		},
+		"Happy path sorted newest first": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				QueryStringParameters: map[string]string{
+					"sort": "-created_at",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("FindFavoritesByUserSortedByCreation", "userId", true).
+					Return(favorites, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledFavorites,
+			},
+		},
+		"Invalid sort": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				QueryStringParameters: map[string]string{
+					"sort": "drink_id",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody("invalid sort 'drink_id'; the sort must be either 'created_at' or '-created_at'"),
+			},
+		},
+		"Happy path with drinks": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				QueryStringParameters: map[string]string{
+					"expand": "drink",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("FindFavoritesByUser", "userId").
+					Return(favoritesWithDrinks, nil)
+
+				ts.mockDrinkResolver.On("FindDrinks", "userId", []string{"11007", "banana"}).
+					Return(map[string]model.Drink{"11007": {Id: "11007", Name: "Margarita", Category: "Ordinary Drink"}}, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       `[{"id":"favorite1","drinkId":"11007","drink":{"id":"11007","name":"Margarita","category":"Ordinary Drink"}},{"id":"favorite2","drinkId":"banana","drink":null}]`,
+			},
+		},
+		"Drink resolver error": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				QueryStringParameters: map[string]string{
+					"expand": "drink",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("FindFavoritesByUser", "userId").
+					Return(favoritesWithDrinks, nil)
+
+				ts.mockDrinkResolver.On("FindDrinks", "userId", []string{"11007", "banana"}).
+					Return(nil, errors.New("testing"))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+		"Invalid expand": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				QueryStringParameters: map[string]string{
+					"expand": "user",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody("invalid expand 'user'; the only expansion is 'drink'"),
+			},
+		},
 		"No favorites found": {
 			request: events.APIGatewayV2HTTPRequest{
 				Headers: map[string]string{
@@ -176,7 +303,7 @@ func TestFavoritesLambdaHandler_FindFavoritesByUser(t *testing.T) {
 			},
 			expectedResult: events.APIGatewayV2HTTPResponse{
 				StatusCode: http.StatusInternalServerError,
-				Body:       messageToResponseBody("testing"),
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
 			},
 		},
 		"Auth service error": {
@@ -209,12 +336,121 @@ func TestFavoritesLambdaHandler_FindFavoritesByUser(t *testing.T) {
 	}
 }
 
+func TestFavoritesLambdaHandler_FindFavoriteByUserAndDrink(t *testing.T) {
+	favorite := model.Favorite{Id: "favorite1", DrinkId: "drink1", UserId: "userId", Version: 2}
+	favoriteResponse := dto.FavoriteResponse{Id: "favorite1", DrinkId: "drink1"}
+	marshalledFavoriteResponse, err := jsoniter.MarshalToString(favoriteResponse)
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		request        events.APIGatewayV2HTTPRequest
+		mockCalls      func(ts *favoritesTestSuite)
+		expectedResult events.APIGatewayV2HTTPResponse
+		expectError    bool
+	}{
+		"Happy path": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				PathParameters: map[string]string{
+					"drinkId": "drink1",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("FindFavoriteByUserAndDrink", "userId", "drink1").
+					Return(&favorite, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"ETag": `"2"`},
+				Body:       marshalledFavoriteResponse,
+			},
+		},
+		"No favorite found": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				PathParameters: map[string]string{
+					"drinkId": "drink1",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("FindFavoriteByUserAndDrink", "userId", "drink1").
+					Return(nil, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody("no favorite was found for user with id userId and drink with id drink1"),
+			},
+		},
+		"Favorite service error": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				PathParameters: map[string]string{
+					"drinkId": "drink1",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("FindFavoriteByUserAndDrink", "userId", "drink1").
+					Return(nil, errors.New("testing"))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+		"Auth service error": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				PathParameters: map[string]string{
+					"drinkId": "drink1",
+				},
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("", errors.New("testing"))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(InvalidTokenError.Error()),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ts := favoritesSetup(t)
+			tc.mockCalls(ts)
+
+			result, err := ts.handler.FindFavoriteByUserAndDrink(tc.request)
+
+			assert.Equal(t, tc.expectError, err != nil)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
 func TestFavoritesLambdaHandler_CreateNewFavorite(t *testing.T) {
 	favoriteRequest := dto.FavoritePostRequest{DrinkId: "drink1"}
 	marshalledFavoriteRequest, err := jsoniter.MarshalToString(favoriteRequest)
 	assert.NoError(t, err)
 
-	favorite := model.Favorite{DrinkId: "drink1", UserId: "userId"}
+	favorite := model.Favorite{DrinkId: "drink1", UserId: "userId", Version: 1}
 	favoriteResponse := dto.FavoriteResponse{DrinkId: "drink1"}
 	marshalledFavoriteResponse, err := jsoniter.MarshalToString(favoriteResponse)
 	assert.NoError(t, err)
@@ -241,6 +477,7 @@ func TestFavoritesLambdaHandler_CreateNewFavorite(t *testing.T) {
 			},
 			expectedResult: events.APIGatewayV2HTTPResponse{
 				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"ETag": `"1"`},
 				Body:       marshalledFavoriteResponse,
 			},
 		},
@@ -263,6 +500,25 @@ func TestFavoritesLambdaHandler_CreateNewFavorite(t *testing.T) {
 				Body:       messageToResponseBody("the user 'userId' already favorited the drink with id 'drink1'"),
 			},
 		},
+		"Drink doesn't exist": {
+			request: events.APIGatewayV2HTTPRequest{
+				Headers: map[string]string{
+					"Token": "token",
+				},
+				Body: marshalledFavoriteRequest,
+			},
+			mockCalls: func(ts *favoritesTestSuite) {
+				ts.mockAuthService.On("ValidateToken", "token").
+					Return("userId", nil)
+
+				ts.mockFavoriteService.On("CreateNewFavorite", "drink1", "userId").
+					Return(nil, apperrors.NewDrinkNotFoundError("drink1"))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       messageToResponseBody("no drink exists with the id 'drink1'"),
+			},
+		},
 		"Favorite service error": {
 			request: events.APIGatewayV2HTTPRequest{
 				Headers: map[string]string{
@@ -279,7 +535,7 @@ func TestFavoritesLambdaHandler_CreateNewFavorite(t *testing.T) {
 			},
 			expectedResult: events.APIGatewayV2HTTPResponse{
 				StatusCode: http.StatusInternalServerError,
-				Body:       messageToResponseBody("unable to add the new favorite due to testing"),
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
 			},
 		},
 		"Auth service error": {
@@ -358,7 +614,7 @@ func TestFavoritesLambdaHandler_DeleteFavorite(t *testing.T) {
 			},
 			expectedResult: events.APIGatewayV2HTTPResponse{
 				StatusCode: http.StatusInternalServerError,
-				Body:       messageToResponseBody("testing"),
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
 			},
 		},
 		"Auth service error": {
@@ -397,20 +653,24 @@ func TestFavoritesLambdaHandler_DeleteFavorite(t *testing.T) {
 type favoritesTestSuite struct {
 	mockFavoriteService *service.MockFavoriteService
 	mockAuthService     *service.MockAuthService
+	mockDrinkResolver   *service.MockDrinkResolver
 	handler             *FavoritesLambdaHandler
 }
 
 func favoritesSetup(t *testing.T) *favoritesTestSuite {
 	mockFavoriteService := service.NewMockFavoriteService(t)
 	mockAuthService := service.NewMockAuthService(t)
+	mockDrinkResolver := service.NewMockDrinkResolver(t)
 	handler := &FavoritesLambdaHandler{
 		favoriteService: mockFavoriteService,
 		authService:     mockAuthService,
+		drinkResolver:   mockDrinkResolver,
 	}
 
 	return &favoritesTestSuite{
 		mockFavoriteService: mockFavoriteService,
 		mockAuthService:     mockAuthService,
+		mockDrinkResolver:   mockDrinkResolver,
 		handler:             handler,
 	}
 }
@@ -0,0 +1,281 @@
+package lambda
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+)
+
+type InventoryLambdaHandler struct {
+	inventoryService service.InventoryService
+	authService      service.AuthService
+}
+
+func NewInventoryLambdaHandler(inventoryService service.InventoryService, authService service.AuthService) InventoryLambdaHandler {
+	return InventoryLambdaHandler{
+		inventoryService: inventoryService,
+		authService:      authService,
+	}
+}
+
+// pathIngredient returns the ingredient from the path, which api gateway leaves percent-encoded (e.g. "lime%20juice")
+func pathIngredient(request events.APIGatewayV2HTTPRequest) string {
+	ingredient := request.PathParameters["ingredient"]
+	if unescaped, err := url.PathUnescape(ingredient); err == nil {
+		return unescaped
+	}
+	return ingredient
+}
+
+func (h *InventoryLambdaHandler) FindInventory(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	items, err := h.inventoryService.FindInventory(userId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	body, err := jsoniter.MarshalToString(dto.NewInventoryResponse(items))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+func (h *InventoryLambdaHandler) FindInventoryItem(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	ingredient := pathIngredient(request)
+	item, err := h.inventoryService.FindInventoryItem(userId, ingredient)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if item == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("the ingredient '%s' isn't in the inventory", ingredient)),
+		}
+		return response, nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewInventoryItemResponse(*item))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(item.Version),
+		},
+	}
+	return response, nil
+}
+
+func (h *InventoryLambdaHandler) AddInventoryItem(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	var itemRequest dto.InventoryItemRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &itemRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := itemRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+
+	item, err := h.inventoryService.AddInventoryItem(userId, itemRequest.ToInventoryItem())
+	if err != nil {
+		if errors.As(err, &apperrors.InventoryItemAlreadyExistsError{}) {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusConflict,
+				Body:       messageToResponseBody(err.Error()),
+			}
+			return response, nil
+		}
+		return errorResponse(err), nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewInventoryItemResponse(*item))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusCreated,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(item.Version),
+		},
+	}
+	return response, nil
+}
+
+// UpdateInventoryItem replaces the item's quantity, unit and expiry; with an If-Match header,
+// the update is rejected with 412 if the item changed since the client read it
+func (h *InventoryLambdaHandler) UpdateInventoryItem(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	expectedVersion, conditional, err := dto.ParseIfMatch(request.Headers["If-Match"])
+	if err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	var itemRequest dto.InventoryItemRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &itemRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := itemRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	ingredient := pathIngredient(request)
+	if model.IngredientKey(itemRequest.Name) != model.IngredientKey(ingredient) {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(fmt.Sprintf("the name '%s' doesn't match the ingredient '%s'", itemRequest.Name, ingredient)),
+		}
+		return response, nil
+	}
+
+	existingItem, err := h.inventoryService.FindInventoryItem(userId, ingredient)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if existingItem == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("the ingredient '%s' isn't in the inventory", ingredient)),
+		}
+		return response, nil
+	}
+	if !conditional {
+		expectedVersion = existingItem.Version
+	}
+
+	item := itemRequest.ToInventoryItem()
+	item.UserId = userId
+	item.CreatedAt = existingItem.CreatedAt
+	updatedItem, err := h.inventoryService.UpdateInventoryItem(item, expectedVersion)
+	if err != nil {
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusPreconditionFailed,
+				Body:       messageToResponseBody("the ingredient was modified since it was read"),
+			}
+			return response, nil
+		}
+		return errorResponse(err), nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewInventoryItemResponse(*updatedItem))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(updatedItem.Version),
+		},
+	}
+	return response, nil
+}
+
+func (h *InventoryLambdaHandler) DeleteInventoryItem(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	err = h.inventoryService.DeleteInventoryItem(userId, pathIngredient(request))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusNoContent,
+	}
+	return response, nil
+}
+
+func (h *InventoryLambdaHandler) RouteRequest(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	requestMarshalled, _ := jsoniter.MarshalToString(request)
+	fmt.Printf("request: %v", requestMarshalled)
+	switch request.RouteKey {
+	case "GET /inventory":
+		return h.FindInventory(request)
+	case "POST /inventory":
+		return h.AddInventoryItem(request)
+	case "GET /inventory/{ingredient}":
+		return h.FindInventoryItem(request)
+	case "PUT /inventory/{ingredient}":
+		return h.UpdateInventoryItem(request)
+	case "DELETE /inventory/{ingredient}":
+		return h.DeleteInventoryItem(request)
+	default:
+		fmt.Printf("invalid path in request: %v", request)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(fmt.Sprintf("invalid request path: '%s'", request.RawPath)),
+		}, nil
+	}
+}
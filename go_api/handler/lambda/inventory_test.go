@@ -0,0 +1,137 @@
+package lambda
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+)
+
+func TestInventoryLambdaHandler_FindInventoryItem(t *testing.T) {
+	item := &model.InventoryItem{UserId: "0", Ingredient: "lime juice", Name: "Lime Juice", Quantity: 1, Version: 2}
+	marshalledItem, err := jsoniter.MarshalToString(dto.NewInventoryItemResponse(*item))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		returnedItem   *model.InventoryItem
+		returnedError  error
+		expectedResult events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			returnedItem: item,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledItem,
+				Headers:    map[string]string{"ETag": `"2"`},
+			},
+		},
+		"Ingredient isn't in the inventory": {
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody("the ingredient 'lime juice' isn't in the inventory"),
+			},
+		},
+		"Inventory service error": {
+			returnedError: errors.New("testing"),
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockInventoryService := service.NewMockInventoryService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			mockInventoryService.On("FindInventoryItem", "0", "lime juice").Return(tc.returnedItem, tc.returnedError)
+			handler := NewInventoryLambdaHandler(mockInventoryService, mockAuthService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:       "GET /inventory/{ingredient}",
+				Headers:        map[string]string{"Token": "token"},
+				PathParameters: map[string]string{"ingredient": "lime%20juice"},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestInventoryLambdaHandler_UpdateInventoryItem(t *testing.T) {
+	existingItem := &model.InventoryItem{UserId: "0", Ingredient: "gin", Name: "Gin", Quantity: 700, Version: 3}
+
+	testCases := map[string]struct {
+		ifMatch            string
+		expectedVersion    int
+		returnedError      error
+		expectedStatusCode int
+	}{
+		"Happy path": {
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		"Stale If-Match": {
+			ifMatch:            `"2"`,
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the inventory item was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		"Concurrent update without If-Match": {
+			expectedVersion:    3,
+			returnedError:      apperrors.NewConflictError("the inventory item was modified", nil),
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockInventoryService := service.NewMockInventoryService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			mockInventoryService.On("FindInventoryItem", "0", "gin").Return(existingItem, nil)
+			var returnedItem *model.InventoryItem
+			if tc.returnedError == nil {
+				returnedItem = &model.InventoryItem{UserId: "0", Ingredient: "gin", Name: "Gin", Quantity: 350, Version: tc.expectedVersion + 1}
+			}
+			mockInventoryService.On("UpdateInventoryItem", mock.MatchedBy(func(item model.InventoryItem) bool {
+				return item.UserId == "0" && item.Quantity == 350
+			}), tc.expectedVersion).Return(returnedItem, tc.returnedError)
+			handler := NewInventoryLambdaHandler(mockInventoryService, mockAuthService)
+
+			headers := map[string]string{"Token": "token"}
+			if tc.ifMatch != "" {
+				headers["If-Match"] = tc.ifMatch
+			}
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:       "PUT /inventory/{ingredient}",
+				Headers:        headers,
+				PathParameters: map[string]string{"ingredient": "gin"},
+				Body:           `{"name": "Gin", "quantity": 350}`,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestInventoryLambdaHandler_Unauthorized(t *testing.T) {
+	mockAuthService := service.NewMockAuthService(t)
+	handler := NewInventoryLambdaHandler(service.NewMockInventoryService(t), mockAuthService)
+
+	result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{RouteKey: "GET /inventory"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, result.StatusCode)
+}
@@ -0,0 +1,94 @@
+package lambda
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/nutrition"
+	"the-drink-almanac-api/service"
+)
+
+type NutritionLambdaHandler struct {
+	nutritionService service.NutritionService
+	authService      service.AuthService
+}
+
+func NewNutritionLambdaHandler(nutritionService service.NutritionService, authService service.AuthService) NutritionLambdaHandler {
+	return NutritionLambdaHandler{
+		nutritionService: nutritionService,
+		authService:      authService,
+	}
+}
+
+// EstimateDrink estimates the strength, volume, standard drinks and calories of a serving of the drink;
+// with the favoriteId query parameter, it estimates the drink of that favorite of the user instead
+func (h *NutritionLambdaHandler) EstimateDrink(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	favoriteId := request.QueryStringParameters["favoriteId"]
+	userId := ""
+	if _, hasToken := request.Headers["Token"]; hasToken || favoriteId != "" {
+		var err error
+		userId, err = authorizeUser(request.Headers, h.authService)
+		if err != nil {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
+		}
+	}
+
+	drinkId := request.PathParameters["drinkId"]
+	var estimate *nutrition.Estimate
+	var err error
+	if favoriteId != "" {
+		estimate, err = h.nutritionService.EstimateFavorite(userId, drinkId, favoriteId)
+	} else {
+		estimate, err = h.nutritionService.EstimateDrink(userId, drinkId)
+	}
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if estimate == nil {
+		message := fmt.Sprintf("no recipe was found for the drink with id %s", drinkId)
+		if favoriteId != "" {
+			message = fmt.Sprintf("no recipe was found for the favorite with id %s", favoriteId)
+		}
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(message),
+		}
+		return response, nil
+	}
+
+	var responseBody interface{} = dto.NewNutritionResponse(*estimate)
+	if favoriteId != "" {
+		responseBody = dto.NewFavoriteNutritionResponse(favoriteId, *estimate)
+	}
+	body, err := jsoniter.MarshalToString(responseBody)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+func (h *NutritionLambdaHandler) RouteRequest(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	requestMarshalled, _ := jsoniter.MarshalToString(request)
+	fmt.Printf("request: %v", requestMarshalled)
+	switch request.RouteKey {
+	case "GET /drink/{drinkId}/nutrition":
+		return h.EstimateDrink(request)
+	default:
+		fmt.Printf("invalid path in request: %v", request)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(fmt.Sprintf("invalid request path: '%s'", request.RawPath)),
+		}, nil
+	}
+}
@@ -0,0 +1,155 @@
+package lambda
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/nutrition"
+	"the-drink-almanac-api/service"
+)
+
+func TestNutritionLambdaHandler_RouteRequest(t *testing.T) {
+	estimate := &nutrition.Estimate{DrinkId: "11007", DrinkName: "Margarita", Method: nutrition.Shaken, Volume: 154.53, ABV: 14.35}
+	marshalledEstimate, err := jsoniter.MarshalToString(dto.NewNutritionResponse(*estimate))
+	assert.NoError(t, err)
+	marshalledFavoriteEstimate, err := jsoniter.MarshalToString(dto.NewFavoriteNutritionResponse("fav0", *estimate))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		request        events.APIGatewayV2HTTPRequest
+		mockCalls      func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService)
+		expectedResult events.APIGatewayV2HTTPResponse
+	}{
+		"Drink": {
+			request: events.APIGatewayV2HTTPRequest{
+				RouteKey:       "GET /drink/{drinkId}/nutrition",
+				PathParameters: map[string]string{"drinkId": "11007"},
+			},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+				mockNutritionService.On("EstimateDrink", "", "11007").Return(estimate, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledEstimate,
+			},
+		},
+		"Drink without a recipe": {
+			request: events.APIGatewayV2HTTPRequest{
+				RouteKey:       "GET /drink/{drinkId}/nutrition",
+				PathParameters: map[string]string{"drinkId": "11007"},
+			},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+				mockNutritionService.On("EstimateDrink", "", "11007").Return(nil, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody("no recipe was found for the drink with id 11007"),
+			},
+		},
+		"Drink with a token": {
+			request: events.APIGatewayV2HTTPRequest{
+				RouteKey:       "GET /drink/{drinkId}/nutrition",
+				Headers:        map[string]string{"Token": "token"},
+				PathParameters: map[string]string{"drinkId": "11007"},
+			},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+				mockNutritionService.On("EstimateDrink", "0", "11007").Return(estimate, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledEstimate,
+			},
+		},
+		"Favorite": {
+			request: events.APIGatewayV2HTTPRequest{
+				RouteKey:              "GET /drink/{drinkId}/nutrition",
+				Headers:               map[string]string{"Token": "token"},
+				PathParameters:        map[string]string{"drinkId": "11007"},
+				QueryStringParameters: map[string]string{"favoriteId": "fav0"},
+			},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+				mockNutritionService.On("EstimateFavorite", "0", "11007", "fav0").Return(estimate, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledFavoriteEstimate,
+			},
+		},
+		"Favorite without a token": {
+			request: events.APIGatewayV2HTTPRequest{
+				RouteKey:              "GET /drink/{drinkId}/nutrition",
+				PathParameters:        map[string]string{"drinkId": "11007"},
+				QueryStringParameters: map[string]string{"favoriteId": "fav0"},
+			},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(MissingTokenError.Error()),
+			},
+		},
+		"Favorite of another drink": {
+			request: events.APIGatewayV2HTTPRequest{
+				RouteKey:              "GET /drink/{drinkId}/nutrition",
+				Headers:               map[string]string{"Token": "token"},
+				PathParameters:        map[string]string{"drinkId": "11007"},
+				QueryStringParameters: map[string]string{"favoriteId": "fav0"},
+			},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+				mockNutritionService.On("EstimateFavorite", "0", "11007", "fav0").Return(nil, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody("no recipe was found for the favorite with id fav0"),
+			},
+		},
+		"Favorite service error": {
+			request: events.APIGatewayV2HTTPRequest{
+				RouteKey:              "GET /drink/{drinkId}/nutrition",
+				Headers:               map[string]string{"Token": "token"},
+				PathParameters:        map[string]string{"drinkId": "11007"},
+				QueryStringParameters: map[string]string{"favoriteId": "fav0"},
+			},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+				mockNutritionService.On("EstimateFavorite", "0", "11007", "fav0").Return(nil, errors.New("testing"))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+		"Invalid path": {
+			request: events.APIGatewayV2HTTPRequest{RouteKey: "GET /nutrition", RawPath: "/nutrition"},
+			mockCalls: func(mockNutritionService *service.MockNutritionService, mockAuthService *service.MockAuthService) {
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody("invalid request path: '/nutrition'"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockNutritionService := service.NewMockNutritionService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			tc.mockCalls(mockNutritionService, mockAuthService)
+			handler := NewNutritionLambdaHandler(mockNutritionService, mockAuthService)
+
+			result, err := handler.RouteRequest(tc.request)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package lambda
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+)
+
+type RecipesLambdaHandler struct {
+	recipeService service.RecipeService
+	authService   service.AuthService
+}
+
+func NewRecipesLambdaHandler(recipeService service.RecipeService, authService service.AuthService) RecipesLambdaHandler {
+	return RecipesLambdaHandler{
+		recipeService: recipeService,
+		authService:   authService,
+	}
+}
+
+// FindRecipes returns the user's own recipes, whatever their visibility
+func (h *RecipesLambdaHandler) FindRecipes(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	recipes, err := h.recipeService.FindRecipesByOwner(userId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	body, err := jsoniter.MarshalToString(dto.NewRecipesResponse(recipes))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+// FindRecipe returns the recipe if the user can see it; without a token, only unlisted and public recipes are found
+func (h *RecipesLambdaHandler) FindRecipe(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId := ""
+	if _, hasToken := request.Headers["Token"]; hasToken {
+		var err error
+		userId, err = authorizeUser(request.Headers, h.authService)
+		if err != nil {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
+		}
+	}
+
+	recipeId := request.PathParameters["recipeId"]
+	recipe, err := h.recipeService.FindRecipe(userId, recipeId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if recipe == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no recipe was found with id %s", recipeId)),
+		}
+		return response, nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewRecipeResponse(*recipe))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(recipe.Version),
+		},
+	}
+	return response, nil
+}
+
+func (h *RecipesLambdaHandler) CreateRecipe(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	var recipeRequest dto.RecipeRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &recipeRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := recipeRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+
+	recipe, err := h.recipeService.CreateRecipe(userId, recipeRequest.ToRecipe())
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewRecipeResponse(*recipe))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusCreated,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(recipe.Version),
+		},
+	}
+	return response, nil
+}
+
+// UpdateRecipe replaces the user's recipe; with an If-Match header,
+// the update is rejected with 412 if the recipe changed since the client read it
+func (h *RecipesLambdaHandler) UpdateRecipe(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	expectedVersion, conditional, err := dto.ParseIfMatch(request.Headers["If-Match"])
+	if err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	var recipeRequest dto.RecipeRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &recipeRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := recipeRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+
+	recipeId := request.PathParameters["recipeId"]
+	if !conditional {
+		existingRecipe, err := h.recipeService.FindRecipe(userId, recipeId)
+		if err != nil {
+			return errorResponse(err), nil
+		}
+		if existingRecipe == nil {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody(fmt.Sprintf("no recipe was found with id %s", recipeId)),
+			}
+			return response, nil
+		}
+		expectedVersion = existingRecipe.Version
+	}
+
+	recipe := recipeRequest.ToRecipe()
+	recipe.Id = recipeId
+	updatedRecipe, err := h.recipeService.UpdateRecipe(userId, recipe, expectedVersion)
+	if err != nil {
+		if errors.As(err, &apperrors.RecipeNotOwnedError{}) {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}
+			return response, nil
+		}
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusPreconditionFailed,
+				Body:       messageToResponseBody("the recipe was modified since it was read"),
+			}
+			return response, nil
+		}
+		return errorResponse(err), nil
+	}
+	if updatedRecipe == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no recipe was found with id %s", recipeId)),
+		}
+		return response, nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewRecipeResponse(*updatedRecipe))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(updatedRecipe.Version),
+		},
+	}
+	return response, nil
+}
+
+func (h *RecipesLambdaHandler) DeleteRecipe(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	err = h.recipeService.DeleteRecipe(userId, request.PathParameters["recipeId"])
+	if err != nil {
+		if errors.As(err, &apperrors.RecipeNotOwnedError{}) {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}
+			return response, nil
+		}
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusNoContent,
+	}
+	return response, nil
+}
+
+func (h *RecipesLambdaHandler) RouteRequest(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	requestMarshalled, _ := jsoniter.MarshalToString(request)
+	fmt.Printf("request: %v", requestMarshalled)
+	switch request.RouteKey {
+	case "GET /recipe":
+		return h.FindRecipes(request)
+	case "POST /recipe":
+		return h.CreateRecipe(request)
+	case "GET /recipe/{recipeId}":
+		return h.FindRecipe(request)
+	case "PUT /recipe/{recipeId}":
+		return h.UpdateRecipe(request)
+	case "DELETE /recipe/{recipeId}":
+		return h.DeleteRecipe(request)
+	default:
+		fmt.Printf("invalid path in request: %v", request)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(fmt.Sprintf("invalid request path: '%s'", request.RawPath)),
+		}, nil
+	}
+}
@@ -0,0 +1,249 @@
+package lambda
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+)
+
+func TestRecipesLambdaHandler_FindRecipe(t *testing.T) {
+	recipe := &model.Recipe{Id: "recipe-0", OwnerId: "1", Name: "House Margarita", Visibility: model.RecipePublic, Version: 2}
+	marshalledRecipe, err := jsoniter.MarshalToString(dto.NewRecipeResponse(*recipe))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		headers        map[string]string
+		expectedUserId string
+		returnedRecipe *model.Recipe
+		returnedError  error
+		expectedResult events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			headers:        map[string]string{"Token": "token"},
+			expectedUserId: "0",
+			returnedRecipe: recipe,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledRecipe,
+				Headers:    map[string]string{"ETag": `"2"`},
+			},
+		},
+		"Anonymous user": {
+			returnedRecipe: recipe,
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledRecipe,
+				Headers:    map[string]string{"ETag": `"2"`},
+			},
+		},
+		"Recipe doesn't exist or isn't visible": {
+			headers:        map[string]string{"Token": "token"},
+			expectedUserId: "0",
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody("no recipe was found with id recipe-0"),
+			},
+		},
+		"Recipe service error": {
+			returnedError: errors.New("testing"),
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			if tc.headers["Token"] != "" {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			}
+			mockRecipeService.On("FindRecipe", tc.expectedUserId, "recipe-0").Return(tc.returnedRecipe, tc.returnedError)
+			handler := NewRecipesLambdaHandler(mockRecipeService, mockAuthService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:       "GET /recipe/{recipeId}",
+				Headers:        tc.headers,
+				PathParameters: map[string]string{"recipeId": "recipe-0"},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestRecipesLambdaHandler_CreateRecipe(t *testing.T) {
+	testCases := map[string]struct {
+		headers            map[string]string
+		body               string
+		expectCreate       bool
+		expectedStatusCode int
+	}{
+		"Happy path": {
+			headers:            map[string]string{"Token": "token"},
+			body:               `{"name": "House Margarita", "ingredients": [{"name": "Tequila", "measure": "2 oz"}]}`,
+			expectCreate:       true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		"Missing ingredients": {
+			headers:            map[string]string{"Token": "token"},
+			body:               `{"name": "House Margarita"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"Missing token": {
+			body:               `{"name": "House Margarita", "ingredients": [{"name": "Tequila"}]}`,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			if tc.headers["Token"] != "" {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			}
+			if tc.expectCreate {
+				mockRecipeService.On("CreateRecipe", "0", mock.MatchedBy(func(recipe model.Recipe) bool {
+					return recipe.Name == "House Margarita" && len(recipe.Ingredients) == 1
+				})).Return(&model.Recipe{Id: "recipe-0", OwnerId: "0", Name: "House Margarita", Visibility: model.RecipePrivate, Version: 1}, nil)
+			}
+			handler := NewRecipesLambdaHandler(mockRecipeService, mockAuthService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey: "POST /recipe",
+				Headers:  tc.headers,
+				Body:     tc.body,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestRecipesLambdaHandler_UpdateRecipe(t *testing.T) {
+	ownRecipe := &model.Recipe{Id: "recipe-0", OwnerId: "0", Name: "Margarita", Visibility: model.RecipePrivate, Version: 3}
+
+	testCases := map[string]struct {
+		ifMatch            string
+		existingRecipe     *model.Recipe
+		expectUpdate       bool
+		expectedVersion    int
+		recipeMissing      bool
+		returnedError      error
+		expectedStatusCode int
+	}{
+		"Happy path": {
+			existingRecipe:     ownRecipe,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		"Stale If-Match": {
+			ifMatch:            `"2"`,
+			expectUpdate:       true,
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the recipe was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		"Another user's recipe": {
+			existingRecipe:     &model.Recipe{Id: "recipe-0", OwnerId: "1", Visibility: model.RecipePublic, Version: 3},
+			expectUpdate:       true,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewRecipeNotOwnedError("recipe-0"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		"Recipe doesn't exist": {
+			expectedStatusCode: http.StatusNotFound,
+		},
+		"Recipe doesn't exist with If-Match": {
+			ifMatch:            `"3"`,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			recipeMissing:      true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			if tc.ifMatch == "" {
+				mockRecipeService.On("FindRecipe", "0", "recipe-0").Return(tc.existingRecipe, nil)
+			}
+			if tc.expectUpdate {
+				var returnedRecipe *model.Recipe
+				if tc.returnedError == nil && !tc.recipeMissing {
+					returnedRecipe = &model.Recipe{Id: "recipe-0", OwnerId: "0", Name: "House Margarita", Version: tc.expectedVersion + 1}
+				}
+				mockRecipeService.On("UpdateRecipe", "0", mock.MatchedBy(func(recipe model.Recipe) bool {
+					return recipe.Id == "recipe-0" && recipe.Name == "House Margarita"
+				}), tc.expectedVersion).Return(returnedRecipe, tc.returnedError)
+			}
+			handler := NewRecipesLambdaHandler(mockRecipeService, mockAuthService)
+
+			headers := map[string]string{"Token": "token"}
+			if tc.ifMatch != "" {
+				headers["If-Match"] = tc.ifMatch
+			}
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:       "PUT /recipe/{recipeId}",
+				Headers:        headers,
+				PathParameters: map[string]string{"recipeId": "recipe-0"},
+				Body:           `{"name": "House Margarita", "ingredients": [{"name": "Tequila", "measure": "2 oz"}]}`,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestRecipesLambdaHandler_DeleteRecipe(t *testing.T) {
+	testCases := map[string]struct {
+		returnedError      error
+		expectedStatusCode int
+	}{
+		"Happy path": {
+			expectedStatusCode: http.StatusNoContent,
+		},
+		"Another user's recipe": {
+			returnedError:      apperrors.NewRecipeNotOwnedError("recipe-0"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			mockRecipeService.On("DeleteRecipe", "0", "recipe-0").Return(tc.returnedError)
+			handler := NewRecipesLambdaHandler(mockRecipeService, mockAuthService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:       "DELETE /recipe/{recipeId}",
+				Headers:        map[string]string{"Token": "token"},
+				PathParameters: map[string]string{"recipeId": "recipe-0"},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
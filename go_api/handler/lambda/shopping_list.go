@@ -0,0 +1,101 @@
+package lambda
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+)
+
+type ShoppingListLambdaHandler struct {
+	shoppingListService service.ShoppingListService
+	authService         service.AuthService
+}
+
+func NewShoppingListLambdaHandler(shoppingListService service.ShoppingListService, authService service.AuthService) ShoppingListLambdaHandler {
+	return ShoppingListLambdaHandler{
+		shoppingListService: shoppingListService,
+		authService:         authService,
+	}
+}
+
+// CreateShoppingList builds the list for the drinks in the body, or for the favorites of the user in the token
+// if there aren't any; `format=markdown` returns only the Markdown rendering
+func (h *ShoppingListLambdaHandler) CreateShoppingList(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	format, err := dto.NewShoppingListFormat(request.QueryStringParameters["format"])
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+	var listRequest dto.ShoppingListRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &listRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := listRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+
+	userId := ""
+	if len(listRequest.DrinkIds) == 0 {
+		userId, err = authorizeUser(request.Headers, h.authService)
+		if err != nil {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
+		}
+	}
+
+	list, err := h.shoppingListService.CreateShoppingList(userId, listRequest.DrinkIds, listRequest.ServingsOrDefault(), listRequest.ToOnHand())
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if format == dto.ShoppingListMarkdown {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusOK,
+			Body:       list.Markdown(),
+			Headers: map[string]string{
+				"Content-Type": "text/markdown; charset=utf-8",
+			},
+		}
+		return response, nil
+	}
+	body, err := jsoniter.MarshalToString(dto.NewShoppingListResponse(*list))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+func (h *ShoppingListLambdaHandler) RouteRequest(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	requestMarshalled, _ := jsoniter.MarshalToString(request)
+	fmt.Printf("request: %v", requestMarshalled)
+	switch request.RouteKey {
+	case "POST /shopping-list":
+		return h.CreateShoppingList(request)
+	default:
+		fmt.Printf("invalid path in request: %v", request)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(fmt.Sprintf("invalid request path: '%s'", request.RawPath)),
+		}, nil
+	}
+}
@@ -0,0 +1,94 @@
+package lambda
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+	"the-drink-almanac-api/shopping"
+)
+
+func TestShoppingListLambdaHandler_CreateShoppingList(t *testing.T) {
+	list := shopping.NewList([]model.Drink{{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}}}}, 1, nil)
+	marshalledList, err := jsoniter.MarshalToString(dto.NewShoppingListResponse(list))
+	assert.NoError(t, err)
+
+	testCases := map[string]struct {
+		body           string
+		params         map[string]string
+		headers        map[string]string
+		mockCalls      func(mockShoppingListService *service.MockShoppingListService, mockAuthService *service.MockAuthService)
+		expectedResult events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			body: `{"drinkIds": ["11007"]}`,
+			mockCalls: func(mockShoppingListService *service.MockShoppingListService, mockAuthService *service.MockAuthService) {
+				mockShoppingListService.On("CreateShoppingList", "", []string{"11007"}, 1, mock.Anything).Return(&list, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       marshalledList,
+			},
+		},
+		"Favorites as markdown": {
+			body:    `{}`,
+			params:  map[string]string{"format": "markdown"},
+			headers: map[string]string{"Token": "token"},
+			mockCalls: func(mockShoppingListService *service.MockShoppingListService, mockAuthService *service.MockAuthService) {
+				mockAuthService.On("ValidateToken", "token").Return("0", nil)
+				mockShoppingListService.On("CreateShoppingList", "0", []string(nil), 1, mock.Anything).Return(&list, nil)
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       list.Markdown(),
+				Headers:    map[string]string{"Content-Type": "text/markdown; charset=utf-8"},
+			},
+		},
+		"Favorites without a token": {
+			body: `{}`,
+			mockCalls: func(mockShoppingListService *service.MockShoppingListService, mockAuthService *service.MockAuthService) {
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(MissingTokenError.Error()),
+			},
+		},
+		"Shopping list service error": {
+			body: `{"drinkIds": ["11007"]}`,
+			mockCalls: func(mockShoppingListService *service.MockShoppingListService, mockAuthService *service.MockAuthService) {
+				mockShoppingListService.On("CreateShoppingList", "", []string{"11007"}, 1, mock.Anything).Return(nil, errors.New("testing"))
+			},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusInternalServerError,
+				Body:       messageToResponseBody(apperrors.InternalErrorMessage),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockShoppingListService := service.NewMockShoppingListService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			tc.mockCalls(mockShoppingListService, mockAuthService)
+			handler := NewShoppingListLambdaHandler(mockShoppingListService, mockAuthService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:              "POST /shopping-list",
+				Headers:               tc.headers,
+				QueryStringParameters: tc.params,
+				Body:                  tc.body,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
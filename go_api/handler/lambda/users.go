@@ -1,6 +1,7 @@
 package lambda
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,21 +10,26 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/service"
 )
 
 type UsersLambdaHandler struct {
-	userService service.UserService
-	authService service.AuthService
+	userService    service.UserService
+	authService    service.AuthService
+	profileService service.ProfileService
 }
 
-func NewUsersLambdaHandler(userService service.UserService, authService service.AuthService) UsersLambdaHandler {
+func NewUsersLambdaHandler(userService service.UserService, authService service.AuthService, profileService service.ProfileService) UsersLambdaHandler {
 	return UsersLambdaHandler{
-		userService: userService,
-		authService: authService,
+		userService:    userService,
+		authService:    authService,
+		profileService: profileService,
 	}
 }
 
+// FindUser returns the user's data; `include=favorites` adds the user's favorites,
+// which the single-table design reads in the same request as the user
 func (h *UsersLambdaHandler) FindUser(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
 	userId, err := authorizeUser(request.Headers, h.authService)
 	if err != nil {
@@ -33,12 +39,24 @@ func (h *UsersLambdaHandler) FindUser(request events.APIGatewayV2HTTPRequest) (e
 		}, nil
 	}
 
-	user, err := h.userService.FindUser(userId)
+	inclusion, err := dto.NewUserInclusion(request.QueryStringParameters["include"])
 	if err != nil {
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
 			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		}
+		return response, nil
+	}
+
+	var user *model.User
+	var favorites []model.Favorite
+	if inclusion.Favorites {
+		user, favorites, err = h.userService.FindUserWithFavorites(userId)
+	} else {
+		user, err = h.userService.FindUser(userId)
+	}
+	if err != nil {
+		return errorResponse(err), nil
 	}
 
 	if user == nil {
@@ -50,17 +68,20 @@ func (h *UsersLambdaHandler) FindUser(request events.APIGatewayV2HTTPRequest) (e
 	}
 
 	userResponse := dto.NewUserResponse(*user)
+	if inclusion.Favorites {
+		userResponse = dto.NewUserWithFavoritesResponse(*user, favorites)
+	}
 	body, err := jsoniter.MarshalToString(userResponse)
 	if err != nil {
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 
 	response := events.APIGatewayV2HTTPResponse{
 		StatusCode: http.StatusOK,
 		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(user.Version),
+		},
 	}
 	return response, nil
 }
@@ -92,11 +113,7 @@ func (h *UsersLambdaHandler) CreateNewUser(request events.APIGatewayV2HTTPReques
 			}
 			return response, nil
 		}
-		response := events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(err.Error()),
-		}
-		return response, nil
+		return errorResponse(err), nil
 	}
 	response := events.APIGatewayV2HTTPResponse{
 		StatusCode: http.StatusCreated,
@@ -115,14 +132,13 @@ func (h *UsersLambdaHandler) DeleteUser(request events.APIGatewayV2HTTPRequest)
 
 	err = h.userService.DeleteUser(userId)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
 		if errors.As(err, &apperrors.InvalidAuthTokenError{}) {
-			statusCode = http.StatusForbidden
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusForbidden,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
 		}
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: statusCode,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 
 	return events.APIGatewayV2HTTPResponse{
@@ -131,6 +147,316 @@ func (h *UsersLambdaHandler) DeleteUser(request events.APIGatewayV2HTTPRequest)
 	}, nil
 }
 
+// UpdatePreferences saves the user's preferences, such as the units that recipes are rendered in;
+// with an If-Match header, the update is rejected with 412 if the user changed since the client read it
+func (h *UsersLambdaHandler) UpdatePreferences(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	expectedVersion, conditional, err := dto.ParseIfMatch(request.Headers["If-Match"])
+	if err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	var preferencesRequest dto.UserPreferencesRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &preferencesRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := preferencesRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+
+	user, err := h.userService.FindUser(userId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if user == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no user was found for user id %s", userId)),
+		}
+		return response, nil
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	user.Units = *preferencesRequest.Units
+	updatedUser, err := h.userService.UpdateUser(*user, expectedVersion)
+	if err != nil {
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusPreconditionFailed,
+				Body:       messageToResponseBody("the user was modified since it was read"),
+			}, nil
+		}
+		return errorResponse(err), nil
+	}
+	body, err := jsoniter.MarshalToString(dto.NewUserResponse(*updatedUser))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(updatedUser.Version),
+		},
+	}
+	return response, nil
+}
+
+// UpdateUser changes the fields of the user's profile and preferences that are in the body, leaving the rest as they are;
+// with an If-Match header, the update is rejected with 412 if the user changed since the client read it
+func (h *UsersLambdaHandler) UpdateUser(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	expectedVersion, conditional, err := dto.ParseIfMatch(request.Headers["If-Match"])
+	if err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	var patchRequest dto.UserPatchRequest
+	if err := jsoniter.Unmarshal([]byte(request.Body), &patchRequest); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	if err := patchRequest.ValidateRequest(); err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+
+	user, err := h.userService.FindUser(userId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if user == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no user was found for user id %s", userId)),
+		}
+		return response, nil
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	patchRequest.ApplyTo(user)
+	updatedUser, err := h.userService.UpdateUser(*user, expectedVersion)
+	return userUpdateResponse(updatedUser, conditional, err), nil
+}
+
+// FindProfile returns the user's public profile, which doesn't need a token
+func (h *UsersLambdaHandler) FindProfile(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	username := request.PathParameters["username"]
+	user, favorites, err := h.profileService.FindProfile(username)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if user == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no user was found with the username '%s'", username)),
+		}
+		return response, nil
+	}
+
+	body, err := jsoniter.MarshalToString(dto.NewProfileResponse(*user, favorites))
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+	}
+	return response, nil
+}
+
+// FindAvatar returns the user's avatar image, base64 encoded for API Gateway, which doesn't need a token
+func (h *UsersLambdaHandler) FindAvatar(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	username := request.PathParameters["username"]
+	image, contentType, err := h.profileService.FindAvatar(username)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if image == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("the user '%s' has no avatar", username)),
+		}
+		return response, nil
+	}
+
+	response := events.APIGatewayV2HTTPResponse{
+		StatusCode:      http.StatusOK,
+		Body:            base64.StdEncoding.EncodeToString(image),
+		IsBase64Encoded: true,
+		Headers: map[string]string{
+			"Content-Type": contentType,
+		},
+	}
+	return response, nil
+}
+
+// UpdateAvatar saves the image in the body as the user's avatar; API Gateway base64 encodes binary bodies
+func (h *UsersLambdaHandler) UpdateAvatar(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	expectedVersion, conditional, err := dto.ParseIfMatch(request.Headers["If-Match"])
+	if err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	image := []byte(request.Body)
+	if request.IsBase64Encoded {
+		image, err = base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			response := events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody("the image isn't valid base64"),
+			}
+			return response, nil
+		}
+	}
+	if len(image) > service.MaxAvatarSize {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Body:       messageToResponseBody(fmt.Sprintf("the avatar must be at most %d bytes", service.MaxAvatarSize)),
+		}
+		return response, nil
+	}
+
+	user, err := h.userService.FindUser(userId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if user == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no user was found for user id %s", userId)),
+		}
+		return response, nil
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	updatedUser, err := h.profileService.UpdateAvatar(*user, expectedVersion, image)
+	if errors.As(err, &apperrors.InvalidAvatarError{}) {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	return userUpdateResponse(updatedUser, conditional, err), nil
+}
+
+// DeleteAvatar removes the user's avatar; with an If-Match header,
+// the delete is rejected with 412 if the user changed since the client read it
+func (h *UsersLambdaHandler) DeleteAvatar(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	userId, err := authorizeUser(request.Headers, h.authService)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusForbidden,
+			Body:       messageToResponseBody(err.Error()),
+		}, nil
+	}
+
+	expectedVersion, conditional, err := dto.ParseIfMatch(request.Headers["If-Match"])
+	if err != nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusBadRequest,
+			Body:       messageToResponseBody(err.Error()),
+		}
+		return response, nil
+	}
+	user, err := h.userService.FindUser(userId)
+	if err != nil {
+		return errorResponse(err), nil
+	}
+	if user == nil {
+		response := events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusNotFound,
+			Body:       messageToResponseBody(fmt.Sprintf("no user was found for user id %s", userId)),
+		}
+		return response, nil
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	updatedUser, err := h.profileService.DeleteAvatar(*user, expectedVersion)
+	return userUpdateResponse(updatedUser, conditional, err), nil
+}
+
+// userUpdateResponse returns the updated user with its new ETag, or the error of a failed update;
+// a conflicting conditional update is rejected with 412
+func userUpdateResponse(updatedUser *model.User, conditional bool, err error) events.APIGatewayV2HTTPResponse {
+	if err != nil {
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusPreconditionFailed,
+				Body:       messageToResponseBody("the user was modified since it was read"),
+			}
+		}
+		return errorResponse(err)
+	}
+	body, err := jsoniter.MarshalToString(dto.NewUserResponse(*updatedUser))
+	if err != nil {
+		return errorResponse(err)
+	}
+
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusOK,
+		Body:       body,
+		Headers: map[string]string{
+			"ETag": dto.NewETag(updatedUser.Version),
+		},
+	}
+}
+
 func (h *UsersLambdaHandler) Login(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
 	var userRequest dto.UserPostRequest
 	if err := jsoniter.Unmarshal([]byte(request.Body), userRequest); err != nil {
@@ -151,26 +477,24 @@ func (h *UsersLambdaHandler) Login(request events.APIGatewayV2HTTPRequest) (even
 
 	user, err := h.userService.Login(userRequest.Username, userRequest.Password)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
 		if errors.As(err, &apperrors.UserNotFoundError{}) {
-			statusCode = http.StatusNotFound
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
 		}
 		if errors.As(err, &apperrors.IncorrectPasswordError{}) {
-			statusCode = http.StatusBadRequest
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusBadRequest,
+				Body:       messageToResponseBody(err.Error()),
+			}, nil
 		}
-
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: statusCode,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 
 	tokenString, err := h.authService.CreateNewToken(user.Id, 60*24)
 	if err != nil {
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: http.StatusInternalServerError,
-			Body:       messageToResponseBody(err.Error()),
-		}, nil
+		return errorResponse(err), nil
 	}
 
 	response := events.APIGatewayV2HTTPResponse{
@@ -194,6 +518,18 @@ func (h *UsersLambdaHandler) RouteRequest(request events.APIGatewayV2HTTPRequest
 		return h.Login(request)
 	case "POST /user/register":
 		return h.CreateNewUser(request)
+	case "PUT /user/preferences":
+		return h.UpdatePreferences(request)
+	case "PATCH /user":
+		return h.UpdateUser(request)
+	case "PUT /user/avatar":
+		return h.UpdateAvatar(request)
+	case "DELETE /user/avatar":
+		return h.DeleteAvatar(request)
+	case "GET /users/{username}":
+		return h.FindProfile(request)
+	case "GET /users/{username}/avatar":
+		return h.FindAvatar(request)
 	default:
 		fmt.Printf("invalid path in request: %v", request)
 		return events.APIGatewayV2HTTPResponse{
@@ -0,0 +1,310 @@
+package lambda
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+)
+
+func TestUsersLambdaHandler_UpdatePreferences(t *testing.T) {
+	existingUser := &model.User{Id: "0", Username: "user0", Version: 3}
+
+	testCases := map[string]struct {
+		body               string
+		ifMatch            string
+		expectedVersion    int
+		returnedError      error
+		expectedStatusCode int
+	}{
+		"Happy path": {
+			body:               `{"units": "metric"}`,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		"Stale If-Match": {
+			body:               `{"units": "metric"}`,
+			ifMatch:            `"2"`,
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the user was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		"Concurrent update without If-Match": {
+			body:               `{"units": "metric"}`,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewConflictError("the user was modified", nil),
+			expectedStatusCode: http.StatusConflict,
+		},
+		"Invalid units": {
+			body:               `{"units": "nautical"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"No units": {
+			body:               `{}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockUserService := service.NewMockUserService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			if tc.expectedVersion != 0 {
+				mockUserService.On("FindUser", "0").Return(existingUser, nil)
+				var returnedUser *model.User
+				if tc.returnedError == nil {
+					returnedUser = &model.User{Id: "0", Username: "user0", Units: "metric", Version: tc.expectedVersion + 1}
+				}
+				mockUserService.On("UpdateUser", mock.MatchedBy(func(user model.User) bool {
+					return user.Id == "0" && user.Units == "metric"
+				}), tc.expectedVersion).Return(returnedUser, tc.returnedError)
+			}
+			handler := NewUsersLambdaHandler(mockUserService, mockAuthService, service.NewMockProfileService(t))
+
+			headers := map[string]string{"Token": "token"}
+			if tc.ifMatch != "" {
+				headers["If-Match"] = tc.ifMatch
+			}
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey: "PUT /user/preferences",
+				Headers:  headers,
+				Body:     tc.body,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+			if tc.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, dto.NewETag(tc.expectedVersion+1), result.Headers["ETag"])
+			}
+		})
+	}
+}
+
+func TestUsersLambdaHandler_FindUser(t *testing.T) {
+	user := &model.User{Id: "0", Username: "user0", Version: 3}
+	testCases := map[string]struct {
+		include            string
+		expectFavorites    bool
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		"Happy path": {
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `"favorites"`,
+		},
+		"Include favorites": {
+			include:            "favorites",
+			expectFavorites:    true,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `"favorites":[{"id":"1","drinkId":"11007"`,
+		},
+		"Invalid include": {
+			include:            "recipes",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       "invalid include",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockUserService := service.NewMockUserService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			switch {
+			case tc.expectFavorites:
+				mockUserService.On("FindUserWithFavorites", "0").Return(user, []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007"}}, nil)
+			case tc.expectedStatusCode == http.StatusOK:
+				mockUserService.On("FindUser", "0").Return(user, nil)
+			}
+			handler := NewUsersLambdaHandler(mockUserService, mockAuthService, service.NewMockProfileService(t))
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:              "GET /user",
+				Headers:               map[string]string{"Token": "token"},
+				QueryStringParameters: map[string]string{"include": tc.include},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+			if tc.expectFavorites || tc.expectedStatusCode != http.StatusOK {
+				assert.Contains(t, result.Body, tc.expectedBody)
+			} else {
+				assert.NotContains(t, result.Body, tc.expectedBody)
+			}
+		})
+	}
+}
+
+func TestUsersLambdaHandler_UpdateUser(t *testing.T) {
+	testCases := map[string]struct {
+		body               string
+		expectUpdate       bool
+		expectedStatusCode int
+	}{
+		"Happy path": {
+			body:               `{"displayName": "User Zero", "bio": "Mostly sours.", "publicFavorites": true}`,
+			expectUpdate:       true,
+			expectedStatusCode: http.StatusOK,
+		},
+		"No changes": {
+			body:               `{}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"Bio is too long": {
+			body:               `{"bio": "` + strings.Repeat("b", dto.MaxBioLength+1) + `"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockUserService := service.NewMockUserService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			if tc.expectUpdate {
+				mockUserService.On("FindUser", "0").Return(&model.User{Id: "0", Username: "user0", Units: "metric", Version: 3}, nil)
+				mockUserService.On("UpdateUser", model.User{
+					Id: "0", Username: "user0", Units: "metric", DisplayName: "User Zero", Bio: "Mostly sours.", PublicFavorites: true, Version: 3,
+				}, 3).Return(&model.User{Id: "0", Username: "user0", Version: 4}, nil)
+			}
+			handler := NewUsersLambdaHandler(mockUserService, mockAuthService, service.NewMockProfileService(t))
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey: "PATCH /user",
+				Headers:  map[string]string{"Token": "token"},
+				Body:     tc.body,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+			if tc.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, `"4"`, result.Headers["ETag"])
+			}
+		})
+	}
+}
+
+func TestUsersLambdaHandler_FindProfile(t *testing.T) {
+	testCases := map[string]struct {
+		returnedUser      *model.User
+		returnedFavorites []model.Favorite
+		expectedResult    events.APIGatewayV2HTTPResponse
+	}{
+		"Happy path": {
+			returnedUser:      &model.User{Id: "0", Username: "user0", Password: "hash", DisplayName: "User Zero", PublicFavorites: true},
+			returnedFavorites: []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007"}},
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusOK,
+				Body:       `{"username":"user0","displayName":"User Zero","publicFavorites":true,"favorites":[{"drinkId":"11007"}]}`,
+			},
+		},
+		"User doesn't exist": {
+			expectedResult: events.APIGatewayV2HTTPResponse{
+				StatusCode: http.StatusNotFound,
+				Body:       messageToResponseBody("no user was found with the username 'user0'"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockProfileService := service.NewMockProfileService(t)
+			mockProfileService.On("FindProfile", "user0").Return(tc.returnedUser, tc.returnedFavorites, nil)
+			handler := NewUsersLambdaHandler(service.NewMockUserService(t), service.NewMockAuthService(t), mockProfileService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:       "GET /users/{username}",
+				PathParameters: map[string]string{"username": "user0"},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestUsersLambdaHandler_FindAvatar(t *testing.T) {
+	mockProfileService := service.NewMockProfileService(t)
+	mockProfileService.On("FindAvatar", "user0").Return([]byte("image"), "image/png", nil)
+	handler := NewUsersLambdaHandler(service.NewMockUserService(t), service.NewMockAuthService(t), mockProfileService)
+
+	result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+		RouteKey:       "GET /users/{username}/avatar",
+		PathParameters: map[string]string{"username": "user0"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, events.APIGatewayV2HTTPResponse{
+		StatusCode:      http.StatusOK,
+		Body:            base64.StdEncoding.EncodeToString([]byte("image")),
+		IsBase64Encoded: true,
+		Headers:         map[string]string{"Content-Type": "image/png"},
+	}, result)
+}
+
+func TestUsersLambdaHandler_UpdateAvatar(t *testing.T) {
+	testCases := map[string]struct {
+		body               string
+		isBase64Encoded    bool
+		expectUpdate       bool
+		returnedError      error
+		expectedStatusCode int
+	}{
+		"Happy path": {
+			body:               base64.StdEncoding.EncodeToString([]byte("image")),
+			isBase64Encoded:    true,
+			expectUpdate:       true,
+			expectedStatusCode: http.StatusOK,
+		},
+		"Not an image": {
+			body:               base64.StdEncoding.EncodeToString([]byte("image")),
+			isBase64Encoded:    true,
+			expectUpdate:       true,
+			returnedError:      apperrors.NewInvalidAvatarError("the image must be a PNG, JPEG, GIF or WebP"),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		"Invalid base64": {
+			body:               "not base64!",
+			isBase64Encoded:    true,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mockUserService := service.NewMockUserService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			mockProfileService := service.NewMockProfileService(t)
+			mockAuthService.On("ValidateToken", "token").Return("0", nil)
+			if tc.expectUpdate {
+				existingUser := model.User{Id: "0", Username: "user0", Version: 3}
+				mockUserService.On("FindUser", "0").Return(&existingUser, nil)
+				var returnedUser *model.User
+				if tc.returnedError == nil {
+					returnedUser = &model.User{Id: "0", Username: "user0", Avatar: "avatars/0/avatar.png", Version: 4}
+				}
+				mockProfileService.On("UpdateAvatar", existingUser, 3, []byte("image")).Return(returnedUser, tc.returnedError)
+			}
+			handler := NewUsersLambdaHandler(mockUserService, mockAuthService, mockProfileService)
+
+			result, err := handler.RouteRequest(events.APIGatewayV2HTTPRequest{
+				RouteKey:        "PUT /user/avatar",
+				Headers:         map[string]string{"Token": "token"},
+				Body:            tc.body,
+				IsBase64Encoded: tc.isBase64Encoded,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatusCode, result.StatusCode)
+		})
+	}
+}
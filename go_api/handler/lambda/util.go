@@ -2,8 +2,12 @@ package lambda
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 
+	"github.com/aws/aws-lambda-go/events"
 	jsoniter "github.com/json-iterator/go"
+	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/service"
 )
 
@@ -34,3 +38,16 @@ func messageToResponseBody(message string) string {
 	body, _ := jsoniter.MarshalToString(m)
 	return body
 }
+
+// errorResponse maps the error to its status code and a message that is safe to return to the client;
+// unexpected errors are logged since their details aren't included in the response
+func errorResponse(err error) events.APIGatewayV2HTTPResponse {
+	statusCode, message := apperrors.StatusCode(err)
+	if statusCode == http.StatusInternalServerError {
+		fmt.Printf("unexpected error: %v\n", err)
+	}
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: statusCode,
+		Body:       messageToResponseBody(message),
+	}
+}
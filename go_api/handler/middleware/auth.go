@@ -30,6 +30,16 @@ func (m AuthMiddleware) AuthUser(c *gin.Context) {
 	c.Next()
 }
 
+// OptionalAuthUser adds the userId to the request context like AuthUser if the Token header is included,
+// for endpoints that don't require a token; an invalid token is still rejected
+func (m AuthMiddleware) OptionalAuthUser(c *gin.Context) {
+	if len(c.Request.Header["Token"]) == 0 {
+		c.Next()
+		return
+	}
+	m.AuthUser(c)
+}
+
 func NewAuthMiddleware(authService service.AuthService) AuthMiddleware {
 	return AuthMiddleware{
 		authService: authService,
@@ -79,3 +79,57 @@ func TestAuthUser(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionalAuthUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := []struct {
+		testName           string
+		userId             string
+		token              string
+		authError          error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully retrieved user id",
+			userId:             "0",
+			token:              "testToken",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "No token in request headers",
+			userId:             "",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Invalid token",
+			userId:             "0",
+			token:              "testToken",
+			authError:          fmt.Errorf("invalid token"),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockAuthService := service.NewMockAuthService(t)
+			if d.token != "" {
+				mockAuthService.On("ValidateToken", d.token).Return(d.userId, d.authError)
+			}
+			authMiddleware := NewAuthMiddleware(mockAuthService)
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/drink", nil)
+			assert.NoError(t, err)
+			if d.token != "" {
+				request.Header["Token"] = []string{d.token}
+			}
+
+			router := gin.Default()
+			router.GET("/drink", authMiddleware.OptionalAuthUser, checkIfUserIdContextVarIsSet(t, d.userId))
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			mockAuthService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DrinkHandler struct {
+	Service       service.DrinkService
+	SearchService service.DrinkSearchService
+	// UserService finds the units that the token's user prefers for recipes
+	UserService service.UserService
+}
+
+func (dh *DrinkHandler) FindAllDrinks(c *gin.Context) {
+	drinks, err := dh.Service.FindAllDrinks()
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewDrinksResponse(drinks))
+}
+
+// FindDrinkById renders the drink's recipe for the `servings` and in the `units` query parameters;
+// without `units`, the recipe is rendered in the units that the token's user prefers
+func (dh *DrinkHandler) FindDrinkById(c *gin.Context) {
+	drinkId := c.Param("drinkId")
+	format, err := dto.NewRecipeFormat(c.Query("servings"), c.Query("units"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if userId := c.GetString("userId"); userId != "" && format.UserUnits {
+		user, err := dh.UserService.FindUser(userId)
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		if user != nil {
+			format = format.WithUser(*user)
+		}
+	}
+
+	drink, err := dh.Service.FindDrinkById(drinkId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if drink == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no drink was found with id %s", drinkId)})
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewFormattedDrinkResponse(*drink, format))
+}
+
+func (dh *DrinkHandler) SearchDrinks(c *gin.Context) {
+	// repeated parameters are joined the same way that API Gateway joins them for the lambdas
+	params := map[string]string{}
+	for name, values := range c.Request.URL.Query() {
+		params[name] = strings.Join(values, ",")
+	}
+	query, err := dto.NewDrinkSearchQuery(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	result, err := dh.SearchService.SearchDrinks(query)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewDrinkSearchResponse(query, result))
+}
+
+// FindMakeableDrinks finds the drinks that can be made with the ingredients in the body;
+// a token is only needed to restrict the drinks to the user's favorites
+func (dh *DrinkHandler) FindMakeableDrinks(c *gin.Context) {
+	var makeableRequest dto.MakeableDrinksRequest
+	if err := c.BindJSON(&makeableRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the list of ingredients in the body of your request"})
+		return
+	}
+	if err := makeableRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	favoritesOf := ""
+	if makeableRequest.FavoritesOnly {
+		favoritesOf = c.GetString("userId")
+		if favoritesOf == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "the 'Token' header is required to restrict the drinks to favorites"})
+			return
+		}
+	}
+	result, err := dh.SearchService.FindMakeableDrinks(makeableRequest.ToQuery(), favoritesOf)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewMakeableDrinksResponse(result))
+}
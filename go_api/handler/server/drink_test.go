@@ -0,0 +1,325 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/search"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAllDrinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDrinks := []model.Drink{
+		{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "1 1/2 oz"}}},
+		{Id: "11008", Name: "Water"},
+	}
+	data := []struct {
+		testName           string
+		returnedDrinks     []model.Drink
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully retrieve drinks",
+			returnedDrinks:     mockDrinks,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Failed to retrieve drinks",
+			returnedError:      fmt.Errorf("failed to retrieve drinks"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "Database unavailable",
+			returnedError:      apperrors.NewUnavailableError("unavailable", errors.New("testing")),
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockDrinkService := service.NewMockDrinkService(t)
+			mockDrinkService.On("FindAllDrinks").Return(d.returnedDrinks, d.returnedError)
+			drinkHandler := DrinkHandler{Service: mockDrinkService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/drink", nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/drink", drinkHandler.FindAllDrinks)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.returnedError == nil {
+				expectedResponseBody, err := json.Marshal(dto.NewDrinksResponse(d.returnedDrinks))
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestFindDrinkById(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDrink := &model.Drink{
+		Id:          "11007",
+		Name:        "Margarita",
+		Glass:       "Cocktail glass",
+		Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "1 1/2 oz"}, {Name: "Salt"}},
+		Alcoholic:   true,
+	}
+	data := []struct {
+		testName           string
+		url                string
+		userId             string
+		returnedUser       *model.User
+		returnedUserError  error
+		expectFindDrink    bool
+		returnedDrink      *model.Drink
+		returnedError      error
+		expectedStatusCode int
+		expectedMeasure    string
+	}{
+		{
+			testName:           "Successfully retrieve drink",
+			url:                "/drink/11007",
+			expectFindDrink:    true,
+			returnedDrink:      mockDrink,
+			expectedStatusCode: http.StatusOK,
+			expectedMeasure:    "1 1/2 oz",
+		},
+		{
+			testName:           "Successfully retrieve drink for servings in units",
+			url:                "/drink/11007?servings=2&units=metric",
+			userId:             "user0",
+			expectFindDrink:    true,
+			returnedDrink:      mockDrink,
+			expectedStatusCode: http.StatusOK,
+			expectedMeasure:    "90 ml",
+		},
+		{
+			testName:           "Successfully retrieve drink in the user's units",
+			url:                "/drink/11007",
+			userId:             "user0",
+			returnedUser:       &model.User{Id: "user0", Units: "metric"},
+			expectFindDrink:    true,
+			returnedDrink:      mockDrink,
+			expectedStatusCode: http.StatusOK,
+			expectedMeasure:    "45 ml",
+		},
+		{
+			testName:           "Failed to retrieve the user's units",
+			url:                "/drink/11007",
+			userId:             "user0",
+			returnedUserError:  fmt.Errorf("failed to retrieve user"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "Invalid servings",
+			url:                "/drink/11007?servings=0",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Failed to retrieve drink",
+			url:                "/drink/11007",
+			expectFindDrink:    true,
+			returnedError:      fmt.Errorf("failed to retrieve drink"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "Drink isn't in the catalog",
+			url:                "/drink/11007",
+			expectFindDrink:    true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockDrinkService := service.NewMockDrinkService(t)
+			if d.expectFindDrink {
+				mockDrinkService.On("FindDrinkById", "11007").Return(d.returnedDrink, d.returnedError)
+			}
+			mockUserService := service.NewMockUserService(t)
+			if d.returnedUser != nil || d.returnedUserError != nil {
+				mockUserService.On("FindUser", d.userId).Return(d.returnedUser, d.returnedUserError)
+			}
+			drinkHandler := DrinkHandler{Service: mockDrinkService, UserService: mockUserService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, d.url, nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			if d.userId != "" {
+				router.Use(setUserIdInContext(d.userId))
+			}
+			router.GET("/drink/:drinkId", drinkHandler.FindDrinkById)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				var response dto.DrinkResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, d.returnedDrink.Name, response.Name)
+				assert.Equal(t, d.expectedMeasure, response.Ingredients[0].Measure)
+			}
+		})
+	}
+}
+
+func TestSearchDrinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	result := search.Result{
+		Total: 1,
+		Hits:  []search.Hit{{Drink: model.Drink{Id: "11007", Name: "Margarita"}, Score: 1.5, Popularity: 3}},
+	}
+	data := []struct {
+		testName           string
+		url                string
+		expectedQuery      *search.Query
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName: "Successfully search drinks",
+			url:      "/drink/search?q=margarita&ingredient=Tequila&ingredient=Lime+juice&limit=5",
+			expectedQuery: &search.Query{
+				Text:        "margarita",
+				Ingredients: []string{"Tequila", "Lime juice"},
+				Sort:        search.SortRelevance,
+				Limit:       5,
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Failed to search drinks",
+			url:                "/drink/search",
+			expectedQuery:      &search.Query{Sort: search.SortRelevance, Limit: 20},
+			returnedError:      fmt.Errorf("failed to search drinks"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "Invalid query",
+			url:                "/drink/search?sort=name",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockDrinkService := service.NewMockDrinkService(t)
+			mockSearchService := service.NewMockDrinkSearchService(t)
+			if d.expectedQuery != nil {
+				mockSearchService.On("SearchDrinks", *d.expectedQuery).Return(result, d.returnedError)
+			}
+			drinkHandler := DrinkHandler{Service: mockDrinkService, SearchService: mockSearchService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, d.url, nil)
+			assert.NoError(t, err)
+
+			// the search route is registered alongside the drink id route, like the api does
+			router := gin.Default()
+			router.GET("/drink/search", drinkHandler.SearchDrinks)
+			router.GET("/drink/:drinkId", drinkHandler.FindDrinkById)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				expectedResponseBody, err := json.Marshal(dto.NewDrinkSearchResponse(*d.expectedQuery, result))
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestFindMakeableDrinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	result := search.MakeableResult{
+		Makeable:   []search.MakeableHit{{Drink: model.Drink{Id: "11007", Name: "Margarita"}, Missing: []string{}}},
+		MissingOne: []search.MakeableHit{},
+		MissingTwo: []search.MakeableHit{},
+	}
+	query := search.MakeableQuery{Ingredients: []string{"Tequila", "Lime juice", "Triple sec"}, MaxMissing: 1, Limit: 20}
+	data := []struct {
+		testName            string
+		requestBody         string
+		userId              string
+		expectSearch        bool
+		expectedFavoritesOf string
+		returnedError       error
+		expectedStatusCode  int
+	}{
+		{
+			testName:           "Successfully find makeable drinks",
+			requestBody:        `{"ingredients": ["Tequila", "Lime juice", "Triple sec"], "maxMissing": 1}`,
+			expectSearch:       true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:            "Successfully find makeable favorites",
+			requestBody:         `{"ingredients": ["Tequila", "Lime juice", "Triple sec"], "maxMissing": 1, "favoritesOnly": true}`,
+			userId:              "0",
+			expectSearch:        true,
+			expectedFavoritesOf: "0",
+			expectedStatusCode:  http.StatusOK,
+		},
+		{
+			testName:           "Favorites without a token",
+			requestBody:        `{"ingredients": ["Tequila"], "favoritesOnly": true}`,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			testName:           "Too many missing ingredients",
+			requestBody:        `{"ingredients": ["Tequila"], "maxMissing": 3}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Failed to find makeable drinks",
+			requestBody:        `{"ingredients": ["Tequila", "Lime juice", "Triple sec"], "maxMissing": 1}`,
+			expectSearch:       true,
+			returnedError:      fmt.Errorf("failed to load drinks"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockSearchService := service.NewMockDrinkSearchService(t)
+			if d.expectSearch {
+				mockSearchService.On("FindMakeableDrinks", query, d.expectedFavoritesOf).Return(result, d.returnedError)
+			}
+			drinkHandler := DrinkHandler{Service: service.NewMockDrinkService(t), SearchService: mockSearchService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/drink/makeable", strings.NewReader(d.requestBody))
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.POST("/drink/makeable", setUserIdInContext(d.userId), drinkHandler.FindMakeableDrinks)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				expectedResponseBody, err := json.Marshal(dto.NewMakeableDrinksResponse(result))
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"net/http"
+
+	"the-drink-almanac-api/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondWithError maps the error to its status code and a message that is safe to return to the client;
+// unexpected errors are attached to the context so that they are still logged
+func respondWithError(c *gin.Context, err error) {
+	statusCode, message := apperrors.StatusCode(err)
+	if statusCode == http.StatusInternalServerError {
+		_ = c.Error(err)
+	}
+	c.JSON(statusCode, gin.H{"message": message})
+}
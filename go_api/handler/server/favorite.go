@@ -8,6 +8,7 @@ import (
 
 	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/service"
 
 	"github.com/gin-gonic/gin"
@@ -15,12 +16,15 @@ import (
 
 type FavoriteHandler struct {
 	Service service.FavoriteService
+	// DrinkResolver provides the drinks and the user's recipes embedded by `expand=drink`
+	DrinkResolver service.DrinkResolver
 }
 
 func (fh *FavoriteHandler) FindAllFavorites(c *gin.Context) {
 	favorites, err := fh.Service.FindAllFavorites()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		respondWithError(c, err)
+		return
 	}
 	favoritesResponse := dto.NewFavoritesResponse(favorites)
 	c.JSON(http.StatusOK, favoritesResponse)
@@ -28,19 +32,61 @@ func (fh *FavoriteHandler) FindAllFavorites(c *gin.Context) {
 
 func (fh *FavoriteHandler) FindFavoritesByUser(c *gin.Context) {
 	userId := c.GetString("userId")
-	favorites, err := fh.Service.FindFavoritesByUser(userId)
+	sort, err := dto.NewFavoriteSort(c.Query("sort"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	expansion, err := dto.NewFavoriteExpansion(c.Query("expand"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var favorites []model.Favorite
+	if sort.ByCreation {
+		favorites, err = fh.Service.FindFavoritesByUserSortedByCreation(userId, sort.NewestFirst)
+	} else {
+		favorites, err = fh.Service.FindFavoritesByUser(userId)
+	}
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 	if len(favorites) == 0 {
 		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no favorites were found for user with id %s", userId)})
 		return
 	}
+	if expansion.Drink {
+		drinks, err := fh.DrinkResolver.FindDrinks(userId, dto.FavoriteDrinkIds(favorites))
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewExpandedFavoritesResponse(favorites, drinks))
+		return
+	}
 	favoritesResponse := dto.NewFavoritesResponse(favorites)
 	c.JSON(http.StatusOK, favoritesResponse)
 }
 
+func (fh *FavoriteHandler) FindFavoriteByUserAndDrink(c *gin.Context) {
+	userId := c.GetString("userId")
+	drinkId := c.Param("drinkId")
+	favorite, err := fh.Service.FindFavoriteByUserAndDrink(userId, drinkId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if favorite == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no favorite was found for user with id %s and drink with id %s", userId, drinkId)})
+		return
+	}
+	favoriteResponse := dto.NewFavoriteResponse(*favorite)
+	c.Header("ETag", dto.NewETag(favorite.Version))
+	c.JSON(http.StatusOK, favoriteResponse)
+}
+
 func (fh *FavoriteHandler) CreateNewFavorite(c *gin.Context) {
 	var newFavoritePostRequest dto.FavoritePostRequest
 	if err := c.BindJSON(&newFavoritePostRequest); err != nil {
@@ -65,10 +111,16 @@ func (fh *FavoriteHandler) CreateNewFavorite(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("unable to add the new favorite due to %s", err.Error())})
+		// the request is well-formed, but the drink it refers to doesn't exist
+		if errors.As(err, &apperrors.DrinkNotFoundError{}) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return
+		}
+		respondWithError(c, err)
 		return
 	}
 	favoriteResponse := dto.NewFavoriteResponse(*newFavorite)
+	c.Header("ETag", dto.NewETag(newFavorite.Version))
 	c.JSON(http.StatusCreated, favoriteResponse)
 }
 
@@ -76,7 +128,7 @@ func (fh *FavoriteHandler) DeleteFavorite(c *gin.Context) {
 	favoriteId := c.Param("favoriteId")
 	err := fh.Service.DeleteFavorite(favoriteId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/dto"
@@ -54,6 +55,12 @@ func TestFindAllFavorites(t *testing.T) {
 			returnedError:      fmt.Errorf("failed to retrieve favorites"),
 			expectedStatusCode: http.StatusInternalServerError,
 		},
+		{
+			testName:           "Database unavailable",
+			returnedFavorites:  nil,
+			returnedError:      apperrors.NewUnavailableError("unavailable", fmt.Errorf("throttled")),
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
 	}
 
 	for _, d := range data {
@@ -156,6 +163,237 @@ func TestFindFavoritesByUser(t *testing.T) {
 	}
 }
 
+func TestFindFavoritesByUser_ExpandDrink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	favorites := []model.Favorite{
+		{Id: "0", DrinkId: "11007", UserId: "0"},
+		{Id: "1", DrinkId: "banana", UserId: "0"},
+		{Id: "2", DrinkId: "11007", UserId: "0"},
+		{Id: "3", DrinkId: "recipe-0", UserId: "0"},
+	}
+	drinks := map[string]model.Drink{
+		"11007":    {Id: "11007", Name: "Margarita", Category: "Ordinary Drink", ImageUrl: "https://www.thecocktaildb.com/images/media/drink/11007.jpg"},
+		"recipe-0": {Id: "recipe-0", Name: "House Sour", Category: "Cocktail"},
+	}
+	data := []struct {
+		testName           string
+		expand             string
+		returnedDrinks     map[string]model.Drink
+		returnedError      error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			testName:           "Embeds the drinks and recipes",
+			expand:             "drink",
+			returnedDrinks:     drinks,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `[` +
+				`{"id":"0","drinkId":"11007","drink":{"id":"11007","name":"Margarita","category":"Ordinary Drink","imageUrl":"https://www.thecocktaildb.com/images/media/drink/11007.jpg"}},` +
+				`{"id":"1","drinkId":"banana","drink":null},` +
+				`{"id":"2","drinkId":"11007","drink":{"id":"11007","name":"Margarita","category":"Ordinary Drink","imageUrl":"https://www.thecocktaildb.com/images/media/drink/11007.jpg"}},` +
+				`{"id":"3","drinkId":"recipe-0","drink":{"id":"recipe-0","name":"House Sour","category":"Cocktail"}}` +
+				`]`,
+		},
+		{
+			testName:           "Failed to retrieve the drinks",
+			expand:             "drink",
+			returnedError:      fmt.Errorf("failed to retrieve the drinks"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "Invalid expand",
+			expand:             "drink,user",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"invalid expand 'user'; the only expansion is 'drink'"}`,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockFavoriteService := service.NewMockFavoriteService(t)
+			mockDrinkResolver := service.NewMockDrinkResolver(t)
+			if d.expectedStatusCode != http.StatusBadRequest {
+				mockFavoriteService.On("FindFavoritesByUser", "0").Return(favorites, nil)
+				mockDrinkResolver.On("FindDrinks", "0", []string{"11007", "banana", "11007", "recipe-0"}).Return(d.returnedDrinks, d.returnedError)
+			}
+			favoriteHandler := FavoriteHandler{Service: mockFavoriteService, DrinkResolver: mockDrinkResolver}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/favorite?expand="+d.expand, nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/favorite", setUserIdInContext("0"), favoriteHandler.FindFavoritesByUser)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedBody != "" {
+				assert.JSONEq(t, d.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestFindFavoritesByUser_Sorted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockFavorites := []model.Favorite{
+		{
+			Id:        "1",
+			DrinkId:   "1",
+			UserId:    "0",
+			CreatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Id:        "0",
+			DrinkId:   "0",
+			UserId:    "0",
+			CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	data := []struct {
+		testName             string
+		sort                 string
+		newestFirst          bool
+		shouldMethodBeCalled bool
+		returnedFavorites    []model.Favorite
+		returnedError        error
+		expectedStatusCode   int
+	}{
+		{
+			testName:             "Successfully retrieve favorites newest first",
+			sort:                 "-created_at",
+			newestFirst:          true,
+			shouldMethodBeCalled: true,
+			returnedFavorites:    mockFavorites,
+			returnedError:        nil,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			testName:             "Successfully retrieve favorites oldest first",
+			sort:                 "created_at",
+			newestFirst:          false,
+			shouldMethodBeCalled: true,
+			returnedFavorites:    mockFavorites,
+			returnedError:        nil,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			testName:             "Failed to retrieve favorites",
+			sort:                 "created_at",
+			newestFirst:          false,
+			shouldMethodBeCalled: true,
+			returnedFavorites:    nil,
+			returnedError:        fmt.Errorf("failed to retrieve favorites"),
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+		{
+			testName:             "Invalid sort",
+			sort:                 "drink_id",
+			shouldMethodBeCalled: false,
+			returnedFavorites:    nil,
+			returnedError:        nil,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockFavoriteService := service.NewMockFavoriteService(t)
+			if d.shouldMethodBeCalled {
+				mockFavoriteService.On("FindFavoritesByUserSortedByCreation", "0", d.newestFirst).Return(d.returnedFavorites, d.returnedError)
+			}
+			favoriteHandler := FavoriteHandler{Service: mockFavoriteService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/favorite?sort=%s", d.sort), nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/favorite", setUserIdInContext("0"), favoriteHandler.FindFavoritesByUser)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			mockFavoriteService.AssertExpectations(t)
+
+			if d.returnedFavorites != nil {
+				favoritesResponse := dto.NewFavoritesResponse(d.returnedFavorites)
+				expectedResponseBody, err := json.Marshal(favoritesResponse)
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestFindFavoriteByUserAndDrink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockFavorite := &model.Favorite{
+		Id:      "0",
+		DrinkId: "0",
+		UserId:  "0",
+	}
+	data := []struct {
+		testName           string
+		userId             string
+		drinkId            string
+		returnedFavorite   *model.Favorite
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully retrieve favorite",
+			userId:             "0",
+			drinkId:            "0",
+			returnedFavorite:   mockFavorite,
+			returnedError:      nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Failed to retrieve favorite",
+			userId:             "0",
+			drinkId:            "0",
+			returnedFavorite:   nil,
+			returnedError:      fmt.Errorf("failed to retrieve favorite"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "User hasn't favorited the drink",
+			userId:             "0",
+			drinkId:            "1",
+			returnedFavorite:   nil,
+			returnedError:      nil,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockFavoriteService := service.NewMockFavoriteService(t)
+			mockFavoriteService.On("FindFavoriteByUserAndDrink", d.userId, d.drinkId).Return(d.returnedFavorite, d.returnedError)
+			favoriteHandler := FavoriteHandler{Service: mockFavoriteService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/favorite/drink/%s", d.drinkId), nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/favorite/drink/:drinkId", setUserIdInContext(d.userId), favoriteHandler.FindFavoriteByUserAndDrink)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			mockFavoriteService.AssertExpectations(t)
+
+			if d.returnedFavorite != nil {
+				favoriteResponse := dto.NewFavoriteResponse(*d.returnedFavorite)
+				expectedResponseBody, err := json.Marshal(favoriteResponse)
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
+
 func TestCreateNewFavorite(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockFavorite := &model.Favorite{
@@ -203,6 +441,16 @@ func TestCreateNewFavorite(t *testing.T) {
 			expectedStatusCode:   http.StatusConflict,
 			shouldMethodBeCalled: true,
 		},
+		{
+			testName:             "Drink doesn't exist",
+			userId:               "0",
+			drinkId:              "banana",
+			requestBody:          []byte(`{"drink_id": "banana"}`),
+			returnedFavorite:     nil,
+			returnedError:        apperrors.NewDrinkNotFoundError("banana"),
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			shouldMethodBeCalled: true,
+		},
 		{
 			testName:             "No request body",
 			userId:               "",
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	Service service.InventoryService
+}
+
+func (ih *InventoryHandler) FindInventory(c *gin.Context) {
+	userId := c.GetString("userId")
+	items, err := ih.Service.FindInventory(userId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewInventoryResponse(items))
+}
+
+func (ih *InventoryHandler) FindInventoryItem(c *gin.Context) {
+	userId := c.GetString("userId")
+	ingredient := c.Param("ingredient")
+	item, err := ih.Service.FindInventoryItem(userId, ingredient)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if item == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("the ingredient '%s' isn't in the inventory", ingredient)})
+		return
+	}
+	c.Header("ETag", dto.NewETag(item.Version))
+	c.JSON(http.StatusOK, dto.NewInventoryItemResponse(*item))
+}
+
+func (ih *InventoryHandler) AddInventoryItem(c *gin.Context) {
+	var itemRequest dto.InventoryItemRequest
+	if err := c.BindJSON(&itemRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the name and quantity of the ingredient in the body of your request"})
+		return
+	}
+	if err := itemRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	userId := c.GetString("userId")
+	item, err := ih.Service.AddInventoryItem(userId, itemRequest.ToInventoryItem())
+	if err != nil {
+		if errors.As(err, &apperrors.InventoryItemAlreadyExistsError{}) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", dto.NewETag(item.Version))
+	c.JSON(http.StatusCreated, dto.NewInventoryItemResponse(*item))
+}
+
+// UpdateInventoryItem replaces the item's quantity, unit and expiry; with an If-Match header,
+// the update is rejected with 412 if the item changed since the client read it
+func (ih *InventoryHandler) UpdateInventoryItem(c *gin.Context) {
+	expectedVersion, conditional, err := dto.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	var itemRequest dto.InventoryItemRequest
+	if err := c.BindJSON(&itemRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the name and quantity of the ingredient in the body of your request"})
+		return
+	}
+	if err := itemRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	ingredient := c.Param("ingredient")
+	if model.IngredientKey(itemRequest.Name) != model.IngredientKey(ingredient) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("the name '%s' doesn't match the ingredient '%s'", itemRequest.Name, ingredient)})
+		return
+	}
+
+	userId := c.GetString("userId")
+	existingItem, err := ih.Service.FindInventoryItem(userId, ingredient)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if existingItem == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("the ingredient '%s' isn't in the inventory", ingredient)})
+		return
+	}
+	if !conditional {
+		expectedVersion = existingItem.Version
+	}
+
+	item := itemRequest.ToInventoryItem()
+	item.UserId = userId
+	item.CreatedAt = existingItem.CreatedAt
+	updatedItem, err := ih.Service.UpdateInventoryItem(item, expectedVersion)
+	if err != nil {
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"message": "the ingredient was modified since it was read"})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", dto.NewETag(updatedItem.Version))
+	c.JSON(http.StatusOK, dto.NewInventoryItemResponse(*updatedItem))
+}
+
+func (ih *InventoryHandler) DeleteInventoryItem(c *gin.Context) {
+	userId := c.GetString("userId")
+	err := ih.Service.DeleteInventoryItem(userId, c.Param("ingredient"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{"message": "the ingredient was removed from the inventory"})
+}
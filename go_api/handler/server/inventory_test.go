@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddInventoryItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := []struct {
+		testName           string
+		requestBody        string
+		expectAdd          bool
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully add an ingredient",
+			requestBody:        `{"name": "Gin", "quantity": 700, "unit": "ml", "expiresOn": "2024-06-30"}`,
+			expectAdd:          true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			testName:           "Ingredient already in the inventory",
+			requestBody:        `{"name": "Gin", "quantity": 700}`,
+			expectAdd:          true,
+			returnedError:      apperrors.NewInventoryItemAlreadyExistsError("gin"),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			testName:           "Missing quantity",
+			requestBody:        `{"name": "Gin"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Invalid body",
+			requestBody:        `{"name": 0}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockInventoryService := service.NewMockInventoryService(t)
+			if d.expectAdd {
+				var returnedItem *model.InventoryItem
+				if d.returnedError == nil {
+					returnedItem = &model.InventoryItem{UserId: "0", Ingredient: "gin", Name: "Gin", Quantity: 700, Version: 1}
+				}
+				mockInventoryService.On("AddInventoryItem", "0", mock.MatchedBy(func(item model.InventoryItem) bool {
+					return item.Name == "Gin" && item.Quantity == 700
+				})).Return(returnedItem, d.returnedError)
+			}
+			inventoryHandler := InventoryHandler{Service: mockInventoryService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/inventory", bytes.NewBufferString(d.requestBody))
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.POST("/inventory", setUserIdInContext("0"), inventoryHandler.AddInventoryItem)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusCreated {
+				assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+			}
+			mockInventoryService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateInventoryItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	existingItem := &model.InventoryItem{UserId: "0", Ingredient: "lime juice", Name: "Lime Juice", Quantity: 1, Version: 3}
+	data := []struct {
+		testName           string
+		path               string
+		ifMatch            string
+		requestBody        string
+		existingItem       *model.InventoryItem
+		expectFind         bool
+		expectUpdate       bool
+		expectedVersion    int
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully update an ingredient",
+			path:               "/inventory/lime%20juice",
+			requestBody:        `{"name": "lime juice", "quantity": 0.5, "unit": "l"}`,
+			existingItem:       existingItem,
+			expectFind:         true,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Successfully update an ingredient with If-Match",
+			path:               "/inventory/lime%20juice",
+			ifMatch:            `"3"`,
+			requestBody:        `{"name": "Lime Juice", "quantity": 0.5}`,
+			existingItem:       existingItem,
+			expectFind:         true,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Stale If-Match",
+			path:               "/inventory/lime%20juice",
+			ifMatch:            `"2"`,
+			requestBody:        `{"name": "Lime Juice", "quantity": 0.5}`,
+			existingItem:       existingItem,
+			expectFind:         true,
+			expectUpdate:       true,
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the inventory item was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			testName:           "Concurrent update without If-Match",
+			path:               "/inventory/lime%20juice",
+			requestBody:        `{"name": "Lime Juice", "quantity": 0.5}`,
+			existingItem:       existingItem,
+			expectFind:         true,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewConflictError("the inventory item was modified", nil),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			testName:           "Ingredient isn't in the inventory",
+			path:               "/inventory/lime%20juice",
+			requestBody:        `{"name": "Lime Juice", "quantity": 0.5}`,
+			expectFind:         true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			testName:           "Name doesn't match the path",
+			path:               "/inventory/lime%20juice",
+			requestBody:        `{"name": "Lemon Juice", "quantity": 0.5}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Invalid If-Match",
+			path:               "/inventory/lime%20juice",
+			ifMatch:            "3",
+			requestBody:        `{"name": "Lime Juice", "quantity": 0.5}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockInventoryService := service.NewMockInventoryService(t)
+			if d.expectFind {
+				mockInventoryService.On("FindInventoryItem", "0", "lime juice").Return(d.existingItem, nil)
+			}
+			if d.expectUpdate {
+				var returnedItem *model.InventoryItem
+				if d.returnedError == nil {
+					returnedItem = &model.InventoryItem{UserId: "0", Ingredient: "lime juice", Name: "Lime Juice", Quantity: 0.5, Version: d.expectedVersion + 1}
+				}
+				mockInventoryService.On("UpdateInventoryItem", mock.MatchedBy(func(item model.InventoryItem) bool {
+					return item.UserId == "0" && item.Quantity == 0.5
+				}), d.expectedVersion).Return(returnedItem, d.returnedError)
+			}
+			inventoryHandler := InventoryHandler{Service: mockInventoryService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPut, d.path, bytes.NewBufferString(d.requestBody))
+			assert.NoError(t, err)
+			if d.ifMatch != "" {
+				request.Header.Set("If-Match", d.ifMatch)
+			}
+
+			router := gin.Default()
+			router.PUT("/inventory/:ingredient", setUserIdInContext("0"), inventoryHandler.UpdateInventoryItem)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, fmt.Sprintf(`"%d"`, d.expectedVersion+1), rr.Header().Get("ETag"))
+			}
+			mockInventoryService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFindInventoryItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockInventoryService := service.NewMockInventoryService(t)
+	mockInventoryService.On("FindInventoryItem", "0", "gin").Return(nil, nil)
+	inventoryHandler := InventoryHandler{Service: mockInventoryService}
+
+	rr := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/inventory/gin", nil)
+	assert.NoError(t, err)
+
+	router := gin.Default()
+	router.GET("/inventory/:ingredient", setUserIdInContext("0"), inventoryHandler.FindInventoryItem)
+	router.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	mockInventoryService.AssertExpectations(t)
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NutritionHandler struct {
+	Service service.NutritionService
+}
+
+// EstimateDrink estimates the strength, volume, standard drinks and calories of a serving of the drink;
+// with the favoriteId query parameter, it estimates the drink of that favorite of the user instead
+func (nh *NutritionHandler) EstimateDrink(c *gin.Context) {
+	userId := c.GetString("userId")
+	drinkId := c.Param("drinkId")
+	if favoriteId := c.Query("favoriteId"); favoriteId != "" {
+		nh.estimateFavorite(c, userId, drinkId, favoriteId)
+		return
+	}
+
+	estimate, err := nh.Service.EstimateDrink(userId, drinkId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if estimate == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no recipe was found for the drink with id %s", drinkId)})
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewNutritionResponse(*estimate))
+}
+
+func (nh *NutritionHandler) estimateFavorite(c *gin.Context, userId, drinkId, favoriteId string) {
+	if userId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "the 'Token' header is required to estimate a favorite"})
+		return
+	}
+	estimate, err := nh.Service.EstimateFavorite(userId, drinkId, favoriteId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if estimate == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no recipe was found for the favorite with id %s", favoriteId)})
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewFavoriteNutritionResponse(favoriteId, *estimate))
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/nutrition"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateDrink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	estimate := &nutrition.Estimate{DrinkId: "11007", DrinkName: "Margarita", Method: nutrition.Shaken, Volume: 154.53, ABV: 14.35}
+	data := []struct {
+		testName           string
+		userId             string
+		returnedEstimate   *nutrition.Estimate
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully estimate drink",
+			returnedEstimate:   estimate,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Successfully estimate drink as a user",
+			userId:             "0",
+			returnedEstimate:   estimate,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Failed to estimate drink",
+			returnedError:      fmt.Errorf("failed to retrieve drink"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "Drink doesn't have a recipe",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockNutritionService := service.NewMockNutritionService(t)
+			mockNutritionService.On("EstimateDrink", d.userId, "11007").Return(d.returnedEstimate, d.returnedError)
+			nutritionHandler := NutritionHandler{Service: mockNutritionService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/drink/11007/nutrition", nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/drink/:drinkId/nutrition", setUserIdInContext(d.userId), nutritionHandler.EstimateDrink)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.returnedEstimate != nil {
+				expectedResponseBody, err := json.Marshal(dto.NewNutritionResponse(*d.returnedEstimate))
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestEstimateDrink_Favorite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	estimate := &nutrition.Estimate{DrinkId: "recipe-0", DrinkName: "House Sour", Method: nutrition.Shaken, Volume: 154.53, ABV: 14.35}
+	data := []struct {
+		testName           string
+		userId             string
+		expectServiceCall  bool
+		returnedEstimate   *nutrition.Estimate
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully estimate favorite",
+			userId:             "0",
+			expectServiceCall:  true,
+			returnedEstimate:   estimate,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Failed to estimate favorite",
+			userId:             "0",
+			expectServiceCall:  true,
+			returnedError:      fmt.Errorf("failed to retrieve favorites"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "User doesn't have the favorite",
+			userId:             "0",
+			expectServiceCall:  true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			testName:           "Missing token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockNutritionService := service.NewMockNutritionService(t)
+			if d.expectServiceCall {
+				mockNutritionService.On("EstimateFavorite", "0", "recipe-0", "fav0").Return(d.returnedEstimate, d.returnedError)
+			}
+			nutritionHandler := NutritionHandler{Service: mockNutritionService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/drink/recipe-0/nutrition?favoriteId=fav0", nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/drink/:drinkId/nutrition", setUserIdInContext(d.userId), nutritionHandler.EstimateDrink)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.returnedEstimate != nil {
+				expectedResponseBody, err := json.Marshal(dto.NewFavoriteNutritionResponse("fav0", *d.returnedEstimate))
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
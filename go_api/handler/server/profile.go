@@ -0,0 +1,133 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	Service     service.ProfileService
+	UserService service.UserService
+}
+
+// FindProfile returns the user's public profile, which doesn't need a token
+func (ph *ProfileHandler) FindProfile(c *gin.Context) {
+	username := c.Param("username")
+	user, favorites, err := ph.Service.FindProfile(username)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no user was found with the username '%s'", username)})
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewProfileResponse(*user, favorites))
+}
+
+// FindAvatar returns the user's avatar image, which doesn't need a token
+func (ph *ProfileHandler) FindAvatar(c *gin.Context) {
+	username := c.Param("username")
+	image, contentType, err := ph.Service.FindAvatar(username)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if image == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("the user '%s' has no avatar", username)})
+		return
+	}
+	c.Data(http.StatusOK, contentType, image)
+}
+
+// UpdateAvatar saves the image in the body as the user's avatar;
+// the If-Match header makes the update conditional on the ETag returned by GET /user
+func (ph *ProfileHandler) UpdateAvatar(c *gin.Context) {
+	expectedVersion, conditional, err := dto.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	// one byte more than the limit is read, so that a larger image can be told apart from one that just fits
+	image, err := io.ReadAll(io.LimitReader(c.Request.Body, service.MaxAvatarSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the image in the body of your request"})
+		return
+	}
+	if len(image) > service.MaxAvatarSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": fmt.Sprintf("the avatar must be at most %d bytes", service.MaxAvatarSize)})
+		return
+	}
+
+	userId := c.GetString("userId")
+	user, err := ph.UserService.FindUser(userId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no user was found with the userId '%s'", userId)})
+		return
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	updatedUser, err := ph.Service.UpdateAvatar(*user, expectedVersion, image)
+	if err != nil {
+		if errors.As(err, &apperrors.InvalidAvatarError{}) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"message": "the user was modified since it was read"})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", dto.NewETag(updatedUser.Version))
+	c.JSON(http.StatusOK, dto.NewUserResponse(*updatedUser))
+}
+
+// DeleteAvatar removes the user's avatar; the If-Match header makes the delete conditional on the ETag returned by GET /user
+func (ph *ProfileHandler) DeleteAvatar(c *gin.Context) {
+	expectedVersion, conditional, err := dto.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	userId := c.GetString("userId")
+	user, err := ph.UserService.FindUser(userId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no user was found with the userId '%s'", userId)})
+		return
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	updatedUser, err := ph.Service.DeleteAvatar(*user, expectedVersion)
+	if err != nil {
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"message": "the user was modified since it was read"})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", dto.NewETag(updatedUser.Version))
+	c.JSON(http.StatusOK, dto.NewUserResponse(*updatedUser))
+}
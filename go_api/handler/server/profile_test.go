@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := []struct {
+		testName           string
+		returnedUser       *model.User
+		returnedFavorites  []model.Favorite
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			testName:           "Successfully find a profile with public favorites",
+			returnedUser:       &model.User{Id: "0", Username: "user0", Password: "hash", DisplayName: "User Zero", Avatar: "avatars/0/avatar.png", PublicFavorites: true, CreatedAt: createdAt},
+			returnedFavorites:  []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007", CreatedAt: createdAt}},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{"username":"user0","displayName":"User Zero","avatarUrl":"/users/user0/avatar","createdAt":"2023-01-01T00:00:00Z",` +
+				`"publicFavorites":true,"favorites":[{"drinkId":"11007","createdAt":"2023-01-01T00:00:00Z"}]}`,
+		},
+		{
+			testName:           "Successfully find a profile with private favorites",
+			returnedUser:       &model.User{Id: "0", Username: "user0", Password: "hash", Bio: "Mostly sours.", CreatedAt: createdAt},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"username":"user0","bio":"Mostly sours.","createdAt":"2023-01-01T00:00:00Z","publicFavorites":false}`,
+		},
+		{
+			testName:           "No user exists",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"no user was found with the username 'user0'"}`,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockProfileService := service.NewMockProfileService(t)
+			mockProfileService.On("FindProfile", "user0").Return(d.returnedUser, d.returnedFavorites, nil)
+			profileHandler := ProfileHandler{Service: mockProfileService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/users/user0", nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/users/:username", profileHandler.FindProfile)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			assert.JSONEq(t, d.expectedBody, rr.Body.String())
+		})
+	}
+}
+
+func TestFindAvatar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := []struct {
+		testName            string
+		returnedImage       []byte
+		returnedType        string
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{
+			testName:            "Successfully find an avatar",
+			returnedImage:       []byte("image"),
+			returnedType:        "image/png",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "image/png",
+		},
+		{
+			testName:            "User has no avatar",
+			expectedStatusCode:  http.StatusNotFound,
+			expectedContentType: "application/json; charset=utf-8",
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockProfileService := service.NewMockProfileService(t)
+			mockProfileService.On("FindAvatar", "user0").Return(d.returnedImage, d.returnedType, nil)
+			profileHandler := ProfileHandler{Service: mockProfileService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/users/user0/avatar", nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/users/:username/avatar", profileHandler.FindAvatar)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			assert.Equal(t, d.expectedContentType, rr.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestUpdateAvatar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	existingUser := &model.User{Id: "0", Username: "user0", Version: 3}
+	data := []struct {
+		testName           string
+		body               []byte
+		ifMatch            string
+		returnedUser       *model.User
+		expectUpdate       bool
+		expectedVersion    int
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully update the avatar",
+			body:               []byte("image"),
+			returnedUser:       existingUser,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Not an image",
+			body:               []byte("text"),
+			returnedUser:       existingUser,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewInvalidAvatarError("the image must be a PNG, JPEG, GIF or WebP"),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "User was modified since it was read",
+			body:               []byte("image"),
+			ifMatch:            `"2"`,
+			returnedUser:       existingUser,
+			expectUpdate:       true,
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the user was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			testName:           "Image is too large",
+			body:               make([]byte, service.MaxAvatarSize+1),
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			testName:           "No user exists",
+			body:               []byte("image"),
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockProfileService := service.NewMockProfileService(t)
+			mockUserService := service.NewMockUserService(t)
+			if d.expectedStatusCode != http.StatusRequestEntityTooLarge {
+				mockUserService.On("FindUser", "0").Return(d.returnedUser, nil)
+			}
+			if d.expectUpdate {
+				var updatedUser *model.User
+				if d.returnedError == nil {
+					updatedUser = &model.User{Id: "0", Username: "user0", Avatar: "avatars/0/avatar.png", Version: d.expectedVersion + 1}
+				}
+				mockProfileService.On("UpdateAvatar", *existingUser, d.expectedVersion, d.body).Return(updatedUser, d.returnedError)
+			}
+			profileHandler := ProfileHandler{Service: mockProfileService, UserService: mockUserService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPut, "/user/avatar", bytes.NewReader(d.body))
+			assert.NoError(t, err)
+			if d.ifMatch != "" {
+				request.Header.Set("If-Match", d.ifMatch)
+			}
+
+			router := gin.Default()
+			router.PUT("/user/avatar", setUserIdInContext("0"), profileHandler.UpdateAvatar)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestDeleteAvatar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	existingUser := &model.User{Id: "0", Username: "user0", Avatar: "avatars/0/avatar.png", Version: 3}
+	mockProfileService := service.NewMockProfileService(t)
+	mockUserService := service.NewMockUserService(t)
+	mockUserService.On("FindUser", "0").Return(existingUser, nil)
+	mockProfileService.On("DeleteAvatar", *existingUser, 3).Return(&model.User{Id: "0", Username: "user0", Version: 4}, nil)
+	profileHandler := ProfileHandler{Service: mockProfileService, UserService: mockUserService}
+
+	rr := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodDelete, "/user/avatar", nil)
+	assert.NoError(t, err)
+
+	router := gin.Default()
+	router.DELETE("/user/avatar", setUserIdInContext("0"), profileHandler.DeleteAvatar)
+	router.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecipeHandler struct {
+	Service service.RecipeService
+}
+
+// FindRecipes returns the user's own recipes, whatever their visibility
+func (rh *RecipeHandler) FindRecipes(c *gin.Context) {
+	userId := c.GetString("userId")
+	recipes, err := rh.Service.FindRecipesByOwner(userId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewRecipesResponse(recipes))
+}
+
+// FindRecipe returns the recipe if the user can see it; without a token, only unlisted and public recipes are found
+func (rh *RecipeHandler) FindRecipe(c *gin.Context) {
+	userId := c.GetString("userId")
+	recipeId := c.Param("recipeId")
+	recipe, err := rh.Service.FindRecipe(userId, recipeId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if recipe == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no recipe was found with id %s", recipeId)})
+		return
+	}
+	c.Header("ETag", dto.NewETag(recipe.Version))
+	c.JSON(http.StatusOK, dto.NewRecipeResponse(*recipe))
+}
+
+func (rh *RecipeHandler) CreateRecipe(c *gin.Context) {
+	var recipeRequest dto.RecipeRequest
+	if err := c.BindJSON(&recipeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the name and ingredients of the recipe in the body of your request"})
+		return
+	}
+	if err := recipeRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	userId := c.GetString("userId")
+	recipe, err := rh.Service.CreateRecipe(userId, recipeRequest.ToRecipe())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", dto.NewETag(recipe.Version))
+	c.JSON(http.StatusCreated, dto.NewRecipeResponse(*recipe))
+}
+
+// UpdateRecipe replaces the user's recipe; with an If-Match header,
+// the update is rejected with 412 if the recipe changed since the client read it
+func (rh *RecipeHandler) UpdateRecipe(c *gin.Context) {
+	expectedVersion, conditional, err := dto.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	var recipeRequest dto.RecipeRequest
+	if err := c.BindJSON(&recipeRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the name and ingredients of the recipe in the body of your request"})
+		return
+	}
+	if err := recipeRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	userId := c.GetString("userId")
+	recipeId := c.Param("recipeId")
+	if !conditional {
+		existingRecipe, err := rh.Service.FindRecipe(userId, recipeId)
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		if existingRecipe == nil {
+			c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no recipe was found with id %s", recipeId)})
+			return
+		}
+		expectedVersion = existingRecipe.Version
+	}
+
+	recipe := recipeRequest.ToRecipe()
+	recipe.Id = recipeId
+	updatedRecipe, err := rh.Service.UpdateRecipe(userId, recipe, expectedVersion)
+	if err != nil {
+		if errors.As(err, &apperrors.RecipeNotOwnedError{}) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"message": "the recipe was modified since it was read"})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	if updatedRecipe == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no recipe was found with id %s", recipeId)})
+		return
+	}
+	c.Header("ETag", dto.NewETag(updatedRecipe.Version))
+	c.JSON(http.StatusOK, dto.NewRecipeResponse(*updatedRecipe))
+}
+
+func (rh *RecipeHandler) DeleteRecipe(c *gin.Context) {
+	userId := c.GetString("userId")
+	err := rh.Service.DeleteRecipe(userId, c.Param("recipeId"))
+	if err != nil {
+		if errors.As(err, &apperrors.RecipeNotOwnedError{}) {
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{"message": "the recipe was deleted"})
+}
@@ -0,0 +1,283 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testRecipeBody = `{"name": "House Margarita", "ingredients": [{"name": "Tequila", "measure": "2 oz"}], "visibility": "public"}`
+
+func TestCreateRecipe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := []struct {
+		testName           string
+		requestBody        string
+		expectCreate       bool
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully create a recipe",
+			requestBody:        testRecipeBody,
+			expectCreate:       true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			testName:           "Missing ingredients",
+			requestBody:        `{"name": "House Margarita"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Invalid visibility",
+			requestBody:        `{"name": "House Margarita", "ingredients": [{"name": "Tequila"}], "visibility": "friends"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Invalid body",
+			requestBody:        `{"name": 0}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			if d.expectCreate {
+				mockRecipeService.On("CreateRecipe", "0", mock.MatchedBy(func(recipe model.Recipe) bool {
+					return recipe.Name == "House Margarita" && recipe.Visibility == model.RecipePublic && len(recipe.Ingredients) == 1
+				})).Return(&model.Recipe{Id: "recipe-0", OwnerId: "0", Name: "House Margarita", Visibility: model.RecipePublic, Version: 1}, nil)
+			}
+			recipeHandler := RecipeHandler{Service: mockRecipeService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/recipe", bytes.NewBufferString(d.requestBody))
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.POST("/recipe", setUserIdInContext("0"), recipeHandler.CreateRecipe)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusCreated {
+				assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+			}
+			mockRecipeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFindRecipe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := []struct {
+		testName           string
+		returnedRecipe     *model.Recipe
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully find a recipe",
+			returnedRecipe:     &model.Recipe{Id: "recipe-0", OwnerId: "1", Name: "House Margarita", Visibility: model.RecipeUnlisted, Version: 2},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Recipe doesn't exist or isn't visible",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			mockRecipeService.On("FindRecipe", "0", "recipe-0").Return(d.returnedRecipe, nil)
+			recipeHandler := RecipeHandler{Service: mockRecipeService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/recipe/recipe-0", nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.GET("/recipe/:recipeId", setUserIdInContext("0"), recipeHandler.FindRecipe)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+			}
+			mockRecipeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateRecipe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ownRecipe := &model.Recipe{Id: "recipe-0", OwnerId: "0", Name: "Margarita", Visibility: model.RecipePrivate, Version: 3}
+	otherUsersRecipe := &model.Recipe{Id: "recipe-0", OwnerId: "1", Name: "Margarita", Visibility: model.RecipePublic, Version: 3}
+	data := []struct {
+		testName           string
+		ifMatch            string
+		requestBody        string
+		existingRecipe     *model.Recipe
+		expectFind         bool
+		expectUpdate       bool
+		expectedVersion    int
+		recipeMissing      bool
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully update a recipe",
+			requestBody:        testRecipeBody,
+			existingRecipe:     ownRecipe,
+			expectFind:         true,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Stale If-Match",
+			ifMatch:            `"2"`,
+			requestBody:        testRecipeBody,
+			expectUpdate:       true,
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the recipe was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			testName:           "Concurrent update without If-Match",
+			requestBody:        testRecipeBody,
+			existingRecipe:     ownRecipe,
+			expectFind:         true,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewConflictError("the recipe was modified", nil),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			testName:           "Another user's recipe",
+			requestBody:        testRecipeBody,
+			existingRecipe:     otherUsersRecipe,
+			expectFind:         true,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewRecipeNotOwnedError("recipe-0"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			testName:           "Another user's recipe with If-Match",
+			ifMatch:            `"3"`,
+			requestBody:        testRecipeBody,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewRecipeNotOwnedError("recipe-0"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			testName:           "Recipe doesn't exist",
+			requestBody:        testRecipeBody,
+			expectFind:         true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			testName:           "Recipe doesn't exist with If-Match",
+			ifMatch:            `"3"`,
+			requestBody:        testRecipeBody,
+			expectUpdate:       true,
+			expectedVersion:    3,
+			recipeMissing:      true,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			testName:           "Invalid If-Match",
+			ifMatch:            "three",
+			requestBody:        testRecipeBody,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Missing name",
+			requestBody:        `{"ingredients": [{"name": "Tequila"}]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			if d.expectFind {
+				mockRecipeService.On("FindRecipe", "0", "recipe-0").Return(d.existingRecipe, nil)
+			}
+			if d.expectUpdate {
+				var updatedRecipe *model.Recipe
+				if d.returnedError == nil && !d.recipeMissing {
+					updatedRecipe = &model.Recipe{Id: "recipe-0", OwnerId: "0", Name: "House Margarita", Visibility: model.RecipePublic, Version: d.expectedVersion + 1}
+				}
+				mockRecipeService.On("UpdateRecipe", "0", mock.MatchedBy(func(recipe model.Recipe) bool {
+					return recipe.Id == "recipe-0" && recipe.Name == "House Margarita" && recipe.Visibility == model.RecipePublic
+				}), d.expectedVersion).Return(updatedRecipe, d.returnedError)
+			}
+			recipeHandler := RecipeHandler{Service: mockRecipeService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPut, "/recipe/recipe-0", bytes.NewBufferString(d.requestBody))
+			assert.NoError(t, err)
+			if d.ifMatch != "" {
+				request.Header.Set("If-Match", d.ifMatch)
+			}
+
+			router := gin.Default()
+			router.PUT("/recipe/:recipeId", setUserIdInContext("0"), recipeHandler.UpdateRecipe)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+			}
+			mockRecipeService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteRecipe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := []struct {
+		testName           string
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully delete a recipe",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			testName:           "Another user's recipe",
+			returnedError:      apperrors.NewRecipeNotOwnedError("recipe-0"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockRecipeService := service.NewMockRecipeService(t)
+			mockRecipeService.On("DeleteRecipe", "0", "recipe-0").Return(d.returnedError)
+			recipeHandler := RecipeHandler{Service: mockRecipeService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodDelete, "/recipe/recipe-0", nil)
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.DELETE("/recipe/:recipeId", setUserIdInContext("0"), recipeHandler.DeleteRecipe)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			mockRecipeService.AssertExpectations(t)
+		})
+	}
+}
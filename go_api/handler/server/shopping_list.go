@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShoppingListHandler struct {
+	Service service.ShoppingListService
+}
+
+// CreateShoppingList builds the list for the drinks in the body, or for the favorites of the user in the token
+// if there aren't any; `format=markdown` returns only the Markdown rendering
+func (sh *ShoppingListHandler) CreateShoppingList(c *gin.Context) {
+	format, err := dto.NewShoppingListFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	var listRequest dto.ShoppingListRequest
+	if err := c.BindJSON(&listRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the drinkIds, servings and onHand ingredients in the body of your request"})
+		return
+	}
+	if err := listRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	userId := c.GetString("userId")
+	if len(listRequest.DrinkIds) == 0 && userId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "the 'Token' header is required to shop for the favorites"})
+		return
+	}
+
+	list, err := sh.Service.CreateShoppingList(userId, listRequest.DrinkIds, listRequest.ServingsOrDefault(), listRequest.ToOnHand())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if format == dto.ShoppingListMarkdown {
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(list.Markdown()))
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewShoppingListResponse(*list))
+}
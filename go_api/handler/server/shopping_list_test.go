@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+	"the-drink-almanac-api/shopping"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateShoppingList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	list := shopping.NewList([]model.Drink{{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}}}}, 2, nil)
+	data := []struct {
+		testName           string
+		url                string
+		requestBody        string
+		userId             string
+		expectCreate       bool
+		expectedUserId     string
+		expectedDrinkIds   []string
+		returnedError      error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			testName:           "Successfully create a list",
+			url:                "/shopping-list",
+			requestBody:        `{"drinkIds": ["11007"], "servings": 2, "onHand": [{"name": "Salt"}]}`,
+			expectCreate:       true,
+			expectedDrinkIds:   []string{"11007"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Successfully create a list of the favorites as markdown",
+			url:                "/shopping-list?format=markdown",
+			requestBody:        `{"servings": 2}`,
+			userId:             "0",
+			expectCreate:       true,
+			expectedUserId:     "0",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       list.Markdown(),
+		},
+		{
+			testName:           "Favorites without a token",
+			url:                "/shopping-list",
+			requestBody:        `{"servings": 2}`,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			testName:           "Invalid format",
+			url:                "/shopping-list?format=csv",
+			requestBody:        `{"drinkIds": ["11007"]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Too many servings",
+			url:                "/shopping-list",
+			requestBody:        `{"drinkIds": ["11007"], "servings": 1000}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Failed to create a list",
+			url:                "/shopping-list",
+			requestBody:        `{"drinkIds": ["11007"], "servings": 2}`,
+			expectCreate:       true,
+			expectedDrinkIds:   []string{"11007"},
+			returnedError:      fmt.Errorf("failed to find drinks"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockShoppingListService := service.NewMockShoppingListService(t)
+			if d.expectCreate {
+				var returnedList *shopping.List
+				if d.returnedError == nil {
+					returnedList = &list
+				}
+				mockShoppingListService.On("CreateShoppingList", d.expectedUserId, d.expectedDrinkIds, 2, mock.Anything).Return(returnedList, d.returnedError)
+			}
+			shoppingListHandler := ShoppingListHandler{Service: mockShoppingListService}
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, d.url, strings.NewReader(d.requestBody))
+			assert.NoError(t, err)
+
+			router := gin.Default()
+			router.POST("/shopping-list", setUserIdInContext(d.userId), shoppingListHandler.CreateShoppingList)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedBody != "" {
+				assert.Equal(t, d.expectedBody, rr.Body.String())
+			} else if d.expectedStatusCode == http.StatusOK {
+				expectedResponseBody, err := json.Marshal(dto.NewShoppingListResponse(list))
+				assert.NoError(t, err)
+				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/dto"
+	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/service"
 
 	"github.com/gin-gonic/gin"
@@ -18,16 +19,23 @@ type UserHandler struct {
 	authService service.AuthService
 }
 
+// FindUser returns the user's data; `include=favorites` adds the user's favorites,
+// which the single-table design reads in the same request as the user
 func (uh *UserHandler) FindUser(c *gin.Context) {
 	userId := c.GetString("userId")
 	if userId == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "user id was not successfully retrieved from token"})
 		return
 	}
+	inclusion, err := dto.NewUserInclusion(c.Query("include"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
 
-	user, err := uh.userService.FindUser(userId)
+	user, favorites, err := uh.findUser(userId, inclusion)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 	if user == nil {
@@ -36,9 +44,21 @@ func (uh *UserHandler) FindUser(c *gin.Context) {
 	}
 
 	userResponse := dto.NewUserResponse(*user)
+	if inclusion.Favorites {
+		userResponse = dto.NewUserWithFavoritesResponse(*user, favorites)
+	}
+	c.Header("ETag", dto.NewETag(user.Version))
 	c.JSON(http.StatusOK, userResponse)
 }
 
+func (uh *UserHandler) findUser(userId string, inclusion dto.UserInclusion) (*model.User, []model.Favorite, error) {
+	if inclusion.Favorites {
+		return uh.userService.FindUserWithFavorites(userId)
+	}
+	user, err := uh.userService.FindUser(userId)
+	return user, nil, err
+}
+
 func (uh *UserHandler) CreateNewUser(c *gin.Context) {
 	var userRequest dto.UserPostRequest
 	err := c.BindJSON(&userRequest)
@@ -70,9 +90,7 @@ func (uh *UserHandler) CreateNewUser(c *gin.Context) {
 			})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		respondWithError(c, err)
 		return
 	}
 	c.Status(http.StatusCreated)
@@ -87,17 +105,117 @@ func (uh *UserHandler) DeleteUser(c *gin.Context) {
 
 	err := uh.userService.DeleteUser(userId)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
 		if errors.As(err, &apperrors.InvalidAuthTokenError{}) {
-			statusCode = http.StatusForbidden
+			c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
 		}
-		c.JSON(statusCode, gin.H{"message": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, gin.H{"message": "the user was deleted"})
 }
 
+// UpdatePreferences saves the user's preferences, such as the units that recipes are rendered in;
+// the If-Match header makes the update conditional on the ETag returned by GET /user
+func (uh *UserHandler) UpdatePreferences(c *gin.Context) {
+	userId := c.GetString("userId")
+	if userId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "user id was not successfully retrieved from token"})
+		return
+	}
+	expectedVersion, conditional, err := dto.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	var preferencesRequest dto.UserPreferencesRequest
+	if err := c.BindJSON(&preferencesRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the preferences in the body of your request"})
+		return
+	}
+	if err := preferencesRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	user, err := uh.userService.FindUser(userId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no user was found with the userId '%s'", userId)})
+		return
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	user.Units = *preferencesRequest.Units
+	updatedUser, err := uh.userService.UpdateUser(*user, expectedVersion)
+	if err != nil {
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"message": "the user was modified since it was read"})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", dto.NewETag(updatedUser.Version))
+	c.JSON(http.StatusOK, dto.NewUserResponse(*updatedUser))
+}
+
+// UpdateUser changes the fields of the user's profile and preferences that are in the body, leaving the rest as they are;
+// the If-Match header makes the update conditional on the ETag returned by GET /user
+func (uh *UserHandler) UpdateUser(c *gin.Context) {
+	userId := c.GetString("userId")
+	if userId == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "user id was not successfully retrieved from token"})
+		return
+	}
+	expectedVersion, conditional, err := dto.ParseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	var patchRequest dto.UserPatchRequest
+	if err := c.BindJSON(&patchRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "please provide the fields to change in the body of your request"})
+		return
+	}
+	if err := patchRequest.ValidateRequest(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	user, err := uh.userService.FindUser(userId)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no user was found with the userId '%s'", userId)})
+		return
+	}
+	if !conditional {
+		expectedVersion = user.Version
+	}
+
+	patchRequest.ApplyTo(user)
+	updatedUser, err := uh.userService.UpdateUser(*user, expectedVersion)
+	if err != nil {
+		if conditional && errors.As(err, &apperrors.ConflictError{}) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"message": "the user was modified since it was read"})
+			return
+		}
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", dto.NewETag(updatedUser.Version))
+	c.JSON(http.StatusOK, dto.NewUserResponse(*updatedUser))
+}
+
 func (uh *UserHandler) Login(c *gin.Context) {
 	var userRequest dto.UserPostRequest
 	err := c.BindJSON(&userRequest)
@@ -125,21 +243,21 @@ func (uh *UserHandler) Login(c *gin.Context) {
 
 	user, err := uh.userService.Login(userRequest.Username, userRequest.Password)
 	if err != nil {
-		statusCode := http.StatusInternalServerError
 		if errors.As(err, &apperrors.UserNotFoundError{}) {
-			statusCode = http.StatusNotFound
+			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			return
 		}
 		if errors.As(err, &apperrors.IncorrectPasswordError{}) {
-			statusCode = http.StatusBadRequest
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
 		}
-
-		c.JSON(statusCode, gin.H{"message": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
 	tokenString, err := uh.authService.CreateNewToken(user.Id, 60*24)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"the-drink-almanac-api/apperrors"
@@ -20,10 +21,13 @@ import (
 
 func TestFindUser(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	favorites := []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007"}}
 	data := []struct {
 		testName           string
 		userId             string
+		include            string
 		returnedUser       *model.User
+		returnedFavorites  []model.Favorite
 		returnedError      error
 		expectedStatusCode int
 	}{
@@ -34,10 +38,19 @@ func TestFindUser(t *testing.T) {
 				Id:       "0",
 				Username: "0",
 				Password: "0",
+				Version:  3,
 			},
 			returnedError:      nil,
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			testName:           "Successfully retrieve user with favorites",
+			userId:             "0",
+			include:            "favorites",
+			returnedUser:       &model.User{Id: "0", Username: "0", Password: "0", Version: 3},
+			returnedFavorites:  favorites,
+			expectedStatusCode: http.StatusOK,
+		},
 		{
 			testName:           "Failed to retrieve user",
 			userId:             "0",
@@ -45,6 +58,12 @@ func TestFindUser(t *testing.T) {
 			returnedError:      nil,
 			expectedStatusCode: http.StatusNotFound,
 		},
+		{
+			testName:           "Invalid include",
+			userId:             "0",
+			include:            "recipes",
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		{
 			testName:           "No user id in context",
 			userId:             "",
@@ -57,14 +76,18 @@ func TestFindUser(t *testing.T) {
 	for _, d := range data {
 		t.Run(d.testName, func(t *testing.T) {
 			mockUserService := service.NewMockUserService(t)
-			if d.userId != "" {
+			switch {
+			case d.userId == "" || d.expectedStatusCode == http.StatusBadRequest:
+			case d.include == "favorites":
+				mockUserService.On("FindUserWithFavorites", d.userId).Return(d.returnedUser, d.returnedFavorites, d.returnedError)
+			default:
 				mockUserService.On("FindUser", d.userId).Return(d.returnedUser, d.returnedError)
 			}
 			mockAuthService := service.NewMockAuthService(t)
 			userHandler := NewUserHandler(mockUserService, mockAuthService)
 
 			rr := httptest.NewRecorder()
-			request, err := http.NewRequest(http.MethodGet, "/user", nil)
+			request, err := http.NewRequest(http.MethodGet, "/user?include="+d.include, nil)
 			assert.NoError(t, err)
 
 			router := gin.Default()
@@ -76,9 +99,13 @@ func TestFindUser(t *testing.T) {
 
 			if d.returnedUser != nil {
 				usersResponse := dto.NewUserResponse(*d.returnedUser)
+				if d.include == "favorites" {
+					usersResponse = dto.NewUserWithFavoritesResponse(*d.returnedUser, d.returnedFavorites)
+				}
 				expectedResponseBody, err := json.Marshal(usersResponse)
 				assert.NoError(t, err)
 				assert.Equal(t, expectedResponseBody, rr.Body.Bytes())
+				assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
 			}
 		})
 	}
@@ -268,6 +295,248 @@ func TestDeleteUser(t *testing.T) {
 	}
 }
 
+func TestUpdatePreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	existingUser := &model.User{Id: "0", Username: "user0", Version: 3}
+	data := []struct {
+		testName           string
+		userId             string
+		body               string
+		ifMatch            string
+		returnedUser       *model.User
+		returnedFindError  error
+		expectedVersion    int
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully update preferences",
+			userId:             "0",
+			body:               `{"units": "imperial"}`,
+			returnedUser:       existingUser,
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Successfully update preferences if they match",
+			userId:             "0",
+			body:               `{"units": ""}`,
+			ifMatch:            `"2"`,
+			returnedUser:       existingUser,
+			expectedVersion:    2,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "User was modified since it was read",
+			userId:             "0",
+			body:               `{"units": "metric"}`,
+			ifMatch:            `"2"`,
+			returnedUser:       existingUser,
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the user was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			testName:           "Concurrent update without If-Match",
+			userId:             "0",
+			body:               `{"units": "metric"}`,
+			returnedUser:       existingUser,
+			expectedVersion:    3,
+			returnedError:      apperrors.NewConflictError("the user was modified", nil),
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			testName:           "Failed to find user",
+			userId:             "0",
+			body:               `{"units": "metric"}`,
+			returnedFindError:  fmt.Errorf("failed to find user"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			testName:           "No user exists",
+			userId:             "0",
+			body:               `{"units": "metric"}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			testName:           "Invalid units",
+			userId:             "0",
+			body:               `{"units": "nautical"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Invalid If-Match",
+			userId:             "0",
+			body:               `{"units": "metric"}`,
+			ifMatch:            "2",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "User id not retrieved",
+			body:               `{"units": "metric"}`,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockUserService := service.NewMockUserService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			if d.returnedUser != nil || d.returnedFindError != nil || d.expectedStatusCode == http.StatusNotFound {
+				mockUserService.On("FindUser", d.userId).Return(d.returnedUser, d.returnedFindError)
+			}
+			if d.expectedVersion != 0 {
+				mockUserService.On("UpdateUser", mock.AnythingOfType("model.User"), d.expectedVersion).Return(
+					func(user model.User, expectedVersion int) *model.User {
+						if d.returnedError != nil {
+							return nil
+						}
+						user.Version = expectedVersion + 1
+						return &user
+					},
+					d.returnedError,
+				)
+			}
+			userHandler := NewUserHandler(mockUserService, mockAuthService)
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPut, "/user/preferences", bytes.NewBufferString(d.body))
+			assert.NoError(t, err)
+			if d.ifMatch != "" {
+				request.Header.Set("If-Match", d.ifMatch)
+			}
+
+			router := gin.Default()
+			router.PUT("/user/preferences", setUserIdInContext(d.userId), userHandler.UpdatePreferences)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, dto.NewETag(d.expectedVersion+1), rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	existingUser := &model.User{Id: "0", Username: "user0", Bio: "Mostly sours.", Units: "metric", Version: 3}
+	data := []struct {
+		testName           string
+		userId             string
+		body               string
+		ifMatch            string
+		returnedUser       *model.User
+		expectedUser       model.User
+		expectedVersion    int
+		returnedError      error
+		expectedStatusCode int
+	}{
+		{
+			testName:           "Successfully update the display name",
+			userId:             "0",
+			body:               `{"displayName": " User Zero ", "publicFavorites": true}`,
+			returnedUser:       existingUser,
+			expectedUser:       model.User{Id: "0", Username: "user0", DisplayName: "User Zero", Bio: "Mostly sours.", Units: "metric", PublicFavorites: true, Version: 3},
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "Successfully clear the bio if the user matches",
+			userId:             "0",
+			body:               `{"bio": ""}`,
+			ifMatch:            `"3"`,
+			returnedUser:       existingUser,
+			expectedUser:       model.User{Id: "0", Username: "user0", Units: "metric", Version: 3},
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			testName:           "User was modified since it was read",
+			userId:             "0",
+			body:               `{"bio": ""}`,
+			ifMatch:            `"2"`,
+			returnedUser:       existingUser,
+			expectedUser:       model.User{Id: "0", Username: "user0", Units: "metric", Version: 3},
+			expectedVersion:    2,
+			returnedError:      apperrors.NewConflictError("the user was modified", nil),
+			expectedStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			testName:           "No user exists",
+			userId:             "0",
+			body:               `{"bio": ""}`,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			testName:           "No changes",
+			userId:             "0",
+			body:               `{}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Display name is too long",
+			userId:             "0",
+			body:               `{"displayName": "` + strings.Repeat("n", dto.MaxDisplayNameLength+1) + `"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "Invalid body",
+			userId:             "0",
+			body:               `{"publicFavorites": "yes"}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			testName:           "User id not retrieved",
+			body:               `{"bio": ""}`,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.testName, func(t *testing.T) {
+			mockUserService := service.NewMockUserService(t)
+			mockAuthService := service.NewMockAuthService(t)
+			if d.returnedUser != nil || d.expectedStatusCode == http.StatusNotFound {
+				// the handler changes the user that it found, so each case gets its own copy
+				var returnedUser *model.User
+				if d.returnedUser != nil {
+					userCopy := *d.returnedUser
+					returnedUser = &userCopy
+				}
+				mockUserService.On("FindUser", d.userId).Return(returnedUser, nil)
+			}
+			if d.expectedVersion != 0 {
+				var updatedUser *model.User
+				if d.returnedError == nil {
+					userCopy := d.expectedUser
+					userCopy.Version = d.expectedVersion + 1
+					updatedUser = &userCopy
+				}
+				mockUserService.On("UpdateUser", d.expectedUser, d.expectedVersion).Return(updatedUser, d.returnedError)
+			}
+			userHandler := NewUserHandler(mockUserService, mockAuthService)
+
+			rr := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPatch, "/user", bytes.NewBufferString(d.body))
+			assert.NoError(t, err)
+			if d.ifMatch != "" {
+				request.Header.Set("If-Match", d.ifMatch)
+			}
+
+			router := gin.Default()
+			router.PATCH("/user", setUserIdInContext(d.userId), userHandler.UpdateUser)
+			router.ServeHTTP(rr, request)
+
+			assert.Equal(t, d.expectedStatusCode, rr.Code)
+			if d.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, dto.NewETag(d.expectedVersion+1), rr.Header().Get("ETag"))
+			}
+			mockUserService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestLogin(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockUser := &model.User{
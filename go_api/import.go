@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"the-drink-almanac-api/archive"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+// runImport verifies an archive created by the export command and then writes its users and favorites
+func runImport(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	modeFlag := flags.String("mode", string(archive.ModeMerge), "'merge' keeps existing records, 'overwrite' replaces them")
+	dryRun := flags.Bool("dry-run", false, "print what would be imported without writing anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import [-mode merge|overwrite] [-dry-run] <archive file>")
+	}
+	path := flags.Arg(0)
+	mode, err := archive.ParseMode(*modeFlag)
+	if err != nil {
+		return err
+	}
+
+	// verify the whole archive before writing anything so a corrupted archive isn't partially imported
+	if err := verifyArchive(path); err != nil {
+		return err
+	}
+
+	userStore, favoriteStore, err := repository.NewRepositories(appConfig)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	report, err := archive.NewImporter(userStore, favoriteStore, mode, *dryRun).Import(file)
+	if err != nil {
+		return fmt.Errorf("failed to import: %w", err)
+	}
+
+	verb := "imported"
+	if *dryRun {
+		verb = "would import"
+	}
+	fmt.Printf("%s users: %+v\n", verb, report.Users)
+	fmt.Printf("%s favorites: %+v\n", verb, report.Favorites)
+	return nil
+}
+
+func verifyArchive(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := archive.Verify(file); err != nil {
+		return fmt.Errorf("the archive '%s' is invalid: %w", path, err)
+	}
+	return nil
+}
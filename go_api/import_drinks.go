@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"the-drink-almanac-api/cocktaildb"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+// runImportDrinks upserts the drinks of local TheCocktailDB dumps into the drink catalog
+// and prints every drink that was malformed
+func runImportDrinks(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("import-drinks", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "check and count the drinks without saving them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("usage: import-drinks [-dry-run] <TheCocktailDB json file> ...")
+	}
+
+	drinkStore, err := repository.NewDrinkRepository(appConfig)
+	if err != nil {
+		return err
+	}
+	importer := cocktaildb.NewImporter(service.NewDefaultDrinkService(drinkStore), *dryRun)
+
+	report := cocktaildb.Report{}
+	for _, path := range flags.Args() {
+		records, err := cocktaildb.ReadDump(path)
+		if err != nil {
+			return err
+		}
+		if err := importer.Import(path, records, &report); err != nil {
+			return fmt.Errorf("failed to import '%s': %w", path, err)
+		}
+	}
+
+	for _, problem := range report.Problems {
+		fmt.Fprintf(os.Stderr, "skipped drink %d (id '%s') of %s: %s\n", problem.Index, problem.Id, problem.File, problem.Reason)
+	}
+	verb := "imported"
+	if *dryRun {
+		verb = "would import"
+	}
+	fmt.Printf("%s drinks: created %d, updated %d, unchanged %d, malformed %d\n", verb, report.Created, report.Updated, report.Unchanged, report.Malformed)
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"the-drink-almanac-api/catalog"
+	lambdaHandler "the-drink-almanac-api/handler/lambda"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+// newHandler is only called once per lambda container,
+// so the search index is reused by every invocation that the container handles
+func newHandler() lambdaHandler.DrinksLambdaHandler {
+	fmt.Println("starting drinks lambda")
+	appConfig := model.NewAppConfig()
+	authService := service.NewJwtAuthService(appConfig.JwtSecretKey)
+	// the drinks come from the catalog, or from the dataset if it's set
+	drinkStore, err := catalog.NewDrinkRepository(appConfig)
+	if err != nil {
+		panic(err)
+	}
+	userStore, favoriteStore, _ := repository.NewRepositories(appConfig)
+	drinkService := service.NewDefaultDrinkService(drinkStore)
+	// public recipes are searched alongside the drinks
+	recipeStore, _ := repository.NewRecipeRepository(appConfig)
+	recipeService := service.NewDefaultRecipeService(recipeStore)
+	loadDrinks := service.WithPublicRecipes(drinkStore.FindAll, recipeService)
+	// a user's makeable favorites include their recipes that aren't public, which aren't searched
+	drinkResolver := service.NewDefaultDrinkResolver(drinkService, recipeService)
+	drinkSearchService := service.NewIndexedDrinkSearchService(loadDrinks, favoriteStore, drinkResolver, appConfig.DrinkSearchRefreshInterval)
+	// the index is built during the cold start; the container is frozen between invocations,
+	// so the background rebuilds only run while it's handling one
+	if err := drinkSearchService.Start(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build the drink search index; the first search will try again: %v\n", err)
+	}
+	// the users aren't cached, so that the units saved through the users lambda are used right away
+	userService := service.NewDefaultUserService(userStore)
+	return lambdaHandler.NewDrinksLambdaHandler(drinkService, drinkSearchService, authService, userService)
+}
+
+func main() {
+	drinkHandler := newHandler()
+	lambda.Start(drinkHandler.RouteRequest)
+}
@@ -3,25 +3,43 @@ package main
 import (
 	"fmt"
 
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"the-drink-almanac-api/catalog"
 	lambdaHandler "the-drink-almanac-api/handler/lambda"
 	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/repository"
 	"the-drink-almanac-api/service"
 )
 
-func start(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+// newHandler is only called once per lambda container,
+// so the cache and a dataset are reused by every invocation that the container handles
+func newHandler() lambdaHandler.FavoritesLambdaHandler {
 	fmt.Println("starting favorites lambda")
 	appConfig := model.NewAppConfig()
 	authService := service.NewJwtAuthService(appConfig.JwtSecretKey)
-	favoriteStore, _ := repository.NewFavoriteRepository(appConfig.FavoritesTableName, appConfig.AwsEndpoint)
-	favoriteService := service.NewDefaultFavoriteService(favoriteStore)
-	favoriteHandler := lambdaHandler.NewFavoritesLambdaHandler(favoriteService, authService)
-
-	return favoriteHandler.RouteRequest(request)
+	_, favoriteStore, _ := repository.NewRepositories(appConfig)
+	cache := repository.NewLRUCache(appConfig.CacheSize)
+	cachedFavoriteStore := repository.NewCachedFavoriteRepository(favoriteStore, cache, appConfig.CacheTTL)
+	// new favorites' drinks are checked against, and expanded favorites embed, the drinks of the catalog or dataset
+	drinkStore, err := catalog.NewDrinkRepository(appConfig)
+	if err != nil {
+		panic(err)
+	}
+	// an invalid lookup is a configuration error, which shouldn't fall back to accepting every drink id
+	drinkLookup, err := catalog.NewDrinkLookup(appConfig, drinkStore)
+	if err != nil {
+		panic(err)
+	}
+	// users' recipes can be favorited as long as the user can see them
+	recipeStore, _ := repository.NewRecipeRepository(appConfig)
+	recipeService := service.NewDefaultRecipeService(recipeStore)
+	favoriteService := service.NewDefaultFavoriteService(cachedFavoriteStore).WithDrinkLookup(drinkLookup).
+		WithRecipes(recipeService)
+	drinkResolver := service.NewDefaultDrinkResolver(service.NewDefaultDrinkService(drinkStore), recipeService)
+	return lambdaHandler.NewFavoritesLambdaHandler(favoriteService, authService, drinkResolver)
 }
 
 func main() {
-	lambda.Start(start)
+	favoriteHandler := newHandler()
+	lambda.Start(favoriteHandler.RouteRequest)
 }
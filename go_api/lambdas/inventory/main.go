@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	lambdaHandler "the-drink-almanac-api/handler/lambda"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+func newHandler() lambdaHandler.InventoryLambdaHandler {
+	fmt.Println("starting inventory lambda")
+	appConfig := model.NewAppConfig()
+	authService := service.NewJwtAuthService(appConfig.JwtSecretKey)
+	inventoryStore, _ := repository.NewInventoryRepository(appConfig)
+	inventoryService := service.NewDefaultInventoryService(inventoryStore)
+	return lambdaHandler.NewInventoryLambdaHandler(inventoryService, authService)
+}
+
+func main() {
+	inventoryHandler := newHandler()
+	lambda.Start(inventoryHandler.RouteRequest)
+}
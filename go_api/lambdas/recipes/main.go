@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	lambdaHandler "the-drink-almanac-api/handler/lambda"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+func newHandler() lambdaHandler.RecipesLambdaHandler {
+	fmt.Println("starting recipes lambda")
+	appConfig := model.NewAppConfig()
+	authService := service.NewJwtAuthService(appConfig.JwtSecretKey)
+	recipeStore, _ := repository.NewRecipeRepository(appConfig)
+	recipeService := service.NewDefaultRecipeService(recipeStore)
+	return lambdaHandler.NewRecipesLambdaHandler(recipeService, authService)
+}
+
+func main() {
+	recipeHandler := newHandler()
+	lambda.Start(recipeHandler.RouteRequest)
+}
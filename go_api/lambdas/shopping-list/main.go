@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"the-drink-almanac-api/catalog"
+	lambdaHandler "the-drink-almanac-api/handler/lambda"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+// newHandler is only called once per lambda container,
+// so a dataset is only read once for every invocation that the container handles
+func newHandler() lambdaHandler.ShoppingListLambdaHandler {
+	fmt.Println("starting shopping list lambda")
+	appConfig := model.NewAppConfig()
+	authService := service.NewJwtAuthService(appConfig.JwtSecretKey)
+	_, favoriteStore, _ := repository.NewRepositories(appConfig)
+	favoriteService := service.NewDefaultFavoriteService(favoriteStore)
+	// the recipes come from the catalog, or from the dataset if it's set
+	drinkStore, err := catalog.NewDrinkRepository(appConfig)
+	if err != nil {
+		panic(err)
+	}
+	// users' recipes are read like the drinks, as long as the user can see them
+	recipeStore, _ := repository.NewRecipeRepository(appConfig)
+	drinkResolver := service.NewDefaultDrinkResolver(service.NewDefaultDrinkService(drinkStore), service.NewDefaultRecipeService(recipeStore))
+	shoppingListService := service.NewDefaultShoppingListService(drinkResolver, favoriteService)
+	return lambdaHandler.NewShoppingListLambdaHandler(shoppingListService, authService)
+}
+
+func main() {
+	shoppingListHandler := newHandler()
+	lambda.Start(shoppingListHandler.RouteRequest)
+}
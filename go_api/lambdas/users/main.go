@@ -2,26 +2,47 @@ package main
 
 import (
 	"fmt"
+	"os"
 
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"the-drink-almanac-api/blob"
 	lambdaHandler "the-drink-almanac-api/handler/lambda"
 	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/repository"
 	"the-drink-almanac-api/service"
 )
 
-func start(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+// newHandler is only called once per lambda container,
+// so the cache is reused by every invocation that the container handles
+func newHandler() lambdaHandler.UsersLambdaHandler {
 	fmt.Println("starting users lambda")
 	appConfig := model.NewAppConfig()
 	authService := service.NewJwtAuthService(appConfig.JwtSecretKey)
-	userStore, _ := repository.NewUserRepository(appConfig.UsersTableName, appConfig.AwsEndpoint)
-	userService := service.NewDefaultUserService(userStore)
-	userHandler := lambdaHandler.NewUsersLambdaHandler(userService, authService)
-
-	return userHandler.RouteRequest(request)
+	userStore, favoriteStore, _ := repository.NewRepositories(appConfig)
+	cache := repository.NewLRUCache(appConfig.CacheSize)
+	cachedUserStore := repository.NewCachedUserRepository(userStore, cache, appConfig.CacheTTL)
+	inventoryStore, _ := repository.NewInventoryRepository(appConfig)
+	recipeStore, _ := repository.NewRecipeRepository(appConfig)
+	cachedFavoriteStore := repository.NewCachedFavoriteRepository(favoriteStore, cache, appConfig.CacheTTL)
+	// avatars need a store that every container shares, which is optional, so the rest of the users lambda
+	// keeps working without one; uploads fail with 503 until it's configured
+	avatarStore, err := blob.NewSharedStore(appConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "avatars are disabled: %v\n", err)
+		avatarStore = blob.NewUnavailableStore("avatars are disabled, since no shared blob store is configured")
+	}
+	// public profiles only list favorites of public recipes
+	recipeService := service.NewDefaultRecipeService(recipeStore)
+	profileService := service.NewDefaultProfileService(service.NewDefaultUserService(cachedUserStore),
+		service.NewDefaultFavoriteService(cachedFavoriteStore), avatarStore).WithRecipes(recipeService)
+	// deleting a user also deletes their inventory, recipes and avatar
+	userService := service.NewDefaultUserService(cachedUserStore).
+		WithUserData(service.NewDefaultInventoryService(inventoryStore), recipeService, profileService).
+		WithFavorites(repository.NewUserWithFavoritesFinder(userStore, favoriteStore))
+	return lambdaHandler.NewUsersLambdaHandler(userService, authService, profileService)
 }
 
 func main() {
-	lambda.Start(start)
+	userHandler := newHandler()
+	lambda.Start(userHandler.RouteRequest)
 }
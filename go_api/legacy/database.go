@@ -0,0 +1,85 @@
+// Package legacy migrates the users and favorites of the original Flask/SQLAlchemy api into the current repositories.
+//
+// The legacy api stored its records with integer ids in a relational database (SQLite locally);
+// see api/models/user.py and api/models/favorite.py for its schema.
+package legacy
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// User is a row of the legacy users table; the legacy api stored passwords in plaintext,
+// but the column may also contain hashes if the database was modified outside the api
+type User struct {
+	Id       int64
+	Username string
+	Password string
+}
+
+// Favorite is a row of the legacy favorites table
+type Favorite struct {
+	Id      int64
+	UserId  int64
+	DrinkId int64
+}
+
+// Database is the contents of the legacy database
+type Database struct {
+	Users     []User
+	Favorites []Favorite
+}
+
+// ReadDatabase reads every user and favorite from the legacy database, ordered by id
+func ReadDatabase(db *sql.DB) (Database, error) {
+	var database Database
+
+	userRows, err := db.Query("SELECT id, username, password FROM users ORDER BY id")
+	if err != nil {
+		return database, fmt.Errorf("failed to read the legacy users: %w", err)
+	}
+	defer userRows.Close()
+	for userRows.Next() {
+		var user User
+		var username, password sql.NullString
+		if err := userRows.Scan(&user.Id, &username, &password); err != nil {
+			return database, fmt.Errorf("failed to read a legacy user: %w", err)
+		}
+		user.Username = username.String
+		user.Password = password.String
+		database.Users = append(database.Users, user)
+	}
+	if err := userRows.Err(); err != nil {
+		return database, fmt.Errorf("failed to read the legacy users: %w", err)
+	}
+
+	favoriteRows, err := db.Query("SELECT id, user_id, drink_id FROM favorites ORDER BY id")
+	if err != nil {
+		return database, fmt.Errorf("failed to read the legacy favorites: %w", err)
+	}
+	defer favoriteRows.Close()
+	for favoriteRows.Next() {
+		var favorite Favorite
+		var userId, drinkId sql.NullInt64
+		if err := favoriteRows.Scan(&favorite.Id, &userId, &drinkId); err != nil {
+			return database, fmt.Errorf("failed to read a legacy favorite: %w", err)
+		}
+		// a missing user or drink id is read as -1, which never matches a legacy row,
+		// so the favorite is kept and reported as orphaned instead of failing the migration
+		favorite.UserId = nullableId(userId)
+		favorite.DrinkId = nullableId(drinkId)
+		database.Favorites = append(database.Favorites, favorite)
+	}
+	if err := favoriteRows.Err(); err != nil {
+		return database, fmt.Errorf("failed to read the legacy favorites: %w", err)
+	}
+
+	return database, nil
+}
+
+func nullableId(id sql.NullInt64) int64 {
+	if !id.Valid {
+		return -1
+	}
+	return id.Int64
+}
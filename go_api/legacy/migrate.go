@@ -0,0 +1,290 @@
+package legacy
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// userNamespace is used to derive deterministic user ids from legacy user ids,
+// so running the migration again maps every legacy user to the same user
+var userNamespace = uuid.MustParse("9d3f6a27-4b8e-4f0c-8a61-2c7e5b1d9f43")
+
+// Status is the outcome of migrating a single legacy record
+type Status string
+
+const (
+	StatusCreated Status = "created"
+	// StatusAlreadyMigrated is used for records that were created by a previous run of the migration
+	StatusAlreadyMigrated Status = "already-migrated"
+	// StatusConflict is used for users whose username already belongs to a different user
+	// and for favorites that duplicate another favorite of the same user and drink
+	StatusConflict Status = "conflict"
+	// StatusSkipped is used for favorites whose user doesn't exist or wasn't migrated
+	StatusSkipped Status = "skipped"
+)
+
+// Mapping records what happened to a single legacy record and which id it was given;
+// Id is empty for records that weren't migrated
+type Mapping struct {
+	Table                 string
+	LegacyId              int64
+	Id                    string
+	Status                Status
+	PasswordResetRequired bool
+	Reason                string
+}
+
+// MigrationCounts is the number of records of a single type with each Status
+type MigrationCounts struct {
+	Created         int
+	AlreadyMigrated int
+	Conflicts       int
+	Skipped         int
+	// PasswordResets is the number of migrated users whose password couldn't be carried over
+	PasswordResets int
+}
+
+type Report struct {
+	Users     MigrationCounts
+	Favorites MigrationCounts
+	Mappings  []Mapping
+}
+
+// Migrator writes the legacy records through the repositories
+type Migrator struct {
+	users     repository.UserRepository
+	favorites repository.FavoriteRepository
+	clock     service.Clock
+	dryRun    bool
+}
+
+func NewMigrator(users repository.UserRepository, favorites repository.FavoriteRepository, dryRun bool) Migrator {
+	return Migrator{
+		users:     users,
+		favorites: favorites,
+		clock:     service.SystemClock{},
+		dryRun:    dryRun,
+	}
+}
+
+// WithClock returns a copy of the migrator that uses the given clock for timestamps
+func (m Migrator) WithClock(clock service.Clock) Migrator {
+	m.clock = clock
+	return m
+}
+
+// Migrate creates a user for every legacy user and a favorite for every legacy favorite;
+// it can safely be run again, since records that were already migrated are left untouched
+func (m Migrator) Migrate(database Database) (Report, error) {
+	report := Report{}
+	now := m.clock.Now()
+
+	// legacy usernames weren't unique, and a dry run doesn't write anything that later lookups would find,
+	// so the usernames and favorites claimed by this run are tracked separately
+	userIds := map[int64]string{}
+	claimedUsernames := map[string]bool{}
+	for _, legacyUser := range database.Users {
+		mapping, err := m.migrateUser(legacyUser, now, claimedUsernames)
+		if err != nil {
+			return report, err
+		}
+		if mapping.Id != "" {
+			userIds[legacyUser.Id] = mapping.Id
+		}
+		countMapping(&report.Users, mapping)
+		report.Mappings = append(report.Mappings, mapping)
+	}
+
+	claimedFavorites := map[string]bool{}
+	for _, legacyFavorite := range database.Favorites {
+		mapping, err := m.migrateFavorite(legacyFavorite, now, userIds, claimedFavorites)
+		if err != nil {
+			return report, err
+		}
+		countMapping(&report.Favorites, mapping)
+		report.Mappings = append(report.Mappings, mapping)
+	}
+
+	return report, nil
+}
+
+func (m Migrator) migrateUser(legacyUser User, now time.Time, claimedUsernames map[string]bool) (Mapping, error) {
+	mapping := Mapping{Table: "users", LegacyId: legacyUser.Id}
+	id := NewUserId(legacyUser.Id)
+
+	existingUser, err := m.users.FindUserById(id)
+	if err != nil {
+		return mapping, err
+	}
+	if existingUser != nil {
+		mapping.Id = id
+		mapping.Status = StatusAlreadyMigrated
+		claimedUsernames[existingUser.Username] = true
+		return mapping, nil
+	}
+
+	if legacyUser.Username == "" {
+		mapping.Status = StatusConflict
+		mapping.Reason = "the username is empty"
+		return mapping, nil
+	}
+	if claimedUsernames[legacyUser.Username] {
+		mapping.Status = StatusConflict
+		mapping.Reason = "another legacy user has the same username"
+		return mapping, nil
+	}
+	userWithUsername, err := m.users.FindUserByUsername(legacyUser.Username)
+	if err != nil {
+		return mapping, err
+	}
+	if userWithUsername != nil {
+		mapping.Status = StatusConflict
+		mapping.Reason = "the username belongs to user " + userWithUsername.Id
+		return mapping, nil
+	}
+
+	password, resetRequired, err := migratePassword(legacyUser.Password)
+	if err != nil {
+		return mapping, err
+	}
+	mapping.Id = id
+	mapping.Status = StatusCreated
+	mapping.PasswordResetRequired = resetRequired
+	claimedUsernames[legacyUser.Username] = true
+	if m.dryRun {
+		return mapping, nil
+	}
+	return mapping, m.users.CreateNewUser(model.User{
+		Id:        id,
+		Username:  legacyUser.Username,
+		Password:  password,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	})
+}
+
+func (m Migrator) migrateFavorite(legacyFavorite Favorite, now time.Time, userIds map[int64]string, claimedFavorites map[string]bool) (Mapping, error) {
+	mapping := Mapping{Table: "favorites", LegacyId: legacyFavorite.Id}
+	userId, ok := userIds[legacyFavorite.UserId]
+	if !ok {
+		mapping.Status = StatusSkipped
+		mapping.Reason = "the favorite's user wasn't migrated"
+		return mapping, nil
+	}
+	if legacyFavorite.DrinkId < 0 {
+		mapping.Status = StatusSkipped
+		mapping.Reason = "the favorite doesn't have a drink"
+		return mapping, nil
+	}
+
+	// favorites use the same ids as the ones created by the FavoriteService,
+	// so a drink the user favorited again after switching to the current api is recognized as a duplicate
+	drinkId := strconv.FormatInt(legacyFavorite.DrinkId, 10)
+	id := service.NewFavoriteId(userId, drinkId)
+	mapping.Id = id
+	if claimedFavorites[id] {
+		mapping.Status = StatusConflict
+		mapping.Reason = "another legacy favorite has the same user and drink"
+		return mapping, nil
+	}
+	claimedFavorites[id] = true
+
+	existingFavorite, err := m.favorites.FindFavoriteById(id)
+	if err != nil {
+		return mapping, err
+	}
+	if existingFavorite != nil {
+		mapping.Status = StatusAlreadyMigrated
+		return mapping, nil
+	}
+
+	mapping.Status = StatusCreated
+	if m.dryRun {
+		return mapping, nil
+	}
+	err = m.favorites.CreateNewFavorite(model.Favorite{
+		Id:        id,
+		UserId:    userId,
+		DrinkId:   drinkId,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	})
+	if errors.As(err, &apperrors.FavoriteAlreadyExistsError{}) {
+		mapping.Status = StatusAlreadyMigrated
+		return mapping, nil
+	}
+	return mapping, err
+}
+
+// NewUserId generates the same uuid for every migration of the legacy user
+func NewUserId(legacyId int64) string {
+	return uuid.NewSHA1(userNamespace, []byte(strconv.FormatInt(legacyId, 10))).String()
+}
+
+// migratePassword converts the legacy password into a bcrypt hash that DefaultUserService.Login can verify
+//
+// The legacy api stored plaintext passwords, which are hashed; bcrypt hashes are kept as they are.
+// Empty passwords and hashes created by other algorithms (e.g. werkzeug's pbkdf2 hashes) can't be converted,
+// so the user is migrated without a password, which prevents logging in until the password is reset
+func migratePassword(password string) (hash string, resetRequired bool, err error) {
+	switch {
+	case password == "":
+		return "", true, nil
+	case isBcryptHash(password):
+		return password, false, nil
+	case isOtherHash(password):
+		return "", true, nil
+	}
+	hash, err = service.HashPassword(password)
+	return hash, false, err
+}
+
+func isBcryptHash(password string) bool {
+	_, err := bcrypt.Cost([]byte(password))
+	return err == nil
+}
+
+// isOtherHash checks for the "method$salt$hash" format used by werkzeug and passlib,
+// e.g. "pbkdf2:sha256:260000$salt$hash" or "$pbkdf2-sha256$29000$salt$hash"
+func isOtherHash(password string) bool {
+	parts := strings.Split(strings.TrimPrefix(password, "$"), "$")
+	if len(parts) < 3 {
+		return false
+	}
+	method := strings.SplitN(parts[0], ":", 2)[0]
+	method = strings.SplitN(method, "-", 2)[0]
+	switch method {
+	case "pbkdf2", "scrypt", "argon2", "argon2i", "argon2id", "md5", "sha1", "sha256", "sha512", "plain":
+		return true
+	default:
+		return false
+	}
+}
+
+func countMapping(counts *MigrationCounts, mapping Mapping) {
+	switch mapping.Status {
+	case StatusCreated:
+		counts.Created++
+	case StatusAlreadyMigrated:
+		counts.AlreadyMigrated++
+	case StatusConflict:
+		counts.Conflicts++
+	case StatusSkipped:
+		counts.Skipped++
+	}
+	if mapping.PasswordResetRequired {
+		counts.PasswordResets++
+	}
+}
@@ -0,0 +1,234 @@
+package legacy
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	_ "modernc.org/sqlite"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+var now = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestReadDatabase(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec(`
+		CREATE TABLE users (id INTEGER PRIMARY KEY, username VARCHAR(80), password VARCHAR(80));
+		CREATE TABLE favorites (id INTEGER PRIMARY KEY, drink_id INTEGER, user_id INTEGER REFERENCES users(id));
+		INSERT INTO users VALUES (2, 'user2', 'password2'), (1, 'user1', NULL);
+		INSERT INTO favorites VALUES (1, 11007, 1), (2, NULL, 2), (3, 11000, NULL);
+	`)
+	assert.NoError(t, err)
+
+	database, err := ReadDatabase(db)
+	assert.NoError(t, err)
+	assert.Equal(t, Database{
+		Users: []User{
+			{Id: 1, Username: "user1"},
+			{Id: 2, Username: "user2", Password: "password2"},
+		},
+		Favorites: []Favorite{
+			{Id: 1, UserId: 1, DrinkId: 11007},
+			{Id: 2, UserId: 2, DrinkId: -1},
+			{Id: 3, UserId: -1, DrinkId: 11000},
+		},
+	}, database)
+}
+
+func TestMigrator_Migrate(t *testing.T) {
+	userId := NewUserId(1)
+	favoriteId := service.NewFavoriteId(userId, "11007")
+	database := Database{
+		Users:     []User{{Id: 1, Username: "user1", Password: "password1"}},
+		Favorites: []Favorite{{Id: 1, UserId: 1, DrinkId: 11007}},
+	}
+	tests := []struct {
+		name           string
+		database       Database
+		dryRun         bool
+		mockCalls      func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository)
+		expectedReport Report
+	}{
+		{
+			name:     "Records are created with hashed passwords",
+			database: database,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", userId).Return(nil, nil)
+				users.On("FindUserByUsername", "user1").Return(nil, nil)
+				users.On("CreateNewUser", mock.MatchedBy(func(user model.User) bool {
+					return user.Id == userId && user.Username == "user1" && user.Version == 1 && user.CreatedAt.Equal(now) &&
+						bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("password1")) == nil
+				})).Return(nil)
+				favorites.On("FindFavoriteById", favoriteId).Return(nil, nil)
+				favorites.On("CreateNewFavorite", model.Favorite{
+					Id:        favoriteId,
+					UserId:    userId,
+					DrinkId:   "11007",
+					CreatedAt: now,
+					UpdatedAt: now,
+					Version:   1,
+				}).Return(nil)
+			},
+			expectedReport: Report{
+				Users:     MigrationCounts{Created: 1},
+				Favorites: MigrationCounts{Created: 1},
+				Mappings: []Mapping{
+					{Table: "users", LegacyId: 1, Id: userId, Status: StatusCreated},
+					{Table: "favorites", LegacyId: 1, Id: favoriteId, Status: StatusCreated},
+				},
+			},
+		},
+		{
+			name:     "Dry run doesn't write anything",
+			database: database,
+			dryRun:   true,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", userId).Return(nil, nil)
+				users.On("FindUserByUsername", "user1").Return(nil, nil)
+				favorites.On("FindFavoriteById", favoriteId).Return(nil, nil)
+			},
+			expectedReport: Report{
+				Users:     MigrationCounts{Created: 1},
+				Favorites: MigrationCounts{Created: 1},
+				Mappings: []Mapping{
+					{Table: "users", LegacyId: 1, Id: userId, Status: StatusCreated},
+					{Table: "favorites", LegacyId: 1, Id: favoriteId, Status: StatusCreated},
+				},
+			},
+		},
+		{
+			name:     "Records that were already migrated are kept",
+			database: database,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", userId).Return(&model.User{Id: userId, Username: "user1"}, nil)
+				favorites.On("FindFavoriteById", favoriteId).Return(&model.Favorite{Id: favoriteId}, nil)
+			},
+			expectedReport: Report{
+				Users:     MigrationCounts{AlreadyMigrated: 1},
+				Favorites: MigrationCounts{AlreadyMigrated: 1},
+				Mappings: []Mapping{
+					{Table: "users", LegacyId: 1, Id: userId, Status: StatusAlreadyMigrated},
+					{Table: "favorites", LegacyId: 1, Id: favoriteId, Status: StatusAlreadyMigrated},
+				},
+			},
+		},
+		{
+			name:     "Username conflicts skip the user's favorites",
+			database: database,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", userId).Return(nil, nil)
+				users.On("FindUserByUsername", "user1").Return(&model.User{Id: "other"}, nil)
+			},
+			expectedReport: Report{
+				Users:     MigrationCounts{Conflicts: 1},
+				Favorites: MigrationCounts{Skipped: 1},
+				Mappings: []Mapping{
+					{Table: "users", LegacyId: 1, Status: StatusConflict, Reason: "the username belongs to user other"},
+					{Table: "favorites", LegacyId: 1, Status: StatusSkipped, Reason: "the favorite's user wasn't migrated"},
+				},
+			},
+		},
+		{
+			name: "Duplicate legacy records and unverifiable passwords",
+			database: Database{
+				Users: []User{
+					{Id: 1, Username: "user1", Password: "pbkdf2:sha256:260000$salt$0123abcd"},
+					{Id: 2, Username: "user1", Password: "password2"},
+				},
+				Favorites: []Favorite{
+					{Id: 1, UserId: 1, DrinkId: 11007},
+					{Id: 2, UserId: 1, DrinkId: 11007},
+				},
+			},
+			dryRun: true,
+			mockCalls: func(users *repository.MockUserRepository, favorites *repository.MockFavoriteRepository) {
+				users.On("FindUserById", userId).Return(nil, nil)
+				users.On("FindUserById", NewUserId(2)).Return(nil, nil)
+				users.On("FindUserByUsername", "user1").Return(nil, nil).Once()
+				favorites.On("FindFavoriteById", favoriteId).Return(nil, nil).Once()
+			},
+			expectedReport: Report{
+				Users:     MigrationCounts{Created: 1, Conflicts: 1, PasswordResets: 1},
+				Favorites: MigrationCounts{Created: 1, Conflicts: 1},
+				Mappings: []Mapping{
+					{Table: "users", LegacyId: 1, Id: userId, Status: StatusCreated, PasswordResetRequired: true},
+					{Table: "users", LegacyId: 2, Status: StatusConflict, Reason: "another legacy user has the same username"},
+					{Table: "favorites", LegacyId: 1, Id: favoriteId, Status: StatusCreated},
+					{Table: "favorites", LegacyId: 2, Id: favoriteId, Status: StatusConflict, Reason: "another legacy favorite has the same user and drink"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := repository.NewMockUserRepository(t)
+			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+			tt.mockCalls(mockUserRepo, mockFavoriteRepo)
+
+			migrator := NewMigrator(mockUserRepo, mockFavoriteRepo, tt.dryRun).WithClock(fixedClock(now))
+			report, err := migrator.Migrate(tt.database)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
+
+func TestMigratePassword(t *testing.T) {
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("password"), 8)
+	tests := []struct {
+		name          string
+		password      string
+		keepsPassword bool
+		resetRequired bool
+	}{
+		{name: "Plaintext passwords are hashed", password: "password"},
+		{name: "Bcrypt hashes are kept", password: string(bcryptHash), keepsPassword: true},
+		{name: "Empty passwords require a reset", password: "", resetRequired: true},
+		{name: "Werkzeug hashes require a reset", password: "pbkdf2:sha256:260000$abc$0123", resetRequired: true},
+		{name: "Passlib hashes require a reset", password: "$pbkdf2-sha256$29000$abc$0123", resetRequired: true},
+		{name: "Passwords containing dollar signs are hashed", password: "pa$$word"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, resetRequired, err := migratePassword(tt.password)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.resetRequired, resetRequired)
+			switch {
+			case tt.resetRequired:
+				assert.Empty(t, hash)
+			case tt.keepsPassword:
+				assert.Equal(t, tt.password, hash)
+			default:
+				assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte(tt.password)))
+			}
+		})
+	}
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	report := Report{Mappings: []Mapping{
+		{Table: "users", LegacyId: 1, Id: "user0", Status: StatusCreated, PasswordResetRequired: true},
+		{Table: "favorites", LegacyId: 2, Status: StatusSkipped, Reason: "the favorite's user wasn't migrated"},
+	}}
+	var buffer bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buffer))
+	assert.Equal(t, "table,legacy_id,id,status,password_reset_required,reason\n"+
+		"users,1,user0,created,true,\n"+
+		"favorites,2,,skipped,false,the favorite's user wasn't migrated\n", buffer.String())
+}
@@ -0,0 +1,32 @@
+package legacy
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+var reportHeader = []string{"table", "legacy_id", "id", "status", "password_reset_required", "reason"}
+
+// WriteCSV writes the id mapping of every legacy record, one row per record
+func (r Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportHeader); err != nil {
+		return err
+	}
+	for _, mapping := range r.Mappings {
+		err := writer.Write([]string{
+			mapping.Table,
+			strconv.FormatInt(mapping.LegacyId, 10),
+			mapping.Id,
+			string(mapping.Status),
+			strconv.FormatBool(mapping.PasswordResetRequired),
+			mapping.Reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
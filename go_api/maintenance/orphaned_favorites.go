@@ -0,0 +1,111 @@
+// Package maintenance contains jobs that repair records the api can't keep consistent by itself
+package maintenance
+
+import (
+	"encoding/csv"
+	"io"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+)
+
+// OrphanReport lists the favorites whose drink or recipe no longer exists
+type OrphanReport struct {
+	// Checked is the number of favorites that were checked
+	Checked  int
+	Orphaned []model.Favorite
+	// Removed is the number of orphaned favorites that were deleted
+	Removed int
+}
+
+// OrphanedFavoriteCleaner finds favorites of drinks that were removed from the catalog,
+// and of users' recipes that were deleted or that their favorite's user can no longer see
+type OrphanedFavoriteCleaner struct {
+	favorites service.FavoriteService
+	drinks    service.DrinkLookup
+	recipes   service.RecipeService
+	remove    bool
+}
+
+// NewOrphanedFavoriteCleaner creates a cleaner that only reports the orphaned favorites, unless remove is set;
+// favorites are removed through the service, so their deletion is recorded like any other
+func NewOrphanedFavoriteCleaner(favorites service.FavoriteService, drinks service.DrinkLookup, recipes service.RecipeService, remove bool) OrphanedFavoriteCleaner {
+	return OrphanedFavoriteCleaner{
+		favorites: favorites,
+		drinks:    drinks,
+		recipes:   recipes,
+		remove:    remove,
+	}
+}
+
+// recipeFavorite is a recipe as seen by a user, since whether a user can see a recipe depends on the user
+type recipeFavorite struct {
+	userId   string
+	recipeId string
+}
+
+// Clean checks the drink or recipe of every favorite, like new favorites are checked: drinks with the lookup and
+// recipes against the recipes that the favorite's user can see; each drink, and each recipe for each user,
+// is only looked up once
+func (c OrphanedFavoriteCleaner) Clean() (OrphanReport, error) {
+	report := OrphanReport{}
+	favorites, err := c.favorites.FindAllFavorites()
+	if err != nil {
+		return report, err
+	}
+
+	drinkExists := map[string]bool{}
+	recipeExists := map[recipeFavorite]bool{}
+	for _, favorite := range favorites {
+		report.Checked++
+		var exists, checked bool
+		if model.IsRecipeId(favorite.DrinkId) {
+			key := recipeFavorite{userId: favorite.UserId, recipeId: favorite.DrinkId}
+			if exists, checked = recipeExists[key]; !checked {
+				recipe, err := c.recipes.FindRecipe(favorite.UserId, favorite.DrinkId)
+				if err != nil {
+					return report, err
+				}
+				exists = recipe != nil
+				recipeExists[key] = exists
+			}
+		} else if exists, checked = drinkExists[favorite.DrinkId]; !checked {
+			exists, err = c.drinks.DrinkExists(favorite.DrinkId)
+			if err != nil {
+				return report, err
+			}
+			drinkExists[favorite.DrinkId] = exists
+		}
+		if exists {
+			continue
+		}
+
+		report.Orphaned = append(report.Orphaned, favorite)
+		if !c.remove {
+			continue
+		}
+		// deleting a favorite that its user deleted since it was found isn't an error
+		if err := c.favorites.DeleteFavorite(favorite.Id); err != nil {
+			return report, err
+		}
+		report.Removed++
+	}
+	return report, nil
+}
+
+var orphanReportHeader = []string{"id", "user_id", "drink_id"}
+
+// WriteCSV writes one row per orphaned favorite
+func (r OrphanReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(orphanReportHeader); err != nil {
+		return err
+	}
+	for _, favorite := range r.Orphaned {
+		if err := writer.Write([]string{favorite.Id, favorite.UserId, favorite.DrinkId}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
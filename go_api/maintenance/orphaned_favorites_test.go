@@ -0,0 +1,131 @@
+package maintenance
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrphanedFavoriteCleaner_Clean(t *testing.T) {
+	favorites := []model.Favorite{
+		{Id: "0", UserId: "user0", DrinkId: "11007"},
+		{Id: "1", UserId: "user0", DrinkId: "banana"},
+		{Id: "2", UserId: "user1", DrinkId: "banana"},
+	}
+	// recipe-0 is private to user0, and recipe-1 was deleted; recipe ids are never looked up as drinks
+	recipeFavorites := []model.Favorite{
+		{Id: "0", UserId: "user0", DrinkId: "recipe-0"},
+		{Id: "1", UserId: "user0", DrinkId: "11007"},
+		{Id: "2", UserId: "user1", DrinkId: "recipe-0"},
+		{Id: "3", UserId: "user1", DrinkId: "recipe-1"},
+	}
+	tests := []struct {
+		name           string
+		remove         bool
+		mockCalls      func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService)
+		expectedReport OrphanReport
+		expectError    bool
+	}{
+		{
+			name: "Reports the orphaned favorites",
+			mockCalls: func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService) {
+				favoriteService.On("FindAllFavorites").Return(favorites, nil)
+				drinkLookup.On("DrinkExists", "11007").Return(true, nil).Once()
+				drinkLookup.On("DrinkExists", "banana").Return(false, nil).Once()
+			},
+			expectedReport: OrphanReport{Checked: 3, Orphaned: favorites[1:]},
+		},
+		{
+			name:   "Removes the orphaned favorites",
+			remove: true,
+			mockCalls: func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService) {
+				favoriteService.On("FindAllFavorites").Return(favorites, nil)
+				favoriteService.On("DeleteFavorite", "1").Return(nil)
+				favoriteService.On("DeleteFavorite", "2").Return(nil)
+				drinkLookup.On("DrinkExists", "11007").Return(true, nil).Once()
+				drinkLookup.On("DrinkExists", "banana").Return(false, nil).Once()
+			},
+			expectedReport: OrphanReport{Checked: 3, Orphaned: favorites[1:], Removed: 2},
+		},
+		{
+			name:   "Checks recipe favorites against the recipes that their user can see",
+			remove: true,
+			mockCalls: func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService) {
+				favoriteService.On("FindAllFavorites").Return(recipeFavorites, nil)
+				favoriteService.On("DeleteFavorite", "2").Return(nil)
+				favoriteService.On("DeleteFavorite", "3").Return(nil)
+				drinkLookup.On("DrinkExists", "11007").Return(true, nil).Once()
+				recipeService.On("FindRecipe", "user0", "recipe-0").Return(&model.Recipe{Id: "recipe-0", OwnerId: "user0"}, nil).Once()
+				recipeService.On("FindRecipe", "user1", "recipe-0").Return(nil, nil).Once()
+				recipeService.On("FindRecipe", "user1", "recipe-1").Return(nil, nil).Once()
+			},
+			expectedReport: OrphanReport{Checked: 4, Orphaned: recipeFavorites[2:], Removed: 2},
+		},
+		{
+			name: "Failed to look up a recipe",
+			mockCalls: func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService) {
+				favoriteService.On("FindAllFavorites").Return(recipeFavorites, nil)
+				recipeService.On("FindRecipe", "user0", "recipe-0").Return(nil, errors.New("failed"))
+			},
+			expectedReport: OrphanReport{Checked: 1},
+			expectError:    true,
+		},
+		{
+			name: "Failed to find the favorites",
+			mockCalls: func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService) {
+				favoriteService.On("FindAllFavorites").Return(nil, errors.New("failed"))
+			},
+			expectError: true,
+		},
+		{
+			name: "Failed to look up a drink",
+			mockCalls: func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService) {
+				favoriteService.On("FindAllFavorites").Return(favorites, nil)
+				drinkLookup.On("DrinkExists", "11007").Return(false, errors.New("failed"))
+			},
+			expectedReport: OrphanReport{Checked: 1},
+			expectError:    true,
+		},
+		{
+			name:   "Failed to remove a favorite",
+			remove: true,
+			mockCalls: func(favoriteService *service.MockFavoriteService, drinkLookup *service.MockDrinkLookup, recipeService *service.MockRecipeService) {
+				favoriteService.On("FindAllFavorites").Return(favorites, nil)
+				favoriteService.On("DeleteFavorite", "1").Return(errors.New("failed"))
+				drinkLookup.On("DrinkExists", "11007").Return(true, nil)
+				drinkLookup.On("DrinkExists", "banana").Return(false, nil)
+			},
+			expectedReport: OrphanReport{Checked: 2, Orphaned: favorites[1:2]},
+			expectError:    true,
+		},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			favoriteService := service.NewMockFavoriteService(t)
+			drinkLookup := service.NewMockDrinkLookup(t)
+			recipeService := service.NewMockRecipeService(t)
+			d.mockCalls(favoriteService, drinkLookup, recipeService)
+
+			report, err := NewOrphanedFavoriteCleaner(favoriteService, drinkLookup, recipeService, d.remove).Clean()
+			if d.expectError {
+				assert.Error(t, err, "An error should have been returned from Clean")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from Clean")
+			}
+			assert.Equal(t, d.expectedReport, report, "The report doesn't match the orphaned favorites")
+		})
+	}
+}
+
+func TestOrphanReport_WriteCSV(t *testing.T) {
+	report := OrphanReport{Orphaned: []model.Favorite{{Id: "1", UserId: "user0", DrinkId: "banana"}}}
+	var buffer bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&buffer), "No error should have been returned from WriteCSV")
+	assert.Equal(t, "id,user_id,drink_id\n1,user0,banana\n", buffer.String())
+}
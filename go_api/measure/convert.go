@@ -0,0 +1,83 @@
+package measure
+
+import "math"
+
+// PartMilliliters is the volume of a part when a recipe that's written in parts has to be measured;
+// a part is only relative to the recipe's other parts, so it's taken to be an ounce
+const PartMilliliters = 29.5735
+
+// System is a system of units that recipes are rendered in; AsWritten keeps the units of the recipe
+type System string
+
+const (
+	AsWritten System = ""
+	Metric    System = "metric"
+	Imperial  System = "imperial"
+)
+
+// ParseSystem reads the name of a system, e.g. "metric"; ok is false for an unknown system
+func ParseSystem(name string) (System, bool) {
+	switch system := System(name); system {
+	case AsWritten, Metric, Imperial:
+		return system, true
+	}
+	return AsWritten, false
+}
+
+// metricUnits are the volumes that are already metric; every other volume is imperial
+var metricUnits = map[Unit]bool{Milliliter: true, Centiliter: true, Liter: true}
+
+// Scale multiplies the amount by the factor, e.g. for a number of servings
+func (q Quantity) Scale(factor float64) Quantity {
+	q.Amount *= factor
+	return q
+}
+
+// Convert converts a volume to another unit of volume, e.g. 1 oz to 29.57 ml; parts are PartMilliliters.
+// ok is false if either unit isn't a volume or a part
+func Convert(q Quantity, unit Unit) (Quantity, bool) {
+	from, ok := convertibleMilliliters(q.Unit)
+	if !ok {
+		return q, false
+	}
+	to, ok := convertibleMilliliters(unit)
+	if !ok {
+		return q, false
+	}
+	return Quantity{Amount: q.Amount * from / to, Unit: unit}, true
+}
+
+func convertibleMilliliters(unit Unit) (float64, bool) {
+	if unit == Part {
+		return PartMilliliters, true
+	}
+	factor, ok := milliliters[unit]
+	return factor, ok
+}
+
+// In converts a volume to the system the way that a bartender would measure it:
+// imperial volumes become milliliters rounded to 5 ml from 10 ml up, so that 1 oz is 30 ml,
+// and metric volumes become ounces rounded to a quarter ounce, or teaspoons below a quarter ounce.
+// Parts, units that aren't volumes and volumes that are already in the system are unchanged
+func (q Quantity) In(system System) Quantity {
+	if !q.IsVolume() {
+		return q
+	}
+	switch {
+	case system == Metric && !metricUnits[q.Unit]:
+		converted, _ := Convert(q, Milliliter)
+		if converted.Amount >= 10 {
+			converted.Amount = math.Round(converted.Amount/5) * 5
+		}
+		return converted
+	case system == Imperial && metricUnits[q.Unit]:
+		converted, _ := Convert(q, Ounce)
+		if converted.Amount < 0.25 {
+			converted, _ = Convert(q, Teaspoon)
+			return converted
+		}
+		converted.Amount = math.Round(converted.Amount*4) / 4
+		return converted
+	}
+	return q
+}
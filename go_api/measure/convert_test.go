@@ -0,0 +1,72 @@
+package measure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		testName         string
+		quantity         Quantity
+		unit             Unit
+		expectedQuantity Quantity
+		expectOk         bool
+	}{
+		{testName: "Ounces to milliliters", quantity: Quantity{Amount: 2, Unit: Ounce}, unit: Milliliter, expectedQuantity: Quantity{Amount: 59.147, Unit: Milliliter}, expectOk: true},
+		{testName: "Centiliters to ounces", quantity: Quantity{Amount: 3, Unit: Centiliter}, unit: Ounce, expectedQuantity: Quantity{Amount: 1.0144, Unit: Ounce}, expectOk: true},
+		{testName: "Tablespoons to teaspoons", quantity: Quantity{Amount: 1, Unit: Tablespoon}, unit: Teaspoon, expectedQuantity: Quantity{Amount: 3, Unit: Teaspoon}, expectOk: true},
+		{testName: "Parts to milliliters", quantity: Quantity{Amount: 2, Unit: Part}, unit: Milliliter, expectedQuantity: Quantity{Amount: 59.147, Unit: Milliliter}, expectOk: true},
+		{testName: "Milliliters to parts", quantity: Quantity{Amount: 15, Unit: Milliliter}, unit: Part, expectedQuantity: Quantity{Amount: 0.5072, Unit: Part}, expectOk: true},
+		{testName: "A dash isn't a volume", quantity: Quantity{Amount: 2, Unit: Dash}, unit: Milliliter, expectedQuantity: Quantity{Amount: 2, Unit: Dash}},
+		{testName: "A slice isn't a volume", quantity: Quantity{Amount: 1, Unit: Ounce}, unit: "slice", expectedQuantity: Quantity{Amount: 1, Unit: Ounce}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			quantity, ok := Convert(tt.quantity, tt.unit)
+			assert.Equal(t, tt.expectOk, ok)
+			assert.InDelta(t, tt.expectedQuantity.Amount, quantity.Amount, 0.001)
+			assert.Equal(t, tt.expectedQuantity.Unit, quantity.Unit)
+		})
+	}
+}
+
+func TestQuantity_In(t *testing.T) {
+	tests := []struct {
+		testName         string
+		quantity         Quantity
+		system           System
+		expectedQuantity Quantity
+	}{
+		{testName: "Ounces are rounded to 5 ml", quantity: Quantity{Amount: 1.5, Unit: Ounce}, system: Metric, expectedQuantity: Quantity{Amount: 45, Unit: Milliliter}},
+		{testName: "Small volumes aren't rounded to 5 ml", quantity: Quantity{Amount: 1, Unit: Teaspoon}, system: Metric, expectedQuantity: Quantity{Amount: 4.9289, Unit: Milliliter}},
+		{testName: "Centiliters are already metric", quantity: Quantity{Amount: 2, Unit: Centiliter}, system: Metric, expectedQuantity: Quantity{Amount: 2, Unit: Centiliter}},
+		{testName: "Milliliters are rounded to a quarter ounce", quantity: Quantity{Amount: 50, Unit: Milliliter}, system: Imperial, expectedQuantity: Quantity{Amount: 1.75, Unit: Ounce}},
+		{testName: "Small volumes become teaspoons", quantity: Quantity{Amount: 5, Unit: Milliliter}, system: Imperial, expectedQuantity: Quantity{Amount: 1.0144, Unit: Teaspoon}},
+		{testName: "Tablespoons are already imperial", quantity: Quantity{Amount: 1, Unit: Tablespoon}, system: Imperial, expectedQuantity: Quantity{Amount: 1, Unit: Tablespoon}},
+		{testName: "Parts are unchanged", quantity: Quantity{Amount: 2, Unit: Part}, system: Metric, expectedQuantity: Quantity{Amount: 2, Unit: Part}},
+		{testName: "Dashes are unchanged", quantity: Quantity{Amount: 2, Unit: Dash}, system: Imperial, expectedQuantity: Quantity{Amount: 2, Unit: Dash}},
+		{testName: "Nothing is converted as written", quantity: Quantity{Amount: 30, Unit: Milliliter}, system: AsWritten, expectedQuantity: Quantity{Amount: 30, Unit: Milliliter}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			quantity := tt.quantity.In(tt.system)
+			assert.InDelta(t, tt.expectedQuantity.Amount, quantity.Amount, 0.001)
+			assert.Equal(t, tt.expectedQuantity.Unit, quantity.Unit)
+		})
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+	system, ok := ParseSystem("imperial")
+	assert.True(t, ok)
+	assert.Equal(t, Imperial, system)
+
+	system, ok = ParseSystem("")
+	assert.True(t, ok)
+	assert.Equal(t, AsWritten, system)
+
+	_, ok = ParseSystem("Imperial")
+	assert.False(t, ok, "The names of the systems should be lowercase")
+}
@@ -0,0 +1,249 @@
+// Package measure parses the free text measures of recipes, such as "1 1/2 oz" or "2 cl", into quantities.
+//
+// Volumes are converted to milliliters so that the same ingredient can be added up across recipes
+// that measure it differently; units that aren't volumes, like dashes or slices, are only added to themselves.
+// The quantities can also be scaled and converted to the metric or imperial system (see convert.go)
+// and rendered again the way a recipe writes them (see render.go).
+package measure
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Unit is the normalized unit of a quantity; Count is for things that are counted rather than measured, like "2"
+type Unit string
+
+const (
+	Count      Unit = ""
+	Milliliter Unit = "ml"
+	Centiliter Unit = "cl"
+	Liter      Unit = "l"
+	Ounce      Unit = "oz"
+	Teaspoon   Unit = "tsp"
+	Tablespoon Unit = "tbsp"
+	Cup        Unit = "cup"
+	Shot       Unit = "shot"
+	Jigger     Unit = "jigger"
+	Dash       Unit = "dash"
+	Splash     Unit = "splash"
+	Drop       Unit = "drop"
+	Pinch      Unit = "pinch"
+	Part       Unit = "part"
+	// Juice is the juice of a number of fruit, e.g. "Juice of 1/2" a lemon
+	Juice Unit = "juice"
+)
+
+// milliliters is the volume of each unit that can be converted; dashes, splashes and drops are too vague to add up
+var milliliters = map[Unit]float64{
+	Milliliter: 1,
+	Centiliter: 10,
+	Liter:      1000,
+	Ounce:      29.5735,
+	Teaspoon:   4.92892,
+	Tablespoon: 14.7868,
+	Cup:        236.588,
+	Shot:       44.3603,
+	Jigger:     44.3603,
+}
+
+// unitNames maps the ways that recipes write the units to the units
+var unitNames = map[string]Unit{
+	"ml": Milliliter, "milliliter": Milliliter, "milliliters": Milliliter, "millilitre": Milliliter, "millilitres": Milliliter,
+	"cl": Centiliter, "centiliter": Centiliter, "centiliters": Centiliter, "centilitre": Centiliter, "centilitres": Centiliter,
+	"l": Liter, "liter": Liter, "liters": Liter, "litre": Liter, "litres": Liter,
+	"oz": Ounce, "ounce": Ounce, "ounces": Ounce, "fl oz": Ounce, "fl. oz": Ounce,
+	"tsp": Teaspoon, "teaspoon": Teaspoon, "teaspoons": Teaspoon, "t": Teaspoon,
+	"tbsp": Tablespoon, "tblsp": Tablespoon, "tbl": Tablespoon, "tablespoon": Tablespoon, "tablespoons": Tablespoon,
+	"cup": Cup, "cups": Cup,
+	"shot": Shot, "shots": Shot,
+	"jigger": Jigger, "jiggers": Jigger,
+	"dash": Dash, "dashes": Dash,
+	"splash": Splash, "splashes": Splash,
+	"drop": Drop, "drops": Drop,
+	"pinch": Pinch, "pinches": Pinch,
+	"part": Part, "parts": Part,
+}
+
+// unicodeFractions are the fractions that some recipes write as a single character
+var unicodeFractions = map[rune]float64{'¼': 0.25, '½': 0.5, '¾': 0.75, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '⅛': 0.125}
+
+// Quantity is an amount of a unit; units that aren't in the unit table keep the word from the recipe, e.g. "slice"
+type Quantity struct {
+	Amount float64
+	Unit   Unit
+}
+
+// IsVolume reports whether the quantity can be converted to milliliters
+func (q Quantity) IsVolume() bool {
+	_, ok := milliliters[q.Unit]
+	return ok
+}
+
+// Milliliters converts a volume to milliliters; ok is false if the unit isn't a volume
+func (q Quantity) Milliliters() (amount float64, ok bool) {
+	factor, ok := milliliters[q.Unit]
+	return q.Amount * factor, ok
+}
+
+// ParseUnit normalizes the name of a unit, e.g. "Tablespoons" is Tablespoon;
+// ok is false if it isn't one of the known units
+func ParseUnit(name string) (Unit, bool) {
+	unit, ok := unitNames[strings.ToLower(strings.Join(strings.Fields(name), " "))]
+	return unit, ok
+}
+
+// Parse reads a measure that starts with an amount, such as "1 1/2 oz", "2-3 dashes" or "1 slice";
+// a known unit without an amount, like "dash", is one of that unit, and a range is its upper bound.
+// "Juice of 1/2" is half of a fruit's Juice.
+// ok is false for measures that don't have an amount, like "to taste"
+func Parse(text string) (quantity Quantity, ok bool) {
+	quantity, _, ok = parse(text)
+	return quantity, ok
+}
+
+// parse is Parse, which also returns the words after the unit as they're written, e.g. "of orange" in "1 slice of orange"
+func parse(text string) (Quantity, []string, bool) {
+	written := strings.Fields(separateNumbers(strings.ReplaceAll(text, "–", "-")))
+	fields := make([]string, len(written))
+	for i, field := range written {
+		fields[i] = strings.ToLower(field)
+	}
+	remainder := func(rest []string) []string {
+		return written[len(written)-len(rest):]
+	}
+
+	if len(fields) > 2 && fields[0] == "juice" && fields[1] == "of" {
+		amount, rest, ok := parseAmount(fields[2:])
+		if !ok && (fields[2] == "a" || fields[2] == "an") {
+			amount, rest, ok = 1, fields[3:], true
+		}
+		if !ok {
+			return Quantity{}, nil, false
+		}
+		return Quantity{Amount: amount, Unit: Juice}, remainder(rest), true
+	}
+
+	amount, rest, ok := parseAmount(fields)
+	if !ok {
+		if len(fields) > 0 {
+			if unit, known := unitNames[fields[0]]; known {
+				return Quantity{Amount: 1, Unit: unit}, remainder(fields[1:]), true
+			}
+		}
+		return Quantity{}, nil, false
+	}
+	if len(rest) == 0 {
+		return Quantity{Amount: amount, Unit: Count}, nil, true
+	}
+	if len(rest) > 1 {
+		if unit, known := unitNames[rest[0]+" "+rest[1]]; known {
+			return Quantity{Amount: amount, Unit: unit}, remainder(rest[2:]), true
+		}
+	}
+	if unit, known := unitNames[rest[0]]; known {
+		return Quantity{Amount: amount, Unit: unit}, remainder(rest[1:]), true
+	}
+	return Quantity{Amount: amount, Unit: Unit(strings.Trim(rest[0], ".,()"))}, remainder(rest[1:]), true
+}
+
+// separateNumbers splits numbers from the words that they're written against, e.g. "30ml" and "½oz"
+func separateNumbers(text string) string {
+	var builder strings.Builder
+	var previous rune
+	for i, r := range text {
+		if i > 0 && unicode.IsLetter(r) && (unicode.IsDigit(previous) || unicodeFractions[previous] != 0) {
+			builder.WriteRune(' ')
+		}
+		if _, ok := unicodeFractions[r]; ok && i > 0 && unicode.IsDigit(previous) {
+			builder.WriteRune(' ')
+		}
+		builder.WriteRune(r)
+		previous = r
+	}
+	return builder.String()
+}
+
+// parseAmount reads a whole number, decimal, fraction, mixed number ("1 1/2") or range ("1-2") from the start of fields
+func parseAmount(fields []string) (float64, []string, bool) {
+	if len(fields) == 0 {
+		return 0, fields, false
+	}
+	first := fields[0]
+	if lower, upper, found := strings.Cut(first, "-"); found {
+		// for a range, enough of the ingredient is needed for the upper bound
+		if _, ok := parseNumber(lower); ok {
+			if upper, ok := parseNumber(upper); ok {
+				return upper, fields[1:], true
+			}
+		}
+	}
+	amount, ok := parseNumber(first)
+	if !ok {
+		return 0, fields, false
+	}
+	rest := fields[1:]
+	if len(rest) > 0 && strings.ContainsAny(rest[0], "/¼½¾⅓⅔⅛") {
+		if fraction, ok := parseNumber(rest[0]); ok && fraction < 1 {
+			amount += fraction
+			rest = rest[1:]
+		}
+	}
+	if len(rest) > 1 && (rest[0] == "-" || rest[0] == "to" || rest[0] == "or") {
+		if upper, ok := parseNumber(rest[1]); ok {
+			amount = upper
+			rest = rest[2:]
+		}
+	}
+	return amount, rest, true
+}
+
+// parseNumber reads a whole number, decimal, fraction or unicode fraction
+func parseNumber(text string) (float64, bool) {
+	runes := []rune(text)
+	if len(runes) == 1 {
+		if fraction, ok := unicodeFractions[runes[0]]; ok {
+			return fraction, true
+		}
+	}
+	if numerator, denominator, found := strings.Cut(text, "/"); found {
+		n, err := strconv.ParseFloat(numerator, 64)
+		if err != nil {
+			return 0, false
+		}
+		d, err := strconv.ParseFloat(denominator, 64)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	// ParseFloat would also accept words like "inf" and "nan"
+	if text == "" || !(unicode.IsDigit(runes[0]) || runes[0] == '.') {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// String formats the quantity with at most two decimals, e.g. "1.5 oz", or just the amount for a Count
+func (q Quantity) String() string {
+	amount := formatDecimal(q.Amount, 2)
+	switch q.Unit {
+	case Count:
+		return amount
+	case Juice:
+		return "juice of " + amount
+	}
+	return amount + " " + string(q.Unit)
+}
+
+// formatDecimal formats the amount with at most the given number of decimals, e.g. "1.5" rather than "1.50"
+func formatDecimal(amount float64, decimals int) string {
+	scale := math.Pow(10, float64(decimals))
+	return strconv.FormatFloat(math.Round(amount*scale)/scale, 'f', -1, 64)
+}
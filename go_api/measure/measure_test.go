@@ -0,0 +1,70 @@
+package measure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text             string
+		expectedQuantity Quantity
+		expectOk         bool
+	}{
+		{text: "1 1/2 oz", expectedQuantity: Quantity{Amount: 1.5, Unit: Ounce}, expectOk: true},
+		{text: "2 cl ", expectedQuantity: Quantity{Amount: 2, Unit: Centiliter}, expectOk: true},
+		{text: "30ml", expectedQuantity: Quantity{Amount: 30, Unit: Milliliter}, expectOk: true},
+		{text: "1½ oz", expectedQuantity: Quantity{Amount: 1.5, Unit: Ounce}, expectOk: true},
+		{text: ".5 fl oz", expectedQuantity: Quantity{Amount: 0.5, Unit: Ounce}, expectOk: true},
+		{text: "2-3 dashes", expectedQuantity: Quantity{Amount: 3, Unit: Dash}, expectOk: true},
+		{text: "1 or 2 tsp", expectedQuantity: Quantity{Amount: 2, Unit: Teaspoon}, expectOk: true},
+		{text: "1 tblsp", expectedQuantity: Quantity{Amount: 1, Unit: Tablespoon}, expectOk: true},
+		{text: "Dash", expectedQuantity: Quantity{Amount: 1, Unit: Dash}, expectOk: true},
+		{text: "2 slices", expectedQuantity: Quantity{Amount: 2, Unit: "slices"}, expectOk: true},
+		{text: "3", expectedQuantity: Quantity{Amount: 3, Unit: Count}, expectOk: true},
+		{text: "to taste"},
+		{text: "Juice of 1/2", expectedQuantity: Quantity{Amount: 0.5, Unit: Juice}, expectOk: true},
+		{text: "Juice of a lime", expectedQuantity: Quantity{Amount: 1, Unit: Juice}, expectOk: true},
+		{text: "Juice of the lemon"},
+		{text: "nan oz"},
+		{text: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			quantity, ok := Parse(tt.text)
+			assert.Equal(t, tt.expectOk, ok)
+			assert.InDelta(t, tt.expectedQuantity.Amount, quantity.Amount, 0.0001)
+			assert.Equal(t, tt.expectedQuantity.Unit, quantity.Unit)
+		})
+	}
+}
+
+func TestParse_KeepsTheWordsAfterTheUnit(t *testing.T) {
+	tests := []struct {
+		text         string
+		expectedRest []string
+	}{
+		{text: "2 Slices of Orange", expectedRest: []string{"of", "Orange"}},
+		{text: "1 1/2 fl oz chilled", expectedRest: []string{"chilled"}},
+		{text: "Dash of Tabasco", expectedRest: []string{"of", "Tabasco"}},
+		{text: "Juice of 1 Lime", expectedRest: []string{"Lime"}},
+		{text: "2 oz", expectedRest: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, rest, ok := parse(tt.text)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expectedRest, rest)
+		})
+	}
+}
+
+func TestQuantity_Milliliters(t *testing.T) {
+	amount, ok := Quantity{Amount: 2, Unit: Ounce}.Milliliters()
+	assert.True(t, ok)
+	assert.InDelta(t, 59.147, amount, 0.001)
+
+	_, ok = Quantity{Amount: 2, Unit: Dash}.Milliliters()
+	assert.False(t, ok, "A dash shouldn't be converted to milliliters")
+}
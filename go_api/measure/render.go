@@ -0,0 +1,82 @@
+package measure
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pluralUnits are the names of the units that are words rather than abbreviations, for more than one of them
+var pluralUnits = map[Unit]string{
+	Cup: "cups", Shot: "shots", Jigger: "jiggers", Dash: "dashes", Splash: "splashes", Drop: "drops", Pinch: "pinches", Part: "parts",
+}
+
+// Format writes the quantity the way a recipe does: metric volumes with at most one decimal, e.g. "22.5 ml",
+// and everything else with fractions, e.g. "1 1/2 oz", "2 dashes" or "juice of 1/2"
+func (q Quantity) Format() string {
+	if metricUnits[q.Unit] {
+		return formatDecimal(q.Amount, 1) + " " + string(q.Unit)
+	}
+	amount := formatFraction(q.Amount)
+	switch q.Unit {
+	case Count:
+		return amount
+	case Juice:
+		return "juice of " + amount
+	}
+	if plural, ok := pluralUnits[q.Unit]; ok && q.Amount > 1 {
+		return amount + " " + plural
+	}
+	return amount + " " + string(q.Unit)
+}
+
+// formatFraction rounds the amount to the nearest quarter or third, whichever is closer, e.g. "1 1/2" or "2/3";
+// an amount that's too small for either keeps two decimals
+func formatFraction(amount float64) string {
+	whole := math.Floor(amount)
+	numerator, denominator := 0.0, 1.0
+	closest := math.Inf(1)
+	for _, d := range []float64{4, 3} {
+		n := math.Round((amount - whole) * d)
+		if difference := math.Abs(amount - whole - n/d); difference < closest {
+			numerator, denominator, closest = n, d, difference
+		}
+	}
+	if numerator == denominator {
+		whole, numerator = whole+1, 0
+	}
+	if denominator == 4 && numerator == 2 {
+		numerator, denominator = 1, 2
+	}
+	switch {
+	case numerator == 0 && whole == 0:
+		return formatDecimal(amount, 2)
+	case numerator == 0:
+		return strconv.FormatFloat(whole, 'f', 0, 64)
+	case whole == 0:
+		return strconv.FormatFloat(numerator, 'f', 0, 64) + "/" + strconv.FormatFloat(denominator, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(whole, 'f', 0, 64) + " " + strconv.FormatFloat(numerator, 'f', 0, 64) + "/" + strconv.FormatFloat(denominator, 'f', 0, 64)
+}
+
+// Render rewrites a recipe's measure for a number of servings in the system, e.g. "1 1/2 oz" for 2 servings
+// in the Metric system is "90 ml"; the words after the unit are kept, e.g. "2 slices of orange".
+// Parts are only relative to each other, so they aren't scaled, and a measure without an amount,
+// like "to taste", is returned as it's written
+func Render(text string, servings int, system System) string {
+	if servings == 1 && system == AsWritten {
+		return text
+	}
+	quantity, rest, ok := parse(text)
+	if !ok {
+		return text
+	}
+	if quantity.Unit != Part {
+		quantity = quantity.Scale(float64(servings))
+	}
+	rendered := quantity.In(system).Format()
+	if len(rest) > 0 {
+		rendered += " " + strings.Join(rest, " ")
+	}
+	return rendered
+}
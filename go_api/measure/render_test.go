@@ -0,0 +1,59 @@
+package measure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantity_Format(t *testing.T) {
+	tests := []struct {
+		quantity     Quantity
+		expectedText string
+	}{
+		{quantity: Quantity{Amount: 1.5, Unit: Ounce}, expectedText: "1 1/2 oz"},
+		{quantity: Quantity{Amount: 0.76, Unit: Ounce}, expectedText: "3/4 oz"},
+		{quantity: Quantity{Amount: 2.32, Unit: Teaspoon}, expectedText: "2 1/3 tsp"},
+		{quantity: Quantity{Amount: 1.95, Unit: Tablespoon}, expectedText: "2 tbsp"},
+		{quantity: Quantity{Amount: 0.1, Unit: Cup}, expectedText: "0.1 cup"},
+		{quantity: Quantity{Amount: 22.5, Unit: Milliliter}, expectedText: "22.5 ml"},
+		{quantity: Quantity{Amount: 4.9289, Unit: Milliliter}, expectedText: "4.9 ml"},
+		{quantity: Quantity{Amount: 3, Unit: Dash}, expectedText: "3 dashes"},
+		{quantity: Quantity{Amount: 1, Unit: Dash}, expectedText: "1 dash"},
+		{quantity: Quantity{Amount: 0.5, Unit: Juice}, expectedText: "juice of 1/2"},
+		{quantity: Quantity{Amount: 2, Unit: Count}, expectedText: "2"},
+		{quantity: Quantity{Amount: 4, Unit: "slices"}, expectedText: "4 slices"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expectedText, func(t *testing.T) {
+			assert.Equal(t, tt.expectedText, tt.quantity.Format())
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		testName     string
+		text         string
+		servings     int
+		system       System
+		expectedText string
+	}{
+		{testName: "Unchanged", text: "1 1/2 oz ", servings: 1, system: AsWritten, expectedText: "1 1/2 oz "},
+		{testName: "Scaled", text: "1 1/2 oz", servings: 3, system: AsWritten, expectedText: "4 1/2 oz"},
+		{testName: "Scaled to metric", text: "1 1/2 oz", servings: 2, system: Metric, expectedText: "90 ml"},
+		{testName: "Imperial", text: "2 cl", servings: 1, system: Imperial, expectedText: "3/4 oz"},
+		{testName: "Imperial teaspoons", text: "5 ml", servings: 1, system: Imperial, expectedText: "1 tsp"},
+		{testName: "Already metric", text: "2 cl", servings: 1, system: Metric, expectedText: "2 cl"},
+		{testName: "Dashes", text: "Dash", servings: 2, system: Metric, expectedText: "2 dashes"},
+		{testName: "Juice", text: "Juice of 1/2", servings: 4, system: Imperial, expectedText: "juice of 2"},
+		{testName: "Words after the unit", text: "1 slice of Orange", servings: 2, system: Metric, expectedText: "2 slice of Orange"},
+		{testName: "Parts aren't scaled", text: "2 parts", servings: 3, system: Metric, expectedText: "2 parts"},
+		{testName: "Without an amount", text: "to taste", servings: 2, system: Metric, expectedText: "to taste"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			assert.Equal(t, tt.expectedText, Render(tt.text, tt.servings, tt.system))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"the-drink-almanac-api/migration"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+)
+
+// runMigrate creates or updates the api's tables and indexes and applies any pending migrations
+func runMigrate(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the planned steps without applying them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ddbClient, err := client.CreateLocalDDBClient(appConfig.AwsEndpoint)
+	if err != nil {
+		return err
+	}
+	migrator := migration.NewMigrator(ddbClient, appConfig)
+
+	ctx := context.TODO()
+	steps, err := migrator.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(steps) == 0 {
+		fmt.Println("the tables are already up to date")
+		return nil
+	}
+
+	for _, step := range steps {
+		if *dryRun {
+			fmt.Println("would", step.Description)
+			continue
+		}
+		fmt.Println(step.Description)
+		if err := step.Run(ctx); err != nil {
+			return fmt.Errorf("failed to %s: %w", step.Description, err)
+		}
+	}
+	return nil
+}
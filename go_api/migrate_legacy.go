@@ -0,0 +1,81 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"the-drink-almanac-api/legacy"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+
+	// a pure Go sqlite driver, so the command works in the image, which is built with CGO_ENABLED=0
+	_ "modernc.org/sqlite"
+)
+
+// runMigrateLegacy copies the users and favorites of the legacy api's SQLite database into the api's tables
+// and writes a report mapping every legacy id to its new id
+func runMigrateLegacy(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("migrate-legacy", flag.ContinueOnError)
+	reportPath := flags.String("report", "-", "the file to write the id-mapping report (csv) to, or '-' for stdout")
+	dryRun := flags.Bool("dry-run", false, "write the report without migrating anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: migrate-legacy [-report <file>] [-dry-run] <sqlite database file>")
+	}
+	path := flags.Arg(0)
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	database, err := legacy.ReadDatabase(db)
+	if err != nil {
+		return err
+	}
+
+	userStore, favoriteStore, err := repository.NewRepositories(appConfig)
+	if err != nil {
+		return err
+	}
+
+	// the report is written even if the migration fails part of the way, so the records that were migrated are known
+	report, migrateErr := legacy.NewMigrator(userStore, favoriteStore, *dryRun).Migrate(database)
+	if err := writeLegacyReport(report, *reportPath); err != nil {
+		return err
+	}
+	if migrateErr != nil {
+		return fmt.Errorf("failed to migrate: %w", migrateErr)
+	}
+
+	verb := "migrated"
+	if *dryRun {
+		verb = "would migrate"
+	}
+	// the report may be written to stdout, so the summary goes to stderr
+	fmt.Fprintf(os.Stderr, "%s users: %+v\n", verb, report.Users)
+	fmt.Fprintf(os.Stderr, "%s favorites: %+v\n", verb, report.Favorites)
+	return nil
+}
+
+func writeLegacyReport(report legacy.Report, path string) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	return report.WriteCSV(w)
+}
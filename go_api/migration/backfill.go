@@ -0,0 +1,54 @@
+package migration
+
+import (
+	"context"
+
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Backfill returns a migration action that scans every item in the table and applies
+// the update built for it; buildUpdate should return nil for items that are already up to date
+// so that rerunning an interrupted backfill is safe
+func Backfill(
+	tableName string,
+	buildUpdate func(item map[string]types.AttributeValue) *dynamodb.UpdateItemInput,
+) func(ctx context.Context, db client.DDBClient) error {
+	return func(ctx context.Context, db client.DDBClient) error {
+		return scanAll(ctx, db, tableName, func(item map[string]types.AttributeValue) error {
+			updateInput := buildUpdate(item)
+			if updateInput == nil {
+				return nil
+			}
+			updateInput.TableName = aws.String(tableName)
+			_, err := db.UpdateItem(ctx, updateInput)
+			return err
+		})
+	}
+}
+
+// scanAll calls handleItem for every item in the table, following the scan's pagination
+func scanAll(ctx context.Context, db client.DDBClient, tableName string, handleItem func(item map[string]types.AttributeValue) error) error {
+	var startKey map[string]types.AttributeValue
+	for {
+		scanOutput, err := db.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(tableName),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range scanOutput.Items {
+			if err := handleItem(item); err != nil {
+				return err
+			}
+		}
+		if len(scanOutput.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		startKey = scanOutput.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/repository/client"
+)
+
+func TestBackfill(t *testing.T) {
+	firstPage := &dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{
+			{"id": &types.AttributeValueMemberS{Value: "0"}},
+			{"id": &types.AttributeValueMemberS{Value: "1"}, "done": &types.AttributeValueMemberBOOL{Value: true}},
+		},
+		LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "1"}},
+	}
+	secondPage := &dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{
+			{"id": &types.AttributeValueMemberS{Value: "2"}},
+		},
+	}
+	tests := []struct {
+		name                string
+		updateError         error
+		scanError           error
+		expectedUpdateCalls int
+		expectError         bool
+	}{
+		{
+			name:                "Successfully backfilled every page",
+			expectedUpdateCalls: 2,
+		},
+		{
+			name:                "Failed to update an item",
+			updateError:         fmt.Errorf("failed to update item"),
+			expectedUpdateCalls: 1,
+			expectError:         true,
+		},
+		{
+			name:        "Failed to scan the table",
+			scanError:   fmt.Errorf("failed to scan"),
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("Scan", context.TODO(), mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+				return input.ExclusiveStartKey == nil
+			})).Return(firstPage, tt.scanError).Once()
+			if tt.scanError == nil && tt.updateError == nil {
+				mockDdbClient.On("Scan", context.TODO(), mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+					return input.ExclusiveStartKey != nil
+				})).Return(secondPage, nil).Once()
+			}
+			if tt.expectedUpdateCalls > 0 {
+				mockDdbClient.On("UpdateItem", context.TODO(), mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+					return *input.TableName == "table"
+				})).Return(&dynamodb.UpdateItemOutput{}, tt.updateError).Times(tt.expectedUpdateCalls)
+			}
+
+			backfill := Backfill("table", func(item map[string]types.AttributeValue) *dynamodb.UpdateItemInput {
+				if _, ok := item["done"]; ok {
+					return nil
+				}
+				return &dynamodb.UpdateItemInput{Key: map[string]types.AttributeValue{"id": item["id"]}}
+			})
+			err := backfill(context.TODO(), mockDdbClient)
+			assert.Equal(t, tt.expectError, err != nil, "Backfill() error = %v", err)
+		})
+	}
+}
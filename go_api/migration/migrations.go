@@ -0,0 +1,117 @@
+package migration
+
+import (
+	"context"
+	"time"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Migrations returns every migration in the order they were introduced;
+// versions must never be reused or reordered once they have been applied
+func Migrations(appConfig model.AppConfig) []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "create the users and favorites tables",
+		},
+		{
+			Version:     2,
+			Description: "add the user-drink-index to the favorites table",
+		},
+		{
+			Version:     3,
+			Description: "add the user-created-index to the favorites table",
+		},
+		{
+			Version:     4,
+			Description: "backfill created_at and updated_at on existing users and favorites",
+			Apply: func(ctx context.Context, db client.DDBClient) error {
+				// the single table was introduced after timestamps, so its items always have them
+				if appConfig.TableDesign == model.SingleTableDesign {
+					return nil
+				}
+				now := model.FormatTimestamp(time.Now())
+				for _, tableName := range []string{appConfig.UsersTableName, appConfig.FavoritesTableName} {
+					if err := Backfill(tableName, backfillTimestamps(now))(ctx, db); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     5,
+			Description: "create the outbox table for domain events",
+		},
+		{
+			Version:     6,
+			Description: "create the expiring records table and enable time to live on expires_at",
+		},
+		{
+			Version:     7,
+			Description: "create the drinks table for the drink catalog",
+		},
+		{
+			Version:     8,
+			Description: "create the inventory table for users' bar inventories",
+		},
+		{
+			Version:     9,
+			Description: "create the recipes table for users' own recipes",
+		},
+		{
+			Version:     10,
+			Description: "add the pending-index to the outbox table and backfill status on the pending events",
+			Apply: func(ctx context.Context, db client.DDBClient) error {
+				// the single-table design already keeps its outbox sorted in the OUTBOX partition
+				if appConfig.TableDesign == model.SingleTableDesign {
+					return nil
+				}
+				return Backfill(appConfig.OutboxTableName, backfillOutboxStatus)(ctx, db)
+			},
+		},
+	}
+}
+
+// backfillTimestamps sets created_at and updated_at on items that don't have them yet;
+// the original creation time is unknown, so the time of the migration is used instead
+func backfillTimestamps(now string) func(item map[string]types.AttributeValue) *dynamodb.UpdateItemInput {
+	return func(item map[string]types.AttributeValue) *dynamodb.UpdateItemInput {
+		if _, ok := item["created_at"]; ok {
+			return nil
+		}
+		return &dynamodb.UpdateItemInput{
+			Key: map[string]types.AttributeValue{
+				"id": item["id"],
+			},
+			UpdateExpression: aws.String("SET created_at = if_not_exists(created_at, :now), updated_at = if_not_exists(updated_at, :now)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":now": &types.AttributeValueMemberS{Value: now},
+			},
+		}
+	}
+}
+
+// backfillOutboxStatus adds the events recorded before the pending-index to the index
+func backfillOutboxStatus(item map[string]types.AttributeValue) *dynamodb.UpdateItemInput {
+	if _, ok := item["status"]; ok {
+		return nil
+	}
+	return &dynamodb.UpdateItemInput{
+		Key: map[string]types.AttributeValue{
+			"id": item["id"],
+		},
+		UpdateExpression: aws.String("SET #status = :pending"),
+		// status is a reserved word
+		ExpressionAttributeNames: map[string]string{"#status": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: "pending"},
+		},
+	}
+}
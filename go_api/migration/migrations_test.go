@@ -0,0 +1,55 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations := Migrations(model.AppConfig{})
+	versions := map[int]bool{}
+	for i, migration := range migrations {
+		assert.False(t, versions[migration.Version], "Migration version %d is used more than once", migration.Version)
+		versions[migration.Version] = true
+		if i > 0 {
+			assert.Greater(t, migration.Version, migrations[i-1].Version, "Migrations must be listed in version order")
+		}
+	}
+}
+
+func TestBackfillTimestamps(t *testing.T) {
+	buildUpdate := backfillTimestamps("2023-01-01T00:00:00.000Z")
+
+	existingItem := map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: "0"},
+		"created_at": &types.AttributeValueMemberS{Value: "2022-01-01T00:00:00.000Z"},
+	}
+	assert.Nil(t, buildUpdate(existingItem), "Items that already have timestamps should not be updated")
+
+	legacyItem := map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: "1"},
+	}
+	updateInput := buildUpdate(legacyItem)
+	assert.NotNil(t, updateInput)
+	assert.Equal(t, map[string]types.AttributeValue{"id": legacyItem["id"]}, updateInput.Key)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00.000Z"}, updateInput.ExpressionAttributeValues[":now"])
+}
+
+func TestBackfillOutboxStatus(t *testing.T) {
+	indexedEvent := map[string]types.AttributeValue{
+		"id":     &types.AttributeValueMemberS{Value: "event0"},
+		"status": &types.AttributeValueMemberS{Value: "pending"},
+	}
+	assert.Nil(t, backfillOutboxStatus(indexedEvent), "Events that are already in the pending-index should not be updated")
+
+	legacyEvent := map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: "event1"},
+	}
+	updateInput := backfillOutboxStatus(legacyEvent)
+	assert.NotNil(t, updateInput)
+	assert.Equal(t, map[string]types.AttributeValue{"id": legacyEvent["id"]}, updateInput.Key)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "pending"}, updateInput.ExpressionAttributeValues[":pending"])
+}
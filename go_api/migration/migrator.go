@@ -0,0 +1,317 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Migration is a versioned change to the api's data;
+// schema changes are applied declaratively from Tables, so migrations that only change
+// the schema leave Apply nil and exist to record when the change was rolled out
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(ctx context.Context, db client.DDBClient) error
+}
+
+// Step is a single action that the Migrator will take to bring the tables up to date
+type Step struct {
+	Description string
+	run         func(ctx context.Context) error
+}
+
+// Run performs the step's action
+func (s Step) Run(ctx context.Context) error {
+	return s.run(ctx)
+}
+
+type Migrator struct {
+	db            client.DDBAdminClient
+	tables        []TableSchema
+	versionsTable string
+	migrations    []Migration
+	now           func() time.Time
+
+	// PollInterval is how long to wait between checks on a table that is being created or updated
+	PollInterval time.Duration
+}
+
+// NewMigrator creates a migrator for the tables and migrations described by the app config
+func NewMigrator(db client.DDBAdminClient, appConfig model.AppConfig) Migrator {
+	return Migrator{
+		db:            db,
+		tables:        Tables(appConfig),
+		versionsTable: appConfig.SchemaVersionsTableName,
+		migrations:    Migrations(appConfig),
+		now:           time.Now,
+		PollInterval:  time.Second,
+	}
+}
+
+// Plan compares the tables and applied schema versions against the Go schema definition
+// and returns the steps needed to bring them up to date; an empty plan means nothing needs to change
+func (m Migrator) Plan(ctx context.Context) ([]Step, error) {
+	steps := []Step{}
+	versionsTableExists := true
+	for _, table := range m.tables {
+		tableSteps, exists, err := m.planTable(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		if table.Name == m.versionsTable {
+			versionsTableExists = exists
+		}
+		steps = append(steps, tableSteps...)
+	}
+
+	appliedVersions := map[int]bool{}
+	if versionsTableExists {
+		var err error
+		appliedVersions, err = m.appliedVersions(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	migrations := make([]Migration, len(m.migrations))
+	copy(migrations, m.migrations)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for _, migration := range migrations {
+		if appliedVersions[migration.Version] {
+			continue
+		}
+		migration := migration
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("apply migration %d: %s", migration.Version, migration.Description),
+			run: func(ctx context.Context) error {
+				return m.applyMigration(ctx, migration)
+			},
+		})
+	}
+
+	return steps, nil
+}
+
+// Run plans and then applies every step needed to bring the tables up to date
+func (m Migrator) Run(ctx context.Context) error {
+	steps, err := m.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	for _, step := range steps {
+		if err := step.Run(ctx); err != nil {
+			return fmt.Errorf("failed to %s: %w", step.Description, err)
+		}
+	}
+	return nil
+}
+
+// planTable returns the steps needed to create the table or add any of its missing indexes,
+// along with whether the table already exists
+func (m Migrator) planTable(ctx context.Context, table TableSchema) ([]Step, bool, error) {
+	description, err := m.describeTable(ctx, table.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	if description == nil {
+		indexNames := make([]string, len(table.Indexes))
+		for i, index := range table.Indexes {
+			indexNames[i] = index.Name
+		}
+		stepDescription := fmt.Sprintf("create table %s", table.Name)
+		if len(indexNames) > 0 {
+			stepDescription = fmt.Sprintf("%s with indexes %s", stepDescription, strings.Join(indexNames, ", "))
+		}
+		steps := []Step{{
+			Description: stepDescription,
+			run: func(ctx context.Context) error {
+				if _, err := m.db.CreateTable(ctx, table.CreateTableInput()); err != nil {
+					return err
+				}
+				return m.waitUntilActive(ctx, table.Name)
+			},
+		}}
+		if table.TTLAttribute != "" {
+			steps = append(steps, m.enableTTLStep(table))
+		}
+		return steps, false, nil
+	}
+
+	existingIndexes := map[string]types.GlobalSecondaryIndexDescription{}
+	for _, index := range description.GlobalSecondaryIndexes {
+		existingIndexes[aws.ToString(index.IndexName)] = index
+	}
+
+	steps := []Step{}
+	for _, index := range table.Indexes {
+		existingIndex, ok := existingIndexes[index.Name]
+		if ok {
+			if !sameKeySchema(existingIndex.KeySchema, index) {
+				return nil, true, fmt.Errorf("the index %s on table %s has a different key schema than its definition and must be recreated manually", index.Name, table.Name)
+			}
+			continue
+		}
+		index := index
+		steps = append(steps, Step{
+			Description: fmt.Sprintf("create index %s on table %s", index.Name, table.Name),
+			run: func(ctx context.Context) error {
+				if _, err := m.db.UpdateTable(ctx, table.CreateIndexInput(index)); err != nil {
+					return err
+				}
+				return m.waitUntilActive(ctx, table.Name)
+			},
+		})
+	}
+
+	if table.TTLAttribute != "" {
+		ttlStep, err := m.planTTL(ctx, table)
+		if err != nil {
+			return nil, true, err
+		}
+		if ttlStep != nil {
+			steps = append(steps, *ttlStep)
+		}
+	}
+	return steps, true, nil
+}
+
+// planTTL returns the step that enables time to live on the existing table, or nil if it's already enabled
+func (m Migrator) planTTL(ctx context.Context, table TableSchema) (*Step, error) {
+	output, err := m.db.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(table.Name)})
+	if err != nil {
+		return nil, err
+	}
+	description := output.TimeToLiveDescription
+	if description == nil {
+		description = &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled}
+	}
+	switch description.TimeToLiveStatus {
+	case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+		if aws.ToString(description.AttributeName) != table.TTLAttribute {
+			return nil, fmt.Errorf("time to live on table %s uses the attribute %s instead of %s and must be changed manually", table.Name, aws.ToString(description.AttributeName), table.TTLAttribute)
+		}
+		return nil, nil
+	case types.TimeToLiveStatusDisabling:
+		return nil, fmt.Errorf("time to live on table %s is being disabled; run the migration again once it's disabled", table.Name)
+	}
+	step := m.enableTTLStep(table)
+	return &step, nil
+}
+
+func (m Migrator) enableTTLStep(table TableSchema) Step {
+	return Step{
+		Description: fmt.Sprintf("enable time to live on table %s using %s", table.Name, table.TTLAttribute),
+		run: func(ctx context.Context) error {
+			_, err := m.db.UpdateTimeToLive(ctx, table.EnableTTLInput())
+			return err
+		},
+	}
+}
+
+// describeTable returns the table's description or nil if the table doesn't exist
+func (m Migrator) describeTable(ctx context.Context, tableName string) (*types.TableDescription, error) {
+	output, err := m.db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return output.Table, nil
+}
+
+// waitUntilActive polls the table until both it and all of its indexes are active
+func (m Migrator) waitUntilActive(ctx context.Context, tableName string) error {
+	for {
+		description, err := m.describeTable(ctx, tableName)
+		if err != nil {
+			return err
+		}
+		if description != nil && isActive(description) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.PollInterval):
+		}
+	}
+}
+
+// appliedVersions returns the set of migration versions recorded in the schema versions table
+func (m Migrator) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	versions := map[int]bool{}
+	err := scanAll(ctx, m.db, m.versionsTable, func(item map[string]types.AttributeValue) error {
+		versionAttribute, ok := item["version"].(*types.AttributeValueMemberS)
+		if !ok {
+			return fmt.Errorf("the schema versions table contains an item without a version")
+		}
+		version, err := strconv.Atoi(versionAttribute.Value)
+		if err != nil {
+			return fmt.Errorf("the schema versions table contains an invalid version '%s'", versionAttribute.Value)
+		}
+		versions[version] = true
+		return nil
+	})
+	return versions, err
+}
+
+// applyMigration runs the migration's item-level changes and records its version as applied
+func (m Migrator) applyMigration(ctx context.Context, migration Migration) error {
+	if migration.Apply != nil {
+		if err := migration.Apply(ctx, m.db); err != nil {
+			return err
+		}
+	}
+	_, err := m.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(m.versionsTable),
+		Item: map[string]types.AttributeValue{
+			"version":     &types.AttributeValueMemberS{Value: strconv.Itoa(migration.Version)},
+			"description": &types.AttributeValueMemberS{Value: migration.Description},
+			"applied_at":  &types.AttributeValueMemberS{Value: m.now().UTC().Format(time.RFC3339)},
+		},
+	})
+	return err
+}
+
+func isActive(description *types.TableDescription) bool {
+	if description.TableStatus != types.TableStatusActive {
+		return false
+	}
+	for _, index := range description.GlobalSecondaryIndexes {
+		if index.IndexStatus != types.IndexStatusActive {
+			return false
+		}
+	}
+	return true
+}
+
+func sameKeySchema(keySchema []types.KeySchemaElement, index IndexSchema) bool {
+	expected := map[types.KeyType]string{types.KeyTypeHash: index.HashKey}
+	if index.RangeKey != "" {
+		expected[types.KeyTypeRange] = index.RangeKey
+	}
+	if len(keySchema) != len(expected) {
+		return false
+	}
+	for _, element := range keySchema {
+		if expected[element.KeyType] != aws.ToString(element.AttributeName) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,313 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/repository/client"
+)
+
+var testTable = TableSchema{
+	Name:    "favorites",
+	HashKey: "id",
+	Indexes: []IndexSchema{
+		{Name: "user-index", HashKey: "user_id"},
+		{Name: "user-drink-index", HashKey: "user_id", RangeKey: "drink_id"},
+	},
+}
+
+var testVersionsTable = TableSchema{
+	Name:    "versions",
+	HashKey: "version",
+}
+
+func activeTable(indexes ...IndexSchema) *dynamodb.DescribeTableOutput {
+	indexDescriptions := make([]types.GlobalSecondaryIndexDescription, len(indexes))
+	for i, index := range indexes {
+		indexDescriptions[i] = types.GlobalSecondaryIndexDescription{
+			IndexName:   aws.String(index.Name),
+			KeySchema:   keySchema(index.HashKey, index.RangeKey),
+			IndexStatus: types.IndexStatusActive,
+		}
+	}
+	return &dynamodb.DescribeTableOutput{
+		Table: &types.TableDescription{
+			TableStatus:            types.TableStatusActive,
+			GlobalSecondaryIndexes: indexDescriptions,
+		},
+	}
+}
+
+func describeInput(tableName string) interface{} {
+	return mock.MatchedBy(func(input *dynamodb.DescribeTableInput) bool {
+		return *input.TableName == tableName
+	})
+}
+
+func newTestMigrator(db client.DDBAdminClient, migrations ...Migration) Migrator {
+	return Migrator{
+		db:            db,
+		tables:        []TableSchema{testTable, testVersionsTable},
+		versionsTable: testVersionsTable.Name,
+		migrations:    migrations,
+		now:           func() time.Time { return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC) },
+	}
+}
+
+func TestMigrator_Plan(t *testing.T) {
+	migrations := []Migration{
+		{Version: 2, Description: "second"},
+		{Version: 1, Description: "first"},
+	}
+	appliedVersions := &dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{
+			{"version": &types.AttributeValueMemberS{Value: "1"}},
+		},
+	}
+	tests := []struct {
+		name                 string
+		mockCalls            func(db *client.MockDDBAdminClient)
+		expectedDescriptions []string
+		expectError          bool
+	}{
+		{
+			name: "No tables exist",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), mock.AnythingOfType("*dynamodb.DescribeTableInput")).
+					Return(nil, &types.ResourceNotFoundException{})
+			},
+			expectedDescriptions: []string{
+				"create table favorites with indexes user-index, user-drink-index",
+				"create table versions",
+				"apply migration 1: first",
+				"apply migration 2: second",
+			},
+		},
+		{
+			name: "Favorites table is missing an index",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(testTable.Name)).
+					Return(activeTable(testTable.Indexes[0]), nil)
+				db.On("DescribeTable", context.TODO(), describeInput(testVersionsTable.Name)).
+					Return(activeTable(), nil)
+				db.On("Scan", context.TODO(), mock.AnythingOfType("*dynamodb.ScanInput")).
+					Return(appliedVersions, nil)
+			},
+			expectedDescriptions: []string{
+				"create index user-drink-index on table favorites",
+				"apply migration 2: second",
+			},
+		},
+		{
+			name: "Everything is up to date",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(testTable.Name)).
+					Return(activeTable(testTable.Indexes...), nil)
+				db.On("DescribeTable", context.TODO(), describeInput(testVersionsTable.Name)).
+					Return(activeTable(), nil)
+				db.On("Scan", context.TODO(), mock.AnythingOfType("*dynamodb.ScanInput")).
+					Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+						{"version": &types.AttributeValueMemberS{Value: "1"}},
+						{"version": &types.AttributeValueMemberS{Value: "2"}},
+					}}, nil)
+			},
+			expectedDescriptions: []string{},
+		},
+		{
+			name: "Index has a different key schema",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(testTable.Name)).
+					Return(activeTable(testTable.Indexes[0], IndexSchema{Name: "user-drink-index", HashKey: "drink_id"}), nil)
+			},
+			expectError: true,
+		},
+		{
+			name: "Failed to describe table",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), mock.AnythingOfType("*dynamodb.DescribeTableInput")).
+					Return(nil, fmt.Errorf("failed to describe table"))
+			},
+			expectError: true,
+		},
+		{
+			name: "Invalid version recorded",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), mock.AnythingOfType("*dynamodb.DescribeTableInput")).
+					Return(activeTable(testTable.Indexes...), nil)
+				db.On("Scan", context.TODO(), mock.AnythingOfType("*dynamodb.ScanInput")).
+					Return(&dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+						{"version": &types.AttributeValueMemberS{Value: "one"}},
+					}}, nil)
+			},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBAdminClient(t)
+			tt.mockCalls(mockDdbClient)
+			migrator := newTestMigrator(mockDdbClient, migrations...)
+
+			steps, err := migrator.Plan(context.TODO())
+			assert.Equal(t, tt.expectError, err != nil, "Migrator.Plan() error = %v", err)
+			if tt.expectError {
+				return
+			}
+			descriptions := make([]string, len(steps))
+			for i, step := range steps {
+				descriptions[i] = step.Description
+			}
+			assert.Equal(t, tt.expectedDescriptions, descriptions)
+		})
+	}
+}
+
+func TestMigrator_Plan_TTL(t *testing.T) {
+	ttlTable := TableSchema{Name: "expiring", HashKey: "id", TTLAttribute: "expires_at"}
+	ttlDescription := func(status types.TimeToLiveStatus, attributeName string) *dynamodb.DescribeTimeToLiveOutput {
+		return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: &types.TimeToLiveDescription{
+			TimeToLiveStatus: status,
+			AttributeName:    aws.String(attributeName),
+		}}
+	}
+	tests := []struct {
+		name                string
+		mockCalls           func(db *client.MockDDBAdminClient)
+		expectedDescription []string
+		expectError         bool
+	}{
+		{
+			name: "New table",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(ttlTable.Name)).Return(nil, &types.ResourceNotFoundException{})
+			},
+			expectedDescription: []string{
+				"create table expiring",
+				"enable time to live on table expiring using expires_at",
+			},
+		},
+		{
+			name: "Time to live is disabled",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(ttlTable.Name)).Return(activeTable(), nil)
+				db.On("DescribeTimeToLive", context.TODO(), &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(ttlTable.Name)}).
+					Return(&dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled}}, nil)
+			},
+			expectedDescription: []string{"enable time to live on table expiring using expires_at"},
+		},
+		{
+			name: "Time to live is already enabled",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(ttlTable.Name)).Return(activeTable(), nil)
+				db.On("DescribeTimeToLive", context.TODO(), mock.AnythingOfType("*dynamodb.DescribeTimeToLiveInput")).
+					Return(ttlDescription(types.TimeToLiveStatusEnabled, "expires_at"), nil)
+			},
+			expectedDescription: []string{},
+		},
+		{
+			name: "Time to live uses another attribute",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(ttlTable.Name)).Return(activeTable(), nil)
+				db.On("DescribeTimeToLive", context.TODO(), mock.AnythingOfType("*dynamodb.DescribeTimeToLiveInput")).
+					Return(ttlDescription(types.TimeToLiveStatusEnabled, "ttl"), nil)
+			},
+			expectError: true,
+		},
+		{
+			name: "Time to live is being disabled",
+			mockCalls: func(db *client.MockDDBAdminClient) {
+				db.On("DescribeTable", context.TODO(), describeInput(ttlTable.Name)).Return(activeTable(), nil)
+				db.On("DescribeTimeToLive", context.TODO(), mock.AnythingOfType("*dynamodb.DescribeTimeToLiveInput")).
+					Return(ttlDescription(types.TimeToLiveStatusDisabling, "expires_at"), nil)
+			},
+			expectError: true,
+		},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBAdminClient(t)
+			d.mockCalls(mockDdbClient)
+			// no migrations have been applied yet
+			mockDdbClient.On("Scan", context.TODO(), mock.AnythingOfType("*dynamodb.ScanInput")).
+				Return(&dynamodb.ScanOutput{}, nil).Maybe()
+			migrator := Migrator{db: mockDdbClient, tables: []TableSchema{ttlTable}, versionsTable: "versions"}
+
+			steps, err := migrator.Plan(context.TODO())
+			if d.expectError {
+				assert.Error(t, err, "An error should have been returned from Plan")
+				return
+			}
+			assert.NoError(t, err, "No error should have been returned from Plan")
+			descriptions := []string{}
+			for _, step := range steps {
+				descriptions = append(descriptions, step.Description)
+			}
+			assert.Equal(t, d.expectedDescription, descriptions)
+		})
+	}
+}
+
+func TestMigrator_Run(t *testing.T) {
+	mockDdbClient := client.NewMockDDBAdminClient(t)
+	creatingTable := activeTable()
+	creatingTable.Table.TableStatus = types.TableStatusCreating
+
+	// the versions table already exists, but the favorites table has to be created
+	mockDdbClient.On("DescribeTable", context.TODO(), describeInput(testTable.Name)).
+		Return(nil, &types.ResourceNotFoundException{}).Once()
+	mockDdbClient.On("DescribeTable", context.TODO(), describeInput(testVersionsTable.Name)).
+		Return(activeTable(), nil)
+	mockDdbClient.On("Scan", context.TODO(), mock.AnythingOfType("*dynamodb.ScanInput")).
+		Return(&dynamodb.ScanOutput{}, nil)
+	mockDdbClient.On("CreateTable", context.TODO(), testTable.CreateTableInput()).
+		Return(&dynamodb.CreateTableOutput{}, nil)
+	mockDdbClient.On("DescribeTable", context.TODO(), describeInput(testTable.Name)).
+		Return(creatingTable, nil).Once()
+	mockDdbClient.On("DescribeTable", context.TODO(), describeInput(testTable.Name)).
+		Return(activeTable(testTable.Indexes...), nil).Once()
+	mockDdbClient.On("UpdateItem", context.TODO(), mock.AnythingOfType("*dynamodb.UpdateItemInput")).
+		Return(&dynamodb.UpdateItemOutput{}, nil)
+	mockDdbClient.On("PutItem", context.TODO(), &dynamodb.PutItemInput{
+		TableName: aws.String(testVersionsTable.Name),
+		Item: map[string]types.AttributeValue{
+			"version":     &types.AttributeValueMemberS{Value: "1"},
+			"description": &types.AttributeValueMemberS{Value: "first"},
+			"applied_at":  &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00Z"},
+		},
+	}).Return(&dynamodb.PutItemOutput{}, nil)
+
+	migrationApplied := false
+	migrator := newTestMigrator(mockDdbClient, Migration{
+		Version:     1,
+		Description: "first",
+		Apply: func(ctx context.Context, db client.DDBClient) error {
+			migrationApplied = true
+			_, err := db.UpdateItem(ctx, &dynamodb.UpdateItemInput{})
+			return err
+		},
+	})
+
+	err := migrator.Run(context.TODO())
+	assert.NoError(t, err)
+	assert.True(t, migrationApplied, "The migration's Apply function should have been called")
+}
+
+func TestTableSchema_CreateIndexInput(t *testing.T) {
+	input := testTable.CreateIndexInput(testTable.Indexes[1])
+	assert.Equal(t, "favorites", *input.TableName)
+	assert.Equal(t, []types.AttributeDefinition{
+		{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+		{AttributeName: aws.String("user_id"), AttributeType: types.ScalarAttributeTypeS},
+		{AttributeName: aws.String("drink_id"), AttributeType: types.ScalarAttributeTypeS},
+	}, input.AttributeDefinitions)
+	assert.Len(t, input.GlobalSecondaryIndexUpdates, 1)
+	assert.Equal(t, "user-drink-index", *input.GlobalSecondaryIndexUpdates[0].Create.IndexName)
+}
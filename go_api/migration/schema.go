@@ -0,0 +1,200 @@
+package migration
+
+import (
+	"the-drink-almanac-api/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	defaultReadCapacityUnits  = 10
+	defaultWriteCapacityUnits = 5
+)
+
+// TableSchema describes a dynamodb table and its global secondary indexes;
+// every key attribute is stored as a string
+type TableSchema struct {
+	Name     string
+	HashKey  string
+	RangeKey string
+	Indexes  []IndexSchema
+	// TTLAttribute is the attribute that dynamodb's time to live expires items by, if it's enabled for the table
+	TTLAttribute string
+}
+
+// IndexSchema describes a global secondary index that projects all attributes
+type IndexSchema struct {
+	Name     string
+	HashKey  string
+	RangeKey string
+}
+
+// Tables returns the schema of every table used by the api's configured table design
+func Tables(appConfig model.AppConfig) []TableSchema {
+	versionsTable := TableSchema{
+		Name:    appConfig.SchemaVersionsTableName,
+		HashKey: "version",
+	}
+	if appConfig.TableDesign == model.SingleTableDesign {
+		// see repository/single_table.go for the layout of the items
+		return []TableSchema{
+			{
+				Name:     appConfig.SingleTableName,
+				HashKey:  "PK",
+				RangeKey: "SK",
+				Indexes: []IndexSchema{
+					{Name: "GSI1", HashKey: "GSI1PK", RangeKey: "GSI1SK"},
+					{Name: "GSI2", HashKey: "GSI2PK", RangeKey: "GSI2SK"},
+				},
+				TTLAttribute: "expires_at",
+			},
+			versionsTable,
+		}
+	}
+
+	return []TableSchema{
+		{
+			Name:    appConfig.UsersTableName,
+			HashKey: "id",
+			Indexes: []IndexSchema{
+				{Name: "username-index", HashKey: "username"},
+			},
+		},
+		{
+			Name:    appConfig.FavoritesTableName,
+			HashKey: "id",
+			Indexes: []IndexSchema{
+				{Name: "user-index", HashKey: "user_id"},
+				{Name: "user-drink-index", HashKey: "user_id", RangeKey: "drink_id"},
+				{Name: "user-created-index", HashKey: "user_id", RangeKey: "created_at"},
+			},
+		},
+		{
+			Name:    appConfig.OutboxTableName,
+			HashKey: "id",
+			Indexes: []IndexSchema{
+				{Name: "pending-index", HashKey: "status", RangeKey: "occurred_at"},
+			},
+		},
+		{
+			Name:         appConfig.ExpiringRecordsTableName,
+			HashKey:      "id",
+			TTLAttribute: "expires_at",
+		},
+		{
+			Name:    appConfig.DrinksTableName,
+			HashKey: "id",
+		},
+		{
+			Name:     appConfig.InventoryTableName,
+			HashKey:  "user_id",
+			RangeKey: "ingredient",
+		},
+		{
+			Name:    appConfig.RecipesTableName,
+			HashKey: "id",
+			Indexes: []IndexSchema{
+				{Name: "owner-index", HashKey: "owner_id"},
+			},
+		},
+		versionsTable,
+	}
+}
+
+// CreateTableInput builds the request for creating the table along with all of its indexes
+func (t TableSchema) CreateTableInput() *dynamodb.CreateTableInput {
+	globalSecondaryIndexes := make([]types.GlobalSecondaryIndex, len(t.Indexes))
+	for i, index := range t.Indexes {
+		globalSecondaryIndexes[i] = index.globalSecondaryIndex()
+	}
+	input := &dynamodb.CreateTableInput{
+		TableName:             aws.String(t.Name),
+		AttributeDefinitions:  t.attributeDefinitions(t.Indexes...),
+		KeySchema:             keySchema(t.HashKey, t.RangeKey),
+		ProvisionedThroughput: provisionedThroughput(),
+	}
+	if len(globalSecondaryIndexes) > 0 {
+		input.GlobalSecondaryIndexes = globalSecondaryIndexes
+	}
+	return input
+}
+
+// CreateIndexInput builds the request for adding the given index to the existing table
+func (t TableSchema) CreateIndexInput(index IndexSchema) *dynamodb.UpdateTableInput {
+	gsi := index.globalSecondaryIndex()
+	return &dynamodb.UpdateTableInput{
+		TableName:            aws.String(t.Name),
+		AttributeDefinitions: t.attributeDefinitions(index),
+		GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+			{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:             gsi.IndexName,
+					KeySchema:             gsi.KeySchema,
+					Projection:            gsi.Projection,
+					ProvisionedThroughput: gsi.ProvisionedThroughput,
+				},
+			},
+		},
+	}
+}
+
+// EnableTTLInput builds the request for enabling time to live on the table's TTLAttribute
+func (t TableSchema) EnableTTLInput() *dynamodb.UpdateTimeToLiveInput {
+	return &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(t.Name),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(t.TTLAttribute),
+			Enabled:       aws.Bool(true),
+		},
+	}
+}
+
+// attributeDefinitions lists the table's key attributes along with the key attributes of the given indexes
+func (t TableSchema) attributeDefinitions(indexes ...IndexSchema) []types.AttributeDefinition {
+	attributeNames := []string{t.HashKey, t.RangeKey}
+	for _, index := range indexes {
+		attributeNames = append(attributeNames, index.HashKey, index.RangeKey)
+	}
+
+	definitions := []types.AttributeDefinition{}
+	seen := map[string]bool{}
+	for _, name := range attributeNames {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		definitions = append(definitions, types.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: types.ScalarAttributeTypeS,
+		})
+	}
+	return definitions
+}
+
+func (i IndexSchema) globalSecondaryIndex() types.GlobalSecondaryIndex {
+	return types.GlobalSecondaryIndex{
+		IndexName:             aws.String(i.Name),
+		KeySchema:             keySchema(i.HashKey, i.RangeKey),
+		Projection:            &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		ProvisionedThroughput: provisionedThroughput(),
+	}
+}
+
+func keySchema(hashKey, rangeKey string) []types.KeySchemaElement {
+	elements := []types.KeySchemaElement{
+		{AttributeName: aws.String(hashKey), KeyType: types.KeyTypeHash},
+	}
+	if rangeKey != "" {
+		elements = append(elements, types.KeySchemaElement{AttributeName: aws.String(rangeKey), KeyType: types.KeyTypeRange})
+	}
+	return elements
+}
+
+func provisionedThroughput() *types.ProvisionedThroughput {
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(defaultReadCapacityUnits),
+		WriteCapacityUnits: aws.Int64(defaultWriteCapacityUnits),
+	}
+}
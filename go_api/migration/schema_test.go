@@ -0,0 +1,42 @@
+package migration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestTables(t *testing.T) {
+	appConfig := model.AppConfig{
+		UsersTableName:           "users",
+		FavoritesTableName:       "favorites",
+		SingleTableName:          "single",
+		SchemaVersionsTableName:  "versions",
+		OutboxTableName:          "outbox",
+		ExpiringRecordsTableName: "expiring",
+		DrinksTableName:          "drinks",
+		InventoryTableName:       "inventory",
+		RecipesTableName:         "recipes",
+	}
+	tableNames := func(tables []TableSchema) []string {
+		names := []string{}
+		for _, table := range tables {
+			names = append(names, table.Name)
+		}
+		return names
+	}
+
+	appConfig.TableDesign = model.MultiTableDesign
+	tables := Tables(appConfig)
+	assert.Equal(t, []string{"users", "favorites", "outbox", "expiring", "drinks", "inventory", "recipes", "versions"}, tableNames(tables))
+	assert.Equal(t, "expires_at", tables[3].TTLAttribute)
+
+	appConfig.TableDesign = model.SingleTableDesign
+	tables = Tables(appConfig)
+	assert.Equal(t, []string{"single", "versions"}, tableNames(tables))
+	assert.Equal(t, "PK", tables[0].HashKey)
+	assert.Equal(t, "SK", tables[0].RangeKey)
+	assert.Len(t, tables[0].Indexes, 2)
+	assert.Equal(t, "expires_at", tables[0].TTLAttribute)
+}
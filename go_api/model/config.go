@@ -2,26 +2,128 @@ package model
 
 import (
 	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// MultiTableDesign stores users and favorites in separate tables
+	MultiTableDesign = "multi-table"
+	// SingleTableDesign stores users, username-uniqueness records and favorites in a single table
+	SingleTableDesign = "single-table"
+
+	// DrinkLookupNone accepts favorites of any drink id, e.g. for tests without a catalog
+	DrinkLookupNone = "none"
+	// DrinkLookupCatalog only accepts favorites of the drinks that the api serves, which are the drink catalog's
+	// or DrinkDataset's; it's the default
+	DrinkLookupCatalog = "catalog"
+
+	// ExpiringStoreDynamodb keeps expiring records in dynamodb, which deletes them with its time to live
+	ExpiringStoreDynamodb = "dynamodb"
+	// ExpiringStoreMemory keeps expiring records in memory, where a sweeper deletes them every ExpiringSweepInterval
+	ExpiringStoreMemory = "memory"
+
+	// BlobStoreFile keeps blobs, such as avatars, in files under BlobStoreDir
+	BlobStoreFile = "file"
+	// BlobStoreS3 keeps blobs in the S3 bucket BlobStoreBucket, through AwsEndpoint if it's set
+	BlobStoreS3 = "s3"
 )
 
 type AppConfig struct {
-	Env                string
-	Port               string
-	UsersTableName     string
-	FavoritesTableName string
-	AwsEndpoint        string
-	JwtSecretKey       string
+	Env                     string
+	Port                    string
+	UsersTableName          string
+	FavoritesTableName      string
+	SchemaVersionsTableName string
+	OutboxTableName         string
+	// ExpiringRecordsTableName is the table of short-lived records in the multi-table design;
+	// the single-table design stores them in SingleTableName
+	ExpiringRecordsTableName string
+	// ExpiringStore is where expiring records are kept: ExpiringStoreDynamodb or ExpiringStoreMemory
+	ExpiringStore string
+	// ExpiringSweepInterval is how often expired records are deleted from the backends without a time to live
+	ExpiringSweepInterval time.Duration
+	// DrinksTableName is the drink catalog's table in the multi-table design
+	DrinksTableName string
+	// InventoryTableName is the table of users' bar inventories in the multi-table design
+	InventoryTableName string
+	// RecipesTableName is the table of users' own recipes in the multi-table design
+	RecipesTableName string
+	// TableDesign is either MultiTableDesign, which uses UsersTableName and FavoritesTableName,
+	// or SingleTableDesign, which uses SingleTableName
+	TableDesign     string
+	SingleTableName string
+	AwsEndpoint     string
+	JwtSecretKey    string
+	// DynamodbMaxAttempts is the maximum number of times a dynamodb request is sent, including the first attempt
+	DynamodbMaxAttempts int
+	// DynamodbRetryBaseDelay and DynamodbRetryMaxDelay bound the exponential backoff between attempts
+	DynamodbRetryBaseDelay time.Duration
+	DynamodbRetryMaxDelay  time.Duration
+	// DynamodbCircuitFailureThreshold is the number of consecutive failed requests that opens the circuit breaker
+	DynamodbCircuitFailureThreshold int
+	// DynamodbCircuitResetTimeout is how long the circuit breaker stays open before letting a trial request through
+	DynamodbCircuitResetTimeout time.Duration
+	// CacheSize is the maximum number of entries in the repositories' read-through cache;
+	// the cache is disabled if it's 0
+	CacheSize int
+	CacheTTL  time.Duration
+	// EventsFile is the file that published domain events are appended to; events aren't written to a file if it's empty
+	EventsFile string
+	// EventsQueueUrl is the SQS queue that published domain events are sent to, through AwsEndpoint if it's set;
+	// events aren't sent to a queue if it's empty
+	EventsQueueUrl string
+	// EventRelayInterval is how often the outbox is checked for events to publish
+	EventRelayInterval time.Duration
+	// DrinkDataset is a TheCocktailDB dump that every drink is read from instead of the drink catalog, if it's set
+	DrinkDataset string
+	// DrinkSearchRefreshInterval is how old the drink search index can get before it's rebuilt
+	DrinkSearchRefreshInterval time.Duration
+	// DrinkLookup is how new favorites' drinks are checked: DrinkLookupNone or DrinkLookupCatalog
+	DrinkLookup string
+	// BlobStore is where blobs such as avatars are kept: BlobStoreFile or BlobStoreS3
+	BlobStore string
+	// BlobStoreDir is the directory of BlobStoreFile
+	BlobStoreDir string
+	// BlobStoreBucket is the bucket of BlobStoreS3
+	BlobStoreBucket string
 }
 
 // NewAppConfig creates a new config using environment variables
 func NewAppConfig() AppConfig {
 	return AppConfig{
-		Env:                DefaultEnv("ENV", "local"),
-		Port:               DefaultEnv("PORT", "8000"),
-		UsersTableName:     DefaultEnv("USERS_TABLE_NAME", "the-drink-almanac-users"),
-		FavoritesTableName: DefaultEnv("FAVORITES_TABLE_NAME", "the-drink-almanac-favorites"),
-		AwsEndpoint:        os.Getenv("AWS_ENDPOINT"),
-		JwtSecretKey:       os.Getenv("JWT_SECRET_KEY"),
+		Env:                             DefaultEnv("ENV", "local"),
+		Port:                            DefaultEnv("PORT", "8000"),
+		UsersTableName:                  DefaultEnv("USERS_TABLE_NAME", "the-drink-almanac-users"),
+		FavoritesTableName:              DefaultEnv("FAVORITES_TABLE_NAME", "the-drink-almanac-favorites"),
+		SchemaVersionsTableName:         DefaultEnv("SCHEMA_VERSIONS_TABLE_NAME", "the-drink-almanac-schema-versions"),
+		OutboxTableName:                 DefaultEnv("OUTBOX_TABLE_NAME", "the-drink-almanac-outbox"),
+		ExpiringRecordsTableName:        DefaultEnv("EXPIRING_RECORDS_TABLE_NAME", "the-drink-almanac-expiring-records"),
+		ExpiringStore:                   DefaultEnv("EXPIRING_STORE", ExpiringStoreDynamodb),
+		ExpiringSweepInterval:           DefaultEnvDuration("EXPIRING_SWEEP_INTERVAL", time.Minute),
+		DrinksTableName:                 DefaultEnv("DRINKS_TABLE_NAME", "the-drink-almanac-drinks"),
+		InventoryTableName:              DefaultEnv("INVENTORY_TABLE_NAME", "the-drink-almanac-inventory"),
+		RecipesTableName:                DefaultEnv("RECIPES_TABLE_NAME", "the-drink-almanac-recipes"),
+		TableDesign:                     DefaultEnv("TABLE_DESIGN", MultiTableDesign),
+		SingleTableName:                 DefaultEnv("SINGLE_TABLE_NAME", "the-drink-almanac"),
+		AwsEndpoint:                     os.Getenv("AWS_ENDPOINT"),
+		JwtSecretKey:                    os.Getenv("JWT_SECRET_KEY"),
+		DynamodbMaxAttempts:             DefaultEnvInt("DYNAMODB_MAX_ATTEMPTS", 3),
+		DynamodbRetryBaseDelay:          DefaultEnvDuration("DYNAMODB_RETRY_BASE_DELAY", 50*time.Millisecond),
+		DynamodbRetryMaxDelay:           DefaultEnvDuration("DYNAMODB_RETRY_MAX_DELAY", time.Second),
+		DynamodbCircuitFailureThreshold: DefaultEnvInt("DYNAMODB_CIRCUIT_FAILURE_THRESHOLD", 5),
+		DynamodbCircuitResetTimeout:     DefaultEnvDuration("DYNAMODB_CIRCUIT_RESET_TIMEOUT", 30*time.Second),
+		CacheSize:                       DefaultEnvInt("CACHE_SIZE", 1000),
+		CacheTTL:                        DefaultEnvDuration("CACHE_TTL", time.Minute),
+		EventsFile:                      os.Getenv("EVENTS_FILE"),
+		EventsQueueUrl:                  os.Getenv("EVENTS_QUEUE_URL"),
+		EventRelayInterval:              DefaultEnvDuration("EVENT_RELAY_INTERVAL", 5*time.Second),
+		DrinkDataset:                    os.Getenv("DRINK_DATASET"),
+		DrinkSearchRefreshInterval:      DefaultEnvDuration("DRINK_SEARCH_REFRESH_INTERVAL", 5*time.Minute),
+		DrinkLookup:                     DefaultEnv("DRINK_LOOKUP", DrinkLookupCatalog),
+		BlobStore:                       DefaultEnv("BLOB_STORE", BlobStoreFile),
+		BlobStoreDir:                    DefaultEnv("BLOB_STORE_DIR", "blobs"),
+		BlobStoreBucket:                 os.Getenv("BLOB_STORE_BUCKET"),
 	}
 }
 
@@ -37,3 +139,23 @@ func DefaultEnv(envVarName, defaultValue string) string {
 	}
 	return envValue
 }
+
+// DefaultEnvInt works like DefaultEnv for integer values;
+// the default value is also returned if the environment variable isn't a valid integer
+func DefaultEnvInt(envVarName string, defaultValue int) int {
+	value, err := strconv.Atoi(DefaultEnv(envVarName, strconv.Itoa(defaultValue)))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// DefaultEnvDuration works like DefaultEnv for durations (e.g. "30s");
+// the default value is also returned if the environment variable isn't a valid duration
+func DefaultEnvDuration(envVarName string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(DefaultEnv(envVarName, defaultValue.String()))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 )
 
 func TestDefaultEnv(t *testing.T) {
@@ -45,3 +46,31 @@ func TestDefaultEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultEnvInt(t *testing.T) {
+	assert := func(got, want int) {
+		t.Helper()
+		if got != want {
+			t.Errorf("DefaultEnvInt() = %v, want %v", got, want)
+		}
+	}
+	assert(DefaultEnvInt("CACHE_SIZE", 10), 10)
+	t.Setenv("CACHE_SIZE", "20")
+	assert(DefaultEnvInt("CACHE_SIZE", 10), 20)
+	t.Setenv("CACHE_SIZE", "twenty")
+	assert(DefaultEnvInt("CACHE_SIZE", 10), 10)
+}
+
+func TestDefaultEnvDuration(t *testing.T) {
+	assert := func(got, want time.Duration) {
+		t.Helper()
+		if got != want {
+			t.Errorf("DefaultEnvDuration() = %v, want %v", got, want)
+		}
+	}
+	assert(DefaultEnvDuration("CACHE_TTL", time.Minute), time.Minute)
+	t.Setenv("CACHE_TTL", "30s")
+	assert(DefaultEnvDuration("CACHE_TTL", time.Minute), 30*time.Second)
+	t.Setenv("CACHE_TTL", "thirty seconds")
+	assert(DefaultEnvDuration("CACHE_TTL", time.Minute), time.Minute)
+}
@@ -0,0 +1,20 @@
+package model
+
+// Drink is a recipe in the drink catalog
+type Drink struct {
+	Id           string       `dynamodbav:"id"`
+	Name         string       `dynamodbav:"name"`
+	Category     string       `dynamodbav:"category,omitempty"`
+	Glass        string       `dynamodbav:"glass,omitempty"`
+	Instructions string       `dynamodbav:"instructions,omitempty"`
+	Ingredients  []Ingredient `dynamodbav:"ingredients,omitempty"`
+	ImageUrl     string       `dynamodbav:"image_url,omitempty"`
+	Alcoholic    bool         `dynamodbav:"alcoholic"`
+}
+
+// Ingredient is one of a drink's ingredients; the measure is free text (e.g. "1 1/2 oz"), and it's empty
+// for ingredients that aren't measured, like a garnish
+type Ingredient struct {
+	Name    string `dynamodbav:"name"`
+	Measure string `dynamodbav:"measure,omitempty"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+type EventType string
+
+const (
+	UserCreated     EventType = "UserCreated"
+	UserDeleted     EventType = "UserDeleted"
+	FavoriteAdded   EventType = "FavoriteAdded"
+	FavoriteRemoved EventType = "FavoriteRemoved"
+)
+
+// Event is a domain event, recorded in the repository's outbox in the same transaction as the change it describes;
+// only the ids relevant to the event's type are set
+type Event struct {
+	Id         string    `dynamodbav:"id" json:"id"`
+	Type       EventType `dynamodbav:"event_type" json:"type"`
+	OccurredAt time.Time `dynamodbav:"occurred_at" json:"occurred_at"`
+	UserId     string    `dynamodbav:"user_id" json:"user_id"`
+	Username   string    `dynamodbav:"username,omitempty" json:"username,omitempty"`
+	FavoriteId string    `dynamodbav:"favorite_id,omitempty" json:"favorite_id,omitempty"`
+	DrinkId    string    `dynamodbav:"drink_id,omitempty" json:"drink_id,omitempty"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// ExpiringRecord is a short-lived value, such as a reset token or a lockout counter, that's removed once it expires
+type ExpiringRecord struct {
+	Key   string `dynamodbav:"id"`
+	Value []byte `dynamodbav:"value,omitempty"`
+	// Count is the value of a counter created by ExpiringStore.Increment
+	Count     int       `dynamodbav:"count,omitempty"`
+	ExpiresAt time.Time `dynamodbav:"expires_at,unixtime"`
+}
+
+// Expired reports whether the record has expired at the given time
+func (r ExpiringRecord) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
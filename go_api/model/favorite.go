@@ -1,7 +1,12 @@
 package model
 
+import "time"
+
 type Favorite struct {
-	Id      string `dynamodbav:"id"`
-	UserId  string `dynamodbav:"user_id"`
-	DrinkId string `dynamodbav:"drink_id"`
+	Id        string    `dynamodbav:"id"`
+	UserId    string    `dynamodbav:"user_id"`
+	DrinkId   string    `dynamodbav:"drink_id"`
+	CreatedAt time.Time `dynamodbav:"created_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at"`
+	Version   int       `dynamodbav:"version"`
 }
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// InventoryItem is an ingredient that a user has at home, such as a bottle or a mixer;
+// each user has at most one item per ingredient
+type InventoryItem struct {
+	UserId string `dynamodbav:"user_id"`
+	// Ingredient is the item's normalized name (see IngredientKey), which identifies it within the user's inventory
+	Ingredient string `dynamodbav:"ingredient"`
+	// Name is the ingredient's name as the user entered it
+	Name     string  `dynamodbav:"name"`
+	Quantity float64 `dynamodbav:"quantity"`
+	// Unit is free text (e.g. "ml" or "bottle"), and it's empty when the quantity is a count
+	Unit string `dynamodbav:"unit,omitempty"`
+	// ExpiresOn is the optional best-before date; it isn't named expires_at, which the single table's time to live deletes on
+	ExpiresOn *time.Time `dynamodbav:"expires_on,omitempty"`
+	CreatedAt time.Time  `dynamodbav:"created_at"`
+	UpdatedAt time.Time  `dynamodbav:"updated_at"`
+	Version   int        `dynamodbav:"version"`
+}
+
+// Expired reports whether the item is past its best-before date at the given time
+func (i InventoryItem) Expired(now time.Time) bool {
+	return i.ExpiresOn != nil && !now.Before(*i.ExpiresOn)
+}
+
+// IngredientKey normalizes an ingredient's name so that the names of inventories and of the drink catalog match
+// regardless of case and spacing, e.g. "Lime  Juice" and "lime juice"
+func IngredientKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// The visibilities of a recipe
+const (
+	// RecipePrivate recipes are only visible to their owner
+	RecipePrivate = "private"
+	// RecipeUnlisted recipes are visible to anyone who has their id, but they aren't searchable
+	RecipeUnlisted = "unlisted"
+	// RecipePublic recipes are visible to anyone and searchable alongside the drink catalog
+	RecipePublic = "public"
+)
+
+// RecipeIdPrefix starts the id of every recipe, which tells them apart from the drink catalog's ids
+const RecipeIdPrefix = "recipe-"
+
+// Recipe is a drink written by a user rather than taken from the drink catalog
+type Recipe struct {
+	Id           string       `dynamodbav:"id"`
+	OwnerId      string       `dynamodbav:"owner_id"`
+	Name         string       `dynamodbav:"name"`
+	Category     string       `dynamodbav:"category,omitempty"`
+	Glass        string       `dynamodbav:"glass,omitempty"`
+	Instructions string       `dynamodbav:"instructions,omitempty"`
+	Ingredients  []Ingredient `dynamodbav:"ingredients,omitempty"`
+	Alcoholic    bool         `dynamodbav:"alcoholic"`
+	Visibility   string       `dynamodbav:"visibility"`
+	CreatedAt    time.Time    `dynamodbav:"created_at"`
+	UpdatedAt    time.Time    `dynamodbav:"updated_at"`
+	Version      int          `dynamodbav:"version"`
+}
+
+// IsRecipeId reports whether the id refers to a user's recipe rather than to a drink of the catalog
+func IsRecipeId(id string) bool {
+	return strings.HasPrefix(id, RecipeIdPrefix)
+}
+
+// ValidRecipeVisibility reports whether the visibility is one of RecipePrivate, RecipeUnlisted and RecipePublic
+func ValidRecipeVisibility(visibility string) bool {
+	return visibility == RecipePrivate || visibility == RecipeUnlisted || visibility == RecipePublic
+}
+
+// VisibleTo reports whether the user can see the recipe; an empty user id is an anonymous user
+func (r Recipe) VisibleTo(userId string) bool {
+	return r.Visibility != RecipePrivate || (userId != "" && r.OwnerId == userId)
+}
+
+// Drink returns the recipe as a drink, so that it can be searched and matched like the catalog's drinks
+func (r Recipe) Drink() Drink {
+	return Drink{
+		Id:           r.Id,
+		Name:         r.Name,
+		Category:     r.Category,
+		Glass:        r.Glass,
+		Instructions: r.Instructions,
+		Ingredients:  r.Ingredients,
+		Alcoholic:    r.Alcoholic,
+	}
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// timestampLayout is a fixed-width RFC 3339 layout, so stored timestamps sort chronologically as strings
+const timestampLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// FormatTimestamp converts the time to UTC and formats it for storage
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
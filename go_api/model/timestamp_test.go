@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want string
+	}{
+		{
+			name: "UTC time",
+			time: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+			want: "2023-01-02T03:04:05.000Z",
+		},
+		{
+			name: "Non-UTC time with milliseconds",
+			time: time.Date(2023, 1, 2, 3, 4, 5, 6000000, time.FixedZone("CST", -6*60*60)),
+			want: "2023-01-02T09:04:05.006Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTimestamp(tt.time); got != tt.want {
+				t.Errorf("FormatTimestamp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,23 @@
 package model
 
+import "time"
+
 type User struct {
-	Id       string `dynamodbav:"id"`
-	Username string `dynamodbav:"username"`
-	Password string `dynamodbav:"password"`
+	Id        string    `dynamodbav:"id"`
+	Username  string    `dynamodbav:"username"`
+	Password  string    `dynamodbav:"password"`
+	CreatedAt time.Time `dynamodbav:"created_at"`
+	UpdatedAt time.Time `dynamodbav:"updated_at"`
+	Version   int       `dynamodbav:"version"`
+	// Units is the system of units that recipes are rendered in for the user, "metric" or "imperial";
+	// empty renders the recipes as they're written
+	Units string `dynamodbav:"units,omitempty"`
+	// DisplayName is the name shown on the user's public profile in place of the username, if it's set
+	DisplayName string `dynamodbav:"display_name,omitempty"`
+	// Bio is the short text shown on the user's public profile
+	Bio string `dynamodbav:"bio,omitempty"`
+	// Avatar is the key of the user's avatar image in the blob store; empty if the user has no avatar
+	Avatar string `dynamodbav:"avatar,omitempty"`
+	// PublicFavorites shows the user's favorites on their public profile
+	PublicFavorites bool `dynamodbav:"public_favorites,omitempty"`
 }
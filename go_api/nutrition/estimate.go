@@ -0,0 +1,140 @@
+// Package nutrition estimates the strength, volume, standard drinks and calories of a serving of a drink
+// from its recipe and a table of the ingredients' alcohol by volume (see ingredients.go).
+package nutrition
+
+import (
+	"strings"
+
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/model"
+)
+
+const (
+	// ethanolDensity is the grams of a milliliter of ethanol
+	ethanolDensity = 0.789
+	// ethanolCalories are the calories of a gram of ethanol
+	ethanolCalories = 7
+	// StandardDrinkGrams is the ethanol in a US standard drink; other countries use e.g. 10 g (Australia) or 8 g (UK)
+	StandardDrinkGrams = 14
+)
+
+// approximateMilliliters are the volumes of the measures that recipes use for small amounts, which the measure
+// package doesn't add up; they're close enough for an estimate, since the amounts are so small
+var approximateMilliliters = map[measure.Unit]float64{
+	measure.Dash:   0.92,
+	measure.Splash: 5,
+	measure.Drop:   0.05,
+}
+
+// Method is how a drink is mixed, which decides how much water the ice adds to it
+type Method string
+
+const (
+	Shaken  Method = "shaken"
+	Stirred Method = "stirred"
+	Blended Method = "blended"
+	Built   Method = "built"
+)
+
+// MethodOf guesses how the drink is mixed from its instructions; a drink that isn't shaken, stirred or blended
+// is built in the glass that it's served in
+func MethodOf(instructions string) Method {
+	instructions = strings.ToLower(instructions)
+	switch {
+	case strings.Contains(instructions, "blend"):
+		return Blended
+	case strings.Contains(instructions, "shake"):
+		return Shaken
+	case strings.Contains(instructions, "stir"):
+		return Stirred
+	}
+	return Built
+}
+
+// dilution is the water that the ice adds, as a fraction of the volume of the ingredients, for a drink of the abv
+// (a fraction), from the measurements in Dave Arnold's Liquid Intelligence. Blended drinks are at least as diluted
+// as shaken ones, so they use the same equation, and built drinks aren't diluted before they're served
+func (m Method) dilution(abv float64) float64 {
+	switch m {
+	case Shaken, Blended:
+		return 1.567*abv*abv + 1.742*abv + 0.203
+	case Stirred:
+		return -1.21*abv*abv + 1.246*abv + 0.145
+	}
+	return 0
+}
+
+// Estimate is the estimate for a serving of a drink
+type Estimate struct {
+	DrinkId   string
+	DrinkName string
+	Method    Method
+	// Volume is the milliliters of the drink once it's mixed, of which Dilution is the water from the ice
+	Volume   float64
+	Dilution float64
+	// ABV is the alcohol by volume of the mixed drink, as a percentage
+	ABV            float64
+	AlcoholGrams   float64
+	StandardDrinks float64
+	Calories       float64
+	// Unknown are the measured ingredients that aren't in the table, which are counted as non-alcoholic without calories
+	Unknown []string
+	// Unmeasured are the ingredients without a volume, like garnishes or "top up with soda", which are left out
+	Unmeasured []string
+}
+
+// NewEstimate estimates a serving of the drink
+func NewEstimate(drink model.Drink) Estimate {
+	estimate := Estimate{
+		DrinkId:    drink.Id,
+		DrinkName:  drink.Name,
+		Method:     MethodOf(drink.Instructions),
+		Unknown:    []string{},
+		Unmeasured: []string{},
+	}
+	var ingredientsVolume, alcoholVolume float64
+	for _, ingredient := range drink.Ingredients {
+		profile, known := LookupProfile(model.IngredientKey(ingredient.Name))
+		milliliters, measured := volumeOf(ingredient.Measure, profile)
+		if !measured {
+			estimate.Unmeasured = append(estimate.Unmeasured, ingredient.Name)
+			continue
+		}
+		if !known {
+			estimate.Unknown = append(estimate.Unknown, ingredient.Name)
+		}
+		ingredientsVolume += milliliters
+		alcoholVolume += milliliters * profile.ABV
+		estimate.Calories += milliliters * profile.CaloriesPerMl
+	}
+	if ingredientsVolume == 0 {
+		return estimate
+	}
+
+	estimate.Dilution = ingredientsVolume * estimate.Method.dilution(alcoholVolume/ingredientsVolume)
+	estimate.Volume = ingredientsVolume + estimate.Dilution
+	estimate.ABV = alcoholVolume / estimate.Volume * 100
+	estimate.AlcoholGrams = alcoholVolume * ethanolDensity
+	estimate.StandardDrinks = estimate.AlcoholGrams / StandardDrinkGrams
+	estimate.Calories += estimate.AlcoholGrams * ethanolCalories
+	return estimate
+}
+
+// volumeOf converts the ingredient's measure to milliliters; parts are taken to be measure.PartMilliliters,
+// and the juice of a fruit is the fruit's JuicePerFruit. measured is false for measures without a volume
+func volumeOf(text string, profile Profile) (milliliters float64, measured bool) {
+	quantity, ok := measure.Parse(text)
+	if !ok {
+		return 0, false
+	}
+	if converted, ok := measure.Convert(quantity, measure.Milliliter); ok {
+		return converted.Amount, true
+	}
+	if approximate, ok := approximateMilliliters[quantity.Unit]; ok {
+		return quantity.Amount * approximate, true
+	}
+	if quantity.Unit == measure.Juice && profile.JuicePerFruit > 0 {
+		return quantity.Amount * profile.JuicePerFruit, true
+	}
+	return 0, false
+}
@@ -0,0 +1,94 @@
+package nutrition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestLookupProfile(t *testing.T) {
+	tests := []struct {
+		ingredient  string
+		expectedABV float64
+		expectOk    bool
+	}{
+		{ingredient: "light rum", expectedABV: 0.4, expectOk: true},
+		{ingredient: "sloe gin", expectedABV: 0.26, expectOk: true},
+		{ingredient: "orange bitters", expectedABV: 0.447, expectOk: true},
+		{ingredient: "ginger beer", expectedABV: 0, expectOk: true},
+		{ingredient: "lime juice", expectedABV: 0, expectOk: true},
+		{ingredient: "dry vermouth", expectedABV: 0.18, expectOk: true},
+		{ingredient: "mint", expectOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ingredient, func(t *testing.T) {
+			profile, ok := LookupProfile(tt.ingredient)
+			assert.Equal(t, tt.expectOk, ok)
+			assert.Equal(t, tt.expectedABV, profile.ABV)
+		})
+	}
+}
+
+func TestMethodOf(t *testing.T) {
+	assert.Equal(t, Shaken, MethodOf("Shake with ice and strain into a chilled glass."))
+	assert.Equal(t, Stirred, MethodOf("Stir over ice, strain and serve."))
+	assert.Equal(t, Blended, MethodOf("Blend until smooth, then stir in the rum."))
+	assert.Equal(t, Built, MethodOf("Pour over ice and top with soda."))
+}
+
+func TestNewEstimate(t *testing.T) {
+	margarita := model.Drink{
+		Id:           "11007",
+		Name:         "Margarita",
+		Instructions: "Shake with ice and strain into a salt-rimmed glass.",
+		Ingredients: []model.Ingredient{
+			{Name: "Tequila", Measure: "1 1/2 oz"},
+			{Name: "Triple sec", Measure: "1/2 oz"},
+			{Name: "Lime", Measure: "Juice of 1"},
+			{Name: "Salt"},
+		},
+	}
+	estimate := NewEstimate(margarita)
+
+	assert.Equal(t, "11007", estimate.DrinkId)
+	assert.Equal(t, "Margarita", estimate.DrinkName)
+	assert.Equal(t, Shaken, estimate.Method)
+	// 44.36 ml of tequila and 14.79 ml of triple sec are 22.18 ml of alcohol in 89.15 ml, which is 24.9%
+	assert.InDelta(t, 89.15*(1.567*0.2488*0.2488+1.742*0.2488+0.203), estimate.Dilution, 0.1)
+	assert.InDelta(t, 89.15+estimate.Dilution, estimate.Volume, 0.01)
+	assert.InDelta(t, 22.18/estimate.Volume*100, estimate.ABV, 0.01)
+	assert.InDelta(t, 17.5, estimate.AlcoholGrams, 0.01)
+	assert.InDelta(t, 1.25, estimate.StandardDrinks, 0.01)
+	assert.InDelta(t, 17.5*7+14.79*1.2+30*0.25, estimate.Calories, 0.1)
+	assert.Empty(t, estimate.Unknown)
+	assert.Equal(t, []string{"Salt"}, estimate.Unmeasured)
+}
+
+func TestNewEstimate_UnknownAndSmallMeasures(t *testing.T) {
+	drink := model.Drink{
+		Id:   "1",
+		Name: "Highball",
+		Ingredients: []model.Ingredient{
+			{Name: "Whiskey", Measure: "2 parts"},
+			{Name: "Mystery cordial", Measure: "1 cl"},
+			{Name: "Angostura bitters", Measure: "2 dashes"},
+			{Name: "Ginger ale", Measure: "Top up"},
+		},
+	}
+	estimate := NewEstimate(drink)
+
+	assert.Equal(t, Built, estimate.Method)
+	assert.Zero(t, estimate.Dilution, "A built drink isn't diluted")
+	assert.InDelta(t, 59.147+10+1.84, estimate.Volume, 0.001)
+	assert.Equal(t, []string{"Mystery cordial"}, estimate.Unknown)
+	assert.Equal(t, []string{"Ginger ale"}, estimate.Unmeasured)
+}
+
+func TestNewEstimate_WithoutMeasures(t *testing.T) {
+	estimate := NewEstimate(model.Drink{Id: "2", Name: "Garnish", Ingredients: []model.Ingredient{{Name: "Mint"}}})
+
+	assert.Zero(t, estimate.Volume)
+	assert.Zero(t, estimate.ABV)
+	assert.Equal(t, []string{"Mint"}, estimate.Unmeasured)
+}
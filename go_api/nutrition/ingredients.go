@@ -0,0 +1,107 @@
+package nutrition
+
+import "strings"
+
+// Profile is what the estimate knows about an ingredient
+type Profile struct {
+	// ABV is the alcohol by volume as a fraction, e.g. 0.4 for most spirits
+	ABV float64
+	// CaloriesPerMl are the calories of a milliliter that don't come from the alcohol, e.g. from sugar or cream
+	CaloriesPerMl float64
+	// JuicePerFruit is the milliliters of juice in one fruit, for measures like "Juice of 1/2"
+	JuicePerFruit float64
+}
+
+// ingredientProfiles are checked in order, so that e.g. "sloe gin" is a liqueur rather than a gin,
+// "orange bitters" are bitters and "ginger beer" isn't a beer
+var ingredientProfiles = []struct {
+	keywords []string
+	profile  Profile
+}{
+	// bitters
+	{[]string{"peychaud"}, Profile{ABV: 0.35}},
+	{[]string{"bitters", "angostura"}, Profile{ABV: 0.447}},
+
+	// mixers that share their words with an alcohol
+	{[]string{"ginger beer", "root beer"}, Profile{CaloriesPerMl: 0.42}},
+	{[]string{"ginger ale", "tonic", "tonic water"}, Profile{CaloriesPerMl: 0.34}},
+	{[]string{"cream of coconut", "coconut cream"}, Profile{CaloriesPerMl: 3.3}},
+	{[]string{"irish cream", "baileys"}, Profile{ABV: 0.17, CaloriesPerMl: 2.3}},
+
+	// liqueurs
+	{[]string{"sloe gin"}, Profile{ABV: 0.26, CaloriesPerMl: 1.2}},
+	{[]string{"coconut rum", "malibu"}, Profile{ABV: 0.21, CaloriesPerMl: 1.0}},
+	{[]string{"cherry brandy", "apricot brandy", "blackberry brandy", "peach brandy"}, Profile{ABV: 0.25, CaloriesPerMl: 1.3}},
+	{[]string{"cointreau", "grand marnier", "benedictine", "drambuie"}, Profile{ABV: 0.4, CaloriesPerMl: 1.0}},
+	{[]string{"triple sec", "limoncello"}, Profile{ABV: 0.3, CaloriesPerMl: 1.2}},
+	{[]string{"yellow chartreuse"}, Profile{ABV: 0.4, CaloriesPerMl: 1.0}},
+	{[]string{"chartreuse"}, Profile{ABV: 0.55, CaloriesPerMl: 0.9}},
+	{[]string{"galliano"}, Profile{ABV: 0.423, CaloriesPerMl: 1.0}},
+	{[]string{"sambuca"}, Profile{ABV: 0.38, CaloriesPerMl: 1.4}},
+	{[]string{"southern comfort", "jagermeister"}, Profile{ABV: 0.35, CaloriesPerMl: 0.8}},
+	{[]string{"maraschino liqueur", "luxardo"}, Profile{ABV: 0.32, CaloriesPerMl: 1.2}},
+	{[]string{"amaretto"}, Profile{ABV: 0.28, CaloriesPerMl: 1.6}},
+	{[]string{"campari"}, Profile{ABV: 0.25, CaloriesPerMl: 0.9}},
+	{[]string{"aperol"}, Profile{ABV: 0.11, CaloriesPerMl: 1.2}},
+	{[]string{"kahlua", "coffee liqueur", "frangelico", "midori", "creme de cassis", "chambord"}, Profile{ABV: 0.2, CaloriesPerMl: 1.6}},
+	{[]string{"st. germain", "elderflower liqueur", "schnapps"}, Profile{ABV: 0.2, CaloriesPerMl: 1.2}},
+	{[]string{"falernum"}, Profile{ABV: 0.11, CaloriesPerMl: 1.5}},
+	{[]string{"creme de", "curacao", "liqueur"}, Profile{ABV: 0.25, CaloriesPerMl: 1.3}},
+
+	// spirits
+	{[]string{"everclear", "grain alcohol"}, Profile{ABV: 0.95}},
+	{[]string{"151 proof rum", "overproof rum"}, Profile{ABV: 0.755}},
+	{[]string{"absinthe"}, Profile{ABV: 0.6}},
+	{[]string{"vodka", "gin", "rum", "tequila", "mezcal", "whiskey", "whisky", "bourbon", "scotch", "rye", "brandy", "cognac", "pisco", "cachaca", "applejack", "ouzo", "pernod", "pastis"}, Profile{ABV: 0.4}},
+	{[]string{"sake"}, Profile{ABV: 0.15, CaloriesPerMl: 0.5}},
+
+	// wine and beer
+	{[]string{"champagne", "prosecco", "cava", "sparkling wine"}, Profile{ABV: 0.12, CaloriesPerMl: 0.1}},
+	{[]string{"dry vermouth"}, Profile{ABV: 0.18, CaloriesPerMl: 0.2}},
+	{[]string{"sweet vermouth", "vermouth", "lillet", "dubonnet"}, Profile{ABV: 0.16, CaloriesPerMl: 0.6}},
+	{[]string{"port"}, Profile{ABV: 0.2, CaloriesPerMl: 0.6}},
+	{[]string{"sherry"}, Profile{ABV: 0.17, CaloriesPerMl: 0.3}},
+	{[]string{"wine"}, Profile{ABV: 0.12, CaloriesPerMl: 0.15}},
+	{[]string{"stout", "guinness"}, Profile{ABV: 0.06, CaloriesPerMl: 0.2}},
+	{[]string{"beer", "lager", "ale", "cider"}, Profile{ABV: 0.05, CaloriesPerMl: 0.15}},
+
+	// mixers
+	{[]string{"soda water", "club soda", "carbonated water", "water", "ice"}, Profile{}},
+	{[]string{"cola", "coca-cola", "coke", "sprite", "7-up", "lemonade", "lemon-lime soda"}, Profile{CaloriesPerMl: 0.42}},
+	{[]string{"espresso", "coffee", "tea"}, Profile{CaloriesPerMl: 0.01}},
+	{[]string{"sweet and sour", "sour mix"}, Profile{CaloriesPerMl: 1.0}},
+
+	// sweeteners
+	{[]string{"honey"}, Profile{CaloriesPerMl: 4.3}},
+	{[]string{"agave", "maple syrup"}, Profile{CaloriesPerMl: 3.8}},
+	{[]string{"sugar"}, Profile{CaloriesPerMl: 3.4}},
+	{[]string{"grenadine", "orgeat", "syrup"}, Profile{CaloriesPerMl: 2.6}},
+
+	// dairy
+	{[]string{"half-and-half"}, Profile{CaloriesPerMl: 1.3}},
+	{[]string{"cream", "whipped cream"}, Profile{CaloriesPerMl: 3.4}},
+	{[]string{"coconut milk"}, Profile{CaloriesPerMl: 2.0}},
+	{[]string{"milk"}, Profile{CaloriesPerMl: 0.64}},
+
+	// juices, and the fruit that's juiced
+	{[]string{"lime"}, Profile{CaloriesPerMl: 0.25, JuicePerFruit: 30}},
+	{[]string{"lemon"}, Profile{CaloriesPerMl: 0.22, JuicePerFruit: 45}},
+	{[]string{"orange"}, Profile{CaloriesPerMl: 0.45, JuicePerFruit: 80}},
+	{[]string{"grapefruit"}, Profile{CaloriesPerMl: 0.39, JuicePerFruit: 120}},
+	{[]string{"tomato"}, Profile{CaloriesPerMl: 0.17}},
+	{[]string{"juice", "nectar"}, Profile{CaloriesPerMl: 0.5}},
+}
+
+// LookupProfile finds the profile of an ingredient from the words in its normalized name (see model.IngredientKey);
+// ok is false if the ingredient isn't in the table
+func LookupProfile(ingredient string) (profile Profile, ok bool) {
+	words := " " + ingredient + " "
+	for _, candidate := range ingredientProfiles {
+		for _, keyword := range candidate.keywords {
+			if strings.Contains(words, " "+keyword+" ") || strings.Contains(words, " "+keyword+"s ") {
+				return candidate.profile, true
+			}
+		}
+	}
+	return Profile{}, false
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"the-drink-almanac-api/catalog"
+	"the-drink-almanac-api/maintenance"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+)
+
+// runOrphanedFavorites reports the favorites of drinks and recipes that no longer exist as csv, and optionally removes them
+func runOrphanedFavorites(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("orphaned-favorites", flag.ContinueOnError)
+	remove := flags.Bool("remove", false, "delete the orphaned favorites instead of only reporting them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: orphaned-favorites [-remove]")
+	}
+	// without a lookup every drink exists, so nothing would ever be reported
+	if appConfig.DrinkLookup == model.DrinkLookupNone {
+		return fmt.Errorf("DRINK_LOOKUP must be '%s' to find orphaned favorites", model.DrinkLookupCatalog)
+	}
+	drinks, err := catalog.NewDrinkRepository(appConfig)
+	if err != nil {
+		return err
+	}
+	drinkLookup, err := catalog.NewDrinkLookup(appConfig, drinks)
+	if err != nil {
+		return err
+	}
+	_, favoriteStore, err := repository.NewRepositories(appConfig)
+	if err != nil {
+		return err
+	}
+
+	recipeStore, err := repository.NewRecipeRepository(appConfig)
+	if err != nil {
+		return err
+	}
+
+	favoriteService := service.NewDefaultFavoriteService(favoriteStore)
+	recipeService := service.NewDefaultRecipeService(recipeStore)
+	report, cleanErr := maintenance.NewOrphanedFavoriteCleaner(favoriteService, drinkLookup, recipeService, *remove).Clean()
+	if err := report.WriteCSV(os.Stdout); err != nil {
+		return err
+	}
+	if cleanErr != nil {
+		return fmt.Errorf("failed to clean up the orphaned favorites: %w", cleanErr)
+	}
+
+	// the report is written to stdout, so the summary goes to stderr
+	fmt.Fprintf(os.Stderr, "checked %d favorites, found %d orphaned, removed %d\n", report.Checked, len(report.Orphaned), report.Removed)
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"the-drink-almanac-api/events"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+// errNoEventSink is returned when the app config doesn't enable any sink,
+// in which case the outbox is left alone rather than relayed to nobody
+var errNoEventSink = errors.New("no event sink is configured; set EVENTS_FILE or EVENTS_QUEUE_URL to publish the outbox")
+
+// newEventBus creates the bus that the outbox's events are relayed to, with the sinks enabled by the app config
+func newEventBus(appConfig model.AppConfig) (*events.Bus, error) {
+	bus := events.NewBus()
+	if appConfig.EventsFile != "" {
+		bus.SubscribeSink(events.NewFileSink(appConfig.EventsFile))
+	}
+	if appConfig.EventsQueueUrl != "" {
+		queue, err := events.NewSQSQueue(appConfig.AwsEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		bus.SubscribeSink(events.NewQueueSink(queue, appConfig.EventsQueueUrl))
+	}
+	return bus, nil
+}
+
+// newEventRelay creates the relay from the outbox of the configured table design to the event bus,
+// or returns errNoEventSink if the bus wouldn't have any sinks
+func newEventRelay(appConfig model.AppConfig) (*events.Relay, error) {
+	bus, err := newEventBus(appConfig)
+	if err != nil {
+		return nil, err
+	}
+	if !bus.HasSubscribers() {
+		return nil, errNoEventSink
+	}
+	outbox, err := repository.NewOutboxRepository(appConfig)
+	if err != nil {
+		return nil, err
+	}
+	return events.NewRelay(outbox, bus), nil
+}
+
+// runRelayEvents publishes every pending event in the outbox once, e.g. for the lambdas' events from a scheduled job
+func runRelayEvents(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("relay-events", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	relay, err := newEventRelay(appConfig)
+	if err != nil {
+		return err
+	}
+	published, err := relay.RelayPending(context.TODO())
+	fmt.Printf("published %d events\n", published)
+	return err
+}
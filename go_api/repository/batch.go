@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// batchGetSize is the most keys that dynamodb accepts in one BatchGetItem request
+	batchGetSize = 100
+	// maxConcurrentBatchGets limits how many BatchGetItem requests a single lookup sends at once
+	maxConcurrentBatchGets = 4
+	// maxBatchGetAttempts is how many times a batch is sent before its unprocessed keys are given up on
+	maxBatchGetAttempts = 5
+)
+
+// batchGetRetryDelay is the initial delay before unprocessed keys are requested again; it's doubled after every attempt
+var batchGetRetryDelay = 25 * time.Millisecond
+
+// batchGetRecords reads the items with the given keys in batches of batchGetSize, sending up to maxConcurrentBatchGets batches at once;
+// the keys must be distinct, and missing items are left out of the result
+func batchGetRecords(db client.DDBClient, tableName string, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	var batches [][]map[string]types.AttributeValue
+	for start := 0; start < len(keys); start += batchGetSize {
+		end := start + batchGetSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batches = append(batches, keys[start:end])
+	}
+
+	results := make([][]map[string]types.AttributeValue, len(batches))
+	errs := make([]error, len(batches))
+	semaphore := make(chan struct{}, maxConcurrentBatchGets)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, batch []map[string]types.AttributeValue) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i], errs[i] = batchGet(db, tableName, batch)
+		}(i, batch)
+	}
+	wg.Wait()
+
+	items := []map[string]types.AttributeValue{}
+	for i := range batches {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		items = append(items, results[i]...)
+	}
+	return items, nil
+}
+
+// batchGet sends a single batch, requesting the keys that dynamodb didn't process again with a backoff
+func batchGet(db client.DDBClient, tableName string, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	items := []map[string]types.AttributeValue{}
+	delay := batchGetRetryDelay
+	for attempt := 1; ; attempt++ {
+		output, err := db.BatchGetItem(context.TODO(), &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]types.KeysAndAttributes{tableName: {Keys: keys}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, output.Responses[tableName]...)
+
+		keys = output.UnprocessedKeys[tableName].Keys
+		if len(keys) == 0 {
+			return items, nil
+		}
+		if attempt == maxBatchGetAttempts {
+			return nil, apperrors.NewUnavailableError("the items couldn't all be read; please try again later", nil)
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
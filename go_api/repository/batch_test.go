@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDrinkRepositoryDDB_FindDrinksByIds_UnprocessedKeys(t *testing.T) {
+	batchGetRetryDelay = 0
+	drinkItem := func(id string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"id":   &types.AttributeValueMemberS{Value: id},
+			"name": &types.AttributeValueMemberS{Value: "Drink " + id},
+		}
+	}
+	drinkKey := func(id string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}}
+	}
+	requestsKeys := func(ids ...string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.BatchGetItemInput) bool {
+			keys := input.RequestItems["drinks"].Keys
+			if len(keys) != len(ids) {
+				return false
+			}
+			for i, id := range ids {
+				if keys[i]["id"].(*types.AttributeValueMemberS).Value != id {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	unprocessed := func(ids ...string) map[string]types.KeysAndAttributes {
+		keys := []map[string]types.AttributeValue{}
+		for _, id := range ids {
+			keys = append(keys, drinkKey(id))
+		}
+		return map[string]types.KeysAndAttributes{"drinks": {Keys: keys}}
+	}
+
+	tests := []struct {
+		name           string
+		mockCalls      func(db *client.MockDDBClient)
+		expectedDrinks map[string]model.Drink
+		expectError    bool
+		// expectUnavailable is set when the keys were never processed
+		expectUnavailable bool
+	}{
+		{
+			name: "Requests the unprocessed keys again",
+			mockCalls: func(db *client.MockDDBClient) {
+				db.On("BatchGetItem", context.TODO(), requestsKeys("a", "b")).Return(&dynamodb.BatchGetItemOutput{
+					Responses:       map[string][]map[string]types.AttributeValue{"drinks": {drinkItem("a")}},
+					UnprocessedKeys: unprocessed("b"),
+				}, nil).Once()
+				db.On("BatchGetItem", context.TODO(), requestsKeys("b")).Return(&dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]types.AttributeValue{"drinks": {drinkItem("b")}},
+				}, nil).Once()
+			},
+			expectedDrinks: map[string]model.Drink{"a": {Id: "a", Name: "Drink a"}, "b": {Id: "b", Name: "Drink b"}},
+		},
+		{
+			name: "Gives up on keys that are never processed",
+			mockCalls: func(db *client.MockDDBClient) {
+				db.On("BatchGetItem", context.TODO(), requestsKeys("a", "b")).Return(&dynamodb.BatchGetItemOutput{
+					Responses:       map[string][]map[string]types.AttributeValue{"drinks": {drinkItem("a")}},
+					UnprocessedKeys: unprocessed("b"),
+				}, nil).Once()
+				db.On("BatchGetItem", context.TODO(), requestsKeys("b")).Return(&dynamodb.BatchGetItemOutput{
+					UnprocessedKeys: unprocessed("b"),
+				}, nil).Times(maxBatchGetAttempts - 1)
+			},
+			expectError:       true,
+			expectUnavailable: true,
+		},
+		{
+			name: "Failed to read the batch",
+			mockCalls: func(db *client.MockDDBClient) {
+				db.On("BatchGetItem", context.TODO(), requestsKeys("a", "b")).Return(nil, errors.New("failed"))
+			},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			tt.mockCalls(mockDdbClient)
+			drinkStore := DrinkRepositoryDDB{DynamodbClient: mockDdbClient, TableName: "drinks"}
+
+			drinks, err := drinkStore.FindDrinksByIds([]string{"a", "b", "a", ""})
+			if tt.expectError {
+				assert.Error(t, err, "An error should have been returned from FindDrinksByIds")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from FindDrinksByIds")
+			}
+			assert.Equal(t, tt.expectUnavailable, errors.As(err, &apperrors.UnavailableError{}))
+			assert.Equal(t, tt.expectedDrinks, drinks, "The drinks don't match the batches")
+		})
+	}
+}
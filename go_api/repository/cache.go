@@ -0,0 +1,134 @@
+//go:generate mockery --name=Cache --output=./ --outpkg=repository --filename=cache_mock.go --inpackage
+package repository
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Cache stores serialized repository results so that repeated lookups don't hit dynamodb;
+// a shared backend (e.g. redis) can implement it so that every instance of the api sees the same entries
+type Cache interface {
+	// Get returns the value stored for the key, or false if there's no entry or it has expired
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(keys ...string)
+}
+
+// LRUCache is an in-memory Cache that holds at most capacity entries,
+// evicting the least recently used entry once it's full
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache creates an empty cache; a capacity of 0 or less disables caching
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	if c.capacity <= 0 || ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRUCache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+}
+
+// Len returns the number of entries in the cache, including any that have expired but haven't been evicted yet
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
+
+// readThrough returns the cached value for the key if there is one;
+// otherwise, it loads the value and caches it unless it's nil
+//
+// Entries that can't be decoded are treated as missing, so a bad entry never breaks a lookup
+func readThrough[T any](cache Cache, key string, ttl time.Duration, load func() (*T, error)) (*T, error) {
+	if cached, ok := cache.Get(key); ok {
+		value := new(T)
+		if err := jsoniter.Unmarshal(cached, value); err == nil {
+			return value, nil
+		}
+	}
+
+	value, err := load()
+	if err != nil || value == nil {
+		return value, err
+	}
+	storeInCache(cache, ttl, value, key)
+	return value, nil
+}
+
+// storeInCache caches the value under each of the keys
+func storeInCache(cache Cache, ttl time.Duration, value interface{}, keys ...string) {
+	encoded, err := jsoniter.Marshal(value)
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		cache.Set(key, encoded, ttl)
+	}
+}
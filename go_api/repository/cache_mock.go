@@ -0,0 +1,70 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package repository
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCache is an autogenerated mock type for the Cache type
+type MockCache struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: keys
+func (_m *MockCache) Delete(keys ...string) {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
+
+// Get provides a mock function with given fields: key
+func (_m *MockCache) Get(key string) ([]byte, bool) {
+	ret := _m.Called(key)
+
+	var r0 []byte
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) ([]byte, bool)); ok {
+		return rf(key)
+	}
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// Set provides a mock function with given fields: key, value, ttl
+func (_m *MockCache) Set(key string, value []byte, ttl time.Duration) {
+	_m.Called(key, value, ttl)
+}
+
+// NewMockCache creates a new instance of MockCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCache {
+	mock := &MockCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLRUCache(2)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+
+	// b is the least recently used entry, so it's evicted to make room for c
+	cache.Set("c", []byte("3"), time.Minute)
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "the least recently used entry should have been evicted")
+	value, ok := cache.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("3"), value)
+
+	cache.Delete("c", "missing")
+	_, ok = cache.Get("c")
+	assert.False(t, ok, "the deleted entry should be missing")
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("a")
+	assert.False(t, ok, "the entry should have expired")
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLRUCache_Disabled(t *testing.T) {
+	cache := NewLRUCache(0)
+	cache.Set("a", []byte("1"), time.Minute)
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+}
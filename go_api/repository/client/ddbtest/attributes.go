@@ -0,0 +1,288 @@
+package ddbtest
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// wireValue is the json representation of an AttributeValue in the dynamodb protocol (e.g. {"S": "value"});
+// the list and map members are pointers so that empty lists and maps survive a round trip
+type wireValue struct {
+	S    *string               `json:"S,omitempty"`
+	N    *string               `json:"N,omitempty"`
+	B    []byte                `json:"B,omitempty"`
+	BOOL *bool                 `json:"BOOL,omitempty"`
+	NULL *bool                 `json:"NULL,omitempty"`
+	M    *map[string]wireValue `json:"M,omitempty"`
+	L    *[]wireValue          `json:"L,omitempty"`
+	SS   []string              `json:"SS,omitempty"`
+	NS   []string              `json:"NS,omitempty"`
+	BS   [][]byte              `json:"BS,omitempty"`
+}
+
+type wireItem map[string]wireValue
+
+func (v wireValue) attributeValue() (types.AttributeValue, error) {
+	switch {
+	case v.S != nil:
+		return &types.AttributeValueMemberS{Value: *v.S}, nil
+	case v.N != nil:
+		if _, ok := parseNumber(*v.N); !ok {
+			return nil, fmt.Errorf("the number '%s' isn't valid", *v.N)
+		}
+		return &types.AttributeValueMemberN{Value: *v.N}, nil
+	case v.B != nil:
+		return &types.AttributeValueMemberB{Value: v.B}, nil
+	case v.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *v.BOOL}, nil
+	case v.NULL != nil:
+		return &types.AttributeValueMemberNULL{Value: *v.NULL}, nil
+	case v.M != nil:
+		item, err := wireItem(*v.M).item()
+		return &types.AttributeValueMemberM{Value: item}, err
+	case v.L != nil:
+		list := make([]types.AttributeValue, len(*v.L))
+		for i, element := range *v.L {
+			value, err := element.attributeValue()
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return &types.AttributeValueMemberL{Value: list}, nil
+	case v.SS != nil:
+		return &types.AttributeValueMemberSS{Value: v.SS}, nil
+	case v.NS != nil:
+		for _, number := range v.NS {
+			if _, ok := parseNumber(number); !ok {
+				return nil, fmt.Errorf("the number '%s' isn't valid", number)
+			}
+		}
+		return &types.AttributeValueMemberNS{Value: v.NS}, nil
+	case v.BS != nil:
+		return &types.AttributeValueMemberBS{Value: v.BS}, nil
+	}
+	return nil, fmt.Errorf("the attribute value doesn't have a type")
+}
+
+func (w wireItem) item() (map[string]types.AttributeValue, error) {
+	if w == nil {
+		return nil, nil
+	}
+	item := make(map[string]types.AttributeValue, len(w))
+	for name, wire := range w {
+		value, err := wire.attributeValue()
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for the attribute '%s': %w", name, err)
+		}
+		item[name] = value
+	}
+	return item, nil
+}
+
+func toWire(value types.AttributeValue) wireValue {
+	switch value := value.(type) {
+	case *types.AttributeValueMemberS:
+		return wireValue{S: &value.Value}
+	case *types.AttributeValueMemberN:
+		return wireValue{N: &value.Value}
+	case *types.AttributeValueMemberB:
+		return wireValue{B: value.Value}
+	case *types.AttributeValueMemberBOOL:
+		return wireValue{BOOL: &value.Value}
+	case *types.AttributeValueMemberNULL:
+		return wireValue{NULL: &value.Value}
+	case *types.AttributeValueMemberM:
+		m := map[string]wireValue(toWireItem(value.Value))
+		return wireValue{M: &m}
+	case *types.AttributeValueMemberL:
+		list := make([]wireValue, len(value.Value))
+		for i, element := range value.Value {
+			list[i] = toWire(element)
+		}
+		return wireValue{L: &list}
+	case *types.AttributeValueMemberSS:
+		return wireValue{SS: value.Value}
+	case *types.AttributeValueMemberNS:
+		return wireValue{NS: value.Value}
+	case *types.AttributeValueMemberBS:
+		return wireValue{BS: value.Value}
+	}
+	return wireValue{}
+}
+
+func toWireItem(item map[string]types.AttributeValue) wireItem {
+	if item == nil {
+		return nil
+	}
+	wire := make(wireItem, len(item))
+	for name, value := range item {
+		wire[name] = toWire(value)
+	}
+	return wire
+}
+
+func toWireItems(items []map[string]types.AttributeValue) []wireItem {
+	wire := make([]wireItem, len(items))
+	for i, item := range items {
+		wire[i] = toWireItem(item)
+	}
+	return wire
+}
+
+func parseNumber(number string) (*big.Float, bool) {
+	value, _, err := big.ParseFloat(number, 10, 128, big.ToNearestEven)
+	return value, err == nil
+}
+
+// typeName returns the dynamodb type descriptor of the value (e.g. "S" or "NS")
+func typeName(value types.AttributeValue) string {
+	switch value.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberM:
+		return "M"
+	case *types.AttributeValueMemberL:
+		return "L"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	}
+	return ""
+}
+
+// compareValues orders two scalar values of the same type (S, N or B);
+// ok is false if the values can't be ordered
+func compareValues(a, b types.AttributeValue) (result int, ok bool) {
+	switch a := a.(type) {
+	case *types.AttributeValueMemberS:
+		if b, isString := b.(*types.AttributeValueMemberS); isString {
+			switch {
+			case a.Value < b.Value:
+				return -1, true
+			case a.Value > b.Value:
+				return 1, true
+			}
+			return 0, true
+		}
+	case *types.AttributeValueMemberN:
+		if b, isNumber := b.(*types.AttributeValueMemberN); isNumber {
+			aNumber, _ := parseNumber(a.Value)
+			bNumber, _ := parseNumber(b.Value)
+			return aNumber.Cmp(bNumber), true
+		}
+	case *types.AttributeValueMemberB:
+		if b, isBinary := b.(*types.AttributeValueMemberB); isBinary {
+			return bytes.Compare(a.Value, b.Value), true
+		}
+	}
+	return 0, false
+}
+
+// equalValues compares values of any type the way dynamodb's = operator does;
+// the elements of sets are compared regardless of their order
+func equalValues(a, b types.AttributeValue) bool {
+	if typeName(a) != typeName(b) {
+		return false
+	}
+	if result, ok := compareValues(a, b); ok {
+		return result == 0
+	}
+	switch a := a.(type) {
+	case *types.AttributeValueMemberBOOL:
+		return a.Value == b.(*types.AttributeValueMemberBOOL).Value
+	case *types.AttributeValueMemberNULL:
+		return true
+	case *types.AttributeValueMemberM:
+		bMap := b.(*types.AttributeValueMemberM).Value
+		if len(a.Value) != len(bMap) {
+			return false
+		}
+		for name, value := range a.Value {
+			other, ok := bMap[name]
+			if !ok || !equalValues(value, other) {
+				return false
+			}
+		}
+		return true
+	case *types.AttributeValueMemberL:
+		bList := b.(*types.AttributeValueMemberL).Value
+		if len(a.Value) != len(bList) {
+			return false
+		}
+		for i := range a.Value {
+			if !equalValues(a.Value[i], bList[i]) {
+				return false
+			}
+		}
+		return true
+	case *types.AttributeValueMemberSS:
+		return equalStrings(a.Value, b.(*types.AttributeValueMemberSS).Value)
+	case *types.AttributeValueMemberNS:
+		return equalStrings(normalizeNumbers(a.Value), normalizeNumbers(b.(*types.AttributeValueMemberNS).Value))
+	case *types.AttributeValueMemberBS:
+		aStrings := make([]string, len(a.Value))
+		for i, value := range a.Value {
+			aStrings[i] = string(value)
+		}
+		bValues := b.(*types.AttributeValueMemberBS).Value
+		bStrings := make([]string, len(bValues))
+		for i, value := range bValues {
+			bStrings[i] = string(value)
+		}
+		return equalStrings(aStrings, bStrings)
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeNumbers(numbers []string) []string {
+	normalized := make([]string, len(numbers))
+	for i, number := range numbers {
+		value, _ := parseNumber(number)
+		normalized[i] = value.Text('g', -1)
+	}
+	return normalized
+}
+
+// copyItem makes a shallow copy of the item, which is enough since stored values are never modified in place
+func copyItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if item == nil {
+		return nil
+	}
+	copied := make(map[string]types.AttributeValue, len(item))
+	for name, value := range item {
+		copied[name] = value
+	}
+	return copied
+}
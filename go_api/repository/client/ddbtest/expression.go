@@ -0,0 +1,816 @@
+package ddbtest
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// placeholders resolves the #name and :value placeholders of a request's expressions,
+// recording which ones were used so that unused placeholders can be rejected like dynamodb does
+type placeholders struct {
+	names      map[string]string
+	values     map[string]types.AttributeValue
+	usedNames  map[string]bool
+	usedValues map[string]bool
+}
+
+func newPlaceholders(names map[string]string, values map[string]types.AttributeValue) *placeholders {
+	return &placeholders{names: names, values: values, usedNames: map[string]bool{}, usedValues: map[string]bool{}}
+}
+
+func (p *placeholders) name(placeholder string) (string, error) {
+	name, ok := p.names[placeholder]
+	if !ok {
+		return "", fmt.Errorf("An expression attribute name used in the document path is not defined; attribute name: %s", placeholder)
+	}
+	p.usedNames[placeholder] = true
+	return name, nil
+}
+
+func (p *placeholders) value(placeholder string) (types.AttributeValue, error) {
+	value, ok := p.values[placeholder]
+	if !ok {
+		return nil, fmt.Errorf("An expression attribute value used in expression is not defined; attribute value: %s", placeholder)
+	}
+	p.usedValues[placeholder] = true
+	return value, nil
+}
+
+// checkUnused returns an error if any placeholder wasn't used by the request's expressions
+func (p *placeholders) checkUnused() error {
+	for placeholder := range p.names {
+		if !p.usedNames[placeholder] {
+			return fmt.Errorf("Value provided in ExpressionAttributeNames unused in expressions: keys: {%s}", placeholder)
+		}
+	}
+	for placeholder := range p.values {
+		if !p.usedValues[placeholder] {
+			return fmt.Errorf("Value provided in ExpressionAttributeValues unused in expressions: keys: {%s}", placeholder)
+		}
+	}
+	return nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdentifier
+	tokenName
+	tokenValue
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expression string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(expression)
+	isNameRune := func(r rune) bool { return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) }
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '#' || r == ':':
+			start := i
+			i++
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("Invalid expression: a placeholder must have a name; expression: %s", expression)
+			}
+			kind := tokenName
+			if r == ':' {
+				kind = tokenValue
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i])})
+		case isNameRune(r):
+			start := i
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[start:i])})
+		default:
+			text := string(r)
+			if i+1 < len(runes) {
+				if pair := string(runes[i : i+2]); pair == "<>" || pair == "<=" || pair == ">=" {
+					text = pair
+				}
+			}
+			if !strings.Contains("()[],.=<>+-", string(r)) {
+				return nil, fmt.Errorf("Invalid expression: unexpected character '%c'; expression: %s", r, expression)
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: text})
+			i += len([]rune(text))
+		}
+	}
+	return append(tokens, token{kind: tokenEnd}), nil
+}
+
+type parser struct {
+	expression   string
+	tokens       []token
+	position     int
+	placeholders *placeholders
+}
+
+func newParser(expression string, placeholders *placeholders) (*parser, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{expression: expression, tokens: tokens, placeholders: placeholders}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEnd {
+		p.position++
+	}
+	return t
+}
+
+// accept consumes the next token if it's the given symbol or keyword (keywords are case insensitive)
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokenSymbol && t.text == text) || (t.kind == tokenIdentifier && strings.EqualFold(t.text, text)) {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("expected '%s'", text)
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	near := p.peek().text
+	if p.peek().kind == tokenEnd {
+		near = "the end of the expression"
+	}
+	return fmt.Errorf("Invalid expression: %s near %s; expression: %s", fmt.Sprintf(format, args...), near, p.expression)
+}
+
+// path is a document path, e.g. a.b[0]; string elements are map keys and int elements are list indexes
+type path []interface{}
+
+func (p path) String() string {
+	var builder strings.Builder
+	for i, element := range p {
+		switch element := element.(type) {
+		case string:
+			if i > 0 {
+				builder.WriteByte('.')
+			}
+			builder.WriteString(element)
+		case int:
+			fmt.Fprintf(&builder, "[%d]", element)
+		}
+	}
+	return builder.String()
+}
+
+func (p path) resolve(item map[string]types.AttributeValue) (types.AttributeValue, bool) {
+	var current types.AttributeValue = &types.AttributeValueMemberM{Value: item}
+	for _, element := range p {
+		switch element := element.(type) {
+		case string:
+			m, ok := current.(*types.AttributeValueMemberM)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m.Value[element]; !ok {
+				return nil, false
+			}
+		case int:
+			l, ok := current.(*types.AttributeValueMemberL)
+			if !ok || element >= len(l.Value) {
+				return nil, false
+			}
+			current = l.Value[element]
+		}
+	}
+	return current, true
+}
+
+func (p *parser) parsePath() (path, error) {
+	result := path{}
+	for {
+		t := p.next()
+		switch t.kind {
+		case tokenIdentifier:
+			result = append(result, t.text)
+		case tokenName:
+			name, err := p.placeholders.name(t.text)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, name)
+		default:
+			p.position--
+			return nil, p.errorf("expected an attribute name")
+		}
+		for p.accept("[") {
+			index := p.next()
+			if index.kind != tokenNumber {
+				p.position--
+				return nil, p.errorf("expected a list index")
+			}
+			i, _ := strconv.Atoi(index.text)
+			result = append(result, i)
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		}
+		if !p.accept(".") {
+			return result, nil
+		}
+	}
+}
+
+// operand is a value in an expression: a document path, a :value placeholder or a function of them
+type operand interface {
+	// evaluate returns the operand's value for the item, or false if the path doesn't exist in the item
+	evaluate(item map[string]types.AttributeValue) (types.AttributeValue, bool, error)
+}
+
+type pathOperand struct{ path path }
+
+func (o pathOperand) evaluate(item map[string]types.AttributeValue) (types.AttributeValue, bool, error) {
+	value, ok := o.path.resolve(item)
+	return value, ok, nil
+}
+
+type valueOperand struct{ value types.AttributeValue }
+
+func (o valueOperand) evaluate(map[string]types.AttributeValue) (types.AttributeValue, bool, error) {
+	return o.value, true, nil
+}
+
+type sizeOperand struct{ path path }
+
+func (o sizeOperand) evaluate(item map[string]types.AttributeValue) (types.AttributeValue, bool, error) {
+	value, ok := o.path.resolve(item)
+	if !ok {
+		return nil, false, nil
+	}
+	var size int
+	switch value := value.(type) {
+	case *types.AttributeValueMemberS:
+		size = len(value.Value)
+	case *types.AttributeValueMemberB:
+		size = len(value.Value)
+	case *types.AttributeValueMemberM:
+		size = len(value.Value)
+	case *types.AttributeValueMemberL:
+		size = len(value.Value)
+	case *types.AttributeValueMemberSS:
+		size = len(value.Value)
+	case *types.AttributeValueMemberNS:
+		size = len(value.Value)
+	case *types.AttributeValueMemberBS:
+		size = len(value.Value)
+	default:
+		return nil, false, fmt.Errorf("Invalid expression: the size function isn't supported for the type %s of %s", typeName(value), o.path)
+	}
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(size)}, true, nil
+}
+
+// ifNotExistsOperand and arithmeticOperand are only valid in the SET action of an update expression
+type ifNotExistsOperand struct {
+	path     path
+	fallback operand
+}
+
+func (o ifNotExistsOperand) evaluate(item map[string]types.AttributeValue) (types.AttributeValue, bool, error) {
+	if value, ok := o.path.resolve(item); ok {
+		return value, true, nil
+	}
+	return o.fallback.evaluate(item)
+}
+
+type listAppendOperand struct{ first, second operand }
+
+func (o listAppendOperand) evaluate(item map[string]types.AttributeValue) (types.AttributeValue, bool, error) {
+	lists := [][]types.AttributeValue{}
+	for _, operand := range []operand{o.first, o.second} {
+		value, ok, err := operand.evaluate(item)
+		if err != nil {
+			return nil, false, err
+		}
+		list, isList := value.(*types.AttributeValueMemberL)
+		if !ok || !isList {
+			return nil, false, fmt.Errorf("Invalid UpdateExpression: the operands of list_append must be lists")
+		}
+		lists = append(lists, list.Value)
+	}
+	return &types.AttributeValueMemberL{Value: append(append([]types.AttributeValue{}, lists[0]...), lists[1]...)}, true, nil
+}
+
+type arithmeticOperand struct {
+	left, right operand
+	subtract    bool
+}
+
+func (o arithmeticOperand) evaluate(item map[string]types.AttributeValue) (types.AttributeValue, bool, error) {
+	numbers := []*big.Float{}
+	for _, operand := range []operand{o.left, o.right} {
+		value, ok, err := operand.evaluate(item)
+		if err != nil {
+			return nil, false, err
+		}
+		number, isNumber := value.(*types.AttributeValueMemberN)
+		if !ok || !isNumber {
+			return nil, false, fmt.Errorf("An operand in the update expression has an incorrect data type")
+		}
+		parsed, _ := parseNumber(number.Value)
+		numbers = append(numbers, parsed)
+	}
+	result := new(big.Float).SetPrec(128)
+	if o.subtract {
+		result.Sub(numbers[0], numbers[1])
+	} else {
+		result.Add(numbers[0], numbers[1])
+	}
+	return &types.AttributeValueMemberN{Value: result.Text('g', -1)}, true, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.peek()
+	if t.kind == tokenValue {
+		p.next()
+		value, err := p.placeholders.value(t.text)
+		if err != nil {
+			return nil, err
+		}
+		return valueOperand{value: value}, nil
+	}
+	if t.kind == tokenIdentifier && strings.EqualFold(t.text, "size") && p.tokens[p.position+1].text == "(" {
+		p.position += 2
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return sizeOperand{path: path}, p.expect(")")
+	}
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	return pathOperand{path: path}, nil
+}
+
+// condition is a condition, filter or key condition expression
+type condition interface {
+	matches(item map[string]types.AttributeValue) (bool, error)
+}
+
+type andCondition struct{ left, right condition }
+
+func (c andCondition) matches(item map[string]types.AttributeValue) (bool, error) {
+	left, err := c.left.matches(item)
+	if err != nil || !left {
+		return false, err
+	}
+	return c.right.matches(item)
+}
+
+type orCondition struct{ left, right condition }
+
+func (c orCondition) matches(item map[string]types.AttributeValue) (bool, error) {
+	left, err := c.left.matches(item)
+	if err != nil || left {
+		return left, err
+	}
+	return c.right.matches(item)
+}
+
+type notCondition struct{ condition condition }
+
+func (c notCondition) matches(item map[string]types.AttributeValue) (bool, error) {
+	matches, err := c.condition.matches(item)
+	return !matches, err
+}
+
+type comparison struct {
+	left, right operand
+	comparator  string
+}
+
+func (c comparison) matches(item map[string]types.AttributeValue) (bool, error) {
+	left, leftExists, err := c.left.evaluate(item)
+	if err != nil {
+		return false, err
+	}
+	right, rightExists, err := c.right.evaluate(item)
+	if err != nil {
+		return false, err
+	}
+	// comparisons with a missing attribute are false, except that a missing attribute is never equal to a value
+	if !leftExists || !rightExists {
+		return c.comparator == "<>", nil
+	}
+	switch c.comparator {
+	case "=":
+		return equalValues(left, right), nil
+	case "<>":
+		return !equalValues(left, right), nil
+	}
+	result, ok := compareValues(left, right)
+	if !ok {
+		return false, nil
+	}
+	switch c.comparator {
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	}
+	return result >= 0, nil
+}
+
+type betweenCondition struct{ value, lower, upper operand }
+
+func (c betweenCondition) matches(item map[string]types.AttributeValue) (bool, error) {
+	lower, err := comparison{left: c.value, right: c.lower, comparator: ">="}.matches(item)
+	if err != nil || !lower {
+		return false, err
+	}
+	return comparison{left: c.value, right: c.upper, comparator: "<="}.matches(item)
+}
+
+type inCondition struct {
+	value   operand
+	options []operand
+}
+
+func (c inCondition) matches(item map[string]types.AttributeValue) (bool, error) {
+	for _, option := range c.options {
+		matches, err := comparison{left: c.value, right: option, comparator: "="}.matches(item)
+		if err != nil || matches {
+			return matches, err
+		}
+	}
+	return false, nil
+}
+
+type functionCondition struct {
+	name      string
+	path      path
+	arguments []operand
+}
+
+func (c functionCondition) matches(item map[string]types.AttributeValue) (bool, error) {
+	value, exists := c.path.resolve(item)
+	switch c.name {
+	case "attribute_exists":
+		return exists, nil
+	case "attribute_not_exists":
+		return !exists, nil
+	}
+
+	argument, argumentExists, err := c.arguments[0].evaluate(item)
+	if err != nil || !exists || !argumentExists {
+		return false, err
+	}
+	switch c.name {
+	case "attribute_type":
+		expected, ok := argument.(*types.AttributeValueMemberS)
+		return ok && typeName(value) == expected.Value, nil
+	case "begins_with":
+		switch value := value.(type) {
+		case *types.AttributeValueMemberS:
+			prefix, ok := argument.(*types.AttributeValueMemberS)
+			return ok && strings.HasPrefix(value.Value, prefix.Value), nil
+		case *types.AttributeValueMemberB:
+			prefix, ok := argument.(*types.AttributeValueMemberB)
+			return ok && strings.HasPrefix(string(value.Value), string(prefix.Value)), nil
+		}
+		return false, nil
+	}
+
+	// contains
+	switch value := value.(type) {
+	case *types.AttributeValueMemberS:
+		substring, ok := argument.(*types.AttributeValueMemberS)
+		return ok && strings.Contains(value.Value, substring.Value), nil
+	case *types.AttributeValueMemberL:
+		for _, element := range value.Value {
+			if equalValues(element, argument) {
+				return true, nil
+			}
+		}
+	case *types.AttributeValueMemberSS:
+		if member, ok := argument.(*types.AttributeValueMemberS); ok {
+			for _, element := range value.Value {
+				if element == member.Value {
+					return true, nil
+				}
+			}
+		}
+	case *types.AttributeValueMemberNS:
+		if member, ok := argument.(*types.AttributeValueMemberN); ok {
+			for _, element := range value.Value {
+				if equalValues(&types.AttributeValueMemberN{Value: element}, member) {
+					return true, nil
+				}
+			}
+		}
+	case *types.AttributeValueMemberBS:
+		if member, ok := argument.(*types.AttributeValueMemberB); ok {
+			for _, element := range value.Value {
+				if string(element) == string(member.Value) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+var conditionFunctions = map[string]int{
+	"attribute_exists":     0,
+	"attribute_not_exists": 0,
+	"attribute_type":       1,
+	"begins_with":          1,
+	"contains":             1,
+}
+
+// parseCondition parses a whole condition, filter or key condition expression
+func parseCondition(expression string, placeholders *placeholders) (condition, error) {
+	p, err := newParser(expression, placeholders)
+	if err != nil {
+		return nil, err
+	}
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEnd {
+		return nil, p.errorf("unexpected token")
+	}
+	return c, nil
+}
+
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCondition{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andCondition{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (condition, error) {
+	if p.accept("NOT") {
+		c, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{condition: c}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (condition, error) {
+	if p.accept("(") {
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return c, p.expect(")")
+	}
+
+	t := p.peek()
+	if arguments, ok := conditionFunctions[strings.ToLower(t.text)]; ok && t.kind == tokenIdentifier && p.tokens[p.position+1].text == "(" {
+		p.position += 2
+		function := functionCondition{name: strings.ToLower(t.text)}
+		var err error
+		if function.path, err = p.parsePath(); err != nil {
+			return nil, err
+		}
+		for i := 0; i < arguments; i++ {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			argument, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			function.arguments = append(function.arguments, argument)
+		}
+		return function, p.expect(")")
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.accept("BETWEEN"):
+		lower, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		upper, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return betweenCondition{value: left, lower: lower, upper: upper}, nil
+	case p.accept("IN"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		in := inCondition{value: left}
+		for {
+			option, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			in.options = append(in.options, option)
+			if !p.accept(",") {
+				break
+			}
+		}
+		return in, p.expect(")")
+	}
+
+	comparator := p.next()
+	switch comparator.text {
+	case "=", "<>", "<", "<=", ">", ">=":
+	default:
+		p.position--
+		return nil, p.errorf("expected a comparator")
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return comparison{left: left, right: right, comparator: comparator.text}, nil
+}
+
+// updateAction is one action of an update expression's SET, REMOVE, ADD or DELETE clause
+type updateAction struct {
+	clause string
+	name   string
+	value  operand
+}
+
+// parseUpdate parses an update expression; only top-level attributes can be updated
+func parseUpdate(expression string, placeholders *placeholders) ([]updateAction, error) {
+	p, err := newParser(expression, placeholders)
+	if err != nil {
+		return nil, err
+	}
+	actions := []updateAction{}
+	seenClauses := map[string]bool{}
+	for p.peek().kind != tokenEnd {
+		clause := strings.ToUpper(p.next().text)
+		switch clause {
+		case "SET", "REMOVE", "ADD", "DELETE":
+		default:
+			p.position--
+			return nil, p.errorf("expected SET, REMOVE, ADD or DELETE")
+		}
+		if seenClauses[clause] {
+			return nil, p.errorf("the %s clause can only be used once", clause)
+		}
+		seenClauses[clause] = true
+
+		for {
+			target, err := p.parsePath()
+			if err != nil {
+				return nil, err
+			}
+			if len(target) != 1 {
+				return nil, fmt.Errorf("the fake only supports updating top-level attributes; path: %s", target)
+			}
+			action := updateAction{clause: clause, name: target[0].(string)}
+			switch clause {
+			case "SET":
+				if err := p.expect("="); err != nil {
+					return nil, err
+				}
+				if action.value, err = p.parseSetValue(); err != nil {
+					return nil, err
+				}
+			case "ADD", "DELETE":
+				if action.value, err = p.parseOperand(); err != nil {
+					return nil, err
+				}
+			}
+			actions = append(actions, action)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("Invalid UpdateExpression: the expression is empty")
+	}
+	return actions, nil
+}
+
+func (p *parser) parseSetValue() (operand, error) {
+	left, err := p.parseSetOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.accept("+"):
+		right, err := p.parseSetOperand()
+		return arithmeticOperand{left: left, right: right}, err
+	case p.accept("-"):
+		right, err := p.parseSetOperand()
+		return arithmeticOperand{left: left, right: right, subtract: true}, err
+	}
+	return left, nil
+}
+
+func (p *parser) parseSetOperand() (operand, error) {
+	t := p.peek()
+	if t.kind == tokenIdentifier && p.tokens[p.position+1].text == "(" {
+		switch strings.ToLower(t.text) {
+		case "if_not_exists":
+			p.position += 2
+			target, err := p.parsePath()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			fallback, err := p.parseSetOperand()
+			if err != nil {
+				return nil, err
+			}
+			return ifNotExistsOperand{path: target, fallback: fallback}, p.expect(")")
+		case "list_append":
+			p.position += 2
+			first, err := p.parseSetOperand()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			second, err := p.parseSetOperand()
+			if err != nil {
+				return nil, err
+			}
+			return listAppendOperand{first: first, second: second}, p.expect(")")
+		}
+	}
+	return p.parseOperand()
+}
@@ -0,0 +1,895 @@
+package ddbtest
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type keySchemaElement struct {
+	AttributeName string
+	KeyType       string
+}
+
+type attributeDefinition struct {
+	AttributeName string
+	AttributeType string
+}
+
+type projection struct {
+	ProjectionType string
+}
+
+type globalSecondaryIndex struct {
+	IndexName  string
+	KeySchema  []keySchemaElement
+	Projection projection
+}
+
+type tableRequest struct {
+	TableName                   string
+	KeySchema                   []keySchemaElement
+	AttributeDefinitions        []attributeDefinition
+	GlobalSecondaryIndexes      []globalSecondaryIndex
+	LocalSecondaryIndexes       []globalSecondaryIndex
+	GlobalSecondaryIndexUpdates []struct {
+		Create *globalSecondaryIndex
+		Delete *struct{ IndexName string }
+		Update *struct{ IndexName string }
+	}
+}
+
+type indexDescription struct {
+	IndexName   string
+	KeySchema   []keySchemaElement
+	Projection  projection
+	IndexStatus string
+	ItemCount   int
+}
+
+type tableDescription struct {
+	TableName              string
+	TableArn               string
+	TableStatus            string
+	KeySchema              []keySchemaElement
+	AttributeDefinitions   []attributeDefinition
+	GlobalSecondaryIndexes []indexDescription `json:",omitempty"`
+	ItemCount              int
+}
+
+func parseKeySchema(elements []keySchemaElement) (keySchema, error) {
+	schema := keySchema{}
+	for _, element := range elements {
+		switch {
+		case element.KeyType == "HASH" && schema.hashKey == "":
+			schema.hashKey = element.AttributeName
+		case element.KeyType == "RANGE" && schema.rangeKey == "":
+			schema.rangeKey = element.AttributeName
+		default:
+			return keySchema{}, fmt.Errorf("Invalid KeySchema: the key schema must have one HASH key and at most one RANGE key")
+		}
+	}
+	if schema.hashKey == "" {
+		return keySchema{}, fmt.Errorf("Invalid KeySchema: the key schema must have a HASH key")
+	}
+	return schema, nil
+}
+
+func describeKeySchema(schema keySchema) []keySchemaElement {
+	elements := []keySchemaElement{{AttributeName: schema.hashKey, KeyType: "HASH"}}
+	if schema.rangeKey != "" {
+		elements = append(elements, keySchemaElement{AttributeName: schema.rangeKey, KeyType: "RANGE"})
+	}
+	return elements
+}
+
+// addIndex validates the index against the attribute definitions and adds it to the table
+func (t *table) addIndex(definition globalSecondaryIndex) error {
+	if _, exists := t.index(definition.IndexName); exists {
+		return fmt.Errorf("Attempting to create an index which already exists: %s", definition.IndexName)
+	}
+	schema, err := parseKeySchema(definition.KeySchema)
+	if err != nil {
+		return err
+	}
+	for _, name := range schema.names() {
+		if _, ok := t.attributeTypes[name]; !ok {
+			return fmt.Errorf("Global Secondary Index key %s isn't defined in AttributeDefinitions", name)
+		}
+	}
+	t.indexes = append(t.indexes, index{name: definition.IndexName, keySchema: schema})
+	return nil
+}
+
+func (t *table) description() tableDescription {
+	description := tableDescription{
+		TableName:   t.name,
+		TableArn:    "arn:aws:dynamodb:us-east-1:000000000000:table/" + t.name,
+		TableStatus: "ACTIVE",
+		KeySchema:   describeKeySchema(t.keySchema),
+		ItemCount:   len(t.items),
+	}
+	names := make([]string, 0, len(t.attributeTypes))
+	for name := range t.attributeTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		description.AttributeDefinitions = append(description.AttributeDefinitions, attributeDefinition{AttributeName: name, AttributeType: t.attributeTypes[name]})
+	}
+	for _, index := range t.indexes {
+		itemCount := 0
+		for _, item := range t.items {
+			if len(keyAttributes(item, index.keySchema)) == len(index.names()) {
+				itemCount++
+			}
+		}
+		description.GlobalSecondaryIndexes = append(description.GlobalSecondaryIndexes, indexDescription{
+			IndexName:   index.name,
+			KeySchema:   describeKeySchema(index.keySchema),
+			Projection:  projection{ProjectionType: "ALL"},
+			IndexStatus: "ACTIVE",
+			ItemCount:   itemCount,
+		})
+	}
+	return description
+}
+
+// usedAttributes returns the names of every attribute used by the table's keys and indexes
+func (t *table) usedAttributes() map[string]bool {
+	used := map[string]bool{}
+	for _, name := range t.keySchema.names() {
+		used[name] = true
+	}
+	for _, index := range t.indexes {
+		for _, name := range index.names() {
+			used[name] = true
+		}
+	}
+	return used
+}
+
+func (s *Server) createTable(request tableRequest) (interface{}, *apiError) {
+	if _, exists := s.tables[request.TableName]; exists {
+		return nil, &apiError{code: "ResourceInUseException", message: "Table already exists: " + request.TableName}
+	}
+	if len(request.LocalSecondaryIndexes) > 0 {
+		return nil, validationError(fmt.Errorf("the fake doesn't support local secondary indexes"))
+	}
+	schema, err := parseKeySchema(request.KeySchema)
+	if err != nil {
+		return nil, validationError(err)
+	}
+
+	t := &table{
+		name:           request.TableName,
+		keySchema:      schema,
+		attributeTypes: map[string]string{},
+		items:          map[string]map[string]types.AttributeValue{},
+	}
+	for _, definition := range request.AttributeDefinitions {
+		switch definition.AttributeType {
+		case "S", "N", "B":
+			t.attributeTypes[definition.AttributeName] = definition.AttributeType
+		default:
+			return nil, validationError(fmt.Errorf("Invalid AttributeType %s for %s", definition.AttributeType, definition.AttributeName))
+		}
+	}
+	for _, name := range schema.names() {
+		if _, ok := t.attributeTypes[name]; !ok {
+			return nil, validationError(fmt.Errorf("Key %s isn't defined in AttributeDefinitions", name))
+		}
+	}
+	for _, definition := range request.GlobalSecondaryIndexes {
+		if err := t.addIndex(definition); err != nil {
+			return nil, validationError(err)
+		}
+	}
+	if used := t.usedAttributes(); len(used) != len(t.attributeTypes) {
+		return nil, validationError(fmt.Errorf("One or more parameter values were invalid: Some AttributeDefinitions are not used. AttributeDefinitions: %d, keys used: %d", len(t.attributeTypes), len(used)))
+	}
+
+	s.tables[t.name] = t
+	return map[string]interface{}{"TableDescription": t.description()}, nil
+}
+
+func (s *Server) describeTable(request tableRequest) (interface{}, *apiError) {
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return map[string]interface{}{"Table": t.description()}, nil
+}
+
+func (s *Server) updateTable(request tableRequest) (interface{}, *apiError) {
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	for _, definition := range request.AttributeDefinitions {
+		if existingType, ok := t.attributeTypes[definition.AttributeName]; ok && existingType != definition.AttributeType {
+			return nil, validationError(fmt.Errorf("Cannot change the type of the attribute %s", definition.AttributeName))
+		}
+		t.attributeTypes[definition.AttributeName] = definition.AttributeType
+	}
+	for _, update := range request.GlobalSecondaryIndexUpdates {
+		switch {
+		case update.Create != nil:
+			if err := t.addIndex(*update.Create); err != nil {
+				return nil, validationError(err)
+			}
+		case update.Delete != nil:
+			if _, ok := t.index(update.Delete.IndexName); !ok {
+				return nil, resourceNotFound("Requested resource not found: Index: " + update.Delete.IndexName + " not found")
+			}
+			indexes := []index{}
+			for _, index := range t.indexes {
+				if index.name != update.Delete.IndexName {
+					indexes = append(indexes, index)
+				}
+			}
+			t.indexes = indexes
+		}
+	}
+	// attributes are only defined while a key uses them
+	used := t.usedAttributes()
+	for name := range t.attributeTypes {
+		if !used[name] {
+			delete(t.attributeTypes, name)
+		}
+	}
+	return map[string]interface{}{"TableDescription": t.description()}, nil
+}
+
+func (s *Server) deleteTable(request tableRequest) (interface{}, *apiError) {
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	delete(s.tables, t.name)
+	description := t.description()
+	description.TableStatus = "DELETING"
+	return map[string]interface{}{"TableDescription": description}, nil
+}
+
+type timeToLiveSpecification struct {
+	AttributeName string `json:"AttributeName"`
+	Enabled       bool   `json:"Enabled"`
+}
+
+type timeToLiveRequest struct {
+	TableName               string                  `json:"TableName"`
+	TimeToLiveSpecification timeToLiveSpecification `json:"TimeToLiveSpecification"`
+}
+
+func (s *Server) describeTimeToLive(request timeToLiveRequest) (interface{}, *apiError) {
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	description := map[string]string{"TimeToLiveStatus": "DISABLED"}
+	if t.ttlAttribute != "" {
+		description = map[string]string{"TimeToLiveStatus": "ENABLED", "AttributeName": t.ttlAttribute}
+	}
+	return map[string]interface{}{"TimeToLiveDescription": description}, nil
+}
+
+// updateTimeToLive only records the table's ttl attribute; like dynamodb, which can take days to delete expired items,
+// the fake leaves them in place
+func (s *Server) updateTimeToLive(request timeToLiveRequest) (interface{}, *apiError) {
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	specification := request.TimeToLiveSpecification
+	switch {
+	case specification.AttributeName == "":
+		return nil, validationError(fmt.Errorf("TimeToLiveSpecification.AttributeName must be set"))
+	case specification.Enabled && t.ttlAttribute != "":
+		return nil, validationError(fmt.Errorf("TimeToLive is already enabled"))
+	case !specification.Enabled && t.ttlAttribute == "":
+		return nil, validationError(fmt.Errorf("TimeToLive is already disabled"))
+	case specification.Enabled:
+		t.ttlAttribute = specification.AttributeName
+	default:
+		t.ttlAttribute = ""
+	}
+	return map[string]interface{}{"TimeToLiveSpecification": specification}, nil
+}
+
+// itemRequest has the fields of GetItem, PutItem, DeleteItem, UpdateItem and the items of TransactWriteItems
+type itemRequest struct {
+	TableName                 string
+	Key                       wireItem
+	Item                      wireItem
+	UpdateExpression          string
+	ConditionExpression       string
+	ProjectionExpression      string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues wireItem
+	ReturnValues              string
+}
+
+// pendingWrite is a validated write that hasn't been applied yet
+type pendingWrite struct {
+	table     *table
+	key       string
+	keyItem   map[string]types.AttributeValue
+	condition condition
+	// item is the new item of a put
+	item map[string]types.AttributeValue
+	// actions are the actions of an update
+	actions []updateAction
+	delete  bool
+	// check is set for the ConditionCheck of a transaction, which doesn't write anything
+	check bool
+}
+
+// prepareWrite validates the request and parses its expressions without changing anything
+func (s *Server) prepareWrite(operation string, request itemRequest) (*pendingWrite, *apiError) {
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	values, err := request.ExpressionAttributeValues.item()
+	if err != nil {
+		return nil, validationError(err)
+	}
+	placeholders := newPlaceholders(request.ExpressionAttributeNames, values)
+
+	write := &pendingWrite{table: t}
+	if request.ConditionExpression != "" {
+		if write.condition, err = parseCondition(request.ConditionExpression, placeholders); err != nil {
+			return nil, validationError(err)
+		}
+	} else if operation == "ConditionCheck" {
+		return nil, validationError(fmt.Errorf("ConditionExpression is required for a ConditionCheck"))
+	}
+
+	switch operation {
+	case "PutItem", "Put":
+		if write.item, err = request.Item.item(); err != nil {
+			return nil, validationError(err)
+		}
+		if write.key, err = t.checkItem(write.item); err != nil {
+			return nil, validationError(err)
+		}
+		write.keyItem = keyAttributes(write.item, t.keySchema)
+	default:
+		if write.keyItem, err = request.Key.item(); err != nil {
+			return nil, validationError(err)
+		}
+		if write.key, err = t.primaryKey(write.keyItem, false); err != nil {
+			return nil, validationError(err)
+		}
+	}
+	switch operation {
+	case "DeleteItem", "Delete":
+		write.delete = true
+	case "ConditionCheck":
+		write.check = true
+	case "UpdateItem", "Update":
+		if request.UpdateExpression == "" {
+			return nil, validationError(fmt.Errorf("the fake only supports updates with an UpdateExpression"))
+		}
+		if write.actions, err = parseUpdate(request.UpdateExpression, placeholders); err != nil {
+			return nil, validationError(err)
+		}
+		if err := checkUpdateActions(write.actions, t.keySchema); err != nil {
+			return nil, validationError(err)
+		}
+	}
+
+	if err := placeholders.checkUnused(); err != nil {
+		return nil, validationError(err)
+	}
+	return write, nil
+}
+
+func checkUpdateActions(actions []updateAction, schema keySchema) error {
+	seen := map[string]bool{}
+	for _, action := range actions {
+		for _, name := range schema.names() {
+			if action.name == name {
+				return fmt.Errorf("One or more parameter values were invalid: Cannot update attribute %s. This attribute is part of the key", name)
+			}
+		}
+		if seen[action.name] {
+			return fmt.Errorf("Invalid UpdateExpression: Two document paths overlap with each other; path one: [%s], path two: [%s]", action.name, action.name)
+		}
+		seen[action.name] = true
+	}
+	return nil
+}
+
+// conditionHolds evaluates the write's condition against the stored item, or an empty item if there's none
+func (w *pendingWrite) conditionHolds() (bool, *apiError) {
+	if w.condition == nil {
+		return true, nil
+	}
+	existing := w.table.items[w.key]
+	if existing == nil {
+		existing = map[string]types.AttributeValue{}
+	}
+	holds, err := w.condition.matches(existing)
+	if err != nil {
+		return false, validationError(err)
+	}
+	return holds, nil
+}
+
+// newItem computes the item that the write leaves behind, or nil if it deletes the item
+func (w *pendingWrite) newItem() (map[string]types.AttributeValue, *apiError) {
+	existing := w.table.items[w.key]
+	switch {
+	case w.check:
+		return existing, nil
+	case w.delete:
+		return nil, nil
+	case w.item != nil:
+		return copyItem(w.item), nil
+	}
+
+	original := existing
+	if original == nil {
+		original = w.keyItem
+	}
+	updated := copyItem(original)
+	for _, action := range w.actions {
+		if err := applyAction(updated, original, action); err != nil {
+			return nil, validationError(err)
+		}
+	}
+	if _, err := w.table.checkItem(updated); err != nil {
+		return nil, validationError(err)
+	}
+	return updated, nil
+}
+
+// applyAction applies an update action to the item; operands are evaluated against the original item, like dynamodb does
+func applyAction(item, original map[string]types.AttributeValue, action updateAction) error {
+	if action.clause == "REMOVE" {
+		delete(item, action.name)
+		return nil
+	}
+	value, exists, err := action.value.evaluate(original)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("The provided expression refers to an attribute that does not exist in the item")
+	}
+	if action.clause == "SET" {
+		item[action.name] = value
+		return nil
+	}
+
+	current, hasCurrent := original[action.name]
+	switch value := value.(type) {
+	case *types.AttributeValueMemberN:
+		if action.clause == "DELETE" {
+			return fmt.Errorf("Invalid UpdateExpression: Incorrect operand type for operator or function; operator: DELETE, operand type: NUMBER")
+		}
+		if !hasCurrent {
+			item[action.name] = value
+			return nil
+		}
+		currentNumber, ok := current.(*types.AttributeValueMemberN)
+		if !ok {
+			return fmt.Errorf("An operand in the update expression has an incorrect data type")
+		}
+		a, _ := parseNumber(currentNumber.Value)
+		b, _ := parseNumber(value.Value)
+		item[action.name] = &types.AttributeValueMemberN{Value: new(big.Float).SetPrec(128).Add(a, b).Text('g', -1)}
+		return nil
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		if hasCurrent && typeName(current) != typeName(value) {
+			return fmt.Errorf("An operand in the update expression has an incorrect data type")
+		}
+		result := combineSets(current, value, action.clause == "ADD")
+		if result == nil {
+			delete(item, action.name)
+		} else {
+			item[action.name] = result
+		}
+		return nil
+	}
+	return fmt.Errorf("Invalid UpdateExpression: Incorrect operand type for operator or function; operator: %s, operand type: %s", action.clause, typeName(value))
+}
+
+// combineSets returns the union (add) or difference of two sets of the same type, or nil if the result is empty
+func combineSets(current, value types.AttributeValue, add bool) types.AttributeValue {
+	members := func(set types.AttributeValue) []string {
+		switch set := set.(type) {
+		case *types.AttributeValueMemberSS:
+			return set.Value
+		case *types.AttributeValueMemberNS:
+			return normalizeNumbers(set.Value)
+		case *types.AttributeValueMemberBS:
+			strings := make([]string, len(set.Value))
+			for i, member := range set.Value {
+				strings[i] = string(member)
+			}
+			return strings
+		}
+		return nil
+	}
+
+	removed := map[string]bool{}
+	result := []string{}
+	seen := map[string]bool{}
+	if !add {
+		for _, member := range members(value) {
+			removed[member] = true
+		}
+	}
+	candidates := members(current)
+	if add {
+		candidates = append(candidates, members(value)...)
+	}
+	for _, member := range candidates {
+		if !removed[member] && !seen[member] {
+			seen[member] = true
+			result = append(result, member)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+
+	switch value.(type) {
+	case *types.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: result}
+	case *types.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: result}
+	}
+	binary := make([][]byte, len(result))
+	for i, member := range result {
+		binary[i] = []byte(member)
+	}
+	return &types.AttributeValueMemberBS{Value: binary}
+}
+
+func (w *pendingWrite) apply(item map[string]types.AttributeValue) {
+	if w.check {
+		return
+	}
+	if item == nil {
+		delete(w.table.items, w.key)
+		return
+	}
+	w.table.items[w.key] = item
+}
+
+// returnValues builds the Attributes of a write's response
+func returnValues(returnValues string, old, new map[string]types.AttributeValue, actions []updateAction) (map[string]interface{}, *apiError) {
+	response := map[string]interface{}{}
+	var attributes map[string]types.AttributeValue
+	switch returnValues {
+	case "", "NONE":
+		return response, nil
+	case "ALL_OLD":
+		attributes = old
+	case "ALL_NEW":
+		attributes = new
+	case "UPDATED_OLD", "UPDATED_NEW":
+		source := old
+		if returnValues == "UPDATED_NEW" {
+			source = new
+		}
+		attributes = map[string]types.AttributeValue{}
+		for _, action := range actions {
+			if value, ok := source[action.name]; ok {
+				attributes[action.name] = value
+			}
+		}
+	default:
+		return nil, validationError(fmt.Errorf("the fake doesn't support ReturnValues %s", returnValues))
+	}
+	if len(attributes) > 0 {
+		response["Attributes"] = toWireItem(attributes)
+	}
+	return response, nil
+}
+
+func (s *Server) write(operation string, request itemRequest) (interface{}, *apiError) {
+	if operation != "UpdateItem" && request.ReturnValues != "" && request.ReturnValues != "NONE" && request.ReturnValues != "ALL_OLD" {
+		return nil, validationError(fmt.Errorf("ReturnValues can only be ALL_OLD or NONE"))
+	}
+	write, apiErr := s.prepareWrite(operation, request)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	holds, apiErr := write.conditionHolds()
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if !holds {
+		return nil, conditionalCheckFailed()
+	}
+	old := write.table.items[write.key]
+	item, apiErr := write.newItem()
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	write.apply(item)
+	return returnValues(request.ReturnValues, old, item, write.actions)
+}
+
+func (s *Server) putItem(request itemRequest) (interface{}, *apiError) {
+	return s.write("PutItem", request)
+}
+
+func (s *Server) deleteItem(request itemRequest) (interface{}, *apiError) {
+	return s.write("DeleteItem", request)
+}
+
+func (s *Server) updateItem(request itemRequest) (interface{}, *apiError) {
+	return s.write("UpdateItem", request)
+}
+
+func (s *Server) getItem(request itemRequest) (interface{}, *apiError) {
+	if request.ProjectionExpression != "" {
+		return nil, validationError(fmt.Errorf("the fake doesn't support ProjectionExpression"))
+	}
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	keyItem, err := request.Key.item()
+	if err != nil {
+		return nil, validationError(err)
+	}
+	key, err := t.primaryKey(keyItem, false)
+	if err != nil {
+		return nil, validationError(err)
+	}
+	response := map[string]interface{}{}
+	if item, ok := t.items[key]; ok {
+		response["Item"] = toWireItem(item)
+	}
+	return response, nil
+}
+
+type batchGetRequest struct {
+	RequestItems map[string]struct {
+		Keys                 []wireItem
+		ProjectionExpression string
+		ConsistentRead       bool
+	}
+}
+
+// batchGetItem reads every key in one go, so UnprocessedKeys is always empty; like DynamoDB,
+// it rejects more than 100 keys and keys that are requested twice
+func (s *Server) batchGetItem(request batchGetRequest) (interface{}, *apiError) {
+	keyCount := 0
+	for _, tableRequest := range request.RequestItems {
+		keyCount += len(tableRequest.Keys)
+	}
+	if keyCount == 0 {
+		return nil, validationError(fmt.Errorf("1 validation error detected: Value at 'requestItems' failed to satisfy constraint: Member must have at least 1 key"))
+	}
+	if keyCount > 100 {
+		return nil, validationError(fmt.Errorf("Too many items requested for the BatchGetItem call"))
+	}
+
+	responses := map[string][]wireItem{}
+	for tableName, tableRequest := range request.RequestItems {
+		if tableRequest.ProjectionExpression != "" {
+			return nil, validationError(fmt.Errorf("the fake doesn't support ProjectionExpression"))
+		}
+		t, apiErr := s.table(tableName)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		items := []wireItem{}
+		requested := map[string]bool{}
+		for _, wireKey := range tableRequest.Keys {
+			keyItem, err := wireKey.item()
+			if err != nil {
+				return nil, validationError(err)
+			}
+			key, err := t.primaryKey(keyItem, false)
+			if err != nil {
+				return nil, validationError(err)
+			}
+			if requested[key] {
+				return nil, validationError(fmt.Errorf("Provided list of item keys contains duplicates"))
+			}
+			requested[key] = true
+			if item, ok := t.items[key]; ok {
+				items = append(items, toWireItem(item))
+			}
+		}
+		responses[tableName] = items
+	}
+	return map[string]interface{}{"Responses": responses, "UnprocessedKeys": map[string]interface{}{}}, nil
+}
+
+type transactRequest struct {
+	TransactItems []struct {
+		ConditionCheck *itemRequest
+		Put            *itemRequest
+		Delete         *itemRequest
+		Update         *itemRequest
+	}
+}
+
+// transactWriteItems applies every write or none of them; if any condition fails,
+// the TransactionCanceledException lists which item failed in the order of the items
+func (s *Server) transactWriteItems(request transactRequest) (interface{}, *apiError) {
+	if len(request.TransactItems) == 0 || len(request.TransactItems) > 100 {
+		return nil, validationError(fmt.Errorf("1 validation error detected: Value at 'transactItems' failed to satisfy constraint: Member must have length between 1 and 100"))
+	}
+
+	writes := make([]*pendingWrite, len(request.TransactItems))
+	seen := map[string]bool{}
+	for i, transactItem := range request.TransactItems {
+		var write *pendingWrite
+		var apiErr *apiError
+		switch {
+		case transactItem.ConditionCheck != nil:
+			write, apiErr = s.prepareWrite("ConditionCheck", *transactItem.ConditionCheck)
+		case transactItem.Put != nil:
+			write, apiErr = s.prepareWrite("Put", *transactItem.Put)
+		case transactItem.Delete != nil:
+			write, apiErr = s.prepareWrite("Delete", *transactItem.Delete)
+		case transactItem.Update != nil:
+			write, apiErr = s.prepareWrite("Update", *transactItem.Update)
+		default:
+			return nil, validationError(fmt.Errorf("each TransactItem must have exactly one operation"))
+		}
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		itemId := write.table.name + "\x00" + write.key
+		if seen[itemId] {
+			return nil, validationError(fmt.Errorf("Transaction request cannot include multiple operations on one item"))
+		}
+		seen[itemId] = true
+		writes[i] = write
+	}
+
+	reasons := make([]cancellationReason, len(writes))
+	canceled := false
+	for i, write := range writes {
+		reasons[i] = cancellationReason{Code: "None"}
+		holds, apiErr := write.conditionHolds()
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if !holds {
+			reasons[i] = cancellationReason{Code: "ConditionalCheckFailed", Message: "The conditional request failed"}
+			canceled = true
+		}
+	}
+	if canceled {
+		codes := make([]string, len(reasons))
+		for i, reason := range reasons {
+			codes[i] = reason.Code
+		}
+		return nil, &apiError{
+			code:                "TransactionCanceledException",
+			message:             fmt.Sprintf("Transaction cancelled, please refer cancellation reasons for specific reasons [%s]", strings.Join(codes, ", ")),
+			cancellationReasons: reasons,
+		}
+	}
+
+	items := make([]map[string]types.AttributeValue, len(writes))
+	for i, write := range writes {
+		item, apiErr := write.newItem()
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		items[i] = item
+	}
+	for i, write := range writes {
+		write.apply(items[i])
+	}
+	return map[string]interface{}{}, nil
+}
+
+type readRequestWire struct {
+	TableName                 string
+	IndexName                 string
+	KeyConditionExpression    string
+	FilterExpression          string
+	ProjectionExpression      string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues wireItem
+	ExclusiveStartKey         wireItem
+	Limit                     int
+	ScanIndexForward          *bool
+	ConsistentRead            bool
+	Select                    string
+	TotalSegments             int
+}
+
+func (s *Server) read(operation string, request readRequestWire) (interface{}, *apiError) {
+	t, apiErr := s.table(request.TableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	switch {
+	case request.ProjectionExpression != "":
+		return nil, validationError(fmt.Errorf("the fake doesn't support ProjectionExpression"))
+	case request.TotalSegments > 0:
+		return nil, validationError(fmt.Errorf("the fake doesn't support parallel scans"))
+	case request.Select != "" && request.Select != "ALL_ATTRIBUTES" && request.Select != "COUNT":
+		return nil, validationError(fmt.Errorf("the fake doesn't support Select %s", request.Select))
+	case request.Limit < 0:
+		return nil, validationError(fmt.Errorf("Limit must be greater than or equal to 1"))
+	}
+
+	schema := t.keySchema
+	if request.IndexName != "" {
+		index, ok := t.index(request.IndexName)
+		if !ok {
+			return nil, validationError(fmt.Errorf("The table does not have the specified index: %s", request.IndexName))
+		}
+		if request.ConsistentRead {
+			return nil, validationError(fmt.Errorf("Consistent reads are not supported on global secondary indexes"))
+		}
+		schema = index.keySchema
+	}
+
+	values, err := request.ExpressionAttributeValues.item()
+	if err != nil {
+		return nil, validationError(err)
+	}
+	placeholders := newPlaceholders(request.ExpressionAttributeNames, values)
+	readRequest := readRequest{
+		indexName: request.IndexName,
+		limit:     request.Limit,
+		forward:   request.ScanIndexForward == nil || *request.ScanIndexForward,
+	}
+	if operation == "Query" {
+		if request.KeyConditionExpression == "" {
+			return nil, validationError(fmt.Errorf("Either the KeyConditions or KeyConditionExpression parameter must be specified in the request"))
+		}
+		if readRequest.condition, err = parseCondition(request.KeyConditionExpression, placeholders); err != nil {
+			return nil, validationError(err)
+		}
+		if err := checkKeyCondition(readRequest.condition, schema); err != nil {
+			return nil, validationError(err)
+		}
+	}
+	if request.FilterExpression != "" {
+		if readRequest.filter, err = parseCondition(request.FilterExpression, placeholders); err != nil {
+			return nil, validationError(err)
+		}
+	}
+	if err := placeholders.checkUnused(); err != nil {
+		return nil, validationError(err)
+	}
+	if readRequest.exclusiveStartKey, err = request.ExclusiveStartKey.item(); err != nil {
+		return nil, validationError(err)
+	}
+
+	result, err := t.read(readRequest, s.PageSize)
+	if err != nil {
+		return nil, validationError(err)
+	}
+	response := map[string]interface{}{
+		"Count":        len(result.items),
+		"ScannedCount": result.scannedCount,
+	}
+	if request.Select != "COUNT" {
+		response["Items"] = toWireItems(result.items)
+	}
+	if result.lastEvaluatedKey != nil {
+		response["LastEvaluatedKey"] = toWireItem(result.lastEvaluatedKey)
+	}
+	return response, nil
+}
+
+func (s *Server) query(request readRequestWire) (interface{}, *apiError) {
+	return s.read("Query", request)
+}
+
+func (s *Server) scan(request readRequestWire) (interface{}, *apiError) {
+	return s.read("Scan", request)
+}
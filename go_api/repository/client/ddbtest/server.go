@@ -0,0 +1,191 @@
+// Package ddbtest provides an in-process fake of dynamodb that speaks its http protocol,
+// so the real sdk client (and everything built on it) can be tested without localstack
+//
+// The fake supports the operations the api uses: CreateTable, DescribeTable, UpdateTable (adding indexes), DeleteTable,
+// DescribeTimeToLive, UpdateTimeToLive, GetItem, PutItem, DeleteItem, UpdateItem, Query, Scan and TransactWriteItems.
+// Condition, filter, key condition and update expressions are evaluated like dynamodb does,
+// including rejecting unused placeholders. Indexes always project every attribute, reads are always consistent,
+// tables and indexes are active as soon as they're created, and expired items aren't deleted.
+package ddbtest
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const targetPrefix = "DynamoDB_20120810."
+
+// Server is a fake dynamodb server backed by memory
+type Server struct {
+	// PageSize is the maximum number of items a Query or Scan evaluates before returning a page,
+	// standing in for dynamodb's 1MB limit so that pagination can be tested with a few items; 0 means unlimited
+	PageSize int
+
+	httpServer *httptest.Server
+	mu         sync.Mutex
+	tables     map[string]*table
+}
+
+// NewServer starts a fake dynamodb server with no tables; it's closed when the test finishes
+func NewServer(t interface{ Cleanup(func()) }) *Server {
+	s := &Server{tables: map[string]*table{}}
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.httpServer.Close)
+	return s
+}
+
+// URL is the server's endpoint, e.g. for the AWS_ENDPOINT environment variable
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Client creates a dynamodb client that sends its requests to the server, without retries
+func (s *Server) Client() *dynamodb.Client {
+	return dynamodb.New(dynamodb.Options{
+		Region:           "us-east-1",
+		Credentials:      aws.AnonymousCredentials{},
+		EndpointResolver: dynamodb.EndpointResolverFromURL(s.URL()),
+		HTTPClient:       s.httpServer.Client(),
+		Retryer:          aws.NopRetryer{},
+	})
+}
+
+// apiError is an error response in the dynamodb protocol
+type apiError struct {
+	code    string
+	message string
+	// cancellationReasons is only set for the TransactionCanceledException
+	cancellationReasons []cancellationReason
+}
+
+type cancellationReason struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message,omitempty"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s: %s", e.code, e.message)
+}
+
+func validationError(err error) *apiError {
+	return &apiError{code: "ValidationException", message: err.Error()}
+}
+
+func resourceNotFound(message string) *apiError {
+	return &apiError{code: "ResourceNotFoundException", message: message}
+}
+
+func conditionalCheckFailed() *apiError {
+	return &apiError{code: "ConditionalCheckFailedException", message: "The conditional request failed"}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	target := r.Header.Get("X-Amz-Target")
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, &apiError{code: "SerializationException", message: err.Error()})
+		return
+	}
+
+	operation, ok := s.operations()[strings.TrimPrefix(target, targetPrefix)]
+	if !ok || !strings.HasPrefix(target, targetPrefix) {
+		s.writeError(w, &apiError{code: "UnknownOperationException", message: fmt.Sprintf("the fake doesn't support the operation '%s'", target)})
+		return
+	}
+
+	s.mu.Lock()
+	response, apiErr := operation(body)
+	s.mu.Unlock()
+	if apiErr != nil {
+		s.writeError(w, apiErr)
+		return
+	}
+	responseBody, err := jsoniter.Marshal(response)
+	if err != nil {
+		s.writeError(w, &apiError{code: "InternalServerError", message: err.Error()})
+		return
+	}
+	s.writeResponse(w, http.StatusOK, responseBody)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, apiErr *apiError) {
+	response := map[string]interface{}{
+		"__type":  "com.amazonaws.dynamodb.v20120810#" + apiErr.code,
+		"message": apiErr.message,
+	}
+	if apiErr.cancellationReasons != nil {
+		response["CancellationReasons"] = apiErr.cancellationReasons
+	}
+	body, _ := jsoniter.Marshal(response)
+	s.writeResponse(w, http.StatusBadRequest, body)
+}
+
+func (s *Server) writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.Header().Set("X-Amz-Crc32", strconv.FormatUint(uint64(crc32.ChecksumIEEE(body)), 10))
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// operation handles the json body of a request while the server's lock is held
+type operation func(body []byte) (interface{}, *apiError)
+
+func (s *Server) operations() map[string]operation {
+	return map[string]operation{
+		"CreateTable":        decode(s.createTable),
+		"DescribeTable":      decode(s.describeTable),
+		"UpdateTable":        decode(s.updateTable),
+		"DeleteTable":        decode(s.deleteTable),
+		"DescribeTimeToLive": decode(s.describeTimeToLive),
+		"UpdateTimeToLive":   decode(s.updateTimeToLive),
+		"GetItem":            decode(s.getItem),
+		"BatchGetItem":       decode(s.batchGetItem),
+		"PutItem":            decode(s.putItem),
+		"DeleteItem":         decode(s.deleteItem),
+		"UpdateItem":         decode(s.updateItem),
+		"Query":              decode(s.query),
+		"Scan":               decode(s.scan),
+		"TransactWriteItems": decode(s.transactWriteItems),
+	}
+}
+
+func decode[Request any](handle func(request Request) (interface{}, *apiError)) operation {
+	return func(body []byte) (interface{}, *apiError) {
+		var request Request
+		if err := jsoniter.Unmarshal(body, &request); err != nil {
+			return nil, &apiError{code: "SerializationException", message: err.Error()}
+		}
+		return handle(request)
+	}
+}
+
+func (s *Server) table(name string) (*table, *apiError) {
+	t, ok := s.tables[name]
+	if !ok {
+		return nil, resourceNotFound("Requested resource not found: Table: " + name + " not found")
+	}
+	return t, nil
+}
+
+// Items returns every item in the table in key order, e.g. to assert on what a test wrote
+func (s *Server) Items(tableName string) ([]map[string]types.AttributeValue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, apiErr := s.table(tableName)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	result, err := t.read(readRequest{forward: true}, 0)
+	return result.items, err
+}
@@ -0,0 +1,440 @@
+package ddbtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTable starts a server with a table keyed on pk and sk, with an index keyed on group and sk
+func newTestTable(t *testing.T) (*Server, *dynamodb.Client) {
+	server := NewServer(t)
+	db := server.Client()
+	_, err := db.CreateTable(context.TODO(), &dynamodb.CreateTableInput{
+		TableName: aws.String("test"),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sk"), AttributeType: types.ScalarAttributeTypeN},
+			{AttributeName: aws.String("group"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName: aws.String("group-index"),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("group"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	assert.Nil(t, err, "No error should have been returned from CreateTable")
+	return server, db
+}
+
+func testItem(pk string, sk int, attributes ...string) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"pk": &types.AttributeValueMemberS{Value: pk},
+		"sk": &types.AttributeValueMemberN{Value: fmt.Sprint(sk)},
+	}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		item[attributes[i]] = &types.AttributeValueMemberS{Value: attributes[i+1]}
+	}
+	return item
+}
+
+func TestServer_DescribeTable(t *testing.T) {
+	_, db := newTestTable(t)
+	output, err := db.DescribeTable(context.TODO(), &dynamodb.DescribeTableInput{TableName: aws.String("test")})
+	assert.Nil(t, err)
+	assert.Equal(t, types.TableStatusActive, output.Table.TableStatus)
+	assert.Len(t, output.Table.GlobalSecondaryIndexes, 1)
+	assert.Equal(t, types.IndexStatusActive, output.Table.GlobalSecondaryIndexes[0].IndexStatus)
+
+	_, err = db.DescribeTable(context.TODO(), &dynamodb.DescribeTableInput{TableName: aws.String("missing")})
+	var notFound *types.ResourceNotFoundException
+	assert.True(t, errors.As(err, &notFound), "A missing table should return the ResourceNotFoundException")
+}
+
+func TestServer_UpdateTimeToLive(t *testing.T) {
+	_, db := newTestTable(t)
+	describe := func() *types.TimeToLiveDescription {
+		output, err := db.DescribeTimeToLive(context.TODO(), &dynamodb.DescribeTimeToLiveInput{TableName: aws.String("test")})
+		assert.Nil(t, err, "No error should have been returned from DescribeTimeToLive")
+		return output.TimeToLiveDescription
+	}
+	update := func(enabled bool) error {
+		_, err := db.UpdateTimeToLive(context.TODO(), &dynamodb.UpdateTimeToLiveInput{
+			TableName: aws.String("test"),
+			TimeToLiveSpecification: &types.TimeToLiveSpecification{
+				AttributeName: aws.String("expires_at"),
+				Enabled:       aws.Bool(enabled),
+			},
+		})
+		return err
+	}
+
+	assert.Equal(t, types.TimeToLiveStatusDisabled, describe().TimeToLiveStatus)
+	assert.Nil(t, update(true), "No error should have been returned from UpdateTimeToLive")
+	assert.Equal(t, types.TimeToLiveStatusEnabled, describe().TimeToLiveStatus)
+	assert.Equal(t, "expires_at", aws.ToString(describe().AttributeName))
+	assert.Error(t, update(true), "TimeToLive can't be enabled twice")
+	assert.Nil(t, update(false), "No error should have been returned from UpdateTimeToLive")
+	assert.Equal(t, types.TimeToLiveStatusDisabled, describe().TimeToLiveStatus)
+}
+
+func TestServer_PutItem(t *testing.T) {
+	tests := []struct {
+		name                 string
+		item                 map[string]types.AttributeValue
+		conditionExpression  *string
+		expectConditionError bool
+		expectValidation     bool
+	}{
+		{
+			name: "Replaces the item without a condition",
+			item: testItem("a", 1, "value", "new"),
+		},
+		{
+			name:                 "Fails the condition if the item exists",
+			item:                 testItem("a", 1, "value", "new"),
+			conditionExpression:  aws.String("attribute_not_exists(pk)"),
+			expectConditionError: true,
+		},
+		{
+			name:                "Passes the condition if the item doesn't exist",
+			item:                testItem("b", 1, "value", "new"),
+			conditionExpression: aws.String("attribute_not_exists(pk)"),
+		},
+		{
+			name:             "Rejects an item without its range key",
+			item:             map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "a"}},
+			expectValidation: true,
+		},
+		{
+			name:             "Rejects an empty index key",
+			item:             testItem("a", 2, "group", ""),
+			expectValidation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, db := newTestTable(t)
+			_, err := db.PutItem(context.TODO(), &dynamodb.PutItemInput{TableName: aws.String("test"), Item: testItem("a", 1, "value", "old")})
+			assert.Nil(t, err)
+
+			_, err = db.PutItem(context.TODO(), &dynamodb.PutItemInput{
+				TableName:           aws.String("test"),
+				Item:                tt.item,
+				ConditionExpression: tt.conditionExpression,
+			})
+			var conditionFailed *types.ConditionalCheckFailedException
+			assert.Equal(t, tt.expectConditionError, errors.As(err, &conditionFailed))
+			if tt.expectValidation {
+				assert.ErrorContains(t, err, "ValidationException")
+			}
+			if err == nil {
+				output, err := db.GetItem(context.TODO(), &dynamodb.GetItemInput{
+					TableName: aws.String("test"),
+					Key:       map[string]types.AttributeValue{"pk": tt.item["pk"], "sk": tt.item["sk"]},
+				})
+				assert.Nil(t, err)
+				assert.Equal(t, tt.item, output.Item)
+			}
+		})
+	}
+}
+
+func TestServer_BatchGetItem(t *testing.T) {
+	_, db := newTestTable(t)
+	for _, item := range []map[string]types.AttributeValue{testItem("a", 1), testItem("b", 1)} {
+		_, err := db.PutItem(context.TODO(), &dynamodb.PutItemInput{TableName: aws.String("test"), Item: item})
+		assert.Nil(t, err)
+	}
+	key := func(pk string) map[string]types.AttributeValue {
+		return testItem(pk, 1)
+	}
+
+	output, err := db.BatchGetItem(context.TODO(), &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{"test": {Keys: []map[string]types.AttributeValue{key("a"), key("b"), key("c")}}},
+	})
+	assert.Nil(t, err, "No error should have been returned from BatchGetItem")
+	assert.ElementsMatch(t, []map[string]types.AttributeValue{testItem("a", 1), testItem("b", 1)}, output.Responses["test"], "Only the existing items should have been returned")
+	assert.Empty(t, output.UnprocessedKeys, "Every key should have been processed")
+
+	_, err = db.BatchGetItem(context.TODO(), &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{"test": {Keys: []map[string]types.AttributeValue{key("a"), key("a")}}},
+	})
+	assert.ErrorContains(t, err, "duplicates", "Duplicate keys should have been rejected")
+
+	keys := make([]map[string]types.AttributeValue, 101)
+	for i := range keys {
+		keys[i] = key(fmt.Sprint(i))
+	}
+	_, err = db.BatchGetItem(context.TODO(), &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{"test": {Keys: keys}},
+	})
+	assert.ErrorContains(t, err, "Too many items", "More than 100 keys should have been rejected")
+}
+
+func TestServer_UpdateItem(t *testing.T) {
+	_, db := newTestTable(t)
+	update, err := expression.NewBuilder().WithUpdate(
+		expression.Set(expression.Name("count"), expression.Name("count").Plus(expression.Value(2))).
+			Set(expression.Name("created"), expression.IfNotExists(expression.Name("created"), expression.Value("now"))),
+	).Build()
+	assert.Nil(t, err)
+	_, err = db.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName: aws.String("test"),
+		Item:      map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "a"}, "sk": &types.AttributeValueMemberN{Value: "1"}, "count": &types.AttributeValueMemberN{Value: "1"}},
+	})
+	assert.Nil(t, err)
+
+	output, err := db.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String("test"),
+		Key:                       testItem("a", 1),
+		UpdateExpression:          update.Update(),
+		ExpressionAttributeNames:  update.Names(),
+		ExpressionAttributeValues: update.Values(),
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "3"}, output.Attributes["count"])
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "now"}, output.Attributes["created"])
+
+	_, err = db.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String("test"),
+		Key:                       testItem("a", 1),
+		UpdateExpression:          aws.String("SET pk = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":pk": &types.AttributeValueMemberS{Value: "b"}},
+	})
+	assert.ErrorContains(t, err, "part of the key", "Key attributes can't be updated")
+}
+
+func TestServer_Query(t *testing.T) {
+	server, db := newTestTable(t)
+	server.PageSize = 2
+	for i := 0; i < 5; i++ {
+		_, err := db.PutItem(context.TODO(), &dynamodb.PutItemInput{TableName: aws.String("test"), Item: testItem("a", i, "group", "g")})
+		assert.Nil(t, err)
+	}
+	_, err := db.PutItem(context.TODO(), &dynamodb.PutItemInput{TableName: aws.String("test"), Item: testItem("b", 0)})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name          string
+		indexName     *string
+		keyCondition  expression.KeyConditionBuilder
+		forward       bool
+		expectedOrder []string
+	}{
+		{
+			name:          "Reads the partition in order across pages",
+			keyCondition:  expression.Key("pk").Equal(expression.Value("a")),
+			forward:       true,
+			expectedOrder: []string{"0", "1", "2", "3", "4"},
+		},
+		{
+			name:          "Reads the partition backwards",
+			keyCondition:  expression.Key("pk").Equal(expression.Value("a")),
+			expectedOrder: []string{"4", "3", "2", "1", "0"},
+		},
+		{
+			name:          "Applies the range key condition",
+			keyCondition:  expression.Key("pk").Equal(expression.Value("a")).And(expression.Key("sk").Between(expression.Value(1), expression.Value(3))),
+			forward:       true,
+			expectedOrder: []string{"1", "2", "3"},
+		},
+		{
+			name:          "Reads the index, which skips the items without its keys",
+			indexName:     aws.String("group-index"),
+			keyCondition:  expression.Key("group").Equal(expression.Value("g")).And(expression.Key("sk").GreaterThanEqual(expression.Value(3))),
+			forward:       true,
+			expectedOrder: []string{"3", "4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyExpression, err := expression.NewBuilder().WithKeyCondition(tt.keyCondition).Build()
+			assert.Nil(t, err)
+
+			order := []string{}
+			pages := 0
+			var startKey map[string]types.AttributeValue
+			for {
+				output, err := db.Query(context.TODO(), &dynamodb.QueryInput{
+					TableName:                 aws.String("test"),
+					IndexName:                 tt.indexName,
+					KeyConditionExpression:    keyExpression.KeyCondition(),
+					ExpressionAttributeNames:  keyExpression.Names(),
+					ExpressionAttributeValues: keyExpression.Values(),
+					ScanIndexForward:          aws.Bool(tt.forward),
+					ExclusiveStartKey:         startKey,
+				})
+				assert.Nil(t, err)
+				pages++
+				for _, item := range output.Items {
+					order = append(order, item["sk"].(*types.AttributeValueMemberN).Value)
+				}
+				if len(output.LastEvaluatedKey) == 0 {
+					break
+				}
+				startKey = output.LastEvaluatedKey
+			}
+			assert.Equal(t, tt.expectedOrder, order)
+			assert.Greater(t, pages, 1, "The results should have been split into pages")
+		})
+	}
+}
+
+func TestServer_Query_Validation(t *testing.T) {
+	_, db := newTestTable(t)
+	tests := []struct {
+		name   string
+		input  *dynamodb.QueryInput
+		reason string
+	}{
+		{
+			name: "Rejects a condition on a non-key attribute",
+			input: &dynamodb.QueryInput{
+				KeyConditionExpression:    aws.String("pk = :pk AND other = :other"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{":pk": &types.AttributeValueMemberS{Value: "a"}, ":other": &types.AttributeValueMemberS{Value: "b"}},
+			},
+			reason: "Query key condition not supported",
+		},
+		{
+			name: "Rejects unused placeholders",
+			input: &dynamodb.QueryInput{
+				KeyConditionExpression:    aws.String("pk = :pk"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{":pk": &types.AttributeValueMemberS{Value: "a"}, ":unused": &types.AttributeValueMemberS{Value: "b"}},
+			},
+			reason: "unused in expressions",
+		},
+		{
+			name: "Rejects consistent reads on an index",
+			input: &dynamodb.QueryInput{
+				IndexName:                 aws.String("group-index"),
+				KeyConditionExpression:    aws.String("#group = :group"),
+				ExpressionAttributeNames:  map[string]string{"#group": "group"},
+				ExpressionAttributeValues: map[string]types.AttributeValue{":group": &types.AttributeValueMemberS{Value: "g"}},
+				ConsistentRead:            aws.Bool(true),
+			},
+			reason: "Consistent reads are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.TableName = aws.String("test")
+			_, err := db.Query(context.TODO(), tt.input)
+			assert.ErrorContains(t, err, tt.reason)
+		})
+	}
+}
+
+func TestServer_Scan(t *testing.T) {
+	server, db := newTestTable(t)
+	server.PageSize = 2
+	for i := 0; i < 4; i++ {
+		_, err := db.PutItem(context.TODO(), &dynamodb.PutItemInput{TableName: aws.String("test"), Item: testItem(fmt.Sprint(i), i, "kind", []string{"even", "odd"}[i%2])})
+		assert.Nil(t, err)
+	}
+
+	filter, err := expression.NewBuilder().WithFilter(expression.Name("kind").Equal(expression.Value("odd"))).Build()
+	assert.Nil(t, err)
+	keys := []string{}
+	var startKey map[string]types.AttributeValue
+	for {
+		output, err := db.Scan(context.TODO(), &dynamodb.ScanInput{
+			TableName:                 aws.String("test"),
+			FilterExpression:          filter.Filter(),
+			ExpressionAttributeNames:  filter.Names(),
+			ExpressionAttributeValues: filter.Values(),
+			ExclusiveStartKey:         startKey,
+		})
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, output.ScannedCount, int32(2), "The page size applies before the filter")
+		for _, item := range output.Items {
+			keys = append(keys, item["pk"].(*types.AttributeValueMemberS).Value)
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = output.LastEvaluatedKey
+	}
+	assert.Equal(t, []string{"1", "3"}, keys)
+}
+
+func TestServer_TransactWriteItems(t *testing.T) {
+	tests := []struct {
+		name            string
+		transactItems   []types.TransactWriteItem
+		expectedReasons []string
+		expectedItems   int
+		expectError     bool
+	}{
+		{
+			name: "Applies every write",
+			transactItems: []types.TransactWriteItem{
+				{Put: &types.Put{TableName: aws.String("test"), Item: testItem("b", 1), ConditionExpression: aws.String("attribute_not_exists(pk)")}},
+				{Delete: &types.Delete{TableName: aws.String("test"), Key: testItem("a", 1), ConditionExpression: aws.String("attribute_exists(pk)")}},
+				{ConditionCheck: &types.ConditionCheck{TableName: aws.String("test"), Key: testItem("a", 2), ConditionExpression: aws.String("attribute_not_exists(pk)")}},
+			},
+			expectedItems: 1,
+		},
+		{
+			name: "Applies none of the writes if a condition fails",
+			transactItems: []types.TransactWriteItem{
+				{Put: &types.Put{TableName: aws.String("test"), Item: testItem("b", 1)}},
+				{Put: &types.Put{TableName: aws.String("test"), Item: testItem("a", 1), ConditionExpression: aws.String("attribute_not_exists(pk)")}},
+			},
+			expectedReasons: []string{"None", "ConditionalCheckFailed"},
+			expectedItems:   1,
+			expectError:     true,
+		},
+		{
+			name: "Rejects two writes to the same item",
+			transactItems: []types.TransactWriteItem{
+				{Put: &types.Put{TableName: aws.String("test"), Item: testItem("a", 1)}},
+				{Delete: &types.Delete{TableName: aws.String("test"), Key: testItem("a", 1)}},
+			},
+			expectedItems: 1,
+			expectError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, db := newTestTable(t)
+			_, err := db.PutItem(context.TODO(), &dynamodb.PutItemInput{TableName: aws.String("test"), Item: testItem("a", 1)})
+			assert.Nil(t, err)
+
+			_, err = db.TransactWriteItems(context.TODO(), &dynamodb.TransactWriteItemsInput{TransactItems: tt.transactItems})
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from TransactWriteItems")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from TransactWriteItems")
+			}
+			if tt.expectedReasons != nil {
+				var canceled *types.TransactionCanceledException
+				assert.True(t, errors.As(err, &canceled), "The TransactionCanceledException should have been returned")
+				reasons := []string{}
+				for _, reason := range canceled.CancellationReasons {
+					reasons = append(reasons, aws.ToString(reason.Code))
+				}
+				assert.Equal(t, tt.expectedReasons, reasons)
+			}
+			items, err := server.Items("test")
+			assert.Nil(t, err)
+			assert.Len(t, items, tt.expectedItems)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package ddbtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// keySchema is the hash key and optional range key of a table or index
+type keySchema struct {
+	hashKey  string
+	rangeKey string
+}
+
+func (k keySchema) names() []string {
+	if k.rangeKey == "" {
+		return []string{k.hashKey}
+	}
+	return []string{k.hashKey, k.rangeKey}
+}
+
+type index struct {
+	name string
+	keySchema
+}
+
+type table struct {
+	name           string
+	keySchema      keySchema
+	attributeTypes map[string]string
+	indexes        []index
+	// ttlAttribute is empty unless time to live is enabled
+	ttlAttribute string
+	// items are stored by the string form of their primary key
+	items map[string]map[string]types.AttributeValue
+}
+
+func (t *table) index(name string) (index, bool) {
+	for _, index := range t.indexes {
+		if index.name == name {
+			return index, true
+		}
+	}
+	return index{}, false
+}
+
+// primaryKey validates that the key has exactly the table's key attributes and returns its string form;
+// the item may have other attributes if allowExtra is set
+func (t *table) primaryKey(key map[string]types.AttributeValue, allowExtra bool) (string, error) {
+	parts := []string{}
+	for _, name := range t.keySchema.names() {
+		value, ok := key[name]
+		if !ok {
+			return "", fmt.Errorf("One of the required keys was not given a value")
+		}
+		if err := t.checkKeyValue(name, value); err != nil {
+			return "", err
+		}
+		parts = append(parts, keyString(value))
+	}
+	if !allowExtra && len(key) != len(parts) {
+		return "", fmt.Errorf("The provided key element does not match the schema")
+	}
+	return strings.Join(parts, "\x00"), nil
+}
+
+// checkKeyValue validates the value of a table or index key attribute against its attribute definition
+func (t *table) checkKeyValue(name string, value types.AttributeValue) error {
+	expectedType := t.attributeTypes[name]
+	if typeName(value) != expectedType {
+		return fmt.Errorf("One or more parameter values were invalid: Type mismatch for key %s expected: %s actual: %s", name, expectedType, typeName(value))
+	}
+	switch value := value.(type) {
+	case *types.AttributeValueMemberS:
+		if value.Value == "" {
+			return fmt.Errorf("One or more parameter values are not valid. The AttributeValue for a key attribute cannot contain an empty string value. Key: %s", name)
+		}
+	case *types.AttributeValueMemberB:
+		if len(value.Value) == 0 {
+			return fmt.Errorf("One or more parameter values are not valid. The AttributeValue for a key attribute cannot contain an empty binary value. Key: %s", name)
+		}
+	}
+	return nil
+}
+
+// checkItem validates an item before it's written: the table's keys must be present,
+// and the index keys must have the right type if present, since items without them simply aren't indexed
+func (t *table) checkItem(item map[string]types.AttributeValue) (string, error) {
+	key, err := t.primaryKey(item, true)
+	if err != nil {
+		return "", err
+	}
+	for _, index := range t.indexes {
+		for _, name := range index.names() {
+			if value, ok := item[name]; ok {
+				if err := t.checkKeyValue(name, value); err != nil {
+					return "", fmt.Errorf("%s (index %s)", err, index.name)
+				}
+			}
+		}
+	}
+	return key, nil
+}
+
+func keyString(value types.AttributeValue) string {
+	switch value := value.(type) {
+	case *types.AttributeValueMemberS:
+		return "S" + value.Value
+	case *types.AttributeValueMemberN:
+		number, _ := parseNumber(value.Value)
+		return "N" + number.Text('g', -1)
+	case *types.AttributeValueMemberB:
+		return "B" + string(value.Value)
+	}
+	return ""
+}
+
+// keyAttributes returns the attributes of the item that make up the given key schemas,
+// which is what LastEvaluatedKey holds
+func keyAttributes(item map[string]types.AttributeValue, schemas ...keySchema) map[string]types.AttributeValue {
+	key := map[string]types.AttributeValue{}
+	for _, schema := range schemas {
+		for _, name := range schema.names() {
+			if value, ok := item[name]; ok {
+				key[name] = value
+			}
+		}
+	}
+	return key
+}
+
+// compareItems orders items by the given key schemas in turn, which gives every item a unique position
+func compareItems(a, b map[string]types.AttributeValue, schemas ...keySchema) int {
+	for _, schema := range schemas {
+		for _, name := range schema.names() {
+			if result, _ := compareValues(a[name], b[name]); result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+// readRequest is what Query and Scan have in common
+type readRequest struct {
+	indexName         string
+	condition         condition
+	filter            condition
+	limit             int
+	exclusiveStartKey map[string]types.AttributeValue
+	forward           bool
+}
+
+type readResult struct {
+	items            []map[string]types.AttributeValue
+	scannedCount     int
+	lastEvaluatedKey map[string]types.AttributeValue
+}
+
+// read evaluates items in key order, starting after exclusiveStartKey, until limit or pageSize items were evaluated;
+// like dynamodb, the limit applies before the filter, so a page can be empty even though more items match
+func (t *table) read(request readRequest, pageSize int) (readResult, error) {
+	// the table's key orders items for a scan; an index orders them by its own key first, then the table's
+	schemas := []keySchema{t.keySchema}
+	if request.indexName != "" {
+		index, ok := t.index(request.indexName)
+		if !ok {
+			return readResult{}, fmt.Errorf("The table does not have the specified index: %s", request.indexName)
+		}
+		schemas = []keySchema{index.keySchema, t.keySchema}
+	}
+
+	candidates := []map[string]types.AttributeValue{}
+	for _, item := range t.items {
+		// items without the index's key attributes aren't in the index
+		if len(keyAttributes(item, schemas[0])) != len(schemas[0].names()) {
+			continue
+		}
+		if request.condition != nil {
+			matches, err := request.condition.matches(item)
+			if err != nil {
+				return readResult{}, err
+			}
+			if !matches {
+				continue
+			}
+		}
+		candidates = append(candidates, item)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		result := compareItems(candidates[i], candidates[j], schemas...)
+		if request.forward {
+			return result < 0
+		}
+		return result > 0
+	})
+
+	if request.exclusiveStartKey != nil {
+		start := len(candidates)
+		for i, item := range candidates {
+			result := compareItems(item, request.exclusiveStartKey, schemas...)
+			if (request.forward && result > 0) || (!request.forward && result < 0) {
+				start = i
+				break
+			}
+		}
+		candidates = candidates[start:]
+	}
+
+	limit := request.limit
+	if limit <= 0 || (pageSize > 0 && pageSize < limit) {
+		limit = pageSize
+	}
+	result := readResult{items: []map[string]types.AttributeValue{}}
+	// like dynamodb, LastEvaluatedKey is set whenever the limit was reached, even if no items are left
+	if limit > 0 && len(candidates) >= limit {
+		candidates = candidates[:limit]
+		result.lastEvaluatedKey = keyAttributes(candidates[limit-1], schemas...)
+	}
+	result.scannedCount = len(candidates)
+	for _, item := range candidates {
+		if request.filter != nil {
+			matches, err := request.filter.matches(item)
+			if err != nil {
+				return readResult{}, err
+			}
+			if !matches {
+				continue
+			}
+		}
+		result.items = append(result.items, copyItem(item))
+	}
+	return result, nil
+}
+
+// checkKeyCondition validates that a query's key condition is an equality on the hash key,
+// optionally combined with a single condition on the range key
+func checkKeyCondition(c condition, schema keySchema) error {
+	conditions := []condition{c}
+	if and, ok := c.(andCondition); ok {
+		conditions = []condition{and.left, and.right}
+	}
+	hashKeyFound := false
+	rangeKeyFound := false
+	for _, c := range conditions {
+		name, comparator := keyConditionTarget(c)
+		switch {
+		case name == schema.hashKey && comparator == "=" && !hashKeyFound:
+			hashKeyFound = true
+		case name == schema.rangeKey && schema.rangeKey != "" && comparator != "" && comparator != "<>" && !rangeKeyFound:
+			rangeKeyFound = true
+		default:
+			return fmt.Errorf("Query key condition not supported")
+		}
+	}
+	if !hashKeyFound {
+		return fmt.Errorf("Query condition missed key schema element: %s", schema.hashKey)
+	}
+	return nil
+}
+
+// keyConditionTarget returns the key attribute that a key condition applies to and its comparator,
+// or an empty name if the condition can't be used in a key condition
+func keyConditionTarget(c condition) (string, string) {
+	switch c := c.(type) {
+	case comparison:
+		left, isPath := c.left.(pathOperand)
+		_, isValue := c.right.(valueOperand)
+		if isPath && isValue && len(left.path) == 1 {
+			return left.path[0].(string), c.comparator
+		}
+	case betweenCondition:
+		target, isPath := c.value.(pathOperand)
+		if isPath && len(target.path) == 1 {
+			return target.path[0].(string), "BETWEEN"
+		}
+	case functionCondition:
+		if c.name == "begins_with" && len(c.path) == 1 {
+			return c.path[0].(string), "begins_with"
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var (
+	isRetryable = retry.IsErrorRetryables(retry.DefaultRetryables)
+	isThrottle  = retry.IsErrorThrottles(retry.DefaultThrottles)
+
+	errCircuitOpen = errors.New("the circuit breaker is open")
+)
+
+// ResilienceConfig controls how the ResilientDDBClient retries failed requests
+// and when its circuit breaker stops sending requests to dynamodb
+type ResilienceConfig struct {
+	// MaxAttempts is the maximum number of times a request is sent, including the first attempt
+	MaxAttempts int
+	// BaseDelay and MaxDelay bound the exponential backoff between attempts
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// FailureThreshold is the number of consecutive failed requests that opens the circuit breaker
+	FailureThreshold int
+	// ResetTimeout is how long the circuit breaker stays open before letting a trial request through
+	ResetTimeout time.Duration
+}
+
+// NewResilienceConfig takes the retry and circuit breaker settings from the app config
+func NewResilienceConfig(appConfig model.AppConfig) ResilienceConfig {
+	return ResilienceConfig{
+		MaxAttempts:      appConfig.DynamodbMaxAttempts,
+		BaseDelay:        appConfig.DynamodbRetryBaseDelay,
+		MaxDelay:         appConfig.DynamodbRetryMaxDelay,
+		FailureThreshold: appConfig.DynamodbCircuitFailureThreshold,
+		ResetTimeout:     appConfig.DynamodbCircuitResetTimeout,
+	}
+}
+
+// ResilientDDBClient wraps a DDBClient with retries for throttling and transient errors,
+// a circuit breaker, and translation of the sdk's errors into apperrors:
+//   - ConditionalCheckFailedException, TransactionConflictException and TransactionCanceledException become the ConflictError
+//   - ResourceNotFoundException, a missing table or index, is a misconfiguration rather than a missing item,
+//     so it's left as an unexpected error that clients see as an internal error
+//   - errors that are still transient after every attempt, or an open circuit, become the UnavailableError
+//
+// The translated errors wrap the original error, so errors.As still finds the sdk's exceptions
+type ResilientDDBClient struct {
+	db      DDBClient
+	config  ResilienceConfig
+	breaker *circuitBreaker
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewResilientDDBClient wraps the client using the given config
+func NewResilientDDBClient(db DDBClient, config ResilienceConfig) *ResilientDDBClient {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	return &ResilientDDBClient{
+		db:      db,
+		config:  config,
+		breaker: newCircuitBreaker(config.FailureThreshold, config.ResetTimeout, time.Now),
+		sleep:   sleepContext,
+	}
+}
+
+// CreateResilientDDBClient creates a dynamodb client for the app config's endpoint and wraps it in a ResilientDDBClient
+func CreateResilientDDBClient(appConfig model.AppConfig) (*ResilientDDBClient, error) {
+	ddbClient, err := CreateLocalDDBClient(appConfig.AwsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewResilientDDBClient(ddbClient, NewResilienceConfig(appConfig)), nil
+}
+
+func (c *ResilientDDBClient) Scan(ctx context.Context, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	var output *dynamodb.ScanOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.Scan(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+func (c *ResilientDDBClient) GetItem(ctx context.Context, input *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	var output *dynamodb.GetItemOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.GetItem(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+func (c *ResilientDDBClient) BatchGetItem(ctx context.Context, input *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	var output *dynamodb.BatchGetItemOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.BatchGetItem(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+func (c *ResilientDDBClient) Query(ctx context.Context, input *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	var output *dynamodb.QueryOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.Query(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+func (c *ResilientDDBClient) PutItem(ctx context.Context, input *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	var output *dynamodb.PutItemOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.PutItem(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+func (c *ResilientDDBClient) UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	var output *dynamodb.UpdateItemOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.UpdateItem(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+func (c *ResilientDDBClient) DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	var output *dynamodb.DeleteItemOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.DeleteItem(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+func (c *ResilientDDBClient) TransactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	var output *dynamodb.TransactWriteItemsOutput
+	err := c.do(ctx, func(ctx context.Context) (err error) {
+		output, err = c.db.TransactWriteItems(ctx, input, withoutSDKRetries(optFns)...)
+		return err
+	})
+	return output, err
+}
+
+// do sends the request until it succeeds, fails with an error that isn't transient, or runs out of attempts
+func (c *ResilientDDBClient) do(ctx context.Context, request func(ctx context.Context) error) error {
+	if !c.breaker.allow() {
+		return apperrors.NewUnavailableError("the database is temporarily unavailable; please try again later", errCircuitOpen)
+	}
+
+	var err error
+	for attempt := 0; attempt < c.config.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			if sleepErr := c.sleep(ctx, c.backoff(attempt)); sleepErr != nil {
+				break
+			}
+		}
+		err = request(ctx)
+		if !isTransient(err) {
+			break
+		}
+	}
+
+	// only transient errors count against the circuit breaker;
+	// errors like failed conditions mean that dynamodb is handling requests just fine
+	if isTransient(err) {
+		c.breaker.recordFailure()
+	} else {
+		c.breaker.recordSuccess()
+	}
+	return translateError(err)
+}
+
+// backoff returns a random delay of up to BaseDelay * 2^(attempt-1), capped at MaxDelay
+func (c *ResilientDDBClient) backoff(attempt int) time.Duration {
+	delay := c.config.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > c.config.MaxDelay {
+		delay = c.config.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
+
+// isTransient checks if the error is caused by throttling or a temporary failure that might succeed when retried
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	return isThrottle.IsErrorThrottle(err) == aws.TrueTernary || isRetryable.IsErrorRetryable(err) == aws.TrueTernary
+}
+
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	var transactionConflict *types.TransactionConflictException
+	var transactionCanceled *types.TransactionCanceledException
+	var resourceNotFound *types.ResourceNotFoundException
+	switch {
+	case errors.As(err, &conditionalCheckFailed), errors.As(err, &transactionConflict):
+		return apperrors.NewConflictError("the item was modified by another request", err)
+	case errors.As(err, &transactionCanceled):
+		return apperrors.NewConflictError("the transaction was canceled because one of its items failed a condition or was modified by another request", err)
+	case errors.As(err, &resourceNotFound):
+		return fmt.Errorf("the requested table or index doesn't exist; check the table names in the config: %w", err)
+	case isTransient(err):
+		return apperrors.NewUnavailableError("the database is temporarily unavailable; please try again later", err)
+	default:
+		return err
+	}
+}
+
+// withoutSDKRetries disables the sdk's own retryer so that the ResilientDDBClient's policy is the only one applied
+func withoutSDKRetries(optFns []func(*dynamodb.Options)) []func(*dynamodb.Options) {
+	return append(optFns, func(o *dynamodb.Options) {
+		o.RetryMaxAttempts = 1
+	})
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// circuitBreaker stops requests from being sent after failureThreshold consecutive failures;
+// once resetTimeout has passed, a single trial request is let through, which closes the circuit if it succeeds
+type circuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	resetTimeout     time.Duration
+	now              func() time.Time
+
+	failures int
+	openedAt time.Time
+	trialing bool
+}
+
+func newCircuitBreaker(failureThreshold int, resetTimeout time.Duration, now func() time.Time) *circuitBreaker {
+	return &circuitBreaker{
+		failureThreshold: failureThreshold,
+		resetTimeout:     resetTimeout,
+		now:              now,
+	}
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failureThreshold <= 0 || b.failures < b.failureThreshold {
+		return true
+	}
+	if b.trialing || b.now().Sub(b.openedAt) < b.resetTimeout {
+		return false
+	}
+	b.trialing = true
+	return true
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.trialing = false
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trialing = false
+	if b.failures >= b.failureThreshold {
+		b.openedAt = b.now()
+	}
+}
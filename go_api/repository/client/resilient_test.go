@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+)
+
+func newTestResilientClient(db DDBClient, config ResilienceConfig) (*ResilientDDBClient, *[]time.Duration) {
+	c := NewResilientDDBClient(db, config)
+	delays := []time.Duration{}
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return c, &delays
+}
+
+func TestResilientDDBClient_Query(t *testing.T) {
+	config := ResilienceConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	input := &dynamodb.QueryInput{}
+	output := &dynamodb.QueryOutput{Count: 1}
+	throttled := &types.ProvisionedThroughputExceededException{}
+	tests := []struct {
+		name                 string
+		returnedErrors       []error
+		expectedCalls        int
+		expectedErrorType    interface{}
+		expectSDKErrorCause  interface{}
+		expectUnchangedError bool
+	}{
+		{
+			name:           "Succeeded after being throttled",
+			returnedErrors: []error{throttled, throttled, nil},
+			expectedCalls:  3,
+		},
+		{
+			name:                "Throttled on every attempt",
+			returnedErrors:      []error{throttled, throttled, throttled},
+			expectedCalls:       3,
+			expectedErrorType:   &apperrors.UnavailableError{},
+			expectSDKErrorCause: new(*types.ProvisionedThroughputExceededException),
+		},
+		{
+			name:                "Condition failed",
+			returnedErrors:      []error{&types.ConditionalCheckFailedException{}},
+			expectedCalls:       1,
+			expectedErrorType:   &apperrors.ConflictError{},
+			expectSDKErrorCause: new(*types.ConditionalCheckFailedException),
+		},
+		{
+			name:                "Transaction canceled",
+			returnedErrors:      []error{&types.TransactionCanceledException{}},
+			expectedCalls:       1,
+			expectedErrorType:   &apperrors.ConflictError{},
+			expectSDKErrorCause: new(*types.TransactionCanceledException),
+		},
+		{
+			name:              "Table doesn't exist",
+			returnedErrors:    []error{&types.ResourceNotFoundException{}},
+			expectedCalls:     1,
+			expectedErrorType: new(*types.ResourceNotFoundException),
+		},
+		{
+			name:                 "Unexpected error isn't retried",
+			returnedErrors:       []error{fmt.Errorf("invalid request")},
+			expectedCalls:        1,
+			expectUnchangedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := NewMockDDBClient(t)
+			for _, err := range tt.returnedErrors {
+				if err == nil {
+					mockDdbClient.On("Query", context.TODO(), input, mock.Anything).Return(output, nil).Once()
+					continue
+				}
+				mockDdbClient.On("Query", context.TODO(), input, mock.Anything).Return(nil, err).Once()
+			}
+			c, delays := newTestResilientClient(mockDdbClient, config)
+
+			actualOutput, err := c.Query(context.TODO(), input)
+			mockDdbClient.AssertNumberOfCalls(t, "Query", tt.expectedCalls)
+			assert.Len(t, *delays, tt.expectedCalls-1)
+			for _, delay := range *delays {
+				assert.LessOrEqual(t, delay, config.MaxDelay)
+			}
+
+			if tt.expectedErrorType == nil && !tt.expectUnchangedError {
+				assert.NoError(t, err)
+				assert.Equal(t, output, actualOutput)
+				return
+			}
+			if tt.expectUnchangedError {
+				assert.Equal(t, tt.returnedErrors[len(tt.returnedErrors)-1], err)
+				return
+			}
+			assert.ErrorAs(t, err, tt.expectedErrorType)
+			if tt.expectSDKErrorCause != nil {
+				assert.ErrorAs(t, err, tt.expectSDKErrorCause, "the translated error should wrap the sdk's error")
+			}
+		})
+	}
+}
+
+func TestResilientDDBClient_CircuitBreaker(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	input := &dynamodb.PutItemInput{}
+	mockDdbClient := NewMockDDBClient(t)
+	c, _ := newTestResilientClient(mockDdbClient, ResilienceConfig{MaxAttempts: 1, FailureThreshold: 2, ResetTimeout: time.Minute})
+	c.breaker.now = func() time.Time { return now }
+
+	// two consecutive failures open the circuit
+	mockDdbClient.On("PutItem", context.TODO(), input, mock.Anything).
+		Return(nil, &types.ProvisionedThroughputExceededException{}).Twice()
+	for i := 0; i < 2; i++ {
+		_, err := c.PutItem(context.TODO(), input)
+		assert.ErrorAs(t, err, &apperrors.UnavailableError{})
+	}
+
+	// requests are rejected without calling dynamodb while the circuit is open
+	_, err := c.PutItem(context.TODO(), input)
+	assert.ErrorAs(t, err, &apperrors.UnavailableError{})
+	assert.True(t, errors.Is(err, errCircuitOpen))
+	mockDdbClient.AssertNumberOfCalls(t, "PutItem", 2)
+
+	// after the reset timeout, a successful trial request closes the circuit
+	now = now.Add(time.Minute)
+	mockDdbClient.On("PutItem", context.TODO(), input, mock.Anything).
+		Return(&dynamodb.PutItemOutput{}, nil).Twice()
+	for i := 0; i < 2; i++ {
+		_, err = c.PutItem(context.TODO(), input)
+		assert.NoError(t, err)
+	}
+	mockDdbClient.AssertNumberOfCalls(t, "PutItem", 4)
+}
+
+func TestNewResilienceConfig(t *testing.T) {
+	t.Setenv("DYNAMODB_MAX_ATTEMPTS", "5")
+	t.Setenv("DYNAMODB_RETRY_BASE_DELAY", "not a duration")
+	config := NewResilienceConfig(model.NewAppConfig())
+	assert.Equal(t, 5, config.MaxAttempts)
+	assert.Equal(t, 50*time.Millisecond, config.BaseDelay, "invalid values should fall back to the default")
+	assert.Equal(t, 30*time.Second, config.ResetTimeout)
+}
+
+func TestTranslateError_MissingTable(t *testing.T) {
+	statusCode, message := apperrors.StatusCode(translateError(&types.ResourceNotFoundException{}))
+	assert.Equal(t, http.StatusInternalServerError, statusCode, "a missing table is a server fault, not a missing item")
+	assert.Equal(t, apperrors.InternalErrorMessage, message)
+}
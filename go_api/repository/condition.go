@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// isConditionalCheckFailed checks if the write was rejected because its condition expression failed
+func isConditionalCheckFailed(err error) bool {
+	var conditionalCheckFailed *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionalCheckFailed)
+}
+
+// versionCondition builds the condition for optimistic concurrency control, which only allows a write
+// if the item exists and hasn't been modified since expectedVersion was read;
+// items written before versions were tracked have no version attribute and are treated as version 0
+func versionCondition(expectedVersion int) (expression.Expression, error) {
+	return versionConditionOn("id", expectedVersion)
+}
+
+// versionConditionOn is versionCondition for items that are identified by their keyAttribute instead of an id
+func versionConditionOn(keyAttribute string, expectedVersion int) (expression.Expression, error) {
+	versionMatches := expression.Name("version").Equal(expression.Value(expectedVersion))
+	if expectedVersion == 0 {
+		versionMatches = expression.Or(expression.AttributeNotExists(expression.Name("version")), versionMatches)
+	}
+	condition := expression.AttributeExists(expression.Name(keyAttribute)).And(versionMatches)
+	return expression.NewBuilder().WithCondition(condition).Build()
+}
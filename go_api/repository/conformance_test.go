@@ -0,0 +1,280 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/migration"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/repository/client"
+	"the-drink-almanac-api/repository/client/ddbtest"
+	"the-drink-almanac-api/repository/repositorytest"
+)
+
+// conformancePageSize is smaller than the suites' page tests, so every read has to follow the pagination
+const conformancePageSize = 10
+
+var conformanceConfig = model.AppConfig{
+	UsersTableName:          "users",
+	FavoritesTableName:      "favorites",
+	SchemaVersionsTableName: "schema-versions",
+	OutboxTableName:         "outbox",
+	DrinksTableName:         "drinks",
+	InventoryTableName:      "inventory",
+	RecipesTableName:        "recipes",
+	SingleTableName:         "the-drink-almanac",
+}
+
+// newConformanceClient migrates a fresh fake dynamodb server for the table design and returns a client for it
+func newConformanceClient(t *testing.T, appConfig model.AppConfig) client.DDBClient {
+	server := ddbtest.NewServer(t)
+	server.PageSize = conformancePageSize
+	migrator := migration.NewMigrator(server.Client(), appConfig)
+	migrator.PollInterval = time.Millisecond
+	if err := migrator.Run(context.Background()); err != nil {
+		t.Fatalf("The tables should have been created: %v", err)
+	}
+	return client.NewResilientDDBClient(server.Client(), client.ResilienceConfig{MaxAttempts: 1})
+}
+
+// newConformanceRepositories creates the repositories of the table design on top of a fresh fake dynamodb server
+func newConformanceRepositories(t *testing.T, tableDesign string) (repository.UserRepository, repository.FavoriteRepository) {
+	appConfig := conformanceConfig
+	appConfig.TableDesign = tableDesign
+	ddbClient := newConformanceClient(t, appConfig)
+	if tableDesign == model.SingleTableDesign {
+		return &repository.SingleTableUserRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName},
+			&repository.SingleTableFavoriteRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName}
+	}
+	return &repository.UserRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.UsersTableName, OutboxTableName: appConfig.OutboxTableName},
+		&repository.FavoriteRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.FavoritesTableName, OutboxTableName: appConfig.OutboxTableName}
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	backends := []struct {
+		name            string
+		tableDesign     string
+		cached          bool
+		uniqueUsernames bool
+	}{
+		{name: "Multi-table", tableDesign: model.MultiTableDesign},
+		{name: "Multi-table with cache", tableDesign: model.MultiTableDesign, cached: true},
+		{name: "Single-table", tableDesign: model.SingleTableDesign, uniqueUsernames: true},
+		{name: "Single-table with cache", tableDesign: model.SingleTableDesign, cached: true, uniqueUsernames: true},
+	}
+	for _, backend := range backends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			repositorytest.UserRepositorySuite{
+				NewRepository: func(t *testing.T) repository.UserRepository {
+					userRepository, _ := newConformanceRepositories(t, backend.tableDesign)
+					if backend.cached {
+						return repository.NewCachedUserRepository(userRepository, repository.NewLRUCache(100), time.Minute)
+					}
+					return userRepository
+				},
+				UniqueUsernames: backend.uniqueUsernames,
+			}.Run(t)
+		})
+	}
+}
+
+func TestFavoriteRepositoryConformance(t *testing.T) {
+	backends := []struct {
+		name        string
+		tableDesign string
+		cached      bool
+	}{
+		{name: "Multi-table", tableDesign: model.MultiTableDesign},
+		{name: "Multi-table with cache", tableDesign: model.MultiTableDesign, cached: true},
+		{name: "Single-table", tableDesign: model.SingleTableDesign},
+		{name: "Single-table with cache", tableDesign: model.SingleTableDesign, cached: true},
+	}
+	for _, backend := range backends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			repositorytest.FavoriteRepositorySuite{
+				NewRepository: func(t *testing.T) repository.FavoriteRepository {
+					_, favoriteRepository := newConformanceRepositories(t, backend.tableDesign)
+					if backend.cached {
+						return repository.NewCachedFavoriteRepository(favoriteRepository, repository.NewLRUCache(100), time.Minute)
+					}
+					return favoriteRepository
+				},
+			}.Run(t)
+		})
+	}
+}
+
+func TestDrinkRepositoryConformance(t *testing.T) {
+	backends := []struct {
+		name          string
+		newRepository func(t *testing.T) repository.DrinkRepository
+	}{
+		{
+			name: "Multi-table",
+			newRepository: func(t *testing.T) repository.DrinkRepository {
+				appConfig := conformanceConfig
+				appConfig.TableDesign = model.MultiTableDesign
+				return &repository.DrinkRepositoryDDB{DynamodbClient: newConformanceClient(t, appConfig), TableName: appConfig.DrinksTableName}
+			},
+		},
+		{
+			name: "Single-table",
+			newRepository: func(t *testing.T) repository.DrinkRepository {
+				appConfig := conformanceConfig
+				appConfig.TableDesign = model.SingleTableDesign
+				return &repository.SingleTableDrinkRepositoryDDB{DynamodbClient: newConformanceClient(t, appConfig), TableName: appConfig.SingleTableName}
+			},
+		},
+		{
+			name: "Memory",
+			newRepository: func(t *testing.T) repository.DrinkRepository {
+				return repository.NewMemoryDrinkRepository()
+			},
+		},
+	}
+	for _, backend := range backends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			repositorytest.DrinkRepositorySuite{NewRepository: backend.newRepository}.Run(t)
+		})
+	}
+}
+
+func TestInventoryRepositoryConformance(t *testing.T) {
+	backends := []struct {
+		name          string
+		newRepository func(t *testing.T) repository.InventoryRepository
+	}{
+		{
+			name: "Multi-table",
+			newRepository: func(t *testing.T) repository.InventoryRepository {
+				appConfig := conformanceConfig
+				appConfig.TableDesign = model.MultiTableDesign
+				return &repository.InventoryRepositoryDDB{DynamodbClient: newConformanceClient(t, appConfig), TableName: appConfig.InventoryTableName}
+			},
+		},
+		{
+			name: "Single-table",
+			newRepository: func(t *testing.T) repository.InventoryRepository {
+				appConfig := conformanceConfig
+				appConfig.TableDesign = model.SingleTableDesign
+				return &repository.SingleTableInventoryRepositoryDDB{DynamodbClient: newConformanceClient(t, appConfig), TableName: appConfig.SingleTableName}
+			},
+		},
+		{
+			name: "Memory",
+			newRepository: func(t *testing.T) repository.InventoryRepository {
+				return repository.NewMemoryInventoryRepository()
+			},
+		},
+	}
+	for _, backend := range backends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			repositorytest.InventoryRepositorySuite{NewRepository: backend.newRepository}.Run(t)
+		})
+	}
+}
+
+func TestRecipeRepositoryConformance(t *testing.T) {
+	backends := []struct {
+		name          string
+		newRepository func(t *testing.T) repository.RecipeRepository
+	}{
+		{
+			name: "Multi-table",
+			newRepository: func(t *testing.T) repository.RecipeRepository {
+				appConfig := conformanceConfig
+				appConfig.TableDesign = model.MultiTableDesign
+				return &repository.RecipeRepositoryDDB{DynamodbClient: newConformanceClient(t, appConfig), TableName: appConfig.RecipesTableName}
+			},
+		},
+		{
+			name: "Single-table",
+			newRepository: func(t *testing.T) repository.RecipeRepository {
+				appConfig := conformanceConfig
+				appConfig.TableDesign = model.SingleTableDesign
+				return &repository.SingleTableRecipeRepositoryDDB{DynamodbClient: newConformanceClient(t, appConfig), TableName: appConfig.SingleTableName}
+			},
+		},
+		{
+			name: "Memory",
+			newRepository: func(t *testing.T) repository.RecipeRepository {
+				return repository.NewMemoryRecipeRepository()
+			},
+		},
+	}
+	for _, backend := range backends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			repositorytest.RecipeRepositorySuite{NewRepository: backend.newRepository}.Run(t)
+		})
+	}
+}
+
+func TestOutboxRepositoryConformance(t *testing.T) {
+	for _, tableDesign := range []string{model.MultiTableDesign, model.SingleTableDesign} {
+		tableDesign := tableDesign
+		t.Run(tableDesign, func(t *testing.T) {
+			appConfig := conformanceConfig
+			appConfig.TableDesign = tableDesign
+			ddbClient := newConformanceClient(t, appConfig)
+			users := repository.UserRepository(&repository.UserRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.UsersTableName, OutboxTableName: appConfig.OutboxTableName})
+			outbox := repository.OutboxRepository(&repository.OutboxRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.OutboxTableName})
+			if tableDesign == model.SingleTableDesign {
+				users = &repository.SingleTableUserRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName}
+				outbox = &repository.SingleTableOutboxRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName}
+			}
+
+			// more events than a page, so that the pending events have to be read from the index rather than scanned
+			eventCount := conformancePageSize + 2
+			for i := 0; i < eventCount; i++ {
+				user := model.User{Id: fmt.Sprintf("%d", i), Username: fmt.Sprintf("username%d", i)}
+				event := model.Event{
+					Id:         fmt.Sprintf("event%d", i),
+					Type:       model.UserCreated,
+					OccurredAt: time.Date(2023, 1, 1, 0, eventCount-i, 0, 0, time.UTC),
+					UserId:     user.Id,
+				}
+				if err := users.CreateNewUser(user, event); err != nil {
+					t.Fatalf("The user should have been created: %v", err)
+				}
+			}
+
+			now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+			relayed := 0
+			for {
+				events, err := outbox.FindPendingEvents(5)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, len(events), 5)
+				if len(events) == 0 {
+					break
+				}
+				for i, event := range events {
+					assert.Equal(t, model.UserCreated, event.Type)
+					if i > 0 {
+						assert.False(t, event.OccurredAt.Before(events[i-1].OccurredAt), "The events should be oldest first")
+					}
+					claimed, err := outbox.ClaimEvent(event, now, time.Minute)
+					assert.NoError(t, err)
+					assert.True(t, claimed, "The pending event should have been claimed")
+					claimed, err = outbox.ClaimEvent(event, now.Add(time.Second), time.Minute)
+					assert.NoError(t, err)
+					assert.False(t, claimed, "The event should still be claimed by the first claim")
+					assert.NoError(t, outbox.DeleteEvent(event))
+					claimed, err = outbox.ClaimEvent(event, now.Add(time.Hour), time.Minute)
+					assert.NoError(t, err)
+					assert.False(t, claimed, "A deleted event shouldn't be claimed")
+					relayed++
+				}
+			}
+			assert.Equal(t, eventCount, relayed)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+//go:generate mockery --name=DrinkRepository --output=./ --outpkg=repository --filename=drink_mock.go --inpackage
+package repository
+
+import (
+	"context"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type DrinkRepository interface {
+	FindAll() ([]model.Drink, error)
+	// FindDrinkById returns the drink with the given id, or nil if it isn't in the catalog
+	FindDrinkById(id string) (*model.Drink, error)
+	// FindDrinksByIds returns the drinks with the given ids by id; ids may repeat, and drinks that aren't in the catalog are left out
+	FindDrinksByIds(ids []string) (map[string]model.Drink, error)
+	// SaveDrink inserts the drink or replaces the drink with the same id
+	SaveDrink(drink model.Drink) error
+}
+
+// NewDrinkRepository creates the drink repository for the table design selected by the app config
+func NewDrinkRepository(appConfig model.AppConfig) (DrinkRepository, error) {
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
+	if appConfig.TableDesign == model.SingleTableDesign {
+		return &SingleTableDrinkRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName}, err
+	}
+	return &DrinkRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.DrinksTableName}, err
+}
+
+type DrinkRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *DrinkRepositoryDDB) FindAll() ([]model.Drink, error) {
+	items, err := scanPages(r.DynamodbClient, &dynamodb.ScanInput{
+		TableName: aws.String(r.TableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	drinks := []model.Drink{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &drinks); err != nil {
+		return nil, err
+	}
+	return drinks, nil
+}
+
+func (r *DrinkRepositoryDDB) FindDrinkById(id string) (*model.Drink, error) {
+	drink := model.Drink{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: id},
+	}, &drink)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &drink, nil
+}
+
+func (r *DrinkRepositoryDDB) FindDrinksByIds(ids []string) (map[string]model.Drink, error) {
+	keys := []map[string]types.AttributeValue{}
+	for _, id := range distinctIds(ids) {
+		keys = append(keys, map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: id}})
+	}
+	return batchGetDrinks(r.DynamodbClient, r.TableName, keys)
+}
+
+func (r *DrinkRepositoryDDB) SaveDrink(drink model.Drink) error {
+	item, err := attributevalue.MarshalMap(drink)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName: aws.String(r.TableName),
+		Item:      item,
+	})
+	return err
+}
+
+// batchGetDrinks reads the drinks with the given keys and maps them by id
+func batchGetDrinks(db client.DDBClient, tableName string, keys []map[string]types.AttributeValue) (map[string]model.Drink, error) {
+	drinks := map[string]model.Drink{}
+	if len(keys) == 0 {
+		return drinks, nil
+	}
+	items, err := batchGetRecords(db, tableName, keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		drink := model.Drink{}
+		if err := attributevalue.UnmarshalMap(item, &drink); err != nil {
+			return nil, err
+		}
+		drinks[drink.Id] = drink
+	}
+	return drinks, nil
+}
+
+// distinctIds drops empty and repeated ids, since dynamodb rejects batches that request a key twice
+func distinctIds(ids []string) []string {
+	seen := map[string]bool{}
+	distinct := []string{}
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			distinct = append(distinct, id)
+		}
+	}
+	return distinct
+}
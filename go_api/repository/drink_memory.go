@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"the-drink-almanac-api/model"
+)
+
+// MemoryDrinkRepository keeps the catalog in memory, e.g. for local development and tests
+type MemoryDrinkRepository struct {
+	mu     sync.RWMutex
+	drinks map[string]model.Drink
+}
+
+// NewMemoryDrinkRepository creates a repository with the given drinks
+func NewMemoryDrinkRepository(drinks ...model.Drink) *MemoryDrinkRepository {
+	r := &MemoryDrinkRepository{drinks: map[string]model.Drink{}}
+	for _, drink := range drinks {
+		r.drinks[drink.Id] = copyDrink(drink)
+	}
+	return r
+}
+
+// FindAll returns the drinks ordered by id
+func (r *MemoryDrinkRepository) FindAll() ([]model.Drink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	drinks := make([]model.Drink, 0, len(r.drinks))
+	for _, drink := range r.drinks {
+		drinks = append(drinks, copyDrink(drink))
+	}
+	sort.Slice(drinks, func(i, j int) bool { return drinks[i].Id < drinks[j].Id })
+	return drinks, nil
+}
+
+func (r *MemoryDrinkRepository) FindDrinkById(id string) (*model.Drink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	drink, ok := r.drinks[id]
+	if !ok {
+		return nil, nil
+	}
+	drink = copyDrink(drink)
+	return &drink, nil
+}
+
+func (r *MemoryDrinkRepository) FindDrinksByIds(ids []string) (map[string]model.Drink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	drinks := map[string]model.Drink{}
+	for _, id := range ids {
+		if drink, ok := r.drinks[id]; ok {
+			drinks[id] = copyDrink(drink)
+		}
+	}
+	return drinks, nil
+}
+
+func (r *MemoryDrinkRepository) SaveDrink(drink model.Drink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drinks[drink.Id] = copyDrink(drink)
+	return nil
+}
+
+// copyDrink copies the ingredients so that callers can't modify the stored drink
+func copyDrink(drink model.Drink) model.Drink {
+	if drink.Ingredients != nil {
+		drink.Ingredients = append([]model.Ingredient{}, drink.Ingredients...)
+	}
+	return drink
+}
@@ -0,0 +1,258 @@
+//go:generate mockery --name=ExpiringStore --output=./ --outpkg=repository --filename=expiring_mock.go --inpackage
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxIncrementAttempts bounds how often Increment retries after racing with another request that restarted the counter
+const maxIncrementAttempts = 3
+
+// ExpiringStore stores short-lived records, such as reset tokens, revoked tokens and lockout counters,
+// which are removed once they expire; expired records are never returned, even if the backend hasn't removed them yet
+//
+// Keys should be prefixed with the kind of record (e.g. "reset-token#<token>"), since every kind shares the store
+type ExpiringStore interface {
+	// Put stores the value under the key until the ttl has elapsed, replacing any existing record
+	Put(key string, value []byte, ttl time.Duration) error
+	// Get returns the record stored under the key, or nil if there isn't one or it has expired
+	Get(key string) (*model.ExpiringRecord, error)
+	// Increment adds 1 to the counter stored under the key and returns its new value;
+	// a missing or expired counter starts again at 1 and expires once the ttl has elapsed
+	Increment(key string, ttl time.Duration) (int, error)
+	// Delete removes the record; deleting a record that doesn't exist does nothing
+	Delete(key string) error
+}
+
+// NewExpiringStore creates the expiring store selected by the app config; the dynamodb store uses the configured
+// table design, and the memory store has to be swept (see ExpiredRecordDeleter)
+func NewExpiringStore(appConfig model.AppConfig, metrics *ExpiryMetrics) (ExpiringStore, error) {
+	switch appConfig.ExpiringStore {
+	case "", model.ExpiringStoreDynamodb:
+	case model.ExpiringStoreMemory:
+		return NewMemoryExpiringStore(metrics), nil
+	default:
+		return nil, fmt.Errorf("invalid expiring store '%s'; it must be '%s' or '%s'",
+			appConfig.ExpiringStore, model.ExpiringStoreDynamodb, model.ExpiringStoreMemory)
+	}
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
+	if appConfig.TableDesign == model.SingleTableDesign {
+		return &ExpiringStoreDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName, SingleTableDesign: true, Metrics: metrics}, err
+	}
+	return &ExpiringStoreDDB{DynamodbClient: ddbClient, TableName: appConfig.ExpiringRecordsTableName, Metrics: metrics}, err
+}
+
+// ExpiryMetrics counts the records that expired; it's safe for concurrent use,
+// and it implements expvar.Var so it can be published with expvar.Publish
+//
+// Records deleted by dynamodb's time to live aren't counted, since the api never sees them;
+// they're reported by the table's TimeToLiveDeletedItemCount metric in CloudWatch instead
+type ExpiryMetrics struct {
+	expiredOnRead int64
+	swept         int64
+}
+
+// ExpiredOnRead is the number of reads that found a record which had expired but hadn't been removed yet
+func (m *ExpiryMetrics) ExpiredOnRead() int64 {
+	return atomic.LoadInt64(&m.expiredOnRead)
+}
+
+// Swept is the number of expired records that a Sweeper deleted
+func (m *ExpiryMetrics) Swept() int64 {
+	return atomic.LoadInt64(&m.swept)
+}
+
+func (m *ExpiryMetrics) String() string {
+	return fmt.Sprintf(`{"expired_on_read": %d, "swept": %d}`, m.ExpiredOnRead(), m.Swept())
+}
+
+// the metrics are optional, so recording them on a nil *ExpiryMetrics does nothing
+func (m *ExpiryMetrics) addExpiredOnRead() {
+	if m != nil {
+		atomic.AddInt64(&m.expiredOnRead, 1)
+	}
+}
+
+func (m *ExpiryMetrics) addSwept(count int) {
+	if m != nil {
+		atomic.AddInt64(&m.swept, int64(count))
+	}
+}
+
+// expiryTime rounds the expiry up to the second, which is the precision of dynamodb's time to live,
+// so that every store expires a record at the same time and never before its ttl has elapsed
+func expiryTime(now time.Time, ttl time.Duration) time.Time {
+	expiresAt := now.Add(ttl)
+	if truncated := expiresAt.Truncate(time.Second); truncated.Before(expiresAt) {
+		return truncated.Add(time.Second)
+	}
+	return expiresAt
+}
+
+// ExpiringStoreDDB stores the records with their expiry in the expires_at attribute as unix seconds,
+// which the table's time to live uses to delete them; dynamodb can take a few days to do so,
+// so reads also check the expiry
+type ExpiringStoreDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+	// SingleTableDesign stores the records in the single table, keyed on PK and SK (see single_table.go)
+	SingleTableDesign bool
+	// Metrics is optional
+	Metrics *ExpiryMetrics
+
+	now func() time.Time
+}
+
+func (r *ExpiringStoreDDB) clock() time.Time {
+	if r.now == nil {
+		return time.Now()
+	}
+	return r.now()
+}
+
+func (r *ExpiringStoreDDB) itemKey(key string) map[string]types.AttributeValue {
+	if r.SingleTableDesign {
+		return singleTableKey(expiringKeyPrefix+key, expiringSortKey)
+	}
+	return map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: key},
+	}
+}
+
+func (r *ExpiringStoreDDB) item(record model.ExpiringRecord) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range r.itemKey(record.Key) {
+		item[name] = value
+	}
+	if r.SingleTableDesign {
+		item[recordTypeAttribute] = &types.AttributeValueMemberS{Value: expiringRecordType}
+	}
+	return item, nil
+}
+
+func (r *ExpiringStoreDDB) Put(key string, value []byte, ttl time.Duration) error {
+	item, err := r.item(model.ExpiringRecord{Key: key, Value: value, ExpiresAt: expiryTime(r.clock(), ttl)})
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName: aws.String(r.TableName),
+		Item:      item,
+	})
+	return err
+}
+
+func (r *ExpiringStoreDDB) Get(key string) (*model.ExpiringRecord, error) {
+	record := model.ExpiringRecord{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, r.itemKey(key), &record)
+	if err != nil || !found {
+		return nil, err
+	}
+	if record.Expired(r.clock()) {
+		r.Metrics.addExpiredOnRead()
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// Increment adds to the counter as long as it hasn't expired; otherwise, the counter is restarted,
+// which is conditional too so that a concurrent restart and increment aren't lost
+func (r *ExpiringStoreDDB) Increment(key string, ttl time.Duration) (int, error) {
+	for attempt := 0; attempt < maxIncrementAttempts; attempt++ {
+		now := r.clock()
+		count, incremented, err := r.incrementUnexpired(key, now)
+		if err != nil || incremented {
+			return count, err
+		}
+
+		restarted, err := r.restartExpired(key, now, ttl)
+		if err != nil || restarted {
+			return 1, err
+		}
+		// another request restarted the counter first, so increment that one
+	}
+	return 0, apperrors.NewConflictError(fmt.Sprintf("the counter '%s' was restarted by other requests too often", key), nil)
+}
+
+// incrementUnexpired returns false if the counter doesn't exist or has expired
+func (r *ExpiringStoreDDB) incrementUnexpired(key string, now time.Time) (int, bool, error) {
+	update, err := expression.NewBuilder().
+		WithUpdate(expression.Add(expression.Name("count"), expression.Value(1))).
+		WithCondition(expression.Name("expires_at").GreaterThan(expression.Value(now.Unix()))).
+		Build()
+	if err != nil {
+		return 0, false, err
+	}
+	output, err := r.DynamodbClient.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.TableName),
+		Key:                       r.itemKey(key),
+		UpdateExpression:          update.Update(),
+		ConditionExpression:       update.Condition(),
+		ExpressionAttributeNames:  update.Names(),
+		ExpressionAttributeValues: update.Values(),
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	if isConditionalCheckFailed(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	count, ok := output.Attributes["count"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, false, fmt.Errorf("the counter '%s' doesn't have a count", key)
+	}
+	value, err := strconv.Atoi(count.Value)
+	return value, true, err
+}
+
+// restartExpired returns false if another request created or restarted the counter first
+func (r *ExpiringStoreDDB) restartExpired(key string, now time.Time, ttl time.Duration) (bool, error) {
+	item, err := r.item(model.ExpiringRecord{Key: key, Count: 1, ExpiresAt: expiryTime(now, ttl)})
+	if err != nil {
+		return false, err
+	}
+	condition, err := expression.NewBuilder().WithCondition(expression.Or(
+		expression.AttributeNotExists(expression.Name("expires_at")),
+		expression.Name("expires_at").LessThanEqual(expression.Value(now.Unix())),
+	)).Build()
+	if err != nil {
+		return false, err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(r.TableName),
+		Item:                      item,
+		ConditionExpression:       condition.Condition(),
+		ExpressionAttributeNames:  condition.Names(),
+		ExpressionAttributeValues: condition.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *ExpiringStoreDDB) Delete(key string) error {
+	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       r.itemKey(key),
+	})
+	return err
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"the-drink-almanac-api/model"
+)
+
+// ExpiredRecordDeleter is implemented by the expiring stores whose backend doesn't delete expired records by itself,
+// such as the MemoryExpiringStore or a SQL database, so that a Sweeper can delete them
+type ExpiredRecordDeleter interface {
+	// DeleteExpired deletes every expired record and returns how many were deleted
+	DeleteExpired() (int, error)
+}
+
+// MemoryExpiringStore keeps the records in memory, e.g. for local development and tests;
+// expired records are only deleted by a Sweeper, or when they're replaced
+type MemoryExpiringStore struct {
+	mu      sync.Mutex
+	records map[string]model.ExpiringRecord
+	metrics *ExpiryMetrics
+	now     func() time.Time
+}
+
+// NewMemoryExpiringStore creates an empty store; the metrics are optional
+func NewMemoryExpiringStore(metrics *ExpiryMetrics) *MemoryExpiringStore {
+	return &MemoryExpiringStore{
+		records: map[string]model.ExpiringRecord{},
+		metrics: metrics,
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryExpiringStore) Put(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = model.ExpiringRecord{
+		Key:       key,
+		Value:     append([]byte{}, value...),
+		ExpiresAt: expiryTime(s.now(), ttl),
+	}
+	return nil
+}
+
+func (s *MemoryExpiringStore) Get(key string) (*model.ExpiringRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+	if record.Expired(s.now()) {
+		s.metrics.addExpiredOnRead()
+		return nil, nil
+	}
+	record.Value = append([]byte{}, record.Value...)
+	return &record, nil
+}
+
+func (s *MemoryExpiringStore) Increment(key string, ttl time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	record, ok := s.records[key]
+	if !ok || record.Expired(now) {
+		record = model.ExpiringRecord{Key: key, ExpiresAt: expiryTime(now, ttl)}
+	}
+	record.Count++
+	s.records[key] = record
+	return record.Count, nil
+}
+
+func (s *MemoryExpiringStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *MemoryExpiringStore) DeleteExpired() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	deleted := 0
+	for key, record := range s.records {
+		if record.Expired(now) {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Sweeper periodically deletes the expired records of a store that doesn't delete them by itself
+type Sweeper struct {
+	store   ExpiredRecordDeleter
+	metrics *ExpiryMetrics
+}
+
+// NewSweeper creates a sweeper for the store; the metrics are optional
+func NewSweeper(store ExpiredRecordDeleter, metrics *ExpiryMetrics) Sweeper {
+	return Sweeper{
+		store:   store,
+		metrics: metrics,
+	}
+}
+
+// Sweep deletes the store's expired records once and returns how many were deleted
+func (s Sweeper) Sweep() (int, error) {
+	deleted, err := s.store.DeleteExpired()
+	s.metrics.addSwept(deleted)
+	return deleted, err
+}
+
+// Run sweeps the store every interval until the context is done;
+// errors are printed rather than returned, since the next sweep tries again
+func (s Sweeper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := s.Sweep(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to delete the expired records: %v\n", err)
+		}
+	}
+}
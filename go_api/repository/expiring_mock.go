@@ -0,0 +1,108 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package repository
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockExpiringStore is an autogenerated mock type for the ExpiringStore type
+type MockExpiringStore struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: key
+func (_m *MockExpiringStore) Delete(key string) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: key
+func (_m *MockExpiringStore) Get(key string) (*model.ExpiringRecord, error) {
+	ret := _m.Called(key)
+
+	var r0 *model.ExpiringRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.ExpiringRecord, error)); ok {
+		return rf(key)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.ExpiringRecord); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ExpiringRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Increment provides a mock function with given fields: key, ttl
+func (_m *MockExpiringStore) Increment(key string, ttl time.Duration) (int, error) {
+	ret := _m.Called(key, ttl)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Duration) (int, error)); ok {
+		return rf(key, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Duration) int); ok {
+		r0 = rf(key, ttl)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Duration) error); ok {
+		r1 = rf(key, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Put provides a mock function with given fields: key, value, ttl
+func (_m *MockExpiringStore) Put(key string, value []byte, ttl time.Duration) error {
+	ret := _m.Called(key, value, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []byte, time.Duration) error); ok {
+		r0 = rf(key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockExpiringStore creates a new instance of MockExpiringStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockExpiringStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockExpiringStore {
+	mock := &MockExpiringStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
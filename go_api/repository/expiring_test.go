@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/migration"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+	"the-drink-almanac-api/repository/client/ddbtest"
+)
+
+// newTestExpiringStores creates every kind of expiring store, all using the given clock
+func newTestExpiringStores(t *testing.T, now func() time.Time) map[string]func(metrics *ExpiryMetrics) ExpiringStore {
+	newDDBStore := func(tableDesign string) func(metrics *ExpiryMetrics) ExpiringStore {
+		return func(metrics *ExpiryMetrics) ExpiringStore {
+			server := ddbtest.NewServer(t)
+			appConfig := model.AppConfig{
+				TableDesign:              tableDesign,
+				SingleTableName:          "single",
+				UsersTableName:           "users",
+				FavoritesTableName:       "favorites",
+				OutboxTableName:          "outbox",
+				ExpiringRecordsTableName: "expiring",
+				DrinksTableName:          "drinks",
+				InventoryTableName:       "inventory",
+				SchemaVersionsTableName:  "versions",
+			}
+			assert.NoError(t, migration.NewMigrator(server.Client(), appConfig).Run(context.TODO()), "The tables should have been created")
+			store := &ExpiringStoreDDB{
+				DynamodbClient:    server.Client(),
+				TableName:         appConfig.ExpiringRecordsTableName,
+				SingleTableDesign: tableDesign == model.SingleTableDesign,
+				Metrics:           metrics,
+				now:               now,
+			}
+			if store.SingleTableDesign {
+				store.TableName = appConfig.SingleTableName
+			}
+			return store
+		}
+	}
+	return map[string]func(metrics *ExpiryMetrics) ExpiringStore{
+		"Memory": func(metrics *ExpiryMetrics) ExpiringStore {
+			store := NewMemoryExpiringStore(metrics)
+			store.now = now
+			return store
+		},
+		"Multi-table":  newDDBStore(model.MultiTableDesign),
+		"Single-table": newDDBStore(model.SingleTableDesign),
+	}
+}
+
+func TestExpiringStores(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+
+	for name, newStore := range newTestExpiringStores(t, clock) {
+		t.Run(name, func(t *testing.T) {
+			t.Run("Put and Get", func(t *testing.T) {
+				now = start
+				metrics := &ExpiryMetrics{}
+				store := newStore(metrics)
+				assert.NoError(t, store.Put("token#0", []byte("user0"), time.Minute), "No error should have been returned from Put")
+
+				now = start.Add(59 * time.Second)
+				record, err := store.Get("token#0")
+				assert.NoError(t, err, "No error should have been returned from Get")
+				assert.Equal(t, "token#0", record.Key)
+				assert.Equal(t, []byte("user0"), record.Value)
+				assert.True(t, start.Add(time.Minute).Equal(record.ExpiresAt), "The record should expire after the ttl, got %v", record.ExpiresAt)
+
+				now = start.Add(time.Minute)
+				record, err = store.Get("token#0")
+				assert.NoError(t, err, "No error should have been returned from Get")
+				assert.Nil(t, record, "The record should have expired")
+				assert.Equal(t, int64(1), metrics.ExpiredOnRead(), "The expired read should have been counted")
+
+				record, err = store.Get("token#1")
+				assert.NoError(t, err, "No error should have been returned from Get")
+				assert.Nil(t, record, "A missing record should return nil")
+			})
+
+			t.Run("Increment", func(t *testing.T) {
+				now = start
+				store := newStore(nil)
+				for expected := 1; expected <= 3; expected++ {
+					count, err := store.Increment("lockout#user0", time.Minute)
+					assert.NoError(t, err, "No error should have been returned from Increment")
+					assert.Equal(t, expected, count)
+				}
+				record, err := store.Get("lockout#user0")
+				assert.NoError(t, err, "No error should have been returned from Get")
+				assert.Equal(t, 3, record.Count)
+				assert.True(t, start.Add(time.Minute).Equal(record.ExpiresAt), "Incrementing shouldn't extend the expiry, got %v", record.ExpiresAt)
+
+				now = start.Add(time.Minute)
+				count, err := store.Increment("lockout#user0", time.Minute)
+				assert.NoError(t, err, "No error should have been returned from Increment")
+				assert.Equal(t, 1, count, "An expired counter should start again")
+				record, err = store.Get("lockout#user0")
+				assert.NoError(t, err, "No error should have been returned from Get")
+				assert.True(t, start.Add(2*time.Minute).Equal(record.ExpiresAt), "The restarted counter should expire after the ttl, got %v", record.ExpiresAt)
+			})
+
+			t.Run("Delete", func(t *testing.T) {
+				now = start
+				store := newStore(nil)
+				assert.NoError(t, store.Put("token#0", []byte("user0"), time.Minute), "No error should have been returned from Put")
+				assert.NoError(t, store.Delete("token#0"), "No error should have been returned from Delete")
+				record, err := store.Get("token#0")
+				assert.NoError(t, err, "No error should have been returned from Get")
+				assert.Nil(t, record, "The record should have been deleted")
+				assert.NoError(t, store.Delete("token#0"), "Deleting a missing record should do nothing")
+			})
+		})
+	}
+}
+
+func TestExpiringStoreDDB_Increment_Contention(t *testing.T) {
+	mockDdbClient := client.NewMockDDBClient(t)
+	conditionFailed := apperrors.NewConflictError("conflict", &types.ConditionalCheckFailedException{})
+	mockDdbClient.On("UpdateItem", context.TODO(), mock.AnythingOfType("*dynamodb.UpdateItemInput")).Return(nil, conditionFailed).Times(maxIncrementAttempts)
+	mockDdbClient.On("PutItem", context.TODO(), mock.AnythingOfType("*dynamodb.PutItemInput")).Return(nil, conditionFailed).Times(maxIncrementAttempts)
+
+	store := &ExpiringStoreDDB{DynamodbClient: mockDdbClient, TableName: "expiring"}
+	_, err := store.Increment("lockout#user0", time.Minute)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned, got %v", err)
+}
+
+func TestNewExpiringStore(t *testing.T) {
+	store, err := NewExpiringStore(model.AppConfig{ExpiringStore: model.ExpiringStoreMemory}, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &MemoryExpiringStore{}, store)
+	_, sweepable := store.(ExpiredRecordDeleter)
+	assert.True(t, sweepable, "The memory store has to be swept")
+
+	store, err = NewExpiringStore(model.AppConfig{ExpiringStore: model.ExpiringStoreDynamodb, ExpiringRecordsTableName: "expiring"}, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &ExpiringStoreDDB{}, store)
+	_, sweepable = store.(ExpiredRecordDeleter)
+	assert.False(t, sweepable, "Dynamodb's time to live deletes the expired records")
+
+	_, err = NewExpiringStore(model.AppConfig{ExpiringStore: "redis"}, nil)
+	assert.Error(t, err, "An invalid expiring store should have been rejected")
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics := &ExpiryMetrics{}
+	store := NewMemoryExpiringStore(metrics)
+	store.now = func() time.Time { return start }
+	assert.NoError(t, store.Put("token#0", nil, time.Minute))
+	assert.NoError(t, store.Put("token#1", nil, time.Hour))
+	_, err := store.Increment("lockout#user0", time.Minute)
+	assert.NoError(t, err)
+
+	store.now = func() time.Time { return start.Add(time.Minute) }
+	deleted, err := NewSweeper(store, metrics).Sweep()
+	assert.NoError(t, err, "No error should have been returned from Sweep")
+	assert.Equal(t, 2, deleted)
+	assert.Equal(t, int64(2), metrics.Swept())
+	assert.Equal(t, `{"expired_on_read": 0, "swept": 2}`, metrics.String())
+
+	record, err := store.Get("token#1")
+	assert.NoError(t, err)
+	assert.NotNil(t, record, "The unexpired record should have been kept")
+}
+
+func TestExpiryTime(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(time.Minute), expiryTime(now, time.Minute))
+	assert.Equal(t, now.Add(2*time.Second), expiryTime(now, 1500*time.Millisecond), "The expiry should be rounded up to the second")
+}
@@ -3,7 +3,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
+	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/repository/client"
 
@@ -17,12 +20,19 @@ import (
 type FavoriteRepository interface {
 	FindAll() ([]model.Favorite, error)
 	FindFavoritesByUser(userId string) ([]model.Favorite, error)
-	CreateNewFavorite(favorite model.Favorite) error
-	DeleteFavorite(id string) error
+	FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error)
+	FindFavoriteByUserAndDrink(userId, drinkId string) (*model.Favorite, error)
+	FindFavoriteById(id string) (*model.Favorite, error)
+	// CreateNewFavorite inserts the favorite, recording the events in the outbox in the same transaction
+	CreateNewFavorite(favorite model.Favorite, events ...model.Event) error
+	UpdateFavorite(favorite model.Favorite, expectedVersion int) error
+	// DeleteFavorite removes the favorite, recording the events in the outbox in the same transaction;
+	// the events are only recorded if the favorite existed
+	DeleteFavorite(id string, events ...model.Event) error
 }
 
-func NewFavoriteRepository(tableName, awsEndpoint string) (*FavoriteRepositoryDDB, error) {
-	ddbClient, err := client.CreateLocalDDBClient(awsEndpoint)
+func NewFavoriteRepository(tableName string, appConfig model.AppConfig) (*FavoriteRepositoryDDB, error) {
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
 	return &FavoriteRepositoryDDB{
 		DynamodbClient: ddbClient,
 		TableName:      tableName,
@@ -32,19 +42,20 @@ func NewFavoriteRepository(tableName, awsEndpoint string) (*FavoriteRepositoryDD
 type FavoriteRepositoryDDB struct {
 	DynamodbClient client.DDBClient
 	TableName      string
+	// OutboxTableName is the table that events are recorded in
+	OutboxTableName string
 }
 
 func (r *FavoriteRepositoryDDB) FindAll() ([]model.Favorite, error) {
 	scanInput := dynamodb.ScanInput{
 		TableName: aws.String(r.TableName),
 	}
-	ctx := context.TODO()
-	scanOutput, err := r.DynamodbClient.Scan(ctx, &scanInput)
+	items, err := scanPages(r.DynamodbClient, &scanInput)
 	if err != nil {
 		return nil, err
 	}
 	favorites := []model.Favorite{}
-	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &favorites)
+	err = attributevalue.UnmarshalListOfMaps(items, &favorites)
 	if err != nil {
 		return nil, err
 	}
@@ -68,13 +79,12 @@ func (r *FavoriteRepositoryDDB) FindFavoritesByUser(userId string) ([]model.Favo
 		KeyConditionExpression:    filterExpression.KeyCondition(),
 	}
 
-	ctx := context.TODO()
-	queryOutput, err := r.DynamodbClient.Query(ctx, &queryInput)
+	items, err := queryPages(r.DynamodbClient, &queryInput)
 	if err != nil {
 		return nil, err
 	}
 	favorites := []model.Favorite{}
-	err = attributevalue.UnmarshalListOfMaps(queryOutput.Items, &favorites)
+	err = attributevalue.UnmarshalListOfMaps(items, &favorites)
 	if err != nil {
 		return nil, err
 	}
@@ -82,24 +92,188 @@ func (r *FavoriteRepositoryDDB) FindFavoritesByUser(userId string) ([]model.Favo
 	return favorites, nil
 }
 
-func (r *FavoriteRepositoryDDB) CreateNewFavorite(favorite model.Favorite) error {
-	_, err := r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
-		TableName: aws.String(r.TableName),
-		Item: map[string]types.AttributeValue{
-			"id":       &types.AttributeValueMemberS{Value: favorite.Id},
-			"drink_id": &types.AttributeValueMemberS{Value: favorite.DrinkId},
-			"user_id":  &types.AttributeValueMemberS{Value: favorite.UserId},
-		},
-	})
-	return err
+// FindFavoritesByUserSortedByCreation retrieves the user's favorites ordered by their creation time
+// using the user-created-index, which uses created_at as its sort key
+func (r *FavoriteRepositoryDDB) FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error) {
+	filterExpression, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key("user_id").Equal(expression.Value(userId)),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	queryInput := dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		IndexName:                 aws.String("user-created-index"),
+		ExpressionAttributeNames:  filterExpression.Names(),
+		ExpressionAttributeValues: filterExpression.Values(),
+		KeyConditionExpression:    filterExpression.KeyCondition(),
+		ScanIndexForward:          aws.Bool(!newestFirst),
+	}
+
+	items, err := queryPages(r.DynamodbClient, &queryInput)
+	if err != nil {
+		return nil, err
+	}
+	favorites := []model.Favorite{}
+	err = attributevalue.UnmarshalListOfMaps(items, &favorites)
+	if err != nil {
+		return nil, err
+	}
+
+	return favorites, nil
 }
 
-func (r *FavoriteRepositoryDDB) DeleteFavorite(id string) error {
-	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+// FindFavoriteByUserAndDrink checks the repository's favorite table for a favorite with the given userId and drinkId
+// using the user-drink-index, which is keyed on the (user_id, drink_id) pair;
+//
+// Returns:
+//   - an error if there are multiple favorites for that user and drink
+//   - the favorite if there is only 1 favorite
+//   - nil if there are 0 favorites
+func (r *FavoriteRepositoryDDB) FindFavoriteByUserAndDrink(userId, drinkId string) (*model.Favorite, error) {
+	filterExpression, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key("user_id").Equal(expression.Value(userId)).And(
+			expression.Key("drink_id").Equal(expression.Value(drinkId)),
+		),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	queryInput := dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		IndexName:                 aws.String("user-drink-index"),
+		ExpressionAttributeNames:  filterExpression.Names(),
+		ExpressionAttributeValues: filterExpression.Values(),
+		KeyConditionExpression:    filterExpression.KeyCondition(),
+	}
+
+	items, err := queryPages(r.DynamodbClient, &queryInput)
+	if err != nil {
+		return nil, err
+	}
+	favorites := []model.Favorite{}
+	err = attributevalue.UnmarshalListOfMaps(items, &favorites)
+	if err != nil {
+		return nil, err
+	}
+
+	switch num_favorites := len(favorites); num_favorites {
+	case 0:
+		return nil, nil
+	case 1:
+		return &favorites[0], nil
+	default:
+		return nil, fmt.Errorf("there are %s favorites for the user '%s' and drink '%s'", strconv.Itoa(len(favorites)), userId, drinkId)
+	}
+}
+
+// FindFavoriteById retrieves the favorite with the given id from the repository's favorite table,
+// or nil if no favorite exists with that id
+func (r *FavoriteRepositoryDDB) FindFavoriteById(id string) (*model.Favorite, error) {
+	getItemOutput, err := r.DynamodbClient.GetItem(context.TODO(), &dynamodb.GetItemInput{
 		TableName: aws.String(r.TableName),
 		Key: map[string]types.AttributeValue{
 			"id": &types.AttributeValueMemberS{Value: id},
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	if getItemOutput.Item == nil {
+		return nil, nil
+	}
+	favorite := model.Favorite{}
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, &favorite)
+	if err != nil {
+		return nil, err
+	}
+	return &favorite, nil
+}
+
+// CreateNewFavorite inserts the provided favorite into the repository's favorite table
+// as long as no favorite already exists with the same id;
+//
+// Favorite ids are derived from the (user_id, drink_id) pair, so the conditional put
+// atomically rejects duplicates and returns the FavoriteAlreadyExistsError
+func (r *FavoriteRepositoryDDB) CreateNewFavorite(favorite model.Favorite, events ...model.Event) error {
+	put := &types.Put{
+		TableName:           aws.String(r.TableName),
+		Item:                favoriteItem(favorite),
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	}
+	var err error
+	conditionFailed := false
+	if len(events) == 0 {
+		_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+			TableName:           put.TableName,
+			Item:                put.Item,
+			ConditionExpression: put.ConditionExpression,
+		})
+		conditionFailed = isConditionalCheckFailed(err)
+	} else {
+		conditionFailed, err = writeWithEvents(r.DynamodbClient, types.TransactWriteItem{Put: put}, outboxPuts(r.OutboxTableName, events))
+	}
+	if conditionFailed {
+		return apperrors.NewFavoriteAlreadyExistsError("the user already favorited this drink")
+	}
+	return err
+}
+
+// UpdateFavorite replaces the favorite's record as long as the stored version still matches expectedVersion;
+// if another request modified the favorite first, the ConflictError is returned
+//
+// The caller is responsible for incrementing favorite.Version
+func (r *FavoriteRepositoryDDB) UpdateFavorite(favorite model.Favorite, expectedVersion int) error {
+	condition, err := versionCondition(expectedVersion)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(r.TableName),
+		Item:                      favoriteItem(favorite),
+		ConditionExpression:       condition.Condition(),
+		ExpressionAttributeNames:  condition.Names(),
+		ExpressionAttributeValues: condition.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return apperrors.NewConflictError(fmt.Sprintf("the favorite '%s' doesn't exist or was modified by another request", favorite.Id), err)
+	}
 	return err
 }
+
+func (r *FavoriteRepositoryDDB) DeleteFavorite(id string, events ...model.Event) error {
+	key := map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: id},
+	}
+	if len(events) == 0 {
+		_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+			TableName: aws.String(r.TableName),
+			Key:       key,
+		})
+		return err
+	}
+
+	// the delete is conditional so that the events aren't recorded if another request already deleted the favorite
+	conditionFailed, err := writeWithEvents(r.DynamodbClient, types.TransactWriteItem{Delete: &types.Delete{
+		TableName:           aws.String(r.TableName),
+		Key:                 key,
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	}}, outboxPuts(r.OutboxTableName, events))
+	if conditionFailed {
+		return nil
+	}
+	return err
+}
+
+func favoriteItem(favorite model.Favorite) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: favorite.Id},
+		"drink_id":   &types.AttributeValueMemberS{Value: favorite.DrinkId},
+		"user_id":    &types.AttributeValueMemberS{Value: favorite.UserId},
+		"created_at": &types.AttributeValueMemberS{Value: model.FormatTimestamp(favorite.CreatedAt)},
+		"updated_at": &types.AttributeValueMemberS{Value: model.FormatTimestamp(favorite.UpdatedAt)},
+		"version":    &types.AttributeValueMemberN{Value: strconv.Itoa(favorite.Version)},
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"the-drink-almanac-api/model"
+)
+
+// CachedFavoriteRepository is a read-through cache in front of another FavoriteRepository;
+// every cached lookup is scoped to a single user, so writing a favorite invalidates that user's entries
+//
+// Entries are shared between api instances only if the Cache is, so a write handled by another instance
+// may not be visible until the entries expire
+type CachedFavoriteRepository struct {
+	repo  FavoriteRepository
+	cache Cache
+	ttl   time.Duration
+}
+
+func NewCachedFavoriteRepository(repo FavoriteRepository, cache Cache, ttl time.Duration) *CachedFavoriteRepository {
+	return &CachedFavoriteRepository{
+		repo:  repo,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+func (r *CachedFavoriteRepository) FindAll() ([]model.Favorite, error) {
+	return r.repo.FindAll()
+}
+
+func (r *CachedFavoriteRepository) FindFavoritesByUser(userId string) ([]model.Favorite, error) {
+	return r.readThroughList(userFavoritesKey(userId), func() ([]model.Favorite, error) {
+		return r.repo.FindFavoritesByUser(userId)
+	})
+}
+
+func (r *CachedFavoriteRepository) FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error) {
+	return r.readThroughList(sortedUserFavoritesKey(userId, newestFirst), func() ([]model.Favorite, error) {
+		return r.repo.FindFavoritesByUserSortedByCreation(userId, newestFirst)
+	})
+}
+
+func (r *CachedFavoriteRepository) FindFavoriteByUserAndDrink(userId, drinkId string) (*model.Favorite, error) {
+	return readThrough(r.cache, userDrinkFavoriteKey(userId, drinkId), r.ttl, func() (*model.Favorite, error) {
+		return r.repo.FindFavoriteByUserAndDrink(userId, drinkId)
+	})
+}
+
+func (r *CachedFavoriteRepository) FindFavoriteById(id string) (*model.Favorite, error) {
+	return readThrough(r.cache, favoriteIdKey(id), r.ttl, func() (*model.Favorite, error) {
+		return r.repo.FindFavoriteById(id)
+	})
+}
+
+func (r *CachedFavoriteRepository) CreateNewFavorite(favorite model.Favorite, events ...model.Event) error {
+	err := r.repo.CreateNewFavorite(favorite, events...)
+	r.invalidate(favorite)
+	return err
+}
+
+func (r *CachedFavoriteRepository) UpdateFavorite(favorite model.Favorite, expectedVersion int) error {
+	err := r.repo.UpdateFavorite(favorite, expectedVersion)
+	r.invalidate(favorite)
+	return err
+}
+
+// DeleteFavorite looks up the favorite before deleting it
+// so that the cached entries of the user who owned it can be invalidated
+func (r *CachedFavoriteRepository) DeleteFavorite(id string, events ...model.Event) error {
+	favorite, err := r.FindFavoriteById(id)
+	if err != nil {
+		return err
+	}
+	err = r.repo.DeleteFavorite(id, events...)
+	if favorite != nil {
+		r.invalidate(*favorite)
+	}
+	r.cache.Delete(favoriteIdKey(id))
+	return err
+}
+
+func (r *CachedFavoriteRepository) readThroughList(key string, load func() ([]model.Favorite, error)) ([]model.Favorite, error) {
+	favorites, err := readThrough(r.cache, key, r.ttl, func() (*[]model.Favorite, error) {
+		favorites, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return &favorites, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *favorites, nil
+}
+
+func (r *CachedFavoriteRepository) invalidate(favorite model.Favorite) {
+	r.cache.Delete(
+		favoriteIdKey(favorite.Id),
+		userDrinkFavoriteKey(favorite.UserId, favorite.DrinkId),
+		userFavoritesKey(favorite.UserId),
+		sortedUserFavoritesKey(favorite.UserId, true),
+		sortedUserFavoritesKey(favorite.UserId, false),
+	)
+}
+
+func favoriteIdKey(id string) string {
+	return fmt.Sprintf("favorite:id:%s", id)
+}
+
+func userDrinkFavoriteKey(userId, drinkId string) string {
+	return fmt.Sprintf("favorite:user:%s:drink:%s", userId, drinkId)
+}
+
+func userFavoritesKey(userId string) string {
+	return fmt.Sprintf("favorites:user:%s", userId)
+}
+
+func sortedUserFavoritesKey(userId string, newestFirst bool) string {
+	return fmt.Sprintf("favorites:user:%s:created:newest-first=%t", userId, newestFirst)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestCachedFavoriteRepository_FindFavoritesByUser(t *testing.T) {
+	favorites := []model.Favorite{{Id: "0", UserId: "user0", DrinkId: "drink0"}}
+	mockFavoriteRepo := NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindFavoritesByUser", "user0").Return(favorites, nil).Once()
+	mockFavoriteRepo.On("FindFavoritesByUserSortedByCreation", "user0", true).Return(favorites, nil).Once()
+	favoriteStore := NewCachedFavoriteRepository(mockFavoriteRepo, NewLRUCache(10), time.Minute)
+
+	for i := 0; i < 2; i++ {
+		actualFavorites, err := favoriteStore.FindFavoritesByUser("user0")
+		assert.NoError(t, err)
+		assert.Equal(t, favorites, actualFavorites)
+		actualFavorites, err = favoriteStore.FindFavoritesByUserSortedByCreation("user0", true)
+		assert.NoError(t, err)
+		assert.Equal(t, favorites, actualFavorites)
+	}
+}
+
+func TestCachedFavoriteRepository_WritesInvalidateTheUsersFavorites(t *testing.T) {
+	favorite := model.Favorite{Id: "0", UserId: "user0", DrinkId: "drink0"}
+	tests := []struct {
+		name  string
+		write func(favoriteStore *CachedFavoriteRepository, mockFavoriteRepo *MockFavoriteRepository) error
+	}{
+		{
+			name: "Create favorite",
+			write: func(favoriteStore *CachedFavoriteRepository, mockFavoriteRepo *MockFavoriteRepository) error {
+				mockFavoriteRepo.On("CreateNewFavorite", favorite).Return(nil)
+				return favoriteStore.CreateNewFavorite(favorite)
+			},
+		},
+		{
+			name: "Delete favorite",
+			write: func(favoriteStore *CachedFavoriteRepository, mockFavoriteRepo *MockFavoriteRepository) error {
+				mockFavoriteRepo.On("FindFavoriteById", "0").Return(&favorite, nil)
+				mockFavoriteRepo.On("DeleteFavorite", "0").Return(nil)
+				return favoriteStore.DeleteFavorite("0")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFavoriteRepo := NewMockFavoriteRepository(t)
+			mockFavoriteRepo.On("FindFavoritesByUser", "user0").Return([]model.Favorite{}, nil)
+			mockFavoriteRepo.On("FindFavoriteByUserAndDrink", "user0", "drink0").Return(&favorite, nil)
+			favoriteStore := NewCachedFavoriteRepository(mockFavoriteRepo, NewLRUCache(10), time.Minute)
+			_, err := favoriteStore.FindFavoritesByUser("user0")
+			assert.NoError(t, err)
+			_, err = favoriteStore.FindFavoriteByUserAndDrink("user0", "drink0")
+			assert.NoError(t, err)
+
+			assert.NoError(t, tt.write(favoriteStore, mockFavoriteRepo))
+
+			_, err = favoriteStore.FindFavoritesByUser("user0")
+			assert.NoError(t, err)
+			_, err = favoriteStore.FindFavoriteByUserAndDrink("user0", "drink0")
+			assert.NoError(t, err)
+			mockFavoriteRepo.AssertNumberOfCalls(t, "FindFavoritesByUser", 2)
+			mockFavoriteRepo.AssertNumberOfCalls(t, "FindFavoriteByUserAndDrink", 2)
+		})
+	}
+}
@@ -13,13 +13,20 @@ type MockFavoriteRepository struct {
 	mock.Mock
 }
 
-// CreateNewFavorite provides a mock function with given fields: favorite
-func (_m *MockFavoriteRepository) CreateNewFavorite(favorite model.Favorite) error {
-	ret := _m.Called(favorite)
+// CreateNewFavorite provides a mock function with given fields: favorite, events
+func (_m *MockFavoriteRepository) CreateNewFavorite(favorite model.Favorite, events ...model.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, favorite)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.Favorite) error); ok {
-		r0 = rf(favorite)
+	if rf, ok := ret.Get(0).(func(model.Favorite, ...model.Event) error); ok {
+		r0 = rf(favorite, events...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,13 +34,20 @@ func (_m *MockFavoriteRepository) CreateNewFavorite(favorite model.Favorite) err
 	return r0
 }
 
-// DeleteFavorite provides a mock function with given fields: id
-func (_m *MockFavoriteRepository) DeleteFavorite(id string) error {
-	ret := _m.Called(id)
+// DeleteFavorite provides a mock function with given fields: id, events
+func (_m *MockFavoriteRepository) DeleteFavorite(id string, events ...model.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(string, ...model.Event) error); ok {
+		r0 = rf(id, events...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -67,6 +81,58 @@ func (_m *MockFavoriteRepository) FindAll() ([]model.Favorite, error) {
 	return r0, r1
 }
 
+// FindFavoriteById provides a mock function with given fields: id
+func (_m *MockFavoriteRepository) FindFavoriteById(id string) (*model.Favorite, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Favorite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Favorite, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Favorite); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Favorite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindFavoriteByUserAndDrink provides a mock function with given fields: userId, drinkId
+func (_m *MockFavoriteRepository) FindFavoriteByUserAndDrink(userId string, drinkId string) (*model.Favorite, error) {
+	ret := _m.Called(userId, drinkId)
+
+	var r0 *model.Favorite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.Favorite, error)); ok {
+		return rf(userId, drinkId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.Favorite); ok {
+		r0 = rf(userId, drinkId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Favorite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, drinkId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindFavoritesByUser provides a mock function with given fields: userId
 func (_m *MockFavoriteRepository) FindFavoritesByUser(userId string) ([]model.Favorite, error) {
 	ret := _m.Called(userId)
@@ -93,6 +159,46 @@ func (_m *MockFavoriteRepository) FindFavoritesByUser(userId string) ([]model.Fa
 	return r0, r1
 }
 
+// FindFavoritesByUserSortedByCreation provides a mock function with given fields: userId, newestFirst
+func (_m *MockFavoriteRepository) FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error) {
+	ret := _m.Called(userId, newestFirst)
+
+	var r0 []model.Favorite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, bool) ([]model.Favorite, error)); ok {
+		return rf(userId, newestFirst)
+	}
+	if rf, ok := ret.Get(0).(func(string, bool) []model.Favorite); ok {
+		r0 = rf(userId, newestFirst)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Favorite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(userId, newestFirst)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFavorite provides a mock function with given fields: favorite, expectedVersion
+func (_m *MockFavoriteRepository) UpdateFavorite(favorite model.Favorite, expectedVersion int) error {
+	ret := _m.Called(favorite, expectedVersion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Favorite, int) error); ok {
+		r0 = rf(favorite, expectedVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockFavoriteRepository creates a new instance of MockFavoriteRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockFavoriteRepository(t interface {
@@ -2,16 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/repository/client"
 )
@@ -126,26 +129,224 @@ func TestFavoriteStoreDDB_FindFavoritesByUser(t *testing.T) {
 	}
 }
 
+func TestFavoriteStoreDDB_FindFavoritesByUserSortedByCreation(t *testing.T) {
+	numFavorites := 3
+	favoriteItems := make([]map[string]types.AttributeValue, numFavorites)
+	mockFavorites := make([]model.Favorite, numFavorites)
+	for i := 0; i < numFavorites; i++ {
+		iStr := strconv.Itoa(i)
+		createdAt := time.Date(2023, 1, i+1, 0, 0, 0, 0, time.UTC)
+		favoriteItems[i] = map[string]types.AttributeValue{
+			"id":         &types.AttributeValueMemberS{Value: iStr},
+			"user_id":    &types.AttributeValueMemberS{Value: "0"},
+			"drink_id":   &types.AttributeValueMemberS{Value: iStr},
+			"created_at": &types.AttributeValueMemberS{Value: model.FormatTimestamp(createdAt)},
+			"updated_at": &types.AttributeValueMemberS{Value: model.FormatTimestamp(createdAt)},
+		}
+		mockFavorites[i] = model.Favorite{
+			Id:        iStr,
+			UserId:    "0",
+			DrinkId:   iStr,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+	}
+	tests := []struct {
+		name              string
+		userId            string
+		newestFirst       bool
+		expectedFavorites []model.Favorite
+		queryOutput       *dynamodb.QueryOutput
+		returnedError     error
+		expectError       bool
+	}{
+		{
+			name:              "Successfully retrieve favorites oldest first",
+			userId:            "0",
+			newestFirst:       false,
+			expectedFavorites: mockFavorites,
+			queryOutput:       &dynamodb.QueryOutput{Items: favoriteItems},
+			returnedError:     nil,
+			expectError:       false,
+		},
+		{
+			name:              "Successfully retrieve favorites newest first",
+			userId:            "0",
+			newestFirst:       true,
+			expectedFavorites: mockFavorites,
+			queryOutput:       &dynamodb.QueryOutput{Items: favoriteItems},
+			returnedError:     nil,
+			expectError:       false,
+		},
+		{
+			name:              "Failed to retrieve favorites",
+			userId:            "0",
+			expectedFavorites: nil,
+			returnedError:     fmt.Errorf("failed to retrieve favorites"),
+			expectError:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("Query", context.TODO(), mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+				return *input.IndexName == "user-created-index" && *input.ScanIndexForward == !tt.newestFirst
+			})).Return(tt.queryOutput, tt.returnedError)
+			favoriteStore := FavoriteRepositoryDDB{DynamodbClient: mockDdbClient}
+			actualFavorites, err := favoriteStore.FindFavoritesByUserSortedByCreation(tt.userId, tt.newestFirst)
+			assert.Equal(t, tt.expectError, err != nil, "FavoriteRepository.FindFavoritesByUserSortedByCreation() error = %v", err)
+			assert.Equal(t, tt.expectedFavorites, actualFavorites, "FavoriteRepository.FindFavoritesByUserSortedByCreation() = %v, want %v", actualFavorites, tt.expectedFavorites)
+		})
+	}
+}
+
+func TestFavoriteStoreDDB_FindFavoriteByUserAndDrink(t *testing.T) {
+	numFavorites := 2
+	favoriteItems := make([]map[string]types.AttributeValue, numFavorites)
+	for i := 0; i < numFavorites; i++ {
+		favoriteItems[i] = map[string]types.AttributeValue{
+			"id":       &types.AttributeValueMemberS{Value: strconv.Itoa(i)},
+			"user_id":  &types.AttributeValueMemberS{Value: "0"},
+			"drink_id": &types.AttributeValueMemberS{Value: "0"},
+		}
+	}
+	tests := []struct {
+		name             string
+		userId           string
+		drinkId          string
+		expectedFavorite *model.Favorite
+		queryOutput      *dynamodb.QueryOutput
+		returnedError    error
+		expectError      bool
+	}{
+		{
+			name:    "Successfully retrieve favorite",
+			userId:  "0",
+			drinkId: "0",
+			expectedFavorite: &model.Favorite{
+				Id:      "0",
+				UserId:  "0",
+				DrinkId: "0",
+			},
+			queryOutput:   &dynamodb.QueryOutput{Items: favoriteItems[:1]},
+			returnedError: nil,
+			expectError:   false,
+		},
+		{
+			name:             "Failed to retrieve favorite",
+			userId:           "0",
+			drinkId:          "0",
+			expectedFavorite: nil,
+			returnedError:    fmt.Errorf("failed to retrieve favorite"),
+			expectError:      true,
+		},
+		{
+			name:             "No existing favorite",
+			userId:           "0",
+			drinkId:          "0",
+			expectedFavorite: nil,
+			queryOutput:      &dynamodb.QueryOutput{Items: nil},
+			returnedError:    nil,
+			expectError:      false,
+		},
+		{
+			name:             "Too many favorites",
+			userId:           "0",
+			drinkId:          "0",
+			expectedFavorite: nil,
+			queryOutput:      &dynamodb.QueryOutput{Items: favoriteItems},
+			returnedError:    nil,
+			expectError:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("Query", context.TODO(), mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+				return *input.IndexName == "user-drink-index"
+			})).Return(tt.queryOutput, tt.returnedError)
+			favoriteStore := FavoriteRepositoryDDB{DynamodbClient: mockDdbClient}
+			actualFavorite, err := favoriteStore.FindFavoriteByUserAndDrink(tt.userId, tt.drinkId)
+			assert.Equal(t, tt.expectError, err != nil, "FavoriteRepository.FindFavoriteByUserAndDrink() error = %v", err)
+			assert.Equal(t, tt.expectedFavorite, actualFavorite, "FavoriteRepository.FindFavoriteByUserAndDrink() = %v, want %v", actualFavorite, tt.expectedFavorite)
+		})
+	}
+}
+
+func TestFavoriteStoreDDB_FindFavoriteById(t *testing.T) {
+	favoriteItem := map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "0"},
+		"user_id":  &types.AttributeValueMemberS{Value: "1"},
+		"drink_id": &types.AttributeValueMemberS{Value: "2"},
+	}
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(""),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: "0"},
+		},
+	}
+	tests := []struct {
+		name             string
+		expectedFavorite *model.Favorite
+		getItemOutput    *dynamodb.GetItemOutput
+		returnedError    error
+		expectError      bool
+	}{
+		{
+			name:             "Successfully retrieve favorite",
+			expectedFavorite: &model.Favorite{Id: "0", UserId: "1", DrinkId: "2"},
+			getItemOutput:    &dynamodb.GetItemOutput{Item: favoriteItem},
+		},
+		{
+			name:          "Failed to retrieve favorite",
+			returnedError: fmt.Errorf("failed to retrieve favorite"),
+			expectError:   true,
+		},
+		{
+			name:          "No existing favorite",
+			getItemOutput: &dynamodb.GetItemOutput{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("GetItem", context.TODO(), getItemInput).Return(tt.getItemOutput, tt.returnedError)
+			favoriteStore := FavoriteRepositoryDDB{DynamodbClient: mockDdbClient}
+			actualFavorite, err := favoriteStore.FindFavoriteById("0")
+			assert.Equal(t, tt.expectError, err != nil, "FavoriteRepository.FindFavoriteById() error = %v", err)
+			assert.Equal(t, tt.expectedFavorite, actualFavorite)
+		})
+	}
+}
+
 func TestFavoriteStoreDDB_CreateNewFavorite(t *testing.T) {
 	mockFavorite := model.Favorite{
-		Id:      "0",
-		DrinkId: "0",
-		UserId:  "0",
+		Id:        "0",
+		DrinkId:   "0",
+		UserId:    "0",
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   1,
 	}
 	putItemInput := &dynamodb.PutItemInput{
 		TableName: aws.String(""),
 		Item: map[string]types.AttributeValue{
-			"id":       &types.AttributeValueMemberS{Value: mockFavorite.Id},
-			"user_id":  &types.AttributeValueMemberS{Value: mockFavorite.DrinkId},
-			"drink_id": &types.AttributeValueMemberS{Value: mockFavorite.UserId},
+			"id":         &types.AttributeValueMemberS{Value: mockFavorite.Id},
+			"user_id":    &types.AttributeValueMemberS{Value: mockFavorite.DrinkId},
+			"drink_id":   &types.AttributeValueMemberS{Value: mockFavorite.UserId},
+			"created_at": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00.000Z"},
+			"updated_at": &types.AttributeValueMemberS{Value: "2023-01-02T00:00:00.000Z"},
+			"version":    &types.AttributeValueMemberN{Value: "1"},
 		},
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
 	}
 	putItemOutput := &dynamodb.PutItemOutput{}
 	tests := []struct {
-		name             string
-		expectedFavorite model.Favorite
-		returnedError    error
-		expectError      bool
+		name                     string
+		expectedFavorite         model.Favorite
+		returnedError            error
+		expectError              bool
+		expectAlreadyExistsError bool
 	}{
 		{
 			name:             "Successfully created a favorite",
@@ -159,6 +360,20 @@ func TestFavoriteStoreDDB_CreateNewFavorite(t *testing.T) {
 			returnedError:    fmt.Errorf("failed to create the favorite"),
 			expectError:      true,
 		},
+		{
+			name:                     "Favorite already exists",
+			expectedFavorite:         mockFavorite,
+			returnedError:            &types.ConditionalCheckFailedException{},
+			expectError:              true,
+			expectAlreadyExistsError: true,
+		},
+		{
+			name:                     "Favorite already exists and the client translated the error",
+			expectedFavorite:         mockFavorite,
+			returnedError:            apperrors.NewConflictError("conflict", &types.ConditionalCheckFailedException{}),
+			expectError:              true,
+			expectAlreadyExistsError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -170,6 +385,52 @@ func TestFavoriteStoreDDB_CreateNewFavorite(t *testing.T) {
 				t.Errorf("FavoriteRepository.CreateNewFavorite() error = %v", err)
 				return
 			}
+			assert.Equal(t, tt.expectAlreadyExistsError, errors.As(err, &apperrors.FavoriteAlreadyExistsError{}))
+		})
+	}
+}
+
+func TestFavoriteStoreDDB_UpdateFavorite(t *testing.T) {
+	mockFavorite := model.Favorite{
+		Id:        "0",
+		DrinkId:   "0",
+		UserId:    "0",
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   3,
+	}
+	tests := []struct {
+		name                string
+		returnedError       error
+		expectError         bool
+		expectConflictError bool
+	}{
+		{
+			name: "Successfully updated a favorite",
+		},
+		{
+			name:          "Failed to update a favorite",
+			returnedError: fmt.Errorf("failed to update the favorite"),
+			expectError:   true,
+		},
+		{
+			name:                "Favorite was modified by another request",
+			returnedError:       &types.ConditionalCheckFailedException{},
+			expectError:         true,
+			expectConflictError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("PutItem", context.TODO(), mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+				return input.Item["version"].(*types.AttributeValueMemberN).Value == "3" &&
+					input.ExpressionAttributeValues[":0"].(*types.AttributeValueMemberN).Value == "2"
+			})).Return(&dynamodb.PutItemOutput{}, tt.returnedError)
+			favoriteStore := FavoriteRepositoryDDB{DynamodbClient: mockDdbClient}
+			err := favoriteStore.UpdateFavorite(mockFavorite, 2)
+			assert.Equal(t, tt.expectError, err != nil, "FavoriteRepository.UpdateFavorite() error = %v", err)
+			assert.Equal(t, tt.expectConflictError, errors.As(err, &apperrors.ConflictError{}))
 		})
 	}
 }
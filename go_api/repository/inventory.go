@@ -0,0 +1,152 @@
+//go:generate mockery --name=InventoryRepository --output=./ --outpkg=repository --filename=inventory_mock.go --inpackage
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type InventoryRepository interface {
+	// FindInventoryByUser returns the user's items ordered by ingredient
+	FindInventoryByUser(userId string) ([]model.InventoryItem, error)
+	// FindInventoryItem returns the user's item for the ingredient, or nil if the user doesn't have it
+	FindInventoryItem(userId, ingredient string) (*model.InventoryItem, error)
+	// CreateInventoryItem inserts the item unless the user already has the ingredient,
+	// in which case the InventoryItemAlreadyExistsError is returned
+	CreateInventoryItem(item model.InventoryItem) error
+	// UpdateInventoryItem replaces the item as long as the stored version still matches expectedVersion;
+	// if the item doesn't exist or another request modified it first, the ConflictError is returned
+	UpdateInventoryItem(item model.InventoryItem, expectedVersion int) error
+	// DeleteInventoryItem removes the item; deleting an item that doesn't exist does nothing
+	DeleteInventoryItem(userId, ingredient string) error
+	// DeleteInventoryByUser removes every item of the user's inventory
+	DeleteInventoryByUser(userId string) error
+}
+
+// NewInventoryRepository creates the inventory repository for the table design selected by the app config
+func NewInventoryRepository(appConfig model.AppConfig) (InventoryRepository, error) {
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
+	if appConfig.TableDesign == model.SingleTableDesign {
+		return &SingleTableInventoryRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName}, err
+	}
+	return &InventoryRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.InventoryTableName}, err
+}
+
+// InventoryRepositoryDDB stores the items keyed on user_id and ingredient, so a user's inventory is a single Query
+type InventoryRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *InventoryRepositoryDDB) FindInventoryByUser(userId string) ([]model.InventoryItem, error) {
+	keyCondition, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key("user_id").Equal(expression.Value(userId)),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	items, err := queryPages(r.DynamodbClient, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		KeyConditionExpression:    keyCondition.KeyCondition(),
+		ExpressionAttributeNames:  keyCondition.Names(),
+		ExpressionAttributeValues: keyCondition.Values(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := []model.InventoryItem{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &inventory); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+func (r *InventoryRepositoryDDB) FindInventoryItem(userId, ingredient string) (*model.InventoryItem, error) {
+	item := model.InventoryItem{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, inventoryTableKey(userId, ingredient), &item)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *InventoryRepositoryDDB) CreateInventoryItem(item model.InventoryItem) error {
+	record, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:           aws.String(r.TableName),
+		Item:                record,
+		ConditionExpression: aws.String("attribute_not_exists(user_id)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return apperrors.NewInventoryItemAlreadyExistsError(item.Name)
+	}
+	return err
+}
+
+func (r *InventoryRepositoryDDB) UpdateInventoryItem(item model.InventoryItem, expectedVersion int) error {
+	record, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return err
+	}
+	return putInventoryItem(r.DynamodbClient, r.TableName, record, "user_id", item, expectedVersion)
+}
+
+func (r *InventoryRepositoryDDB) DeleteInventoryItem(userId, ingredient string) error {
+	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       inventoryTableKey(userId, ingredient),
+	})
+	return err
+}
+
+func (r *InventoryRepositoryDDB) DeleteInventoryByUser(userId string) error {
+	inventory, err := r.FindInventoryByUser(userId)
+	if err != nil {
+		return err
+	}
+	for _, item := range inventory {
+		if err := r.DeleteInventoryItem(userId, item.Ingredient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func inventoryTableKey(userId, ingredient string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"user_id":    &types.AttributeValueMemberS{Value: userId},
+		"ingredient": &types.AttributeValueMemberS{Value: ingredient},
+	}
+}
+
+// putInventoryItem replaces the item if its version still matches; keyAttribute is the table's hash key, which only exists on stored items
+func putInventoryItem(db client.DDBClient, tableName string, record map[string]types.AttributeValue, keyAttribute string, item model.InventoryItem, expectedVersion int) error {
+	condition, err := versionConditionOn(keyAttribute, expectedVersion)
+	if err != nil {
+		return err
+	}
+	_, err = db.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(tableName),
+		Item:                      record,
+		ConditionExpression:       condition.Condition(),
+		ExpressionAttributeNames:  condition.Names(),
+		ExpressionAttributeValues: condition.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return apperrors.NewConflictError(fmt.Sprintf("the inventory item '%s' doesn't exist or was modified by another request", item.Name), err)
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+)
+
+// MemoryInventoryRepository keeps the inventories in memory, e.g. for local development and tests
+type MemoryInventoryRepository struct {
+	mu    sync.RWMutex
+	items map[string]map[string]model.InventoryItem
+}
+
+func NewMemoryInventoryRepository() *MemoryInventoryRepository {
+	return &MemoryInventoryRepository{items: map[string]map[string]model.InventoryItem{}}
+}
+
+// FindInventoryByUser returns the user's items ordered by ingredient
+func (r *MemoryInventoryRepository) FindInventoryByUser(userId string) ([]model.InventoryItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	inventory := []model.InventoryItem{}
+	for _, item := range r.items[userId] {
+		inventory = append(inventory, copyInventoryItem(item))
+	}
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Ingredient < inventory[j].Ingredient })
+	return inventory, nil
+}
+
+func (r *MemoryInventoryRepository) FindInventoryItem(userId, ingredient string) (*model.InventoryItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	item, ok := r.items[userId][ingredient]
+	if !ok {
+		return nil, nil
+	}
+	item = copyInventoryItem(item)
+	return &item, nil
+}
+
+func (r *MemoryInventoryRepository) CreateInventoryItem(item model.InventoryItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[item.UserId][item.Ingredient]; ok {
+		return apperrors.NewInventoryItemAlreadyExistsError(item.Name)
+	}
+	if r.items[item.UserId] == nil {
+		r.items[item.UserId] = map[string]model.InventoryItem{}
+	}
+	r.items[item.UserId][item.Ingredient] = copyInventoryItem(item)
+	return nil
+}
+
+func (r *MemoryInventoryRepository) UpdateInventoryItem(item model.InventoryItem, expectedVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.items[item.UserId][item.Ingredient]
+	if !ok || existing.Version != expectedVersion {
+		return apperrors.NewConflictError(fmt.Sprintf("the inventory item '%s' doesn't exist or was modified by another request", item.Name), nil)
+	}
+	r.items[item.UserId][item.Ingredient] = copyInventoryItem(item)
+	return nil
+}
+
+func (r *MemoryInventoryRepository) DeleteInventoryItem(userId, ingredient string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items[userId], ingredient)
+	return nil
+}
+
+func (r *MemoryInventoryRepository) DeleteInventoryByUser(userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, userId)
+	return nil
+}
+
+// copyInventoryItem copies the expiry date so that callers can't modify the stored item
+func copyInventoryItem(item model.InventoryItem) model.InventoryItem {
+	if item.ExpiresOn != nil {
+		expiresOn := *item.ExpiresOn
+		item.ExpiresOn = &expiresOn
+	}
+	return item
+}
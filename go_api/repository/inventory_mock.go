@@ -0,0 +1,136 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package repository
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockInventoryRepository is an autogenerated mock type for the InventoryRepository type
+type MockInventoryRepository struct {
+	mock.Mock
+}
+
+// CreateInventoryItem provides a mock function with given fields: item
+func (_m *MockInventoryRepository) CreateInventoryItem(item model.InventoryItem) error {
+	ret := _m.Called(item)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.InventoryItem) error); ok {
+		r0 = rf(item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteInventoryByUser provides a mock function with given fields: userId
+func (_m *MockInventoryRepository) DeleteInventoryByUser(userId string) error {
+	ret := _m.Called(userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteInventoryItem provides a mock function with given fields: userId, ingredient
+func (_m *MockInventoryRepository) DeleteInventoryItem(userId string, ingredient string) error {
+	ret := _m.Called(userId, ingredient)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, ingredient)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindInventoryByUser provides a mock function with given fields: userId
+func (_m *MockInventoryRepository) FindInventoryByUser(userId string) ([]model.InventoryItem, error) {
+	ret := _m.Called(userId)
+
+	var r0 []model.InventoryItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]model.InventoryItem, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.InventoryItem); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.InventoryItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindInventoryItem provides a mock function with given fields: userId, ingredient
+func (_m *MockInventoryRepository) FindInventoryItem(userId string, ingredient string) (*model.InventoryItem, error) {
+	ret := _m.Called(userId, ingredient)
+
+	var r0 *model.InventoryItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.InventoryItem, error)); ok {
+		return rf(userId, ingredient)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.InventoryItem); ok {
+		r0 = rf(userId, ingredient)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.InventoryItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, ingredient)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateInventoryItem provides a mock function with given fields: item, expectedVersion
+func (_m *MockInventoryRepository) UpdateInventoryItem(item model.InventoryItem, expectedVersion int) error {
+	ret := _m.Called(item, expectedVersion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.InventoryItem, int) error); ok {
+		r0 = rf(item, expectedVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockInventoryRepository creates a new instance of MockInventoryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInventoryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInventoryRepository {
+	mock := &MockInventoryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
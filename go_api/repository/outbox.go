@@ -0,0 +1,224 @@
+//go:generate mockery --name=OutboxRepository --output=./ --outpkg=repository --filename=outbox_mock.go --inpackage
+package repository
+
+import (
+	"context"
+	"time"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// OutboxRepository reads the domain events that the user and favorite repositories recorded alongside their writes,
+// so that they can be published after the writes have been committed
+type OutboxRepository interface {
+	// FindPendingEvents returns up to limit events that haven't been published yet, oldest first
+	FindPendingEvents(limit int) ([]model.Event, error)
+	// ClaimEvent leases a pending event to one relay until now+lease, so that concurrent relays don't publish it at once;
+	// it returns false if another relay holds an unexpired claim or the event was already deleted
+	ClaimEvent(event model.Event, now time.Time, lease time.Duration) (bool, error)
+	// DeleteEvent removes an event from the outbox once it has been published
+	DeleteEvent(event model.Event) error
+}
+
+// NewOutboxRepository creates the outbox repository for the table design selected by the app config
+func NewOutboxRepository(appConfig model.AppConfig) (OutboxRepository, error) {
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
+	if appConfig.TableDesign == model.SingleTableDesign {
+		return &SingleTableOutboxRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName}, err
+	}
+	return &OutboxRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.OutboxTableName}, err
+}
+
+// OutboxRepositoryDDB stores the outbox of the multi-table design in its own table, keyed on the event id;
+// every event is also in the pending-index, which sorts them by the time they occurred
+type OutboxRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+const (
+	// outboxPendingIndex is the outbox table's index of the pending events, oldest first
+	outboxPendingIndex = "pending-index"
+	// outboxStatusAttribute is the pending-index's hash key, which is outboxPendingStatus on every event
+	outboxStatusAttribute = "status"
+	outboxPendingStatus   = "pending"
+	// outboxClaimAttribute is the time in unix milliseconds until which a relay has claimed the event
+	outboxClaimAttribute = "claimed_until"
+)
+
+func (r *OutboxRepositoryDDB) FindPendingEvents(limit int) ([]model.Event, error) {
+	keyExpression, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key(outboxStatusAttribute).Equal(expression.Value(outboxPendingStatus)),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	queryOutput, err := r.DynamodbClient.Query(context.TODO(), &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		IndexName:                 aws.String(outboxPendingIndex),
+		ExpressionAttributeNames:  keyExpression.Names(),
+		ExpressionAttributeValues: keyExpression.Values(),
+		KeyConditionExpression:    keyExpression.KeyCondition(),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := []model.Event{}
+	if err := attributevalue.UnmarshalListOfMaps(queryOutput.Items, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *OutboxRepositoryDDB) ClaimEvent(event model.Event, now time.Time, lease time.Duration) (bool, error) {
+	return claimEvent(r.DynamodbClient, r.TableName, map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: event.Id},
+	}, "id", now, lease)
+}
+
+func (r *OutboxRepositoryDDB) DeleteEvent(event model.Event) error {
+	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: event.Id},
+		},
+	})
+	return err
+}
+
+// SingleTableOutboxRepositoryDDB stores the outbox of the single-table design in the OUTBOX partition,
+// sorted by the time each event occurred
+type SingleTableOutboxRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *SingleTableOutboxRepositoryDDB) FindPendingEvents(limit int) ([]model.Event, error) {
+	keyExpression, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key(singleTableHashKey).Equal(expression.Value(outboxPartitionKey)),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	queryOutput, err := r.DynamodbClient.Query(context.TODO(), &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		ExpressionAttributeNames:  keyExpression.Names(),
+		ExpressionAttributeValues: keyExpression.Values(),
+		KeyConditionExpression:    keyExpression.KeyCondition(),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := []model.Event{}
+	if err := attributevalue.UnmarshalListOfMaps(queryOutput.Items, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *SingleTableOutboxRepositoryDDB) ClaimEvent(event model.Event, now time.Time, lease time.Duration) (bool, error) {
+	return claimEvent(r.DynamodbClient, r.TableName, singleTableKey(outboxPartitionKey, outboxSortKey(event)), singleTableHashKey, now, lease)
+}
+
+func (r *SingleTableOutboxRepositoryDDB) DeleteEvent(event model.Event) error {
+	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       singleTableKey(outboxPartitionKey, outboxSortKey(event)),
+	})
+	return err
+}
+
+// claimEvent sets the event's claim if it still exists, identified by its keyAttribute, and isn't claimed until after now
+func claimEvent(db client.DDBClient, tableName string, key map[string]types.AttributeValue, keyAttribute string, now time.Time, lease time.Duration) (bool, error) {
+	update, err := expression.NewBuilder().
+		WithUpdate(expression.Set(expression.Name(outboxClaimAttribute), expression.Value(now.Add(lease).UnixMilli()))).
+		WithCondition(expression.AttributeExists(expression.Name(keyAttribute)).And(expression.Or(
+			expression.AttributeNotExists(expression.Name(outboxClaimAttribute)),
+			expression.Name(outboxClaimAttribute).LessThanEqual(expression.Value(now.UnixMilli())),
+		))).
+		Build()
+	if err != nil {
+		return false, err
+	}
+	_, err = db.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       key,
+		UpdateExpression:          update.Update(),
+		ConditionExpression:       update.Condition(),
+		ExpressionAttributeNames:  update.Names(),
+		ExpressionAttributeValues: update.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// outboxPuts builds the transaction items that record the events in the multi-table design's outbox table
+func outboxPuts(tableName string, events []model.Event) []types.TransactWriteItem {
+	transactItems := make([]types.TransactWriteItem, len(events))
+	for i, event := range events {
+		transactItems[i] = types.TransactWriteItem{Put: &types.Put{
+			TableName: aws.String(tableName),
+			Item:      withAttributes(eventItem(event), map[string]string{outboxStatusAttribute: outboxPendingStatus}),
+		}}
+	}
+	return transactItems
+}
+
+// singleTableOutboxPuts builds the transaction items that record the events in the single table's OUTBOX partition
+func singleTableOutboxPuts(tableName string, events []model.Event) []types.TransactWriteItem {
+	transactItems := make([]types.TransactWriteItem, len(events))
+	for i, event := range events {
+		transactItems[i] = types.TransactWriteItem{Put: &types.Put{
+			TableName: aws.String(tableName),
+			Item: withAttributes(eventItem(event), map[string]string{
+				singleTableHashKey:  outboxPartitionKey,
+				singleTableRangeKey: outboxSortKey(event),
+				recordTypeAttribute: outboxRecordType,
+			}),
+		}}
+	}
+	return transactItems
+}
+
+func outboxSortKey(event model.Event) string {
+	return model.FormatTimestamp(event.OccurredAt) + "#" + event.Id
+}
+
+func eventItem(event model.Event) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{}
+	for name, value := range map[string]string{
+		"id":          event.Id,
+		"event_type":  string(event.Type),
+		"occurred_at": model.FormatTimestamp(event.OccurredAt),
+		"user_id":     event.UserId,
+		"username":    event.Username,
+		"favorite_id": event.FavoriteId,
+		"drink_id":    event.DrinkId,
+	} {
+		if value != "" {
+			item[name] = &types.AttributeValueMemberS{Value: value}
+		}
+	}
+	return item
+}
+
+// writeWithEvents applies the write and records the events in a single transaction,
+// reporting whether the write was canceled because its own condition failed
+func writeWithEvents(db client.DDBClient, write types.TransactWriteItem, outbox []types.TransactWriteItem) (bool, error) {
+	_, err := db.TransactWriteItems(context.TODO(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{write}, outbox...),
+	})
+	failed := canceledConditions(err)
+	return len(failed) > 0 && failed[0], err
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package repository
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockOutboxRepository is an autogenerated mock type for the OutboxRepository type
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+// ClaimEvent provides a mock function with given fields: event, now, lease
+func (_m *MockOutboxRepository) ClaimEvent(event model.Event, now time.Time, lease time.Duration) (bool, error) {
+	ret := _m.Called(event, now, lease)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.Event, time.Time, time.Duration) (bool, error)); ok {
+		return rf(event, now, lease)
+	}
+	if rf, ok := ret.Get(0).(func(model.Event, time.Time, time.Duration) bool); ok {
+		r0 = rf(event, now, lease)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(model.Event, time.Time, time.Duration) error); ok {
+		r1 = rf(event, now, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEvent provides a mock function with given fields: event
+func (_m *MockOutboxRepository) DeleteEvent(event model.Event) error {
+	ret := _m.Called(event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Event) error); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindPendingEvents provides a mock function with given fields: limit
+func (_m *MockOutboxRepository) FindPendingEvents(limit int) ([]model.Event, error) {
+	ret := _m.Called(limit)
+
+	var r0 []model.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]model.Event, error)); ok {
+		return rf(limit)
+	}
+	if rf, ok := ret.Get(0).(func(int) []model.Event); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockOutboxRepository creates a new instance of MockOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOutboxRepository {
+	mock := &MockOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
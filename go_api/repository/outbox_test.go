@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+)
+
+var outboxEvent = model.Event{
+	Id:         "event0",
+	Type:       model.UserCreated,
+	OccurredAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	UserId:     "0",
+	Username:   "username0",
+}
+
+func TestOutboxRepositoryDDB_FindPendingEvents(t *testing.T) {
+	newer := outboxEvent
+	newer.Id = "event1"
+	newer.OccurredAt = outboxEvent.OccurredAt.Add(time.Minute)
+
+	tests := []struct {
+		name           string
+		returnedError  error
+		expectedEvents []model.Event
+		expectError    bool
+	}{
+		{
+			name:           "Returns the events in the order of the pending-index",
+			expectedEvents: []model.Event{outboxEvent, newer},
+		},
+		{
+			name:          "Failed to query the outbox",
+			returnedError: fmt.Errorf("failed to query the outbox"),
+			expectError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryOutput := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{eventItem(outboxEvent), eventItem(newer)}}
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("Query", context.TODO(), mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+				return *input.IndexName == outboxPendingIndex && *input.Limit == 10
+			})).Return(queryOutput, tt.returnedError)
+
+			outbox := OutboxRepositoryDDB{DynamodbClient: mockDdbClient, TableName: "outbox"}
+			events, err := outbox.FindPendingEvents(10)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from outbox.FindPendingEvents")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from outbox.FindPendingEvents")
+				assert.Equal(t, tt.expectedEvents, events)
+			}
+			mockDdbClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSingleTableOutboxRepositoryDDB_DeleteEvent(t *testing.T) {
+	mockDdbClient := client.NewMockDDBClient(t)
+	mockDdbClient.On("DeleteItem", context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String("table"),
+		Key:       singleTableKey(outboxPartitionKey, "2023-01-01T00:00:00.000Z#event0"),
+	}).Return(&dynamodb.DeleteItemOutput{}, nil)
+
+	outbox := SingleTableOutboxRepositoryDDB{DynamodbClient: mockDdbClient, TableName: "table"}
+	assert.Nil(t, outbox.DeleteEvent(outboxEvent), "No error should have been returned from outbox.DeleteEvent")
+	mockDdbClient.AssertExpectations(t)
+}
+
+func TestUserRepositoryDDB_CreateNewUser_WithEvents(t *testing.T) {
+	tests := []struct {
+		name                string
+		returnedError       error
+		expectError         bool
+		expectConflictError bool
+	}{
+		{
+			name: "Successfully created the user and recorded the event",
+		},
+		{
+			name: "User already exists",
+			returnedError: &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+				{Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("None")},
+			}},
+			expectError:         true,
+			expectConflictError: true,
+		},
+		{
+			name:          "Failed to create the user",
+			returnedError: fmt.Errorf("failed to create the user"),
+			expectError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("TransactWriteItems", context.TODO(), mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				return len(input.TransactItems) == 2 &&
+					aws.ToString(input.TransactItems[0].Put.TableName) == "users" &&
+					aws.ToString(input.TransactItems[1].Put.TableName) == "outbox" &&
+					stringAttribute(input.TransactItems[1].Put.Item, "event_type") == string(model.UserCreated)
+			})).Return(&dynamodb.TransactWriteItemsOutput{}, tt.returnedError)
+
+			userRepo := UserRepositoryDDB{DynamodbClient: mockDdbClient, TableName: "users", OutboxTableName: "outbox"}
+			err := userRepo.CreateNewUser(singleTableUser, outboxEvent)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from userRepo.CreateNewUser")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from userRepo.CreateNewUser")
+			}
+			assert.Equal(t, tt.expectConflictError, errors.As(err, &apperrors.ConflictError{}))
+			mockDdbClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFavoriteRepositoryDDB_DeleteFavorite_WithEvents(t *testing.T) {
+	event := model.Event{Id: "event0", Type: model.FavoriteRemoved, OccurredAt: outboxEvent.OccurredAt, UserId: "0", FavoriteId: "favorite0", DrinkId: "11007"}
+	tests := []struct {
+		name          string
+		returnedError error
+		expectError   bool
+	}{
+		{
+			name: "Successfully deleted the favorite and recorded the event",
+		},
+		{
+			name: "Favorite was already deleted",
+			returnedError: &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+				{Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("None")},
+			}},
+		},
+		{
+			name:          "Failed to delete the favorite",
+			returnedError: fmt.Errorf("failed to delete the favorite"),
+			expectError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("TransactWriteItems", context.TODO(), mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				return len(input.TransactItems) == 2 &&
+					input.TransactItems[0].Delete != nil &&
+					stringAttribute(input.TransactItems[1].Put.Item, "drink_id") == "11007"
+			})).Return(&dynamodb.TransactWriteItemsOutput{}, tt.returnedError)
+
+			favoriteRepo := FavoriteRepositoryDDB{DynamodbClient: mockDdbClient, TableName: "favorites", OutboxTableName: "outbox"}
+			err := favoriteRepo.DeleteFavorite("favorite0", event)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from favoriteRepo.DeleteFavorite")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from favoriteRepo.DeleteFavorite")
+			}
+			mockDdbClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOutboxRepositoryDDB_ClaimEvent(t *testing.T) {
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		returnedError error
+		expectClaimed bool
+		expectError   bool
+	}{
+		{
+			name:          "Successfully claimed the event",
+			expectClaimed: true,
+		},
+		{
+			name:          "Claimed by another relay or already deleted",
+			returnedError: apperrors.NewConflictError("conflict", &types.ConditionalCheckFailedException{}),
+		},
+		{
+			name:          "Failed to claim the event",
+			returnedError: fmt.Errorf("failed to claim the event"),
+			expectError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("UpdateItem", context.TODO(), mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+				claimedUntil := false
+				for _, value := range input.ExpressionAttributeValues {
+					number, ok := value.(*types.AttributeValueMemberN)
+					claimedUntil = claimedUntil || ok && number.Value == fmt.Sprintf("%d", now.Add(time.Minute).UnixMilli())
+				}
+				return stringAttribute(input.Key, "id") == "event0" && input.ConditionExpression != nil && claimedUntil
+			})).Return(&dynamodb.UpdateItemOutput{}, tt.returnedError)
+
+			outbox := OutboxRepositoryDDB{DynamodbClient: mockDdbClient, TableName: "outbox"}
+			claimed, err := outbox.ClaimEvent(outboxEvent, now, time.Minute)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from outbox.ClaimEvent")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from outbox.ClaimEvent")
+			}
+			assert.Equal(t, tt.expectClaimed, claimed)
+			mockDdbClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// queryPages runs the query until its last page, since dynamodb stops each page at 1MB of items
+func queryPages(db client.DDBClient, input *dynamodb.QueryInput) ([]map[string]types.AttributeValue, error) {
+	items := []map[string]types.AttributeValue{}
+	for {
+		queryOutput, err := db.Query(context.TODO(), input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, queryOutput.Items...)
+		if len(queryOutput.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		next := *input
+		next.ExclusiveStartKey = queryOutput.LastEvaluatedKey
+		input = &next
+	}
+}
+
+// scanPages reads every page of the scan
+func scanPages(db client.DDBClient, input *dynamodb.ScanInput) ([]map[string]types.AttributeValue, error) {
+	items := []map[string]types.AttributeValue{}
+	for {
+		scanOutput, err := db.Scan(context.TODO(), input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, scanOutput.Items...)
+		if len(scanOutput.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		next := *input
+		next.ExclusiveStartKey = scanOutput.LastEvaluatedKey
+		input = &next
+	}
+}
@@ -0,0 +1,181 @@
+//go:generate mockery --name=RecipeRepository --output=./ --outpkg=repository --filename=recipe_mock.go --inpackage
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type RecipeRepository interface {
+	// FindRecipeById returns the recipe, or nil if it doesn't exist
+	FindRecipeById(id string) (*model.Recipe, error)
+	// FindRecipesByOwner returns the user's recipes in no particular order
+	FindRecipesByOwner(ownerId string) ([]model.Recipe, error)
+	// FindPublicRecipes returns every recipe whose visibility is public, in no particular order
+	FindPublicRecipes() ([]model.Recipe, error)
+	// CreateRecipe inserts the recipe unless one already exists with its id, in which case the ConflictError is returned
+	CreateRecipe(recipe model.Recipe) error
+	// UpdateRecipe replaces the recipe as long as the stored version still matches expectedVersion;
+	// if the recipe doesn't exist or another request modified it first, the ConflictError is returned
+	UpdateRecipe(recipe model.Recipe, expectedVersion int) error
+	// DeleteRecipe removes the recipe; deleting a recipe that doesn't exist does nothing
+	DeleteRecipe(id string) error
+	// DeleteRecipesByOwner removes every recipe of the user
+	DeleteRecipesByOwner(ownerId string) error
+}
+
+// NewRecipeRepository creates the recipe repository for the table design selected by the app config
+func NewRecipeRepository(appConfig model.AppConfig) (RecipeRepository, error) {
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
+	if appConfig.TableDesign == model.SingleTableDesign {
+		return &SingleTableRecipeRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName}, err
+	}
+	return &RecipeRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.RecipesTableName}, err
+}
+
+// RecipeRepositoryDDB stores the recipes keyed on id, with the owner-index to query a user's recipes
+type RecipeRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *RecipeRepositoryDDB) FindRecipeById(id string) (*model.Recipe, error) {
+	recipe := model.Recipe{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, recipeTableKey(id), &recipe)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+func (r *RecipeRepositoryDDB) FindRecipesByOwner(ownerId string) ([]model.Recipe, error) {
+	keyCondition, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key("owner_id").Equal(expression.Value(ownerId)),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	items, err := queryPages(r.DynamodbClient, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		IndexName:                 aws.String("owner-index"),
+		KeyConditionExpression:    keyCondition.KeyCondition(),
+		ExpressionAttributeNames:  keyCondition.Names(),
+		ExpressionAttributeValues: keyCondition.Values(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	recipes := []model.Recipe{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &recipes); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// FindPublicRecipes scans the table, since public recipes are only read to build the search index
+func (r *RecipeRepositoryDDB) FindPublicRecipes() ([]model.Recipe, error) {
+	filterExpression, err := expression.NewBuilder().WithFilter(
+		expression.Name("visibility").Equal(expression.Value(model.RecipePublic)),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	items, err := scanPages(r.DynamodbClient, &dynamodb.ScanInput{
+		TableName:                 aws.String(r.TableName),
+		FilterExpression:          filterExpression.Filter(),
+		ExpressionAttributeNames:  filterExpression.Names(),
+		ExpressionAttributeValues: filterExpression.Values(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	recipes := []model.Recipe{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &recipes); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+func (r *RecipeRepositoryDDB) CreateRecipe(recipe model.Recipe) error {
+	record, err := attributevalue.MarshalMap(recipe)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:           aws.String(r.TableName),
+		Item:                record,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return recipeAlreadyExistsError(recipe, err)
+	}
+	return err
+}
+
+func (r *RecipeRepositoryDDB) UpdateRecipe(recipe model.Recipe, expectedVersion int) error {
+	record, err := attributevalue.MarshalMap(recipe)
+	if err != nil {
+		return err
+	}
+	return putRecipe(r.DynamodbClient, r.TableName, record, "id", recipe, expectedVersion)
+}
+
+func (r *RecipeRepositoryDDB) DeleteRecipe(id string) error {
+	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       recipeTableKey(id),
+	})
+	return err
+}
+
+func (r *RecipeRepositoryDDB) DeleteRecipesByOwner(ownerId string) error {
+	recipes, err := r.FindRecipesByOwner(ownerId)
+	if err != nil {
+		return err
+	}
+	for _, recipe := range recipes {
+		if err := r.DeleteRecipe(recipe.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func recipeTableKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func recipeAlreadyExistsError(recipe model.Recipe, err error) error {
+	return apperrors.NewConflictError(fmt.Sprintf("a recipe already exists with the id '%s'", recipe.Id), err)
+}
+
+// putRecipe replaces the recipe if its version still matches; keyAttribute is the table's hash key, which only exists on stored items
+func putRecipe(db client.DDBClient, tableName string, record map[string]types.AttributeValue, keyAttribute string, recipe model.Recipe, expectedVersion int) error {
+	condition, err := versionConditionOn(keyAttribute, expectedVersion)
+	if err != nil {
+		return err
+	}
+	_, err = db.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(tableName),
+		Item:                      record,
+		ConditionExpression:       condition.Condition(),
+		ExpressionAttributeNames:  condition.Names(),
+		ExpressionAttributeValues: condition.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return apperrors.NewConflictError(fmt.Sprintf("the recipe '%s' doesn't exist or was modified by another request", recipe.Id), err)
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+)
+
+// MemoryRecipeRepository keeps the recipes in memory, e.g. for local development and tests
+type MemoryRecipeRepository struct {
+	mu      sync.RWMutex
+	recipes map[string]model.Recipe
+}
+
+func NewMemoryRecipeRepository() *MemoryRecipeRepository {
+	return &MemoryRecipeRepository{recipes: map[string]model.Recipe{}}
+}
+
+func (r *MemoryRecipeRepository) FindRecipeById(id string) (*model.Recipe, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	recipe, ok := r.recipes[id]
+	if !ok {
+		return nil, nil
+	}
+	recipe = copyRecipe(recipe)
+	return &recipe, nil
+}
+
+func (r *MemoryRecipeRepository) FindRecipesByOwner(ownerId string) ([]model.Recipe, error) {
+	return r.findRecipes(func(recipe model.Recipe) bool { return recipe.OwnerId == ownerId }), nil
+}
+
+func (r *MemoryRecipeRepository) FindPublicRecipes() ([]model.Recipe, error) {
+	return r.findRecipes(func(recipe model.Recipe) bool { return recipe.Visibility == model.RecipePublic }), nil
+}
+
+func (r *MemoryRecipeRepository) CreateRecipe(recipe model.Recipe) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.recipes[recipe.Id]; ok {
+		return recipeAlreadyExistsError(recipe, nil)
+	}
+	r.recipes[recipe.Id] = copyRecipe(recipe)
+	return nil
+}
+
+func (r *MemoryRecipeRepository) UpdateRecipe(recipe model.Recipe, expectedVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.recipes[recipe.Id]
+	if !ok || existing.Version != expectedVersion {
+		return apperrors.NewConflictError(fmt.Sprintf("the recipe '%s' doesn't exist or was modified by another request", recipe.Id), nil)
+	}
+	r.recipes[recipe.Id] = copyRecipe(recipe)
+	return nil
+}
+
+func (r *MemoryRecipeRepository) DeleteRecipe(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.recipes, id)
+	return nil
+}
+
+func (r *MemoryRecipeRepository) DeleteRecipesByOwner(ownerId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, recipe := range r.recipes {
+		if recipe.OwnerId == ownerId {
+			delete(r.recipes, id)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRecipeRepository) findRecipes(matches func(recipe model.Recipe) bool) []model.Recipe {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	recipes := []model.Recipe{}
+	for _, recipe := range r.recipes {
+		if matches(recipe) {
+			recipes = append(recipes, copyRecipe(recipe))
+		}
+	}
+	return recipes
+}
+
+// copyRecipe copies the ingredients so that callers can't modify the stored recipe
+func copyRecipe(recipe model.Recipe) model.Recipe {
+	if recipe.Ingredients != nil {
+		recipe.Ingredients = append([]model.Ingredient{}, recipe.Ingredients...)
+	}
+	return recipe
+}
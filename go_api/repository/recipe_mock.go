@@ -0,0 +1,162 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package repository
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRecipeRepository is an autogenerated mock type for the RecipeRepository type
+type MockRecipeRepository struct {
+	mock.Mock
+}
+
+// CreateRecipe provides a mock function with given fields: recipe
+func (_m *MockRecipeRepository) CreateRecipe(recipe model.Recipe) error {
+	ret := _m.Called(recipe)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Recipe) error); ok {
+		r0 = rf(recipe)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRecipe provides a mock function with given fields: id
+func (_m *MockRecipeRepository) DeleteRecipe(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRecipesByOwner provides a mock function with given fields: ownerId
+func (_m *MockRecipeRepository) DeleteRecipesByOwner(ownerId string) error {
+	ret := _m.Called(ownerId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(ownerId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindPublicRecipes provides a mock function with given fields:
+func (_m *MockRecipeRepository) FindPublicRecipes() ([]model.Recipe, error) {
+	ret := _m.Called()
+
+	var r0 []model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]model.Recipe, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.Recipe); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRecipeById provides a mock function with given fields: id
+func (_m *MockRecipeRepository) FindRecipeById(id string) (*model.Recipe, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Recipe, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Recipe); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRecipesByOwner provides a mock function with given fields: ownerId
+func (_m *MockRecipeRepository) FindRecipesByOwner(ownerId string) ([]model.Recipe, error) {
+	ret := _m.Called(ownerId)
+
+	var r0 []model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]model.Recipe, error)); ok {
+		return rf(ownerId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.Recipe); ok {
+		r0 = rf(ownerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRecipe provides a mock function with given fields: recipe, expectedVersion
+func (_m *MockRecipeRepository) UpdateRecipe(recipe model.Recipe, expectedVersion int) error {
+	ret := _m.Called(recipe, expectedVersion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Recipe, int) error); ok {
+		r0 = rf(recipe, expectedVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockRecipeRepository creates a new instance of MockRecipeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRecipeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRecipeRepository {
+	mock := &MockRecipeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,130 @@
+package repositorytest
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// DrinkRepositorySuite checks the behavior that the services rely on from a DrinkRepository
+type DrinkRepositorySuite struct {
+	// NewRepository returns an empty repository for each test case
+	NewRepository func(t *testing.T) repository.DrinkRepository
+}
+
+// Run runs every test case of the suite as a subtest of t
+func (s DrinkRepositorySuite) Run(t *testing.T) {
+	t.Run("Saves and finds a drink", s.testSaveAndFind)
+	t.Run("Returns nil for a missing drink", s.testFindMissing)
+	t.Run("Replaces a saved drink", s.testReplace)
+	t.Run("Finds every drink across pages", s.testFindAllPages)
+	t.Run("Finds drinks by ids across batches", s.testFindByIds)
+}
+
+func newDrink(id string) model.Drink {
+	return model.Drink{
+		Id:           id,
+		Name:         "Margarita " + id,
+		Category:     "Ordinary Drink",
+		Glass:        "Cocktail glass",
+		Instructions: "Shake with ice and strain into the glass.",
+		Ingredients: []model.Ingredient{
+			{Name: "Tequila", Measure: "1 1/2 oz"},
+			{Name: "Triple sec", Measure: "1/2 oz"},
+			{Name: "Lime juice", Measure: "1 oz"},
+			{Name: "Salt"},
+		},
+		ImageUrl:  "https://www.thecocktaildb.com/images/media/drink/" + id + ".jpg",
+		Alcoholic: true,
+	}
+}
+
+func (s DrinkRepositorySuite) testSaveAndFind(t *testing.T) {
+	repo := s.NewRepository(t)
+	drink := newDrink("11007")
+	assert.NoError(t, repo.SaveDrink(drink), "No error should have been returned from SaveDrink")
+	// a drink with only the required fields should be returned as it was saved too
+	minimalDrink := model.Drink{Id: "11008", Name: "Water"}
+	assert.NoError(t, repo.SaveDrink(minimalDrink), "No error should have been returned from SaveDrink")
+
+	foundDrink, err := repo.FindDrinkById(drink.Id)
+	assert.NoError(t, err, "No error should have been returned from FindDrinkById")
+	assert.Equal(t, &drink, foundDrink, "The saved drink should have been found by id")
+
+	foundDrink, err = repo.FindDrinkById(minimalDrink.Id)
+	assert.NoError(t, err, "No error should have been returned from FindDrinkById")
+	assert.Equal(t, &minimalDrink, foundDrink, "The saved drink should have been found by id")
+}
+
+func (s DrinkRepositorySuite) testFindMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+
+	foundDrink, err := repo.FindDrinkById("missing")
+	assert.NoError(t, err, "No error should have been returned from FindDrinkById")
+	assert.Nil(t, foundDrink, "No drink should have been found")
+
+	drinks, err := repo.FindAll()
+	assert.NoError(t, err, "No error should have been returned from FindAll")
+	assert.Empty(t, drinks, "An empty repository shouldn't have any drinks")
+}
+
+func (s DrinkRepositorySuite) testReplace(t *testing.T) {
+	repo := s.NewRepository(t)
+	drink := newDrink("11007")
+	assert.NoError(t, repo.SaveDrink(drink), "No error should have been returned from SaveDrink")
+
+	updatedDrink := drink
+	updatedDrink.Name = "Tommy's Margarita"
+	updatedDrink.Ingredients = []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}, {Name: "Agave syrup", Measure: "1/2 oz"}}
+	updatedDrink.Category = ""
+	assert.NoError(t, repo.SaveDrink(updatedDrink), "No error should have been returned from SaveDrink")
+
+	foundDrink, err := repo.FindDrinkById(drink.Id)
+	assert.NoError(t, err, "No error should have been returned from FindDrinkById")
+	assert.Equal(t, &updatedDrink, foundDrink, "The drink should have been replaced")
+}
+
+func (s DrinkRepositorySuite) testFindAllPages(t *testing.T) {
+	repo := s.NewRepository(t)
+	expectedIds := []string{}
+	for i := 0; i < pageTestSize; i++ {
+		drink := newDrink(fmt.Sprintf("drink%02d", i))
+		assert.NoError(t, repo.SaveDrink(drink), "No error should have been returned from SaveDrink")
+		expectedIds = append(expectedIds, drink.Id)
+	}
+
+	drinks, err := repo.FindAll()
+	assert.NoError(t, err, "No error should have been returned from FindAll")
+	ids := []string{}
+	for _, drink := range drinks {
+		ids = append(ids, drink.Id)
+	}
+	sort.Strings(ids)
+	assert.Equal(t, expectedIds, ids, "Every drink should have been found")
+}
+
+func (s DrinkRepositorySuite) testFindByIds(t *testing.T) {
+	repo := s.NewRepository(t)
+	// more ids than fit in a single BatchGetItem request
+	ids := []string{}
+	for i := 0; i < 120; i++ {
+		drink := newDrink(fmt.Sprintf("drink%03d", i))
+		assert.NoError(t, repo.SaveDrink(drink), "No error should have been returned from SaveDrink")
+		ids = append(ids, drink.Id)
+	}
+
+	drinks, err := repo.FindDrinksByIds(append(ids, "drink000", "missing"))
+	assert.NoError(t, err, "No error should have been returned from FindDrinksByIds")
+	assert.Len(t, drinks, len(ids), "Every saved drink should have been found once")
+	assert.Equal(t, newDrink("drink042"), drinks["drink042"], "The drinks should be mapped by id")
+	assert.NotContains(t, drinks, "missing", "A drink that isn't in the catalog should have been left out")
+
+	drinks, err = repo.FindDrinksByIds(nil)
+	assert.NoError(t, err, "No error should have been returned from FindDrinksByIds")
+	assert.Empty(t, drinks, "No drinks should have been found without ids")
+}
@@ -0,0 +1,240 @@
+package repositorytest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// FavoriteRepositorySuite checks the behavior that the services rely on from a FavoriteRepository;
+// favorites are created with the ids that the FavoriteService derives from the user and drink
+type FavoriteRepositorySuite struct {
+	// NewRepository returns an empty repository for each test case
+	NewRepository func(t *testing.T) repository.FavoriteRepository
+}
+
+// Run runs every test case of the suite as a subtest of t
+func (s FavoriteRepositorySuite) Run(t *testing.T) {
+	t.Run("Creates and finds a favorite", s.testCreateAndFind)
+	t.Run("Returns nothing for a missing favorite", s.testFindMissing)
+	t.Run("Rejects a duplicate favorite", s.testDuplicate)
+	t.Run("Finds every favorite across pages", s.testFindPages)
+	t.Run("Sorts a user's favorites by creation", s.testSortedByCreation)
+	t.Run("Updates a favorite", s.testUpdate)
+	t.Run("Rejects a stale update", s.testStaleUpdate)
+	t.Run("Deletes a favorite", s.testDelete)
+	t.Run("Ignores the delete of a missing favorite", s.testDeleteMissing)
+}
+
+// newFavorite creates a favorite whose creation time is ordered by minute
+func newFavorite(userId, drinkId string, minute int) model.Favorite {
+	createdAt := time.Date(2023, 1, 1, 0, minute, 0, 0, time.UTC)
+	return model.Favorite{
+		Id:        service.NewFavoriteId(userId, drinkId),
+		UserId:    userId,
+		DrinkId:   drinkId,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Version:   1,
+	}
+}
+
+func favoriteIds(favorites []model.Favorite) []string {
+	ids := []string{}
+	for _, favorite := range favorites {
+		ids = append(ids, favorite.Id)
+	}
+	return ids
+}
+
+func (s FavoriteRepositorySuite) testCreateAndFind(t *testing.T) {
+	repo := s.NewRepository(t)
+	favorite := newFavorite("user0", "11007", 0)
+	otherFavorite := newFavorite("user1", "11007", 1)
+	err := repo.CreateNewFavorite(favorite, model.Event{
+		Id:         "event-0",
+		Type:       model.FavoriteAdded,
+		OccurredAt: favorite.CreatedAt,
+		UserId:     favorite.UserId,
+		FavoriteId: favorite.Id,
+		DrinkId:    favorite.DrinkId,
+	})
+	assert.NoError(t, err, "No error should have been returned from CreateNewFavorite")
+	assert.NoError(t, repo.CreateNewFavorite(otherFavorite), "No error should have been returned from CreateNewFavorite")
+
+	foundFavorite, err := repo.FindFavoriteById(favorite.Id)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteById")
+	assert.Equal(t, &favorite, foundFavorite, "The created favorite should have been found by id")
+
+	foundFavorite, err = repo.FindFavoriteByUserAndDrink(favorite.UserId, favorite.DrinkId)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteByUserAndDrink")
+	assert.Equal(t, &favorite, foundFavorite, "The created favorite should have been found by user and drink")
+
+	favorites, err := repo.FindFavoritesByUser(favorite.UserId)
+	assert.NoError(t, err, "No error should have been returned from FindFavoritesByUser")
+	assert.Equal(t, []model.Favorite{favorite}, favorites, "Only the user's favorite should have been found")
+}
+
+func (s FavoriteRepositorySuite) testFindMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+
+	foundFavorite, err := repo.FindFavoriteById("missing")
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteById")
+	assert.Nil(t, foundFavorite, "No favorite should have been found by id")
+
+	foundFavorite, err = repo.FindFavoriteByUserAndDrink("missing", "11007")
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteByUserAndDrink")
+	assert.Nil(t, foundFavorite, "No favorite should have been found by user and drink")
+
+	favorites, err := repo.FindFavoritesByUser("missing")
+	assert.NoError(t, err, "No error should have been returned from FindFavoritesByUser")
+	assert.Empty(t, favorites, "An unknown user shouldn't have any favorites")
+
+	favorites, err = repo.FindFavoritesByUserSortedByCreation("missing", true)
+	assert.NoError(t, err, "No error should have been returned from FindFavoritesByUserSortedByCreation")
+	assert.Empty(t, favorites, "An unknown user shouldn't have any favorites")
+
+	favorites, err = repo.FindAll()
+	assert.NoError(t, err, "No error should have been returned from FindAll")
+	assert.Empty(t, favorites, "An empty repository shouldn't have any favorites")
+}
+
+func (s FavoriteRepositorySuite) testDuplicate(t *testing.T) {
+	repo := s.NewRepository(t)
+	favorite := newFavorite("user0", "11007", 0)
+	assert.NoError(t, repo.CreateNewFavorite(favorite), "No error should have been returned from CreateNewFavorite")
+
+	duplicate := newFavorite("user0", "11007", 1)
+	err := repo.CreateNewFavorite(duplicate)
+	assert.True(t, errors.As(err, &apperrors.FavoriteAlreadyExistsError{}), "The FavoriteAlreadyExistsError should have been returned, got %v", err)
+
+	foundFavorite, err := repo.FindFavoriteByUserAndDrink(favorite.UserId, favorite.DrinkId)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteByUserAndDrink")
+	assert.Equal(t, &favorite, foundFavorite, "The original favorite shouldn't have been replaced")
+}
+
+func (s FavoriteRepositorySuite) testFindPages(t *testing.T) {
+	repo := s.NewRepository(t)
+	expectedIds := []string{}
+	for i := 0; i < pageTestSize; i++ {
+		favorite := newFavorite("user0", fmt.Sprintf("drink%02d", i), i)
+		assert.NoError(t, repo.CreateNewFavorite(favorite), "No error should have been returned from CreateNewFavorite")
+		expectedIds = append(expectedIds, favorite.Id)
+	}
+	sort.Strings(expectedIds)
+
+	favorites, err := repo.FindFavoritesByUser("user0")
+	assert.NoError(t, err, "No error should have been returned from FindFavoritesByUser")
+	ids := favoriteIds(favorites)
+	sort.Strings(ids)
+	assert.Equal(t, expectedIds, ids, "Every favorite of the user should have been found")
+
+	favorites, err = repo.FindAll()
+	assert.NoError(t, err, "No error should have been returned from FindAll")
+	ids = favoriteIds(favorites)
+	sort.Strings(ids)
+	assert.Equal(t, expectedIds, ids, "Every favorite should have been found")
+}
+
+func (s FavoriteRepositorySuite) testSortedByCreation(t *testing.T) {
+	repo := s.NewRepository(t)
+	oldest := newFavorite("user0", "drink2", 0)
+	middle := newFavorite("user0", "drink0", 1)
+	newest := newFavorite("user0", "drink1", 2)
+	for _, favorite := range []model.Favorite{middle, newest, oldest, newFavorite("user1", "drink0", 3)} {
+		assert.NoError(t, repo.CreateNewFavorite(favorite), "No error should have been returned from CreateNewFavorite")
+	}
+
+	favorites, err := repo.FindFavoritesByUserSortedByCreation("user0", false)
+	assert.NoError(t, err, "No error should have been returned from FindFavoritesByUserSortedByCreation")
+	assert.Equal(t, []model.Favorite{oldest, middle, newest}, favorites, "The favorites should have been sorted oldest first")
+
+	favorites, err = repo.FindFavoritesByUserSortedByCreation("user0", true)
+	assert.NoError(t, err, "No error should have been returned from FindFavoritesByUserSortedByCreation")
+	assert.Equal(t, []model.Favorite{newest, middle, oldest}, favorites, "The favorites should have been sorted newest first")
+}
+
+func (s FavoriteRepositorySuite) testUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	favorite := newFavorite("user0", "11007", 0)
+	assert.NoError(t, repo.CreateNewFavorite(favorite), "No error should have been returned from CreateNewFavorite")
+	// read the favorite first, so a caching backend has to notice the update
+	_, err := repo.FindFavoriteById(favorite.Id)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteById")
+
+	updatedFavorite := favorite
+	updatedFavorite.UpdatedAt = time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	updatedFavorite.Version = 2
+	err = repo.UpdateFavorite(updatedFavorite, favorite.Version)
+	assert.NoError(t, err, "No error should have been returned from UpdateFavorite")
+
+	foundFavorite, err := repo.FindFavoriteById(favorite.Id)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteById")
+	assert.Equal(t, &updatedFavorite, foundFavorite, "The favorite should have been updated")
+}
+
+func (s FavoriteRepositorySuite) testStaleUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	favorite := newFavorite("user0", "11007", 0)
+	assert.NoError(t, repo.CreateNewFavorite(favorite), "No error should have been returned from CreateNewFavorite")
+
+	staleFavorite := favorite
+	staleFavorite.UpdatedAt = time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	err := repo.UpdateFavorite(staleFavorite, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned, got %v", err)
+
+	missingFavorite := newFavorite("user0", "missing", 0)
+	err = repo.UpdateFavorite(missingFavorite, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned for a missing favorite, got %v", err)
+
+	foundFavorite, err := repo.FindFavoriteById(favorite.Id)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteById")
+	assert.Equal(t, &favorite, foundFavorite, "The favorite shouldn't have been updated")
+}
+
+func (s FavoriteRepositorySuite) testDelete(t *testing.T) {
+	repo := s.NewRepository(t)
+	favorite := newFavorite("user0", "11007", 0)
+	assert.NoError(t, repo.CreateNewFavorite(favorite), "No error should have been returned from CreateNewFavorite")
+	_, err := repo.FindFavoriteById(favorite.Id)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteById")
+
+	err = repo.DeleteFavorite(favorite.Id, model.Event{
+		Id:         "event-0",
+		Type:       model.FavoriteRemoved,
+		OccurredAt: favorite.CreatedAt,
+		UserId:     favorite.UserId,
+		FavoriteId: favorite.Id,
+		DrinkId:    favorite.DrinkId,
+	})
+	assert.NoError(t, err, "No error should have been returned from DeleteFavorite")
+
+	foundFavorite, err := repo.FindFavoriteById(favorite.Id)
+	assert.NoError(t, err, "No error should have been returned from FindFavoriteById")
+	assert.Nil(t, foundFavorite, "The favorite should have been deleted")
+
+	favorites, err := repo.FindFavoritesByUser(favorite.UserId)
+	assert.NoError(t, err, "No error should have been returned from FindFavoritesByUser")
+	assert.Empty(t, favorites, "The deleted favorite shouldn't be found for the user")
+
+	err = repo.CreateNewFavorite(favorite)
+	assert.NoError(t, err, "The drink should be able to be favorited again")
+}
+
+func (s FavoriteRepositorySuite) testDeleteMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+	err := repo.DeleteFavorite("missing")
+	assert.NoError(t, err, "No error should have been returned from DeleteFavorite")
+
+	err = repo.DeleteFavorite("missing", model.Event{Id: "event-0", Type: model.FavoriteRemoved, UserId: "user0"})
+	assert.NoError(t, err, "No error should have been returned from DeleteFavorite with events")
+}
@@ -0,0 +1,172 @@
+package repositorytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// InventoryRepositorySuite checks the behavior that the services rely on from an InventoryRepository
+type InventoryRepositorySuite struct {
+	// NewRepository returns an empty repository for each test case
+	NewRepository func(t *testing.T) repository.InventoryRepository
+}
+
+// Run runs every test case of the suite as a subtest of t
+func (s InventoryRepositorySuite) Run(t *testing.T) {
+	t.Run("Creates and finds an item", s.testCreateAndFind)
+	t.Run("Returns nothing for a missing item", s.testFindMissing)
+	t.Run("Rejects a duplicate item", s.testDuplicate)
+	t.Run("Finds a user's items ordered by ingredient", s.testFindByUser)
+	t.Run("Updates an item", s.testUpdate)
+	t.Run("Rejects a stale update", s.testStaleUpdate)
+	t.Run("Deletes an item", s.testDelete)
+	t.Run("Deletes a user's inventory", s.testDeleteByUser)
+}
+
+func newInventoryItem(userId, name string) model.InventoryItem {
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresOn := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	return model.InventoryItem{
+		UserId:     userId,
+		Ingredient: model.IngredientKey(name),
+		Name:       name,
+		Quantity:   700,
+		Unit:       "ml",
+		ExpiresOn:  &expiresOn,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+		Version:    1,
+	}
+}
+
+func inventoryIngredients(inventory []model.InventoryItem) []string {
+	ingredients := []string{}
+	for _, item := range inventory {
+		ingredients = append(ingredients, item.Ingredient)
+	}
+	return ingredients
+}
+
+func (s InventoryRepositorySuite) testCreateAndFind(t *testing.T) {
+	repo := s.NewRepository(t)
+	item := newInventoryItem("user0", "Tequila")
+	assert.NoError(t, repo.CreateInventoryItem(item), "No error should have been returned from CreateInventoryItem")
+	// an item without the optional fields should be returned as it was created too
+	minimalItem := model.InventoryItem{UserId: "user0", Ingredient: "lime", Name: "Lime", Quantity: 3, Version: 1}
+	assert.NoError(t, repo.CreateInventoryItem(minimalItem), "No error should have been returned from CreateInventoryItem")
+
+	foundItem, err := repo.FindInventoryItem("user0", "tequila")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryItem")
+	assert.Equal(t, &item, foundItem, "The created item should have been found by its ingredient")
+
+	foundItem, err = repo.FindInventoryItem("user0", "lime")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryItem")
+	assert.Equal(t, &minimalItem, foundItem, "The created item should have been found by its ingredient")
+}
+
+func (s InventoryRepositorySuite) testFindMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+	assert.NoError(t, repo.CreateInventoryItem(newInventoryItem("user0", "Tequila")), "No error should have been returned from CreateInventoryItem")
+
+	foundItem, err := repo.FindInventoryItem("user1", "tequila")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryItem")
+	assert.Nil(t, foundItem, "Another user's item shouldn't have been found")
+
+	inventory, err := repo.FindInventoryByUser("user1")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryByUser")
+	assert.Empty(t, inventory, "A user without items should have an empty inventory")
+}
+
+func (s InventoryRepositorySuite) testDuplicate(t *testing.T) {
+	repo := s.NewRepository(t)
+	item := newInventoryItem("user0", "Tequila")
+	assert.NoError(t, repo.CreateInventoryItem(item), "No error should have been returned from CreateInventoryItem")
+
+	duplicate := newInventoryItem("user0", "TEQUILA")
+	err := repo.CreateInventoryItem(duplicate)
+	assert.True(t, errors.As(err, &apperrors.InventoryItemAlreadyExistsError{}), "The InventoryItemAlreadyExistsError should have been returned; got %v", err)
+
+	foundItem, err := repo.FindInventoryItem("user0", "tequila")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryItem")
+	assert.Equal(t, &item, foundItem, "The original item should have been kept")
+}
+
+func (s InventoryRepositorySuite) testFindByUser(t *testing.T) {
+	repo := s.NewRepository(t)
+	for _, name := range []string{"Triple sec", "Tequila", "Lime juice"} {
+		assert.NoError(t, repo.CreateInventoryItem(newInventoryItem("user0", name)), "No error should have been returned from CreateInventoryItem")
+	}
+	assert.NoError(t, repo.CreateInventoryItem(newInventoryItem("user1", "Gin")), "No error should have been returned from CreateInventoryItem")
+
+	inventory, err := repo.FindInventoryByUser("user0")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryByUser")
+	assert.Equal(t, []string{"lime juice", "tequila", "triple sec"}, inventoryIngredients(inventory), "Only the user's items should have been found")
+}
+
+func (s InventoryRepositorySuite) testUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	item := newInventoryItem("user0", "Tequila")
+	assert.NoError(t, repo.CreateInventoryItem(item), "No error should have been returned from CreateInventoryItem")
+
+	updatedItem := item
+	updatedItem.Quantity = 350
+	updatedItem.ExpiresOn = nil
+	updatedItem.UpdatedAt = item.UpdatedAt.Add(time.Hour)
+	updatedItem.Version = 2
+	assert.NoError(t, repo.UpdateInventoryItem(updatedItem, item.Version), "No error should have been returned from UpdateInventoryItem")
+
+	foundItem, err := repo.FindInventoryItem("user0", "tequila")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryItem")
+	assert.Equal(t, &updatedItem, foundItem, "The item should have been replaced")
+}
+
+func (s InventoryRepositorySuite) testStaleUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	item := newInventoryItem("user0", "Tequila")
+	assert.NoError(t, repo.CreateInventoryItem(item), "No error should have been returned from CreateInventoryItem")
+
+	staleItem := item
+	staleItem.Version = 2
+	err := repo.UpdateInventoryItem(staleItem, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned for a stale version; got %v", err)
+
+	missingItem := newInventoryItem("user0", "Gin")
+	err = repo.UpdateInventoryItem(missingItem, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned for a missing item; got %v", err)
+}
+
+func (s InventoryRepositorySuite) testDelete(t *testing.T) {
+	repo := s.NewRepository(t)
+	assert.NoError(t, repo.CreateInventoryItem(newInventoryItem("user0", "Tequila")), "No error should have been returned from CreateInventoryItem")
+
+	assert.NoError(t, repo.DeleteInventoryItem("user0", "tequila"), "No error should have been returned from DeleteInventoryItem")
+	foundItem, err := repo.FindInventoryItem("user0", "tequila")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryItem")
+	assert.Nil(t, foundItem, "The item should have been deleted")
+
+	assert.NoError(t, repo.DeleteInventoryItem("user0", "tequila"), "Deleting a missing item shouldn't return an error")
+}
+
+func (s InventoryRepositorySuite) testDeleteByUser(t *testing.T) {
+	repo := s.NewRepository(t)
+	for _, name := range []string{"Tequila", "Lime juice"} {
+		assert.NoError(t, repo.CreateInventoryItem(newInventoryItem("user0", name)), "No error should have been returned from CreateInventoryItem")
+	}
+	assert.NoError(t, repo.CreateInventoryItem(newInventoryItem("user1", "Gin")), "No error should have been returned from CreateInventoryItem")
+
+	assert.NoError(t, repo.DeleteInventoryByUser("user0"), "No error should have been returned from DeleteInventoryByUser")
+	inventory, err := repo.FindInventoryByUser("user0")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryByUser")
+	assert.Empty(t, inventory, "The user's inventory should have been deleted")
+
+	inventory, err = repo.FindInventoryByUser("user1")
+	assert.NoError(t, err, "No error should have been returned from FindInventoryByUser")
+	assert.Len(t, inventory, 1, "Other users' inventories should have been kept")
+}
@@ -0,0 +1,204 @@
+package repositorytest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RecipeRepositorySuite checks the behavior that the services rely on from a RecipeRepository
+type RecipeRepositorySuite struct {
+	// NewRepository returns an empty repository for each test case
+	NewRepository func(t *testing.T) repository.RecipeRepository
+}
+
+// Run runs every test case of the suite as a subtest of t
+func (s RecipeRepositorySuite) Run(t *testing.T) {
+	t.Run("Creates and finds a recipe", s.testCreateAndFind)
+	t.Run("Returns nil for a missing recipe", s.testFindMissing)
+	t.Run("Rejects a duplicate recipe", s.testDuplicate)
+	t.Run("Finds an owner's recipes across pages", s.testFindByOwner)
+	t.Run("Finds the public recipes", s.testFindPublic)
+	t.Run("Updates a recipe", s.testUpdate)
+	t.Run("Rejects a stale update", s.testStaleUpdate)
+	t.Run("Deletes a recipe", s.testDelete)
+	t.Run("Deletes an owner's recipes", s.testDeleteByOwner)
+}
+
+func newRecipe(id, ownerId, visibility string) model.Recipe {
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	return model.Recipe{
+		Id:           model.RecipeIdPrefix + id,
+		OwnerId:      ownerId,
+		Name:         "House Margarita " + id,
+		Category:     "Cocktail",
+		Glass:        "Coupe",
+		Instructions: "Shake with ice and strain into the glass.",
+		Ingredients: []model.Ingredient{
+			{Name: "Tequila", Measure: "2 oz"},
+			{Name: "Lime juice", Measure: "1 oz"},
+			{Name: "Salt"},
+		},
+		Alcoholic:  true,
+		Visibility: visibility,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+		Version:    1,
+	}
+}
+
+func recipeIds(recipes []model.Recipe) []string {
+	ids := []string{}
+	for _, recipe := range recipes {
+		ids = append(ids, recipe.Id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s RecipeRepositorySuite) testCreateAndFind(t *testing.T) {
+	repo := s.NewRepository(t)
+	recipe := newRecipe("0", "user0", model.RecipePrivate)
+	assert.NoError(t, repo.CreateRecipe(recipe), "No error should have been returned from CreateRecipe")
+	// a recipe without the optional fields should be returned as it was created too
+	minimalRecipe := model.Recipe{Id: model.RecipeIdPrefix + "1", OwnerId: "user0", Name: "Water", Visibility: model.RecipePublic, Version: 1}
+	assert.NoError(t, repo.CreateRecipe(minimalRecipe), "No error should have been returned from CreateRecipe")
+
+	foundRecipe, err := repo.FindRecipeById(recipe.Id)
+	assert.NoError(t, err, "No error should have been returned from FindRecipeById")
+	assert.Equal(t, &recipe, foundRecipe, "The created recipe should have been found by its id")
+
+	foundRecipe, err = repo.FindRecipeById(minimalRecipe.Id)
+	assert.NoError(t, err, "No error should have been returned from FindRecipeById")
+	assert.Equal(t, &minimalRecipe, foundRecipe, "The created recipe should have been found by its id")
+}
+
+func (s RecipeRepositorySuite) testFindMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+	assert.NoError(t, repo.CreateRecipe(newRecipe("0", "user0", model.RecipePublic)), "No error should have been returned from CreateRecipe")
+
+	foundRecipe, err := repo.FindRecipeById(model.RecipeIdPrefix + "1")
+	assert.NoError(t, err, "No error should have been returned from FindRecipeById")
+	assert.Nil(t, foundRecipe, "No recipe should have been found")
+
+	recipes, err := repo.FindRecipesByOwner("user1")
+	assert.NoError(t, err, "No error should have been returned from FindRecipesByOwner")
+	assert.Empty(t, recipes, "A user without recipes should have none")
+}
+
+func (s RecipeRepositorySuite) testDuplicate(t *testing.T) {
+	repo := s.NewRepository(t)
+	recipe := newRecipe("0", "user0", model.RecipePrivate)
+	assert.NoError(t, repo.CreateRecipe(recipe), "No error should have been returned from CreateRecipe")
+
+	duplicate := newRecipe("0", "user1", model.RecipePublic)
+	err := repo.CreateRecipe(duplicate)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned; got %v", err)
+
+	foundRecipe, err := repo.FindRecipeById(recipe.Id)
+	assert.NoError(t, err, "No error should have been returned from FindRecipeById")
+	assert.Equal(t, &recipe, foundRecipe, "The original recipe should have been kept")
+}
+
+func (s RecipeRepositorySuite) testFindByOwner(t *testing.T) {
+	repo := s.NewRepository(t)
+	expectedIds := []string{}
+	for i := 0; i < 25; i++ {
+		recipe := newRecipe(fmt.Sprintf("%02d", i), "user0", model.RecipePrivate)
+		assert.NoError(t, repo.CreateRecipe(recipe), "No error should have been returned from CreateRecipe")
+		expectedIds = append(expectedIds, recipe.Id)
+	}
+	assert.NoError(t, repo.CreateRecipe(newRecipe("99", "user1", model.RecipePublic)), "No error should have been returned from CreateRecipe")
+
+	recipes, err := repo.FindRecipesByOwner("user0")
+	assert.NoError(t, err, "No error should have been returned from FindRecipesByOwner")
+	assert.Equal(t, expectedIds, recipeIds(recipes), "Only the owner's recipes should have been found")
+}
+
+func (s RecipeRepositorySuite) testFindPublic(t *testing.T) {
+	repo := s.NewRepository(t)
+	for i, visibility := range []string{model.RecipePrivate, model.RecipeUnlisted, model.RecipePublic, model.RecipePublic} {
+		recipe := newRecipe(fmt.Sprint(i), fmt.Sprintf("user%d", i%2), visibility)
+		assert.NoError(t, repo.CreateRecipe(recipe), "No error should have been returned from CreateRecipe")
+	}
+
+	recipes, err := repo.FindPublicRecipes()
+	assert.NoError(t, err, "No error should have been returned from FindPublicRecipes")
+	assert.Equal(t, []string{model.RecipeIdPrefix + "2", model.RecipeIdPrefix + "3"}, recipeIds(recipes), "Only the public recipes should have been found")
+}
+
+func (s RecipeRepositorySuite) testUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	recipe := newRecipe("0", "user0", model.RecipePrivate)
+	assert.NoError(t, repo.CreateRecipe(recipe), "No error should have been returned from CreateRecipe")
+
+	updatedRecipe := recipe
+	updatedRecipe.Name = "Tommy's Margarita"
+	updatedRecipe.Ingredients = []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}, {Name: "Agave syrup", Measure: "1/2 oz"}}
+	updatedRecipe.Glass = ""
+	updatedRecipe.Visibility = model.RecipePublic
+	updatedRecipe.UpdatedAt = recipe.UpdatedAt.Add(time.Hour)
+	updatedRecipe.Version = 2
+	assert.NoError(t, repo.UpdateRecipe(updatedRecipe, recipe.Version), "No error should have been returned from UpdateRecipe")
+
+	foundRecipe, err := repo.FindRecipeById(recipe.Id)
+	assert.NoError(t, err, "No error should have been returned from FindRecipeById")
+	assert.Equal(t, &updatedRecipe, foundRecipe, "The recipe should have been replaced")
+
+	recipes, err := repo.FindPublicRecipes()
+	assert.NoError(t, err, "No error should have been returned from FindPublicRecipes")
+	assert.Equal(t, []string{recipe.Id}, recipeIds(recipes), "The recipe should have become public")
+}
+
+func (s RecipeRepositorySuite) testStaleUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	recipe := newRecipe("0", "user0", model.RecipePrivate)
+	assert.NoError(t, repo.CreateRecipe(recipe), "No error should have been returned from CreateRecipe")
+
+	staleRecipe := recipe
+	staleRecipe.Version = 2
+	err := repo.UpdateRecipe(staleRecipe, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned for a stale version; got %v", err)
+
+	missingRecipe := newRecipe("1", "user0", model.RecipePrivate)
+	err = repo.UpdateRecipe(missingRecipe, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned for a missing recipe; got %v", err)
+}
+
+func (s RecipeRepositorySuite) testDelete(t *testing.T) {
+	repo := s.NewRepository(t)
+	recipe := newRecipe("0", "user0", model.RecipePublic)
+	assert.NoError(t, repo.CreateRecipe(recipe), "No error should have been returned from CreateRecipe")
+
+	assert.NoError(t, repo.DeleteRecipe(recipe.Id), "No error should have been returned from DeleteRecipe")
+	foundRecipe, err := repo.FindRecipeById(recipe.Id)
+	assert.NoError(t, err, "No error should have been returned from FindRecipeById")
+	assert.Nil(t, foundRecipe, "The recipe should have been deleted")
+
+	assert.NoError(t, repo.DeleteRecipe(recipe.Id), "Deleting a missing recipe shouldn't return an error")
+}
+
+func (s RecipeRepositorySuite) testDeleteByOwner(t *testing.T) {
+	repo := s.NewRepository(t)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, repo.CreateRecipe(newRecipe(fmt.Sprint(i), "user0", model.RecipePublic)), "No error should have been returned from CreateRecipe")
+	}
+	assert.NoError(t, repo.CreateRecipe(newRecipe("9", "user1", model.RecipePublic)), "No error should have been returned from CreateRecipe")
+
+	assert.NoError(t, repo.DeleteRecipesByOwner("user0"), "No error should have been returned from DeleteRecipesByOwner")
+	recipes, err := repo.FindRecipesByOwner("user0")
+	assert.NoError(t, err, "No error should have been returned from FindRecipesByOwner")
+	assert.Empty(t, recipes, "The owner's recipes should have been deleted")
+
+	recipes, err = repo.FindPublicRecipes()
+	assert.NoError(t, err, "No error should have been returned from FindPublicRecipes")
+	assert.Equal(t, []string{model.RecipeIdPrefix + "9"}, recipeIds(recipes), "Other users' recipes should have been kept")
+}
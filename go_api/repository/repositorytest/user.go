@@ -0,0 +1,271 @@
+// Package repositorytest provides conformance suites that every implementation of the repository interfaces
+// must pass, so the api behaves the same whichever backend (or table design) it's configured with
+//
+// A backend runs a suite from its own tests by supplying a constructor that returns an empty repository:
+//
+//	repositorytest.UserRepositorySuite{
+//		NewRepository: func(t *testing.T) repository.UserRepository { ... },
+//	}.Run(t)
+package repositorytest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pageTestSize is the number of records created to check that reads return every page;
+// backends should be configured with a page size smaller than this so the check is meaningful
+const pageTestSize = 25
+
+// UserRepositorySuite checks the behavior that the services rely on from a UserRepository
+type UserRepositorySuite struct {
+	// NewRepository returns an empty repository for each test case
+	NewRepository func(t *testing.T) repository.UserRepository
+	// UniqueUsernames is set if the backend rejects a new user whose username is already taken;
+	// otherwise, the services are responsible for checking the username first,
+	// and the suite only checks that FindUserByUsername reports the duplicates
+	UniqueUsernames bool
+}
+
+// Run runs every test case of the suite as a subtest of t
+func (s UserRepositorySuite) Run(t *testing.T) {
+	t.Run("Creates and finds a user", s.testCreateAndFind)
+	t.Run("Returns nil for a missing user", s.testFindMissing)
+	t.Run("Rejects a duplicate id", s.testDuplicateId)
+	t.Run("Handles a duplicate username", s.testDuplicateUsername)
+	t.Run("Finds every user across pages", s.testFindAllPages)
+	t.Run("Updates a user", s.testUpdate)
+	t.Run("Rejects a stale update", s.testStaleUpdate)
+	t.Run("Rejects an update of a missing user", s.testUpdateMissing)
+	t.Run("Changes a username", s.testUsernameChange)
+	t.Run("Deletes a user", s.testDelete)
+	t.Run("Ignores the delete of a missing user", s.testDeleteMissing)
+}
+
+func newUser(id string) model.User {
+	return model.User{
+		Id:        id,
+		Username:  "username" + id,
+		Password:  "password" + id,
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   1,
+	}
+}
+
+func newEvent(eventType model.EventType, userId string) model.Event {
+	return model.Event{
+		Id:         fmt.Sprintf("event-%s-%s", eventType, userId),
+		Type:       eventType,
+		OccurredAt: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+		UserId:     userId,
+	}
+}
+
+func (s UserRepositorySuite) testCreateAndFind(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	err := repo.CreateNewUser(user, newEvent(model.UserCreated, user.Id))
+	assert.NoError(t, err, "No error should have been returned from CreateNewUser")
+
+	foundUser, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+	assert.Equal(t, &user, foundUser, "The created user should have been found by id")
+
+	foundUser, err = repo.FindUserByUsername(user.Username)
+	assert.NoError(t, err, "No error should have been returned from FindUserByUsername")
+	assert.Equal(t, &user, foundUser, "The created user should have been found by username")
+}
+
+func (s UserRepositorySuite) testFindMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+
+	foundUser, err := repo.FindUserById("missing")
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+	assert.Nil(t, foundUser, "No user should have been found by id")
+
+	foundUser, err = repo.FindUserByUsername("missing")
+	assert.NoError(t, err, "No error should have been returned from FindUserByUsername")
+	assert.Nil(t, foundUser, "No user should have been found by username")
+
+	users, err := repo.FindAll()
+	assert.NoError(t, err, "No error should have been returned from FindAll")
+	assert.Empty(t, users, "An empty repository shouldn't have any users")
+}
+
+func (s UserRepositorySuite) testDuplicateId(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	assert.NoError(t, repo.CreateNewUser(user), "No error should have been returned from CreateNewUser")
+
+	duplicate := user
+	duplicate.Username = "another username"
+	err := repo.CreateNewUser(duplicate)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned, got %v", err)
+
+	foundUser, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+	assert.Equal(t, &user, foundUser, "The original user shouldn't have been replaced")
+}
+
+func (s UserRepositorySuite) testDuplicateUsername(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	assert.NoError(t, repo.CreateNewUser(user), "No error should have been returned from CreateNewUser")
+
+	duplicate := newUser("1")
+	duplicate.Username = user.Username
+	err := repo.CreateNewUser(duplicate)
+	if s.UniqueUsernames {
+		assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned, got %v", err)
+		foundUser, err := repo.FindUserByUsername(user.Username)
+		assert.NoError(t, err, "No error should have been returned from FindUserByUsername")
+		assert.Equal(t, &user, foundUser, "The original user should still own the username")
+		return
+	}
+
+	assert.NoError(t, err, "No error should have been returned from CreateNewUser")
+	_, err = repo.FindUserByUsername(user.Username)
+	assert.Error(t, err, "An error should have been returned from FindUserByUsername for a duplicated username")
+}
+
+func (s UserRepositorySuite) testFindAllPages(t *testing.T) {
+	repo := s.NewRepository(t)
+	expectedIds := []string{}
+	for i := 0; i < pageTestSize; i++ {
+		user := newUser(fmt.Sprintf("%02d", i))
+		assert.NoError(t, repo.CreateNewUser(user), "No error should have been returned from CreateNewUser")
+		expectedIds = append(expectedIds, user.Id)
+	}
+
+	users, err := repo.FindAll()
+	assert.NoError(t, err, "No error should have been returned from FindAll")
+	ids := []string{}
+	for _, user := range users {
+		ids = append(ids, user.Id)
+	}
+	sort.Strings(ids)
+	assert.Equal(t, expectedIds, ids, "Every user should have been found")
+}
+
+func (s UserRepositorySuite) testUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	assert.NoError(t, repo.CreateNewUser(user), "No error should have been returned from CreateNewUser")
+	// read the user first, so a caching backend has to notice the update
+	_, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+
+	updatedUser := user
+	updatedUser.Password = "new password"
+	updatedUser.Units = "imperial"
+	updatedUser.DisplayName = "User Zero"
+	updatedUser.Bio = "Mostly sours."
+	updatedUser.Avatar = "avatars/0/avatar.png"
+	updatedUser.PublicFavorites = true
+	updatedUser.UpdatedAt = time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	updatedUser.Version = 2
+	err = repo.UpdateUser(updatedUser, user.Version)
+	assert.NoError(t, err, "No error should have been returned from UpdateUser")
+
+	foundUser, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+	assert.Equal(t, &updatedUser, foundUser, "The user should have been updated")
+}
+
+func (s UserRepositorySuite) testStaleUpdate(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	assert.NoError(t, repo.CreateNewUser(user), "No error should have been returned from CreateNewUser")
+
+	staleUser := user
+	staleUser.Password = "new password"
+	staleUser.Version = 1
+	err := repo.UpdateUser(staleUser, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned, got %v", err)
+
+	foundUser, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+	assert.Equal(t, &user, foundUser, "The user shouldn't have been updated")
+}
+
+func (s UserRepositorySuite) testUpdateMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	err := repo.UpdateUser(user, 0)
+	assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned, got %v", err)
+
+	foundUser, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+	assert.Nil(t, foundUser, "The update shouldn't have created the user")
+}
+
+func (s UserRepositorySuite) testUsernameChange(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	assert.NoError(t, repo.CreateNewUser(user), "No error should have been returned from CreateNewUser")
+	_, err := repo.FindUserByUsername(user.Username)
+	assert.NoError(t, err, "No error should have been returned from FindUserByUsername")
+
+	renamedUser := user
+	renamedUser.Username = "renamed"
+	renamedUser.Version = 2
+	err = repo.UpdateUser(renamedUser, user.Version)
+	assert.NoError(t, err, "No error should have been returned from UpdateUser")
+
+	foundUser, err := repo.FindUserByUsername(user.Username)
+	assert.NoError(t, err, "No error should have been returned from FindUserByUsername")
+	assert.Nil(t, foundUser, "The old username shouldn't find the user anymore")
+
+	foundUser, err = repo.FindUserByUsername(renamedUser.Username)
+	assert.NoError(t, err, "No error should have been returned from FindUserByUsername")
+	assert.Equal(t, &renamedUser, foundUser, "The new username should find the user")
+
+	if s.UniqueUsernames {
+		// the old username is free again
+		err = repo.CreateNewUser(model.User{Id: "1", Username: user.Username, Version: 1})
+		assert.NoError(t, err, "The old username should be available for a new user")
+	}
+}
+
+func (s UserRepositorySuite) testDelete(t *testing.T) {
+	repo := s.NewRepository(t)
+	user := newUser("0")
+	assert.NoError(t, repo.CreateNewUser(user), "No error should have been returned from CreateNewUser")
+	_, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+
+	err = repo.DeleteUser(user.Id, newEvent(model.UserDeleted, user.Id))
+	assert.NoError(t, err, "No error should have been returned from DeleteUser")
+
+	foundUser, err := repo.FindUserById(user.Id)
+	assert.NoError(t, err, "No error should have been returned from FindUserById")
+	assert.Nil(t, foundUser, "The user should have been deleted")
+
+	foundUser, err = repo.FindUserByUsername(user.Username)
+	assert.NoError(t, err, "No error should have been returned from FindUserByUsername")
+	assert.Nil(t, foundUser, "The user shouldn't be found by username after being deleted")
+
+	if s.UniqueUsernames {
+		err = repo.CreateNewUser(newUser("0"))
+		assert.NoError(t, err, "The deleted user's id and username should be available again")
+	}
+}
+
+func (s UserRepositorySuite) testDeleteMissing(t *testing.T) {
+	repo := s.NewRepository(t)
+	err := repo.DeleteUser("missing")
+	assert.NoError(t, err, "No error should have been returned from DeleteUser")
+
+	err = repo.DeleteUser("missing", newEvent(model.UserDeleted, "missing"))
+	assert.NoError(t, err, "No error should have been returned from DeleteUser with events")
+}
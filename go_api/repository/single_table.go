@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// The single-table design stores every record in one table, keyed on the generic PK and SK attributes:
+//
+//	record            PK                   SK                   GSI1PK               GSI1SK     GSI2PK        GSI2SK
+//	user profile      USER#<id>            PROFILE              USERNAME#<username>  PROFILE
+//	username          USERNAME#<username>  USERNAME
+//	favorite          USER#<user id>       FAVORITE#<drink id>  FAVORITE#<id>        FAVORITE   USER#<user>   <created_at>
+//	outbox event      OUTBOX               <occurred_at>#<id>
+//	expiring record   EXPIRING#<key>       RECORD
+//	drink             DRINK#<id>           DRINK
+//	inventory item    USER#<user id>       INVENTORY#<ingredient>
+//	recipe            RECIPE#<id>          RECIPE               USER#<owner id>      RECIPE#<id>
+//
+// A user's profile, favorites and inventory share a partition, so they can be read with a single Query;
+// the username records make usernames unique, since they're written in the same transaction as the profile.
+// Expiring records are deleted by the table's time to live on expires_at.
+// GSI1 is overloaded to look up users by username, favorites by id and recipes by owner, and GSI2 sorts a user's favorites by creation
+const (
+	singleTableHashKey  = "PK"
+	singleTableRangeKey = "SK"
+	gsi1Name            = "GSI1"
+	gsi1HashKey         = "GSI1PK"
+	gsi1RangeKey        = "GSI1SK"
+	gsi2Name            = "GSI2"
+	gsi2HashKey         = "GSI2PK"
+	gsi2RangeKey        = "GSI2SK"
+	recordTypeAttribute = "type"
+
+	userKeyPrefix      = "USER#"
+	usernameKeyPrefix  = "USERNAME#"
+	favoriteKeyPrefix  = "FAVORITE#"
+	profileSortKey     = "PROFILE"
+	usernameSortKey    = "USERNAME"
+	favoriteSortKey    = "FAVORITE"
+	outboxPartitionKey = "OUTBOX"
+	expiringKeyPrefix  = "EXPIRING#"
+	expiringSortKey    = "RECORD"
+	drinkKeyPrefix     = "DRINK#"
+	drinkSortKey       = "DRINK"
+	inventoryKeyPrefix = "INVENTORY#"
+	recipeKeyPrefix    = "RECIPE#"
+	recipeSortKey      = "RECIPE"
+
+	userRecordType      = "user"
+	usernameRecordType  = "username"
+	favoriteRecordType  = "favorite"
+	outboxRecordType    = "outbox"
+	expiringRecordType  = "expiring"
+	drinkRecordType     = "drink"
+	inventoryRecordType = "inventory"
+	recipeRecordType    = "recipe"
+)
+
+// NewRepositories creates the user and favorite repositories for the table design selected by the app config
+func NewRepositories(appConfig model.AppConfig) (UserRepository, FavoriteRepository, error) {
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
+	if appConfig.TableDesign == model.SingleTableDesign {
+		return &SingleTableUserRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName},
+			&SingleTableFavoriteRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.SingleTableName},
+			err
+	}
+	return &UserRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.UsersTableName, OutboxTableName: appConfig.OutboxTableName},
+		&FavoriteRepositoryDDB{DynamodbClient: ddbClient, TableName: appConfig.FavoritesTableName, OutboxTableName: appConfig.OutboxTableName},
+		err
+}
+
+func singleTableKey(pk, sk string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		singleTableHashKey:  &types.AttributeValueMemberS{Value: pk},
+		singleTableRangeKey: &types.AttributeValueMemberS{Value: sk},
+	}
+}
+
+func userProfileKey(userId string) map[string]types.AttributeValue {
+	return singleTableKey(userKeyPrefix+userId, profileSortKey)
+}
+
+func usernameRecordKey(username string) map[string]types.AttributeValue {
+	return singleTableKey(usernameKeyPrefix+username, usernameSortKey)
+}
+
+func userFavoriteKey(userId, drinkId string) map[string]types.AttributeValue {
+	return singleTableKey(userKeyPrefix+userId, favoriteKeyPrefix+drinkId)
+}
+
+func drinkKey(drinkId string) map[string]types.AttributeValue {
+	return singleTableKey(drinkKeyPrefix+drinkId, drinkSortKey)
+}
+
+func inventoryItemKey(userId, ingredient string) map[string]types.AttributeValue {
+	return singleTableKey(userKeyPrefix+userId, inventoryKeyPrefix+ingredient)
+}
+
+func recipeKey(recipeId string) map[string]types.AttributeValue {
+	return singleTableKey(recipeKeyPrefix+recipeId, recipeSortKey)
+}
+
+// withAttributes adds the attributes to the item, which is returned for convenience
+func withAttributes(item map[string]types.AttributeValue, attributes map[string]string) map[string]types.AttributeValue {
+	for name, value := range attributes {
+		item[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return item
+}
+
+// scanRecords reads every item of the given record type, following the scan's pagination,
+// since the filter may leave entire pages empty
+func scanRecords(db client.DDBClient, tableName, recordType string, out interface{}) error {
+	filterExpression, err := expression.NewBuilder().WithFilter(
+		expression.Name(recordTypeAttribute).Equal(expression.Value(recordType)),
+	).Build()
+	if err != nil {
+		return err
+	}
+
+	items, err := scanPages(db, &dynamodb.ScanInput{
+		TableName:                 aws.String(tableName),
+		FilterExpression:          filterExpression.Filter(),
+		ExpressionAttributeNames:  filterExpression.Names(),
+		ExpressionAttributeValues: filterExpression.Values(),
+	})
+	if err != nil {
+		return err
+	}
+	return attributevalue.UnmarshalListOfMaps(items, out)
+}
+
+// getRecord reads the item with the given key into out, returning false if it doesn't exist
+func getRecord(db client.DDBClient, tableName string, key map[string]types.AttributeValue, out interface{}) (bool, error) {
+	getItemOutput, err := db.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName:      aws.String(tableName),
+		Key:            key,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return false, err
+	}
+	if getItemOutput.Item == nil {
+		return false, nil
+	}
+	return true, attributevalue.UnmarshalMap(getItemOutput.Item, out)
+}
+
+// canceledConditions returns which items of a canceled transaction failed their condition, in the order of the items,
+// or nil if the error isn't a TransactionCanceledException
+func canceledConditions(err error) []bool {
+	var transactionCanceled *types.TransactionCanceledException
+	if !errors.As(err, &transactionCanceled) {
+		return nil
+	}
+	failed := make([]bool, len(transactionCanceled.CancellationReasons))
+	for i, reason := range transactionCanceled.CancellationReasons {
+		failed[i] = aws.ToString(reason.Code) == "ConditionalCheckFailed"
+	}
+	return failed
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SingleTableDrinkRepositoryDDB is the DrinkRepository for the single-table design (see single_table.go)
+type SingleTableDrinkRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *SingleTableDrinkRepositoryDDB) FindAll() ([]model.Drink, error) {
+	drinks := []model.Drink{}
+	if err := scanRecords(r.DynamodbClient, r.TableName, drinkRecordType, &drinks); err != nil {
+		return nil, err
+	}
+	return drinks, nil
+}
+
+func (r *SingleTableDrinkRepositoryDDB) FindDrinkById(id string) (*model.Drink, error) {
+	drink := model.Drink{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, drinkKey(id), &drink)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &drink, nil
+}
+
+func (r *SingleTableDrinkRepositoryDDB) FindDrinksByIds(ids []string) (map[string]model.Drink, error) {
+	keys := []map[string]types.AttributeValue{}
+	for _, id := range distinctIds(ids) {
+		keys = append(keys, drinkKey(id))
+	}
+	return batchGetDrinks(r.DynamodbClient, r.TableName, keys)
+}
+
+func (r *SingleTableDrinkRepositoryDDB) SaveDrink(drink model.Drink) error {
+	item, err := attributevalue.MarshalMap(drink)
+	if err != nil {
+		return err
+	}
+	for name, value := range drinkKey(drink.Id) {
+		item[name] = value
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName: aws.String(r.TableName),
+		Item:      withAttributes(item, map[string]string{recordTypeAttribute: drinkRecordType}),
+	})
+	return err
+}
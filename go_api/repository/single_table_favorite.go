@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SingleTableFavoriteRepositoryDDB is the FavoriteRepository for the single-table design (see single_table.go)
+type SingleTableFavoriteRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *SingleTableFavoriteRepositoryDDB) FindAll() ([]model.Favorite, error) {
+	favorites := []model.Favorite{}
+	if err := scanRecords(r.DynamodbClient, r.TableName, favoriteRecordType, &favorites); err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+// FindFavoritesByUser reads the favorites in the user's partition, ordered by drink id
+func (r *SingleTableFavoriteRepositoryDDB) FindFavoritesByUser(userId string) ([]model.Favorite, error) {
+	keyCondition := expression.Key(singleTableHashKey).Equal(expression.Value(userKeyPrefix + userId)).And(
+		expression.Key(singleTableRangeKey).BeginsWith(favoriteKeyPrefix),
+	)
+	return r.query(keyCondition, "", true)
+}
+
+// FindFavoritesByUserSortedByCreation reads the user's favorites from GSI2, which uses created_at as its sort key
+func (r *SingleTableFavoriteRepositoryDDB) FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error) {
+	keyCondition := expression.Key(gsi2HashKey).Equal(expression.Value(userKeyPrefix + userId))
+	return r.query(keyCondition, gsi2Name, !newestFirst)
+}
+
+// FindFavoriteByUserAndDrink reads the favorite directly by its key, or returns nil if the user hasn't favorited the drink
+func (r *SingleTableFavoriteRepositoryDDB) FindFavoriteByUserAndDrink(userId, drinkId string) (*model.Favorite, error) {
+	favorite := model.Favorite{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, userFavoriteKey(userId, drinkId), &favorite)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &favorite, nil
+}
+
+// FindFavoriteById looks up the favorite on GSI1, or returns nil if no favorite exists with that id
+func (r *SingleTableFavoriteRepositoryDDB) FindFavoriteById(id string) (*model.Favorite, error) {
+	favorites, err := r.query(expression.Key(gsi1HashKey).Equal(expression.Value(favoriteKeyPrefix+id)), gsi1Name, true)
+	if err != nil {
+		return nil, err
+	}
+	switch len(favorites) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &favorites[0], nil
+	default:
+		return nil, fmt.Errorf("there are %d favorites with the id '%s'", len(favorites), id)
+	}
+}
+
+// CreateNewFavorite inserts the favorite unless the user already favorited the drink,
+// in which case the FavoriteAlreadyExistsError is returned
+func (r *SingleTableFavoriteRepositoryDDB) CreateNewFavorite(favorite model.Favorite, events ...model.Event) error {
+	put := &types.Put{
+		TableName:           aws.String(r.TableName),
+		Item:                r.item(favorite),
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	}
+	var err error
+	conditionFailed := false
+	if len(events) == 0 {
+		_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+			TableName:           put.TableName,
+			Item:                put.Item,
+			ConditionExpression: put.ConditionExpression,
+		})
+		conditionFailed = isConditionalCheckFailed(err)
+	} else {
+		conditionFailed, err = writeWithEvents(r.DynamodbClient, types.TransactWriteItem{Put: put}, singleTableOutboxPuts(r.TableName, events))
+	}
+	if conditionFailed {
+		return apperrors.NewFavoriteAlreadyExistsError("the user already favorited this drink")
+	}
+	return err
+}
+
+// UpdateFavorite replaces the favorite as long as the stored version still matches expectedVersion;
+// if another request modified the favorite first, the ConflictError is returned
+//
+// The caller is responsible for incrementing favorite.Version
+func (r *SingleTableFavoriteRepositoryDDB) UpdateFavorite(favorite model.Favorite, expectedVersion int) error {
+	condition, err := versionCondition(expectedVersion)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(r.TableName),
+		Item:                      r.item(favorite),
+		ConditionExpression:       condition.Condition(),
+		ExpressionAttributeNames:  condition.Names(),
+		ExpressionAttributeValues: condition.Values(),
+	})
+	if isConditionalCheckFailed(err) {
+		return apperrors.NewConflictError(fmt.Sprintf("the favorite '%s' doesn't exist or was modified by another request", favorite.Id), err)
+	}
+	return err
+}
+
+// DeleteFavorite looks up the favorite's key by its id before deleting it and recording the events;
+// deleting a favorite that doesn't exist does nothing
+func (r *SingleTableFavoriteRepositoryDDB) DeleteFavorite(id string, events ...model.Event) error {
+	favorite, err := r.FindFavoriteById(id)
+	if err != nil || favorite == nil {
+		return err
+	}
+	key := userFavoriteKey(favorite.UserId, favorite.DrinkId)
+	if len(events) == 0 {
+		_, err = r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+			TableName: aws.String(r.TableName),
+			Key:       key,
+		})
+		return err
+	}
+
+	conditionFailed, err := writeWithEvents(r.DynamodbClient, types.TransactWriteItem{Delete: &types.Delete{
+		TableName:           aws.String(r.TableName),
+		Key:                 key,
+		ConditionExpression: aws.String("attribute_exists(PK)"),
+	}}, singleTableOutboxPuts(r.TableName, events))
+	if conditionFailed {
+		return nil
+	}
+	return err
+}
+
+func (r *SingleTableFavoriteRepositoryDDB) query(keyCondition expression.KeyConditionBuilder, indexName string, ascending bool) ([]model.Favorite, error) {
+	keyExpression, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, err
+	}
+	queryInput := dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		ExpressionAttributeNames:  keyExpression.Names(),
+		ExpressionAttributeValues: keyExpression.Values(),
+		KeyConditionExpression:    keyExpression.KeyCondition(),
+		ScanIndexForward:          aws.Bool(ascending),
+	}
+	if indexName != "" {
+		queryInput.IndexName = aws.String(indexName)
+	}
+
+	items, err := queryPages(r.DynamodbClient, &queryInput)
+	if err != nil {
+		return nil, err
+	}
+	favorites := []model.Favorite{}
+	err = attributevalue.UnmarshalListOfMaps(items, &favorites)
+	if err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+func (r *SingleTableFavoriteRepositoryDDB) item(favorite model.Favorite) map[string]types.AttributeValue {
+	return withAttributes(favoriteItem(favorite), map[string]string{
+		singleTableHashKey:  userKeyPrefix + favorite.UserId,
+		singleTableRangeKey: favoriteKeyPrefix + favorite.DrinkId,
+		gsi1HashKey:         favoriteKeyPrefix + favorite.Id,
+		gsi1RangeKey:        favoriteSortKey,
+		gsi2HashKey:         userKeyPrefix + favorite.UserId,
+		gsi2RangeKey:        model.FormatTimestamp(favorite.CreatedAt),
+		recordTypeAttribute: favoriteRecordType,
+	})
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SingleTableInventoryRepositoryDDB is the InventoryRepository for the single-table design (see single_table.go)
+type SingleTableInventoryRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+// FindInventoryByUser reads the items in the user's partition, which are ordered by ingredient
+func (r *SingleTableInventoryRepositoryDDB) FindInventoryByUser(userId string) ([]model.InventoryItem, error) {
+	keyCondition, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key(singleTableHashKey).Equal(expression.Value(userKeyPrefix + userId)).And(
+			expression.Key(singleTableRangeKey).BeginsWith(inventoryKeyPrefix),
+		),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	items, err := queryPages(r.DynamodbClient, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		KeyConditionExpression:    keyCondition.KeyCondition(),
+		ExpressionAttributeNames:  keyCondition.Names(),
+		ExpressionAttributeValues: keyCondition.Values(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := []model.InventoryItem{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &inventory); err != nil {
+		return nil, err
+	}
+	return inventory, nil
+}
+
+func (r *SingleTableInventoryRepositoryDDB) FindInventoryItem(userId, ingredient string) (*model.InventoryItem, error) {
+	item := model.InventoryItem{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, inventoryItemKey(userId, ingredient), &item)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *SingleTableInventoryRepositoryDDB) CreateInventoryItem(item model.InventoryItem) error {
+	record, err := r.item(item)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:           aws.String(r.TableName),
+		Item:                record,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return apperrors.NewInventoryItemAlreadyExistsError(item.Name)
+	}
+	return err
+}
+
+func (r *SingleTableInventoryRepositoryDDB) UpdateInventoryItem(item model.InventoryItem, expectedVersion int) error {
+	record, err := r.item(item)
+	if err != nil {
+		return err
+	}
+	return putInventoryItem(r.DynamodbClient, r.TableName, record, singleTableHashKey, item, expectedVersion)
+}
+
+func (r *SingleTableInventoryRepositoryDDB) DeleteInventoryItem(userId, ingredient string) error {
+	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       inventoryItemKey(userId, ingredient),
+	})
+	return err
+}
+
+func (r *SingleTableInventoryRepositoryDDB) DeleteInventoryByUser(userId string) error {
+	inventory, err := r.FindInventoryByUser(userId)
+	if err != nil {
+		return err
+	}
+	for _, item := range inventory {
+		if err := r.DeleteInventoryItem(userId, item.Ingredient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SingleTableInventoryRepositoryDDB) item(item model.InventoryItem) (map[string]types.AttributeValue, error) {
+	record, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range inventoryItemKey(item.UserId, item.Ingredient) {
+		record[name] = value
+	}
+	return withAttributes(record, map[string]string{recordTypeAttribute: inventoryRecordType}), nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SingleTableRecipeRepositoryDDB is the RecipeRepository for the single-table design (see single_table.go)
+type SingleTableRecipeRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *SingleTableRecipeRepositoryDDB) FindRecipeById(id string) (*model.Recipe, error) {
+	recipe := model.Recipe{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, recipeKey(id), &recipe)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// FindRecipesByOwner looks up the owner's recipes on GSI1
+func (r *SingleTableRecipeRepositoryDDB) FindRecipesByOwner(ownerId string) ([]model.Recipe, error) {
+	keyCondition, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key(gsi1HashKey).Equal(expression.Value(userKeyPrefix + ownerId)).And(
+			expression.Key(gsi1RangeKey).BeginsWith(recipeKeyPrefix),
+		),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+	items, err := queryPages(r.DynamodbClient, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		IndexName:                 aws.String(gsi1Name),
+		KeyConditionExpression:    keyCondition.KeyCondition(),
+		ExpressionAttributeNames:  keyCondition.Names(),
+		ExpressionAttributeValues: keyCondition.Values(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	recipes := []model.Recipe{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &recipes); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// FindPublicRecipes scans the recipes, since public recipes are only read to build the search index
+func (r *SingleTableRecipeRepositoryDDB) FindPublicRecipes() ([]model.Recipe, error) {
+	recipes := []model.Recipe{}
+	if err := scanRecords(r.DynamodbClient, r.TableName, recipeRecordType, &recipes); err != nil {
+		return nil, err
+	}
+	publicRecipes := []model.Recipe{}
+	for _, recipe := range recipes {
+		if recipe.Visibility == model.RecipePublic {
+			publicRecipes = append(publicRecipes, recipe)
+		}
+	}
+	return publicRecipes, nil
+}
+
+func (r *SingleTableRecipeRepositoryDDB) CreateRecipe(recipe model.Recipe) error {
+	record, err := r.item(recipe)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:           aws.String(r.TableName),
+		Item:                record,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if isConditionalCheckFailed(err) {
+		return recipeAlreadyExistsError(recipe, err)
+	}
+	return err
+}
+
+func (r *SingleTableRecipeRepositoryDDB) UpdateRecipe(recipe model.Recipe, expectedVersion int) error {
+	record, err := r.item(recipe)
+	if err != nil {
+		return err
+	}
+	return putRecipe(r.DynamodbClient, r.TableName, record, singleTableHashKey, recipe, expectedVersion)
+}
+
+func (r *SingleTableRecipeRepositoryDDB) DeleteRecipe(id string) error {
+	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.TableName),
+		Key:       recipeKey(id),
+	})
+	return err
+}
+
+func (r *SingleTableRecipeRepositoryDDB) DeleteRecipesByOwner(ownerId string) error {
+	recipes, err := r.FindRecipesByOwner(ownerId)
+	if err != nil {
+		return err
+	}
+	for _, recipe := range recipes {
+		if err := r.DeleteRecipe(recipe.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SingleTableRecipeRepositoryDDB) item(recipe model.Recipe) (map[string]types.AttributeValue, error) {
+	record, err := attributevalue.MarshalMap(recipe)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range recipeKey(recipe.Id) {
+		record[name] = value
+	}
+	return withAttributes(record, map[string]string{
+		gsi1HashKey:         userKeyPrefix + recipe.OwnerId,
+		gsi1RangeKey:        recipeKeyPrefix + recipe.Id,
+		recordTypeAttribute: recipeRecordType,
+	}), nil
+}
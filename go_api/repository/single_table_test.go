@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+)
+
+var (
+	singleTableUser = model.User{
+		Id:        "0",
+		Username:  "username0",
+		Password:  "password0",
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   1,
+	}
+	singleTableFavorite = model.Favorite{
+		Id:        "favorite0",
+		UserId:    "0",
+		DrinkId:   "11007",
+		CreatedAt: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+		Version:   1,
+	}
+)
+
+func stringAttribute(item map[string]types.AttributeValue, name string) string {
+	value, _ := item[name].(*types.AttributeValueMemberS)
+	if value == nil {
+		return ""
+	}
+	return value.Value
+}
+
+func TestSingleTableUserRepositoryDDB_CreateNewUser(t *testing.T) {
+	tests := []struct {
+		name                string
+		returnedError       error
+		expectError         bool
+		expectConflictError bool
+	}{
+		{
+			name: "Successfully created a user",
+		},
+		{
+			name: "User already exists with the same id",
+			returnedError: &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+				{Code: aws.String("ConditionalCheckFailed")}, {Code: aws.String("None")},
+			}},
+			expectError:         true,
+			expectConflictError: true,
+		},
+		{
+			name: "User already exists with the same username",
+			returnedError: &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+				{Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")},
+			}},
+			expectError:         true,
+			expectConflictError: true,
+		},
+		{
+			name:          "Failed to create a user",
+			returnedError: fmt.Errorf("failed to create the user"),
+			expectError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("TransactWriteItems", context.TODO(), mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				if len(input.TransactItems) != 2 {
+					return false
+				}
+				profile, username := input.TransactItems[0].Put.Item, input.TransactItems[1].Put.Item
+				return stringAttribute(profile, "PK") == "USER#0" && stringAttribute(profile, "SK") == "PROFILE" &&
+					stringAttribute(profile, "GSI1PK") == "USERNAME#username0" && stringAttribute(profile, "password") == "password0" &&
+					stringAttribute(username, "PK") == "USERNAME#username0" && stringAttribute(username, "user_id") == "0" &&
+					*input.TransactItems[1].Put.ConditionExpression == "attribute_not_exists(PK)"
+			})).Return(&dynamodb.TransactWriteItemsOutput{}, tt.returnedError)
+			userStore := SingleTableUserRepositoryDDB{DynamodbClient: mockDdbClient}
+			err := userStore.CreateNewUser(singleTableUser)
+			assert.Equal(t, tt.expectError, err != nil, "SingleTableUserRepositoryDDB.CreateNewUser() error = %v", err)
+			assert.Equal(t, tt.expectConflictError, errors.As(err, &apperrors.ConflictError{}))
+		})
+	}
+}
+
+func TestSingleTableUserRepositoryDDB_UpdateUser(t *testing.T) {
+	storedItem := (&SingleTableUserRepositoryDDB{}).profileItem(singleTableUser)
+	renamedUser := singleTableUser
+	renamedUser.Username = "username1"
+	renamedUser.Version = 2
+	tests := []struct {
+		name                string
+		user                model.User
+		storedItem          map[string]types.AttributeValue
+		expectedItems       int
+		returnedError       error
+		expectError         bool
+		expectConflictError bool
+	}{
+		{
+			name:          "Username is unchanged",
+			user:          model.User{Id: "0", Username: "username0", Version: 2},
+			storedItem:    storedItem,
+			expectedItems: 1,
+		},
+		{
+			name:          "Username records are swapped when the username changes",
+			user:          renamedUser,
+			storedItem:    storedItem,
+			expectedItems: 3,
+		},
+		{
+			name:          "New username is already taken",
+			user:          renamedUser,
+			storedItem:    storedItem,
+			expectedItems: 3,
+			returnedError: &types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+				{Code: aws.String("None")}, {Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")},
+			}},
+			expectError:         true,
+			expectConflictError: true,
+		},
+		{
+			name:                "User doesn't exist",
+			user:                renamedUser,
+			expectError:         true,
+			expectConflictError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("GetItem", context.TODO(), &dynamodb.GetItemInput{
+				TableName:      aws.String(""),
+				Key:            userProfileKey("0"),
+				ConsistentRead: aws.Bool(true),
+			}).Return(&dynamodb.GetItemOutput{Item: tt.storedItem}, nil)
+			if tt.expectedItems > 0 {
+				mockDdbClient.On("TransactWriteItems", context.TODO(), mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+					if len(input.TransactItems) != tt.expectedItems {
+						return false
+					}
+					if tt.expectedItems == 3 {
+						return stringAttribute(input.TransactItems[1].Delete.Key, "PK") == "USERNAME#username0" &&
+							stringAttribute(input.TransactItems[2].Put.Item, "PK") == "USERNAME#username1"
+					}
+					return true
+				})).Return(&dynamodb.TransactWriteItemsOutput{}, tt.returnedError)
+			}
+			userStore := SingleTableUserRepositoryDDB{DynamodbClient: mockDdbClient}
+			err := userStore.UpdateUser(tt.user, 1)
+			assert.Equal(t, tt.expectError, err != nil, "SingleTableUserRepositoryDDB.UpdateUser() error = %v", err)
+			assert.Equal(t, tt.expectConflictError, errors.As(err, &apperrors.ConflictError{}))
+		})
+	}
+}
+
+func TestSingleTableUserRepositoryDDB_FindUserWithFavorites(t *testing.T) {
+	userRepository := &SingleTableUserRepositoryDDB{}
+	favoriteRepository := &SingleTableFavoriteRepositoryDDB{}
+	mockDdbClient := client.NewMockDDBClient(t)
+	mockDdbClient.On("Query", context.TODO(), mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return input.IndexName == nil && input.ExclusiveStartKey == nil
+	})).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{favoriteRepository.item(singleTableFavorite)},
+		LastEvaluatedKey: userFavoriteKey("0", "11007"),
+	}, nil).Once()
+	mockDdbClient.On("Query", context.TODO(), mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return input.ExclusiveStartKey != nil
+	})).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{userRepository.profileItem(singleTableUser)},
+	}, nil).Once()
+	userRepository.DynamodbClient = mockDdbClient
+
+	user, favorites, err := userRepository.FindUserWithFavorites("0")
+	assert.NoError(t, err)
+	assert.Equal(t, &singleTableUser, user)
+	assert.Equal(t, []model.Favorite{singleTableFavorite}, favorites)
+}
+
+func TestSingleTableFavoriteRepositoryDDB_FindAll(t *testing.T) {
+	favoriteRepository := &SingleTableFavoriteRepositoryDDB{}
+	mockDdbClient := client.NewMockDDBClient(t)
+	// filtered scans can return empty pages, so every page has to be read
+	mockDdbClient.On("Scan", context.TODO(), mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+		return input.ExclusiveStartKey == nil && *input.FilterExpression == "#0 = :0"
+	})).Return(&dynamodb.ScanOutput{LastEvaluatedKey: userProfileKey("0")}, nil).Once()
+	mockDdbClient.On("Scan", context.TODO(), mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+		return input.ExclusiveStartKey != nil
+	})).Return(&dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{favoriteRepository.item(singleTableFavorite)},
+	}, nil).Once()
+	favoriteRepository.DynamodbClient = mockDdbClient
+
+	favorites, err := favoriteRepository.FindAll()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Favorite{singleTableFavorite}, favorites)
+}
+
+func TestSingleTableFavoriteRepositoryDDB_CreateNewFavorite(t *testing.T) {
+	tests := []struct {
+		name                    string
+		returnedError           error
+		expectAlreadyExistError bool
+	}{
+		{
+			name: "Successfully created a favorite",
+		},
+		{
+			name:                    "User already favorited the drink",
+			returnedError:           &types.ConditionalCheckFailedException{},
+			expectAlreadyExistError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("PutItem", context.TODO(), mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+				return stringAttribute(input.Item, "PK") == "USER#0" &&
+					stringAttribute(input.Item, "SK") == "FAVORITE#11007" &&
+					stringAttribute(input.Item, "GSI1PK") == "FAVORITE#favorite0" &&
+					stringAttribute(input.Item, "GSI2SK") == "2023-01-03T00:00:00.000Z" &&
+					*input.ConditionExpression == "attribute_not_exists(PK)"
+			})).Return(&dynamodb.PutItemOutput{}, tt.returnedError)
+			favoriteStore := SingleTableFavoriteRepositoryDDB{DynamodbClient: mockDdbClient}
+			err := favoriteStore.CreateNewFavorite(singleTableFavorite)
+			assert.Equal(t, tt.expectAlreadyExistError, err != nil)
+			assert.Equal(t, tt.expectAlreadyExistError, errors.As(err, &apperrors.FavoriteAlreadyExistsError{}))
+		})
+	}
+}
+
+func TestSingleTableFavoriteRepositoryDDB_DeleteFavorite(t *testing.T) {
+	tests := []struct {
+		name         string
+		queryOutput  *dynamodb.QueryOutput
+		expectDelete bool
+	}{
+		{
+			name:         "Successfully deleted a favorite",
+			queryOutput:  &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{(&SingleTableFavoriteRepositoryDDB{}).item(singleTableFavorite)}},
+			expectDelete: true,
+		},
+		{
+			name:        "Favorite doesn't exist",
+			queryOutput: &dynamodb.QueryOutput{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("Query", context.TODO(), mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+				return *input.IndexName == "GSI1"
+			})).Return(tt.queryOutput, nil)
+			if tt.expectDelete {
+				mockDdbClient.On("DeleteItem", context.TODO(), &dynamodb.DeleteItemInput{
+					TableName: aws.String(""),
+					Key:       userFavoriteKey("0", "11007"),
+				}).Return(&dynamodb.DeleteItemOutput{}, nil)
+			}
+			favoriteStore := SingleTableFavoriteRepositoryDDB{DynamodbClient: mockDdbClient}
+			assert.NoError(t, favoriteStore.DeleteFavorite("favorite0"))
+		})
+	}
+}
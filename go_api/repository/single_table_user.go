@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository/client"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SingleTableUserRepositoryDDB is the UserRepository for the single-table design (see single_table.go)
+type SingleTableUserRepositoryDDB struct {
+	DynamodbClient client.DDBClient
+	TableName      string
+}
+
+func (r *SingleTableUserRepositoryDDB) FindAll() ([]model.User, error) {
+	users := []model.User{}
+	if err := scanRecords(r.DynamodbClient, r.TableName, userRecordType, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindUserById retrieves the user's profile, or nil if no user exists with that id
+func (r *SingleTableUserRepositoryDDB) FindUserById(userId string) (*model.User, error) {
+	user := model.User{}
+	found, err := getRecord(r.DynamodbClient, r.TableName, userProfileKey(userId), &user)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindUserByUsername looks up the user's profile on GSI1, or returns nil if no user has that username
+func (r *SingleTableUserRepositoryDDB) FindUserByUsername(username string) (*model.User, error) {
+	keyExpression, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key(gsi1HashKey).Equal(expression.Value(usernameKeyPrefix + username)),
+	).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := queryPages(r.DynamodbClient, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		IndexName:                 aws.String(gsi1Name),
+		ExpressionAttributeNames:  keyExpression.Names(),
+		ExpressionAttributeValues: keyExpression.Values(),
+		KeyConditionExpression:    keyExpression.KeyCondition(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	users := []model.User{}
+	err = attributevalue.UnmarshalListOfMaps(items, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(users) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &users[0], nil
+	default:
+		return nil, fmt.Errorf("there are %d users with the username '%s'", len(users), username)
+	}
+}
+
+// FindUserWithFavorites reads the user's profile and all of their favorites with a single Query;
+// the user is nil if it doesn't exist, though favorites may still be returned for a deleted user
+func (r *SingleTableUserRepositoryDDB) FindUserWithFavorites(userId string) (*model.User, []model.Favorite, error) {
+	keyExpression, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key(singleTableHashKey).Equal(expression.Value(userKeyPrefix + userId)),
+	).Build()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := queryPages(r.DynamodbClient, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.TableName),
+		ExpressionAttributeNames:  keyExpression.Names(),
+		ExpressionAttributeValues: keyExpression.Values(),
+		KeyConditionExpression:    keyExpression.KeyCondition(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var user *model.User
+	favorites := []model.Favorite{}
+	for _, item := range items {
+		sortKey, _ := item[singleTableRangeKey].(*types.AttributeValueMemberS)
+		switch {
+		case sortKey == nil:
+			continue
+		case sortKey.Value == profileSortKey:
+			user = &model.User{}
+			err = attributevalue.UnmarshalMap(item, user)
+		case strings.HasPrefix(sortKey.Value, favoriteKeyPrefix):
+			favorite := model.Favorite{}
+			err = attributevalue.UnmarshalMap(item, &favorite)
+			favorites = append(favorites, favorite)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return user, favorites, nil
+}
+
+// CreateNewUser writes the user's profile, username record and events in a single transaction,
+// so it fails with the ConflictError if either the id or the username is already taken
+func (r *SingleTableUserRepositoryDDB) CreateNewUser(user model.User, events ...model.Event) error {
+	_, err := r.DynamodbClient.TransactWriteItems(context.TODO(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
+			{Put: &types.Put{
+				TableName:           aws.String(r.TableName),
+				Item:                r.profileItem(user),
+				ConditionExpression: aws.String("attribute_not_exists(PK)"),
+			}},
+			{Put: &types.Put{
+				TableName:           aws.String(r.TableName),
+				Item:                r.usernameItem(user),
+				ConditionExpression: aws.String("attribute_not_exists(PK)"),
+			}},
+		}, singleTableOutboxPuts(r.TableName, events)...),
+	})
+	if failed := canceledConditions(err); len(failed) >= 2 {
+		if failed[0] {
+			return apperrors.NewConflictError(fmt.Sprintf("a user already exists with the id '%s'", user.Id), err)
+		}
+		if failed[1] {
+			return apperrors.NewConflictError(fmt.Sprintf("a user already exists with the username '%s'", user.Username), err)
+		}
+	}
+	return err
+}
+
+// UpdateUser replaces the user's profile as long as the stored version still matches expectedVersion;
+// if the username changes, the username records are swapped in the same transaction
+//
+// The caller is responsible for incrementing user.Version
+func (r *SingleTableUserRepositoryDDB) UpdateUser(user model.User, expectedVersion int) error {
+	storedUser, err := r.FindUserById(user.Id)
+	if err != nil {
+		return err
+	}
+	if storedUser == nil {
+		return apperrors.NewConflictError(fmt.Sprintf("the user '%s' doesn't exist or was modified by another request", user.Id), nil)
+	}
+
+	condition, err := versionCondition(expectedVersion)
+	if err != nil {
+		return err
+	}
+	transactItems := []types.TransactWriteItem{
+		{Put: &types.Put{
+			TableName:                 aws.String(r.TableName),
+			Item:                      r.profileItem(user),
+			ConditionExpression:       condition.Condition(),
+			ExpressionAttributeNames:  condition.Names(),
+			ExpressionAttributeValues: condition.Values(),
+		}},
+	}
+	if storedUser.Username != user.Username {
+		transactItems = append(transactItems,
+			types.TransactWriteItem{Delete: &types.Delete{
+				TableName:           aws.String(r.TableName),
+				Key:                 usernameRecordKey(storedUser.Username),
+				ConditionExpression: aws.String("user_id = :user_id"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":user_id": &types.AttributeValueMemberS{Value: user.Id},
+				},
+			}},
+			types.TransactWriteItem{Put: &types.Put{
+				TableName:           aws.String(r.TableName),
+				Item:                r.usernameItem(user),
+				ConditionExpression: aws.String("attribute_not_exists(PK)"),
+			}},
+		)
+	}
+
+	_, err = r.DynamodbClient.TransactWriteItems(context.TODO(), &dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
+	failed := canceledConditions(err)
+	if len(failed) == 3 && failed[2] {
+		return apperrors.NewConflictError(fmt.Sprintf("a user already exists with the username '%s'", user.Username), err)
+	}
+	if len(failed) > 0 {
+		return apperrors.NewConflictError(fmt.Sprintf("the user '%s' doesn't exist or was modified by another request", user.Id), err)
+	}
+	return err
+}
+
+// DeleteUser removes the user's profile and username record and records the events in a single transaction;
+// deleting a user that doesn't exist does nothing
+func (r *SingleTableUserRepositoryDDB) DeleteUser(id string, events ...model.Event) error {
+	user, err := r.FindUserById(id)
+	if err != nil || user == nil {
+		return err
+	}
+	_, err = r.DynamodbClient.TransactWriteItems(context.TODO(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: append([]types.TransactWriteItem{
+			{Delete: &types.Delete{
+				TableName:           aws.String(r.TableName),
+				Key:                 userProfileKey(id),
+				ConditionExpression: aws.String("attribute_exists(PK)"),
+			}},
+			{Delete: &types.Delete{
+				TableName: aws.String(r.TableName),
+				Key:       usernameRecordKey(user.Username),
+			}},
+		}, singleTableOutboxPuts(r.TableName, events)...),
+	})
+	// another request deleted the user first
+	if failed := canceledConditions(err); len(failed) > 0 && failed[0] {
+		return nil
+	}
+	return err
+}
+
+func (r *SingleTableUserRepositoryDDB) profileItem(user model.User) map[string]types.AttributeValue {
+	return withAttributes(userItem(user), map[string]string{
+		singleTableHashKey:  userKeyPrefix + user.Id,
+		singleTableRangeKey: profileSortKey,
+		gsi1HashKey:         usernameKeyPrefix + user.Username,
+		gsi1RangeKey:        profileSortKey,
+		recordTypeAttribute: userRecordType,
+	})
+}
+
+func (r *SingleTableUserRepositoryDDB) usernameItem(user model.User) map[string]types.AttributeValue {
+	return withAttributes(usernameRecordKey(user.Username), map[string]string{
+		"user_id":           user.Id,
+		recordTypeAttribute: usernameRecordType,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/repository/client"
 
@@ -20,12 +21,49 @@ type UserRepository interface {
 	FindAll() ([]model.User, error)
 	FindUserById(userId string) (*model.User, error)
 	FindUserByUsername(username string) (*model.User, error)
-	CreateNewUser(model.User) error
-	DeleteUser(id string) error
+	// CreateNewUser inserts the user, recording the events in the outbox in the same transaction
+	CreateNewUser(user model.User, events ...model.Event) error
+	UpdateUser(user model.User, expectedVersion int) error
+	// DeleteUser removes the user, recording the events in the outbox in the same transaction;
+	// the events are only recorded if the user existed
+	DeleteUser(id string, events ...model.Event) error
 }
 
-func NewUserRepository(tableName string, awsEndpoint string) (*UserRepositoryDDB, error) {
-	ddbClient, err := client.CreateLocalDDBClient(awsEndpoint)
+// UserWithFavoritesFinder reads a user along with all of their favorites;
+// the user is nil if it doesn't exist
+type UserWithFavoritesFinder interface {
+	FindUserWithFavorites(userId string) (*model.User, []model.Favorite, error)
+}
+
+// NewUserWithFavoritesFinder uses the user repository itself if it can read both at once,
+// like the single-table design, which keeps a user's favorites in the user's partition;
+// otherwise, the user and their favorites are read from the two repositories separately
+func NewUserWithFavoritesFinder(users UserRepository, favorites FavoriteRepository) UserWithFavoritesFinder {
+	if finder, ok := users.(UserWithFavoritesFinder); ok {
+		return finder
+	}
+	return separateUserWithFavoritesFinder{users: users, favorites: favorites}
+}
+
+type separateUserWithFavoritesFinder struct {
+	users     UserRepository
+	favorites FavoriteRepository
+}
+
+func (f separateUserWithFavoritesFinder) FindUserWithFavorites(userId string) (*model.User, []model.Favorite, error) {
+	user, err := f.users.FindUserById(userId)
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
+	favorites, err := f.favorites.FindFavoritesByUser(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, favorites, nil
+}
+
+func NewUserRepository(tableName string, appConfig model.AppConfig) (*UserRepositoryDDB, error) {
+	ddbClient, err := client.CreateResilientDDBClient(appConfig)
 	return &UserRepositoryDDB{
 		DynamodbClient: ddbClient,
 		TableName:      tableName,
@@ -35,19 +73,20 @@ func NewUserRepository(tableName string, awsEndpoint string) (*UserRepositoryDDB
 type UserRepositoryDDB struct {
 	DynamodbClient client.DDBClient
 	TableName      string
+	// OutboxTableName is the table that events are recorded in
+	OutboxTableName string
 }
 
 func (r *UserRepositoryDDB) FindAll() ([]model.User, error) {
 	scanInput := dynamodb.ScanInput{
 		TableName: aws.String(r.TableName),
 	}
-	ctx := context.TODO()
-	scanOutput, err := r.DynamodbClient.Scan(ctx, &scanInput)
+	items, err := scanPages(r.DynamodbClient, &scanInput)
 	if err != nil {
 		return nil, err
 	}
 	users := []model.User{}
-	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &users)
+	err = attributevalue.UnmarshalListOfMaps(items, &users)
 	if err != nil {
 		return nil, err
 	}
@@ -77,13 +116,12 @@ func (r *UserRepositoryDDB) FindUserByUsername(username string) (*model.User, er
 		KeyConditionExpression:    filterExpression.KeyCondition(),
 	}
 
-	ctx := context.TODO()
-	scanOutput, err := r.DynamodbClient.Query(ctx, &queryInput)
+	items, err := queryPages(r.DynamodbClient, &queryInput)
 	if err != nil {
 		return nil, err
 	}
 	users := []model.User{}
-	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &users)
+	err = attributevalue.UnmarshalListOfMaps(items, &users)
 	if err != nil {
 		return nil, err
 	}
@@ -98,70 +136,128 @@ func (r *UserRepositoryDDB) FindUserByUsername(username string) (*model.User, er
 	}
 }
 
-// FindUserById checks the repository's user table for any users with the given userId;
-//
-// Returns:
-//   - an error if there are multiple users with that username
-//   - the user if there is only 1 user
-//   - nil if there are 0 users
+// FindUserById retrieves the user with the given userId from the repository's user table,
+// or nil if no user exists with that id
 func (r *UserRepositoryDDB) FindUserById(userId string) (*model.User, error) {
-	filterExpression, err := expression.NewBuilder().WithKeyCondition(
-		expression.Key("id").Equal(expression.Value(userId)),
-	).Build()
+	getItemOutput, err := r.DynamodbClient.GetItem(context.TODO(), &dynamodb.GetItemInput{
+		TableName: aws.String(r.TableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: userId},
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	queryInput := dynamodb.QueryInput{
-		TableName:                 aws.String(r.TableName),
-		ExpressionAttributeNames:  filterExpression.Names(),
-		ExpressionAttributeValues: filterExpression.Values(),
-		KeyConditionExpression:    filterExpression.KeyCondition(),
-	}
-	ctx := context.TODO()
-	queryOutput, err := r.DynamodbClient.Query(ctx, &queryInput)
-	if err != nil {
-		return nil, err
+	if getItemOutput.Item == nil {
+		return nil, nil
 	}
-	users := []model.User{}
-	err = attributevalue.UnmarshalListOfMaps(queryOutput.Items, &users)
+	user := model.User{}
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, &user)
 	if err != nil {
 		return nil, err
 	}
-
-	switch num_users := len(users); num_users {
-	case 0:
-		return nil, nil
-	case 1:
-		return &users[0], nil
-	default:
-		return nil, fmt.Errorf("there are %s users with the id '%s'", strconv.Itoa(len(users)), userId)
-	}
+	return &user, nil
 }
 
 // CreateNewUser simply inserts the provided user into the repository's user table
+// as long as no user already exists with the same id
 //
 // Please ensure that you aren't inserting a duplicate record (i.e. user with that username already exists)
-func (r *UserRepositoryDDB) CreateNewUser(user model.User) error {
-	_, err := r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
-		TableName: aws.String(r.TableName),
-		Item: map[string]types.AttributeValue{
-			"id":       &types.AttributeValueMemberS{Value: user.Id},
-			"username": &types.AttributeValueMemberS{Value: user.Username},
-			"password": &types.AttributeValueMemberS{Value: user.Password},
-		},
+func (r *UserRepositoryDDB) CreateNewUser(user model.User, events ...model.Event) error {
+	put := &types.Put{
+		TableName:           aws.String(r.TableName),
+		Item:                userItem(user),
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	}
+	var err error
+	conditionFailed := false
+	if len(events) == 0 {
+		_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+			TableName:           put.TableName,
+			Item:                put.Item,
+			ConditionExpression: put.ConditionExpression,
+		})
+		conditionFailed = isConditionalCheckFailed(err)
+	} else {
+		conditionFailed, err = writeWithEvents(r.DynamodbClient, types.TransactWriteItem{Put: put}, outboxPuts(r.OutboxTableName, events))
+	}
+	if conditionFailed {
+		return apperrors.NewConflictError(fmt.Sprintf("a user already exists with the id '%s'", user.Id), err)
+	}
+	return err
+}
+
+// UpdateUser replaces the user's record as long as the stored version still matches expectedVersion;
+// if another request modified the user first, the ConflictError is returned
+//
+// The caller is responsible for incrementing user.Version
+func (r *UserRepositoryDDB) UpdateUser(user model.User, expectedVersion int) error {
+	condition, err := versionCondition(expectedVersion)
+	if err != nil {
+		return err
+	}
+	_, err = r.DynamodbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(r.TableName),
+		Item:                      userItem(user),
+		ConditionExpression:       condition.Condition(),
+		ExpressionAttributeNames:  condition.Names(),
+		ExpressionAttributeValues: condition.Values(),
 	})
+	if isConditionalCheckFailed(err) {
+		return apperrors.NewConflictError(fmt.Sprintf("the user '%s' doesn't exist or was modified by another request", user.Id), err)
+	}
 	return err
 }
 
 // DeleteUser removes the record associated with the given id
 // from the repository's user table
-func (r *UserRepositoryDDB) DeleteUser(id string) error {
-	_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
-		TableName: aws.String(r.TableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
-		},
-	})
+func (r *UserRepositoryDDB) DeleteUser(id string, events ...model.Event) error {
+	key := map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: id},
+	}
+	if len(events) == 0 {
+		_, err := r.DynamodbClient.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+			TableName: aws.String(r.TableName),
+			Key:       key,
+		})
+		return err
+	}
+
+	// the delete is conditional so that the events aren't recorded if another request already deleted the user
+	conditionFailed, err := writeWithEvents(r.DynamodbClient, types.TransactWriteItem{Delete: &types.Delete{
+		TableName:           aws.String(r.TableName),
+		Key:                 key,
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	}}, outboxPuts(r.OutboxTableName, events))
+	if conditionFailed {
+		return nil
+	}
 	return err
 }
+
+func userItem(user model.User) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"id":         &types.AttributeValueMemberS{Value: user.Id},
+		"username":   &types.AttributeValueMemberS{Value: user.Username},
+		"password":   &types.AttributeValueMemberS{Value: user.Password},
+		"created_at": &types.AttributeValueMemberS{Value: model.FormatTimestamp(user.CreatedAt)},
+		"updated_at": &types.AttributeValueMemberS{Value: model.FormatTimestamp(user.UpdatedAt)},
+		"version":    &types.AttributeValueMemberN{Value: strconv.Itoa(user.Version)},
+	}
+	if user.Units != "" {
+		item["units"] = &types.AttributeValueMemberS{Value: user.Units}
+	}
+	if user.DisplayName != "" {
+		item["display_name"] = &types.AttributeValueMemberS{Value: user.DisplayName}
+	}
+	if user.Bio != "" {
+		item["bio"] = &types.AttributeValueMemberS{Value: user.Bio}
+	}
+	if user.Avatar != "" {
+		item["avatar"] = &types.AttributeValueMemberS{Value: user.Avatar}
+	}
+	if user.PublicFavorites {
+		item["public_favorites"] = &types.AttributeValueMemberBOOL{Value: true}
+	}
+	return item
+}
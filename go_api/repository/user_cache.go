@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"the-drink-almanac-api/model"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// CachedUserRepository is a read-through cache in front of another UserRepository;
+// users are cached by both id and username, and a user's entries are invalidated whenever the user is written
+//
+// Entries are shared between api instances only if the Cache is, so a write handled by another instance
+// may not be visible until the entries expire
+type CachedUserRepository struct {
+	repo  UserRepository
+	cache Cache
+	ttl   time.Duration
+}
+
+func NewCachedUserRepository(repo UserRepository, cache Cache, ttl time.Duration) *CachedUserRepository {
+	return &CachedUserRepository{
+		repo:  repo,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+func (r *CachedUserRepository) FindAll() ([]model.User, error) {
+	return r.repo.FindAll()
+}
+
+func (r *CachedUserRepository) FindUserById(userId string) (*model.User, error) {
+	return r.readThrough(userIdKey(userId), func() (*model.User, error) {
+		return r.repo.FindUserById(userId)
+	})
+}
+
+func (r *CachedUserRepository) FindUserByUsername(username string) (*model.User, error) {
+	return r.readThrough(usernameKey(username), func() (*model.User, error) {
+		return r.repo.FindUserByUsername(username)
+	})
+}
+
+func (r *CachedUserRepository) CreateNewUser(user model.User, events ...model.Event) error {
+	err := r.repo.CreateNewUser(user, events...)
+	r.invalidate(user.Id, user.Username)
+	return err
+}
+
+func (r *CachedUserRepository) UpdateUser(user model.User, expectedVersion int) error {
+	err := r.repo.UpdateUser(user, expectedVersion)
+	r.invalidate(user.Id, user.Username)
+	return err
+}
+
+func (r *CachedUserRepository) DeleteUser(id string, events ...model.Event) error {
+	err := r.repo.DeleteUser(id, events...)
+	r.invalidate(id)
+	return err
+}
+
+// readThrough caches the loaded user under both its id and username,
+// so that the username entry can be invalidated when only the id is known
+func (r *CachedUserRepository) readThrough(key string, load func() (*model.User, error)) (*model.User, error) {
+	return readThrough(r.cache, key, r.ttl, func() (*model.User, error) {
+		user, err := load()
+		if err == nil && user != nil {
+			storeInCache(r.cache, r.ttl, user, userIdKey(user.Id), usernameKey(user.Username))
+		}
+		return user, err
+	})
+}
+
+// invalidate removes the cached entries for the user, including the entry for the username
+// that's currently cached for the user's id in case the username has changed
+func (r *CachedUserRepository) invalidate(userId string, usernames ...string) {
+	keys := []string{userIdKey(userId)}
+	if cached, ok := r.cache.Get(userIdKey(userId)); ok {
+		var cachedUser model.User
+		if jsoniter.Unmarshal(cached, &cachedUser) == nil {
+			usernames = append(usernames, cachedUser.Username)
+		}
+	}
+	for _, username := range usernames {
+		keys = append(keys, usernameKey(username))
+	}
+	r.cache.Delete(keys...)
+}
+
+func userIdKey(userId string) string {
+	return fmt.Sprintf("user:id:%s", userId)
+}
+
+func usernameKey(username string) string {
+	return fmt.Sprintf("user:username:%s", username)
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestCachedUserRepository_FindUserById(t *testing.T) {
+	user := &model.User{Id: "0", Username: "user0", Password: "hash", Version: 1}
+	mockUserRepo := NewMockUserRepository(t)
+	mockUserRepo.On("FindUserById", "0").Return(user, nil).Once()
+	userStore := NewCachedUserRepository(mockUserRepo, NewLRUCache(10), time.Minute)
+
+	for i := 0; i < 2; i++ {
+		actualUser, err := userStore.FindUserById("0")
+		assert.NoError(t, err)
+		assert.Equal(t, user, actualUser)
+	}
+
+	// the user was also cached by username
+	actualUser, err := userStore.FindUserByUsername("user0")
+	assert.NoError(t, err)
+	assert.Equal(t, user, actualUser)
+	mockUserRepo.AssertNumberOfCalls(t, "FindUserById", 1)
+}
+
+func TestCachedUserRepository_MissesAreNotCached(t *testing.T) {
+	mockUserRepo := NewMockUserRepository(t)
+	mockUserRepo.On("FindUserByUsername", "user0").Return(nil, nil).Once()
+	mockUserRepo.On("FindUserByUsername", "user0").Return(nil, fmt.Errorf("failed to retrieve user")).Once()
+	userStore := NewCachedUserRepository(mockUserRepo, NewLRUCache(10), time.Minute)
+
+	user, err := userStore.FindUserByUsername("user0")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+	_, err = userStore.FindUserByUsername("user0")
+	assert.Error(t, err)
+}
+
+func TestCachedUserRepository_WritesInvalidateTheUser(t *testing.T) {
+	user := model.User{Id: "0", Username: "user0", Version: 1}
+	renamedUser := model.User{Id: "0", Username: "user1", Version: 2}
+	tests := []struct {
+		name  string
+		write func(userStore *CachedUserRepository, mockUserRepo *MockUserRepository) error
+	}{
+		{
+			name: "Update user",
+			write: func(userStore *CachedUserRepository, mockUserRepo *MockUserRepository) error {
+				mockUserRepo.On("UpdateUser", renamedUser, 1).Return(nil)
+				return userStore.UpdateUser(renamedUser, 1)
+			},
+		},
+		{
+			name: "Delete user",
+			write: func(userStore *CachedUserRepository, mockUserRepo *MockUserRepository) error {
+				mockUserRepo.On("DeleteUser", "0").Return(nil)
+				return userStore.DeleteUser("0")
+			},
+		},
+		{
+			name: "Failed write",
+			write: func(userStore *CachedUserRepository, mockUserRepo *MockUserRepository) error {
+				mockUserRepo.On("UpdateUser", renamedUser, 1).Return(fmt.Errorf("conflict"))
+				assert.Error(t, userStore.UpdateUser(renamedUser, 1))
+				return nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := NewMockUserRepository(t)
+			mockUserRepo.On("FindUserById", "0").Return(&user, nil)
+			cache := NewLRUCache(10)
+			userStore := NewCachedUserRepository(mockUserRepo, cache, time.Minute)
+			_, err := userStore.FindUserById("0")
+			assert.NoError(t, err)
+
+			assert.NoError(t, tt.write(userStore, mockUserRepo))
+
+			// the entries for both the id and the old username are removed
+			assert.Equal(t, 0, cache.Len())
+		})
+	}
+}
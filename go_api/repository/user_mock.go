@@ -13,13 +13,20 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-// CreateNewUser provides a mock function with given fields: _a0
-func (_m *MockUserRepository) CreateNewUser(_a0 model.User) error {
-	ret := _m.Called(_a0)
+// CreateNewUser provides a mock function with given fields: user, events
+func (_m *MockUserRepository) CreateNewUser(user model.User, events ...model.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, user)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.User) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(model.User, ...model.Event) error); ok {
+		r0 = rf(user, events...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,13 +34,20 @@ func (_m *MockUserRepository) CreateNewUser(_a0 model.User) error {
 	return r0
 }
 
-// DeleteUser provides a mock function with given fields: id
-func (_m *MockUserRepository) DeleteUser(id string) error {
-	ret := _m.Called(id)
+// DeleteUser provides a mock function with given fields: id, events
+func (_m *MockUserRepository) DeleteUser(id string, events ...model.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(string, ...model.Event) error); ok {
+		r0 = rf(id, events...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -119,6 +133,20 @@ func (_m *MockUserRepository) FindUserByUsername(username string) (*model.User,
 	return r0, r1
 }
 
+// UpdateUser provides a mock function with given fields: user, expectedVersion
+func (_m *MockUserRepository) UpdateUser(user model.User, expectedVersion int) error {
+	ret := _m.Called(user, expectedVersion)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.User, int) error); ok {
+		r0 = rf(user, expectedVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUserRepository(t interface {
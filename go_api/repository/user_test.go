@@ -2,16 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/repository/client"
 )
@@ -138,65 +141,59 @@ func TestUserStoreDDB_FindUserByUsername(t *testing.T) {
 }
 
 func TestUserStoreDDB_FindUserById(t *testing.T) {
-	numUsers := 5
-	userItems := make([]map[string]types.AttributeValue, numUsers)
-	for i := 0; i < numUsers; i++ {
-		userItems[i] = map[string]types.AttributeValue{
-			"id":       &types.AttributeValueMemberS{Value: "0"},
-			"username": &types.AttributeValueMemberS{Value: "0"},
-			"password": &types.AttributeValueMemberS{Value: "0"},
-		}
+	userItem := map[string]types.AttributeValue{
+		"id":       &types.AttributeValueMemberS{Value: "0"},
+		"username": &types.AttributeValueMemberS{Value: "0"},
+		"password": &types.AttributeValueMemberS{Value: "0"},
+	}
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(""),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: "0"},
+		},
 	}
 	tests := []struct {
 		name          string
-		username      string
+		userId        string
 		expectedUser  *model.User
-		queryOutput   *dynamodb.QueryOutput
+		getItemOutput *dynamodb.GetItemOutput
 		returnedError error
 		expectError   bool
 	}{
 		{
-			name:     "Successfully retrieve users",
-			username: "0",
+			name:   "Successfully retrieve user",
+			userId: "0",
 			expectedUser: &model.User{
 				Id:       "0",
 				Username: "0",
 				Password: "0",
 			},
-			queryOutput:   &dynamodb.QueryOutput{Items: userItems[:1]},
+			getItemOutput: &dynamodb.GetItemOutput{Item: userItem},
 			returnedError: nil,
 			expectError:   false,
 		},
 		{
-			name:          "Failed to retrieve users",
-			username:      "0",
+			name:          "Failed to retrieve user",
+			userId:        "0",
 			expectedUser:  nil,
-			returnedError: fmt.Errorf("failed to retrieve users"),
+			returnedError: fmt.Errorf("failed to retrieve user"),
 			expectError:   true,
 		},
 		{
 			name:          "No existing user",
-			username:      "0",
+			userId:        "0",
 			expectedUser:  nil,
-			queryOutput:   &dynamodb.QueryOutput{Items: nil},
+			getItemOutput: &dynamodb.GetItemOutput{Item: nil},
 			returnedError: nil,
 			expectError:   false,
 		},
-		{
-			name:          "Too many users",
-			username:      "0",
-			expectedUser:  nil,
-			queryOutput:   &dynamodb.QueryOutput{Items: userItems},
-			returnedError: nil,
-			expectError:   true,
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockDdbClient := client.NewMockDDBClient(t)
-			mockDdbClient.On("Query", context.TODO(), mock.AnythingOfType("*dynamodb.QueryInput")).Return(tt.queryOutput, tt.returnedError)
+			mockDdbClient.On("GetItem", context.TODO(), getItemInput).Return(tt.getItemOutput, tt.returnedError)
 			userStore := UserRepositoryDDB{DynamodbClient: mockDdbClient}
-			actualUser, err := userStore.FindUserById(tt.username)
+			actualUser, err := userStore.FindUserById(tt.userId)
 			assert.Equal(t, tt.expectError, err != nil, "UserRepositoryDDB.FindUserById() error = %v", err)
 			assert.Equal(t, tt.expectedUser, actualUser, "UserRepositoryDDB.FindUserById() = %v, want %v", actualUser, tt.expectedUser)
 		})
@@ -205,24 +202,32 @@ func TestUserStoreDDB_FindUserById(t *testing.T) {
 
 func TestUserStoreDDB_CreateNewUser(t *testing.T) {
 	mockUser := model.User{
-		Id:       "0",
-		Username: "0",
-		Password: "0",
+		Id:        "0",
+		Username:  "0",
+		Password:  "0",
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   1,
 	}
 	putItemInput := &dynamodb.PutItemInput{
 		TableName: aws.String(""),
 		Item: map[string]types.AttributeValue{
-			"id":       &types.AttributeValueMemberS{Value: mockUser.Id},
-			"username": &types.AttributeValueMemberS{Value: mockUser.Username},
-			"password": &types.AttributeValueMemberS{Value: mockUser.Password},
+			"id":         &types.AttributeValueMemberS{Value: mockUser.Id},
+			"username":   &types.AttributeValueMemberS{Value: mockUser.Username},
+			"password":   &types.AttributeValueMemberS{Value: mockUser.Password},
+			"created_at": &types.AttributeValueMemberS{Value: "2023-01-01T00:00:00.000Z"},
+			"updated_at": &types.AttributeValueMemberS{Value: "2023-01-02T00:00:00.000Z"},
+			"version":    &types.AttributeValueMemberN{Value: "1"},
 		},
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
 	}
 	putItemOutput := &dynamodb.PutItemOutput{}
 	tests := []struct {
-		name          string
-		expectedUser  model.User
-		returnedError error
-		expectError   bool
+		name                string
+		expectedUser        model.User
+		returnedError       error
+		expectError         bool
+		expectConflictError bool
 	}{
 		{
 			name:          "Successfully created a user",
@@ -236,6 +241,13 @@ func TestUserStoreDDB_CreateNewUser(t *testing.T) {
 			returnedError: fmt.Errorf("failed to create the user"),
 			expectError:   true,
 		},
+		{
+			name:                "User already exists with the same id",
+			expectedUser:        mockUser,
+			returnedError:       &types.ConditionalCheckFailedException{},
+			expectError:         true,
+			expectConflictError: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -247,6 +259,67 @@ func TestUserStoreDDB_CreateNewUser(t *testing.T) {
 				t.Errorf("UserRepositoryDDB.CreateNewUser() error = %v", err)
 				return
 			}
+			assert.Equal(t, tt.expectConflictError, errors.As(err, &apperrors.ConflictError{}))
+		})
+	}
+}
+
+func TestUserStoreDDB_UpdateUser(t *testing.T) {
+	mockUser := model.User{
+		Id:        "0",
+		Username:  "0",
+		Password:  "0",
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		Version:   2,
+	}
+	tests := []struct {
+		name                string
+		expectedVersion     int
+		expectedCondition   string
+		returnedError       error
+		expectError         bool
+		expectConflictError bool
+	}{
+		{
+			name:              "Successfully updated a user",
+			expectedVersion:   1,
+			expectedCondition: "(attribute_exists (#0)) AND (#1 = :0)",
+		},
+		{
+			name:              "Successfully updated a user that was created before versions were tracked",
+			expectedVersion:   0,
+			expectedCondition: "(attribute_exists (#0)) AND ((attribute_not_exists (#1)) OR (#1 = :0))",
+		},
+		{
+			name:                "User was modified by another request",
+			expectedVersion:     1,
+			expectedCondition:   "(attribute_exists (#0)) AND (#1 = :0)",
+			returnedError:       &types.ConditionalCheckFailedException{},
+			expectError:         true,
+			expectConflictError: true,
+		},
+		{
+			name:              "Failed to update a user",
+			expectedVersion:   1,
+			expectedCondition: "(attribute_exists (#0)) AND (#1 = :0)",
+			returnedError:     fmt.Errorf("failed to update the user"),
+			expectError:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDdbClient := client.NewMockDDBClient(t)
+			mockDdbClient.On("PutItem", context.TODO(), mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+				expectedVersion := strconv.Itoa(tt.expectedVersion)
+				return *input.ConditionExpression == tt.expectedCondition &&
+					input.ExpressionAttributeValues[":0"].(*types.AttributeValueMemberN).Value == expectedVersion &&
+					input.Item["version"].(*types.AttributeValueMemberN).Value == "2"
+			})).Return(&dynamodb.PutItemOutput{}, tt.returnedError)
+			userStore := UserRepositoryDDB{DynamodbClient: mockDdbClient}
+			err := userStore.UpdateUser(mockUser, tt.expectedVersion)
+			assert.Equal(t, tt.expectError, err != nil, "UserRepositoryDDB.UpdateUser() error = %v", err)
+			assert.Equal(t, tt.expectConflictError, errors.As(err, &apperrors.ConflictError{}))
 		})
 	}
 }
@@ -296,3 +369,28 @@ func TestUserStoreDDB_DeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestNewUserWithFavoritesFinder(t *testing.T) {
+	singleTableUsers := &SingleTableUserRepositoryDDB{}
+	assert.Same(t, singleTableUsers, NewUserWithFavoritesFinder(singleTableUsers, &SingleTableFavoriteRepositoryDDB{}),
+		"The single-table repository should read the user and favorites itself")
+
+	user := &model.User{Id: "0", Username: "username0"}
+	favorites := []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007"}}
+	mockUserRepository := NewMockUserRepository(t)
+	mockFavoriteRepository := NewMockFavoriteRepository(t)
+	mockUserRepository.On("FindUserById", "0").Return(user, nil)
+	mockFavoriteRepository.On("FindFavoritesByUser", "0").Return(favorites, nil)
+	mockUserRepository.On("FindUserById", "1").Return(nil, nil)
+
+	finder := NewUserWithFavoritesFinder(mockUserRepository, mockFavoriteRepository)
+	foundUser, foundFavorites, err := finder.FindUserWithFavorites("0")
+	assert.NoError(t, err, "No error should have been returned from FindUserWithFavorites")
+	assert.Equal(t, user, foundUser)
+	assert.Equal(t, favorites, foundFavorites)
+
+	foundUser, foundFavorites, err = finder.FindUserWithFavorites("1")
+	assert.NoError(t, err, "No error should have been returned from FindUserWithFavorites")
+	assert.Nil(t, foundUser, "No user should have been found")
+	assert.Nil(t, foundFavorites, "The favorites of a missing user shouldn't be read")
+}
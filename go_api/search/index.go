@@ -0,0 +1,330 @@
+// Package search is an in-process full-text and faceted search over the drink catalog.
+//
+// The Index is an inverted index from the terms of each drink's name and instructions to the drinks that contain them,
+// built in memory from the whole catalog, so no external search service is needed.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"the-drink-almanac-api/model"
+)
+
+const (
+	// nameWeight makes a term in a drink's name count for more than the same term in its instructions
+	nameWeight         = 3
+	instructionsWeight = 1
+	// FacetLimit is the maximum number of values returned for each facet, the most common first
+	FacetLimit = 20
+)
+
+// stopWords are left out of the index and queries, since nearly every drink's instructions contain them
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "in": true, "into": true, "of": true, "s": true, "the": true, "to": true, "with": true,
+}
+
+// Sort is the order of the results
+type Sort string
+
+const (
+	// SortRelevance orders the drinks by how well they match the text, then by name
+	SortRelevance Sort = "relevance"
+	// SortPopularity orders the drinks by how many users favorited them, then by relevance
+	SortPopularity Sort = "popularity"
+)
+
+// Query is a search of the catalog; every field is optional, and an empty query matches every drink
+type Query struct {
+	// Text matches drinks whose name or instructions contain every one of its words
+	Text string
+	// Ingredients matches drinks that have all of these ingredients
+	Ingredients []string
+	Category    string
+	Glass       string
+	// Alcoholic matches either alcoholic or non-alcoholic drinks if it's set
+	Alcoholic *bool
+	Sort      Sort
+	Offset    int
+	// Limit is the maximum number of drinks to return; all of them are returned if it's 0
+	Limit int
+}
+
+// Hit is a drink that matched the query
+type Hit struct {
+	Drink model.Drink
+	// Score is the relevance of the drink to the query's text; it's 0 if the query doesn't have any text
+	Score float64
+	// Popularity is the number of users who favorited the drink
+	Popularity int
+}
+
+// FacetValue is the number of matching drinks with a value of a facet
+type FacetValue struct {
+	Value string
+	Count int
+}
+
+// Facets counts the matching drinks by each value of the filters; every facet ignores its own filter,
+// except for ingredients, since a drink has to have all of the requested ingredients, so the other
+// values of a facet show how many drinks choosing them instead would match
+type Facets struct {
+	Categories   []FacetValue
+	Glasses      []FacetValue
+	Ingredients  []FacetValue
+	Alcoholic    int
+	NonAlcoholic int
+}
+
+// Result is a page of the drinks that matched a query, along with the total number of matches
+type Result struct {
+	Total  int
+	Hits   []Hit
+	Facets Facets
+}
+
+type posting struct {
+	drink int
+	// weight is the term's frequency in the drink, weighted by where it appears
+	weight int
+}
+
+type indexedDrink struct {
+	drink       model.Drink
+	popularity  int
+	category    string
+	glass       string
+	ingredients map[string]bool
+}
+
+// Index is an immutable inverted index of a catalog; it's safe for concurrent use
+type Index struct {
+	drinks   []indexedDrink
+	postings map[string][]posting
+	// ingredientDrinks lists the drinks that have each normalized ingredient, for finding makeable drinks
+	ingredientDrinks map[string][]int
+	drinksById       map[string]int
+}
+
+// NewIndex indexes the drinks; popularity is the number of favorites of each drink id
+func NewIndex(drinks []model.Drink, popularity map[string]int) *Index {
+	index := &Index{
+		drinks:           make([]indexedDrink, len(drinks)),
+		postings:         map[string][]posting{},
+		ingredientDrinks: map[string][]int{},
+		drinksById:       map[string]int{},
+	}
+	for i, drink := range drinks {
+		indexed := indexedDrink{
+			drink:       drink,
+			popularity:  popularity[drink.Id],
+			category:    normalize(drink.Category),
+			glass:       normalize(drink.Glass),
+			ingredients: map[string]bool{},
+		}
+		for _, ingredient := range drink.Ingredients {
+			name := normalize(ingredient.Name)
+			if name == "" || indexed.ingredients[name] {
+				continue
+			}
+			indexed.ingredients[name] = true
+			index.ingredientDrinks[name] = append(index.ingredientDrinks[name], i)
+		}
+		index.drinks[i] = indexed
+		index.drinksById[drink.Id] = i
+
+		weights := map[string]int{}
+		for _, term := range Tokenize(drink.Name) {
+			weights[term] += nameWeight
+		}
+		for _, term := range Tokenize(drink.Instructions) {
+			weights[term] += instructionsWeight
+		}
+		for term, weight := range weights {
+			index.postings[term] = append(index.postings[term], posting{drink: i, weight: weight})
+		}
+	}
+	return index
+}
+
+// Popularity is the number of favorites of the drink when it was indexed, or 0 if it isn't in the index
+func (idx *Index) Popularity(drinkId string) int {
+	if i, ok := idx.drinksById[drinkId]; ok {
+		return idx.drinks[i].popularity
+	}
+	return 0
+}
+
+// Len is the number of drinks in the index
+func (idx *Index) Len() int {
+	return len(idx.drinks)
+}
+
+// Tokenize splits the text into lowercase terms, leaving out punctuation and stop words
+func Tokenize(text string) []string {
+	terms := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !stopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// normalize is used to match filters regardless of case and spacing
+func normalize(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+// Search returns the page of drinks that match the query, along with the facets of every match
+func (idx *Index) Search(query Query) Result {
+	scores, textMatched := idx.score(query.Text)
+
+	category := normalize(query.Category)
+	glass := normalize(query.Glass)
+	ingredients := make([]string, 0, len(query.Ingredients))
+	for _, ingredient := range query.Ingredients {
+		if normalized := normalize(ingredient); normalized != "" {
+			ingredients = append(ingredients, normalized)
+		}
+	}
+
+	result := Result{}
+	categories := map[string]int{}
+	glasses := map[string]int{}
+	ingredientCounts := map[string]int{}
+	for i, drink := range idx.drinks {
+		if textMatched != nil && !textMatched[i] {
+			continue
+		}
+		matchesCategory := category == "" || drink.category == category
+		matchesGlass := glass == "" || drink.glass == glass
+		matchesAlcoholic := query.Alcoholic == nil || drink.drink.Alcoholic == *query.Alcoholic
+		matchesIngredients := drink.hasIngredients(ingredients)
+
+		// each facet is counted over the drinks that match every other filter
+		if matchesGlass && matchesAlcoholic && matchesIngredients && drink.drink.Category != "" {
+			categories[drink.drink.Category]++
+		}
+		if matchesCategory && matchesAlcoholic && matchesIngredients && drink.drink.Glass != "" {
+			glasses[drink.drink.Glass]++
+		}
+		if matchesCategory && matchesGlass && matchesIngredients {
+			if drink.drink.Alcoholic {
+				result.Facets.Alcoholic++
+			} else {
+				result.Facets.NonAlcoholic++
+			}
+		}
+		if !matchesCategory || !matchesGlass || !matchesAlcoholic || !matchesIngredients {
+			continue
+		}
+		counted := map[string]bool{}
+		for _, ingredient := range drink.drink.Ingredients {
+			if !counted[ingredient.Name] {
+				counted[ingredient.Name] = true
+				ingredientCounts[ingredient.Name]++
+			}
+		}
+		result.Hits = append(result.Hits, Hit{Drink: drink.drink, Score: scores[i], Popularity: drink.popularity})
+	}
+
+	result.Facets.Categories = topValues(categories)
+	result.Facets.Glasses = topValues(glasses)
+	result.Facets.Ingredients = topValues(ingredientCounts)
+	sortHits(result.Hits, query.Sort)
+	result.Total = len(result.Hits)
+	result.Hits = page(result.Hits, query.Offset, query.Limit)
+	return result
+}
+
+// score returns the relevance of every drink that contains all of the text's terms, using tf-idf;
+// the set of matching drinks is nil if the text doesn't have any terms
+func (idx *Index) score(text string) (map[int]float64, map[int]bool) {
+	terms := Tokenize(text)
+	if len(terms) == 0 {
+		return map[int]float64{}, nil
+	}
+
+	scores := map[int]float64{}
+	matches := map[int]int{}
+	seen := map[string]bool{}
+	for _, term := range terms {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		postings := idx.postings[term]
+		idf := math.Log(1 + float64(len(idx.drinks))/float64(len(postings)+1))
+		for _, p := range postings {
+			scores[p.drink] += idf * float64(p.weight)
+			matches[p.drink]++
+		}
+	}
+
+	matched := map[int]bool{}
+	for drink, count := range matches {
+		if count == len(seen) {
+			matched[drink] = true
+		}
+	}
+	return scores, matched
+}
+
+func (d indexedDrink) hasIngredients(ingredients []string) bool {
+	for _, ingredient := range ingredients {
+		if !d.ingredients[ingredient] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortHits(hits []Hit, order Sort) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if order == SortPopularity && a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Drink.Name != b.Drink.Name {
+			return a.Drink.Name < b.Drink.Name
+		}
+		return a.Drink.Id < b.Drink.Id
+	})
+}
+
+func page(hits []Hit, offset, limit int) []Hit {
+	if offset >= len(hits) {
+		return []Hit{}
+	}
+	hits = hits[offset:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// topValues returns the FacetLimit most common values, ordered by count and then by value
+func topValues(counts map[string]int) []FacetValue {
+	values := make([]FacetValue, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, FacetValue{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > FacetLimit {
+		values = values[:FacetLimit]
+	}
+	return values
+}
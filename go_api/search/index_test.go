@@ -0,0 +1,165 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+var testDrinks = []model.Drink{
+	{
+		Id:           "11007",
+		Name:         "Margarita",
+		Category:     "Ordinary Drink",
+		Glass:        "Cocktail glass",
+		Instructions: "Rub the rim of the glass with lime, then shake the tequila with ice.",
+		Ingredients:  []model.Ingredient{{Name: "Tequila"}, {Name: "Triple sec"}, {Name: "Lime juice"}, {Name: "Salt"}},
+		Alcoholic:    true,
+	},
+	{
+		Id:           "11008",
+		Name:         "Tommy's Margarita",
+		Category:     "Cocktail",
+		Glass:        "Old-fashioned glass",
+		Instructions: "Shake and strain over ice.",
+		Ingredients:  []model.Ingredient{{Name: "Tequila"}, {Name: "Lime juice"}, {Name: "Agave syrup"}},
+		Alcoholic:    true,
+	},
+	{
+		Id:           "11009",
+		Name:         "Lime Spritz",
+		Category:     "Soft Drink",
+		Glass:        "Highball glass",
+		Instructions: "Top the lime juice with soda water. Tastes like a margarita without tequila.",
+		Ingredients:  []model.Ingredient{{Name: "Lime juice"}, {Name: "Soda water"}},
+		Alcoholic:    false,
+	},
+	{
+		Id:           "11010",
+		Name:         "Gin and Tonic",
+		Category:     "Ordinary Drink",
+		Glass:        "Highball glass",
+		Instructions: "Pour the gin over ice and top with tonic.",
+		Ingredients:  []model.Ingredient{{Name: "Gin"}, {Name: "Tonic water"}, {Name: "Lime juice"}},
+		Alcoholic:    true,
+	},
+}
+
+func hitIds(result Result) []string {
+	ids := []string{}
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.Drink.Id)
+	}
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"tommy", "margarita"}, Tokenize("Tommy's Margarita"))
+	assert.Equal(t, []string{"pour", "gin", "over", "ice", "1", "2", "oz"}, Tokenize("Pour the gin over ice (1/2 oz)."))
+	assert.Empty(t, Tokenize("  the, and "))
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := NewIndex(testDrinks, map[string]int{"11009": 5, "11010": 2})
+	alcoholic := true
+	nonAlcoholic := false
+
+	tests := []struct {
+		name        string
+		query       Query
+		expectedIds []string
+		total       int
+	}{
+		{
+			name:        "Empty query matches every drink by name",
+			query:       Query{},
+			expectedIds: []string{"11010", "11009", "11007", "11008"},
+			total:       4,
+		},
+		{
+			name:        "Names rank above instructions",
+			query:       Query{Text: "margarita"},
+			expectedIds: []string{"11007", "11008", "11009"},
+			total:       3,
+		},
+		{
+			name:        "Every word has to match",
+			query:       Query{Text: "Margarita tequila"},
+			expectedIds: []string{"11007", "11009"},
+			total:       2,
+		},
+		{
+			name:        "Unknown words don't match",
+			query:       Query{Text: "whiskey"},
+			expectedIds: []string{},
+		},
+		{
+			name:        "Filters by every ingredient, ignoring case",
+			query:       Query{Ingredients: []string{"lime JUICE", " tequila"}},
+			expectedIds: []string{"11007", "11008"},
+			total:       2,
+		},
+		{
+			name:        "Filters by category and glass",
+			query:       Query{Category: "ordinary drink", Glass: "Highball glass"},
+			expectedIds: []string{"11010"},
+			total:       1,
+		},
+		{
+			name:        "Filters alcoholic drinks",
+			query:       Query{Text: "lime", Alcoholic: &nonAlcoholic},
+			expectedIds: []string{"11009"},
+			total:       1,
+		},
+		{
+			name:        "Sorts by popularity",
+			query:       Query{Alcoholic: &alcoholic, Sort: SortPopularity},
+			expectedIds: []string{"11010", "11007", "11008"},
+			total:       3,
+		},
+		{
+			name:        "Pages the results",
+			query:       Query{Offset: 1, Limit: 2},
+			expectedIds: []string{"11009", "11007"},
+			total:       4,
+		},
+		{
+			name:        "Offset past the end",
+			query:       Query{Offset: 10},
+			expectedIds: []string{},
+			total:       4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := index.Search(tt.query)
+			assert.Equal(t, tt.expectedIds, hitIds(result))
+			assert.Equal(t, tt.total, result.Total)
+		})
+	}
+}
+
+func TestIndex_Search_Facets(t *testing.T) {
+	index := NewIndex(testDrinks, nil)
+
+	result := index.Search(Query{Category: "Ordinary Drink"})
+	assert.Equal(t, []string{"11010", "11007"}, hitIds(result))
+	assert.Equal(t, []FacetValue{
+		{Value: "Ordinary Drink", Count: 2},
+		{Value: "Cocktail", Count: 1},
+		{Value: "Soft Drink", Count: 1},
+	}, result.Facets.Categories, "The category facet should ignore the category filter")
+	assert.Equal(t, []FacetValue{
+		{Value: "Cocktail glass", Count: 1},
+		{Value: "Highball glass", Count: 1},
+	}, result.Facets.Glasses)
+	assert.Equal(t, FacetValue{Value: "Lime juice", Count: 2}, result.Facets.Ingredients[0])
+	assert.Equal(t, 2, result.Facets.Alcoholic)
+	assert.Equal(t, 0, result.Facets.NonAlcoholic)
+
+	// only the text matches count, not the ingredients
+	result = index.Search(Query{Text: "lime"})
+	assert.Equal(t, 1, result.Facets.Alcoholic)
+	assert.Equal(t, 1, result.Facets.NonAlcoholic)
+}
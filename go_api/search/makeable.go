@@ -0,0 +1,132 @@
+package search
+
+import (
+	"sort"
+
+	"the-drink-almanac-api/model"
+)
+
+// MaxMissingIngredients is the most ingredients that a suggested drink can be missing
+const MaxMissingIngredients = 2
+
+// MakeableQuery is the ingredients that someone has, to find the drinks that they can make with them
+type MakeableQuery struct {
+	Ingredients []string
+	// MaxMissing is the most ingredients, up to MaxMissingIngredients, that a suggestion can be missing;
+	// only the drinks that can be made are returned if it's 0
+	MaxMissing int
+	// DrinkIds restricts the matches to these drinks, e.g. a user's favorites, unless it's nil
+	DrinkIds []string
+	// Limit is the maximum number of drinks to return in each list; all of them are returned if it's 0
+	Limit int
+}
+
+// MakeableHit is a drink that can be made, or almost made, with the query's ingredients
+type MakeableHit struct {
+	Drink model.Drink
+	// Missing is the names of the drink's ingredients that weren't in the query, in the recipe's order
+	Missing    []string
+	Popularity int
+}
+
+// MakeableResult is the drinks that can be made with the query's ingredients,
+// followed by the ones that are missing one or two ingredients
+type MakeableResult struct {
+	Makeable   []MakeableHit
+	MissingOne []MakeableHit
+	MissingTwo []MakeableHit
+}
+
+// Makeable finds the drinks that can be made with the query's ingredients, by counting how many of each drink's
+// ingredients are in the query using the ingredient postings, so only the drinks that share an ingredient with
+// the query are visited; a drink that doesn't share any isn't suggested, even if it only has one or two ingredients
+func (idx *Index) Makeable(query MakeableQuery) MakeableResult {
+	owned := map[string]bool{}
+	for _, ingredient := range query.Ingredients {
+		if normalized := normalize(ingredient); normalized != "" {
+			owned[normalized] = true
+		}
+	}
+	var allowed map[int]bool
+	if query.DrinkIds != nil {
+		allowed = map[int]bool{}
+		for _, id := range query.DrinkIds {
+			if i, ok := idx.drinksById[id]; ok {
+				allowed[i] = true
+			}
+		}
+	}
+	maxMissing := query.MaxMissing
+	if maxMissing > MaxMissingIngredients {
+		maxMissing = MaxMissingIngredients
+	}
+
+	matches := map[int]int{}
+	for ingredient := range owned {
+		for _, drink := range idx.ingredientDrinks[ingredient] {
+			if allowed == nil || allowed[drink] {
+				matches[drink]++
+			}
+		}
+	}
+
+	result := MakeableResult{Makeable: []MakeableHit{}, MissingOne: []MakeableHit{}, MissingTwo: []MakeableHit{}}
+	for i, count := range matches {
+		drink := idx.drinks[i]
+		missingCount := len(drink.ingredients) - count
+		if missingCount > maxMissing {
+			continue
+		}
+		hit := MakeableHit{Drink: drink.drink, Missing: drink.missing(owned), Popularity: drink.popularity}
+		switch missingCount {
+		case 0:
+			result.Makeable = append(result.Makeable, hit)
+		case 1:
+			result.MissingOne = append(result.MissingOne, hit)
+		default:
+			result.MissingTwo = append(result.MissingTwo, hit)
+		}
+	}
+	for _, hits := range [][]MakeableHit{result.Makeable, result.MissingOne, result.MissingTwo} {
+		sortMakeableHits(hits)
+	}
+	result.Makeable = limitMakeableHits(result.Makeable, query.Limit)
+	result.MissingOne = limitMakeableHits(result.MissingOne, query.Limit)
+	result.MissingTwo = limitMakeableHits(result.MissingTwo, query.Limit)
+	return result
+}
+
+// missing returns the names of the drink's ingredients that aren't owned, once each
+func (d indexedDrink) missing(owned map[string]bool) []string {
+	missing := []string{}
+	seen := map[string]bool{}
+	for _, ingredient := range d.drink.Ingredients {
+		name := normalize(ingredient.Name)
+		if name != "" && !owned[name] && !seen[name] {
+			seen[name] = true
+			missing = append(missing, ingredient.Name)
+		}
+	}
+	return missing
+}
+
+// sortMakeableHits orders the drinks by popularity, then by name
+func sortMakeableHits(hits []MakeableHit) {
+	sort.Slice(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		if a.Drink.Name != b.Drink.Name {
+			return a.Drink.Name < b.Drink.Name
+		}
+		return a.Drink.Id < b.Drink.Id
+	})
+}
+
+func limitMakeableHits(hits []MakeableHit, limit int) []MakeableHit {
+	if limit > 0 && limit < len(hits) {
+		return hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,80 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeableIds(hits []MakeableHit) []string {
+	ids := []string{}
+	for _, hit := range hits {
+		ids = append(ids, hit.Drink.Id)
+	}
+	return ids
+}
+
+func TestIndex_Makeable(t *testing.T) {
+	index := NewIndex(testDrinks, map[string]int{"11010": 2})
+	ingredients := []string{"Tequila", " lime  juice", "SODA WATER"}
+
+	tests := []struct {
+		name               string
+		query              MakeableQuery
+		expectedMakeable   []string
+		expectedMissingOne []string
+		expectedMissingTwo []string
+	}{
+		{
+			name:               "Suggests the drinks that are missing one or two ingredients",
+			query:              MakeableQuery{Ingredients: ingredients, MaxMissing: 2},
+			expectedMakeable:   []string{"11009"},
+			expectedMissingOne: []string{"11008"},
+			expectedMissingTwo: []string{"11010", "11007"},
+		},
+		{
+			name:               "Only the drinks that can be made",
+			query:              MakeableQuery{Ingredients: ingredients},
+			expectedMakeable:   []string{"11009"},
+			expectedMissingOne: []string{},
+			expectedMissingTwo: []string{},
+		},
+		{
+			name:               "Restricts the drinks",
+			query:              MakeableQuery{Ingredients: ingredients, MaxMissing: 2, DrinkIds: []string{"11008", "missing"}},
+			expectedMakeable:   []string{},
+			expectedMissingOne: []string{"11008"},
+			expectedMissingTwo: []string{},
+		},
+		{
+			name:               "Limits each list",
+			query:              MakeableQuery{Ingredients: ingredients, MaxMissing: 2, Limit: 1},
+			expectedMakeable:   []string{"11009"},
+			expectedMissingOne: []string{"11008"},
+			expectedMissingTwo: []string{"11010"},
+		},
+		{
+			name:               "Unknown ingredients don't match",
+			query:              MakeableQuery{Ingredients: []string{"whiskey", " "}, MaxMissing: 2},
+			expectedMakeable:   []string{},
+			expectedMissingOne: []string{},
+			expectedMissingTwo: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := index.Makeable(tt.query)
+			assert.Equal(t, tt.expectedMakeable, makeableIds(result.Makeable))
+			assert.Equal(t, tt.expectedMissingOne, makeableIds(result.MissingOne))
+			assert.Equal(t, tt.expectedMissingTwo, makeableIds(result.MissingTwo))
+		})
+	}
+}
+
+func TestIndex_Makeable_Missing(t *testing.T) {
+	index := NewIndex(testDrinks, nil)
+
+	result := index.Makeable(MakeableQuery{Ingredients: []string{"tequila", "lime juice"}, MaxMissing: 2})
+	assert.Equal(t, []string{"Agave syrup"}, result.MissingOne[1].Missing)
+	assert.Equal(t, []string{"Triple sec", "Salt"}, result.MissingTwo[1].Missing, "The missing ingredients should be in the recipe's order")
+}
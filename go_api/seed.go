@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"the-drink-almanac-api/catalog"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/seed"
+	"the-drink-almanac-api/service"
+)
+
+// runSeed creates the drinks, users and favorites of the fixture files, along with any synthetic users for load testing;
+// records that already exist are left untouched, so it can be run on every start of the local environment
+func runSeed(appConfig model.AppConfig, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	syntheticUsers := flags.Int("users", 0, "the number of synthetic users to generate")
+	favoritesPerUser := flags.Int("favorites-per-user", 0, "the number of favorites to generate for each synthetic user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 && *syntheticUsers == 0 {
+		return errors.New("usage: seed [-users <count>] [-favorites-per-user <count>] [fixture file ...]")
+	}
+
+	fixtures := seed.Fixtures{}
+	for _, path := range flags.Args() {
+		fileFixtures, err := seed.ReadFixtures(path)
+		if err != nil {
+			return err
+		}
+		fixtures = fixtures.Merge(fileFixtures)
+	}
+	fixtures = fixtures.Merge(seed.Synthetic(*syntheticUsers, *favoritesPerUser, fixtures.DrinkIds()))
+
+	userStore, favoriteStore, err := repository.NewRepositories(appConfig)
+	if err != nil {
+		return err
+	}
+	drinkStore, err := repository.NewDrinkRepository(appConfig)
+	if err != nil {
+		return err
+	}
+	recipeStore, err := repository.NewRecipeRepository(appConfig)
+	if err != nil {
+		return err
+	}
+	// the fixture drinks are saved in the catalog, but the favorites are checked by the same drink lookup and drinks
+	// as the api's, so seeding can't create orphaned favorites
+	servedDrinks, err := catalog.NewDrinkRepository(appConfig)
+	if err != nil {
+		return err
+	}
+	favoriteService, err := newFavoriteService(appConfig, favoriteStore, service.NewDefaultRecipeService(recipeStore), servedDrinks)
+	if err != nil {
+		return err
+	}
+	seeder := seed.NewSeeder(service.NewDefaultDrinkService(drinkStore), service.NewDefaultUserService(userStore), favoriteService)
+	report, err := seeder.Seed(fixtures)
+	if err != nil {
+		return fmt.Errorf("failed to seed: %w", err)
+	}
+
+	fmt.Printf("seeded drinks: %+v\n", report.Drinks)
+	fmt.Printf("seeded users: %+v\n", report.Users)
+	fmt.Printf("seeded favorites: %+v\n", report.Favorites)
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package seed loads fixtures of drinks, users and favorites into the api's repositories for local development and load testing.
+//
+// Records are written through the services, so seeded passwords are hashed like the ones of registered users
+// and the seeded users can log in.
+package seed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"the-drink-almanac-api/model"
+
+	jsoniter "github.com/json-iterator/go"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixtures is the contents of a YAML or JSON fixture file
+type Fixtures struct {
+	Users     []UserFixture     `json:"users" yaml:"users"`
+	Favorites []FavoriteFixture `json:"favorites" yaml:"favorites"`
+	// Drinks are added to the drink catalog, so that the favorites of them pass the drink lookup;
+	// the synthetic users' favorites are spread across them
+	Drinks []DrinkFixture `json:"drinks" yaml:"drinks"`
+}
+
+// UserFixture is a user along with their plaintext password
+type UserFixture struct {
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// FavoriteFixture refers to its user by username, since user ids are only assigned when the user is created
+type FavoriteFixture struct {
+	Username string `json:"username" yaml:"username"`
+	DrinkId  string `json:"drink_id" yaml:"drink_id"`
+}
+
+// DrinkFixture is a drink of the catalog; only the id and name are required
+type DrinkFixture struct {
+	Id        string `json:"id" yaml:"id"`
+	Name      string `json:"name" yaml:"name"`
+	Category  string `json:"category" yaml:"category"`
+	Glass     string `json:"glass" yaml:"glass"`
+	Alcoholic bool   `json:"alcoholic" yaml:"alcoholic"`
+}
+
+// Drink converts the fixture to the catalog's drink
+func (f DrinkFixture) Drink() model.Drink {
+	return model.Drink{Id: f.Id, Name: f.Name, Category: f.Category, Glass: f.Glass, Alcoholic: f.Alcoholic}
+}
+
+var strictJson = jsoniter.Config{DisallowUnknownFields: true}.Froze()
+
+// ReadFixtures reads a fixture file, using its extension to pick between YAML (.yaml or .yml) and JSON (.json);
+// unknown fields are rejected so that a typo doesn't silently drop data
+func ReadFixtures(path string) (Fixtures, error) {
+	var fixtures Fixtures
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return fixtures, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(contents))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&fixtures)
+		// an empty file has no documents, which is the same as having no fixtures
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".json":
+		err = strictJson.Unmarshal(contents, &fixtures)
+	default:
+		return fixtures, fmt.Errorf("the fixture file '%s' must have a .yaml, .yml or .json extension", path)
+	}
+	if err != nil {
+		return fixtures, fmt.Errorf("failed to read the fixture file '%s': %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// Merge appends the other fixtures to these, e.g. to load several fixture files at once
+func (f Fixtures) Merge(other Fixtures) Fixtures {
+	return Fixtures{
+		Users:     append(append([]UserFixture{}, f.Users...), other.Users...),
+		Favorites: append(append([]FavoriteFixture{}, f.Favorites...), other.Favorites...),
+		Drinks:    append(append([]DrinkFixture{}, f.Drinks...), other.Drinks...),
+	}
+}
+
+// Validate checks that every user has a unique username and a password,
+// that every favorite has a drink and belongs to one of the users, and that every drink has an id and a name
+func (f Fixtures) Validate() error {
+	usernames := map[string]bool{}
+	for i, user := range f.Users {
+		if user.Username == "" || user.Password == "" {
+			return fmt.Errorf("user %d must have a username and password", i)
+		}
+		if usernames[user.Username] {
+			return fmt.Errorf("the username '%s' is used by more than one user", user.Username)
+		}
+		usernames[user.Username] = true
+	}
+	for i, favorite := range f.Favorites {
+		if favorite.DrinkId == "" {
+			return fmt.Errorf("favorite %d must have a drink_id", i)
+		}
+		if !usernames[favorite.Username] {
+			return fmt.Errorf("favorite %d belongs to the username '%s', which isn't one of the users", i, favorite.Username)
+		}
+	}
+	for i, drink := range f.Drinks {
+		if drink.Id == "" || drink.Name == "" {
+			return fmt.Errorf("drink %d must have an id and a name", i)
+		}
+	}
+	return nil
+}
+
+// defaultDrinks are used for synthetic favorites when the fixtures don't have any drinks;
+// they're a few cocktails from TheCocktailDB, under their ids there
+var defaultDrinks = []DrinkFixture{
+	{Id: "11000", Name: "Mojito", Category: "Cocktail", Glass: "Highball glass", Alcoholic: true},
+	{Id: "11001", Name: "Old Fashioned", Category: "Cocktail", Glass: "Old-fashioned glass", Alcoholic: true},
+	{Id: "11002", Name: "Long Island Tea", Category: "Ordinary Drink", Glass: "Highball glass", Alcoholic: true},
+	{Id: "11003", Name: "Negroni", Category: "Ordinary Drink", Glass: "Old-fashioned glass", Alcoholic: true},
+	{Id: "11004", Name: "Whiskey Sour", Category: "Ordinary Drink", Glass: "Old-fashioned glass", Alcoholic: true},
+	{Id: "11005", Name: "Dry Martini", Category: "Cocktail", Glass: "Cocktail glass", Alcoholic: true},
+	{Id: "11006", Name: "Daiquiri", Category: "Ordinary Drink", Glass: "Cocktail glass", Alcoholic: true},
+	{Id: "11007", Name: "Margarita", Category: "Ordinary Drink", Glass: "Cocktail glass", Alcoholic: true},
+	{Id: "11008", Name: "Manhattan", Category: "Cocktail", Glass: "Cocktail glass", Alcoholic: true},
+	{Id: "11009", Name: "Moscow Mule", Category: "Punch / Party Drink", Glass: "Copper Mug", Alcoholic: true},
+}
+
+// Synthetic generates users and favorites for load testing; the same counts always generate the same fixtures,
+// so seeding them again doesn't create anything new
+//
+// Each user gets up to favoritesPerUser favorites of different drinks, taken from drinkIds in turn;
+// if there are no drinkIds, the favorites are of defaultDrinks, which are included in the fixtures
+func Synthetic(users, favoritesPerUser int, drinkIds []string) Fixtures {
+	fixtures := Fixtures{}
+	if len(drinkIds) == 0 && users > 0 && favoritesPerUser > 0 {
+		fixtures.Drinks = append(fixtures.Drinks, defaultDrinks...)
+		drinkIds = fixtures.DrinkIds()
+	}
+	if favoritesPerUser > len(drinkIds) {
+		favoritesPerUser = len(drinkIds)
+	}
+
+	for i := 0; i < users; i++ {
+		user := UserFixture{
+			Username: fmt.Sprintf("loadtest-user-%d", i),
+			Password: fmt.Sprintf("loadtest-password-%d", i),
+		}
+		fixtures.Users = append(fixtures.Users, user)
+		for j := 0; j < favoritesPerUser; j++ {
+			fixtures.Favorites = append(fixtures.Favorites, FavoriteFixture{
+				Username: user.Username,
+				DrinkId:  drinkIds[(i+j)%len(drinkIds)],
+			})
+		}
+	}
+	return fixtures
+}
+
+// DrinkIds returns the ids of the fixtures' drinks
+func (f Fixtures) DrinkIds() []string {
+	ids := []string{}
+	for _, drink := range f.Drinks {
+		ids = append(ids, drink.Id)
+	}
+	return ids
+}
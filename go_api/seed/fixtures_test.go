@@ -0,0 +1,180 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFixtures(t *testing.T) {
+	expectedFixtures := Fixtures{
+		Users:     []UserFixture{{Username: "test0", Password: "password0"}},
+		Favorites: []FavoriteFixture{{Username: "test0", DrinkId: "11007"}},
+		Drinks:    []DrinkFixture{{Id: "11007", Name: "Margarita"}},
+	}
+	tests := []struct {
+		name             string
+		fileName         string
+		contents         string
+		expectedFixtures Fixtures
+		expectError      bool
+	}{
+		{
+			name:     "Reads YAML",
+			fileName: "fixtures.yaml",
+			contents: `
+users:
+  - username: test0
+    password: password0
+favorites:
+  - username: test0
+    drink_id: "11007"
+drinks:
+  - id: "11007"
+    name: Margarita
+`,
+			expectedFixtures: expectedFixtures,
+		},
+		{
+			name:     "Reads JSON",
+			fileName: "fixtures.json",
+			contents: `{
+				"users": [{"username": "test0", "password": "password0"}],
+				"favorites": [{"username": "test0", "drink_id": "11007"}],
+				"drinks": [{"id": "11007", "name": "Margarita"}]
+			}`,
+			expectedFixtures: expectedFixtures,
+		},
+		{
+			name:     "Reads an empty YAML file",
+			fileName: "fixtures.yml",
+			contents: "",
+		},
+		{
+			name:        "Rejects unknown YAML fields",
+			fileName:    "fixtures.yaml",
+			contents:    "users:\n  - username: test0\n    pasword: password0\n",
+			expectError: true,
+		},
+		{
+			name:        "Rejects unknown JSON fields",
+			fileName:    "fixtures.json",
+			contents:    `{"user": []}`,
+			expectError: true,
+		},
+		{
+			name:        "Rejects other extensions",
+			fileName:    "fixtures.txt",
+			contents:    "users: []",
+			expectError: true,
+		},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), d.fileName)
+			assert.NoError(t, os.WriteFile(path, []byte(d.contents), 0o644), "No error should have been returned from WriteFile")
+
+			fixtures, err := ReadFixtures(path)
+			if d.expectError {
+				assert.Error(t, err, "An error should have been returned from ReadFixtures")
+				return
+			}
+			assert.NoError(t, err, "No error should have been returned from ReadFixtures")
+			assert.Equal(t, d.expectedFixtures, fixtures, "The fixtures don't match the file")
+		})
+	}
+}
+
+func TestFixtures_Validate(t *testing.T) {
+	user := UserFixture{Username: "test0", Password: "password0"}
+	tests := []struct {
+		name        string
+		fixtures    Fixtures
+		expectError bool
+	}{
+		{
+			name: "Valid fixtures",
+			fixtures: Fixtures{
+				Users:     []UserFixture{user},
+				Favorites: []FavoriteFixture{{Username: "test0", DrinkId: "11007"}},
+				Drinks:    []DrinkFixture{{Id: "11007", Name: "Margarita"}},
+			},
+		},
+		{
+			name:        "User without a password",
+			fixtures:    Fixtures{Users: []UserFixture{{Username: "test0"}}},
+			expectError: true,
+		},
+		{
+			name:        "Duplicate usernames",
+			fixtures:    Fixtures{Users: []UserFixture{user, user}},
+			expectError: true,
+		},
+		{
+			name: "Favorite of an unknown user",
+			fixtures: Fixtures{
+				Users:     []UserFixture{user},
+				Favorites: []FavoriteFixture{{Username: "test1", DrinkId: "11007"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "Favorite without a drink",
+			fixtures: Fixtures{
+				Users:     []UserFixture{user},
+				Favorites: []FavoriteFixture{{Username: "test0"}},
+			},
+			expectError: true,
+		},
+		{
+			name:        "Drink without an id",
+			fixtures:    Fixtures{Drinks: []DrinkFixture{{Name: "Margarita"}}},
+			expectError: true,
+		},
+		{
+			name:        "Drink without a name",
+			fixtures:    Fixtures{Drinks: []DrinkFixture{{Id: "11007"}}},
+			expectError: true,
+		},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			err := d.fixtures.Validate()
+			if d.expectError {
+				assert.Error(t, err, "An error should have been returned from Validate")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from Validate")
+			}
+		})
+	}
+}
+
+func TestSynthetic(t *testing.T) {
+	fixtures := Synthetic(3, 2, []string{"a", "b"})
+	assert.NoError(t, fixtures.Validate(), "The synthetic fixtures should be valid")
+	assert.Equal(t, []UserFixture{
+		{Username: "loadtest-user-0", Password: "loadtest-password-0"},
+		{Username: "loadtest-user-1", Password: "loadtest-password-1"},
+		{Username: "loadtest-user-2", Password: "loadtest-password-2"},
+	}, fixtures.Users, "The synthetic users don't match")
+	assert.Equal(t, []FavoriteFixture{
+		{Username: "loadtest-user-0", DrinkId: "a"},
+		{Username: "loadtest-user-0", DrinkId: "b"},
+		{Username: "loadtest-user-1", DrinkId: "b"},
+		{Username: "loadtest-user-1", DrinkId: "a"},
+		{Username: "loadtest-user-2", DrinkId: "a"},
+		{Username: "loadtest-user-2", DrinkId: "b"},
+	}, fixtures.Favorites, "The synthetic favorites don't match")
+
+	assert.Empty(t, fixtures.Drinks, "The given drinks are already in the fixtures")
+
+	fixtures = Synthetic(1, 20, nil)
+	assert.NoError(t, fixtures.Validate(), "The synthetic fixtures should be valid")
+	assert.Equal(t, defaultDrinks, fixtures.Drinks, "The default drinks should be seeded along with their favorites")
+	assert.Len(t, fixtures.Favorites, len(defaultDrinks), "A user can't favorite more drinks than there are")
+	assert.Equal(t, fixtures, Synthetic(1, 20, nil), "The same counts should generate the same fixtures")
+}
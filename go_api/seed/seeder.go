@@ -0,0 +1,95 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/service"
+)
+
+// SeedCounts is the number of records of a single type that were created or already existed
+type SeedCounts struct {
+	Created  int
+	Existing int
+}
+
+type Report struct {
+	Drinks    SeedCounts
+	Users     SeedCounts
+	Favorites SeedCounts
+}
+
+// Seeder writes fixtures through the services
+type Seeder struct {
+	drinks    service.DrinkService
+	users     service.UserService
+	favorites service.FavoriteService
+}
+
+func NewSeeder(drinks service.DrinkService, users service.UserService, favorites service.FavoriteService) Seeder {
+	return Seeder{
+		drinks:    drinks,
+		users:     users,
+		favorites: favorites,
+	}
+}
+
+// Seed creates every drink, user and favorite of the fixtures that doesn't exist yet, so it can safely be run again;
+// existing drinks are matched by id and left unchanged, and existing users are matched by username
+// and keep their current password
+//
+// The drinks are added to the catalog first, so that the favorites of them pass the favorite service's drink lookup
+func (s Seeder) Seed(fixtures Fixtures) (Report, error) {
+	report := Report{}
+	if err := fixtures.Validate(); err != nil {
+		return report, err
+	}
+
+	if len(fixtures.Drinks) > 0 {
+		existingDrinks, err := s.drinks.FindDrinksByIds(fixtures.DrinkIds())
+		if err != nil {
+			return report, fmt.Errorf("failed to read the drinks: %w", err)
+		}
+		// a drink listed twice is only saved once
+		seeded := map[string]bool{}
+		for _, fixture := range fixtures.Drinks {
+			if _, ok := existingDrinks[fixture.Id]; ok || seeded[fixture.Id] {
+				report.Drinks.Existing++
+				continue
+			}
+			if err := s.drinks.SaveDrink(fixture.Drink()); err != nil {
+				return report, fmt.Errorf("failed to seed the drink '%s': %w", fixture.Id, err)
+			}
+			seeded[fixture.Id] = true
+			report.Drinks.Created++
+		}
+	}
+
+	userIds := map[string]string{}
+	for _, fixture := range fixtures.Users {
+		user, err := s.users.CreateNewUser(fixture.Username, fixture.Password)
+		switch {
+		case errors.As(err, &apperrors.UserAlreadyExistsError{}):
+			report.Users.Existing++
+		case err != nil:
+			return report, fmt.Errorf("failed to seed the user '%s': %w", fixture.Username, err)
+		default:
+			report.Users.Created++
+		}
+		userIds[fixture.Username] = user.Id
+	}
+
+	for _, fixture := range fixtures.Favorites {
+		_, err := s.favorites.CreateNewFavorite(fixture.DrinkId, userIds[fixture.Username])
+		switch {
+		case errors.As(err, &apperrors.FavoriteAlreadyExistsError{}):
+			report.Favorites.Existing++
+		case err != nil:
+			return report, fmt.Errorf("failed to seed the favorite of '%s' for the drink '%s': %w", fixture.Username, fixture.DrinkId, err)
+		default:
+			report.Favorites.Created++
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,121 @@
+package seed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/service"
+)
+
+func TestSeeder_Seed(t *testing.T) {
+	fixtures := Fixtures{
+		Users: []UserFixture{
+			{Username: "test0", Password: "password0"},
+			{Username: "test1", Password: "password1"},
+		},
+		Favorites: []FavoriteFixture{
+			{Username: "test0", DrinkId: "11007"},
+			{Username: "test1", DrinkId: "11007"},
+		},
+	}
+	withDrinks := fixtures
+	withDrinks.Drinks = []DrinkFixture{
+		{Id: "11007", Name: "Margarita", Alcoholic: true},
+		{Id: "11000", Name: "Mojito", Alcoholic: true},
+		{Id: "11007", Name: "Margarita", Alcoholic: true},
+	}
+	tests := []struct {
+		name           string
+		fixtures       Fixtures
+		mockCalls      func(drinks *service.MockDrinkService, users *service.MockUserService, favorites *service.MockFavoriteService)
+		expectedReport Report
+		expectError    bool
+	}{
+		{
+			name:     "Creates missing records and keeps existing ones",
+			fixtures: fixtures,
+			mockCalls: func(drinks *service.MockDrinkService, users *service.MockUserService, favorites *service.MockFavoriteService) {
+				users.On("CreateNewUser", "test0", "password0").Return(&model.User{Id: "user0"}, nil)
+				users.On("CreateNewUser", "test1", "password1").Return(&model.User{Id: "user1"}, apperrors.NewUserAlreadyExistsError("test1"))
+				favorites.On("CreateNewFavorite", "11007", "user0").Return(&model.Favorite{}, nil)
+				favorites.On("CreateNewFavorite", "11007", "user1").Return(&model.Favorite{}, apperrors.NewFavoriteAlreadyExistsError("exists"))
+			},
+			expectedReport: Report{
+				Users:     SeedCounts{Created: 1, Existing: 1},
+				Favorites: SeedCounts{Created: 1, Existing: 1},
+			},
+		},
+		{
+			name:     "Adds the missing drinks to the catalog before their favorites",
+			fixtures: withDrinks,
+			mockCalls: func(drinks *service.MockDrinkService, users *service.MockUserService, favorites *service.MockFavoriteService) {
+				drinks.On("FindDrinksByIds", []string{"11007", "11000", "11007"}).Return(map[string]model.Drink{"11000": {Id: "11000", Name: "Mojito"}}, nil)
+				drinks.On("SaveDrink", model.Drink{Id: "11007", Name: "Margarita", Alcoholic: true}).Return(nil).Once()
+				users.On("CreateNewUser", "test0", "password0").Return(&model.User{Id: "user0"}, nil)
+				users.On("CreateNewUser", "test1", "password1").Return(&model.User{Id: "user1"}, nil)
+				favorites.On("CreateNewFavorite", "11007", "user0").Return(&model.Favorite{}, nil)
+				favorites.On("CreateNewFavorite", "11007", "user1").Return(&model.Favorite{}, nil)
+			},
+			expectedReport: Report{
+				Drinks:    SeedCounts{Created: 1, Existing: 2},
+				Users:     SeedCounts{Created: 2},
+				Favorites: SeedCounts{Created: 2},
+			},
+		},
+		{
+			name:     "Failed to save a drink",
+			fixtures: withDrinks,
+			mockCalls: func(drinks *service.MockDrinkService, users *service.MockUserService, favorites *service.MockFavoriteService) {
+				drinks.On("FindDrinksByIds", []string{"11007", "11000", "11007"}).Return(map[string]model.Drink{}, nil)
+				drinks.On("SaveDrink", model.Drink{Id: "11007", Name: "Margarita", Alcoholic: true}).Return(errors.New("failed"))
+			},
+			expectError: true,
+		},
+		{
+			name:     "Invalid fixtures aren't seeded",
+			fixtures: Fixtures{Favorites: []FavoriteFixture{{Username: "test0", DrinkId: "11007"}}},
+			mockCalls: func(drinks *service.MockDrinkService, users *service.MockUserService, favorites *service.MockFavoriteService) {
+			},
+			expectError: true,
+		},
+		{
+			name:     "Failed to create a user",
+			fixtures: fixtures,
+			mockCalls: func(drinks *service.MockDrinkService, users *service.MockUserService, favorites *service.MockFavoriteService) {
+				users.On("CreateNewUser", "test0", "password0").Return(nil, errors.New("failed"))
+			},
+			expectError: true,
+		},
+		{
+			name:     "Failed to create a favorite",
+			fixtures: fixtures,
+			mockCalls: func(drinks *service.MockDrinkService, users *service.MockUserService, favorites *service.MockFavoriteService) {
+				users.On("CreateNewUser", "test0", "password0").Return(&model.User{Id: "user0"}, nil)
+				users.On("CreateNewUser", "test1", "password1").Return(&model.User{Id: "user1"}, nil)
+				favorites.On("CreateNewFavorite", "11007", "user0").Return(nil, errors.New("failed"))
+			},
+			expectedReport: Report{Users: SeedCounts{Created: 2}},
+			expectError:    true,
+		},
+	}
+
+	for _, d := range tests {
+		t.Run(d.name, func(t *testing.T) {
+			drinks := service.NewMockDrinkService(t)
+			users := service.NewMockUserService(t)
+			favorites := service.NewMockFavoriteService(t)
+			d.mockCalls(drinks, users, favorites)
+
+			report, err := NewSeeder(drinks, users, favorites).Seed(d.fixtures)
+			if d.expectError {
+				assert.Error(t, err, "An error should have been returned from Seed")
+			} else {
+				assert.NoError(t, err, "No error should have been returned from Seed")
+			}
+			assert.Equal(t, d.expectedReport, report, "The report doesn't match the seeded records")
+		})
+	}
+}
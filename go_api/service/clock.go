@@ -0,0 +1,16 @@
+package service
+
+import "time"
+
+// Clock provides the current time to the services, so that timestamps can be controlled in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock used by default, which returns the current UTC time
+// truncated to the millisecond precision that timestamps are stored with
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
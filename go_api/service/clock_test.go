@@ -0,0 +1,21 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedClock always returns the same time so that tests can assert on timestamps
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestSystemClock_Now(t *testing.T) {
+	now := SystemClock{}.Now()
+	assert.Equal(t, time.UTC, now.Location(), "The system clock should return UTC times")
+	assert.Equal(t, now, now.Truncate(time.Millisecond), "The system clock should truncate times to milliseconds")
+}
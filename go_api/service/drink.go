@@ -0,0 +1,60 @@
+//go:generate mockery --name=DrinkService --output=./ --outpkg=service --filename=drink_mock.go --inpackage
+package service
+
+import (
+	"fmt"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+type DrinkService interface {
+	FindAllDrinks() ([]model.Drink, error)
+
+	// FindDrinkById retrieves the drink from the catalog, or nil if there isn't a drink with that id
+	FindDrinkById(id string) (*model.Drink, error)
+
+	// FindDrinksByIds retrieves the drinks with the given ids in one go, mapped by id;
+	// drinks that aren't in the catalog are left out
+	FindDrinksByIds(ids []string) (map[string]model.Drink, error)
+
+	// SaveDrink adds the drink to the catalog or replaces the drink with the same id
+	SaveDrink(drink model.Drink) error
+}
+
+func NewDefaultDrinkService(repo repository.DrinkRepository) DefaultDrinkService {
+	return DefaultDrinkService{
+		repo: repo,
+	}
+}
+
+type DefaultDrinkService struct {
+	repo repository.DrinkRepository
+}
+
+func (s DefaultDrinkService) FindAllDrinks() ([]model.Drink, error) {
+	return s.repo.FindAll()
+}
+
+func (s DefaultDrinkService) FindDrinkById(id string) (*model.Drink, error) {
+	return s.repo.FindDrinkById(id)
+}
+
+func (s DefaultDrinkService) FindDrinksByIds(ids []string) (map[string]model.Drink, error) {
+	return s.repo.FindDrinksByIds(ids)
+}
+
+func (s DefaultDrinkService) SaveDrink(drink model.Drink) error {
+	if drink.Id == "" {
+		return fmt.Errorf("the drink's id must not be empty")
+	}
+	if drink.Name == "" {
+		return fmt.Errorf("the drink '%s' must have a name", drink.Id)
+	}
+	for i, ingredient := range drink.Ingredients {
+		if ingredient.Name == "" {
+			return fmt.Errorf("ingredient %d of the drink '%s' must have a name", i, drink.Id)
+		}
+	}
+	return s.repo.SaveDrink(drink)
+}
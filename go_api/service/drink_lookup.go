@@ -0,0 +1,32 @@
+//go:generate mockery --name=DrinkLookup --output=./ --outpkg=service --filename=drink_lookup_mock.go --inpackage
+package service
+
+import (
+	"the-drink-almanac-api/repository"
+)
+
+// DrinkLookup checks whether drinks exist, so that favorites can't refer to drinks that don't
+type DrinkLookup interface {
+	DrinkExists(drinkId string) (bool, error)
+}
+
+// UncheckedDrinkLookup accepts every drink id; it's the lookup used when favorites aren't validated
+type UncheckedDrinkLookup struct{}
+
+func (UncheckedDrinkLookup) DrinkExists(drinkId string) (bool, error) {
+	return true, nil
+}
+
+// CatalogDrinkLookup checks the drink catalog
+type CatalogDrinkLookup struct {
+	drinks repository.DrinkRepository
+}
+
+func NewCatalogDrinkLookup(drinks repository.DrinkRepository) CatalogDrinkLookup {
+	return CatalogDrinkLookup{drinks: drinks}
+}
+
+func (l CatalogDrinkLookup) DrinkExists(drinkId string) (bool, error) {
+	drink, err := l.drinks.FindDrinkById(drinkId)
+	return drink != nil, err
+}
@@ -0,0 +1,48 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockDrinkLookup is an autogenerated mock type for the DrinkLookup type
+type MockDrinkLookup struct {
+	mock.Mock
+}
+
+// DrinkExists provides a mock function with given fields: drinkId
+func (_m *MockDrinkLookup) DrinkExists(drinkId string) (bool, error) {
+	ret := _m.Called(drinkId)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(drinkId)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(drinkId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(drinkId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockDrinkLookup creates a new instance of MockDrinkLookup. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDrinkLookup(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDrinkLookup {
+	mock := &MockDrinkLookup{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogDrinkLookup_DrinkExists(t *testing.T) {
+	mockDrinkRepo := repository.NewMockDrinkRepository(t)
+	mockDrinkRepo.On("FindDrinkById", "11007").Return(&model.Drink{Id: "11007"}, nil)
+	mockDrinkRepo.On("FindDrinkById", "banana").Return(nil, nil)
+	mockDrinkRepo.On("FindDrinkById", "0").Return(nil, errors.New("failed"))
+	lookup := NewCatalogDrinkLookup(mockDrinkRepo)
+
+	exists, err := lookup.DrinkExists("11007")
+	assert.NoError(t, err, "No error should have been returned from DrinkExists")
+	assert.True(t, exists, "A drink in the catalog should exist")
+
+	exists, err = lookup.DrinkExists("banana")
+	assert.NoError(t, err, "No error should have been returned from DrinkExists")
+	assert.False(t, exists, "A drink that isn't in the catalog shouldn't exist")
+
+	_, err = lookup.DrinkExists("0")
+	assert.Error(t, err, "An error should have been returned from DrinkExists")
+}
@@ -0,0 +1,120 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockDrinkService is an autogenerated mock type for the DrinkService type
+type MockDrinkService struct {
+	mock.Mock
+}
+
+// FindAllDrinks provides a mock function with given fields:
+func (_m *MockDrinkService) FindAllDrinks() ([]model.Drink, error) {
+	ret := _m.Called()
+
+	var r0 []model.Drink
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]model.Drink, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.Drink); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Drink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDrinkById provides a mock function with given fields: id
+func (_m *MockDrinkService) FindDrinkById(id string) (*model.Drink, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Drink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Drink, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Drink); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Drink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDrinksByIds provides a mock function with given fields: ids
+func (_m *MockDrinkService) FindDrinksByIds(ids []string) (map[string]model.Drink, error) {
+	ret := _m.Called(ids)
+
+	var r0 map[string]model.Drink
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]string) (map[string]model.Drink, error)); ok {
+		return rf(ids)
+	}
+	if rf, ok := ret.Get(0).(func([]string) map[string]model.Drink); ok {
+		r0 = rf(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]model.Drink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveDrink provides a mock function with given fields: drink
+func (_m *MockDrinkService) SaveDrink(drink model.Drink) error {
+	ret := _m.Called(drink)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Drink) error); ok {
+		r0 = rf(drink)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockDrinkService creates a new instance of MockDrinkService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDrinkService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDrinkService {
+	mock := &MockDrinkService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
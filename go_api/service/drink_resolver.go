@@ -0,0 +1,85 @@
+//go:generate mockery --name=DrinkResolver --output=./ --outpkg=service --filename=drink_resolver_mock.go --inpackage
+package service
+
+import (
+	"the-drink-almanac-api/model"
+)
+
+// DrinkResolver finds the drinks that favorites and requests refer to by id, which are either drinks of the catalog
+// or users' recipes; it's what every feature that reads a favorite's drink goes through, so recipe favorites work
+// everywhere that drink favorites do
+type DrinkResolver interface {
+	// FindDrink retrieves the drink, or the recipe as a drink if the user can see it; it's nil if there's neither.
+	// An empty userId is an anonymous user, who can only see unlisted and public recipes
+	FindDrink(userId, drinkId string) (*model.Drink, error)
+
+	// FindDrinks is FindDrink for many ids in one go, mapped by id; ids that don't resolve are left out
+	FindDrinks(userId string, drinkIds []string) (map[string]model.Drink, error)
+}
+
+// NewDefaultDrinkResolver creates the resolver; recipe ids are only resolved if recipes is set
+func NewDefaultDrinkResolver(drinks DrinkService, recipes RecipeService) DefaultDrinkResolver {
+	return DefaultDrinkResolver{
+		drinks:  drinks,
+		recipes: recipes,
+	}
+}
+
+type DefaultDrinkResolver struct {
+	drinks  DrinkService
+	recipes RecipeService
+}
+
+func (r DefaultDrinkResolver) FindDrink(userId, drinkId string) (*model.Drink, error) {
+	if !model.IsRecipeId(drinkId) {
+		return r.drinks.FindDrinkById(drinkId)
+	}
+	if r.recipes == nil {
+		return nil, nil
+	}
+	recipe, err := r.recipes.FindRecipe(userId, drinkId)
+	if err != nil || recipe == nil {
+		return nil, err
+	}
+	drink := recipe.Drink()
+	return &drink, nil
+}
+
+// FindDrinks reads the catalog's drinks in one batch, and each recipe once
+func (r DefaultDrinkResolver) FindDrinks(userId string, drinkIds []string) (map[string]model.Drink, error) {
+	catalogIds := []string{}
+	recipeIds := []string{}
+	for _, id := range drinkIds {
+		if model.IsRecipeId(id) {
+			recipeIds = append(recipeIds, id)
+		} else {
+			catalogIds = append(catalogIds, id)
+		}
+	}
+
+	drinks := map[string]model.Drink{}
+	if len(catalogIds) > 0 {
+		found, err := r.drinks.FindDrinksByIds(catalogIds)
+		if err != nil {
+			return nil, err
+		}
+		for id, drink := range found {
+			drinks[id] = drink
+		}
+	}
+	seen := map[string]bool{}
+	for _, id := range recipeIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		drink, err := r.FindDrink(userId, id)
+		if err != nil {
+			return nil, err
+		}
+		if drink != nil {
+			drinks[id] = *drink
+		}
+	}
+	return drinks, nil
+}
@@ -0,0 +1,80 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockDrinkResolver is an autogenerated mock type for the DrinkResolver type
+type MockDrinkResolver struct {
+	mock.Mock
+}
+
+// FindDrink provides a mock function with given fields: userId, drinkId
+func (_m *MockDrinkResolver) FindDrink(userId string, drinkId string) (*model.Drink, error) {
+	ret := _m.Called(userId, drinkId)
+
+	var r0 *model.Drink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.Drink, error)); ok {
+		return rf(userId, drinkId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.Drink); ok {
+		r0 = rf(userId, drinkId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Drink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, drinkId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDrinks provides a mock function with given fields: userId, drinkIds
+func (_m *MockDrinkResolver) FindDrinks(userId string, drinkIds []string) (map[string]model.Drink, error) {
+	ret := _m.Called(userId, drinkIds)
+
+	var r0 map[string]model.Drink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []string) (map[string]model.Drink, error)); ok {
+		return rf(userId, drinkIds)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string) map[string]model.Drink); ok {
+		r0 = rf(userId, drinkIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]model.Drink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string) error); ok {
+		r1 = rf(userId, drinkIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockDrinkResolver creates a new instance of MockDrinkResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDrinkResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDrinkResolver {
+	mock := &MockDrinkResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"the-drink-almanac-api/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultDrinkResolver_FindDrink(t *testing.T) {
+	margarita := &model.Drink{Id: "11007", Name: "Margarita"}
+	recipe := &model.Recipe{Id: "recipe-0", OwnerId: "0", Name: "House Sour", Visibility: model.RecipePrivate}
+	tests := []struct {
+		name          string
+		drinkId       string
+		mockCalls     func(drinkService *MockDrinkService, recipeService *MockRecipeService)
+		expectedDrink *model.Drink
+		expectError   bool
+	}{
+		{
+			name:    "Finds a drink of the catalog",
+			drinkId: "11007",
+			mockCalls: func(drinkService *MockDrinkService, recipeService *MockRecipeService) {
+				drinkService.On("FindDrinkById", "11007").Return(margarita, nil)
+			},
+			expectedDrink: margarita,
+		},
+		{
+			name:    "Finds a recipe that the user can see as a drink",
+			drinkId: "recipe-0",
+			mockCalls: func(drinkService *MockDrinkService, recipeService *MockRecipeService) {
+				recipeService.On("FindRecipe", "0", "recipe-0").Return(recipe, nil)
+			},
+			expectedDrink: &model.Drink{Id: "recipe-0", Name: "House Sour"},
+		},
+		{
+			name:    "Doesn't find a recipe that the user can't see",
+			drinkId: "recipe-0",
+			mockCalls: func(drinkService *MockDrinkService, recipeService *MockRecipeService) {
+				recipeService.On("FindRecipe", "0", "recipe-0").Return(nil, nil)
+			},
+		},
+		{
+			name:    "Failed to find the recipe",
+			drinkId: "recipe-0",
+			mockCalls: func(drinkService *MockDrinkService, recipeService *MockRecipeService) {
+				recipeService.On("FindRecipe", "0", "recipe-0").Return(nil, errors.New("failed"))
+			},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drinkService := NewMockDrinkService(t)
+			recipeService := NewMockRecipeService(t)
+			tt.mockCalls(drinkService, recipeService)
+
+			drink, err := NewDefaultDrinkResolver(drinkService, recipeService).FindDrink("0", tt.drinkId)
+			assert.Equal(t, tt.expectError, err != nil, "Unexpected error: %v", err)
+			assert.Equal(t, tt.expectedDrink, drink)
+		})
+	}
+
+	drink, err := NewDefaultDrinkResolver(NewMockDrinkService(t), nil).FindDrink("0", "recipe-0")
+	assert.NoError(t, err, "No error should have been returned without recipes")
+	assert.Nil(t, drink, "Recipes shouldn't be found without recipes")
+}
+
+func TestDefaultDrinkResolver_FindDrinks(t *testing.T) {
+	margarita := model.Drink{Id: "11007", Name: "Margarita"}
+	drinkService := NewMockDrinkService(t)
+	drinkService.On("FindDrinksByIds", []string{"11007", "missing"}).Return(map[string]model.Drink{"11007": margarita}, nil).Once()
+	recipeService := NewMockRecipeService(t)
+	recipeService.On("FindRecipe", "0", "recipe-0").Return(&model.Recipe{Id: "recipe-0", Name: "House Sour"}, nil).Once()
+	recipeService.On("FindRecipe", "0", "recipe-1").Return(nil, nil).Once()
+
+	drinks, err := NewDefaultDrinkResolver(drinkService, recipeService).
+		FindDrinks("0", []string{"11007", "recipe-0", "missing", "recipe-1", "recipe-0", "recipe-1"})
+	assert.NoError(t, err, "No error should have been returned from FindDrinks")
+	assert.Equal(t, map[string]model.Drink{"11007": margarita, "recipe-0": {Id: "recipe-0", Name: "House Sour"}}, drinks,
+		"The catalog's drinks and the recipes that the user can see should have been found, each recipe once")
+
+	drinks, err = NewDefaultDrinkResolver(NewMockDrinkService(t), recipeService).FindDrinks("0", nil)
+	assert.NoError(t, err, "No error should have been returned from FindDrinks")
+	assert.Empty(t, drinks, "Nothing should have been read without ids")
+
+	failingDrinks := NewMockDrinkService(t)
+	failingDrinks.On("FindDrinksByIds", []string{"11007"}).Return(nil, errors.New("failed"))
+	_, err = NewDefaultDrinkResolver(failingDrinks, recipeService).FindDrinks("0", []string{"11007"})
+	assert.Error(t, err, "An error should have been returned from FindDrinks")
+}
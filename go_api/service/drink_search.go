@@ -0,0 +1,175 @@
+//go:generate mockery --name=DrinkSearchService --output=./ --outpkg=service --filename=drink_search_mock.go --inpackage
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/search"
+)
+
+type DrinkSearchService interface {
+	// SearchDrinks returns the page of the catalog's drinks that match the query
+	SearchDrinks(query search.Query) (search.Result, error)
+
+	// FindMakeableDrinks returns the drinks that can be made, or almost made, with the query's ingredients;
+	// if favoritesOf is a user id, only that user's favorite drinks are matched
+	FindMakeableDrinks(query search.MakeableQuery, favoritesOf string) (search.MakeableResult, error)
+}
+
+// NewIndexedDrinkSearchService creates a search over the drinks returned by loadDrinks, ranked by popularity using
+// the favorites; Start builds the index and rebuilds it every refreshInterval, or never if it's 0
+//
+// The favorites are optional; without them, every drink has a popularity of 0. The drinks resolve a user's favorites
+// when makeable drinks are restricted to them, so that their recipes that aren't public, and aren't indexed, are
+// matched too; without them, only the indexed favorites are matched
+func NewIndexedDrinkSearchService(loadDrinks func() ([]model.Drink, error), favorites repository.FavoriteRepository,
+	drinks DrinkResolver, refreshInterval time.Duration) *IndexedDrinkSearchService {
+	return &IndexedDrinkSearchService{
+		loadDrinks:      loadDrinks,
+		favorites:       favorites,
+		drinks:          drinks,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// IndexedDrinkSearchService searches an in-memory index of the catalog; a rebuilt index is swapped in once it's
+// complete, so searches never wait for a rebuild
+type IndexedDrinkSearchService struct {
+	loadDrinks      func() ([]model.Drink, error)
+	favorites       repository.FavoriteRepository
+	drinks          DrinkResolver
+	refreshInterval time.Duration
+
+	// building lets one build run at a time
+	building sync.Mutex
+	// index holds the current *search.Index
+	index atomic.Value
+}
+
+// Start builds the index and then rebuilds it in the background every refreshInterval until the context is done;
+// if the first build fails, the error is returned and the first search builds the index instead
+func (s *IndexedDrinkSearchService) Start(ctx context.Context) error {
+	err := s.Refresh()
+	if s.refreshInterval > 0 {
+		go s.run(ctx)
+	}
+	return err
+}
+
+// run rebuilds the index every refreshInterval; the previous index is kept if a rebuild fails,
+// since slightly outdated results are better than none
+func (s *IndexedDrinkSearchService) run(ctx context.Context) {
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.Refresh(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to refresh the drink search index: %v\n", err)
+		}
+	}
+}
+
+// Refresh rebuilds the index from the current drinks and favorites and swaps it in;
+// searches keep using the previous index until then
+func (s *IndexedDrinkSearchService) Refresh() error {
+	s.building.Lock()
+	defer s.building.Unlock()
+	return s.build()
+}
+
+func (s *IndexedDrinkSearchService) build() error {
+	drinks, err := s.loadDrinks()
+	if err != nil {
+		return err
+	}
+	popularity := map[string]int{}
+	if s.favorites != nil {
+		favorites, err := s.favorites.FindAll()
+		if err != nil {
+			return err
+		}
+		for _, favorite := range favorites {
+			popularity[favorite.DrinkId]++
+		}
+	}
+	s.index.Store(search.NewIndex(drinks, popularity))
+	return nil
+}
+
+// currentIndex returns the current index, building it only if there isn't one yet,
+// e.g. because the service wasn't started or its first build failed
+func (s *IndexedDrinkSearchService) currentIndex() (*search.Index, error) {
+	if index, ok := s.index.Load().(*search.Index); ok {
+		return index, nil
+	}
+	s.building.Lock()
+	defer s.building.Unlock()
+	// another search may have built it while this one waited
+	if index, ok := s.index.Load().(*search.Index); ok {
+		return index, nil
+	}
+	if err := s.build(); err != nil {
+		return nil, err
+	}
+	return s.index.Load().(*search.Index), nil
+}
+
+func (s *IndexedDrinkSearchService) SearchDrinks(query search.Query) (search.Result, error) {
+	index, err := s.currentIndex()
+	if err != nil {
+		return search.Result{}, err
+	}
+	return index.Search(query), nil
+}
+
+func (s *IndexedDrinkSearchService) FindMakeableDrinks(query search.MakeableQuery, favoritesOf string) (search.MakeableResult, error) {
+	if favoritesOf != "" {
+		if s.favorites == nil {
+			return search.MakeableResult{}, fmt.Errorf("the favorites aren't available to restrict the drinks to")
+		}
+		favorites, err := s.favorites.FindFavoritesByUser(favoritesOf)
+		if err != nil {
+			return search.MakeableResult{}, err
+		}
+		query.DrinkIds = make([]string, len(favorites))
+		for i, favorite := range favorites {
+			query.DrinkIds[i] = favorite.DrinkId
+		}
+	}
+	index, err := s.currentIndex()
+	if err != nil {
+		return search.MakeableResult{}, err
+	}
+	if favoritesOf != "" && s.drinks != nil {
+		return s.findMakeableFavorites(index, query, favoritesOf)
+	}
+	return index.Makeable(query), nil
+}
+
+// findMakeableFavorites matches the user's favorites, resolved like every other feature resolves them, in an index
+// of their own, which keeps the popularity that the drinks have in the search index
+func (s *IndexedDrinkSearchService) findMakeableFavorites(index *search.Index, query search.MakeableQuery, userId string) (search.MakeableResult, error) {
+	drinksById, err := s.drinks.FindDrinks(userId, query.DrinkIds)
+	if err != nil {
+		return search.MakeableResult{}, err
+	}
+	drinks := make([]model.Drink, 0, len(drinksById))
+	popularity := map[string]int{}
+	for id, drink := range drinksById {
+		drinks = append(drinks, drink)
+		popularity[id] = index.Popularity(id)
+	}
+	query.DrinkIds = nil
+	return search.NewIndex(drinks, popularity).Makeable(query), nil
+}
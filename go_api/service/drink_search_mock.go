@@ -0,0 +1,76 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	search "the-drink-almanac-api/search"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockDrinkSearchService is an autogenerated mock type for the DrinkSearchService type
+type MockDrinkSearchService struct {
+	mock.Mock
+}
+
+// FindMakeableDrinks provides a mock function with given fields: query, favoritesOf
+func (_m *MockDrinkSearchService) FindMakeableDrinks(query search.MakeableQuery, favoritesOf string) (search.MakeableResult, error) {
+	ret := _m.Called(query, favoritesOf)
+
+	var r0 search.MakeableResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(search.MakeableQuery, string) (search.MakeableResult, error)); ok {
+		return rf(query, favoritesOf)
+	}
+	if rf, ok := ret.Get(0).(func(search.MakeableQuery, string) search.MakeableResult); ok {
+		r0 = rf(query, favoritesOf)
+	} else {
+		r0 = ret.Get(0).(search.MakeableResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(search.MakeableQuery, string) error); ok {
+		r1 = rf(query, favoritesOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchDrinks provides a mock function with given fields: query
+func (_m *MockDrinkSearchService) SearchDrinks(query search.Query) (search.Result, error) {
+	ret := _m.Called(query)
+
+	var r0 search.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(search.Query) (search.Result, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(search.Query) search.Result); ok {
+		r0 = rf(query)
+	} else {
+		r0 = ret.Get(0).(search.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(search.Query) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockDrinkSearchService creates a new instance of MockDrinkSearchService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockDrinkSearchService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockDrinkSearchService {
+	mock := &MockDrinkSearchService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
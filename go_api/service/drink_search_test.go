@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+	"the-drink-almanac-api/search"
+)
+
+func TestIndexedDrinkSearchService_SearchDrinks(t *testing.T) {
+	drinks := []model.Drink{{Id: "11007", Name: "Margarita"}}
+	loads := 0
+	var loadError error
+	loadDrinks := func() ([]model.Drink, error) {
+		loads++
+		return drinks, loadError
+	}
+	mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindAll").Return([]model.Favorite{{DrinkId: "11007"}, {DrinkId: "11007"}}, nil)
+	searchService := NewIndexedDrinkSearchService(loadDrinks, mockFavoriteRepo, nil, 0)
+	assert.NoError(t, searchService.Start(context.TODO()), "The index should have been built by Start")
+	assert.Equal(t, 1, loads)
+
+	result, err := searchService.SearchDrinks(search.Query{Text: "margarita"})
+	assert.NoError(t, err, "No error should have been returned from SearchDrinks")
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, 2, result.Hits[0].Popularity, "The popularity should be the number of favorites")
+
+	// searches never rebuild the index
+	drinks = append(drinks, model.Drink{Id: "11008", Name: "Tommy's Margarita"})
+	result, err = searchService.SearchDrinks(search.Query{Text: "margarita"})
+	assert.NoError(t, err, "No error should have been returned from SearchDrinks")
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, 1, loads)
+
+	assert.NoError(t, searchService.Refresh())
+	result, err = searchService.SearchDrinks(search.Query{Text: "margarita"})
+	assert.NoError(t, err, "No error should have been returned from SearchDrinks")
+	assert.Equal(t, 2, result.Total, "The rebuilt index should have been swapped in")
+
+	// the previous index is still used if it can't be rebuilt
+	loadError = errors.New("testing")
+	assert.Error(t, searchService.Refresh())
+	result, err = searchService.SearchDrinks(search.Query{Text: "margarita"})
+	assert.NoError(t, err, "The previous index should have been used")
+	assert.Equal(t, 2, result.Total)
+}
+
+func TestIndexedDrinkSearchService_SearchDrinks_DuringRefresh(t *testing.T) {
+	drinks := []model.Drink{{Id: "11007", Name: "Margarita"}}
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	slow := false
+	searchService := NewIndexedDrinkSearchService(func() ([]model.Drink, error) {
+		if slow {
+			close(loading)
+			<-release
+		}
+		return drinks, nil
+	}, nil, nil, 0)
+	assert.NoError(t, searchService.Start(context.TODO()))
+
+	slow = true
+	drinks = append(drinks, model.Drink{Id: "11008", Name: "Tommy's Margarita"})
+	refreshed := make(chan error)
+	go func() { refreshed <- searchService.Refresh() }()
+	<-loading
+
+	result, err := searchService.SearchDrinks(search.Query{Text: "margarita"})
+	assert.NoError(t, err, "A search shouldn't wait for the rebuild")
+	assert.Equal(t, 1, result.Total, "The previous index should be used until the rebuild is complete")
+
+	close(release)
+	assert.NoError(t, <-refreshed)
+	result, err = searchService.SearchDrinks(search.Query{Text: "margarita"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+}
+
+func TestIndexedDrinkSearchService_Start_RebuildsInTheBackground(t *testing.T) {
+	var loads int32
+	searchService := NewIndexedDrinkSearchService(func() ([]model.Drink, error) {
+		atomic.AddInt32(&loads, 1)
+		return nil, nil
+	}, nil, nil, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, searchService.Start(ctx))
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&loads) > 2 }, time.Second, time.Millisecond,
+		"The index should have been rebuilt every refresh interval")
+}
+
+func TestIndexedDrinkSearchService_SearchDrinks_LoadError(t *testing.T) {
+	searchService := NewIndexedDrinkSearchService(func() ([]model.Drink, error) {
+		return nil, errors.New("testing")
+	}, nil, nil, 0)
+
+	assert.Error(t, searchService.Start(context.TODO()), "The failed build should have been returned from Start")
+	_, err := searchService.SearchDrinks(search.Query{})
+	assert.Error(t, err, "An error should have been returned when there's no index")
+}
+
+func TestIndexedDrinkSearchService_SearchDrinks_NotStarted(t *testing.T) {
+	searchService := NewIndexedDrinkSearchService(func() ([]model.Drink, error) {
+		return []model.Drink{{Id: "11007", Name: "Margarita"}}, nil
+	}, nil, nil, 0)
+
+	result, err := searchService.SearchDrinks(search.Query{Text: "margarita"})
+	assert.NoError(t, err, "The first search should have built the missing index")
+	assert.Equal(t, 1, result.Total)
+}
+
+func TestIndexedDrinkSearchService_FindMakeableDrinks(t *testing.T) {
+	drinks := []model.Drink{
+		{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila"}, {Name: "Lime juice"}}},
+		{Id: "11009", Name: "Lime Spritz", Ingredients: []model.Ingredient{{Name: "Lime juice"}, {Name: "Soda water"}}},
+	}
+	mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindAll").Return([]model.Favorite{}, nil)
+	mockFavoriteRepo.On("FindFavoritesByUser", "0").Return([]model.Favorite{{UserId: "0", DrinkId: "11009"}}, nil)
+	searchService := NewIndexedDrinkSearchService(func() ([]model.Drink, error) { return drinks, nil }, mockFavoriteRepo, nil, 0)
+	query := search.MakeableQuery{Ingredients: []string{"tequila", "lime juice"}, MaxMissing: 1}
+
+	result, err := searchService.FindMakeableDrinks(query, "")
+	assert.NoError(t, err, "No error should have been returned from FindMakeableDrinks")
+	assert.Len(t, result.Makeable, 1)
+	assert.Len(t, result.MissingOne, 1)
+
+	result, err = searchService.FindMakeableDrinks(query, "0")
+	assert.NoError(t, err, "No error should have been returned from FindMakeableDrinks")
+	assert.Empty(t, result.Makeable, "Only the user's favorites should have been matched")
+	assert.Equal(t, "11009", result.MissingOne[0].Drink.Id)
+}
+
+func TestIndexedDrinkSearchService_FindMakeableDrinks_RecipeFavorites(t *testing.T) {
+	margarita := model.Drink{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila"}, {Name: "Lime juice"}}}
+	// the user's private recipe isn't indexed, so it's only matched through the resolver
+	houseSour := model.Drink{Id: "recipe-0", Name: "House Sour", Ingredients: []model.Ingredient{{Name: "Bourbon"}, {Name: "Lime juice"}}}
+	favorites := []model.Favorite{{UserId: "0", DrinkId: "11007"}, {UserId: "0", DrinkId: "recipe-0"}}
+	mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindAll").Return(favorites, nil)
+	mockFavoriteRepo.On("FindFavoritesByUser", "0").Return(favorites, nil)
+	mockDrinkResolver := NewMockDrinkResolver(t)
+	mockDrinkResolver.On("FindDrinks", "0", []string{"11007", "recipe-0"}).
+		Return(map[string]model.Drink{"11007": margarita, "recipe-0": houseSour}, nil)
+	searchService := NewIndexedDrinkSearchService(func() ([]model.Drink, error) { return []model.Drink{margarita}, nil },
+		mockFavoriteRepo, mockDrinkResolver, 0)
+
+	result, err := searchService.FindMakeableDrinks(search.MakeableQuery{Ingredients: []string{"bourbon", "lime juice"}, MaxMissing: 1}, "0")
+	assert.NoError(t, err, "No error should have been returned from FindMakeableDrinks")
+	assert.Equal(t, "recipe-0", result.Makeable[0].Drink.Id, "The recipe favorite should have been matched")
+	assert.Equal(t, "11007", result.MissingOne[0].Drink.Id)
+	assert.Equal(t, 1, result.MissingOne[0].Popularity, "The indexed drinks should keep their popularity")
+}
+
+func TestIndexedDrinkSearchService_FindMakeableDrinks_FavoritesError(t *testing.T) {
+	mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindFavoritesByUser", "0").Return(nil, errors.New("testing"))
+	searchService := NewIndexedDrinkSearchService(func() ([]model.Drink, error) { return nil, nil }, mockFavoriteRepo, nil, 0)
+
+	_, err := searchService.FindMakeableDrinks(search.MakeableQuery{Ingredients: []string{"gin"}}, "0")
+	assert.Error(t, err, "The favorites' error should have been returned from FindMakeableDrinks")
+}
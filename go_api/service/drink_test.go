@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+func TestDefaultDrinkService_FindDrinkById(t *testing.T) {
+	drink := &model.Drink{Id: "11007", Name: "Margarita"}
+	tests := []struct {
+		name          string
+		returnedDrink *model.Drink
+		returnedError error
+		expectError   bool
+	}{
+		{
+			name:          "Successfully retrieved drink",
+			returnedDrink: drink,
+		},
+		{
+			name: "Drink isn't in the catalog",
+		},
+		{
+			name:          "Failed to retrieve drink",
+			returnedError: fmt.Errorf("failed to retrieve drink"),
+			expectError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDrinkRepo := repository.NewMockDrinkRepository(t)
+			mockDrinkRepo.On("FindDrinkById", "11007").Return(tt.returnedDrink, tt.returnedError)
+			drinkService := NewDefaultDrinkService(mockDrinkRepo)
+			foundDrink, err := drinkService.FindDrinkById("11007")
+			assert.Equal(t, tt.returnedDrink, foundDrink)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from drinkService.FindDrinkById")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from drinkService.FindDrinkById")
+			}
+		})
+	}
+}
+
+func TestDefaultDrinkService_FindDrinksByIds(t *testing.T) {
+	drinks := map[string]model.Drink{"11007": {Id: "11007", Name: "Margarita"}}
+	mockDrinkRepo := repository.NewMockDrinkRepository(t)
+	mockDrinkRepo.On("FindDrinksByIds", []string{"11007", "banana", "11007"}).Return(drinks, nil).Once()
+	drinkService := NewDefaultDrinkService(mockDrinkRepo)
+
+	foundDrinks, err := drinkService.FindDrinksByIds([]string{"11007", "banana", "11007"})
+	assert.Nil(t, err, "No error should have been returned from drinkService.FindDrinksByIds")
+	assert.Equal(t, drinks, foundDrinks, "The drinks that are in the catalog should have been returned")
+}
+
+func TestDefaultDrinkService_SaveDrink(t *testing.T) {
+	tests := []struct {
+		name          string
+		drink         model.Drink
+		isStoreCalled bool
+		returnedError error
+		expectError   bool
+	}{
+		{
+			name:          "Successfully saved drink",
+			drink:         model.Drink{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "1 1/2 oz"}}},
+			isStoreCalled: true,
+		},
+		{
+			name:          "Failed to save drink",
+			drink:         model.Drink{Id: "11007", Name: "Margarita"},
+			isStoreCalled: true,
+			returnedError: fmt.Errorf("failed to save drink"),
+			expectError:   true,
+		},
+		{
+			name:        "Missing id",
+			drink:       model.Drink{Name: "Margarita"},
+			expectError: true,
+		},
+		{
+			name:        "Missing name",
+			drink:       model.Drink{Id: "11007"},
+			expectError: true,
+		},
+		{
+			name:        "Ingredient without a name",
+			drink:       model.Drink{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Measure: "1 oz"}}},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDrinkRepo := repository.NewMockDrinkRepository(t)
+			if tt.isStoreCalled {
+				mockDrinkRepo.On("SaveDrink", tt.drink).Return(tt.returnedError)
+			}
+			err := NewDefaultDrinkService(mockDrinkRepo).SaveDrink(tt.drink)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from drinkService.SaveDrink")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from drinkService.SaveDrink")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 
 	"the-drink-almanac-api/apperrors"
@@ -17,19 +18,55 @@ type FavoriteService interface {
 	// FindFavoritesByUser retrieves favorites based on the user's id
 	FindFavoritesByUser(userId string) ([]model.Favorite, error)
 
+	// FindFavoritesByUserSortedByCreation retrieves the user's favorites ordered by when they were created,
+	// either oldest or newest first
+	FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error)
+
+	// FindFavoriteByUserAndDrink retrieves the user's favorite for the given drink,
+	// or nil if the user hasn't favorited that drink
+	FindFavoriteByUserAndDrink(userId, drinkId string) (*model.Favorite, error)
+
 	// CreateNewFavorite either creates a new favorite if one doesn't exist with the given drinkId and userId
-	// or returns the existing favorite and the FavoriteAlreadyExistsError
+	// or returns the existing favorite and the FavoriteAlreadyExistsError;
+	// the DrinkNotFoundError is returned if the drink doesn't exist
 	CreateNewFavorite(userId, drinkId string) (*model.Favorite, error)
 
 	DeleteFavorite(id string) error
 }
 
+// NewDefaultFavoriteService creates a service that accepts favorites of any drink id; use WithDrinkLookup to check them
 func NewDefaultFavoriteService(repo repository.FavoriteRepository) DefaultFavoriteService {
-	return DefaultFavoriteService{repo: repo}
+	return DefaultFavoriteService{
+		repo:   repo,
+		clock:  SystemClock{},
+		drinks: UncheckedDrinkLookup{},
+	}
 }
 
 type DefaultFavoriteService struct {
-	repo repository.FavoriteRepository
+	repo    repository.FavoriteRepository
+	clock   Clock
+	drinks  DrinkLookup
+	recipes RecipeService
+}
+
+// WithClock returns a copy of the service that uses the given clock for timestamps
+func (s DefaultFavoriteService) WithClock(clock Clock) DefaultFavoriteService {
+	s.clock = clock
+	return s
+}
+
+// WithDrinkLookup returns a copy of the service that only creates favorites of drinks that exist in the lookup
+func (s DefaultFavoriteService) WithDrinkLookup(drinks DrinkLookup) DefaultFavoriteService {
+	s.drinks = drinks
+	return s
+}
+
+// WithRecipes returns a copy of the service that also creates favorites of users' recipes, as long as the user can see them;
+// without it, recipe ids are checked by the drink lookup like any other drink id
+func (s DefaultFavoriteService) WithRecipes(recipes RecipeService) DefaultFavoriteService {
+	s.recipes = recipes
+	return s
 }
 
 func (s DefaultFavoriteService) FindAllFavorites() ([]model.Favorite, error) {
@@ -40,6 +77,14 @@ func (s DefaultFavoriteService) FindFavoritesByUser(userId string) ([]model.Favo
 	return s.repo.FindFavoritesByUser(userId)
 }
 
+func (s DefaultFavoriteService) FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error) {
+	return s.repo.FindFavoritesByUserSortedByCreation(userId, newestFirst)
+}
+
+func (s DefaultFavoriteService) FindFavoriteByUserAndDrink(userId, drinkId string) (*model.Favorite, error) {
+	return s.repo.FindFavoriteByUserAndDrink(userId, drinkId)
+}
+
 func (s DefaultFavoriteService) CreateNewFavorite(drinkId, userId string) (*model.Favorite, error) {
 	if drinkId == "" {
 		return nil, fmt.Errorf("the drinkId must not be empty")
@@ -48,39 +93,82 @@ func (s DefaultFavoriteService) CreateNewFavorite(drinkId, userId string) (*mode
 		return nil, fmt.Errorf("the userId must not be empty")
 	}
 
-	// check if a favorite already exists for this drink/user id pair
-	userFavorites, err := s.repo.FindFavoritesByUser(userId)
+	exists, err := s.drinkExists(userId, drinkId)
 	if err != nil {
 		return nil, err
 	}
+	if !exists {
+		return nil, apperrors.NewDrinkNotFoundError(drinkId)
+	}
 
-	doesFavoriteExist := false
-	var existingFavorite *model.Favorite
-	for _, favorite := range userFavorites {
-		if favorite.DrinkId == drinkId && favorite.UserId == userId {
-			doesFavoriteExist = true
-			existingFavorite = &favorite
-			break
-		}
+	// check if a favorite already exists for this drink/user id pair
+	existingFavorite, err := s.repo.FindFavoriteByUserAndDrink(userId, drinkId)
+	if err != nil {
+		return nil, err
 	}
-	if doesFavoriteExist {
+	if existingFavorite != nil {
 		return existingFavorite, apperrors.NewFavoriteAlreadyExistsError("the user already favorited this drink")
 	}
 
-	favoriteUuid := uuid.New()
+	now := s.clock.Now()
 	newFavorite := model.Favorite{
-		Id:      favoriteUuid.String(),
-		UserId:  userId,
-		DrinkId: drinkId,
+		Id:        NewFavoriteId(userId, drinkId),
+		UserId:    userId,
+		DrinkId:   drinkId,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
 	}
-	err = s.repo.CreateNewFavorite(newFavorite)
+	err = s.repo.CreateNewFavorite(newFavorite, model.Event{
+		Id:         uuid.NewString(),
+		Type:       model.FavoriteAdded,
+		OccurredAt: now,
+		UserId:     userId,
+		FavoriteId: newFavorite.Id,
+		DrinkId:    drinkId,
+	})
 	if err != nil {
+		// the repository rejects duplicates atomically, so another request may have
+		// created the same favorite between the lookup above and the insert
+		if errors.As(err, &apperrors.FavoriteAlreadyExistsError{}) {
+			return &newFavorite, err
+		}
 		return nil, err
 	}
 
 	return &newFavorite, nil
 }
 
+// drinkExists checks recipe ids against the user's recipes and every other id against the drink lookup
+func (s DefaultFavoriteService) drinkExists(userId, drinkId string) (bool, error) {
+	if s.recipes != nil && model.IsRecipeId(drinkId) {
+		recipe, err := s.recipes.FindRecipe(userId, drinkId)
+		return recipe != nil, err
+	}
+	return s.drinks.DrinkExists(drinkId)
+}
+
+// DeleteFavorite records the FavoriteRemoved event along with the delete; deleting a favorite that doesn't exist does nothing
 func (s DefaultFavoriteService) DeleteFavorite(id string) error {
-	return s.repo.DeleteFavorite(id)
+	favorite, err := s.repo.FindFavoriteById(id)
+	if err != nil || favorite == nil {
+		return err
+	}
+	return s.repo.DeleteFavorite(id, model.Event{
+		Id:         uuid.NewString(),
+		Type:       model.FavoriteRemoved,
+		OccurredAt: s.clock.Now(),
+		UserId:     favorite.UserId,
+		FavoriteId: favorite.Id,
+		DrinkId:    favorite.DrinkId,
+	})
+}
+
+// favoriteNamespace is used to derive deterministic favorite ids from user and drink ids
+var favoriteNamespace = uuid.MustParse("5b4c8f1e-2d6a-4c1b-9a57-0f3e1c7d2b90")
+
+// NewFavoriteId generates the same uuid for every favorite with the given user and drink ids,
+// which lets the repository reject duplicate favorites with a conditional insert
+func NewFavoriteId(userId, drinkId string) string {
+	return uuid.NewSHA1(favoriteNamespace, []byte(userId+"#"+drinkId)).String()
 }
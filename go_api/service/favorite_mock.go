@@ -79,6 +79,32 @@ func (_m *MockFavoriteService) FindAllFavorites() ([]model.Favorite, error) {
 	return r0, r1
 }
 
+// FindFavoriteByUserAndDrink provides a mock function with given fields: userId, drinkId
+func (_m *MockFavoriteService) FindFavoriteByUserAndDrink(userId string, drinkId string) (*model.Favorite, error) {
+	ret := _m.Called(userId, drinkId)
+
+	var r0 *model.Favorite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.Favorite, error)); ok {
+		return rf(userId, drinkId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.Favorite); ok {
+		r0 = rf(userId, drinkId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Favorite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, drinkId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindFavoritesByUser provides a mock function with given fields: userId
 func (_m *MockFavoriteService) FindFavoritesByUser(userId string) ([]model.Favorite, error) {
 	ret := _m.Called(userId)
@@ -105,6 +131,32 @@ func (_m *MockFavoriteService) FindFavoritesByUser(userId string) ([]model.Favor
 	return r0, r1
 }
 
+// FindFavoritesByUserSortedByCreation provides a mock function with given fields: userId, newestFirst
+func (_m *MockFavoriteService) FindFavoritesByUserSortedByCreation(userId string, newestFirst bool) ([]model.Favorite, error) {
+	ret := _m.Called(userId, newestFirst)
+
+	var r0 []model.Favorite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, bool) ([]model.Favorite, error)); ok {
+		return rf(userId, newestFirst)
+	}
+	if rf, ok := ret.Get(0).(func(string, bool) []model.Favorite); ok {
+		r0 = rf(userId, newestFirst)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Favorite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(userId, newestFirst)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMockFavoriteService creates a new instance of MockFavoriteService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockFavoriteService(t interface {
@@ -1,12 +1,15 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
 	"the-drink-almanac-api/model"
 	"the-drink-almanac-api/repository"
 )
@@ -66,98 +69,111 @@ func TestDefaultFavoriteService_FindAllFavorites(t *testing.T) {
 }
 
 func TestDefaultFavoriteService_CreateNewFavorite(t *testing.T) {
-	mockFavorites := []model.Favorite{
+	existingFavorite := &model.Favorite{
+		Id:      NewFavoriteId("0", "0"),
+		UserId:  "0",
+		DrinkId: "0",
+	}
+	tests := []struct {
+		name                                    string
+		userId                                  string
+		drinkId                                 string
+		isStoreCreateNewFavoriteCalled          bool
+		isStoreFindFavoriteByUserAndDrinkCalled bool
+		returnedError                           error
+		existingFavorite                        *model.Favorite
+		existingFavoriteError                   error
+		expectError                             bool
+		expectAlreadyExistsError                bool
+	}{
 		{
-			Id:      "0",
-			UserId:  "0",
-			DrinkId: "0",
+			name:                                    "Successfully create favorite",
+			userId:                                  "-1",
+			drinkId:                                 "0",
+			isStoreCreateNewFavoriteCalled:          true,
+			isStoreFindFavoriteByUserAndDrinkCalled: true,
+			returnedError:                           nil,
+			existingFavorite:                        nil,
+			existingFavoriteError:                   nil,
+			expectError:                             false,
 		},
 		{
-			Id:      "1",
-			UserId:  "1",
-			DrinkId: "1",
+			name:                                    "Failed to create favorites",
+			userId:                                  "-1",
+			drinkId:                                 "0",
+			isStoreCreateNewFavoriteCalled:          true,
+			isStoreFindFavoriteByUserAndDrinkCalled: true,
+			returnedError:                           fmt.Errorf("failed to create favorites"),
+			existingFavorite:                        nil,
+			existingFavoriteError:                   nil,
+			expectError:                             true,
 		},
 		{
-			Id:      "2",
-			UserId:  "2",
-			DrinkId: "2",
+			name:                                    "Failed to look up existing favorite",
+			userId:                                  "0",
+			drinkId:                                 "0",
+			isStoreCreateNewFavoriteCalled:          false,
+			isStoreFindFavoriteByUserAndDrinkCalled: true,
+			returnedError:                           nil,
+			existingFavorite:                        nil,
+			existingFavoriteError:                   fmt.Errorf("failed to look up favorite"),
+			expectError:                             true,
+		},
+		{
+			name:                                    "Favorite already exists",
+			userId:                                  "0",
+			drinkId:                                 "0",
+			isStoreCreateNewFavoriteCalled:          false,
+			isStoreFindFavoriteByUserAndDrinkCalled: true,
+			returnedError:                           nil,
+			existingFavorite:                        existingFavorite,
+			existingFavoriteError:                   nil,
+			expectError:                             true,
+			expectAlreadyExistsError:                true,
+		},
+		{
+			name:                                    "Favorite created concurrently",
+			userId:                                  "0",
+			drinkId:                                 "0",
+			isStoreCreateNewFavoriteCalled:          true,
+			isStoreFindFavoriteByUserAndDrinkCalled: true,
+			returnedError:                           apperrors.NewFavoriteAlreadyExistsError("favorite already exists"),
+			existingFavorite:                        nil,
+			existingFavoriteError:                   nil,
+			expectError:                             true,
+			expectAlreadyExistsError:                true,
+		},
+		{
+			name:                                    "UserId is empty",
+			userId:                                  "",
+			drinkId:                                 "0",
+			isStoreCreateNewFavoriteCalled:          false,
+			isStoreFindFavoriteByUserAndDrinkCalled: false,
+			returnedError:                           nil,
+			existingFavorite:                        nil,
+			existingFavoriteError:                   nil,
+			expectError:                             true,
 		},
-	}
-	tests := []struct {
-		name                            string
-		userId                          string
-		drinkId                         string
-		isStoreCreateNewFavoriteCalled  bool
-		isStoreFindFavoriteByUserCalled bool
-		returnedError                   error
-		existingFavorites               []model.Favorite
-		existingFavoritesError          error
-		expectError                     bool
-	}{
 		{
-			name:                            "Successfully create favorite",
-			userId:                          "-1",
-			drinkId:                         "0",
-			isStoreCreateNewFavoriteCalled:  true,
-			isStoreFindFavoriteByUserCalled: true,
-			returnedError:                   nil,
-			existingFavorites:               mockFavorites,
-			existingFavoritesError:          nil,
-			expectError:                     false,
-		},
-		{
-			name:                            "Failed to create favorites",
-			userId:                          "-1",
-			drinkId:                         "0",
-			isStoreCreateNewFavoriteCalled:  true,
-			isStoreFindFavoriteByUserCalled: true,
-			returnedError:                   fmt.Errorf("failed to create favorites"),
-			existingFavorites:               mockFavorites,
-			existingFavoritesError:          nil,
-			expectError:                     true,
-		},
-		{
-			name:                            "Favorite already exists",
-			userId:                          "0",
-			drinkId:                         "0",
-			isStoreCreateNewFavoriteCalled:  false,
-			isStoreFindFavoriteByUserCalled: true,
-			returnedError:                   nil,
-			existingFavorites:               mockFavorites,
-			existingFavoritesError:          fmt.Errorf("favorite already exists"),
-			expectError:                     true,
-		},
-		{
-			name:                            "UserId is empty",
-			userId:                          "",
-			drinkId:                         "0",
-			isStoreCreateNewFavoriteCalled:  false,
-			isStoreFindFavoriteByUserCalled: false,
-			returnedError:                   nil,
-			existingFavorites:               nil,
-			existingFavoritesError:          nil,
-			expectError:                     true,
-		},
-		{
-			name:                            "DrinkId is empty",
-			userId:                          "0",
-			drinkId:                         "",
-			isStoreCreateNewFavoriteCalled:  false,
-			isStoreFindFavoriteByUserCalled: false,
-			returnedError:                   nil,
-			existingFavorites:               nil,
-			existingFavoritesError:          nil,
-			expectError:                     true,
+			name:                                    "DrinkId is empty",
+			userId:                                  "0",
+			drinkId:                                 "",
+			isStoreCreateNewFavoriteCalled:          false,
+			isStoreFindFavoriteByUserAndDrinkCalled: false,
+			returnedError:                           nil,
+			existingFavorite:                        nil,
+			existingFavoriteError:                   nil,
+			expectError:                             true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
-			if tt.isStoreFindFavoriteByUserCalled {
-				mockFavoriteRepo.On("FindFavoritesByUser", tt.userId).Return(tt.existingFavorites, tt.existingFavoritesError)
+			if tt.isStoreFindFavoriteByUserAndDrinkCalled {
+				mockFavoriteRepo.On("FindFavoriteByUserAndDrink", tt.userId, tt.drinkId).Return(tt.existingFavorite, tt.existingFavoriteError)
 			}
 			if tt.isStoreCreateNewFavoriteCalled {
-				mockFavoriteRepo.On("CreateNewFavorite", mock.AnythingOfType("model.Favorite")).Return(tt.returnedError)
+				mockFavoriteRepo.On("CreateNewFavorite", mock.AnythingOfType("model.Favorite"), mock.AnythingOfType("model.Event")).Return(tt.returnedError)
 			}
 
 			favoriteService := NewDefaultFavoriteService(mockFavoriteRepo)
@@ -167,6 +183,7 @@ func TestDefaultFavoriteService_CreateNewFavorite(t *testing.T) {
 			} else {
 				assert.Nil(t, err, "No error should have been returned from favoriteService.CreateNewFavorite")
 			}
+			assert.Equal(t, tt.expectAlreadyExistsError, errors.As(err, &apperrors.FavoriteAlreadyExistsError{}))
 
 			// check that the new favorite has a uuid for Id, UserId matches the provided userId,
 			// and the DrinkId is the hashed version of the provided drinkId
@@ -181,6 +198,229 @@ func TestDefaultFavoriteService_CreateNewFavorite(t *testing.T) {
 	}
 }
 
+func TestDefaultFavoriteService_CreateNewFavorite_DrinkLookup(t *testing.T) {
+	tests := []struct {
+		name                     string
+		drinkExists              bool
+		lookupError              error
+		expectDrinkNotFoundError bool
+	}{
+		{
+			name:        "Drink exists",
+			drinkExists: true,
+		},
+		{
+			name:                     "Drink doesn't exist",
+			drinkExists:              false,
+			expectDrinkNotFoundError: true,
+		},
+		{
+			name:        "Failed to look up the drink",
+			lookupError: fmt.Errorf("failed to look up the drink"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+			mockDrinkLookup := NewMockDrinkLookup(t)
+			mockDrinkLookup.On("DrinkExists", "banana").Return(tt.drinkExists, tt.lookupError)
+			if tt.drinkExists {
+				mockFavoriteRepo.On("FindFavoriteByUserAndDrink", "0", "banana").Return(nil, nil)
+				mockFavoriteRepo.On("CreateNewFavorite", mock.AnythingOfType("model.Favorite"), mock.AnythingOfType("model.Event")).Return(nil)
+			}
+
+			favoriteService := NewDefaultFavoriteService(mockFavoriteRepo).WithDrinkLookup(mockDrinkLookup)
+			favorite, err := favoriteService.CreateNewFavorite("banana", "0")
+			if tt.drinkExists {
+				assert.Nil(t, err, "No error should have been returned from favoriteService.CreateNewFavorite")
+				assert.NotNil(t, favorite, "The favorite should have been created")
+			} else {
+				assert.NotNil(t, err, "An error should have been returned from favoriteService.CreateNewFavorite")
+				assert.Nil(t, favorite, "No favorite should have been created")
+			}
+			assert.Equal(t, tt.expectDrinkNotFoundError, errors.As(err, &apperrors.DrinkNotFoundError{}))
+		})
+	}
+}
+
+func TestDefaultFavoriteService_CreateNewFavorite_Recipes(t *testing.T) {
+	recipeId := model.RecipeIdPrefix + "0"
+	tests := []struct {
+		name                     string
+		recipe                   *model.Recipe
+		expectDrinkNotFoundError bool
+	}{
+		{
+			name:   "Recipe is visible to the user",
+			recipe: &model.Recipe{Id: recipeId, OwnerId: "1", Visibility: model.RecipeUnlisted},
+		},
+		{
+			name:                     "Recipe doesn't exist or is another user's private recipe",
+			expectDrinkNotFoundError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+			// recipes aren't in the drink lookup, so it must not be asked about them
+			mockDrinkLookup := NewMockDrinkLookup(t)
+			mockRecipeService := NewMockRecipeService(t)
+			mockRecipeService.On("FindRecipe", "0", recipeId).Return(tt.recipe, nil)
+			if tt.recipe != nil {
+				mockFavoriteRepo.On("FindFavoriteByUserAndDrink", "0", recipeId).Return(nil, nil)
+				mockFavoriteRepo.On("CreateNewFavorite", mock.AnythingOfType("model.Favorite"), mock.AnythingOfType("model.Event")).Return(nil)
+			}
+
+			favoriteService := NewDefaultFavoriteService(mockFavoriteRepo).WithDrinkLookup(mockDrinkLookup).WithRecipes(mockRecipeService)
+			favorite, err := favoriteService.CreateNewFavorite(recipeId, "0")
+			if tt.recipe != nil {
+				assert.Nil(t, err, "No error should have been returned from favoriteService.CreateNewFavorite")
+				assert.Equal(t, recipeId, favorite.DrinkId, "The recipe should have been favorited")
+			} else {
+				assert.Nil(t, favorite, "No favorite should have been created")
+			}
+			assert.Equal(t, tt.expectDrinkNotFoundError, errors.As(err, &apperrors.DrinkNotFoundError{}))
+		})
+	}
+}
+
+func TestDefaultFavoriteService_CreateNewFavorite_Timestamps(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindFavoriteByUserAndDrink", "0", "0").Return(nil, nil)
+	mockFavoriteRepo.On("CreateNewFavorite", mock.MatchedBy(func(favorite model.Favorite) bool {
+		return favorite.CreatedAt.Equal(now) && favorite.UpdatedAt.Equal(now)
+	}), mock.MatchedBy(func(event model.Event) bool {
+		return event.Type == model.FavoriteAdded && event.OccurredAt.Equal(now) && event.UserId == "0" && event.DrinkId == "0"
+	})).Return(nil)
+
+	favoriteService := NewDefaultFavoriteService(mockFavoriteRepo).WithClock(fixedClock(now))
+	favorite, err := favoriteService.CreateNewFavorite("0", "0")
+	assert.Nil(t, err, "No error should have been returned from favoriteService.CreateNewFavorite")
+	assert.Equal(t, now, favorite.CreatedAt)
+	assert.Equal(t, now, favorite.UpdatedAt)
+	assert.Equal(t, 1, favorite.Version)
+}
+
+func TestDefaultFavoriteService_FindFavoritesByUserSortedByCreation(t *testing.T) {
+	mockFavorites := []model.Favorite{
+		{
+			Id:        "1",
+			UserId:    "0",
+			DrinkId:   "1",
+			CreatedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Id:        "0",
+			UserId:    "0",
+			DrinkId:   "0",
+			CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	tests := []struct {
+		name              string
+		id                string
+		newestFirst       bool
+		returnedFavorites []model.Favorite
+		returnedError     error
+		expectError       bool
+	}{
+		{
+			name:              "Successfully retrieved favorites",
+			id:                "0",
+			newestFirst:       true,
+			returnedFavorites: mockFavorites,
+			returnedError:     nil,
+			expectError:       false,
+		},
+		{
+			name:              "Failed to retrieve favorites",
+			id:                "0",
+			newestFirst:       false,
+			returnedFavorites: nil,
+			returnedError:     fmt.Errorf("failed to retrieve favorites"),
+			expectError:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+			mockFavoriteRepo.On("FindFavoritesByUserSortedByCreation", tt.id, tt.newestFirst).Return(tt.returnedFavorites, tt.returnedError)
+			favoriteService := NewDefaultFavoriteService(mockFavoriteRepo)
+			favorites, err := favoriteService.FindFavoritesByUserSortedByCreation(tt.id, tt.newestFirst)
+			assert.Equal(t, tt.returnedFavorites, favorites)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from favoriteService.FindFavoritesByUserSortedByCreation")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from favoriteService.FindFavoritesByUserSortedByCreation")
+			}
+			mockFavoriteRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultFavoriteService_FindFavoriteByUserAndDrink(t *testing.T) {
+	mockFavorite := &model.Favorite{
+		Id:      "0",
+		UserId:  "0",
+		DrinkId: "0",
+	}
+	tests := []struct {
+		name             string
+		userId           string
+		drinkId          string
+		returnedFavorite *model.Favorite
+		returnedError    error
+		expectError      bool
+	}{
+		{
+			name:             "Successfully retrieved favorite",
+			userId:           "0",
+			drinkId:          "0",
+			returnedFavorite: mockFavorite,
+			returnedError:    nil,
+			expectError:      false,
+		},
+		{
+			name:             "No favorite exists",
+			userId:           "0",
+			drinkId:          "1",
+			returnedFavorite: nil,
+			returnedError:    nil,
+			expectError:      false,
+		},
+		{
+			name:             "Failed to retrieve favorite",
+			userId:           "0",
+			drinkId:          "0",
+			returnedFavorite: nil,
+			returnedError:    fmt.Errorf("failed to retrieve favorite"),
+			expectError:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+			mockFavoriteRepo.On("FindFavoriteByUserAndDrink", tt.userId, tt.drinkId).Return(tt.returnedFavorite, tt.returnedError)
+			favoriteService := NewDefaultFavoriteService(mockFavoriteRepo)
+			favorite, err := favoriteService.FindFavoriteByUserAndDrink(tt.userId, tt.drinkId)
+			assert.Equal(t, tt.returnedFavorite, favorite)
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from favoriteService.FindFavoriteByUserAndDrink")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from favoriteService.FindFavoriteByUserAndDrink")
+			}
+			mockFavoriteRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewFavoriteId(t *testing.T) {
+	assert.Equal(t, NewFavoriteId("0", "0"), NewFavoriteId("0", "0"), "The same user and drink ids should always produce the same favorite id")
+	assert.NotEqual(t, NewFavoriteId("0", "1"), NewFavoriteId("0", "0"), "Different drink ids should produce different favorite ids")
+	assert.NotEqual(t, NewFavoriteId("01", "0"), NewFavoriteId("0", "10"), "The user and drink ids should not be ambiguous when combined")
+}
+
 func TestDefaultFavoriteService_FindFavoritesByUser(t *testing.T) {
 	mockFavorites := []model.Favorite{
 		{
@@ -235,28 +475,50 @@ func TestDefaultFavoriteService_FindFavoritesByUser(t *testing.T) {
 
 func TestDefaultFavoriteService_DeleteFavorite(t *testing.T) {
 	tests := []struct {
-		name          string
-		id            string
-		returnedError error
-		expectError   bool
+		name             string
+		id               string
+		existingFavorite *model.Favorite
+		findError        error
+		returnedError    error
+		expectError      bool
 	}{
 		{
-			name:          "Successfully deleted the favorite",
-			id:            "0",
-			returnedError: nil,
-			expectError:   false,
+			name:             "Successfully deleted the favorite",
+			id:               "0",
+			existingFavorite: &model.Favorite{Id: "0", UserId: "1", DrinkId: "2"},
+			returnedError:    nil,
+			expectError:      false,
+		},
+		{
+			name:             "Failed to delete the favorite",
+			id:               "0",
+			existingFavorite: &model.Favorite{Id: "0", UserId: "1", DrinkId: "2"},
+			returnedError:    fmt.Errorf("failed to delete the favorite"),
+			expectError:      true,
+		},
+		{
+			name:             "Favorite doesn't exist",
+			id:               "0",
+			existingFavorite: nil,
+			expectError:      false,
 		},
 		{
-			name:          "Failed to delete the favorite",
-			id:            "0",
-			returnedError: fmt.Errorf("failed to delete the favorite"),
-			expectError:   true,
+			name:        "Failed to find the favorite",
+			id:          "0",
+			findError:   fmt.Errorf("failed to find the favorite"),
+			expectError: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
-			mockFavoriteRepo.On("DeleteFavorite", tt.id).Return(tt.returnedError)
+			mockFavoriteRepo.On("FindFavoriteById", tt.id).Return(tt.existingFavorite, tt.findError)
+			if tt.existingFavorite != nil {
+				mockFavoriteRepo.On("DeleteFavorite", tt.id, mock.MatchedBy(func(event model.Event) bool {
+					return event.Type == model.FavoriteRemoved && event.FavoriteId == tt.id &&
+						event.UserId == tt.existingFavorite.UserId && event.DrinkId == tt.existingFavorite.DrinkId
+				})).Return(tt.returnedError)
+			}
 			favoriteService := NewDefaultFavoriteService(mockFavoriteRepo)
 			err := favoriteService.DeleteFavorite(tt.id)
 			if tt.expectError {
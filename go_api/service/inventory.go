@@ -0,0 +1,109 @@
+//go:generate mockery --name=InventoryService --output=./ --outpkg=service --filename=inventory_mock.go --inpackage
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+type InventoryService interface {
+	// FindInventory retrieves the user's items ordered by ingredient
+	FindInventory(userId string) ([]model.InventoryItem, error)
+
+	// FindInventoryItem retrieves the user's item for the ingredient, whose name is normalized,
+	// or nil if the user doesn't have it
+	FindInventoryItem(userId, ingredient string) (*model.InventoryItem, error)
+
+	// AddInventoryItem adds the item's ingredient to the user's inventory
+	// or returns the InventoryItemAlreadyExistsError if the user already has it
+	AddInventoryItem(userId string, item model.InventoryItem) (*model.InventoryItem, error)
+
+	// UpdateInventoryItem saves the changes to the item as long as nobody else has modified it
+	// since expectedVersion was read; otherwise, returns the ConflictError
+	UpdateInventoryItem(item model.InventoryItem, expectedVersion int) (*model.InventoryItem, error)
+
+	DeleteInventoryItem(userId, ingredient string) error
+
+	// DeleteUserData removes the user's whole inventory, e.g. when their account is deleted
+	DeleteUserData(userId string) error
+}
+
+func NewDefaultInventoryService(repo repository.InventoryRepository) DefaultInventoryService {
+	return DefaultInventoryService{
+		repo:  repo,
+		clock: SystemClock{},
+	}
+}
+
+type DefaultInventoryService struct {
+	repo  repository.InventoryRepository
+	clock Clock
+}
+
+// WithClock returns a copy of the service that uses the given clock for timestamps
+func (s DefaultInventoryService) WithClock(clock Clock) DefaultInventoryService {
+	s.clock = clock
+	return s
+}
+
+func (s DefaultInventoryService) FindInventory(userId string) ([]model.InventoryItem, error) {
+	return s.repo.FindInventoryByUser(userId)
+}
+
+func (s DefaultInventoryService) FindInventoryItem(userId, ingredient string) (*model.InventoryItem, error) {
+	return s.repo.FindInventoryItem(userId, model.IngredientKey(ingredient))
+}
+
+func (s DefaultInventoryService) AddInventoryItem(userId string, item model.InventoryItem) (*model.InventoryItem, error) {
+	if userId == "" {
+		return nil, fmt.Errorf("the userId must not be empty")
+	}
+	item.UserId = userId
+	if err := normalizeInventoryItem(&item); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	item.Version = 1
+	if err := s.repo.CreateInventoryItem(item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s DefaultInventoryService) UpdateInventoryItem(item model.InventoryItem, expectedVersion int) (*model.InventoryItem, error) {
+	if err := normalizeInventoryItem(&item); err != nil {
+		return nil, err
+	}
+	item.UpdatedAt = s.clock.Now()
+	item.Version = expectedVersion + 1
+	if err := s.repo.UpdateInventoryItem(item, expectedVersion); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (s DefaultInventoryService) DeleteInventoryItem(userId, ingredient string) error {
+	return s.repo.DeleteInventoryItem(userId, model.IngredientKey(ingredient))
+}
+
+func (s DefaultInventoryService) DeleteUserData(userId string) error {
+	return s.repo.DeleteInventoryByUser(userId)
+}
+
+// normalizeInventoryItem derives the item's ingredient from its name and checks its quantity
+func normalizeInventoryItem(item *model.InventoryItem) error {
+	item.Ingredient = model.IngredientKey(item.Name)
+	if item.Ingredient == "" {
+		return fmt.Errorf("the ingredient's name must not be empty")
+	}
+	if item.Quantity < 0 || math.IsNaN(item.Quantity) || math.IsInf(item.Quantity, 0) {
+		return fmt.Errorf("the quantity of '%s' must be a number that isn't negative", item.Name)
+	}
+	return nil
+}
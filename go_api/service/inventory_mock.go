@@ -0,0 +1,160 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockInventoryService is an autogenerated mock type for the InventoryService type
+type MockInventoryService struct {
+	mock.Mock
+}
+
+// AddInventoryItem provides a mock function with given fields: userId, item
+func (_m *MockInventoryService) AddInventoryItem(userId string, item model.InventoryItem) (*model.InventoryItem, error) {
+	ret := _m.Called(userId, item)
+
+	var r0 *model.InventoryItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, model.InventoryItem) (*model.InventoryItem, error)); ok {
+		return rf(userId, item)
+	}
+	if rf, ok := ret.Get(0).(func(string, model.InventoryItem) *model.InventoryItem); ok {
+		r0 = rf(userId, item)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.InventoryItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, model.InventoryItem) error); ok {
+		r1 = rf(userId, item)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteInventoryItem provides a mock function with given fields: userId, ingredient
+func (_m *MockInventoryService) DeleteInventoryItem(userId string, ingredient string) error {
+	ret := _m.Called(userId, ingredient)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, ingredient)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserData provides a mock function with given fields: userId
+func (_m *MockInventoryService) DeleteUserData(userId string) error {
+	ret := _m.Called(userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindInventory provides a mock function with given fields: userId
+func (_m *MockInventoryService) FindInventory(userId string) ([]model.InventoryItem, error) {
+	ret := _m.Called(userId)
+
+	var r0 []model.InventoryItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]model.InventoryItem, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.InventoryItem); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.InventoryItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindInventoryItem provides a mock function with given fields: userId, ingredient
+func (_m *MockInventoryService) FindInventoryItem(userId string, ingredient string) (*model.InventoryItem, error) {
+	ret := _m.Called(userId, ingredient)
+
+	var r0 *model.InventoryItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.InventoryItem, error)); ok {
+		return rf(userId, ingredient)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.InventoryItem); ok {
+		r0 = rf(userId, ingredient)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.InventoryItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, ingredient)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateInventoryItem provides a mock function with given fields: item, expectedVersion
+func (_m *MockInventoryService) UpdateInventoryItem(item model.InventoryItem, expectedVersion int) (*model.InventoryItem, error) {
+	ret := _m.Called(item, expectedVersion)
+
+	var r0 *model.InventoryItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.InventoryItem, int) (*model.InventoryItem, error)); ok {
+		return rf(item, expectedVersion)
+	}
+	if rf, ok := ret.Get(0).(func(model.InventoryItem, int) *model.InventoryItem); ok {
+		r0 = rf(item, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.InventoryItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(model.InventoryItem, int) error); ok {
+		r1 = rf(item, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockInventoryService creates a new instance of MockInventoryService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInventoryService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInventoryService {
+	mock := &MockInventoryService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+func TestDefaultInventoryService_AddInventoryItem(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name               string
+		userId             string
+		item               model.InventoryItem
+		expectedIngredient string
+		storeReturnedError error
+		expectStore        bool
+		expectError        bool
+	}{
+		{
+			name:               "Successfully add an item",
+			userId:             "0",
+			item:               model.InventoryItem{Name: "  White   Rum ", Quantity: 700, Unit: "ml"},
+			expectedIngredient: "white rum",
+			expectStore:        true,
+		},
+		{
+			name:               "Ingredient already in the inventory",
+			userId:             "0",
+			item:               model.InventoryItem{Name: "Lime", Quantity: 2},
+			expectedIngredient: "lime",
+			storeReturnedError: apperrors.NewInventoryItemAlreadyExistsError("lime"),
+			expectStore:        true,
+			expectError:        true,
+		},
+		{
+			name:        "Empty user id",
+			item:        model.InventoryItem{Name: "Lime", Quantity: 2},
+			expectError: true,
+		},
+		{
+			name:        "Empty name",
+			userId:      "0",
+			item:        model.InventoryItem{Name: "   ", Quantity: 2},
+			expectError: true,
+		},
+		{
+			name:        "Negative quantity",
+			userId:      "0",
+			item:        model.InventoryItem{Name: "Lime", Quantity: -1},
+			expectError: true,
+		},
+		{
+			name:        "Quantity isn't a number",
+			userId:      "0",
+			item:        model.InventoryItem{Name: "Lime", Quantity: math.NaN()},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInventoryRepo := repository.NewMockInventoryRepository(t)
+			if tt.expectStore {
+				mockInventoryRepo.On("CreateInventoryItem", mock.MatchedBy(func(item model.InventoryItem) bool {
+					return item.UserId == tt.userId && item.Ingredient == tt.expectedIngredient &&
+						item.CreatedAt.Equal(now) && item.UpdatedAt.Equal(now) && item.Version == 1
+				})).Return(tt.storeReturnedError)
+			}
+
+			inventoryService := NewDefaultInventoryService(mockInventoryRepo).WithClock(fixedClock(now))
+			item, err := inventoryService.AddInventoryItem(tt.userId, tt.item)
+
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from inventoryService.AddInventoryItem")
+				assert.Nil(t, item, "No item should have been returned from inventoryService.AddInventoryItem")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from inventoryService.AddInventoryItem")
+				assert.Equal(t, tt.expectedIngredient, item.Ingredient, "The ingredient should have been normalized from the name")
+				assert.Equal(t, tt.item.Name, item.Name, "The name should have been kept as given")
+			}
+			mockInventoryRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultInventoryService_UpdateInventoryItem(t *testing.T) {
+	now := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := model.InventoryItem{UserId: "0", Name: "Lime", Quantity: 1, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 2}
+	tests := []struct {
+		name               string
+		storeReturnedError error
+		expectError        bool
+	}{
+		{
+			name: "Successfully update an item",
+		},
+		{
+			name:               "Item was modified by someone else",
+			storeReturnedError: apperrors.NewConflictError("the inventory item was modified", nil),
+			expectError:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInventoryRepo := repository.NewMockInventoryRepository(t)
+			mockInventoryRepo.On("UpdateInventoryItem", mock.MatchedBy(func(updated model.InventoryItem) bool {
+				return updated.Ingredient == "lime" && updated.CreatedAt.Equal(createdAt) && updated.UpdatedAt.Equal(now) && updated.Version == 3
+			}), 2).Return(tt.storeReturnedError)
+
+			inventoryService := NewDefaultInventoryService(mockInventoryRepo).WithClock(fixedClock(now))
+			updatedItem, err := inventoryService.UpdateInventoryItem(item, 2)
+
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from inventoryService.UpdateInventoryItem")
+				assert.Nil(t, updatedItem, "No item should have been returned from inventoryService.UpdateInventoryItem")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from inventoryService.UpdateInventoryItem")
+				assert.Equal(t, 3, updatedItem.Version, "The version should have been incremented")
+			}
+			mockInventoryRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultInventoryService_NormalizesIngredients(t *testing.T) {
+	mockInventoryRepo := repository.NewMockInventoryRepository(t)
+	mockInventoryRepo.On("FindInventoryItem", "0", "sweet vermouth").Return(nil, nil)
+	mockInventoryRepo.On("DeleteInventoryItem", "0", "sweet vermouth").Return(nil)
+	inventoryService := NewDefaultInventoryService(mockInventoryRepo)
+
+	item, err := inventoryService.FindInventoryItem("0", "Sweet  Vermouth")
+	assert.Nil(t, err, "No error should have been returned from inventoryService.FindInventoryItem")
+	assert.Nil(t, item, "No item should have been found")
+	assert.Nil(t, inventoryService.DeleteInventoryItem("0", " SWEET vermouth"), "No error should have been returned from inventoryService.DeleteInventoryItem")
+	mockInventoryRepo.AssertExpectations(t)
+}
+
+func TestDefaultInventoryService_DeleteUserData(t *testing.T) {
+	mockInventoryRepo := repository.NewMockInventoryRepository(t)
+	mockInventoryRepo.On("DeleteInventoryByUser", "0").Return(fmt.Errorf("failed to delete inventory"))
+	inventoryService := NewDefaultInventoryService(mockInventoryRepo)
+
+	err := inventoryService.DeleteUserData("0")
+	assert.NotNil(t, err, "The repository's error should have been returned from inventoryService.DeleteUserData")
+	mockInventoryRepo.AssertExpectations(t)
+}
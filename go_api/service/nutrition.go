@@ -0,0 +1,56 @@
+//go:generate mockery --name=NutritionService --output=./ --outpkg=service --filename=nutrition_mock.go --inpackage
+package service
+
+import "the-drink-almanac-api/nutrition"
+
+type NutritionService interface {
+	// EstimateDrink estimates a serving of the drink, or returns nil if there isn't a recipe for the drink;
+	// users' recipes can be estimated by anyone who has their id, unless they're private to another user.
+	// userId is empty for anonymous requests
+	EstimateDrink(userId, drinkId string) (*nutrition.Estimate, error)
+
+	// EstimateFavorite is EstimateDrink for one of the user's favorites, or returns nil if the user
+	// doesn't have the favorite or it isn't a favorite of the drink
+	EstimateFavorite(userId, drinkId, favoriteId string) (*nutrition.Estimate, error)
+}
+
+// NewDefaultNutritionService creates the service; drinks provides the recipes of the drinks and users' recipes
+func NewDefaultNutritionService(drinks DrinkResolver, favorites FavoriteService) DefaultNutritionService {
+	return DefaultNutritionService{
+		drinks:    drinks,
+		favorites: favorites,
+	}
+}
+
+type DefaultNutritionService struct {
+	drinks    DrinkResolver
+	favorites FavoriteService
+}
+
+func (s DefaultNutritionService) EstimateDrink(userId, drinkId string) (*nutrition.Estimate, error) {
+	return s.estimate(userId, drinkId)
+}
+
+// estimate estimates the drink, or the recipe as the user sees it
+func (s DefaultNutritionService) estimate(userId, drinkId string) (*nutrition.Estimate, error) {
+	drink, err := s.drinks.FindDrink(userId, drinkId)
+	if err != nil || drink == nil {
+		return nil, err
+	}
+	estimate := nutrition.NewEstimate(*drink)
+	return &estimate, nil
+}
+
+// EstimateFavorite looks the favorite up among the user's favorites, so that a user can't read another user's favorite
+func (s DefaultNutritionService) EstimateFavorite(userId, drinkId, favoriteId string) (*nutrition.Estimate, error) {
+	favorites, err := s.favorites.FindFavoritesByUser(userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, favorite := range favorites {
+		if favorite.Id == favoriteId && favorite.DrinkId == drinkId {
+			return s.estimate(userId, drinkId)
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	nutrition "the-drink-almanac-api/nutrition"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockNutritionService is an autogenerated mock type for the NutritionService type
+type MockNutritionService struct {
+	mock.Mock
+}
+
+// EstimateDrink provides a mock function with given fields: userId, drinkId
+func (_m *MockNutritionService) EstimateDrink(userId string, drinkId string) (*nutrition.Estimate, error) {
+	ret := _m.Called(userId, drinkId)
+
+	var r0 *nutrition.Estimate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*nutrition.Estimate, error)); ok {
+		return rf(userId, drinkId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *nutrition.Estimate); ok {
+		r0 = rf(userId, drinkId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*nutrition.Estimate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, drinkId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EstimateFavorite provides a mock function with given fields: userId, drinkId, favoriteId
+func (_m *MockNutritionService) EstimateFavorite(userId string, drinkId string, favoriteId string) (*nutrition.Estimate, error) {
+	ret := _m.Called(userId, drinkId, favoriteId)
+
+	var r0 *nutrition.Estimate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*nutrition.Estimate, error)); ok {
+		return rf(userId, drinkId, favoriteId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *nutrition.Estimate); ok {
+		r0 = rf(userId, drinkId, favoriteId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*nutrition.Estimate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(userId, drinkId, favoriteId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockNutritionService creates a new instance of MockNutritionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockNutritionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockNutritionService {
+	mock := &MockNutritionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+)
+
+func TestDefaultNutritionService_EstimateDrink(t *testing.T) {
+	margarita := &model.Drink{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}}}
+	tests := []struct {
+		name           string
+		userId         string
+		returnedDrink  *model.Drink
+		returnedError  error
+		expectEstimate bool
+		expectError    bool
+	}{
+		{name: "Successfully estimate a drink", returnedDrink: margarita, expectEstimate: true},
+		{name: "Successfully estimate a drink as a user", userId: "0", returnedDrink: margarita, expectEstimate: true},
+		{name: "Drink doesn't have a recipe"},
+		{name: "Failed to find the drink", returnedError: fmt.Errorf("failed to find drink"), expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDrinkResolver := NewMockDrinkResolver(t)
+			mockDrinkResolver.On("FindDrink", tt.userId, "11007").Return(tt.returnedDrink, tt.returnedError)
+			s := NewDefaultNutritionService(mockDrinkResolver, NewMockFavoriteService(t))
+
+			estimate, err := s.EstimateDrink(tt.userId, "11007")
+
+			assert.Equal(t, tt.expectError, err != nil, "Unexpected error: %v", err)
+			assert.Equal(t, tt.expectEstimate, estimate != nil)
+			if estimate != nil {
+				assert.Equal(t, "Margarita", estimate.DrinkName)
+				assert.InDelta(t, 0.4*59.147, estimate.AlcoholGrams/0.789, 0.01)
+			}
+		})
+	}
+}
+
+func TestDefaultNutritionService_EstimateFavorite(t *testing.T) {
+	margarita := &model.Drink{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}}}
+	recipe := &model.Drink{Id: "recipe-0", Name: "House Sour", Ingredients: []model.Ingredient{{Name: "Bourbon", Measure: "2 oz"}}}
+	favorites := []model.Favorite{
+		{Id: "fav0", UserId: "0", DrinkId: "11000"},
+		{Id: "fav1", UserId: "0", DrinkId: "11007"},
+		{Id: "fav3", UserId: "0", DrinkId: "recipe-0"},
+	}
+	tests := []struct {
+		name           string
+		drinkId        string
+		favoriteId     string
+		favoritesError error
+		expectLookup   string
+		returnedDrink  *model.Drink
+		expectEstimate bool
+		expectError    bool
+	}{
+		{name: "Successfully estimate a favorite", drinkId: "11007", favoriteId: "fav1", expectLookup: "11007", returnedDrink: margarita, expectEstimate: true},
+		{name: "Successfully estimate a recipe favorite", drinkId: "recipe-0", favoriteId: "fav3", expectLookup: "recipe-0", returnedDrink: recipe, expectEstimate: true},
+		{name: "The user doesn't have the favorite", drinkId: "11007", favoriteId: "fav2"},
+		{name: "The favorite is of another drink", drinkId: "11007", favoriteId: "fav0"},
+		{name: "Failed to find the favorites", drinkId: "11007", favoriteId: "fav1", favoritesError: fmt.Errorf("failed to find favorites"), expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFavoriteService := NewMockFavoriteService(t)
+			mockFavoriteService.On("FindFavoritesByUser", "0").Return(favorites, tt.favoritesError)
+			mockDrinkResolver := NewMockDrinkResolver(t)
+			if tt.expectLookup != "" {
+				mockDrinkResolver.On("FindDrink", "0", tt.expectLookup).Return(tt.returnedDrink, nil)
+			}
+			s := NewDefaultNutritionService(mockDrinkResolver, mockFavoriteService)
+
+			estimate, err := s.EstimateFavorite("0", tt.drinkId, tt.favoriteId)
+
+			assert.Equal(t, tt.expectError, err != nil, "Unexpected error: %v", err)
+			assert.Equal(t, tt.expectEstimate, estimate != nil)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+//go:generate mockery --name=ProfileService --output=./ --outpkg=service --filename=profile_mock.go --inpackage
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/blob"
+	"the-drink-almanac-api/model"
+
+	"github.com/google/uuid"
+)
+
+// MaxAvatarSize is the largest avatar image that can be uploaded, in bytes
+const MaxAvatarSize = 1 << 20
+
+// avatarExtensions are the image types that avatars can be, with the extension that their keys end with
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type ProfileService interface {
+	// FindProfile retrieves the user with the username for their public profile, along with their favorites,
+	// the newest first, if the user made them public; the user is nil if no user has the username.
+	// Favorites of recipes are only listed if the recipe is public, since the id of an unlisted recipe is what
+	// keeps it unlisted
+	FindProfile(username string) (*model.User, []model.Favorite, error)
+
+	// FindAvatar retrieves the avatar image of the user with the username and its content type;
+	// the image is nil if the user doesn't exist or has no avatar
+	FindAvatar(username string) ([]byte, string, error)
+
+	// UpdateAvatar saves the image as the user's avatar in place of their previous one, as long as nobody else
+	// has modified the user since expectedVersion was read; otherwise, returns the ConflictError;
+	// an image that isn't a PNG, JPEG, GIF or WebP of at most MaxAvatarSize bytes returns the InvalidAvatarError
+	UpdateAvatar(user model.User, expectedVersion int, image []byte) (*model.User, error)
+
+	// DeleteAvatar removes the user's avatar, as long as nobody else has modified the user since expectedVersion was read
+	DeleteAvatar(user model.User, expectedVersion int) (*model.User, error)
+
+	// DeleteUserData removes the user's avatar image, e.g. when their account is deleted
+	DeleteUserData(userId string) error
+}
+
+func NewDefaultProfileService(users UserService, favorites FavoriteService, avatars blob.Store) DefaultProfileService {
+	return DefaultProfileService{
+		users:     users,
+		favorites: favorites,
+		avatars:   avatars,
+	}
+}
+
+type DefaultProfileService struct {
+	users     UserService
+	favorites FavoriteService
+	avatars   blob.Store
+	recipes   RecipeService
+}
+
+// WithRecipes returns a copy of the service that lists favorites of public recipes in profiles;
+// without recipes, favorites of recipes are never listed, since their visibility can't be checked
+func (s DefaultProfileService) WithRecipes(recipes RecipeService) DefaultProfileService {
+	s.recipes = recipes
+	return s
+}
+
+func (s DefaultProfileService) FindProfile(username string) (*model.User, []model.Favorite, error) {
+	user, err := s.users.FindUserByUsername(username)
+	if err != nil || user == nil {
+		return nil, nil, err
+	}
+	if !user.PublicFavorites {
+		return user, nil, nil
+	}
+	favorites, err := s.favorites.FindFavoritesByUserSortedByCreation(user.Id, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	favorites, err = s.publicFavorites(favorites)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, favorites, nil
+}
+
+// publicFavorites leaves out the favorites of recipes that aren't public, looking up each recipe only once
+func (s DefaultProfileService) publicFavorites(favorites []model.Favorite) ([]model.Favorite, error) {
+	public := []model.Favorite{}
+	isPublic := map[string]bool{}
+	for _, favorite := range favorites {
+		if !model.IsRecipeId(favorite.DrinkId) {
+			public = append(public, favorite)
+			continue
+		}
+		if s.recipes == nil {
+			continue
+		}
+		recipePublic, checked := isPublic[favorite.DrinkId]
+		if !checked {
+			recipe, err := s.recipes.FindRecipe("", favorite.DrinkId)
+			if err != nil {
+				return nil, err
+			}
+			recipePublic = recipe != nil && recipe.Visibility == model.RecipePublic
+			isPublic[favorite.DrinkId] = recipePublic
+		}
+		if recipePublic {
+			public = append(public, favorite)
+		}
+	}
+	return public, nil
+}
+
+func (s DefaultProfileService) FindAvatar(username string) ([]byte, string, error) {
+	user, err := s.users.FindUserByUsername(username)
+	if err != nil || user == nil || user.Avatar == "" {
+		return nil, "", err
+	}
+	image, found, err := s.avatars.Get(user.Avatar)
+	if err != nil || !found {
+		return nil, "", err
+	}
+	return image, http.DetectContentType(image), nil
+}
+
+// UpdateAvatar saves the image under a new key before pointing the user at it, so the previous avatar
+// is served until the update succeeds; the previous image is only deleted afterwards
+func (s DefaultProfileService) UpdateAvatar(user model.User, expectedVersion int, image []byte) (*model.User, error) {
+	if len(image) == 0 {
+		return nil, apperrors.NewInvalidAvatarError("the image is empty")
+	}
+	if len(image) > MaxAvatarSize {
+		return nil, apperrors.NewInvalidAvatarError(fmt.Sprintf("the image must be at most %d bytes", MaxAvatarSize))
+	}
+	contentType := http.DetectContentType(image)
+	extension, ok := avatarExtensions[contentType]
+	if !ok {
+		return nil, apperrors.NewInvalidAvatarError(fmt.Sprintf("the image must be a PNG, JPEG, GIF or WebP, not %s", contentType))
+	}
+
+	key := path.Join("avatars", user.Id, uuid.NewString()+extension)
+	if err := s.avatars.Put(key, image); err != nil {
+		return nil, err
+	}
+	previousAvatar := user.Avatar
+	user.Avatar = key
+	updatedUser, err := s.users.UpdateUser(user, expectedVersion)
+	if err != nil {
+		// the new image isn't referenced by anyone, so it's removed again; the previous avatar stays in place
+		_ = s.avatars.Delete(key)
+		return nil, err
+	}
+	s.deletePreviousAvatar(previousAvatar)
+	return updatedUser, nil
+}
+
+func (s DefaultProfileService) DeleteAvatar(user model.User, expectedVersion int) (*model.User, error) {
+	previousAvatar := user.Avatar
+	user.Avatar = ""
+	updatedUser, err := s.users.UpdateUser(user, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	s.deletePreviousAvatar(previousAvatar)
+	return updatedUser, nil
+}
+
+// DeleteUserData removes the avatar before the user's record is deleted; the record is left alone,
+// since it's about to be deleted anyway
+func (s DefaultProfileService) DeleteUserData(userId string) error {
+	user, err := s.users.FindUser(userId)
+	if err != nil || user == nil || user.Avatar == "" {
+		return err
+	}
+	return s.avatars.Delete(user.Avatar)
+}
+
+// deletePreviousAvatar removes an image that the user no longer points at; failing to do so only leaves
+// an unreachable image behind, so it doesn't fail the update that has already been saved
+func (s DefaultProfileService) deletePreviousAvatar(key string) {
+	if key != "" {
+		_ = s.avatars.Delete(key)
+	}
+}
@@ -0,0 +1,162 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockProfileService is an autogenerated mock type for the ProfileService type
+type MockProfileService struct {
+	mock.Mock
+}
+
+// DeleteAvatar provides a mock function with given fields: user, expectedVersion
+func (_m *MockProfileService) DeleteAvatar(user model.User, expectedVersion int) (*model.User, error) {
+	ret := _m.Called(user, expectedVersion)
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.User, int) (*model.User, error)); ok {
+		return rf(user, expectedVersion)
+	}
+	if rf, ok := ret.Get(0).(func(model.User, int) *model.User); ok {
+		r0 = rf(user, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(model.User, int) error); ok {
+		r1 = rf(user, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUserData provides a mock function with given fields: userId
+func (_m *MockProfileService) DeleteUserData(userId string) error {
+	ret := _m.Called(userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAvatar provides a mock function with given fields: username
+func (_m *MockProfileService) FindAvatar(username string) ([]byte, string, error) {
+	ret := _m.Called(username)
+
+	var r0 []byte
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) ([]byte, string, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) string); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(username)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindProfile provides a mock function with given fields: username
+func (_m *MockProfileService) FindProfile(username string) (*model.User, []model.Favorite, error) {
+	ret := _m.Called(username)
+
+	var r0 *model.User
+	var r1 []model.Favorite
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (*model.User, []model.Favorite, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) []model.Favorite); ok {
+		r1 = rf(username)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]model.Favorite)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(username)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// UpdateAvatar provides a mock function with given fields: user, expectedVersion, image
+func (_m *MockProfileService) UpdateAvatar(user model.User, expectedVersion int, image []byte) (*model.User, error) {
+	ret := _m.Called(user, expectedVersion, image)
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.User, int, []byte) (*model.User, error)); ok {
+		return rf(user, expectedVersion, image)
+	}
+	if rf, ok := ret.Get(0).(func(model.User, int, []byte) *model.User); ok {
+		r0 = rf(user, expectedVersion, image)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(model.User, int, []byte) error); ok {
+		r1 = rf(user, expectedVersion, image)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockProfileService creates a new instance of MockProfileService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockProfileService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockProfileService {
+	mock := &MockProfileService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/blob"
+	"the-drink-almanac-api/model"
+)
+
+// testPNG is the signature that the PNG content type is detected by
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDefaultProfileService_FindProfile(t *testing.T) {
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	favorites := []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007", CreatedAt: createdAt}}
+	tests := []struct {
+		name              string
+		returnedUser      *model.User
+		expectFavorites   bool
+		expectedFavorites []model.Favorite
+	}{
+		{
+			name:              "User with public favorites",
+			returnedUser:      &model.User{Id: "0", Username: "username0", PublicFavorites: true},
+			expectFavorites:   true,
+			expectedFavorites: favorites,
+		},
+		{
+			name:         "User with private favorites",
+			returnedUser: &model.User{Id: "0", Username: "username0"},
+		},
+		{
+			name: "User doesn't exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := NewMockUserService(t)
+			mockFavoriteService := NewMockFavoriteService(t)
+			mockUserService.On("FindUserByUsername", "username0").Return(tt.returnedUser, nil)
+			if tt.expectFavorites {
+				mockFavoriteService.On("FindFavoritesByUserSortedByCreation", "0", true).Return(favorites, nil)
+			}
+			s := NewDefaultProfileService(mockUserService, mockFavoriteService, blob.NewMockStore(t))
+
+			user, foundFavorites, err := s.FindProfile("username0")
+
+			assert.NoError(t, err, "No error should have been returned from FindProfile")
+			assert.Equal(t, tt.returnedUser, user)
+			assert.Equal(t, tt.expectedFavorites, foundFavorites)
+		})
+	}
+}
+
+func TestDefaultProfileService_FindProfile_RecipeFavorites(t *testing.T) {
+	favorites := []model.Favorite{
+		{Id: "1", UserId: "0", DrinkId: "recipe-public"},
+		{Id: "2", UserId: "0", DrinkId: "recipe-unlisted"},
+		{Id: "3", UserId: "0", DrinkId: "11007"},
+		{Id: "4", UserId: "0", DrinkId: "recipe-private"},
+		{Id: "5", UserId: "0", DrinkId: "recipe-public"},
+	}
+	user := &model.User{Id: "0", Username: "username0", PublicFavorites: true}
+	newService := func(t *testing.T) DefaultProfileService {
+		mockUserService := NewMockUserService(t)
+		mockUserService.On("FindUserByUsername", "username0").Return(user, nil)
+		mockFavoriteService := NewMockFavoriteService(t)
+		mockFavoriteService.On("FindFavoritesByUserSortedByCreation", "0", true).Return(favorites, nil)
+		return NewDefaultProfileService(mockUserService, mockFavoriteService, blob.NewMockStore(t))
+	}
+
+	// private recipes aren't found for an anonymous viewer, and unlisted ones are found but not listed
+	mockRecipeService := NewMockRecipeService(t)
+	mockRecipeService.On("FindRecipe", "", "recipe-public").Return(&model.Recipe{Id: "recipe-public", Visibility: model.RecipePublic}, nil).Once()
+	mockRecipeService.On("FindRecipe", "", "recipe-unlisted").Return(&model.Recipe{Id: "recipe-unlisted", Visibility: model.RecipeUnlisted}, nil).Once()
+	mockRecipeService.On("FindRecipe", "", "recipe-private").Return(nil, nil).Once()
+	_, foundFavorites, err := newService(t).WithRecipes(mockRecipeService).FindProfile("username0")
+	assert.NoError(t, err, "No error should have been returned from FindProfile")
+	assert.Equal(t, []model.Favorite{favorites[0], favorites[2], favorites[4]}, foundFavorites,
+		"Only the favorites of drinks and public recipes should have been listed")
+
+	_, foundFavorites, err = newService(t).FindProfile("username0")
+	assert.NoError(t, err, "No error should have been returned from FindProfile")
+	assert.Equal(t, []model.Favorite{favorites[2]}, foundFavorites, "Recipes shouldn't be listed without the recipes to check them")
+
+	failingRecipeService := NewMockRecipeService(t)
+	failingRecipeService.On("FindRecipe", "", "recipe-public").Return(nil, errors.New("failed"))
+	_, _, err = newService(t).WithRecipes(failingRecipeService).FindProfile("username0")
+	assert.Error(t, err, "An error should have been returned from FindProfile")
+}
+
+func TestDefaultProfileService_FindAvatar(t *testing.T) {
+	tests := []struct {
+		name                string
+		returnedUser        *model.User
+		expectGet           bool
+		storedImage         []byte
+		expectedContentType string
+	}{
+		{
+			name:                "User with an avatar",
+			returnedUser:        &model.User{Id: "0", Avatar: "avatars/0/avatar.png"},
+			expectGet:           true,
+			storedImage:         testPNG,
+			expectedContentType: "image/png",
+		},
+		{
+			name:         "Avatar is missing from the store",
+			returnedUser: &model.User{Id: "0", Avatar: "avatars/0/avatar.png"},
+			expectGet:    true,
+		},
+		{
+			name:         "User without an avatar",
+			returnedUser: &model.User{Id: "0"},
+		},
+		{
+			name: "User doesn't exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := NewMockUserService(t)
+			mockStore := blob.NewMockStore(t)
+			mockUserService.On("FindUserByUsername", "username0").Return(tt.returnedUser, nil)
+			if tt.expectGet {
+				mockStore.On("Get", "avatars/0/avatar.png").Return(tt.storedImage, tt.storedImage != nil, nil)
+			}
+			s := NewDefaultProfileService(mockUserService, NewMockFavoriteService(t), mockStore)
+
+			image, contentType, err := s.FindAvatar("username0")
+
+			assert.NoError(t, err, "No error should have been returned from FindAvatar")
+			assert.Equal(t, tt.storedImage, image)
+			assert.Equal(t, tt.expectedContentType, contentType)
+		})
+	}
+}
+
+func TestDefaultProfileService_UpdateAvatar(t *testing.T) {
+	isNewKey := mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "avatars/0/") && strings.HasSuffix(key, ".png") && key != "avatars/0/old.png"
+	})
+	tests := []struct {
+		name               string
+		image              []byte
+		expectPut          bool
+		updateError        error
+		expectOldDeleted   bool
+		expectNewDeleted   bool
+		expectConflict     bool
+		expectInvalidImage bool
+	}{
+		{
+			name:             "Successfully replace the avatar",
+			image:            testPNG,
+			expectPut:        true,
+			expectOldDeleted: true,
+		},
+		{
+			name:             "User was modified by another request",
+			image:            testPNG,
+			expectPut:        true,
+			updateError:      apperrors.NewConflictError("the user was modified", nil),
+			expectNewDeleted: true,
+			expectConflict:   true,
+		},
+		{
+			name:               "Not an image",
+			image:              []byte("just some text"),
+			expectInvalidImage: true,
+		},
+		{
+			name:               "Empty image",
+			expectInvalidImage: true,
+		},
+		{
+			name:               "Image is too large",
+			image:              append(append([]byte{}, testPNG...), make([]byte, MaxAvatarSize)...),
+			expectInvalidImage: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := model.User{Id: "0", Username: "username0", Avatar: "avatars/0/old.png", Version: 2}
+			mockUserService := NewMockUserService(t)
+			mockStore := blob.NewMockStore(t)
+			if tt.expectPut {
+				mockStore.On("Put", isNewKey, tt.image).Return(nil)
+				var updatedUser *model.User
+				if tt.updateError == nil {
+					updatedUser = &model.User{Id: "0", Avatar: "avatars/0/new.png", Version: 3}
+				}
+				mockUserService.On("UpdateUser", mock.MatchedBy(func(u model.User) bool {
+					return u.Id == "0" && strings.HasPrefix(u.Avatar, "avatars/0/") && u.Avatar != "avatars/0/old.png"
+				}), 2).Return(updatedUser, tt.updateError)
+			}
+			if tt.expectOldDeleted {
+				mockStore.On("Delete", "avatars/0/old.png").Return(nil)
+			}
+			if tt.expectNewDeleted {
+				mockStore.On("Delete", isNewKey).Return(nil)
+			}
+			s := NewDefaultProfileService(mockUserService, NewMockFavoriteService(t), mockStore)
+
+			updatedUser, err := s.UpdateAvatar(user, 2, tt.image)
+
+			switch {
+			case tt.expectInvalidImage:
+				assert.True(t, errors.As(err, &apperrors.InvalidAvatarError{}), "The InvalidAvatarError should have been returned")
+			case tt.expectConflict:
+				assert.True(t, errors.As(err, &apperrors.ConflictError{}), "The ConflictError should have been returned")
+			default:
+				assert.NoError(t, err, "No error should have been returned from UpdateAvatar")
+				assert.Equal(t, 3, updatedUser.Version)
+			}
+		})
+	}
+}
+
+func TestDefaultProfileService_DeleteUserData(t *testing.T) {
+	mockUserService := NewMockUserService(t)
+	mockStore := blob.NewMockStore(t)
+	mockUserService.On("FindUser", "0").Return(&model.User{Id: "0", Avatar: "avatars/0/avatar.png"}, nil)
+	mockStore.On("Delete", "avatars/0/avatar.png").Return(nil)
+	s := NewDefaultProfileService(mockUserService, NewMockFavoriteService(t), mockStore)
+
+	assert.NoError(t, s.DeleteUserData("0"), "No error should have been returned from DeleteUserData")
+}
@@ -0,0 +1,195 @@
+//go:generate mockery --name=RecipeService --output=./ --outpkg=service --filename=recipe_mock.go --inpackage
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+
+	"github.com/google/uuid"
+)
+
+type RecipeService interface {
+	// FindRecipe retrieves the recipe if the user can see it, or nil if it doesn't exist or it's another user's private recipe;
+	// an empty userId is an anonymous user, who can only see unlisted and public recipes
+	FindRecipe(userId, recipeId string) (*model.Recipe, error)
+
+	// FindRecipesByOwner retrieves the user's own recipes ordered by name
+	FindRecipesByOwner(ownerId string) ([]model.Recipe, error)
+
+	// FindPublicRecipes retrieves every public recipe, e.g. to search them alongside the drink catalog
+	FindPublicRecipes() ([]model.Recipe, error)
+
+	// CreateRecipe saves the recipe with a new id as the user's; a recipe without a visibility is private
+	CreateRecipe(ownerId string, recipe model.Recipe) (*model.Recipe, error)
+
+	// UpdateRecipe saves the changes to the user's recipe as long as nobody else has modified it
+	// since expectedVersion was read; otherwise, returns the ConflictError. Like DeleteRecipe,
+	// it returns nil if the user can't see the recipe and the RecipeNotOwnedError if it's another user's
+	UpdateRecipe(userId string, recipe model.Recipe, expectedVersion int) (*model.Recipe, error)
+
+	// DeleteRecipe removes the user's recipe; deleting a recipe that the user can't see does nothing,
+	// and deleting another user's recipe returns the RecipeNotOwnedError
+	DeleteRecipe(userId, recipeId string) error
+
+	// DeleteUserData removes all of the user's recipes, e.g. when their account is deleted
+	DeleteUserData(userId string) error
+}
+
+func NewDefaultRecipeService(repo repository.RecipeRepository) DefaultRecipeService {
+	return DefaultRecipeService{
+		repo:  repo,
+		clock: SystemClock{},
+	}
+}
+
+type DefaultRecipeService struct {
+	repo  repository.RecipeRepository
+	clock Clock
+}
+
+// WithClock returns a copy of the service that uses the given clock for timestamps
+func (s DefaultRecipeService) WithClock(clock Clock) DefaultRecipeService {
+	s.clock = clock
+	return s
+}
+
+func (s DefaultRecipeService) FindRecipe(userId, recipeId string) (*model.Recipe, error) {
+	if !model.IsRecipeId(recipeId) {
+		return nil, nil
+	}
+	recipe, err := s.repo.FindRecipeById(recipeId)
+	if err != nil || recipe == nil || !recipe.VisibleTo(userId) {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+func (s DefaultRecipeService) FindRecipesByOwner(ownerId string) ([]model.Recipe, error) {
+	recipes, err := s.repo.FindRecipesByOwner(ownerId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		iName, jName := strings.ToLower(recipes[i].Name), strings.ToLower(recipes[j].Name)
+		if iName != jName {
+			return iName < jName
+		}
+		return recipes[i].Id < recipes[j].Id
+	})
+	return recipes, nil
+}
+
+func (s DefaultRecipeService) FindPublicRecipes() ([]model.Recipe, error) {
+	return s.repo.FindPublicRecipes()
+}
+
+func (s DefaultRecipeService) CreateRecipe(ownerId string, recipe model.Recipe) (*model.Recipe, error) {
+	if ownerId == "" {
+		return nil, fmt.Errorf("the ownerId must not be empty")
+	}
+	if err := normalizeRecipe(&recipe); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	recipe.Id = model.RecipeIdPrefix + uuid.NewString()
+	recipe.OwnerId = ownerId
+	recipe.CreatedAt = now
+	recipe.UpdatedAt = now
+	recipe.Version = 1
+	if err := s.repo.CreateRecipe(recipe); err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// UpdateRecipe keeps the stored recipe's owner and creation time
+func (s DefaultRecipeService) UpdateRecipe(userId string, recipe model.Recipe, expectedVersion int) (*model.Recipe, error) {
+	if err := normalizeRecipe(&recipe); err != nil {
+		return nil, err
+	}
+	existingRecipe, err := s.FindRecipe(userId, recipe.Id)
+	if err != nil || existingRecipe == nil {
+		return nil, err
+	}
+	if existingRecipe.OwnerId != userId {
+		return nil, apperrors.NewRecipeNotOwnedError(recipe.Id)
+	}
+	recipe.OwnerId = existingRecipe.OwnerId
+	recipe.CreatedAt = existingRecipe.CreatedAt
+	recipe.UpdatedAt = s.clock.Now()
+	recipe.Version = expectedVersion + 1
+	if err := s.repo.UpdateRecipe(recipe, expectedVersion); err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+func (s DefaultRecipeService) DeleteRecipe(userId, recipeId string) error {
+	recipe, err := s.FindRecipe(userId, recipeId)
+	if err != nil || recipe == nil {
+		return err
+	}
+	if recipe.OwnerId != userId {
+		return apperrors.NewRecipeNotOwnedError(recipeId)
+	}
+	return s.repo.DeleteRecipe(recipeId)
+}
+
+func (s DefaultRecipeService) DeleteUserData(userId string) error {
+	return s.repo.DeleteRecipesByOwner(userId)
+}
+
+// normalizeRecipe trims the recipe's name and ingredients, defaults its visibility to private and checks that it's complete
+func normalizeRecipe(recipe *model.Recipe) error {
+	recipe.Name = strings.TrimSpace(recipe.Name)
+	if recipe.Name == "" {
+		return fmt.Errorf("the recipe's name must not be empty")
+	}
+	if recipe.Visibility == "" {
+		recipe.Visibility = model.RecipePrivate
+	}
+	if !model.ValidRecipeVisibility(recipe.Visibility) {
+		return fmt.Errorf("invalid visibility '%s'; it must be '%s', '%s' or '%s'",
+			recipe.Visibility, model.RecipePrivate, model.RecipeUnlisted, model.RecipePublic)
+	}
+	if len(recipe.Ingredients) == 0 {
+		return fmt.Errorf("the recipe '%s' must have at least one ingredient", recipe.Name)
+	}
+	ingredients := make([]model.Ingredient, len(recipe.Ingredients))
+	for i, ingredient := range recipe.Ingredients {
+		ingredients[i] = model.Ingredient{
+			Name:    strings.TrimSpace(ingredient.Name),
+			Measure: strings.TrimSpace(ingredient.Measure),
+		}
+		if ingredients[i].Name == "" {
+			return fmt.Errorf("ingredient %d of the recipe '%s' has no name", i+1, recipe.Name)
+		}
+	}
+	recipe.Ingredients = ingredients
+	return nil
+}
+
+// WithPublicRecipes wraps loadDrinks, such as the drink catalog's FindAll, so that it also returns the public recipes
+// as drinks, which lets them be searched alongside the catalog
+func WithPublicRecipes(loadDrinks func() ([]model.Drink, error), recipes RecipeService) func() ([]model.Drink, error) {
+	return func() ([]model.Drink, error) {
+		drinks, err := loadDrinks()
+		if err != nil {
+			return nil, err
+		}
+		publicRecipes, err := recipes.FindPublicRecipes()
+		if err != nil {
+			return nil, err
+		}
+		for _, recipe := range publicRecipes {
+			drinks = append(drinks, recipe.Drink())
+		}
+		return drinks, nil
+	}
+}
@@ -0,0 +1,186 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	model "the-drink-almanac-api/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRecipeService is an autogenerated mock type for the RecipeService type
+type MockRecipeService struct {
+	mock.Mock
+}
+
+// CreateRecipe provides a mock function with given fields: ownerId, recipe
+func (_m *MockRecipeService) CreateRecipe(ownerId string, recipe model.Recipe) (*model.Recipe, error) {
+	ret := _m.Called(ownerId, recipe)
+
+	var r0 *model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, model.Recipe) (*model.Recipe, error)); ok {
+		return rf(ownerId, recipe)
+	}
+	if rf, ok := ret.Get(0).(func(string, model.Recipe) *model.Recipe); ok {
+		r0 = rf(ownerId, recipe)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, model.Recipe) error); ok {
+		r1 = rf(ownerId, recipe)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteRecipe provides a mock function with given fields: userId, recipeId
+func (_m *MockRecipeService) DeleteRecipe(userId string, recipeId string) error {
+	ret := _m.Called(userId, recipeId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(userId, recipeId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserData provides a mock function with given fields: userId
+func (_m *MockRecipeService) DeleteUserData(userId string) error {
+	ret := _m.Called(userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindPublicRecipes provides a mock function with given fields:
+func (_m *MockRecipeService) FindPublicRecipes() ([]model.Recipe, error) {
+	ret := _m.Called()
+
+	var r0 []model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]model.Recipe, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.Recipe); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRecipe provides a mock function with given fields: userId, recipeId
+func (_m *MockRecipeService) FindRecipe(userId string, recipeId string) (*model.Recipe, error) {
+	ret := _m.Called(userId, recipeId)
+
+	var r0 *model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.Recipe, error)); ok {
+		return rf(userId, recipeId)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.Recipe); ok {
+		r0 = rf(userId, recipeId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(userId, recipeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRecipesByOwner provides a mock function with given fields: ownerId
+func (_m *MockRecipeService) FindRecipesByOwner(ownerId string) ([]model.Recipe, error) {
+	ret := _m.Called(ownerId)
+
+	var r0 []model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]model.Recipe, error)); ok {
+		return rf(ownerId)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.Recipe); ok {
+		r0 = rf(ownerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ownerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateRecipe provides a mock function with given fields: userId, recipe, expectedVersion
+func (_m *MockRecipeService) UpdateRecipe(userId string, recipe model.Recipe, expectedVersion int) (*model.Recipe, error) {
+	ret := _m.Called(userId, recipe, expectedVersion)
+
+	var r0 *model.Recipe
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, model.Recipe, int) (*model.Recipe, error)); ok {
+		return rf(userId, recipe, expectedVersion)
+	}
+	if rf, ok := ret.Get(0).(func(string, model.Recipe, int) *model.Recipe); ok {
+		r0 = rf(userId, recipe, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Recipe)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, model.Recipe, int) error); ok {
+		r1 = rf(userId, recipe, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockRecipeService creates a new instance of MockRecipeService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRecipeService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRecipeService {
+	mock := &MockRecipeService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"the-drink-almanac-api/apperrors"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/repository"
+)
+
+func TestDefaultRecipeService_CreateRecipe(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	ingredients := []model.Ingredient{{Name: " Tequila ", Measure: "2 oz "}, {Name: "Lime juice", Measure: "1 oz"}}
+	tests := []struct {
+		name               string
+		ownerId            string
+		recipe             model.Recipe
+		expectedVisibility string
+		storeReturnedError error
+		expectStore        bool
+		expectError        bool
+	}{
+		{
+			name:               "Successfully create a private recipe by default",
+			ownerId:            "0",
+			recipe:             model.Recipe{Name: " House Margarita ", Ingredients: ingredients},
+			expectedVisibility: model.RecipePrivate,
+			expectStore:        true,
+		},
+		{
+			name:               "Successfully create a public recipe",
+			ownerId:            "0",
+			recipe:             model.Recipe{Name: "House Margarita", Ingredients: ingredients, Visibility: model.RecipePublic},
+			expectedVisibility: model.RecipePublic,
+			expectStore:        true,
+		},
+		{
+			name:               "Failed to store the recipe",
+			ownerId:            "0",
+			recipe:             model.Recipe{Name: "House Margarita", Ingredients: ingredients},
+			expectedVisibility: model.RecipePrivate,
+			storeReturnedError: fmt.Errorf("failed to store the recipe"),
+			expectStore:        true,
+			expectError:        true,
+		},
+		{
+			name:        "Empty owner id",
+			recipe:      model.Recipe{Name: "House Margarita", Ingredients: ingredients},
+			expectError: true,
+		},
+		{
+			name:        "Empty name",
+			ownerId:     "0",
+			recipe:      model.Recipe{Name: "  ", Ingredients: ingredients},
+			expectError: true,
+		},
+		{
+			name:        "No ingredients",
+			ownerId:     "0",
+			recipe:      model.Recipe{Name: "House Margarita"},
+			expectError: true,
+		},
+		{
+			name:        "Ingredient without a name",
+			ownerId:     "0",
+			recipe:      model.Recipe{Name: "House Margarita", Ingredients: []model.Ingredient{{Measure: "1 oz"}}},
+			expectError: true,
+		},
+		{
+			name:        "Invalid visibility",
+			ownerId:     "0",
+			recipe:      model.Recipe{Name: "House Margarita", Ingredients: ingredients, Visibility: "friends"},
+			expectError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRecipeRepo := repository.NewMockRecipeRepository(t)
+			if tt.expectStore {
+				mockRecipeRepo.On("CreateRecipe", mock.MatchedBy(func(recipe model.Recipe) bool {
+					return model.IsRecipeId(recipe.Id) && recipe.OwnerId == tt.ownerId && recipe.Visibility == tt.expectedVisibility &&
+						recipe.CreatedAt.Equal(now) && recipe.UpdatedAt.Equal(now) && recipe.Version == 1
+				})).Return(tt.storeReturnedError)
+			}
+
+			recipeService := NewDefaultRecipeService(mockRecipeRepo).WithClock(fixedClock(now))
+			recipe, err := recipeService.CreateRecipe(tt.ownerId, tt.recipe)
+
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from recipeService.CreateRecipe")
+				assert.Nil(t, recipe, "No recipe should have been returned from recipeService.CreateRecipe")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from recipeService.CreateRecipe")
+				assert.Equal(t, "House Margarita", recipe.Name, "The name should have been trimmed")
+				assert.Equal(t, model.Ingredient{Name: "Tequila", Measure: "2 oz"}, recipe.Ingredients[0], "The ingredients should have been trimmed")
+				assert.Equal(t, " Tequila ", ingredients[0].Name, "The caller's ingredients shouldn't have been modified")
+			}
+			mockRecipeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultRecipeService_FindRecipe(t *testing.T) {
+	tests := []struct {
+		name         string
+		userId       string
+		visibility   string
+		expectRecipe bool
+	}{
+		{name: "Owner sees their private recipe", userId: "0", visibility: model.RecipePrivate, expectRecipe: true},
+		{name: "Other user doesn't see a private recipe", userId: "1", visibility: model.RecipePrivate},
+		{name: "Anonymous user doesn't see a private recipe", visibility: model.RecipePrivate},
+		{name: "Other user sees an unlisted recipe", userId: "1", visibility: model.RecipeUnlisted, expectRecipe: true},
+		{name: "Anonymous user sees a public recipe", visibility: model.RecipePublic, expectRecipe: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipeId := model.RecipeIdPrefix + "0"
+			mockRecipeRepo := repository.NewMockRecipeRepository(t)
+			mockRecipeRepo.On("FindRecipeById", recipeId).Return(&model.Recipe{Id: recipeId, OwnerId: "0", Visibility: tt.visibility}, nil)
+
+			recipe, err := NewDefaultRecipeService(mockRecipeRepo).FindRecipe(tt.userId, recipeId)
+			assert.Nil(t, err, "No error should have been returned from recipeService.FindRecipe")
+			assert.Equal(t, tt.expectRecipe, recipe != nil, "The recipe should only have been found if the user can see it")
+		})
+	}
+}
+
+func TestDefaultRecipeService_FindRecipe_NotARecipeId(t *testing.T) {
+	// a drink of the catalog isn't looked up as a recipe
+	recipe, err := NewDefaultRecipeService(repository.NewMockRecipeRepository(t)).FindRecipe("0", "11007")
+	assert.Nil(t, err, "No error should have been returned from recipeService.FindRecipe")
+	assert.Nil(t, recipe, "No recipe should have been found")
+}
+
+func TestDefaultRecipeService_FindRecipesByOwner(t *testing.T) {
+	mockRecipeRepo := repository.NewMockRecipeRepository(t)
+	mockRecipeRepo.On("FindRecipesByOwner", "0").Return([]model.Recipe{
+		{Id: "recipe-2", Name: "margarita"},
+		{Id: "recipe-0", Name: "Paloma"},
+		{Id: "recipe-1", Name: "Margarita"},
+	}, nil)
+
+	recipes, err := NewDefaultRecipeService(mockRecipeRepo).FindRecipesByOwner("0")
+	assert.Nil(t, err, "No error should have been returned from recipeService.FindRecipesByOwner")
+	ids := []string{}
+	for _, recipe := range recipes {
+		ids = append(ids, recipe.Id)
+	}
+	assert.Equal(t, []string{"recipe-1", "recipe-2", "recipe-0"}, ids, "The recipes should have been ordered by name, then id")
+}
+
+func TestDefaultRecipeService_UpdateRecipe(t *testing.T) {
+	now := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	storedRecipe := &model.Recipe{
+		Id:          "recipe-0",
+		OwnerId:     "0",
+		Name:        "Margarita",
+		Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}},
+		Visibility:  model.RecipePublic,
+		CreatedAt:   createdAt,
+		Version:     2,
+	}
+	recipe := model.Recipe{
+		Id:          "recipe-0",
+		Name:        "House Margarita",
+		Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}},
+		Visibility:  model.RecipeUnlisted,
+	}
+	tests := []struct {
+		name               string
+		userId             string
+		storedRecipe       *model.Recipe
+		expectUpdate       bool
+		storeReturnedError error
+		expectRecipe       bool
+		expectedError      error
+	}{
+		{
+			name:         "Successfully update a recipe",
+			userId:       "0",
+			storedRecipe: storedRecipe,
+			expectUpdate: true,
+			expectRecipe: true,
+		},
+		{
+			name:               "Recipe was modified by someone else",
+			userId:             "0",
+			storedRecipe:       storedRecipe,
+			expectUpdate:       true,
+			storeReturnedError: apperrors.NewConflictError("the recipe was modified", nil),
+			expectedError:      apperrors.NewConflictError("the recipe was modified", nil),
+		},
+		{
+			name:          "Other user can't update a public recipe",
+			userId:        "1",
+			storedRecipe:  storedRecipe,
+			expectedError: apperrors.NewRecipeNotOwnedError("recipe-0"),
+		},
+		{
+			name:   "Missing recipe",
+			userId: "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRecipeRepo := repository.NewMockRecipeRepository(t)
+			mockRecipeRepo.On("FindRecipeById", "recipe-0").Return(tt.storedRecipe, nil)
+			if tt.expectUpdate {
+				mockRecipeRepo.On("UpdateRecipe", mock.MatchedBy(func(updated model.Recipe) bool {
+					return updated.Id == recipe.Id && updated.OwnerId == "0" && updated.CreatedAt.Equal(createdAt) &&
+						updated.UpdatedAt.Equal(now) && updated.Version == 3
+				}), 2).Return(tt.storeReturnedError)
+			}
+
+			recipeService := NewDefaultRecipeService(mockRecipeRepo).WithClock(fixedClock(now))
+			updatedRecipe, err := recipeService.UpdateRecipe(tt.userId, recipe, 2)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectRecipe, updatedRecipe != nil)
+			if updatedRecipe != nil {
+				assert.Equal(t, 3, updatedRecipe.Version, "The version should have been incremented")
+			}
+			mockRecipeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultRecipeService_DeleteRecipe(t *testing.T) {
+	recipeId := model.RecipeIdPrefix + "0"
+	tests := []struct {
+		name          string
+		userId        string
+		recipe        *model.Recipe
+		expectDelete  bool
+		expectedError error
+	}{
+		{
+			name:         "Owner deletes their recipe",
+			userId:       "0",
+			recipe:       &model.Recipe{Id: recipeId, OwnerId: "0", Visibility: model.RecipePrivate},
+			expectDelete: true,
+		},
+		{
+			name:          "Other user can't delete a public recipe",
+			userId:        "1",
+			recipe:        &model.Recipe{Id: recipeId, OwnerId: "0", Visibility: model.RecipePublic},
+			expectedError: apperrors.NewRecipeNotOwnedError(recipeId),
+		},
+		{
+			name:   "Other user's private recipe is treated as missing",
+			userId: "1",
+			recipe: &model.Recipe{Id: recipeId, OwnerId: "0", Visibility: model.RecipePrivate},
+		},
+		{
+			name:   "Missing recipe",
+			userId: "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRecipeRepo := repository.NewMockRecipeRepository(t)
+			mockRecipeRepo.On("FindRecipeById", recipeId).Return(tt.recipe, nil)
+			if tt.expectDelete {
+				mockRecipeRepo.On("DeleteRecipe", recipeId).Return(nil)
+			}
+
+			err := NewDefaultRecipeService(mockRecipeRepo).DeleteRecipe(tt.userId, recipeId)
+			assert.Equal(t, tt.expectedError, err)
+			mockRecipeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDefaultRecipeService_DeleteUserData(t *testing.T) {
+	mockRecipeRepo := repository.NewMockRecipeRepository(t)
+	mockRecipeRepo.On("DeleteRecipesByOwner", "0").Return(fmt.Errorf("failed to delete recipes"))
+
+	err := NewDefaultRecipeService(mockRecipeRepo).DeleteUserData("0")
+	assert.NotNil(t, err, "The repository's error should have been returned from recipeService.DeleteUserData")
+	mockRecipeRepo.AssertExpectations(t)
+}
+
+func TestWithPublicRecipes(t *testing.T) {
+	mockRecipeService := NewMockRecipeService(t)
+	mockRecipeService.On("FindPublicRecipes").Return([]model.Recipe{{Id: "recipe-0", Name: "House Margarita", Visibility: model.RecipePublic}}, nil)
+	loadDrinks := WithPublicRecipes(func() ([]model.Drink, error) {
+		return []model.Drink{{Id: "11007", Name: "Margarita"}}, nil
+	}, mockRecipeService)
+
+	drinks, err := loadDrinks()
+	assert.Nil(t, err, "No error should have been returned from loading the drinks")
+	assert.Equal(t, []model.Drink{{Id: "11007", Name: "Margarita"}, {Id: "recipe-0", Name: "House Margarita"}}, drinks,
+		"The public recipes should have been added to the catalog's drinks")
+
+	failingLoadDrinks := WithPublicRecipes(func() ([]model.Drink, error) {
+		return nil, fmt.Errorf("failed to load the drinks")
+	}, mockRecipeService)
+	_, err = failingLoadDrinks()
+	assert.NotNil(t, err, "The catalog's error should have been returned")
+}
@@ -0,0 +1,63 @@
+//go:generate mockery --name=ShoppingListService --output=./ --outpkg=service --filename=shopping_list_mock.go --inpackage
+package service
+
+import (
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/shopping"
+)
+
+type ShoppingListService interface {
+	// CreateShoppingList adds up the ingredients of the drinks for the servings, leaving out what's on hand;
+	// if no drink ids are given, the user's favorites are used instead. Users' recipes are included as long as
+	// the user can see them
+	CreateShoppingList(userId string, drinkIds []string, servings int, onHand []shopping.OnHand) (*shopping.List, error)
+}
+
+// NewDefaultShoppingListService creates the service; drinks provides the recipes of the drinks and users' recipes
+func NewDefaultShoppingListService(drinks DrinkResolver, favorites FavoriteService) DefaultShoppingListService {
+	return DefaultShoppingListService{
+		drinks:    drinks,
+		favorites: favorites,
+	}
+}
+
+type DefaultShoppingListService struct {
+	drinks    DrinkResolver
+	favorites FavoriteService
+}
+
+func (s DefaultShoppingListService) CreateShoppingList(userId string, drinkIds []string, servings int, onHand []shopping.OnHand) (*shopping.List, error) {
+	if len(drinkIds) == 0 {
+		favorites, err := s.favorites.FindFavoritesByUser(userId)
+		if err != nil {
+			return nil, err
+		}
+		for _, favorite := range favorites {
+			drinkIds = append(drinkIds, favorite.DrinkId)
+		}
+	}
+
+	drinksById, err := s.drinks.FindDrinks(userId, drinkIds)
+	if err != nil {
+		return nil, err
+	}
+	// the list follows the order of the ids, and a drink that's requested twice is only made once per serving
+	drinks := []model.Drink{}
+	missingIds := []string{}
+	seen := map[string]bool{}
+	for _, id := range drinkIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if drink, ok := drinksById[id]; ok {
+			drinks = append(drinks, drink)
+		} else {
+			missingIds = append(missingIds, id)
+		}
+	}
+
+	list := shopping.NewList(drinks, servings, onHand)
+	list.MissingDrinkIds = missingIds
+	return &list, nil
+}
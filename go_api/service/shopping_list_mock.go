@@ -0,0 +1,54 @@
+// Code generated by mockery v2.36.0. DO NOT EDIT.
+
+package service
+
+import (
+	shopping "the-drink-almanac-api/shopping"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockShoppingListService is an autogenerated mock type for the ShoppingListService type
+type MockShoppingListService struct {
+	mock.Mock
+}
+
+// CreateShoppingList provides a mock function with given fields: userId, drinkIds, servings, onHand
+func (_m *MockShoppingListService) CreateShoppingList(userId string, drinkIds []string, servings int, onHand []shopping.OnHand) (*shopping.List, error) {
+	ret := _m.Called(userId, drinkIds, servings, onHand)
+
+	var r0 *shopping.List
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []string, int, []shopping.OnHand) (*shopping.List, error)); ok {
+		return rf(userId, drinkIds, servings, onHand)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string, int, []shopping.OnHand) *shopping.List); ok {
+		r0 = rf(userId, drinkIds, servings, onHand)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*shopping.List)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string, int, []shopping.OnHand) error); ok {
+		r1 = rf(userId, drinkIds, servings, onHand)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockShoppingListService creates a new instance of MockShoppingListService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockShoppingListService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockShoppingListService {
+	mock := &MockShoppingListService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/model"
+	"the-drink-almanac-api/shopping"
+)
+
+func TestDefaultShoppingListService_CreateShoppingList(t *testing.T) {
+	margarita := model.Drink{Id: "11007", Name: "Margarita", Ingredients: []model.Ingredient{{Name: "Tequila", Measure: "2 oz"}}}
+	mojito := model.Drink{Id: "11000", Name: "Mojito", Ingredients: []model.Ingredient{{Name: "Light rum", Measure: "2-3 oz"}}}
+	houseSour := model.Drink{Id: "recipe-0", Name: "House Sour", Ingredients: []model.Ingredient{{Name: "Bourbon", Measure: "2 oz"}}}
+	tests := []struct {
+		name               string
+		drinkIds           []string
+		favorites          []model.Favorite
+		favoritesError     error
+		expectedLookup     []string
+		returnedDrinks     map[string]model.Drink
+		drinksError        error
+		expectedDrinks     []string
+		expectedMissingIds []string
+		expectError        bool
+	}{
+		{
+			name:               "Successfully create a list from drink ids",
+			drinkIds:           []string{"11007", "missing", "11000", "11007"},
+			expectedLookup:     []string{"11007", "missing", "11000", "11007"},
+			returnedDrinks:     map[string]model.Drink{"11007": margarita, "11000": mojito},
+			expectedDrinks:     []string{"Margarita", "Mojito"},
+			expectedMissingIds: []string{"missing"},
+		},
+		{
+			name:               "Successfully create a list from the favorites",
+			favorites:          []model.Favorite{{UserId: "0", DrinkId: "11000"}},
+			expectedLookup:     []string{"11000"},
+			returnedDrinks:     map[string]model.Drink{"11000": mojito},
+			expectedDrinks:     []string{"Mojito"},
+			expectedMissingIds: []string{},
+		},
+		{
+			name:               "Successfully create a list with a recipe favorite",
+			favorites:          []model.Favorite{{UserId: "0", DrinkId: "11000"}, {UserId: "0", DrinkId: "recipe-0"}},
+			expectedLookup:     []string{"11000", "recipe-0"},
+			returnedDrinks:     map[string]model.Drink{"11000": mojito, "recipe-0": houseSour},
+			expectedDrinks:     []string{"Mojito", "House Sour"},
+			expectedMissingIds: []string{},
+		},
+		{
+			name:           "Failed to find the favorites",
+			favoritesError: fmt.Errorf("failed to find favorites"),
+			expectError:    true,
+		},
+		{
+			name:           "Failed to find the drinks",
+			drinkIds:       []string{"11007"},
+			expectedLookup: []string{"11007"},
+			drinksError:    fmt.Errorf("failed to find drinks"),
+			expectError:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDrinkResolver := NewMockDrinkResolver(t)
+			mockFavoriteService := NewMockFavoriteService(t)
+			if len(tt.drinkIds) == 0 {
+				mockFavoriteService.On("FindFavoritesByUser", "0").Return(tt.favorites, tt.favoritesError)
+			}
+			if tt.expectedLookup != nil {
+				mockDrinkResolver.On("FindDrinks", "0", tt.expectedLookup).Return(tt.returnedDrinks, tt.drinksError)
+			}
+
+			shoppingListService := NewDefaultShoppingListService(mockDrinkResolver, mockFavoriteService)
+			list, err := shoppingListService.CreateShoppingList("0", tt.drinkIds, 2, []shopping.OnHand{{Name: "tequila"}})
+
+			if tt.expectError {
+				assert.NotNil(t, err, "An error should have been returned from shoppingListService.CreateShoppingList")
+				assert.Nil(t, list, "No list should have been returned from shoppingListService.CreateShoppingList")
+			} else {
+				assert.Nil(t, err, "No error should have been returned from shoppingListService.CreateShoppingList")
+				assert.Equal(t, tt.expectedDrinks, list.Drinks)
+				assert.Equal(t, tt.expectedMissingIds, list.MissingDrinkIds)
+				assert.Equal(t, 2, list.Servings)
+			}
+			mockDrinkResolver.AssertExpectations(t)
+			mockFavoriteService.AssertExpectations(t)
+		})
+	}
+}
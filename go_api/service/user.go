@@ -19,11 +19,22 @@ type UserService interface {
 	// FindUser retrieves the user's data based on their id
 	FindUser(userId string) (*model.User, error)
 
+	// FindUserWithFavorites retrieves the user's data along with their favorites;
+	// with the single-table design, both are read with one Query
+	FindUserWithFavorites(userId string) (*model.User, []model.Favorite, error)
+
+	// FindUserByUsername retrieves the user with the username; nil if no user has it
+	FindUserByUsername(username string) (*model.User, error)
+
 	// CreateNewUser either creates a new user if one doesn't exist with the given username and password
 	// or returns the existing user and the UserAlreadyExistsError
 	CreateNewUser(username, password string) (*model.User, error)
 
-	// DeleteUser removes the user's record from the user repository
+	// UpdateUser saves the changes to the user as long as nobody else has modified the user
+	// since expectedVersion was read; otherwise, returns the ConflictError
+	UpdateUser(user model.User, expectedVersion int) (*model.User, error)
+
+	// DeleteUser removes the user's record from the user repository, along with the user's data kept by other services
 	DeleteUser(userId string) error
 
 	// Login checks if a user exists with the provided username and password;
@@ -34,8 +45,34 @@ type UserService interface {
 	Login(username, password string) (*model.User, error)
 }
 
+// UserDataDeleter is implemented by the services that keep data for a user, which is deleted along with the user
+type UserDataDeleter interface {
+	DeleteUserData(userId string) error
+}
+
 type DefaultUserService struct {
-	repo repository.UserRepository
+	repo          repository.UserRepository
+	clock         Clock
+	userData      []UserDataDeleter
+	userFavorites repository.UserWithFavoritesFinder
+}
+
+// WithClock returns a copy of the service that uses the given clock for timestamps
+func (s DefaultUserService) WithClock(clock Clock) DefaultUserService {
+	s.clock = clock
+	return s
+}
+
+// WithUserData returns a copy of the service that also deletes the user's data from the given services when deleting a user
+func (s DefaultUserService) WithUserData(deleters ...UserDataDeleter) DefaultUserService {
+	s.userData = append(append([]UserDataDeleter{}, s.userData...), deleters...)
+	return s
+}
+
+// WithFavorites returns a copy of the service that reads users along with their favorites through the given finder
+func (s DefaultUserService) WithFavorites(finder repository.UserWithFavoritesFinder) DefaultUserService {
+	s.userFavorites = finder
+	return s
 }
 
 func (s DefaultUserService) FindAllUsers() ([]model.User, error) {
@@ -46,6 +83,17 @@ func (s DefaultUserService) FindUser(userId string) (*model.User, error) {
 	return s.repo.FindUserById(userId)
 }
 
+func (s DefaultUserService) FindUserWithFavorites(userId string) (*model.User, []model.Favorite, error) {
+	if s.userFavorites == nil {
+		return nil, nil, fmt.Errorf("the user service can't read favorites; it must be created with WithFavorites")
+	}
+	return s.userFavorites.FindUserWithFavorites(userId)
+}
+
+func (s DefaultUserService) FindUserByUsername(username string) (*model.User, error) {
+	return s.repo.FindUserByUsername(username)
+}
+
 func (s DefaultUserService) CreateNewUser(username, password string) (*model.User, error) {
 	if username == "" {
 		return nil, fmt.Errorf("the username must not be empty")
@@ -63,25 +111,64 @@ func (s DefaultUserService) CreateNewUser(username, password string) (*model.Use
 		return user, apperrors.NewUserAlreadyExistsError(username)
 	}
 
-	hashedPassword, err := getHashedPassword(password)
+	hashedPassword, err := HashPassword(password)
 	if err != nil {
 		return nil, err
 	}
 
+	now := s.clock.Now()
 	user = &model.User{
-		Id:       uuid.NewString(),
-		Username: username,
-		Password: hashedPassword,
+		Id:        uuid.NewString(),
+		Username:  username,
+		Password:  hashedPassword,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
 	}
-	err = s.repo.CreateNewUser(*user)
+	err = s.repo.CreateNewUser(*user, model.Event{
+		Id:         uuid.NewString(),
+		Type:       model.UserCreated,
+		OccurredAt: now,
+		UserId:     user.Id,
+		Username:   user.Username,
+	})
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
+func (s DefaultUserService) UpdateUser(user model.User, expectedVersion int) (*model.User, error) {
+	user.UpdatedAt = s.clock.Now()
+	user.Version = expectedVersion + 1
+	err := s.repo.UpdateUser(user, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeleteUser records the UserDeleted event along with the delete; deleting a user that doesn't exist does nothing
+//
+// The user's data is deleted first, so that a failure leaves the user in place to delete again
+// instead of leaving data behind that no user can reach
 func (s DefaultUserService) DeleteUser(userId string) error {
-	return s.repo.DeleteUser(userId)
+	user, err := s.repo.FindUserById(userId)
+	if err != nil || user == nil {
+		return err
+	}
+	for _, deleter := range s.userData {
+		if err := deleter.DeleteUserData(userId); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteUser(userId, model.Event{
+		Id:         uuid.NewString(),
+		Type:       model.UserDeleted,
+		OccurredAt: s.clock.Now(),
+		UserId:     user.Id,
+		Username:   user.Username,
+	})
 }
 
 func (s DefaultUserService) Login(username, password string) (*model.User, error) {
@@ -110,12 +197,13 @@ func (s DefaultUserService) Login(username, password string) (*model.User, error
 
 func NewDefaultUserService(store repository.UserRepository) DefaultUserService {
 	return DefaultUserService{
-		repo: store,
+		repo:  store,
+		clock: SystemClock{},
 	}
 }
 
-// getHashedPassword takes the raw password and hashes it for protection
-func getHashedPassword(password string) (string, error) {
+// HashPassword takes the raw password and hashes it for protection
+func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 8)
 	if err != nil {
 		return "", err
@@ -105,6 +105,67 @@ func (_m *MockUserService) FindUser(userId string) (*model.User, error) {
 	return r0, r1
 }
 
+// FindUserByUsername provides a mock function with given fields: username
+func (_m *MockUserService) FindUserByUsername(username string) (*model.User, error) {
+	ret := _m.Called(username)
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.User, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserWithFavorites provides a mock function with given fields: userId
+func (_m *MockUserService) FindUserWithFavorites(userId string) (*model.User, []model.Favorite, error) {
+	ret := _m.Called(userId)
+
+	var r0 *model.User
+	var r1 []model.Favorite
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (*model.User, []model.Favorite, error)); ok {
+		return rf(userId)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) []model.Favorite); ok {
+		r1 = rf(userId)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]model.Favorite)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(userId)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Login provides a mock function with given fields: username, password
 func (_m *MockUserService) Login(username string, password string) (*model.User, error) {
 	ret := _m.Called(username, password)
@@ -131,6 +192,32 @@ func (_m *MockUserService) Login(username string, password string) (*model.User,
 	return r0, r1
 }
 
+// UpdateUser provides a mock function with given fields: user, expectedVersion
+func (_m *MockUserService) UpdateUser(user model.User, expectedVersion int) (*model.User, error) {
+	ret := _m.Called(user, expectedVersion)
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.User, int) (*model.User, error)); ok {
+		return rf(user, expectedVersion)
+	}
+	if rf, ok := ret.Get(0).(func(model.User, int) *model.User); ok {
+		r0 = rf(user, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(model.User, int) error); ok {
+		r1 = rf(user, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewMockUserService creates a new instance of MockUserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUserService(t interface {
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -147,7 +149,7 @@ func TestDefaultUserService_CreateNewUser(t *testing.T) {
 				mockUserRepo.On("FindUserByUsername", tt.username).Return(tt.existingUser, tt.existingUserError)
 			}
 			if tt.isStoreCreateNewUserCalled {
-				mockUserRepo.On("CreateNewUser", mock.AnythingOfType("model.User")).Return(tt.returnedError)
+				mockUserRepo.On("CreateNewUser", mock.AnythingOfType("model.User"), mock.AnythingOfType("model.Event")).Return(tt.returnedError)
 			}
 
 			userService := NewDefaultUserService(mockUserRepo)
@@ -172,37 +174,132 @@ func TestDefaultUserService_CreateNewUser(t *testing.T) {
 	}
 }
 
+func TestDefaultUserService_CreateNewUser_Timestamps(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockUserRepo := repository.NewMockUserRepository(t)
+	mockUserRepo.On("FindUserByUsername", "0").Return(nil, nil)
+	mockUserRepo.On("CreateNewUser", mock.MatchedBy(func(user model.User) bool {
+		return user.CreatedAt.Equal(now) && user.UpdatedAt.Equal(now)
+	}), mock.MatchedBy(func(event model.Event) bool {
+		return event.Type == model.UserCreated && event.OccurredAt.Equal(now) && event.Username == "0"
+	})).Return(nil)
+
+	userService := NewDefaultUserService(mockUserRepo).WithClock(fixedClock(now))
+	user, err := userService.CreateNewUser("0", "0")
+	assert.Nil(t, err, "No error should have been returned from userService.CreateNewUser")
+	assert.Equal(t, now, user.CreatedAt)
+	assert.Equal(t, now, user.UpdatedAt)
+	assert.Equal(t, 1, user.Version)
+}
+
+func TestDefaultUserService_UpdateUser(t *testing.T) {
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	user := model.User{
+		Id:        "0",
+		Username:  "0",
+		CreatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:   2,
+	}
+	tests := []struct {
+		name                string
+		storeReturnedError  error
+		expectError         bool
+		expectConflictError bool
+	}{
+		{
+			name: "Successfully updated the user",
+		},
+		{
+			name:                "User was modified by another request",
+			storeReturnedError:  apperrors.NewConflictError("conflict", nil),
+			expectError:         true,
+			expectConflictError: true,
+		},
+		{
+			name:               "Failed to update the user",
+			storeReturnedError: fmt.Errorf("failed to update the user"),
+			expectError:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := repository.NewMockUserRepository(t)
+			mockUserRepo.On("UpdateUser", mock.MatchedBy(func(user model.User) bool {
+				return user.Version == 3 && user.UpdatedAt.Equal(now)
+			}), 2).Return(tt.storeReturnedError)
+
+			userService := NewDefaultUserService(mockUserRepo).WithClock(fixedClock(now))
+			updatedUser, err := userService.UpdateUser(user, 2)
+			assert.Equal(t, tt.expectError, err != nil, "userService.UpdateUser() error = %v", err)
+			assert.Equal(t, tt.expectConflictError, errors.As(err, &apperrors.ConflictError{}))
+			if !tt.expectError {
+				assert.Equal(t, 3, updatedUser.Version)
+				assert.Equal(t, user.CreatedAt, updatedUser.CreatedAt)
+			}
+		})
+	}
+}
+
 func TestDefaultUserService_DeleteUser(t *testing.T) {
 	tests := []struct {
 		name               string
 		userId             string
+		existingUser       *model.User
+		findReturnedError  error
 		storeReturnedError error
-		authReturnedError  error
+		userDataError      error
 		expectError        bool
 	}{
 		{
 			name:               "Successfully delete user",
 			userId:             "0",
+			existingUser:       &model.User{Id: "0", Username: "0"},
 			storeReturnedError: nil,
-			authReturnedError:  nil,
 			expectError:        false,
 		},
 		{
 			name:               "Failed to delete user",
 			userId:             "0",
+			existingUser:       &model.User{Id: "0", Username: "0"},
 			storeReturnedError: fmt.Errorf("failed to delete users"),
-			authReturnedError:  nil,
 			expectError:        true,
 		},
+		{
+			name:          "Failed to delete the user's data",
+			userId:        "0",
+			existingUser:  &model.User{Id: "0", Username: "0"},
+			userDataError: fmt.Errorf("failed to delete inventory"),
+			expectError:   true,
+		},
+		{
+			name:         "User doesn't exist",
+			userId:       "0",
+			existingUser: nil,
+			expectError:  false,
+		},
+		{
+			name:              "Failed to find user",
+			userId:            "0",
+			findReturnedError: fmt.Errorf("failed to find user"),
+			expectError:       true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockUserRepo := repository.NewMockUserRepository(t)
-			if tt.authReturnedError == nil {
-				mockUserRepo.On("DeleteUser", tt.userId).Return(tt.storeReturnedError)
+			mockUserData := NewMockInventoryService(t)
+			mockUserRepo.On("FindUserById", tt.userId).Return(tt.existingUser, tt.findReturnedError)
+			if tt.existingUser != nil {
+				mockUserData.On("DeleteUserData", tt.userId).Return(tt.userDataError)
+			}
+			if tt.existingUser != nil && tt.userDataError == nil {
+				mockUserRepo.On("DeleteUser", tt.userId, mock.MatchedBy(func(event model.Event) bool {
+					return event.Type == model.UserDeleted && event.UserId == tt.userId && event.Username == tt.existingUser.Username
+				})).Return(tt.storeReturnedError)
 			}
 
-			userService := NewDefaultUserService(mockUserRepo)
+			userService := NewDefaultUserService(mockUserRepo).WithUserData(mockUserData)
 			err := userService.DeleteUser(tt.userId)
 
 			if tt.expectError {
@@ -303,3 +400,23 @@ func TestDefaultUserService_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultUserService_FindUserWithFavorites(t *testing.T) {
+	user := &model.User{Id: "0", Username: "0"}
+	favorites := []model.Favorite{{Id: "1", UserId: "0", DrinkId: "11007"}}
+
+	mockUserRepo := repository.NewMockUserRepository(t)
+	mockUserRepo.On("FindUserById", "0").Return(user, nil)
+	mockFavoriteRepo := repository.NewMockFavoriteRepository(t)
+	mockFavoriteRepo.On("FindFavoritesByUser", "0").Return(favorites, nil)
+
+	userService := NewDefaultUserService(mockUserRepo).
+		WithFavorites(repository.NewUserWithFavoritesFinder(mockUserRepo, mockFavoriteRepo))
+	foundUser, foundFavorites, err := userService.FindUserWithFavorites("0")
+	assert.NoError(t, err)
+	assert.Equal(t, user, foundUser)
+	assert.Equal(t, favorites, foundFavorites)
+
+	_, _, err = NewDefaultUserService(mockUserRepo).FindUserWithFavorites("0")
+	assert.Error(t, err, "a service created without WithFavorites can't read favorites")
+}
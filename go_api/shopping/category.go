@@ -0,0 +1,54 @@
+package shopping
+
+import "strings"
+
+// Category is the aisle that an ingredient is grouped under
+type Category string
+
+const (
+	Spirits    Category = "Spirits"
+	Liqueurs   Category = "Liqueurs"
+	Wine       Category = "Wine & Vermouth"
+	Beer       Category = "Beer & Cider"
+	Bitters    Category = "Bitters"
+	Juices     Category = "Juices"
+	Mixers     Category = "Mixers"
+	Sweeteners Category = "Sweeteners"
+	Dairy      Category = "Dairy & Eggs"
+	Produce    Category = "Produce & Garnishes"
+	Other      Category = "Other"
+)
+
+// Categories is the order that the groups of a list are in
+var Categories = []Category{Spirits, Liqueurs, Wine, Beer, Bitters, Juices, Mixers, Sweeteners, Dairy, Produce, Other}
+
+// categoryKeywords are checked in order, so that e.g. "lime juice" is a juice rather than produce,
+// and "orange bitters" are bitters
+var categoryKeywords = []struct {
+	category Category
+	keywords []string
+}{
+	{Bitters, []string{"bitters"}},
+	{Juices, []string{"juice"}},
+	{Liqueurs, []string{"liqueur", "schnapps", "triple sec", "curacao", "cointreau", "amaretto", "kahlua", "creme de", "campari", "aperol", "chartreuse", "galliano", "sambuca", "baileys", "irish cream", "grand marnier", "frangelico", "midori", "drambuie", "benedictine", "maraschino", "sloe gin", "limoncello", "chambord", "st. germain"}},
+	{Mixers, []string{"soda", "tonic", "cola", "coke", "ginger ale", "ginger beer", "water", "lemonade", "sprite", "7-up", "espresso", "coffee", "tea"}},
+	{Spirits, []string{"rum", "vodka", "gin", "tequila", "mezcal", "whiskey", "whisky", "bourbon", "scotch", "rye", "brandy", "cognac", "pisco", "cachaca", "absinthe", "everclear", "grain alcohol", "applejack", "ouzo", "sake"}},
+	{Wine, []string{"wine", "champagne", "prosecco", "vermouth", "port", "sherry", "lillet", "dubonnet", "cava"}},
+	{Beer, []string{"beer", "ale", "lager", "stout", "cider"}},
+	{Sweeteners, []string{"syrup", "sugar", "grenadine", "honey", "agave", "orgeat", "falernum"}},
+	{Dairy, []string{"milk", "cream", "egg", "yoghurt", "yogurt", "butter"}},
+	{Produce, []string{"lemon", "lime", "orange", "cherry", "cherries", "mint", "olive", "apple", "pineapple", "berry", "berries", "banana", "peach", "mango", "cucumber", "celery", "ginger", "grapefruit", "nutmeg", "cinnamon", "salt", "pepper", "peel", "twist", "wedge", "slice"}},
+}
+
+// Categorize guesses the aisle of an ingredient from the words in its normalized name
+func Categorize(ingredient string) Category {
+	words := " " + ingredient + " "
+	for _, candidate := range categoryKeywords {
+		for _, keyword := range candidate.keywords {
+			if strings.Contains(words, " "+keyword+" ") || strings.Contains(words, " "+keyword+"s ") {
+				return candidate.category
+			}
+		}
+	}
+	return Other
+}
@@ -0,0 +1,192 @@
+// Package shopping combines the ingredients of several drinks into a shopping list,
+// grouped by the aisle that they're found in.
+package shopping
+
+import (
+	"math"
+	"sort"
+
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/model"
+)
+
+// remainderEpsilon is the amount below which what's left to buy after subtracting what's on hand is nothing
+const remainderEpsilon = 1e-6
+
+// Item is an ingredient to buy, added up across the drinks that use it
+type Item struct {
+	// Ingredient is the normalized name (see model.IngredientKey) that the drinks' ingredients are matched on
+	Ingredient string
+	// Name is the ingredient's name as the first drink that uses it writes it
+	Name     string
+	Category Category
+	// Amounts has the total volume in whole milliliters first, followed by the totals of the units that aren't volumes
+	Amounts []measure.Quantity
+	// Unmeasured is true if a drink doesn't give an amount of the ingredient, e.g. a garnish or "to taste"
+	Unmeasured bool
+	// Drinks is the names of the drinks that use the ingredient
+	Drinks []string
+}
+
+// Group is the items of a category, ordered by name
+type Group struct {
+	Category Category
+	Items    []Item
+}
+
+// OnHand is an ingredient that is already on hand; all of it is on hand if the quantity is nil
+type OnHand struct {
+	Name     string
+	Quantity *measure.Quantity
+}
+
+// List is the ingredients to buy to make every drink the given number of times
+type List struct {
+	Servings int
+	// Drinks is the names of the drinks that the list is for
+	Drinks []string
+	Groups []Group
+	// OnHand is the names of the ingredients that don't need to be bought, since enough of them is on hand
+	OnHand []string
+	// MissingDrinkIds is the ids of the drinks that were asked for but don't have a recipe, so they aren't in the list
+	MissingDrinkIds []string
+}
+
+// total accumulates the amounts of an ingredient
+type total struct {
+	item        Item
+	milliliters float64
+	others      map[measure.Unit]float64
+}
+
+// have is the amount of an ingredient that's on hand
+type have struct {
+	all         bool
+	milliliters float64
+	others      map[measure.Unit]float64
+}
+
+// NewList adds up the ingredients of the drinks for the servings and subtracts what's on hand
+func NewList(drinks []model.Drink, servings int, onHand []OnHand) List {
+	list := List{Servings: servings, Drinks: []string{}, Groups: []Group{}, OnHand: []string{}, MissingDrinkIds: []string{}}
+	totals := map[string]*total{}
+	order := []string{}
+	for _, drink := range drinks {
+		list.Drinks = append(list.Drinks, drink.Name)
+		for _, ingredient := range drink.Ingredients {
+			key := model.IngredientKey(ingredient.Name)
+			if key == "" {
+				continue
+			}
+			t, ok := totals[key]
+			if !ok {
+				t = &total{
+					item:   Item{Ingredient: key, Name: ingredient.Name, Category: Categorize(key), Drinks: []string{}},
+					others: map[measure.Unit]float64{},
+				}
+				totals[key] = t
+				order = append(order, key)
+			}
+			if len(t.item.Drinks) == 0 || t.item.Drinks[len(t.item.Drinks)-1] != drink.Name {
+				t.item.Drinks = append(t.item.Drinks, drink.Name)
+			}
+			t.add(ingredient.Measure, servings)
+		}
+	}
+
+	haves := newHaves(onHand)
+	items := map[Category][]Item{}
+	for _, key := range order {
+		t := totals[key]
+		h, isOnHand := haves[key]
+		if isOnHand {
+			if h.all || t.subtract(h) {
+				list.OnHand = append(list.OnHand, t.item.Name)
+				continue
+			}
+		}
+		t.item.Amounts = t.amounts()
+		items[t.item.Category] = append(items[t.item.Category], t.item)
+	}
+	for _, category := range Categories {
+		if len(items[category]) == 0 {
+			continue
+		}
+		sort.SliceStable(items[category], func(i, j int) bool {
+			return items[category][i].Ingredient < items[category][j].Ingredient
+		})
+		list.Groups = append(list.Groups, Group{Category: category, Items: items[category]})
+	}
+	sort.Strings(list.OnHand)
+	return list
+}
+
+func (t *total) add(text string, servings int) {
+	quantity, ok := measure.Parse(text)
+	if !ok {
+		t.item.Unmeasured = true
+		return
+	}
+	if milliliters, isVolume := quantity.Milliliters(); isVolume {
+		t.milliliters += milliliters * float64(servings)
+		return
+	}
+	// the juice of half a lemon is half of a lemon to buy
+	if quantity.Unit == measure.Juice {
+		quantity.Unit = measure.Count
+	}
+	t.others[quantity.Unit] += quantity.Amount * float64(servings)
+}
+
+// subtract takes what's on hand away from the total, and reports whether that covers all of it
+func (t *total) subtract(h have) bool {
+	t.milliliters -= h.milliliters
+	for unit, amount := range h.others {
+		if _, ok := t.others[unit]; ok {
+			t.others[unit] -= amount
+		}
+	}
+	return len(t.amounts()) == 0
+}
+
+func (t *total) amounts() []measure.Quantity {
+	amounts := []measure.Quantity{}
+	if t.milliliters > remainderEpsilon {
+		// a fraction of a milliliter isn't worth listing, so volumes are rounded up to whole milliliters
+		amounts = append(amounts, measure.Quantity{Amount: math.Ceil(t.milliliters - remainderEpsilon), Unit: measure.Milliliter})
+	}
+	units := make([]string, 0, len(t.others))
+	for unit, amount := range t.others {
+		if amount > remainderEpsilon {
+			units = append(units, string(unit))
+		}
+	}
+	sort.Strings(units)
+	for _, unit := range units {
+		amounts = append(amounts, measure.Quantity{Amount: t.others[measure.Unit(unit)], Unit: measure.Unit(unit)})
+	}
+	return amounts
+}
+
+func newHaves(onHand []OnHand) map[string]have {
+	haves := map[string]have{}
+	for _, o := range onHand {
+		key := model.IngredientKey(o.Name)
+		if key == "" {
+			continue
+		}
+		h, ok := haves[key]
+		if !ok {
+			h = have{others: map[measure.Unit]float64{}}
+		}
+		if o.Quantity == nil {
+			h.all = true
+		} else if milliliters, isVolume := o.Quantity.Milliliters(); isVolume {
+			h.milliliters += milliliters
+		} else {
+			h.others[o.Quantity.Unit] += o.Quantity.Amount
+		}
+		haves[key] = h
+	}
+	return haves
+}
@@ -0,0 +1,125 @@
+package shopping
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"the-drink-almanac-api/measure"
+	"the-drink-almanac-api/model"
+)
+
+var testDrinks = []model.Drink{
+	{
+		Id:   "11007",
+		Name: "Margarita",
+		Ingredients: []model.Ingredient{
+			{Name: "Tequila", Measure: "1 1/2 oz"},
+			{Name: "Triple sec", Measure: "1/2 oz"},
+			{Name: "Lime juice", Measure: "1 oz"},
+			{Name: "Salt"},
+		},
+	},
+	{
+		Id:   "11008",
+		Name: "Tommy's Margarita",
+		Ingredients: []model.Ingredient{
+			{Name: "tequila", Measure: "6 cl"},
+			{Name: "Lime  Juice", Measure: "3 cl"},
+			{Name: "Agave syrup", Measure: "2 tsp"},
+			{Name: "Angostura bitters", Measure: "2 dashes"},
+		},
+	},
+}
+
+func TestCategorize(t *testing.T) {
+	assert.Equal(t, Juices, Categorize("lime juice"))
+	assert.Equal(t, Bitters, Categorize("orange bitters"))
+	assert.Equal(t, Mixers, Categorize("ginger ale"))
+	assert.Equal(t, Liqueurs, Categorize("sloe gin"))
+	assert.Equal(t, Spirits, Categorize("light rum"))
+	assert.Equal(t, Produce, Categorize("lemon peel"))
+	assert.Equal(t, Produce, Categorize("olives"))
+	assert.Equal(t, Other, Categorize("ice"))
+}
+
+func TestNewList(t *testing.T) {
+	list := NewList(testDrinks, 2, nil)
+
+	assert.Equal(t, []string{"Margarita", "Tommy's Margarita"}, list.Drinks)
+	assert.Equal(t, []Category{Spirits, Liqueurs, Bitters, Juices, Sweeteners, Produce}, groupCategories(list))
+
+	tequila := list.Groups[0].Items[0]
+	assert.Equal(t, "Tequila", tequila.Name, "The name should be the one in the first drink")
+	assert.Equal(t, []string{"Margarita", "Tommy's Margarita"}, tequila.Drinks)
+	assert.Len(t, tequila.Amounts, 1)
+	assert.Equal(t, 209.0, tequila.Amounts[0].Amount, "The ounces and centiliters should be added up in milliliters")
+	assert.Equal(t, measure.Milliliter, tequila.Amounts[0].Unit)
+
+	bitters := list.Groups[2].Items[0]
+	assert.Equal(t, []measure.Quantity{{Amount: 4, Unit: measure.Dash}}, bitters.Amounts)
+
+	salt := list.Groups[5].Items[0]
+	assert.Empty(t, salt.Amounts)
+	assert.True(t, salt.Unmeasured)
+	assert.Empty(t, list.OnHand)
+}
+
+func TestNewList_Juice(t *testing.T) {
+	drinks := []model.Drink{{
+		Id:          "12345",
+		Name:        "Whiskey Sour",
+		Ingredients: []model.Ingredient{{Name: "Lemon", Measure: "Juice of 1/2"}},
+	}}
+	list := NewList(drinks, 3, nil)
+
+	lemon := list.Groups[0].Items[0]
+	assert.Equal(t, []measure.Quantity{{Amount: 1.5, Unit: measure.Count}}, lemon.Amounts, "The juice should be counted as the fruit to buy")
+}
+
+func TestNewList_OnHand(t *testing.T) {
+	list := NewList(testDrinks, 1, []OnHand{
+		{Name: "SALT"},
+		{Name: "Tequila", Quantity: &measure.Quantity{Amount: 1, Unit: measure.Ounce}},
+		{Name: "lime juice", Quantity: &measure.Quantity{Amount: 10, Unit: measure.Centiliter}},
+		{Name: "Angostura bitters", Quantity: &measure.Quantity{Amount: 1, Unit: measure.Dash}},
+	})
+
+	assert.Equal(t, []string{"Lime juice", "Salt"}, list.OnHand)
+	tequila := list.Groups[0].Items[0]
+	assert.Equal(t, 75.0, tequila.Amounts[0].Amount, "The ounce on hand should have been subtracted")
+	bitters := list.Groups[2].Items[0]
+	assert.Equal(t, []measure.Quantity{{Amount: 1, Unit: measure.Dash}}, bitters.Amounts)
+}
+
+func TestList_Markdown(t *testing.T) {
+	list := NewList(testDrinks[:1], 1, []OnHand{{Name: "lime juice"}})
+
+	assert.Equal(t, `# Shopping List
+
+1 serving of each: Margarita
+
+## Spirits
+
+- [ ] Tequila: 45 ml
+
+## Liqueurs
+
+- [ ] Triple sec: 15 ml
+
+## Produce & Garnishes
+
+- [ ] Salt: as needed
+
+## Already on Hand
+
+- Lime juice
+`, list.Markdown())
+}
+
+func groupCategories(list List) []Category {
+	categories := []Category{}
+	for _, group := range list.Groups {
+		categories = append(categories, group.Category)
+	}
+	return categories
+}
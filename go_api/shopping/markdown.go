@@ -0,0 +1,58 @@
+package shopping
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the list as a Markdown checklist, which also reads well as plain text
+func (l List) Markdown() string {
+	var builder strings.Builder
+	builder.WriteString("# Shopping List\n\n")
+	servings := "1 serving"
+	if l.Servings != 1 {
+		servings = fmt.Sprintf("%d servings", l.Servings)
+	}
+	if len(l.Drinks) == 0 {
+		builder.WriteString("There aren't any drinks to shop for.\n")
+	} else {
+		fmt.Fprintf(&builder, "%s of each: %s\n", servings, strings.Join(l.Drinks, ", "))
+	}
+
+	for _, group := range l.Groups {
+		fmt.Fprintf(&builder, "\n## %s\n\n", group.Category)
+		for _, item := range group.Items {
+			fmt.Fprintf(&builder, "- [ ] %s", item.Name)
+			if amount := item.amountText(); amount != "" {
+				fmt.Fprintf(&builder, ": %s", amount)
+			}
+			builder.WriteString("\n")
+		}
+	}
+	if len(l.MissingDrinkIds) > 0 {
+		fmt.Fprintf(&builder, "\nNo recipe was found for the drinks with the ids %s.\n", strings.Join(l.MissingDrinkIds, ", "))
+	}
+	if len(l.OnHand) > 0 {
+		builder.WriteString("\n## Already on Hand\n\n")
+		for _, name := range l.OnHand {
+			fmt.Fprintf(&builder, "- %s\n", name)
+		}
+	}
+	return builder.String()
+}
+
+// amountText joins the item's amounts, e.g. "90 ml + 2 dash", noting if some drinks don't measure it
+func (i Item) amountText() string {
+	amounts := make([]string, len(i.Amounts))
+	for j, amount := range i.Amounts {
+		amounts[j] = amount.String()
+	}
+	text := strings.Join(amounts, " + ")
+	if i.Unmeasured {
+		if text == "" {
+			return "as needed"
+		}
+		text += " (plus some as needed)"
+	}
+	return text
+}